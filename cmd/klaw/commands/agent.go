@@ -2,10 +2,10 @@ package commands
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
 	"text/tabwriter"
 	"time"
@@ -14,7 +14,9 @@ import (
 	"github.com/eachlabs/klaw/internal/channel"
 	"github.com/eachlabs/klaw/internal/cluster"
 	"github.com/eachlabs/klaw/internal/config"
+	"github.com/eachlabs/klaw/internal/memory"
 	"github.com/eachlabs/klaw/internal/provider"
+	"github.com/eachlabs/klaw/internal/skill"
 	"github.com/eachlabs/klaw/internal/tool"
 	"github.com/spf13/cobra"
 )
@@ -22,11 +24,15 @@ import (
 var (
 	agentTask        string
 	agentModel       string
+	agentProvider    string
 	agentTools       string
 	agentDescription string
 	agentTriggers    string
 	agentSkills      string
 	agentBootstrap   bool
+	agentTemplate    string
+	agentShareMemory bool
+	agentDryRun      bool
 )
 
 // DefaultAgentSkills are included with every agent (from skills.sh)
@@ -38,9 +44,32 @@ func init() {
 	// Add agent subcommands to existing commands
 	createCmd.AddCommand(createAgentCmd)
 	getCmd.AddCommand(getAgentsCmd)
+	getCmd.AddCommand(getTemplatesCmd)
 	deleteCmd.AddCommand(deleteAgentCmd)
 	describeCmd.AddCommand(describeAgentCmd)
 
+	// klaw agent config ...
+	agentConfigCmd.AddCommand(agentConfigSetCmd)
+	agentConfigCmd.AddCommand(agentConfigGetCmd)
+	agentConfigCmd.AddCommand(agentConfigUnsetCmd)
+	agentCmd.AddCommand(agentConfigCmd)
+
+	// klaw agent policy ...
+	agentPolicyCmd.AddCommand(agentPolicySetCmd)
+	agentPolicyCmd.AddCommand(agentPolicyShowCmd)
+	agentPolicyCmd.AddCommand(agentPolicyClearCmd)
+	agentCmd.AddCommand(agentPolicyCmd)
+
+	// klaw agent memory ...
+	agentMemoryCmd.AddCommand(agentMemoryShareCmd)
+	agentMemoryCmd.AddCommand(agentMemoryIsolateCmd)
+	agentCmd.AddCommand(agentMemoryCmd)
+
+	// klaw agent bootstrap ...
+	agentBootstrapCmd.AddCommand(agentBootstrapRegenerateCmd)
+	agentCmd.AddCommand(agentBootstrapCmd)
+	rootCmd.AddCommand(agentCmd)
+
 	// Worker command (runs inside container)
 	rootCmd.AddCommand(workerCmd)
 }
@@ -60,29 +89,45 @@ Examples:
   klaw create agent researcher --description "Researches topics" --skills web-search
   klaw create agent devops --description "Manages infrastructure" --skills docker,git,api
   klaw create agent writer --description "Writes content" --model claude-opus-4
+  klaw create agent oncall --from-template sre-oncall
+
+Templates provide the description, system prompt, skills, tools, triggers
+and tool policy; flags given on the command line override them. Run
+'klaw get templates' to list them.
+
+--dry-run checks the agent and shows it, with the generated system prompt,
+without creating it.
 
-Available skills: web-search, browser, code-exec, git, docker, api, database, slack, email, calendar
+Available skills: web-search, browser, code-exec, git, github, docker, kubernetes, api, database, slack, email, calendar
 Run 'klaw skill list' to see all available skills.`,
 	Args: cobra.ExactArgs(1),
 	RunE: runCreateAgent,
 }
 
 func init() {
-	createAgentCmd.Flags().StringVarP(&agentDescription, "description", "d", "", "What this agent does (required)")
+	createAgentCmd.Flags().StringVarP(&agentDescription, "description", "d", "", "What this agent does (required unless --from-template)")
 	createAgentCmd.Flags().StringVar(&agentModel, "model", "claude-sonnet-4-20250514", "Model to use")
+	createAgentCmd.Flags().StringVar(&agentProvider, "provider", "", "Provider to run the agent with (default: the one klaw runs with)")
 	createAgentCmd.Flags().StringVar(&agentTools, "tools", "bash,read,write,edit,glob,grep", "Comma-separated list of tools")
 	createAgentCmd.Flags().StringVar(&agentTriggers, "triggers", "", "Keywords that route to this agent (comma-separated)")
 	createAgentCmd.Flags().StringVar(&agentSkills, "skills", "", "Skills to enable (comma-separated, e.g., web-search,git,docker)")
 	createAgentCmd.Flags().StringVar(&agentTask, "task", "", "System prompt / task (optional, uses description if not set)")
 	createAgentCmd.Flags().BoolVar(&agentBootstrap, "bootstrap", true, "Generate AI-enhanced system prompt (default: true)")
-	_ = createAgentCmd.MarkFlagRequired("description")
+	createAgentCmd.Flags().BoolVar(&agentShareMemory, "shared-memory", false, "Also give the agent the namespace's shared memory")
+	createAgentCmd.Flags().StringVar(&agentTemplate, "from-template", "", "Start from an agent template (name, .toml file or org/name from the skills registry)")
+	createAgentCmd.Flags().BoolVar(&agentDryRun, "dry-run", false, "Validate and show the agent without creating it")
+}
+
+// agentTemplates returns the loader for agent templates.
+func agentTemplates() *agent.TemplateLoader {
+	return agent.NewTemplateLoader(config.ConfigDir()+"/templates", config.ConfigDir()+"/skills")
 }
 
 func runCreateAgent(cmd *cobra.Command, args []string) error {
 	name := args[0]
 
 	store := cluster.NewStore(config.StateDir())
-	ctxMgr := cluster.NewContextManager(config.ConfigDir())
+	ctxMgr := contextManager()
 
 	clusterName, namespace, err := ctxMgr.RequireCurrent()
 	if err != nil {
@@ -94,6 +139,34 @@ func runCreateAgent(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("agent already exists: %s (use 'klaw delete agent %s' first)", name, name)
 	}
 
+	// Start from a template; flags given on the command line win
+	var tmpl *agent.Template
+	if agentTemplate != "" {
+		if tmpl, err = agentTemplates().Load(agentTemplate); err != nil {
+			return err
+		}
+		flags := cmd.Flags()
+		if !flags.Changed("description") {
+			agentDescription = tmpl.Description
+		}
+		if !flags.Changed("task") {
+			agentTask = tmpl.SystemPrompt
+		}
+		if !flags.Changed("model") && tmpl.Model != "" {
+			agentModel = tmpl.Model
+		}
+		if !flags.Changed("tools") && len(tmpl.Tools) > 0 {
+			agentTools = strings.Join(tmpl.Tools, ",")
+		}
+		if !flags.Changed("triggers") {
+			agentTriggers = strings.Join(tmpl.Triggers, ",")
+		}
+		agentSkills = strings.Join(append(tmpl.Skills, agentSkills), ",")
+	}
+	if agentDescription == "" {
+		return fmt.Errorf(`required flag "description" not set (or use --from-template)`)
+	}
+
 	// Parse skills early for bootstrap - always include default skills
 	skills := make([]string, len(DefaultAgentSkills))
 	copy(skills, DefaultAgentSkills)
@@ -115,8 +188,12 @@ func runCreateAgent(cmd *cobra.Command, args []string) error {
 		// User provided explicit task
 		systemPrompt = agentTask
 	} else if agentBootstrap {
-		// Generate AI-enhanced bootstrap
-		fmt.Println("🤖 Generating AI-enhanced system prompt...")
+		// Generate AI-enhanced bootstrap; progress stays out of -o json|yaml
+		progress := os.Stdout
+		if structuredOutput() {
+			progress = os.Stderr
+		}
+		fmt.Fprintln(progress, "🤖 Generating AI-enhanced system prompt...")
 
 		cfg, err := config.Load()
 		if err == nil {
@@ -144,10 +221,10 @@ func runCreateAgent(cmd *cobra.Command, args []string) error {
 					ctx := context.Background()
 					if generated, err := agent.GenerateBootstrap(ctx, prov, bootstrapCfg); err == nil {
 						systemPrompt = generated
-						fmt.Println("✓ Generated enhanced system prompt")
+						fmt.Fprintln(progress, "✓ Generated enhanced system prompt")
 					} else {
-						fmt.Printf("⚠ Could not generate AI bootstrap: %v\n", err)
-						fmt.Println("  Using default prompt instead")
+						fmt.Fprintf(progress, "⚠ Could not generate AI bootstrap: %v\n", err)
+						fmt.Fprintln(progress, "  Using default prompt instead")
 					}
 				}
 			}
@@ -180,9 +257,30 @@ func runCreateAgent(cmd *cobra.Command, args []string) error {
 		Description:  agentDescription,
 		SystemPrompt: systemPrompt,
 		Model:        agentModel,
+		Provider:     agentProvider,
 		Tools:        strings.Split(agentTools, ","),
 		Skills:       skills,
 		Triggers:     triggers,
+		SharedMemory: agentShareMemory,
+	}
+	if tmpl != nil && tmpl.Policy != nil {
+		ab.Policy = &cluster.ToolPolicy{
+			AllowCommands:  tmpl.Policy.AllowCommands,
+			DenyCommands:   tmpl.Policy.DenyCommands,
+			WritablePaths:  tmpl.Policy.WritablePaths,
+			AllowedDomains: tmpl.Policy.AllowedDomains,
+		}
+		if _, err := tool.NewRegistry().WithPolicy(agentToolPolicy(ab), "."); err != nil {
+			return fmt.Errorf("template %s: %w", tmpl.Name, err)
+		}
+	}
+
+	if agentDryRun {
+		if err := store.ValidateAgentBinding(ab); err != nil {
+			return err
+		}
+		ab.CreatedAt = time.Now()
+		return printDryRun(fmt.Sprintf("Agent '%s' would be created in %s/%s", name, clusterName, namespace), ab)
 	}
 
 	if err := store.CreateAgentBinding(ab); err != nil {
@@ -190,14 +288,26 @@ func runCreateAgent(cmd *cobra.Command, args []string) error {
 	}
 
 	fmt.Printf("Agent '%s' created in %s/%s\n", name, clusterName, namespace)
+	if tmpl != nil {
+		fmt.Printf("  Template: %s\n", tmpl.Name)
+	}
 	fmt.Printf("  Description: %s\n", agentDescription)
 	fmt.Printf("  Model: %s\n", agentModel)
+	if agentProvider != "" {
+		fmt.Printf("  Provider: %s\n", agentProvider)
+	}
 	if len(skills) > 0 {
 		fmt.Printf("  Skills: %s\n", strings.Join(skills, ", "))
 	}
 	if len(triggers) > 0 {
 		fmt.Printf("  Triggers: %s\n", strings.Join(triggers, ", "))
 	}
+	if ab.Policy != nil {
+		fmt.Printf("  Policy: %s\n", describeToolPolicy(ab.Policy))
+	}
+	if ab.SharedMemory {
+		fmt.Println("  Memory: own + shared namespace memory")
+	}
 	fmt.Println("")
 	fmt.Println("The orchestrator will route messages to this agent based on:")
 	fmt.Println("  - Manual: /klaw @" + name + " <message>")
@@ -219,7 +329,7 @@ var getAgentsCmd = &cobra.Command{
 
 func runGetAgents(cmd *cobra.Command, args []string) error {
 	store := cluster.NewStore(config.StateDir())
-	ctxMgr := cluster.NewContextManager(config.ConfigDir())
+	ctxMgr := contextManager()
 
 	clusterName, namespace, err := ctxMgr.RequireCurrent()
 	if err != nil {
@@ -231,32 +341,62 @@ func runGetAgents(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
+	if structuredOutput() {
+		return printObject(agents)
+	}
+
 	if len(agents) == 0 {
 		fmt.Printf("No agents in %s/%s.\n", clusterName, namespace)
 		fmt.Println("Create one with: klaw create agent <name> --description \"...\"")
 		return nil
 	}
 
-	if jsonOut {
-		return json.NewEncoder(os.Stdout).Encode(agents)
-	}
-
 	fmt.Printf("Agents in %s/%s:\n\n", clusterName, namespace)
 
-	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
-	_, _ = fmt.Fprintln(w, "NAME\tMODEL\tDESCRIPTION\tTRIGGERS")
+	t := newTable("NAME", "MODEL", "DESCRIPTION", "TRIGGERS").withWide("PROVIDER", "SKILLS", "TOOLS", "CREATED")
 	for _, ag := range agents {
-		desc := truncateStr(ag.Description, 30)
-		triggers := ""
-		if len(ag.Triggers) > 0 {
-			triggers = strings.Join(ag.Triggers, ",")
-			if len(triggers) > 20 {
-				triggers = triggers[:17] + "..."
+		t.add(ag.Name, ag.Model, truncateCell(ag.Description, 30), truncateCell(strings.Join(ag.Triggers, ","), 20),
+			ag.Provider, strings.Join(ag.Skills, ","), strings.Join(ag.Tools, ","), ag.CreatedAt.Format("2006-01-02 15:04"))
+	}
+	return t.print()
+}
+
+// --- klaw get templates ---
+
+var getTemplatesCmd = &cobra.Command{
+	Use:     "templates",
+	Aliases: []string{"template"},
+	Short:   "List agent templates",
+	Long: `List the agent templates 'klaw create agent --from-template' can use.
+
+Besides the built-in ones, templates are read from ~/.klaw/templates/<name>.toml
+and from AGENT.toml files of installed skills.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		templates, err := agentTemplates().List()
+		if err != nil {
+			return err
+		}
+
+		if structuredOutput() {
+			return printObject(templates)
+		}
+
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		_, _ = fmt.Fprintln(w, "NAME\tSOURCE\tSKILLS\tDESCRIPTION")
+		for _, t := range templates {
+			source := t.Source
+			if source != "builtin" {
+				source = "local"
 			}
+			_, _ = fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", t.Name, source, joinOrNone(t.Skills), truncateStr(t.Description, 50))
 		}
-		_, _ = fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", ag.Name, ag.Model, desc, triggers)
-	}
-	return w.Flush()
+		if err := w.Flush(); err != nil {
+			return err
+		}
+		fmt.Println()
+		fmt.Println("Create an agent with: klaw create agent <name> --from-template <template>")
+		return nil
+	},
 }
 
 // --- klaw delete agent ---
@@ -269,7 +409,7 @@ var deleteAgentCmd = &cobra.Command{
 		name := args[0]
 
 		store := cluster.NewStore(config.StateDir())
-		ctxMgr := cluster.NewContextManager(config.ConfigDir())
+		ctxMgr := contextManager()
 
 		clusterName, namespace, err := ctxMgr.RequireCurrent()
 		if err != nil {
@@ -295,7 +435,7 @@ var describeAgentCmd = &cobra.Command{
 		name := args[0]
 
 		store := cluster.NewStore(config.StateDir())
-		ctxMgr := cluster.NewContextManager(config.ConfigDir())
+		ctxMgr := contextManager()
 
 		clusterName, namespace, err := ctxMgr.RequireCurrent()
 		if err != nil {
@@ -307,8 +447,8 @@ var describeAgentCmd = &cobra.Command{
 			return err
 		}
 
-		if jsonOut {
-			return json.NewEncoder(os.Stdout).Encode(ag)
+		if structuredOutput() {
+			return printObject(ag)
 		}
 
 		fmt.Printf("Name:        %s\n", ag.Name)
@@ -316,10 +456,29 @@ var describeAgentCmd = &cobra.Command{
 		fmt.Printf("Namespace:   %s\n", ag.Namespace)
 		fmt.Printf("Description: %s\n", ag.Description)
 		fmt.Printf("Model:       %s\n", ag.Model)
+		if ag.Provider != "" {
+			fmt.Printf("Provider:    %s\n", ag.Provider)
+		}
 		fmt.Printf("Tools:       %s\n", strings.Join(ag.Tools, ", "))
 		if len(ag.Triggers) > 0 {
 			fmt.Printf("Triggers:    %s\n", strings.Join(ag.Triggers, ", "))
 		}
+		if len(ag.SkillConfig) > 0 {
+			var keys []string
+			for skillName, kv := range ag.SkillConfig {
+				for k := range kv {
+					keys = append(keys, skillName+"."+k)
+				}
+			}
+			sort.Strings(keys)
+			fmt.Printf("Config:      %s\n", strings.Join(keys, ", "))
+		}
+		if ag.Policy != nil {
+			fmt.Printf("Policy:      %s\n", describeToolPolicy(ag.Policy))
+		}
+		if cfg, err := config.Load(); err == nil {
+			fmt.Printf("Memory:      %s\n", describeAgentMemory(cfg.WorkspaceDir(), ag))
+		}
 		fmt.Printf("Created:     %s\n", ag.CreatedAt.Format(time.RFC3339))
 		fmt.Println("---")
 		fmt.Printf("System Prompt:\n%s\n", ag.SystemPrompt)
@@ -328,6 +487,615 @@ var describeAgentCmd = &cobra.Command{
 	},
 }
 
+// --- klaw agent config ---
+
+var agentCmd = &cobra.Command{
+	Use:   "agent",
+	Short: "Manage agent settings",
+}
+
+var agentConfigCmd = &cobra.Command{
+	Use:   "config",
+	Short: "Manage per-agent skill configuration",
+	Long: `Manage skill configuration values (API keys, DSNs, ...) for an agent.
+
+Values are keyed by skill and stored encrypted. At runtime they are passed
+to the matching tools and exposed to bash as KLAW_<SKILL>_<KEY>.
+
+Examples:
+  klaw agent config set coder database.dsn=postgres://localhost/app
+  klaw agent config set researcher web-search.api_key=BSA...
+  klaw agent config get coder
+  klaw agent config unset coder database.dsn`,
+}
+
+var agentConfigShowValues bool
+
+var agentConfigSetCmd = &cobra.Command{
+	Use:   "set <agent> <skill.key=value>...",
+	Short: "Set skill config values on an agent",
+	Args:  cobra.MinimumNArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		store := cluster.NewStore(config.StateDir())
+		ctxMgr := contextManager()
+
+		clusterName, namespace, err := ctxMgr.RequireCurrent()
+		if err != nil {
+			return err
+		}
+
+		for _, arg := range args[1:] {
+			ref, value, ok := strings.Cut(arg, "=")
+			if !ok {
+				return fmt.Errorf("invalid assignment %q (expected skill.key=value)", arg)
+			}
+			skillName, key, err := parseSkillConfigKey(ref)
+			if err != nil {
+				return err
+			}
+			if err := store.SetAgentSkillConfig(clusterName, namespace, args[0], skillName, key, value); err != nil {
+				return err
+			}
+			fmt.Printf("Set %s.%s on agent '%s'\n", skillName, key, args[0])
+		}
+		return nil
+	},
+}
+
+var agentConfigGetCmd = &cobra.Command{
+	Use:   "get <agent>",
+	Short: "Show skill config values of an agent",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		store := cluster.NewStore(config.StateDir())
+		ctxMgr := contextManager()
+
+		clusterName, namespace, err := ctxMgr.RequireCurrent()
+		if err != nil {
+			return err
+		}
+
+		ab, err := store.GetAgentBinding(clusterName, namespace, args[0])
+		if err != nil {
+			return err
+		}
+		values, err := store.AgentSkillConfig(ab)
+		if err != nil {
+			return err
+		}
+
+		if !agentConfigShowValues {
+			for _, kv := range values {
+				for k, v := range kv {
+					kv[k] = maskToken(v)
+				}
+			}
+		}
+
+		if structuredOutput() {
+			return printObject(values)
+		}
+
+		if len(values) == 0 {
+			fmt.Printf("No skill config set for agent '%s'.\n", args[0])
+			return nil
+		}
+
+		var keys []string
+		for skillName, kv := range values {
+			for k := range kv {
+				keys = append(keys, skillName+"."+k)
+			}
+		}
+		sort.Strings(keys)
+
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		_, _ = fmt.Fprintln(w, "KEY\tVALUE")
+		for _, ref := range keys {
+			skillName, key, _ := parseSkillConfigKey(ref)
+			_, _ = fmt.Fprintf(w, "%s\t%s\n", ref, values[skillName][key])
+		}
+		return w.Flush()
+	},
+}
+
+var agentConfigUnsetCmd = &cobra.Command{
+	Use:   "unset <agent> <skill.key>",
+	Short: "Remove a skill config value from an agent",
+	Args:  cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		store := cluster.NewStore(config.StateDir())
+		ctxMgr := contextManager()
+
+		clusterName, namespace, err := ctxMgr.RequireCurrent()
+		if err != nil {
+			return err
+		}
+
+		skillName, key, err := parseSkillConfigKey(args[1])
+		if err != nil {
+			return err
+		}
+		if err := store.UnsetAgentSkillConfig(clusterName, namespace, args[0], skillName, key); err != nil {
+			return err
+		}
+
+		fmt.Printf("Removed %s.%s from agent '%s'\n", skillName, key, args[0])
+		return nil
+	},
+}
+
+func init() {
+	agentConfigGetCmd.Flags().BoolVar(&agentConfigShowValues, "show-values", false, "Show values unmasked")
+}
+
+// parseSkillConfigKey splits "skill.key" on the first dot.
+func parseSkillConfigKey(ref string) (string, string, error) {
+	skillName, key, ok := strings.Cut(ref, ".")
+	if !ok || skillName == "" || key == "" {
+		return "", "", fmt.Errorf("invalid key %q (expected skill.key)", ref)
+	}
+	return skillName, key, nil
+}
+
+// --- klaw agent policy ---
+
+var agentPolicyCmd = &cobra.Command{
+	Use:   "policy",
+	Short: "Manage per-agent tool permission policies",
+	Long: `Restrict what an agent's tools may do.
+
+  --allow-command   regex a bash command must match (repeatable)
+  --deny-command    regex that blocks a bash command (repeatable)
+  --writable-path   path prefix write/edit may modify (repeatable)
+  --allow-domain    host HTTP, web_fetch and browser tools may reach (repeatable)
+
+Examples:
+  klaw agent policy set coder --deny-command 'rm\s+-rf' --writable-path ./src
+  klaw agent policy set researcher --allow-domain wikipedia.org --allow-domain arxiv.org
+  klaw agent policy show coder
+  klaw agent policy clear coder`,
+}
+
+var (
+	policyAllowCommands  []string
+	policyDenyCommands   []string
+	policyWritablePaths  []string
+	policyAllowedDomains []string
+)
+
+var agentPolicySetCmd = &cobra.Command{
+	Use:   "set <agent>",
+	Short: "Set tool policy rules on an agent",
+	Long: `Set tool policy rules on an agent. Only the rule types given on the
+command line are replaced; others are kept.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		store := cluster.NewStore(config.StateDir())
+		ctxMgr := contextManager()
+
+		clusterName, namespace, err := ctxMgr.RequireCurrent()
+		if err != nil {
+			return err
+		}
+
+		ab, err := store.GetAgentBinding(clusterName, namespace, args[0])
+		if err != nil {
+			return err
+		}
+
+		policy := &cluster.ToolPolicy{}
+		if ab.Policy != nil {
+			policy = ab.Policy
+		}
+		flags := cmd.Flags()
+		if flags.Changed("allow-command") {
+			policy.AllowCommands = policyAllowCommands
+		}
+		if flags.Changed("deny-command") {
+			policy.DenyCommands = policyDenyCommands
+		}
+		if flags.Changed("writable-path") {
+			policy.WritablePaths = policyWritablePaths
+		}
+		if flags.Changed("allow-domain") {
+			policy.AllowedDomains = policyAllowedDomains
+		}
+
+		// Validate the regexes before saving
+		if _, err := tool.NewRegistry().WithPolicy(agentToolPolicy(&cluster.AgentBinding{Policy: policy}), "."); err != nil {
+			return err
+		}
+
+		ab.Policy = policy
+		if err := store.UpdateAgentBinding(ab); err != nil {
+			return err
+		}
+
+		fmt.Printf("Updated policy for agent '%s': %s\n", args[0], describeToolPolicy(policy))
+		return nil
+	},
+}
+
+var agentPolicyShowCmd = &cobra.Command{
+	Use:   "show <agent>",
+	Short: "Show the tool policy of an agent",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		store := cluster.NewStore(config.StateDir())
+		ctxMgr := contextManager()
+
+		clusterName, namespace, err := ctxMgr.RequireCurrent()
+		if err != nil {
+			return err
+		}
+
+		ab, err := store.GetAgentBinding(clusterName, namespace, args[0])
+		if err != nil {
+			return err
+		}
+
+		if structuredOutput() {
+			return printObject(ab.Policy)
+		}
+
+		if ab.Policy == nil {
+			fmt.Printf("No tool policy set for agent '%s'.\n", args[0])
+			return nil
+		}
+
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		_, _ = fmt.Fprintln(w, "RULE\tVALUES")
+		_, _ = fmt.Fprintf(w, "allow-command\t%s\n", joinOrNone(ab.Policy.AllowCommands))
+		_, _ = fmt.Fprintf(w, "deny-command\t%s\n", joinOrNone(ab.Policy.DenyCommands))
+		_, _ = fmt.Fprintf(w, "writable-path\t%s\n", joinOrNone(ab.Policy.WritablePaths))
+		_, _ = fmt.Fprintf(w, "allow-domain\t%s\n", joinOrNone(ab.Policy.AllowedDomains))
+		return w.Flush()
+	},
+}
+
+var agentPolicyClearCmd = &cobra.Command{
+	Use:   "clear <agent>",
+	Short: "Remove the tool policy from an agent",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		store := cluster.NewStore(config.StateDir())
+		ctxMgr := contextManager()
+
+		clusterName, namespace, err := ctxMgr.RequireCurrent()
+		if err != nil {
+			return err
+		}
+
+		ab, err := store.GetAgentBinding(clusterName, namespace, args[0])
+		if err != nil {
+			return err
+		}
+		ab.Policy = nil
+		if err := store.UpdateAgentBinding(ab); err != nil {
+			return err
+		}
+
+		fmt.Printf("Cleared policy for agent '%s'\n", args[0])
+		return nil
+	},
+}
+
+func init() {
+	agentPolicySetCmd.Flags().StringArrayVar(&policyAllowCommands, "allow-command", nil, "Regex bash commands must match")
+	agentPolicySetCmd.Flags().StringArrayVar(&policyDenyCommands, "deny-command", nil, "Regex that blocks bash commands")
+	agentPolicySetCmd.Flags().StringArrayVar(&policyWritablePaths, "writable-path", nil, "Path prefix write/edit may modify")
+	agentPolicySetCmd.Flags().StringArrayVar(&policyAllowedDomains, "allow-domain", nil, "Domain network tools may reach")
+}
+
+// --- klaw agent memory ---
+
+var agentMemoryCmd = &cobra.Command{
+	Use:   "memory",
+	Short: "Manage which memory an agent sees",
+	Long: `Each agent keeps its own memory (MEMORY.md and USER.md in
+<workspace>/agents/<agent>), which other agents don't see. Agents can also
+opt in to the namespace's shared memory (<workspace>/namespaces/<cluster>/<namespace>),
+which the main agent always sees.
+
+Examples:
+  klaw agent memory share support
+  klaw agent memory isolate support`,
+}
+
+var agentMemoryShareCmd = &cobra.Command{
+	Use:   "share <agent>",
+	Short: "Give an agent the namespace's shared memory",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return setAgentSharedMemory(args[0], true)
+	},
+}
+
+var agentMemoryIsolateCmd = &cobra.Command{
+	Use:   "isolate <agent>",
+	Short: "Limit an agent to its own memory",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return setAgentSharedMemory(args[0], false)
+	},
+}
+
+func setAgentSharedMemory(name string, shared bool) error {
+	store := cluster.NewStore(config.StateDir())
+	ctxMgr := contextManager()
+
+	clusterName, namespace, err := ctxMgr.RequireCurrent()
+	if err != nil {
+		return err
+	}
+
+	ab, err := store.GetAgentBinding(clusterName, namespace, name)
+	if err != nil {
+		return err
+	}
+	ab.SharedMemory = shared
+	if err := store.UpdateAgentBinding(ab); err != nil {
+		return err
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Agent '%s' memory: %s\n", name, describeAgentMemory(cfg.WorkspaceDir(), ab))
+	return nil
+}
+
+// describeAgentMemory names the memory directories an agent sees.
+func describeAgentMemory(workspaceDir string, ab *cluster.AgentBinding) string {
+	desc := memory.AgentDir(workspaceDir, ab.Name)
+	if ab.SharedMemory {
+		desc += " + shared " + memory.NamespaceDir(workspaceDir, ab.Cluster, ab.Namespace)
+	}
+	return desc
+}
+
+// --- klaw agent bootstrap ---
+
+var agentBootstrapCmd = &cobra.Command{
+	Use:   "bootstrap",
+	Short: "Manage generated agent system prompts",
+}
+
+var agentBootstrapYes bool
+
+var agentBootstrapRegenerateCmd = &cobra.Command{
+	Use:   "regenerate <agent>",
+	Short: "Regenerate an agent's system prompt",
+	Long: `Regenerate an agent's system prompt from its current description,
+skills and tools, using the agent's own provider and model. The change is
+shown as a diff and saved after confirmation.
+
+Examples:
+  klaw agent bootstrap regenerate coder
+  klaw agent bootstrap regenerate coder --yes`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		store := cluster.NewStore(config.StateDir())
+		ctxMgr := contextManager()
+
+		clusterName, namespace, err := ctxMgr.RequireCurrent()
+		if err != nil {
+			return err
+		}
+
+		ab, err := store.GetAgentBinding(clusterName, namespace, args[0])
+		if err != nil {
+			return err
+		}
+
+		cfg, err := config.Load()
+		if err != nil {
+			return fmt.Errorf("failed to load config: %w", err)
+		}
+		providerName, model := defaultProviderModel(cfg, "", "")
+		prov, err := buildProvider(cfg, providerName, model)
+		if err != nil {
+			return err
+		}
+		providers := newProviderPool(cfg, providerName, model, prov)
+		agentProv, err := providers.forAgent(ab)
+		if err != nil {
+			return err
+		}
+
+		fmt.Println("🤖 Generating system prompt...")
+		prompt, err := agentBootstrapFunc(agentProv)(cmd.Context(), ab)
+		if err != nil {
+			return err
+		}
+		if strings.TrimSpace(prompt) == "" {
+			return fmt.Errorf("the model returned an empty prompt")
+		}
+
+		diff := agent.PromptDiff(ab.SystemPrompt, prompt)
+		if diff == "" {
+			fmt.Printf("System prompt of agent '%s' is unchanged.\n", ab.Name)
+			return nil
+		}
+		fmt.Println()
+		fmt.Print(diff)
+		fmt.Println()
+
+		if !agentBootstrapYes {
+			fmt.Printf("Save the new system prompt for agent '%s'? [y/N] ", ab.Name)
+			var response string
+			_, _ = fmt.Scanln(&response)
+			if strings.ToLower(response) != "y" {
+				fmt.Println("Cancelled.")
+				return nil
+			}
+		}
+
+		ab.SystemPrompt = prompt
+		if err := store.UpdateAgentBinding(ab); err != nil {
+			return err
+		}
+		fmt.Printf("✓ Updated system prompt of agent '%s'\n", ab.Name)
+		return nil
+	},
+}
+
+func init() {
+	agentBootstrapRegenerateCmd.Flags().BoolVarP(&agentBootstrapYes, "yes", "y", false, "Save without asking")
+}
+
+// agentToolPolicy converts an agent's stored policy to a tool.Policy.
+func agentToolPolicy(ab *cluster.AgentBinding) tool.Policy {
+	if ab == nil || ab.Policy == nil {
+		return tool.Policy{}
+	}
+	return tool.Policy{
+		AllowCommands:  ab.Policy.AllowCommands,
+		DenyCommands:   ab.Policy.DenyCommands,
+		WritablePaths:  ab.Policy.WritablePaths,
+		AllowedDomains: ab.Policy.AllowedDomains,
+	}
+}
+
+// agentHooks returns the shell hooks configured for an agent under
+// [agent.<name>].
+func agentHooks(cfg *config.Config, name string) ([]agent.Hook, error) {
+	var hooks []agent.Hook
+	for _, h := range cfg.Agents[name].Hooks {
+		point := agent.HookPoint(h.Event)
+		switch point {
+		case agent.HookPreMessage, agent.HookPostMessage, agent.HookPreTool, agent.HookPostTool, agent.HookError:
+		default:
+			return nil, fmt.Errorf("agent %s: unknown hook event %q (use pre_message, post_message, pre_tool, post_tool or error)", name, h.Event)
+		}
+		if h.Command == "" {
+			return nil, fmt.Errorf("agent %s: %s hook has no command", name, h.Event)
+		}
+		hooks = append(hooks, &agent.ShellHook{
+			Point:   point,
+			Command: h.Command,
+			Tools:   h.Tools,
+			Timeout: time.Duration(h.Timeout) * time.Second,
+		})
+	}
+	return hooks, nil
+}
+
+// agentToolRegistry returns the tools an agent runs with: base plus the
+// tools provided by its skills, restricted by its policy. With an agents
+// root set in [workspace], they work in the agent's own directory instead
+// of workDir.
+func agentToolRegistry(cfg *config.Config, base *tool.Registry, ab *cluster.AgentBinding, workDir string) (*tool.Registry, error) {
+	if dir := cfg.AgentWorkDir(ab.Cluster, ab.Namespace, ab.Name); dir != "" {
+		rooted, err := base.WithRoot(dir)
+		if err != nil {
+			return nil, err
+		}
+		base, workDir = rooted, dir
+	}
+	return withSkillTools(base, ab.Skills, workDir).WithPolicy(agentToolPolicy(ab), workDir)
+}
+
+// withSkillTools returns base plus the tools of the given skills. Tools
+// already in base are kept.
+func withSkillTools(base *tool.Registry, skills []string, workDir string) *tool.Registry {
+	skillTools, _ := skill.NewRegistry(config.StateDir()+"/skills").GetToolsForSkills(skills, workDir)
+	if len(skillTools.All()) == 0 {
+		return base
+	}
+	merged := tool.NewRegistry()
+	for _, t := range skillTools.All() {
+		merged.Register(t)
+	}
+	for _, t := range base.All() {
+		merged.Register(t)
+	}
+	return merged
+}
+
+// agentPrompt returns the system prompt of an agent binding: its own
+// prompt (or fallback when it has none) followed by its memory.
+func agentPrompt(ctx context.Context, workspaceDir string, ab *cluster.AgentBinding, fallback string) string {
+	prompt := ab.SystemPrompt
+	if prompt == "" {
+		prompt = fallback
+	}
+	return prompt + memory.AgentMemoryPrompt(ctx, workspaceDir, ab.Cluster, ab.Namespace, ab.Name, ab.SharedMemory)
+}
+
+// localAgentRunner runs agents of a namespace in-process for agent_dispatch,
+// with each agent's own system prompt, model, tool policy and skill config.
+// Their runtimes are kept warm between dispatches.
+func localAgentRunner(providers *providerPool, tools *tool.Registry, clusterName, namespace, workDir string) tool.AgentRunFunc {
+	store := cluster.NewStore(config.StateDir())
+	runtimes := newAgentRuntimes(providers, store, tools, workDir, nil)
+	return func(ctx context.Context, agentName, task string) (string, error) {
+		ab, err := store.GetAgentBinding(clusterName, namespace, agentName)
+		if err != nil {
+			return "", fmt.Errorf("agent not found: %s", agentName)
+		}
+		rt, err := runtimes.get(ab)
+		if err != nil {
+			return "", err
+		}
+		return agent.RunOnce(ctx, agent.RunOnceConfig{
+			Provider:     rt.provider,
+			Tools:        rt.tools,
+			SystemPrompt: rt.systemPrompt(ctx, providers.cfg.WorkspaceDir(), ""),
+			Prompt:       task,
+			MaxTokens:    8192,
+			SkillConfig:  rt.skillConfig,
+			AgentName:    ab.Name,
+			Secrets:      secretMasker(providers.cfg),
+			Audit:        storeAudit{store: store, cluster: clusterName, namespace: namespace, source: "dispatch"},
+		})
+	}
+}
+
+// agentBootstrapFunc regenerates an agent's system prompt with prov for
+// agent_update.
+func agentBootstrapFunc(prov provider.Provider) tool.BootstrapFunc {
+	return func(ctx context.Context, ab *cluster.AgentBinding) (string, error) {
+		return agent.GenerateBootstrap(ctx, prov, agent.BootstrapConfig{
+			Name:        ab.Name,
+			Description: ab.Description,
+			Skills:      ab.Skills,
+			Tools:       ab.Tools,
+			Model:       ab.Model,
+		})
+	}
+}
+
+// describeToolPolicy summarizes a policy on one line.
+func describeToolPolicy(p *cluster.ToolPolicy) string {
+	var parts []string
+	if len(p.AllowCommands) > 0 {
+		parts = append(parts, fmt.Sprintf("%d allowed commands", len(p.AllowCommands)))
+	}
+	if len(p.DenyCommands) > 0 {
+		parts = append(parts, fmt.Sprintf("%d denied commands", len(p.DenyCommands)))
+	}
+	if len(p.WritablePaths) > 0 {
+		parts = append(parts, "writable: "+strings.Join(p.WritablePaths, ", "))
+	}
+	if len(p.AllowedDomains) > 0 {
+		parts = append(parts, "domains: "+strings.Join(p.AllowedDomains, ", "))
+	}
+	if len(parts) == 0 {
+		return "(no restrictions)"
+	}
+	return strings.Join(parts, "; ")
+}
+
+func joinOrNone(values []string) string {
+	if len(values) == 0 {
+		return "-"
+	}
+	return strings.Join(values, ", ")
+}
+
 // --- klaw worker (internal, runs inside container) ---
 
 var workerCmd = &cobra.Command{
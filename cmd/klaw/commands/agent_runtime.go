@@ -0,0 +1,105 @@
+package commands
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+
+	"github.com/eachlabs/klaw/internal/cluster"
+	"github.com/eachlabs/klaw/internal/provider"
+	"github.com/eachlabs/klaw/internal/tool"
+)
+
+// agentRuntime is what an agent binding runs with, kept warm between runs
+// by a runtimePool.
+type agentRuntime struct {
+	binding      *cluster.AgentBinding
+	provider     provider.Provider
+	providerName string
+	model        string
+	tools        *tool.Registry // with the binding's policy applied
+	skillConfig  map[string]map[string]string
+	skillsPrompt string // instructions of the binding's skills
+
+	version string // the binding the runtime was built from, as JSON
+}
+
+// systemPrompt returns the system prompt of a run: the binding's prompt
+// (or fallback), its memory, which is read again each run, and its skills.
+func (rt *agentRuntime) systemPrompt(ctx context.Context, workspaceDir, fallback string) string {
+	return agentPrompt(ctx, workspaceDir, rt.binding, fallback) + rt.skillsPrompt
+}
+
+// runtimePool keeps the runtime of each agent binding. A runtime is built
+// on first use and again when its binding changes, e.g. with klaw agent
+// update, so runs don't repeat the setup of provider clients, tool
+// registries and skill prompts.
+type runtimePool struct {
+	build func(ab *cluster.AgentBinding) (*agentRuntime, error)
+
+	mu       sync.Mutex
+	runtimes map[string]*agentRuntime // by cluster/namespace/name
+}
+
+func newRuntimePool(build func(ab *cluster.AgentBinding) (*agentRuntime, error)) *runtimePool {
+	return &runtimePool{build: build, runtimes: make(map[string]*agentRuntime)}
+}
+
+// newAgentRuntimes returns a pool building runtimes from the provider pool
+// and the base tools. skillsPrompt, when set, compiles the instructions of
+// an agent's skills.
+func newAgentRuntimes(providers *providerPool, store *cluster.Store, base *tool.Registry, workDir string, skillsPrompt func(skills []string) string) *runtimePool {
+	return newRuntimePool(func(ab *cluster.AgentBinding) (*agentRuntime, error) {
+		tools, err := agentToolRegistry(providers.cfg, base, ab, workDir)
+		if err != nil {
+			return nil, fmt.Errorf("tool policy for agent %s: %w", ab.Name, err)
+		}
+		skillConfig, err := store.AgentSkillConfig(ab)
+		if err != nil {
+			return nil, fmt.Errorf("skill config for agent %s: %w", ab.Name, err)
+		}
+		name, model := providers.resolve(ab)
+		prov, err := providers.get(name, model)
+		if err != nil {
+			return nil, err
+		}
+		rt := &agentRuntime{provider: prov, providerName: name, model: model, tools: tools, skillConfig: skillConfig}
+		if skillsPrompt != nil {
+			rt.skillsPrompt = skillsPrompt(ab.Skills)
+		}
+		return rt, nil
+	})
+}
+
+// get returns the runtime of an agent binding.
+func (p *runtimePool) get(ab *cluster.AgentBinding) (*agentRuntime, error) {
+	data, err := json.Marshal(ab)
+	if err != nil {
+		return nil, err
+	}
+	key := ab.Cluster + "/" + ab.Namespace + "/" + ab.Name
+	version := string(data)
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if rt, ok := p.runtimes[key]; ok && rt.version == version {
+		return rt, nil
+	}
+	rt, err := p.build(ab)
+	if err != nil {
+		delete(p.runtimes, key)
+		return nil, err
+	}
+	rt.binding, rt.version = ab, version
+	p.runtimes[key] = rt
+	return rt, nil
+}
+
+// reset drops the runtimes, e.g. when skills change on disk, so that they
+// are built again on next use.
+func (p *runtimePool) reset() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	clear(p.runtimes)
+}
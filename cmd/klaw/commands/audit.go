@@ -0,0 +1,191 @@
+package commands
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/eachlabs/klaw/internal/agent"
+	"github.com/eachlabs/klaw/internal/cluster"
+	"github.com/eachlabs/klaw/internal/config"
+	"github.com/spf13/cobra"
+)
+
+// maxAuditInput is the number of bytes of tool input kept in the audit
+// trail.
+const maxAuditInput = 2000
+
+func init() {
+	auditCmd.AddCommand(auditToolsCmd)
+	rootCmd.AddCommand(auditCmd)
+}
+
+// --- klaw audit ---
+
+var auditCmd = &cobra.Command{
+	Use:   "audit",
+	Short: "Review what agents did",
+}
+
+var (
+	auditAgent        string
+	auditTool         string
+	auditConversation string
+	auditSince        string
+	auditErrors       bool
+	auditLimit        int
+)
+
+var auditToolsCmd = &cobra.Command{
+	Use:   "tools",
+	Short: "Show the tool calls agents made",
+	Long: `Show the tool calls agents made in the current namespace: the tool, its
+input, whether it failed, how long it took, and the agent and conversation
+it belongs to. Every tool call of the agents run by klaw start, chat, serve
+and the dashboard is kept in an append-only trail under the state
+directory; credentials in the input are masked before it is written.
+
+Examples:
+  klaw audit tools --agent coder --since 24h
+  klaw audit tools --tool bash --errors --since 7d
+  klaw audit tools --conversation C0123456789:1712345678.000100 -o wide
+  klaw audit tools --since 2024-06-01 -o json`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		store := cluster.NewStore(config.StateDir())
+		clusterName, namespace, err := contextManager().RequireCurrent()
+		if err != nil {
+			return err
+		}
+		since, err := parseSince(auditSince, time.Now())
+		if err != nil {
+			return err
+		}
+
+		records, err := store.ListToolAudit(clusterName, namespace, cluster.ToolAuditFilter{
+			Since:        since,
+			Agent:        auditAgent,
+			Tool:         auditTool,
+			Conversation: auditConversation,
+			ErrorsOnly:   auditErrors,
+		})
+		if err != nil {
+			return err
+		}
+		if auditLimit > 0 && len(records) > auditLimit {
+			records = records[len(records)-auditLimit:]
+		}
+
+		if structuredOutput() {
+			return printObject(records)
+		}
+		if len(records) == 0 {
+			fmt.Printf("No tool calls in %s/%s since %s.\n", clusterName, namespace, since.Format("2006-01-02 15:04"))
+			return nil
+		}
+
+		t := newTable("TIME", "AGENT", "TOOL", "STATUS", "DURATION", "INPUT").withWide("CONVERSATION", "SOURCE", "ERROR")
+		for _, rec := range records {
+			t.add(
+				rec.Timestamp.Local().Format("2006-01-02 15:04:05"),
+				rec.Agent,
+				rec.Tool,
+				rec.Status,
+				(time.Duration(rec.DurationMS) * time.Millisecond).String(),
+				truncateCell(auditInputSummary(rec.Input), 60),
+				rec.Conversation,
+				rec.Source,
+				rec.Error,
+			)
+		}
+		return t.print()
+	},
+}
+
+func init() {
+	auditToolsCmd.Flags().StringVar(&auditAgent, "agent", "", "Only calls of this agent")
+	auditToolsCmd.Flags().StringVar(&auditTool, "tool", "", "Only calls of this tool")
+	auditToolsCmd.Flags().StringVar(&auditConversation, "conversation", "", "Only calls of this conversation")
+	auditToolsCmd.Flags().StringVar(&auditSince, "since", "24h", "How far back to look: a duration such as 24h or 7d, or a date")
+	auditToolsCmd.Flags().BoolVar(&auditErrors, "errors", false, "Only failed calls")
+	auditToolsCmd.Flags().IntVar(&auditLimit, "limit", 0, "Show only the last N calls (0 = all)")
+}
+
+// parseSince parses a --since value: a duration before now ("90m",
+// "24h", "7d") or a date ("2006-01-02" or RFC 3339).
+func parseSince(s string, now time.Time) (time.Time, error) {
+	if days, ok := strings.CutSuffix(s, "d"); ok {
+		if n, err := strconv.Atoi(days); err == nil && n >= 0 {
+			return now.AddDate(0, 0, -n), nil
+		}
+	}
+	if d, err := time.ParseDuration(s); err == nil {
+		return now.Add(-d), nil
+	}
+	if t, err := time.ParseInLocation("2006-01-02", s, time.Local); err == nil {
+		return t, nil
+	}
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return t, nil
+	}
+	return time.Time{}, fmt.Errorf("invalid --since %q: use a duration such as 24h or 7d, or a date such as 2006-01-02", s)
+}
+
+// auditInputSummary shows the command of a bash call, the path or URL of
+// file and web tools, and the JSON input of other tools.
+func auditInputSummary(input string) string {
+	var args map[string]any
+	if err := json.Unmarshal([]byte(input), &args); err == nil {
+		for _, key := range []string{"command", "path", "url"} {
+			if v, ok := args[key].(string); ok && v != "" {
+				return v
+			}
+		}
+	}
+	return input
+}
+
+// storeAudit keeps the tool calls of agents in the namespace's audit trail.
+type storeAudit struct {
+	store     *cluster.Store
+	cluster   string
+	namespace string
+	source    string
+}
+
+func (a storeAudit) RecordToolCall(call agent.ToolCall) {
+	rec := &cluster.ToolAuditRecord{
+		Cluster:      a.cluster,
+		Namespace:    a.namespace,
+		Agent:        call.Agent,
+		Conversation: call.ConversationID,
+		Source:       a.source,
+		Tool:         call.Tool,
+		Input:        truncateAuditInput(call.Input),
+		Status:       "ok",
+		DurationMS:   call.Duration.Milliseconds(),
+	}
+	if call.Result.IsError {
+		rec.Status = "error"
+		rec.Error, _, _ = strings.Cut(call.Result.Content, "\n")
+		rec.Error = truncateAuditInput(rec.Error)
+	}
+	if err := a.store.AppendToolAudit(rec); err != nil {
+		fmt.Printf("Warning: failed to record tool call: %v\n", err)
+	}
+}
+
+// truncateAuditInput cuts s to maxAuditInput bytes without splitting a
+// character.
+func truncateAuditInput(s string) string {
+	if len(s) <= maxAuditInput {
+		return s
+	}
+	cut := maxAuditInput
+	for cut > 0 && !utf8.RuneStart(s[cut]) {
+		cut--
+	}
+	return s[:cut] + "…"
+}
@@ -0,0 +1,317 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/eachlabs/klaw/internal/cluster"
+	"github.com/eachlabs/klaw/internal/config"
+	"github.com/eachlabs/klaw/internal/notify"
+	"github.com/eachlabs/klaw/internal/scheduler"
+	"github.com/spf13/cobra"
+)
+
+// pausedByBudget marks cron jobs paused by a budget alert.
+const pausedByBudget = "budget"
+
+func init() {
+	namespaceBudgetCmd.AddCommand(namespaceBudgetSetCmd)
+	namespaceBudgetCmd.AddCommand(namespaceBudgetShowCmd)
+	namespaceBudgetCmd.AddCommand(namespaceBudgetClearCmd)
+	namespaceCmd.AddCommand(namespaceBudgetCmd)
+}
+
+// --- klaw namespace budget ---
+
+var namespaceBudgetCmd = &cobra.Command{
+	Use:   "budget",
+	Short: "Manage the namespace spend thresholds",
+	Long: `Set daily and monthly spend thresholds for a namespace. Spend is the
+estimated cost of the namespace's provider requests.
+
+While klaw start runs, a threshold being reached posts an alert to the
+alert channel and, with --pause-jobs, pauses the namespace's cron jobs
+except those kept with --keep-job. Paused jobs resume when the next day or
+month starts, or when the threshold is raised.
+
+Examples:
+  klaw namespace budget set --daily 5 --monthly 100 --alert-channel C0123456789
+  klaw namespace budget set --pause-jobs --keep-job inbox-triage
+  klaw namespace budget show
+  klaw namespace budget clear`,
+}
+
+var (
+	budgetNamespace    string
+	budgetDaily        float64
+	budgetMonthly      float64
+	budgetAlertChannel string
+	budgetPauseJobs    bool
+	budgetKeepJobs     []string
+)
+
+// budgetTarget resolves the cluster and namespace for the budget commands.
+func budgetTarget() (string, string, error) {
+	clusterName, namespace, err := contextManager().RequireCurrent()
+	if err != nil {
+		return "", "", err
+	}
+	if budgetNamespace != "" {
+		namespace = budgetNamespace
+	}
+	return clusterName, namespace, nil
+}
+
+var namespaceBudgetSetCmd = &cobra.Command{
+	Use:   "set",
+	Short: "Set spend thresholds for a namespace",
+	Long:  `Set spend thresholds for a namespace. Only the flags given are changed.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		store := cluster.NewStore(config.StateDir())
+		clusterName, namespace, err := budgetTarget()
+		if err != nil {
+			return err
+		}
+
+		budget, err := store.NamespaceBudget(clusterName, namespace)
+		if err != nil {
+			return err
+		}
+		if budget == nil {
+			budget = &cluster.BudgetConfig{}
+		}
+
+		flags := cmd.Flags()
+		if flags.Changed("daily") {
+			budget.Daily = budgetDaily
+		}
+		if flags.Changed("monthly") {
+			budget.Monthly = budgetMonthly
+		}
+		if flags.Changed("alert-channel") {
+			budget.AlertChannel = budgetAlertChannel
+		}
+		if flags.Changed("pause-jobs") {
+			budget.PauseJobs = budgetPauseJobs
+		}
+		if flags.Changed("keep-job") {
+			budget.KeepJobs = budgetKeepJobs
+		}
+		if budget.Daily < 0 || budget.Monthly < 0 {
+			return fmt.Errorf("thresholds must not be negative")
+		}
+
+		if err := store.SetNamespaceBudget(clusterName, namespace, budget); err != nil {
+			return err
+		}
+
+		fmt.Printf("Budget updated for namespace '%s'.\n", namespace)
+		if budget.Daily == 0 && budget.Monthly == 0 {
+			fmt.Println("Note: set --daily or --monthly for alerts to be sent.")
+		}
+		return nil
+	},
+}
+
+var namespaceBudgetShowCmd = &cobra.Command{
+	Use:   "show",
+	Short: "Show the thresholds and current spend of a namespace",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		store := cluster.NewStore(config.StateDir())
+		clusterName, namespace, err := budgetTarget()
+		if err != nil {
+			return err
+		}
+
+		budget, err := store.NamespaceBudget(clusterName, namespace)
+		if err != nil {
+			return err
+		}
+		status, err := store.BudgetStatus(clusterName, namespace, budget, time.Now())
+		if err != nil {
+			return err
+		}
+
+		if structuredOutput() {
+			return printObject(struct {
+				Budget *cluster.BudgetConfig `json:"budget"`
+				Status *cluster.BudgetStatus `json:"status"`
+			}{budget, status})
+		}
+
+		if budget == nil {
+			fmt.Printf("No budget for namespace '%s'.\n", namespace)
+			fmt.Printf("Spend:       $%.2f today, $%.2f this month\n", status.DailySpend, status.MonthlySpend)
+			return nil
+		}
+		fmt.Printf("Daily:       %s\n", budgetLine(status.DailySpend, budget.Daily))
+		fmt.Printf("Monthly:     %s\n", budgetLine(status.MonthlySpend, budget.Monthly))
+		alertChannel := budget.AlertChannel
+		if alertChannel == "" {
+			alertChannel = "(none, logged by klaw start only)"
+		}
+		fmt.Printf("Alerts:      %s\n", alertChannel)
+		if budget.PauseJobs {
+			kept := "(none)"
+			if len(budget.KeepJobs) > 0 {
+				kept = strings.Join(budget.KeepJobs, ", ")
+			}
+			fmt.Printf("Pause jobs:  yes, keeping %s\n", kept)
+		} else {
+			fmt.Printf("Pause jobs:  no\n")
+		}
+		return nil
+	},
+}
+
+// budgetLine shows spend against a threshold.
+func budgetLine(spend, limit float64) string {
+	if limit == 0 {
+		return fmt.Sprintf("$%.2f (no threshold)", spend)
+	}
+	line := fmt.Sprintf("$%.2f of $%.2f (%.0f%%)", spend, limit, spend/limit*100)
+	if spend >= limit {
+		line += " - exceeded"
+	}
+	return line
+}
+
+var namespaceBudgetClearCmd = &cobra.Command{
+	Use:   "clear",
+	Short: "Remove the budget of a namespace",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		store := cluster.NewStore(config.StateDir())
+		clusterName, namespace, err := budgetTarget()
+		if err != nil {
+			return err
+		}
+
+		if err := store.SetNamespaceBudget(clusterName, namespace, nil); err != nil {
+			return err
+		}
+		fmt.Printf("Budget removed from namespace '%s'.\n", namespace)
+		return nil
+	},
+}
+
+func init() {
+	namespaceBudgetCmd.PersistentFlags().StringVarP(&budgetNamespace, "namespace", "n", "", "namespace (uses current if not set)")
+	namespaceBudgetSetCmd.Flags().Float64Var(&budgetDaily, "daily", 0, "Daily threshold in USD (0 = none)")
+	namespaceBudgetSetCmd.Flags().Float64Var(&budgetMonthly, "monthly", 0, "Monthly threshold in USD (0 = none)")
+	namespaceBudgetSetCmd.Flags().StringVar(&budgetAlertChannel, "alert-channel", "", "Slack channel ID to alert")
+	namespaceBudgetSetCmd.Flags().BoolVar(&budgetPauseJobs, "pause-jobs", false, "Pause cron jobs while over budget")
+	namespaceBudgetSetCmd.Flags().StringArrayVar(&budgetKeepJobs, "keep-job", nil, "Job that keeps running when over budget (repeatable)")
+}
+
+// budgetWatcher checks a namespace's spend against its budget while klaw
+// start runs. Each crossed threshold is alerted once per day or month.
+type budgetWatcher struct {
+	store     *cluster.Store
+	sched     *scheduler.Scheduler
+	cluster   string
+	namespace string
+	alert     func(channelID, text string) error
+	notify    *notify.Notifier
+
+	alerted map[string]bool // "daily:2006-01-02", "monthly:2006-01"
+}
+
+func (w *budgetWatcher) run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		if err := w.check(time.Now()); err != nil {
+			fmt.Printf("Warning: budget check: %v\n", err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (w *budgetWatcher) check(now time.Time) error {
+	budget, err := w.store.NamespaceBudget(w.cluster, w.namespace)
+	if err != nil {
+		return err
+	}
+	if budget == nil {
+		return w.resumeJobs()
+	}
+	status, err := w.store.BudgetStatus(w.cluster, w.namespace, budget, now)
+	if err != nil {
+		return err
+	}
+
+	if w.alerted == nil {
+		w.alerted = make(map[string]bool)
+	}
+	if status.DailyOver && !w.alerted["daily:"+status.Day] {
+		w.alerted["daily:"+status.Day] = true
+		w.send(budget, fmt.Sprintf("Daily budget of namespace %s/%s reached: $%.2f of $%.2f spent today.",
+			w.cluster, w.namespace, status.DailySpend, budget.Daily), status)
+	}
+	if status.MonthlyOver && !w.alerted["monthly:"+status.Month] {
+		w.alerted["monthly:"+status.Month] = true
+		w.send(budget, fmt.Sprintf("Monthly budget of namespace %s/%s reached: $%.2f of $%.2f spent this month.",
+			w.cluster, w.namespace, status.MonthlySpend, budget.Monthly), status)
+	}
+
+	if status.Over() && budget.PauseJobs {
+		return w.pauseJobs(budget)
+	}
+	return w.resumeJobs()
+}
+
+// send posts an alert to the budget's alert channel and the webhooks, and
+// logs it.
+func (w *budgetWatcher) send(budget *cluster.BudgetConfig, text string, status *cluster.BudgetStatus) {
+	if budget.PauseJobs {
+		text += " Cron jobs are paused until spend is back under budget."
+	}
+	fmt.Printf("[%s] ⚠ %s\n", time.Now().Format("15:04:05"), text)
+	w.notify.Notify(notify.Event{Type: notify.EventBudgetExceeded, Cluster: w.cluster, Namespace: w.namespace, Message: text})
+	if budget.AlertChannel == "" || w.alert == nil {
+		return
+	}
+	if err := w.alert(budget.AlertChannel, "⚠️ "+text); err != nil {
+		fmt.Printf("Warning: budget alert: %v\n", err)
+	}
+}
+
+// pauseJobs disables the namespace's enabled jobs the budget doesn't keep.
+func (w *budgetWatcher) pauseJobs(budget *cluster.BudgetConfig) error {
+	for _, job := range w.sched.ListJobs(w.cluster, w.namespace) {
+		if !job.Enabled || budget.KeepsJob(job.Name) {
+			continue
+		}
+		if _, err := w.sched.UpdateJob(job.ID, func(j *scheduler.Job) {
+			j.Enabled = false
+			j.PausedBy = pausedByBudget
+		}); err != nil {
+			return err
+		}
+		fmt.Printf("[%s] Paused cron job %s (over budget)\n", time.Now().Format("15:04:05"), job.Name)
+	}
+	return nil
+}
+
+// resumeJobs enables the jobs a budget alert paused.
+func (w *budgetWatcher) resumeJobs() error {
+	for _, job := range w.sched.ListJobs(w.cluster, w.namespace) {
+		if job.PausedBy != pausedByBudget {
+			continue
+		}
+		if _, err := w.sched.UpdateJob(job.ID, func(j *scheduler.Job) {
+			j.Enabled = true
+			j.PausedBy = ""
+		}); err != nil {
+			return err
+		}
+		fmt.Printf("[%s] Resumed cron job %s\n", time.Now().Format("15:04:05"), job.Name)
+	}
+	return nil
+}
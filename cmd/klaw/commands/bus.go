@@ -0,0 +1,98 @@
+package commands
+
+import (
+	"fmt"
+	"net/url"
+	"os/signal"
+	"time"
+
+	"github.com/eachlabs/klaw/internal/bus"
+	"github.com/eachlabs/klaw/internal/cluster"
+	"github.com/eachlabs/klaw/internal/config"
+	"github.com/spf13/cobra"
+)
+
+// openBus connects to the [bus] of the config and returns it with the
+// subject prefix of the namespace.
+func openBus(cfg *config.Config, clusterName, namespace string) (bus.Bus, string, error) {
+	if cfg.Bus.URL == "" {
+		return nil, "", fmt.Errorf("set [bus] url in the config to run klaw start as a gateway or worker")
+	}
+	b, err := bus.Open(cfg.Bus.URL)
+	if err != nil {
+		return nil, "", err
+	}
+	prefix := cfg.Bus.Subject
+	if prefix == "" {
+		prefix = "klaw." + clusterName + "." + namespace
+	}
+	return b, prefix, nil
+}
+
+// busGroup is the queue group the workers share.
+func busGroup(cfg *config.Config) string {
+	if cfg.Bus.Group != "" {
+		return cfg.Bus.Group
+	}
+	return "workers"
+}
+
+// runGateway runs the channels of the namespace and relays their messages
+// to the workers over the bus.
+func runGateway(cmd *cobra.Command, cfg *config.Config) error {
+	clusterName, namespace, _ := contextManager().RequireCurrent()
+	if clusterName == "" {
+		clusterName = "default"
+		namespace = "default"
+	}
+
+	b, prefix, err := openBus(cfg, clusterName, namespace)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = b.Close() }()
+
+	store := cluster.NewStore(config.StateDir())
+	channels, err := openChannels(cfg, store, clusterName, namespace)
+	if err != nil {
+		return err
+	}
+	defer channels.stopped()
+
+	gw := &bus.Gateway{
+		Bus:     b,
+		Channel: channels.mux,
+		Prefix:  prefix,
+		ID:      instanceID(),
+	}
+
+	ctx, cancel := signal.NotifyContext(cmd.Context(), stopSignals...)
+	defer cancel()
+	go channels.run(ctx, 10*time.Second)
+
+	fmt.Println("╭─────────────────────────────────────────╮")
+	fmt.Println("│  klaw gateway                           │")
+	fmt.Println("╰─────────────────────────────────────────╯")
+	fmt.Printf("Namespace: %s/%s\n", clusterName, namespace)
+	fmt.Printf("Bus:       %s\n", redactURL(cfg.Bus.URL))
+	fmt.Printf("Subjects:  %s → workers, %s ← workers\n", bus.InSubject(prefix), bus.ReplySubject(prefix, gw.ID))
+	fmt.Println("")
+	printChannels(channels)
+	fmt.Println("Press Ctrl+C to stop")
+	fmt.Println("")
+
+	err = gw.Run(ctx)
+	if ctx.Err() != nil {
+		fmt.Println("\nShutting down...")
+	}
+	return err
+}
+
+// redactURL hides the password of a bus or lease store URL.
+func redactURL(raw string) string {
+	u, err := url.Parse(raw)
+	if err != nil {
+		return raw
+	}
+	return u.Redacted()
+}
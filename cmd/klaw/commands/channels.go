@@ -4,13 +4,13 @@ import (
 	"context"
 	"fmt"
 	"os"
-	"os/signal"
-	"syscall"
+	"strings"
 
 	"github.com/eachlabs/klaw/internal/agent"
 	"github.com/eachlabs/klaw/internal/channel"
 	"github.com/eachlabs/klaw/internal/cluster"
 	"github.com/eachlabs/klaw/internal/config"
+	"github.com/eachlabs/klaw/internal/history"
 	"github.com/eachlabs/klaw/internal/memory"
 	"github.com/eachlabs/klaw/internal/provider"
 	"github.com/eachlabs/klaw/internal/tool"
@@ -44,7 +44,7 @@ func runChannelBot(cmd *cobra.Command, args []string) error {
 	name := args[0]
 
 	store := cluster.NewStore(config.StateDir())
-	ctxMgr := cluster.NewContextManager(config.ConfigDir())
+	ctxMgr := contextManager()
 
 	clusterName, namespace, err := ctxMgr.RequireCurrent()
 	if err != nil {
@@ -105,6 +105,7 @@ func runChannelBot(cmd *cobra.Command, args []string) error {
 
 	// Create tools
 	tools := tool.DefaultRegistry(workDir)
+	tools.Register(namespaceSkillTool(clusterName, namespace))
 
 	// Create memory
 	mem := memory.NewFileMemory(cfg.WorkspaceDir())
@@ -117,54 +118,67 @@ func runChannelBot(cmd *cobra.Command, args []string) error {
 	systemPrompt := memory.BuildSystemPrompt(ws)
 
 	// Create channel based on type
-	var ch channel.Channel
-	switch binding.Type {
-	case "slack":
-		botToken := binding.Config["bot_token"]
-		appToken := binding.Config["app_token"]
-		if botToken == "" || appToken == "" {
-			return fmt.Errorf("slack channel missing tokens")
-		}
-		ch, err = channel.NewSlackChannel(channel.SlackConfig{
-			BotToken: botToken,
-			AppToken: appToken,
+	ch, err := newBindingChannel(binding)
+	if err != nil {
+		return err
+	}
+	if hr, ok := ch.(channel.HealthReporter); ok {
+		hr.OnHealth(func(state, detail string) {
+			_ = store.UpdateChannelBindingHealth(clusterName, namespace, name, state, detail)
 		})
-		if err != nil {
-			return fmt.Errorf("failed to create Slack channel: %w", err)
-		}
+	}
 
-	case "telegram", "discord":
-		return fmt.Errorf("%s channel not yet implemented", binding.Type)
+	// Conversation histories, persisted per thread
+	histories, err := history.OpenFromConfig(cfg)
+	if err != nil {
+		return fmt.Errorf("failed to open conversation history: %w", err)
+	}
+	defer func() { _ = history.Close(histories) }()
 
-	default:
-		return fmt.Errorf("unknown channel type: %s", binding.Type)
+	// Hooks of the default agent profile
+	hooks, err := agentHooks(cfg, cfg.Defaults.Agent)
+	if err != nil {
+		return err
 	}
 
 	// Create agent
 	ag := agent.New(agent.Config{
-		Provider:     prov,
-		Channel:      ch,
-		Tools:        tools,
-		Memory:       mem,
-		SystemPrompt: systemPrompt,
+		Provider:      prov,
+		Channel:       withRateLimit(ch, cfg, binding.Type),
+		Tools:         tools,
+		Memory:        mem,
+		History:       histories,
+		SystemPrompt:  systemPrompt,
+		MaxConcurrent: cfg.Defaults.MaxConcurrent,
+		MaxIterations: cfg.Defaults.MaxIterations,
+		Hooks:         hooks,
+		Secrets:       secretMasker(cfg),
+		Guardrails:    namespaceGuardrails(store, clusterName, namespace),
+		Audit:         storeAudit{store: store, cluster: clusterName, namespace: namespace, source: binding.Type},
+		Transcripts:   transcriptRecorder(cfg, clusterName, namespace),
+		Context:       agent.ContextConfig{MaxContextTokens: cfg.Defaults.MaxContextTokens},
+		Cost: agent.CostConfig{
+			MaxTurnTokens:         cfg.Defaults.MaxTurnTokens,
+			MaxTurnCost:           cfg.Defaults.MaxTurnCost,
+			MaxConversationTurns:  cfg.Defaults.MaxConversationTurns,
+			MaxConversationTokens: cfg.Defaults.MaxConversationTokens,
+			MaxConversationCost:   cfg.Defaults.MaxConversationCost,
+			Handoff:               cfg.Defaults.ConversationHandoff,
+		},
 	})
 
-	// Handle signals
+	// Handle signals: let running turns finish before stopping
 	ctx, cancel := context.WithCancel(cmd.Context())
 	defer cancel()
 
-	sigCh := make(chan os.Signal, 1)
-	signal.Notify(sigCh, syscall.SIGINT, syscall.SIGTERM)
-
-	go func() {
-		<-sigCh
-		fmt.Println("\nShutting down...")
-		cancel()
-	}()
+	drainOnSignal(cancel, shutdownTimeout(cfg), nil, ag)
 
 	// Update status
 	_ = store.UpdateChannelBindingStatus(clusterName, namespace, name, "active")
-	defer func() { _ = store.UpdateChannelBindingStatus(clusterName, namespace, name, "inactive") }()
+	defer func() {
+		_ = store.UpdateChannelBindingStatus(clusterName, namespace, name, "inactive")
+		_ = store.UpdateChannelBindingHealth(clusterName, namespace, name, "stopped", "")
+	}()
 
 	// Start channel
 	if err := ch.Start(ctx); err != nil {
@@ -186,3 +200,26 @@ func runChannelBot(cmd *cobra.Command, args []string) error {
 	// Run agent
 	return ag.Run(ctx)
 }
+
+// withRateLimit applies the [channel.<name>.rate_limit] of the config to
+// ch, when one is set.
+func withRateLimit(ch channel.Channel, cfg *config.Config, name string) channel.Channel {
+	rl := cfg.Channel[name].RateLimit
+	limit := channel.RateLimit{PerUser: rl.PerUser, PerChannel: rl.PerChannel, Message: rl.Message}
+	if !limit.Enabled() {
+		return ch
+	}
+	return channel.NewRateLimited(ch, limit)
+}
+
+// rateLimitLine describes a rate limit, e.g. "10/min per user".
+func rateLimitLine(rl config.RateLimitConfig) string {
+	var parts []string
+	if rl.PerUser > 0 {
+		parts = append(parts, fmt.Sprintf("%d/min per user", rl.PerUser))
+	}
+	if rl.PerChannel > 0 {
+		parts = append(parts, fmt.Sprintf("%d/min per channel", rl.PerChannel))
+	}
+	return strings.Join(parts, ", ")
+}
@@ -0,0 +1,494 @@
+package commands
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/eachlabs/klaw/internal/channel"
+	"github.com/eachlabs/klaw/internal/cluster"
+	"github.com/eachlabs/klaw/internal/config"
+	"github.com/eachlabs/klaw/internal/locale"
+)
+
+// envSlackChannel is the name of the Slack channel klaw start runs from
+// SLACK_BOT_TOKEN and SLACK_APP_TOKEN, next to the channel bindings.
+const envSlackChannel = "env:slack"
+
+// bindingRetry is how long a channel binding that failed to start waits
+// before it is tried again, unless its config changes.
+const bindingRetry = 5 * time.Minute
+
+// newBindingChannel creates the channel of a channel binding.
+func newBindingChannel(b *cluster.ChannelBinding) (channel.Channel, error) {
+	switch b.Type {
+	case "slack":
+		botToken := b.Config["bot_token"]
+		appToken := b.Config["app_token"]
+		if botToken == "" || appToken == "" {
+			return nil, fmt.Errorf("slack channel missing tokens")
+		}
+		retention, err := bindingThreadRetention(b)
+		if err != nil {
+			return nil, err
+		}
+		ch, err := channel.NewSlackChannel(channel.SlackConfig{
+			BotToken:        botToken,
+			AppToken:        appToken,
+			ThreadRetention: retention,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to create Slack channel: %w", err)
+		}
+		return ch, nil
+
+	case "github":
+		if b.Config["secret"] == "" {
+			return nil, fmt.Errorf("github channel missing webhook secret")
+		}
+		ch, err := channel.NewGitHubChannel(channel.GitHubConfig{
+			Listen: b.Config["listen"],
+			Path:   b.Config["path"],
+			Secret: b.Config["secret"],
+			Token:  b.Config["token"],
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to create GitHub channel: %w", err)
+		}
+		return ch, nil
+
+	case "alerts":
+		if b.Config["secret"] == "" {
+			return nil, fmt.Errorf("alerts channel missing webhook secret")
+		}
+		ch, err := channel.NewAlertChannel(channel.AlertConfig{
+			Listen:       b.Config["listen"],
+			Path:         b.Config["path"],
+			Secret:       b.Config["secret"],
+			Agent:        b.Config["agent"],
+			SlackChannel: b.Config["slack_channel"],
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to create alerts channel: %w", err)
+		}
+		return ch, nil
+
+	case "jira":
+		if b.Config["secret"] == "" {
+			return nil, fmt.Errorf("jira channel missing webhook secret")
+		}
+		ch, err := channel.NewJiraChannel(channel.JiraConfig{
+			Listen: b.Config["listen"],
+			Path:   b.Config["path"],
+			Secret: b.Config["secret"],
+			URL:    b.Config["url"],
+			Email:  b.Config["email"],
+			Token:  b.Config["token"],
+			Agent:  b.Config["agent"],
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to create Jira channel: %w", err)
+		}
+		return ch, nil
+
+	case "telegram", "discord":
+		return nil, fmt.Errorf("%s channel not yet implemented", b.Type)
+
+	default:
+		return nil, fmt.Errorf("unknown channel type: %s", b.Type)
+	}
+}
+
+// bindingThreadRetention returns how long the threads of a Slack channel
+// binding stay active after their last message before they are archived:
+// its thread_retention, or the default.
+func bindingThreadRetention(b *cluster.ChannelBinding) (time.Duration, error) {
+	v := b.Config["thread_retention"]
+	if v == "" {
+		return channel.DefaultThreadRetention, nil
+	}
+	d, err := parseAge(v)
+	if err != nil {
+		return 0, fmt.Errorf("thread_retention: %w", err)
+	}
+	return d, nil
+}
+
+// bindingSpec identifies the settings of a binding's channel; a binding
+// whose spec changes is restarted.
+func bindingSpec(b *cluster.ChannelBinding) string {
+	config, _ := json.Marshal(b.Config) // map keys are sorted
+	return b.Type + "\x00" + string(config)
+}
+
+// channelSet is the channels klaw start serves, multiplexed into one. The
+// channel bindings served follow the store, see reconcile.
+type channelSet struct {
+	mux       *channel.Mux
+	cfg       *config.Config
+	store     *cluster.Store
+	cluster   string
+	namespace string
+	texts     *locale.Catalog // templates of help and errors
+	language  string          // the namespace's language
+
+	mu     sync.Mutex
+	slack  map[string]*channel.SlackChannel // by name, for Slack tools and cron jobs
+	specs  map[string]string                // bindings served, by name
+	failed map[string]failedBinding         // bindings that failed to start, by name
+
+	// Summarizer of the idle Slack threads archived, see summarizeThreads
+	summarize func(ctx context.Context, prompt string) (string, error)
+}
+
+type failedBinding struct {
+	spec string
+	at   time.Time
+}
+
+// newChannelSet returns an empty channel set.
+func newChannelSet(cfg *config.Config, store *cluster.Store, clusterName, namespace string) *channelSet {
+	return &channelSet{
+		mux:       channel.NewMux(),
+		cfg:       cfg,
+		store:     store,
+		cluster:   clusterName,
+		namespace: namespace,
+		texts:     localeCatalog(),
+		language:  store.NamespaceLocale(clusterName, namespace),
+		slack:     make(map[string]*channel.SlackChannel),
+		specs:     make(map[string]string),
+		failed:    make(map[string]failedBinding),
+	}
+}
+
+// openChannels creates a channel for each active channel binding of the
+// namespace, and a Slack channel from SLACK_BOT_TOKEN and SLACK_APP_TOKEN
+// when they are set. A binding whose channel cannot be created is skipped
+// with a warning and its error recorded in its health, like the connection
+// state of the channels that start.
+func openChannels(cfg *config.Config, store *cluster.Store, clusterName, namespace string) (*channelSet, error) {
+	cs := newChannelSet(cfg, store, clusterName, namespace)
+
+	if botToken, appToken := os.Getenv("SLACK_BOT_TOKEN"), os.Getenv("SLACK_APP_TOKEN"); botToken != "" && appToken != "" {
+		ch, err := channel.NewSlackChannel(channel.SlackConfig{BotToken: botToken, AppToken: appToken})
+		if err != nil {
+			return nil, fmt.Errorf("failed to create Slack channel: %w", err)
+		}
+		if err := cs.add(envSlackChannel, "slack", ch); err != nil {
+			return nil, err
+		}
+	}
+
+	bindings, err := store.ListChannelBindings(clusterName, namespace)
+	if err != nil {
+		return nil, err
+	}
+	active := 0
+	for _, b := range bindings {
+		if b.Status == "active" {
+			active++
+			_ = cs.startBinding(b)
+		}
+	}
+
+	// Bindings that failed to start are retried by reconcile
+	if len(cs.mux.Names()) == 0 && active == 0 {
+		return nil, fmt.Errorf("no channels to serve: set SLACK_BOT_TOKEN and SLACK_APP_TOKEN, or activate a channel binding with s in klaw dashboard")
+	}
+	return cs, nil
+}
+
+// add serves ch, rate limited by the [channel.<kind>] settings. If the Mux
+// is running, ch is started.
+func (cs *channelSet) add(name, kind string, ch channel.Channel) error {
+	if err := cs.mux.Add(name, withRateLimit(ch, cs.cfg, kind)); err != nil {
+		return err
+	}
+	if slack, ok := ch.(*channel.SlackChannel); ok {
+		slack.SetLocale(cs.texts, cs.language)
+		cs.mu.Lock()
+		slack.SetThreadArchive(cs.threadArchive(name), cs.summarize)
+		cs.slack[name] = slack
+		cs.mu.Unlock()
+	}
+	return nil
+}
+
+// summarizeThreads sets the summarizer of the idle Slack threads archived.
+func (cs *channelSet) summarizeThreads(summarize func(ctx context.Context, prompt string) (string, error)) {
+	cs.mu.Lock()
+	defer cs.mu.Unlock()
+	cs.summarize = summarize
+	for name, slack := range cs.slack {
+		slack.SetThreadArchive(cs.threadArchive(name), summarize)
+	}
+}
+
+// threadArchive returns the archive of the idle threads of the Slack
+// channel named name.
+func (cs *channelSet) threadArchive(name string) channel.ThreadArchive {
+	return storeThreadArchive{store: cs.store, cluster: cs.cluster, namespace: cs.namespace, channel: name}
+}
+
+// storeThreadArchive keeps the idle threads of a Slack channel in the
+// store.
+type storeThreadArchive struct {
+	store     *cluster.Store
+	cluster   string
+	namespace string
+	channel   string
+}
+
+func (a storeThreadArchive) ArchiveThread(threadKey string, history *channel.ThreadHistory) error {
+	t := &cluster.ArchivedThread{
+		Cluster:    a.cluster,
+		Namespace:  a.namespace,
+		Channel:    a.channel,
+		Key:        threadKey,
+		Summary:    history.Summary,
+		LastActive: history.LastActive,
+	}
+	for _, m := range history.Messages {
+		t.Messages = append(t.Messages, cluster.ArchivedMessage{Role: m.Role, Content: m.Content, User: m.User})
+	}
+	return a.store.ArchiveThread(t)
+}
+
+func (a storeThreadArchive) RestoreThread(threadKey string) (*channel.ThreadHistory, error) {
+	t, err := a.store.GetArchivedThread(a.cluster, a.namespace, a.channel, threadKey)
+	if err != nil || t == nil {
+		return nil, err
+	}
+	history := &channel.ThreadHistory{Summary: t.Summary, LastActive: t.LastActive}
+	for _, m := range t.Messages {
+		history.Messages = append(history.Messages, channel.ThreadMessage{Role: m.Role, Content: m.Content, User: m.User})
+	}
+	return history, nil
+}
+
+// startBinding creates and serves the channel of b, recording its health.
+func (cs *channelSet) startBinding(b *cluster.ChannelBinding) error {
+	spec := bindingSpec(b)
+	ch, err := newBindingChannel(b)
+	if err == nil {
+		if alerts, ok := ch.(*channel.AlertChannel); ok {
+			alerts.SetPoster(cs.slackAPI().PostMessage)
+		}
+		if hr, ok := ch.(channel.HealthReporter); ok {
+			hr.OnHealth(func(state, detail string) {
+				_ = cs.store.UpdateChannelBindingHealth(cs.cluster, cs.namespace, b.Name, state, detail)
+			})
+		}
+		err = cs.add(b.Name, b.Type, ch)
+	}
+	if err != nil {
+		fmt.Printf("Warning: channel %s: %v\n", b.Name, err)
+		_ = cs.store.UpdateChannelBindingHealth(cs.cluster, cs.namespace, b.Name, channel.HealthError, err.Error())
+		cs.mu.Lock()
+		cs.failed[b.Name] = failedBinding{spec: spec, at: time.Now()}
+		cs.mu.Unlock()
+		return err
+	}
+
+	cs.mu.Lock()
+	cs.specs[b.Name] = spec
+	delete(cs.failed, b.Name)
+	cs.mu.Unlock()
+	return nil
+}
+
+// stopBinding stops serving the channel of a binding.
+func (cs *channelSet) stopBinding(name string) {
+	cs.mu.Lock()
+	delete(cs.specs, name)
+	delete(cs.slack, name)
+	cs.mu.Unlock()
+	if err := cs.mux.Remove(name); err != nil {
+		fmt.Printf("Warning: channel %s: %v\n", name, err)
+	}
+	_ = cs.store.UpdateChannelBindingHealth(cs.cluster, cs.namespace, name, "stopped", "")
+}
+
+// reconcile makes the channels served match the namespace's channel
+// bindings: bindings made active are started, bindings deleted or made
+// inactive are stopped, and bindings whose type or config changed are
+// restarted. The channel from the environment is left alone.
+func (cs *channelSet) reconcile() {
+	bindings, err := cs.store.ListChannelBindings(cs.cluster, cs.namespace)
+	if err != nil {
+		fmt.Printf("Warning: channel bindings: %v\n", err)
+		return
+	}
+	desired := make(map[string]*cluster.ChannelBinding)
+	for _, b := range bindings {
+		if b.Status == "active" {
+			desired[b.Name] = b
+		}
+	}
+
+	cs.mu.Lock()
+	specs := make(map[string]string, len(cs.specs))
+	for name, spec := range cs.specs {
+		specs[name] = spec
+	}
+	failed := make(map[string]failedBinding, len(cs.failed))
+	for name, f := range cs.failed {
+		failed[name] = f
+	}
+	cs.mu.Unlock()
+
+	for name, spec := range specs {
+		b, ok := desired[name]
+		switch {
+		case !ok:
+			fmt.Printf("[%s] Channel %s stopped\n", time.Now().Format("15:04:05"), name)
+			cs.stopBinding(name)
+		case bindingSpec(b) != spec:
+			fmt.Printf("[%s] Channel %s changed, restarting\n", time.Now().Format("15:04:05"), name)
+			cs.stopBinding(name)
+			delete(specs, name)
+		}
+	}
+
+	names := make([]string, 0, len(desired))
+	for name := range desired {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		b := desired[name]
+		if _, running := specs[name]; running {
+			continue
+		}
+		if f, ok := failed[name]; ok && f.spec == bindingSpec(b) && time.Since(f.at) < bindingRetry {
+			continue
+		}
+		if cs.startBinding(b) == nil {
+			fmt.Printf("[%s] Channel %s started\n", time.Now().Format("15:04:05"), name)
+		}
+	}
+
+	// Forget failures of bindings no longer wanted
+	cs.mu.Lock()
+	for name := range cs.failed {
+		if _, ok := desired[name]; !ok {
+			delete(cs.failed, name)
+		}
+	}
+	cs.mu.Unlock()
+}
+
+// run reconciles the channels every interval until ctx is done.
+func (cs *channelSet) run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			cs.reconcile()
+		}
+	}
+}
+
+// slackChannel returns the Slack channel named name or, when name is
+// empty, the one from the environment or else the first binding's. It
+// returns an error when there is none.
+func (cs *channelSet) slackChannel(name string) (*channel.SlackChannel, error) {
+	cs.mu.Lock()
+	defer cs.mu.Unlock()
+	if name == "" {
+		if ch, ok := cs.slack[envSlackChannel]; ok {
+			return ch, nil
+		}
+		names := make([]string, 0, len(cs.slack))
+		for n := range cs.slack {
+			names = append(names, n)
+		}
+		if len(names) == 0 {
+			return nil, fmt.Errorf("no Slack channel is running")
+		}
+		sort.Strings(names)
+		return cs.slack[names[0]], nil
+	}
+	if ch, ok := cs.slack[name]; ok {
+		return ch, nil
+	}
+	return nil, fmt.Errorf("no Slack channel %q is running", name)
+}
+
+// slackAPI returns the Slack API of the Slack tools, which use the
+// channel slackChannel("") returns at the time of each call.
+func (cs *channelSet) slackAPI() slackAPI {
+	return slackAPI{cs}
+}
+
+// stopped records that the bindings are no longer served.
+func (cs *channelSet) stopped() {
+	cs.mu.Lock()
+	names := make([]string, 0, len(cs.specs))
+	for name := range cs.specs {
+		names = append(names, name)
+	}
+	cs.mu.Unlock()
+	for _, name := range names {
+		_ = cs.store.UpdateChannelBindingHealth(cs.cluster, cs.namespace, name, "stopped", "")
+	}
+}
+
+// slackAPI is a tool.SlackAPI that follows the Slack channels running.
+type slackAPI struct {
+	cs *channelSet
+}
+
+func (a slackAPI) PostMessage(channelID, text string) error {
+	ch, err := a.cs.slackChannel("")
+	if err != nil {
+		return err
+	}
+	return ch.PostMessage(channelID, text)
+}
+
+func (a slackAPI) PostThreadReply(channelID, threadTS, text string) error {
+	ch, err := a.cs.slackChannel("")
+	if err != nil {
+		return err
+	}
+	return ch.PostThreadReply(channelID, threadTS, text)
+}
+
+func (a slackAPI) GetRecentMessages(channelID, threadTS string, limit int) ([]channel.ChannelMessage, error) {
+	ch, err := a.cs.slackChannel("")
+	if err != nil {
+		return nil, err
+	}
+	return ch.GetRecentMessages(channelID, threadTS, limit)
+}
+
+func (a slackAPI) AddReaction(channelID, messageTS, emoji string) error {
+	ch, err := a.cs.slackChannel("")
+	if err != nil {
+		return err
+	}
+	return ch.AddReaction(channelID, messageTS, emoji)
+}
+
+// printChannels lists the channels running.
+func printChannels(cs *channelSet) {
+	fmt.Println("Channels:")
+	names := cs.mux.Names()
+	if len(names) == 0 {
+		fmt.Println("  none running yet; failed channel bindings are retried")
+	}
+	for _, name := range names {
+		ch, _ := cs.mux.Get(name)
+		fmt.Printf("  • %s (%s)\n", name, ch.Name())
+	}
+	fmt.Println("")
+}
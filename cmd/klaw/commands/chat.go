@@ -6,7 +6,7 @@ import (
 	"os"
 	"os/signal"
 	"strings"
-	"syscall"
+	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/eachlabs/klaw/internal/agent"
@@ -140,19 +140,47 @@ func runChat(cmd *cobra.Command, args []string) error {
 
 	// Create tools, applying per-agent filtering if configured
 	tools := tool.DefaultRegistry(workDir)
-	var agentMaxIterations int
+	if clusterName, namespace, err := contextManager().GetCurrent(); err == nil {
+		tools.Register(namespaceSkillTool(clusterName, namespace))
+	}
+	agentMaxIterations := cfg.Defaults.MaxIterations
 	var agentApproval []string
 	if chatAgent != "" {
 		if agentCfg, ok := cfg.Agents[chatAgent]; ok {
 			if len(agentCfg.Tools) > 0 {
 				tools = tools.Filter(agentCfg.Tools)
 			}
-			agentMaxIterations = agentCfg.MaxIterations
+			if agentCfg.MaxIterations > 0 {
+				agentMaxIterations = agentCfg.MaxIterations
+			}
 			agentApproval = agentCfg.RequireApproval
 		}
 	}
+	hooks, err := agentHooks(cfg, chatAgent)
+	if err != nil {
+		return err
+	}
+
+	// Long-term memory tools, indexed for semantic recall when a [memory]
+	// backend is configured
+	semantic, err := memory.OpenFromConfig(cfg)
+	if err != nil {
+		return fmt.Errorf("failed to open semantic memory: %w", err)
+	}
+	facts := memory.NewFactStore(cfg.WorkspaceDir())
+	facts.SetLimits(memory.LimitsFromConfig(cfg))
+	if semantic != nil {
+		defer func() { _ = semantic.Close() }()
+		facts.SetIndex(semantic)
+	}
+	for _, t := range tool.MemoryTools(facts) {
+		tools.Register(t)
+	}
+	journal, episodic := newEpisodicMemory(cfg, prov, semantic)
+	tools.Register(tool.NewMemoryHistory(episodic))
 
 	// Register delegate tool for sub-agent spawning
+	secrets := secretMasker(cfg)
 	delegateTool := tool.NewDelegateTool(
 		func(ctx context.Context, cfg tool.RunConfig) (string, error) {
 			return agent.RunOnce(ctx, agent.RunOnceConfig{
@@ -161,12 +189,23 @@ func runChat(cmd *cobra.Command, args []string) error {
 				SystemPrompt: cfg.SystemPrompt,
 				Prompt:       cfg.Prompt,
 				MaxTokens:    cfg.MaxTokens,
+				Secrets:      secrets,
 			})
 		},
 		prov,
 		tools,
 	)
 	tools.Register(delegateTool)
+	tools.Register(tool.NewAgentUpdateTool(agentBootstrapFunc(prov)))
+
+	// Register agent_dispatch for handing work to named agents
+	if clusterName, namespace, err := contextManager().RequireCurrent(); err == nil {
+		runAgent := localAgentRunner(newProviderPool(cfg, providerName, model, prov), tools, clusterName, namespace, workDir)
+		tools.Register(tool.NewAgentDispatchTool(runAgent, controllerAgentRunner(cfg)))
+		for _, t := range tool.NewBackgroundTasks(workDir, runAgent).Tools() {
+			tools.Register(t)
+		}
+	}
 
 	// Create memory
 	mem := memory.NewFileMemory(cfg.WorkspaceDir())
@@ -217,7 +256,7 @@ func runChat(cmd *cobra.Command, args []string) error {
 	defer cancel()
 
 	sigCh := make(chan os.Signal, 1)
-	signal.Notify(sigCh, syscall.SIGINT, syscall.SIGTERM)
+	signal.Notify(sigCh, stopSignals...)
 
 	go func() {
 		<-sigCh
@@ -226,6 +265,10 @@ func runChat(cmd *cobra.Command, args []string) error {
 		cancel()
 	}()
 
+	// Summarize finished days of the journal in the background
+	go episodic.Run(ctx, time.Hour, nil)
+	go memory.NewCompactor(cfg.WorkspaceDir(), facts, memory.LimitsFromConfig(cfg), agent.DailySummarizer(prov)).Run(ctx, time.Hour, nil)
+
 	// Build base agent config
 	baseCfg := agent.Config{
 		Provider:       prov,
@@ -236,9 +279,21 @@ func runChat(cmd *cobra.Command, args []string) error {
 		SystemPrompt:   systemPrompt,
 		MaxIterations:  agentMaxIterations,
 		Model:          model,
+		AgentName:      chatAgent,
+		Hooks:          hooks,
+		Secrets:        secrets,
+		Recall:         recallConfig(cfg, semantic),
+		Journal:        journal,
+		Context:        agent.ContextConfig{MaxContextTokens: cfg.Defaults.MaxContextTokens},
 		Cost: agent.CostConfig{
-			MaxSessionCost: cfg.Defaults.MaxSessionCost,
-			WarnThreshold:  0.8,
+			MaxSessionCost:        cfg.Defaults.MaxSessionCost,
+			WarnThreshold:         0.8,
+			MaxTurnTokens:         cfg.Defaults.MaxTurnTokens,
+			MaxTurnCost:           cfg.Defaults.MaxTurnCost,
+			MaxConversationTurns:  cfg.Defaults.MaxConversationTurns,
+			MaxConversationTokens: cfg.Defaults.MaxConversationTokens,
+			MaxConversationCost:   cfg.Defaults.MaxConversationCost,
+			Handoff:               cfg.Defaults.ConversationHandoff,
 		},
 	}
 	if len(agentApproval) > 0 {
@@ -247,6 +302,10 @@ func runChat(cmd *cobra.Command, args []string) error {
 			RequireApproval: agentApproval,
 		}
 	}
+	if clusterName, namespace, err := contextManager().RequireCurrent(); err == nil {
+		baseCfg.Audit = storeAudit{store: cluster.NewStore(config.StateDir()), cluster: clusterName, namespace: namespace, source: "chat"}
+		baseCfg.Transcripts = transcriptRecorder(cfg, clusterName, namespace)
+	}
 
 	// Use simple mode or TUI mode
 	if chatSimple {
@@ -255,6 +314,9 @@ func runChat(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
+	if err := tui.SetTheme(cfg.UI.Theme, cfg.UI.Colors); err != nil {
+		return err
+	}
 	tuiErr := runTUIChat(ctx, baseCfg)
 	_ = sessMgr.ForceSave()
 	return tuiErr
@@ -271,8 +333,10 @@ func runTUIChat(ctx context.Context, baseCfg agent.Config) error {
 	// Create TUI channel
 	tuiChan := channel.NewTUIChannel()
 
-	// Create agent
+	// Create agent, reporting usage to the status line
+	usage := make(chan tui.ChatUsage, 16)
 	baseCfg.Channel = tuiChan
+	baseCfg.Usage = chatUsage{usage: usage, model: baseCfg.Model}
 	ag := agent.New(baseCfg)
 
 	// Start agent in background
@@ -287,21 +351,9 @@ func runTUIChat(ctx context.Context, baseCfg agent.Config) error {
 	inputChan := tuiChan.UserInput()
 	outputChan := tuiChan.TUIOutput()
 
-	// Adapter to convert TUIMessage to tui.ChatMessage
-	chatOutput := make(chan tui.ChatMessage, 100)
-	go func() {
-		for msg := range outputChan {
-			chatOutput <- tui.ChatMessage{
-				Role:    msg.Role,
-				Content: msg.Content,
-				Tool:    msg.Tool,
-			}
-		}
-		close(chatOutput)
-	}()
-
 	// Run TUI
-	model := tui.NewChatModel(inputChan, chatOutput)
+	model := tui.NewChatModel(inputChan, tuiChatMessages(ctx, outputChan, usage))
+	model.SetModel(baseCfg.Model)
 	p := tea.NewProgram(model, tea.WithAltScreen())
 	_, err := p.Run()
 
@@ -309,6 +361,53 @@ func runTUIChat(ctx context.Context, baseCfg agent.Config) error {
 	return err
 }
 
+// chatUsage passes the usage of provider requests to the chat status line.
+type chatUsage struct {
+	usage chan<- tui.ChatUsage
+	model string // for requests made with the provider's default model
+}
+
+func (u chatUsage) RecordUsage(agentName, model string, usage provider.Usage, cost float64) {
+	if model == "" {
+		model = u.model
+		cost = agent.EstimateCost(model, usage.InputTokens, usage.OutputTokens)
+	}
+	select {
+	case u.usage <- tui.ChatUsage{Model: model, InputTokens: usage.InputTokens, OutputTokens: usage.OutputTokens, Cost: cost}:
+	default:
+		// The status line is behind; drop the update rather than block
+	}
+}
+
+// tuiChatMessages converts the output of a TUI channel and the usage of
+// provider requests into chat messages until ctx is done.
+func tuiChatMessages(ctx context.Context, output <-chan channel.TUIMessage, usage <-chan tui.ChatUsage) <-chan tui.ChatMessage {
+	messages := make(chan tui.ChatMessage, 100)
+	go func() {
+		defer close(messages)
+		for {
+			var out tui.ChatMessage
+			select {
+			case <-ctx.Done():
+				return
+			case u := <-usage:
+				out = tui.ChatMessage{Role: "usage", Usage: &u}
+			case msg, ok := <-output:
+				if !ok {
+					return
+				}
+				out = tui.ChatMessage{Role: msg.Role, Content: msg.Content, Tool: msg.Tool}
+			}
+			select {
+			case messages <- out:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return messages
+}
+
 // loadSkillsIntoPrompt loads skills from agents and adds their prompts to the system prompt
 func loadSkillsIntoPrompt(basePrompt string) string {
 	// Load skill registry
@@ -316,7 +415,7 @@ func loadSkillsIntoPrompt(basePrompt string) string {
 
 	// Try to get skills from cluster agents
 	store := cluster.NewStore(config.StateDir())
-	ctxMgr := cluster.NewContextManager(config.ConfigDir())
+	ctxMgr := contextManager()
 	clusterName, namespace, _ := ctxMgr.RequireCurrent()
 
 	// Collect skills from agents
@@ -400,6 +499,27 @@ func firstCustomProvider(cfg *config.Config) string {
 	return ""
 }
 
+// recallConfig configures semantic recall for an agent; semantic is nil
+// when no [memory] backend is set.
+func recallConfig(cfg *config.Config, semantic *memory.SemanticMemory) agent.RecallConfig {
+	return agent.RecallConfig{
+		Memory:   semantic,
+		TopK:     cfg.Memory.TopK,
+		MinScore: cfg.Memory.MinScore,
+	}
+}
+
+// newEpisodicMemory returns the journal of finished exchanges and the
+// episodic memory summarizing it per agent and day with prov.
+func newEpisodicMemory(cfg *config.Config, prov provider.Provider, semantic *memory.SemanticMemory) (*memory.Journal, *memory.EpisodicMemory) {
+	journal := memory.NewJournal(cfg.WorkspaceDir())
+	episodic := memory.NewEpisodicMemory(cfg.WorkspaceDir(), journal, agent.DailySummarizer(prov))
+	if semantic != nil {
+		episodic.SetIndex(semantic)
+	}
+	return journal, episodic
+}
+
 // envKeyForProvider returns the conventional environment variable name for a provider.
 func envKeyForProvider(name string) string {
 	switch name {
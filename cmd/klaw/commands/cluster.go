@@ -1,9 +1,9 @@
 package commands
 
 import (
-	"encoding/json"
 	"fmt"
 	"os"
+	"strings"
 	"text/tabwriter"
 
 	"github.com/eachlabs/klaw/internal/cluster"
@@ -27,6 +27,13 @@ func init() {
 	createCmd.AddCommand(createNamespaceCmd)
 	getCmd.AddCommand(getNamespacesCmd)
 	deleteCmd.AddCommand(deleteNamespaceCmd)
+
+	// klaw namespace smtp ...
+	namespaceSMTPCmd.AddCommand(namespaceSMTPSetCmd)
+	namespaceSMTPCmd.AddCommand(namespaceSMTPShowCmd)
+	namespaceSMTPCmd.AddCommand(namespaceSMTPClearCmd)
+	namespaceCmd.AddCommand(namespaceSMTPCmd)
+	rootCmd.AddCommand(namespaceCmd)
 }
 
 // --- klaw create cluster ---
@@ -55,7 +62,7 @@ func runCreateCluster(cmd *cobra.Command, args []string) error {
 	name := args[0]
 
 	store := cluster.NewStore(config.StateDir())
-	ctxMgr := cluster.NewContextManager(config.ConfigDir())
+	ctxMgr := contextManager()
 
 	c := &cluster.Cluster{
 		Name:        name,
@@ -92,7 +99,7 @@ var getClustersCmd = &cobra.Command{
 
 func runGetClusters(cmd *cobra.Command, args []string) error {
 	store := cluster.NewStore(config.StateDir())
-	ctxMgr := cluster.NewContextManager(config.ConfigDir())
+	ctxMgr := contextManager()
 
 	clusters, err := store.ListClusters()
 	if err != nil {
@@ -107,8 +114,8 @@ func runGetClusters(cmd *cobra.Command, args []string) error {
 
 	currentCluster, _, _ := ctxMgr.GetCurrent()
 
-	if jsonOut {
-		return json.NewEncoder(os.Stdout).Encode(clusters)
+	if structuredOutput() {
+		return printObject(clusters)
 	}
 
 	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
@@ -170,8 +177,8 @@ var describeClusterCmd = &cobra.Command{
 		namespaces, _ := store.ListNamespaces(name)
 		channels, _ := store.ListAllChannelBindings(name)
 
-		if jsonOut {
-			return json.NewEncoder(os.Stdout).Encode(c)
+		if structuredOutput() {
+			return printObject(c)
 		}
 
 		fmt.Printf("Name:        %s\n", c.Name)
@@ -227,7 +234,7 @@ func runCreateNamespace(cmd *cobra.Command, args []string) error {
 	name := args[0]
 
 	store := cluster.NewStore(config.StateDir())
-	ctxMgr := cluster.NewContextManager(config.ConfigDir())
+	ctxMgr := contextManager()
 
 	clusterName := nsCluster
 	if clusterName == "" {
@@ -266,7 +273,7 @@ var getNamespacesCmd = &cobra.Command{
 
 func runGetNamespaces(cmd *cobra.Command, args []string) error {
 	store := cluster.NewStore(config.StateDir())
-	ctxMgr := cluster.NewContextManager(config.ConfigDir())
+	ctxMgr := contextManager()
 
 	clusterName, currentNS, err := ctxMgr.RequireCurrent()
 	if err != nil {
@@ -283,8 +290,8 @@ func runGetNamespaces(cmd *cobra.Command, args []string) error {
 		return nil
 	}
 
-	if jsonOut {
-		return json.NewEncoder(os.Stdout).Encode(namespaces)
+	if structuredOutput() {
+		return printObject(namespaces)
 	}
 
 	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
@@ -319,7 +326,7 @@ var deleteNamespaceCmd = &cobra.Command{
 		name := args[0]
 
 		store := cluster.NewStore(config.StateDir())
-		ctxMgr := cluster.NewContextManager(config.ConfigDir())
+		ctxMgr := contextManager()
 
 		clusterName, _, err := ctxMgr.RequireCurrent()
 		if err != nil {
@@ -334,3 +341,172 @@ var deleteNamespaceCmd = &cobra.Command{
 		return nil
 	},
 }
+
+// --- klaw namespace smtp ---
+
+var namespaceCmd = &cobra.Command{
+	Use:     "namespace",
+	Aliases: []string{"ns"},
+	Short:   "Manage namespace settings",
+}
+
+var namespaceSMTPCmd = &cobra.Command{
+	Use:   "smtp",
+	Short: "Manage the namespace SMTP server used by email_send",
+	Long: `Configure the outgoing mail server shared by all agents in a namespace.
+Agents can override individual values with 'klaw agent config set <agent> email.<key>=...'.
+
+Examples:
+  klaw namespace smtp set --host smtp.example.com --username bot --password ... --from "Klaw <bot@example.com>"
+  klaw namespace smtp set --allow-recipient @example.com --allow-recipient ops@partner.io
+  klaw namespace smtp show
+  klaw namespace smtp clear`,
+}
+
+var (
+	smtpNamespace  string
+	smtpHost       string
+	smtpPort       int
+	smtpUsername   string
+	smtpPassword   string
+	smtpFrom       string
+	smtpRecipients []string
+)
+
+// smtpTarget resolves the cluster and namespace for the smtp commands.
+func smtpTarget() (string, string, error) {
+	ctxMgr := contextManager()
+	clusterName, namespace, err := ctxMgr.RequireCurrent()
+	if err != nil {
+		return "", "", err
+	}
+	if smtpNamespace != "" {
+		namespace = smtpNamespace
+	}
+	return clusterName, namespace, nil
+}
+
+var namespaceSMTPSetCmd = &cobra.Command{
+	Use:   "set",
+	Short: "Set SMTP settings for a namespace",
+	Long:  `Set SMTP settings for a namespace. Only the flags given are changed.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		store := cluster.NewStore(config.StateDir())
+		clusterName, namespace, err := smtpTarget()
+		if err != nil {
+			return err
+		}
+
+		smtp, err := store.NamespaceSMTP(clusterName, namespace)
+		if err != nil {
+			return err
+		}
+		if smtp == nil {
+			smtp = &cluster.SMTPConfig{}
+		}
+
+		flags := cmd.Flags()
+		if flags.Changed("host") {
+			smtp.Host = smtpHost
+		}
+		if flags.Changed("port") {
+			smtp.Port = smtpPort
+		}
+		if flags.Changed("username") {
+			smtp.Username = smtpUsername
+		}
+		if flags.Changed("password") {
+			smtp.Password = smtpPassword
+		}
+		if flags.Changed("from") {
+			smtp.From = smtpFrom
+		}
+		if flags.Changed("allow-recipient") {
+			smtp.AllowedRecipients = smtpRecipients
+		}
+
+		if err := store.SetNamespaceSMTP(clusterName, namespace, smtp); err != nil {
+			return err
+		}
+
+		fmt.Printf("SMTP settings updated for namespace '%s'.\n", namespace)
+		if smtp.Host == "" || smtp.From == "" {
+			fmt.Println("Note: --host and --from are required before email_send can be used.")
+		}
+		return nil
+	},
+}
+
+var namespaceSMTPShowCmd = &cobra.Command{
+	Use:   "show",
+	Short: "Show SMTP settings for a namespace",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		store := cluster.NewStore(config.StateDir())
+		clusterName, namespace, err := smtpTarget()
+		if err != nil {
+			return err
+		}
+
+		smtp, err := store.NamespaceSMTP(clusterName, namespace)
+		if err != nil {
+			return err
+		}
+		if smtp != nil && smtp.Password != "" {
+			smtp.Password = maskToken(smtp.Password)
+		}
+
+		if structuredOutput() {
+			return printObject(smtp)
+		}
+
+		if smtp == nil {
+			fmt.Printf("No SMTP settings for namespace '%s'.\n", namespace)
+			return nil
+		}
+
+		port := smtp.Port
+		if port == 0 {
+			port = 587
+		}
+		fmt.Printf("Host:        %s:%d\n", smtp.Host, port)
+		fmt.Printf("From:        %s\n", smtp.From)
+		if smtp.Username != "" {
+			fmt.Printf("Username:    %s\n", smtp.Username)
+			fmt.Printf("Password:    %s\n", smtp.Password)
+		}
+		if len(smtp.AllowedRecipients) > 0 {
+			fmt.Printf("Recipients:  %s\n", strings.Join(smtp.AllowedRecipients, ", "))
+		} else {
+			fmt.Printf("Recipients:  (any)\n")
+		}
+		return nil
+	},
+}
+
+var namespaceSMTPClearCmd = &cobra.Command{
+	Use:   "clear",
+	Short: "Remove SMTP settings from a namespace",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		store := cluster.NewStore(config.StateDir())
+		clusterName, namespace, err := smtpTarget()
+		if err != nil {
+			return err
+		}
+
+		if err := store.SetNamespaceSMTP(clusterName, namespace, nil); err != nil {
+			return err
+		}
+		fmt.Printf("SMTP settings removed from namespace '%s'.\n", namespace)
+		return nil
+	},
+}
+
+func init() {
+	namespaceSMTPCmd.PersistentFlags().StringVarP(&smtpNamespace, "namespace", "n", "", "namespace (uses current if not set)")
+	namespaceSMTPSetCmd.Flags().StringVar(&smtpHost, "host", "", "SMTP server host")
+	namespaceSMTPSetCmd.Flags().IntVar(&smtpPort, "port", 587, "SMTP server port (465 = implicit TLS)")
+	namespaceSMTPSetCmd.Flags().StringVar(&smtpUsername, "username", "", "SMTP username")
+	namespaceSMTPSetCmd.Flags().StringVar(&smtpPassword, "password", "", "SMTP password (stored encrypted)")
+	namespaceSMTPSetCmd.Flags().StringVar(&smtpFrom, "from", "", "Sender address")
+	namespaceSMTPSetCmd.Flags().StringArrayVar(&smtpRecipients, "allow-recipient", nil, "Allowed recipient address or @domain (repeatable)")
+}
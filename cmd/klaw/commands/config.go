@@ -1,7 +1,6 @@
 package commands
 
 import (
-	"encoding/json"
 	"fmt"
 	"os"
 	"os/exec"
@@ -23,6 +22,7 @@ Subcommands:
   set <key> <value>      Set a configuration value
   edit                   Open config in $EDITOR
   path                   Show config file path
+  validate [file]        Check the config file for mistakes
   use-cluster <name>     Switch to a cluster
   use-namespace <name>   Switch to a namespace
   current-context        Show current cluster/namespace`,
@@ -33,6 +33,7 @@ func init() {
 	configCmd.AddCommand(configSetCmd)
 	configCmd.AddCommand(configEditCmd)
 	configCmd.AddCommand(configPathCmd)
+	configCmd.AddCommand(configValidateCmd)
 	configCmd.AddCommand(useClusterCmd)
 	configCmd.AddCommand(useNamespaceCmd)
 	configCmd.AddCommand(currentContextCmd)
@@ -55,10 +56,8 @@ Examples:
 
 		if len(args) == 0 {
 			// Show all config
-			if jsonOut {
-				enc := json.NewEncoder(os.Stdout)
-				enc.SetIndent("", "  ")
-				return enc.Encode(cfg)
+			if structuredOutput() {
+				return printObject(cfg)
 			}
 
 			enc := toml.NewEncoder(os.Stdout)
@@ -72,9 +71,8 @@ Examples:
 			return fmt.Errorf("key not found: %s", key)
 		}
 
-		if jsonOut {
-			enc := json.NewEncoder(os.Stdout)
-			return enc.Encode(value)
+		if structuredOutput() {
+			return printObject(value)
 		}
 
 		fmt.Printf("%v\n", value)
@@ -169,6 +167,22 @@ func getConfigValue(cfg *config.Config, key string) interface{} {
 		case "file":
 			return cfg.Logging.File
 		}
+
+	case "ui":
+		if len(parts) == 1 {
+			return cfg.UI
+		}
+		switch parts[1] {
+		case "theme":
+			return cfg.UI.Theme
+		case "colors":
+			if len(parts) == 2 {
+				return cfg.UI.Colors
+			}
+			if c, ok := cfg.UI.Colors[parts[2]]; ok {
+				return c
+			}
+		}
 	}
 
 	return nil
@@ -182,7 +196,9 @@ var configSetCmd = &cobra.Command{
 Examples:
   klaw config set defaults.model claude-opus-4-20250514
   klaw config set provider.anthropic.api_key sk-ant-...
-  klaw config set server.port 9090`,
+  klaw config set server.port 9090
+  klaw config set ui.theme light
+  klaw config set ui.colors.accent "#5B21B6"`,
 	Args: cobra.ExactArgs(2),
 	RunE: func(cmd *cobra.Command, args []string) error {
 		key := args[0]
@@ -291,6 +307,22 @@ func setConfigValue(cfg *config.Config, key, value string) error {
 			return fmt.Errorf("unknown field: %s", parts[1])
 		}
 
+	case "ui":
+		switch {
+		case len(parts) == 2 && parts[1] == "theme":
+			if value != "auto" && value != "dark" && value != "light" {
+				return fmt.Errorf("invalid theme: %s (use auto, dark or light)", value)
+			}
+			cfg.UI.Theme = value
+		case len(parts) == 3 && parts[1] == "colors":
+			if cfg.UI.Colors == nil {
+				cfg.UI.Colors = make(map[string]string)
+			}
+			cfg.UI.Colors[parts[2]] = value
+		default:
+			return fmt.Errorf("invalid key: %s (use ui.theme or ui.colors.<name>)", key)
+		}
+
 	default:
 		return fmt.Errorf("unknown section: %s", parts[0])
 	}
@@ -309,13 +341,15 @@ var configEditCmd = &cobra.Command{
 
 		configPath := config.ConfigPath()
 
-		// Ensure config exists
-		cfg, err := config.Load()
-		if err != nil {
-			return err
-		}
-		if err := cfg.Save(); err != nil {
-			return err
+		// Ensure config exists; an invalid one is opened as is, to be fixed
+		if _, err := os.Stat(configPath); os.IsNotExist(err) {
+			cfg, err := config.Load()
+			if err != nil {
+				return err
+			}
+			if err := cfg.Save(); err != nil {
+				return err
+			}
 		}
 
 		c := exec.Command(editor, configPath)
@@ -323,10 +357,67 @@ var configEditCmd = &cobra.Command{
 		c.Stdout = os.Stdout
 		c.Stderr = os.Stderr
 
-		return c.Run()
+		if err := c.Run(); err != nil {
+			return err
+		}
+		return reportConfigIssues(configPath)
 	},
 }
 
+var configValidateCmd = &cobra.Command{
+	Use:   "validate [file]",
+	Short: "Check the config file for mistakes",
+	Long: `Check a config file without starting anything: unknown keys (with
+suggestions for typos), invalid values and models, and references to
+providers that are not configured. Defaults to the current config file.
+
+Examples:
+  klaw config validate
+  klaw config validate ./staging.toml
+  klaw config validate -o json`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		path := config.ConfigPath()
+		if len(args) > 0 {
+			path = args[0]
+		}
+		cmd.SilenceUsage = true
+		return reportConfigIssues(path)
+	},
+}
+
+// reportConfigIssues prints the problems of a config file, and fails if
+// any of them is an error.
+func reportConfigIssues(path string) error {
+	issues, err := config.ValidateFile(path)
+	if err != nil {
+		return err
+	}
+	errs := 0
+	for _, issue := range issues {
+		if !issue.Warning {
+			errs++
+		}
+	}
+
+	if structuredOutput() {
+		if err := printObject(issues); err != nil {
+			return err
+		}
+	} else {
+		for _, issue := range issues {
+			fmt.Printf("%s: %s\n", path, issue)
+		}
+		if len(issues) == 0 {
+			fmt.Printf("%s is valid\n", path)
+		}
+	}
+	if errs > 0 {
+		return fmt.Errorf("%s has %d error(s)", path, errs)
+	}
+	return nil
+}
+
 var configPathCmd = &cobra.Command{
 	Use:   "path",
 	Short: "Show config file path",
@@ -349,7 +440,7 @@ Examples:
 		name := args[0]
 
 		store := cluster.NewStore(config.StateDir())
-		ctxMgr := cluster.NewContextManager(config.ConfigDir())
+		ctxMgr := contextManager()
 
 		// Verify cluster exists
 		if !store.ClusterExists(name) {
@@ -379,6 +470,7 @@ Examples:
 		name := args[0]
 
 		store := cluster.NewStore(config.StateDir())
+		// The saved context, not --cluster: this changes what is saved
 		ctxMgr := cluster.NewContextManager(config.ConfigDir())
 
 		// Get current cluster
@@ -406,7 +498,7 @@ var currentContextCmd = &cobra.Command{
 	Aliases: []string{"ctx"},
 	Short:   "Show current cluster/namespace",
 	RunE: func(cmd *cobra.Command, args []string) error {
-		ctxMgr := cluster.NewContextManager(config.ConfigDir())
+		ctxMgr := contextManager()
 
 		clusterName, namespace, err := ctxMgr.GetCurrent()
 		if err != nil {
@@ -419,8 +511,8 @@ var currentContextCmd = &cobra.Command{
 			return nil
 		}
 
-		if jsonOut {
-			return json.NewEncoder(os.Stdout).Encode(map[string]string{
+		if structuredOutput() {
+			return printObject(map[string]string{
 				"cluster":   clusterName,
 				"namespace": namespace,
 			})
@@ -2,16 +2,17 @@ package commands
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
 	"os"
 	"os/signal"
-	"syscall"
-	"text/tabwriter"
+	"sort"
+	"strings"
 	"time"
 
 	"github.com/eachlabs/klaw/internal/config"
 	"github.com/eachlabs/klaw/internal/controller"
+	"github.com/eachlabs/klaw/internal/lease"
+	"github.com/eachlabs/klaw/internal/notify"
 	"github.com/spf13/cobra"
 )
 
@@ -21,6 +22,8 @@ var (
 	controllerStoreType string
 	controllerEtcdAddrs []string
 	controllerUseGRPC   bool
+	controllerBalancing string
+	controllerScheduler bool
 )
 
 var controllerCmd = &cobra.Command{
@@ -47,7 +50,21 @@ var controllerStartCmd = &cobra.Command{
 	Short: "Start the controller",
 	Long: `Start the klaw controller server.
 
-The controller listens for node connections and manages the cluster state.`,
+The controller listens for node connections and manages the cluster state.
+Finished tasks and nodes going down are posted to the [[webhooks]] of
+config.toml.
+
+An agent registered on several nodes gets its tasks spread over them by
+--load-balancing: least-loaded sends each task to the node running the
+fewest tasks, round-robin to each node in turn. Nodes that disconnect or
+miss heartbeats for 60s get no tasks until they are back.
+
+The controller also runs the cluster-wide cron jobs created with
+'klaw cron create --controller', dispatching each run as a task to a node
+with the job's agent, so jobs keep running when no 'klaw start' does. Of
+several controllers sharing the [lease] store of config.toml (by default
+a lease file in the controller data directory) only one runs them, and
+another takes over within two minutes when it stops.`,
 	RunE: runControllerStart,
 }
 
@@ -63,6 +80,8 @@ func init() {
 	controllerStartCmd.Flags().StringVar(&controllerStoreType, "store", "file", "Storage backend (file, etcd)")
 	controllerStartCmd.Flags().StringSliceVar(&controllerEtcdAddrs, "etcd-endpoints", nil, "etcd endpoints (comma-separated)")
 	controllerStartCmd.Flags().BoolVar(&controllerUseGRPC, "grpc", true, "Use gRPC protocol (default: true)")
+	controllerStartCmd.Flags().BoolVar(&controllerScheduler, "scheduler", true, "Run the cluster-wide cron jobs")
+	controllerStartCmd.Flags().StringVar(&controllerBalancing, "load-balancing", controller.LeastLoaded, "How tasks of agents on several nodes are spread: least-loaded or round-robin")
 
 	controllerCmd.AddCommand(controllerStartCmd)
 	controllerCmd.AddCommand(controllerStatusCmd)
@@ -71,6 +90,26 @@ func init() {
 
 func runControllerStart(cmd *cobra.Command, args []string) error {
 	dataDir := config.StateDir() + "/controller"
+	if err := controller.ValidateLoadBalancing(controllerBalancing); err != nil {
+		return err
+	}
+
+	// Webhooks of [[webhooks]] in config.toml, and the lease store electing
+	// the controller running the cron jobs
+	var notifier *notify.Notifier
+	var leases lease.Store
+	if klawCfg, err := config.Load(); err == nil {
+		if notifier, err = notify.FromConfig(klawCfg); err != nil {
+			return err
+		}
+		if leases, err = openLeases(klawCfg); err != nil {
+			return err
+		}
+	}
+	defer notifier.Wait()
+	if leases != nil {
+		defer func() { _ = leases.Close() }()
+	}
 
 	cfg := controller.ServerConfig{
 		Port:      controllerPort,
@@ -78,11 +117,17 @@ func runControllerStart(cmd *cobra.Command, args []string) error {
 		AuthToken: controllerToken,
 		StoreType: controllerStoreType,
 		EtcdAddrs: controllerEtcdAddrs,
+		Notifier:  notifier,
+
+		LoadBalancing: controllerBalancing,
+		Scheduler:     controllerScheduler,
+		Leases:        leases,
+		InstanceID:    instanceID(),
 	}
 
 	// Handle signals
 	sigCh := make(chan os.Signal, 1)
-	signal.Notify(sigCh, syscall.SIGINT, syscall.SIGTERM)
+	signal.Notify(sigCh, stopSignals...)
 
 	fmt.Println("╭─────────────────────────────────────────╮")
 	fmt.Println("│          klaw controller                │")
@@ -90,6 +135,10 @@ func runControllerStart(cmd *cobra.Command, args []string) error {
 	fmt.Printf("Port:     %d\n", controllerPort)
 	fmt.Printf("Protocol: %s\n", map[bool]string{true: "gRPC", false: "TCP/JSON"}[controllerUseGRPC])
 	fmt.Printf("Store:    %s\n", controllerStoreType)
+	fmt.Printf("Routing:  %s\n", controllerBalancing)
+	if controllerScheduler {
+		fmt.Printf("Cron:     %s\n", controller.SchedulerDir(dataDir))
+	}
 	if controllerToken != "" {
 		fmt.Println("Auth:     enabled (token required)")
 	} else {
@@ -161,6 +210,10 @@ func runGetNodes(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
+	if structuredOutput() {
+		return printObject(nodes)
+	}
+
 	if len(nodes) == 0 {
 		fmt.Println("No nodes connected.")
 		fmt.Println()
@@ -170,16 +223,9 @@ func runGetNodes(cmd *cobra.Command, args []string) error {
 		return nil
 	}
 
-	if jsonOut {
-		return json.NewEncoder(os.Stdout).Encode(nodes)
-	}
-
 	fmt.Printf("Nodes (%d):\n\n", len(nodes))
 
-	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
-	_, _ = fmt.Fprintln(w, "ID\tNAME\tSTATUS\tAGENTS\tLAST SEEN")
-	_, _ = fmt.Fprintln(w, "--\t----\t------\t------\t---------")
-
+	t := newTable("ID", "NAME", "STATUS", "AGENTS", "LAST SEEN").withWide("ADDRESS", "VERSION", "LABELS", "JOINED")
 	for _, node := range nodes {
 		status := node.Status
 		switch status {
@@ -196,12 +242,16 @@ func runGetNodes(cmd *cobra.Command, args []string) error {
 			lastSeen = node.LastSeen.Format("Jan 02 15:04")
 		}
 
-		_, _ = fmt.Fprintf(w, "%s\t%s\t%s\t%d\t%s\n",
-			node.ID, node.Name, status, len(node.AgentIDs), lastSeen)
-	}
-	_ = w.Flush()
+		var labels []string
+		for k, v := range node.Labels {
+			labels = append(labels, k+"="+v)
+		}
+		sort.Strings(labels)
 
-	return nil
+		t.add(node.ID, node.Name, status, fmt.Sprint(len(node.AgentIDs)), lastSeen,
+			node.Address, node.Version, strings.Join(labels, ","), node.JoinedAt.Format("2006-01-02 15:04"))
+	}
+	return t.print()
 }
 
 // --- Describe node ---
@@ -233,8 +283,8 @@ func runDescribeNode(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
-	if jsonOut {
-		return json.NewEncoder(os.Stdout).Encode(node)
+	if structuredOutput() {
+		return printObject(node)
 	}
 
 	fmt.Printf("ID:        %s\n", node.ID)
@@ -66,6 +66,18 @@ var channelName string
 var slackBotToken string
 var slackAppToken string
 
+var webhookSecret string
+var webhookListen string
+var webhookPath string
+
+var alertAgent string
+var alertSlackChannel string
+
+var jiraURL string
+var jiraEmail string
+
+var threadRetention string
+
 var createChannelCmd = &cobra.Command{
 	Use:     "channel <type>",
 	Aliases: []string{"ch"},
@@ -76,19 +88,47 @@ Channel types:
   slack      Slack (Socket Mode)
   telegram   Telegram bot
   discord    Discord bot
+  github     GitHub webhooks (issues, PR comments, review requests)
+  alerts     PagerDuty and Opsgenie alert webhooks
+  jira       Jira webhooks (new issues)
+
+A github channel serves a webhook at --listen and --path; point a GitHub
+webhook there with the same --secret. Opened issues, issue and PR
+comments, and review requests become agent messages, and replies are
+posted as comments when --token is set.
+
+An alerts channel serves <path>/pagerduty and <path>/opsgenie. PagerDuty
+webhooks are signed with --secret; Opsgenie webhooks must send it in an
+X-Klaw-Secret header. Each new incident is dispatched at high priority to
+the --agent, and its investigation summary is posted to --slack-channel, or
+to the channel an Opsgenie alert names in its slack_channel detail.
+
+A jira channel serves a webhook at --listen and --path for Jira's "issue
+created" event. Configure the Jira webhook with the same --secret, or add
+?secret=<secret> to its URL. Each new issue goes to the --agent for
+triage, and replies are posted as comments when --jira-url and --token
+(with --jira-email for Jira Cloud API tokens) are set.
+
+A slack channel keeps the conversation of each thread active for
+--thread-retention after its last message, then archives it with a
+summary; a reply to an archived thread restores it.
 
 The channel is bound to the current cluster/namespace context.
 
 Examples:
   klaw create channel slack --name sales-bot --bot-token xoxb-... --app-token xapp-...
+  klaw create channel slack --name support-bot --bot-token xoxb-... --app-token xapp-... --thread-retention 24h
   klaw create channel telegram --name support-bot --token <bot_token>
-  klaw create channel discord --name community-bot --token <bot_token>`,
+  klaw create channel discord --name community-bot --token <bot_token>
+  klaw create channel github --name triage --secret <webhook_secret> --token ghp_... --listen :8090
+  klaw create channel alerts --name oncall --secret <signing_secret> --agent sre --slack-channel C0123456789
+  klaw create channel jira --name triage --secret <secret> --agent triage --jira-url https://acme.atlassian.net --jira-email bot@acme.io --token <api_token>`,
 	Args: cobra.ExactArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
 		channelType := args[0]
 
 		store := cluster.NewStore(config.StateDir())
-		ctxMgr := cluster.NewContextManager(config.ConfigDir())
+		ctxMgr := contextManager()
 
 		// Get current context
 		clusterName, namespace, err := ctxMgr.RequireCurrent()
@@ -112,6 +152,12 @@ Examples:
 			}
 			channelConfig["bot_token"] = slackBotToken
 			channelConfig["app_token"] = slackAppToken
+			if threadRetention != "" {
+				if _, err := parseAge(threadRetention); err != nil {
+					return fmt.Errorf("--thread-retention: %w", err)
+				}
+				channelConfig["thread_retention"] = threadRetention
+			}
 
 		case "telegram", "discord":
 			if channelToken == "" {
@@ -119,8 +165,46 @@ Examples:
 			}
 			channelConfig["token"] = channelToken
 
+		case "github":
+			if webhookSecret == "" {
+				return fmt.Errorf("github requires --secret flag")
+			}
+			channelConfig["secret"] = webhookSecret
+			channelConfig["listen"] = webhookListen
+			channelConfig["path"] = webhookPath
+			if channelToken != "" {
+				channelConfig["token"] = channelToken
+			}
+
+		case "alerts":
+			if webhookSecret == "" {
+				return fmt.Errorf("alerts requires --secret flag")
+			}
+			channelConfig["secret"] = webhookSecret
+			channelConfig["listen"] = webhookListen
+			channelConfig["path"] = webhookPath
+			channelConfig["agent"] = alertAgent
+			channelConfig["slack_channel"] = alertSlackChannel
+
+		case "jira":
+			if webhookSecret == "" {
+				return fmt.Errorf("jira requires --secret flag")
+			}
+			if channelToken != "" && jiraURL == "" {
+				return fmt.Errorf("jira requires --jira-url flag to post replies with --token")
+			}
+			channelConfig["secret"] = webhookSecret
+			channelConfig["listen"] = webhookListen
+			channelConfig["path"] = webhookPath
+			channelConfig["agent"] = alertAgent
+			channelConfig["url"] = jiraURL
+			channelConfig["email"] = jiraEmail
+			if channelToken != "" {
+				channelConfig["token"] = channelToken
+			}
+
 		default:
-			return fmt.Errorf("unknown channel type: %s (use: slack, telegram, discord)", channelType)
+			return fmt.Errorf("unknown channel type: %s (use: slack, telegram, discord, github, alerts, jira)", channelType)
 		}
 
 		// Create channel binding
@@ -146,9 +230,17 @@ Examples:
 
 func init() {
 	createChannelCmd.Flags().StringVar(&channelName, "name", "", "channel name (default: <type>-bot)")
-	createChannelCmd.Flags().StringVar(&channelToken, "token", "", "bot token (telegram/discord)")
+	createChannelCmd.Flags().StringVar(&channelToken, "token", "", "bot token (telegram/discord), or GitHub/Jira token replies are posted with")
 	createChannelCmd.Flags().StringVar(&slackBotToken, "bot-token", "", "Slack bot token (xoxb-...)")
 	createChannelCmd.Flags().StringVar(&slackAppToken, "app-token", "", "Slack app token (xapp-...)")
+	createChannelCmd.Flags().StringVar(&webhookSecret, "secret", "", "webhook secret (github/alerts/jira)")
+	createChannelCmd.Flags().StringVar(&webhookListen, "listen", "", "address the webhook server listens on (default :8090 for github, :8091 for alerts, :8092 for jira)")
+	createChannelCmd.Flags().StringVar(&webhookPath, "path", "", "URL path of the webhook (default /github, /alerts or /jira)")
+	createChannelCmd.Flags().StringVar(&alertAgent, "agent", "", "agent that handles alerts or Jira issues (default: the main agent)")
+	createChannelCmd.Flags().StringVar(&alertSlackChannel, "slack-channel", "", "Slack channel ID alert summaries are posted to")
+	createChannelCmd.Flags().StringVar(&jiraURL, "jira-url", "", "Jira site URL replies are posted to")
+	createChannelCmd.Flags().StringVar(&jiraEmail, "jira-email", "", "Jira account email for Cloud API tokens")
+	createChannelCmd.Flags().StringVar(&threadRetention, "thread-retention", "", "how long a Slack thread stays active after its last message before it is archived, e.g. 24h or 7d (default 1h)")
 }
 
 var createSessionCmd = &cobra.Command{
@@ -1,15 +1,15 @@
 package commands
 
 import (
-	"encoding/json"
 	"fmt"
-	"os"
+	"strconv"
 	"strings"
-	"text/tabwriter"
 	"time"
 
 	"github.com/eachlabs/klaw/internal/cluster"
 	"github.com/eachlabs/klaw/internal/config"
+	"github.com/eachlabs/klaw/internal/controller"
+	"github.com/eachlabs/klaw/internal/priority"
 	"github.com/eachlabs/klaw/internal/scheduler"
 	"github.com/spf13/cobra"
 )
@@ -19,6 +19,11 @@ var (
 	cronAgent    string
 	cronTask     string
 	cronChannel  string
+	cronDryRun   bool
+	cronPriority string
+	cronCentral  bool
+	cronBatch    int
+	cronSkip     []string
 )
 
 var cronCmd = &cobra.Command{
@@ -38,7 +43,14 @@ Examples:
   klaw cron create morning-report --schedule "every day at 9am" --agent reporter --task "Generate daily report"
   klaw cron list
   klaw cron run <job-id>
-  klaw cron delete <job-id>`,
+  klaw cron delete <job-id>
+
+Jobs run while 'klaw start' runs the namespace. With --controller the
+commands manage the cluster-wide jobs of the controller on this host
+instead, which it runs as tasks on the nodes with the job's agent:
+
+  klaw cron create nightly --controller --schedule "every day at 2am" --agent ops --task "Rotate logs"
+  klaw cron list --controller`,
 }
 
 var cronCreateCmd = &cobra.Command{
@@ -49,7 +61,8 @@ var cronCreateCmd = &cobra.Command{
 Examples:
   klaw cron create daily-standup --schedule "every day at 9am" --agent standup-bot --task "Post standup reminder"
   klaw cron create weekly-report --schedule "every monday at 10am" --agent reporter --task "Generate weekly metrics"
-  klaw cron create health-check --schedule "every 5 minutes" --agent monitor --task "Check system health"`,
+  klaw cron create health-check --schedule "every 5 minutes" --agent monitor --task "Check system health"
+  klaw cron create nightly --schedule "every day at 2am" --agent ops --task "Rotate logs" --dry-run`,
 	Args: cobra.ExactArgs(1),
 	RunE: runCronCreate,
 }
@@ -60,6 +73,13 @@ var cronListCmd = &cobra.Command{
 	RunE:  runCronList,
 }
 
+var getJobsCmd = &cobra.Command{
+	Use:     "jobs",
+	Aliases: []string{"job", "cronjobs"},
+	Short:   "List scheduled jobs (same as 'klaw cron list')",
+	RunE:    runCronList,
+}
+
 var cronDeleteCmd = &cobra.Command{
 	Use:   "delete <job-id>",
 	Short: "Delete a scheduled job",
@@ -103,10 +123,15 @@ var cronSetChannelCmd = &cobra.Command{
 }
 
 func init() {
+	cronCmd.PersistentFlags().BoolVar(&cronCentral, "controller", false, "Manage the cluster-wide jobs run by the controller")
 	cronCreateCmd.Flags().StringVarP(&cronSchedule, "schedule", "s", "", "Schedule in plain English (required)")
 	cronCreateCmd.Flags().StringVarP(&cronAgent, "agent", "a", "", "Agent to run the task (required)")
 	cronCreateCmd.Flags().StringVarP(&cronTask, "task", "t", "", "Task/prompt for the agent (required)")
 	cronCreateCmd.Flags().StringVarP(&cronChannel, "channel", "c", "", "Slack channel ID to read messages from (optional)")
+	cronCreateCmd.Flags().IntVar(&cronBatch, "batch", 0, "Analyze up to this many channel messages in one call (0: one call per message)")
+	cronCreateCmd.Flags().StringSliceVar(&cronSkip, "skip-users", nil, "Slack user IDs whose channel messages are never analyzed (comma-separated)")
+	cronCreateCmd.Flags().StringVar(&cronPriority, "priority", "normal", "Priority of the runs against queued messages: low, normal, high or a number")
+	cronCreateCmd.Flags().BoolVar(&cronDryRun, "dry-run", false, "Validate and show the job without creating it")
 	_ = cronCreateCmd.MarkFlagRequired("schedule")
 	_ = cronCreateCmd.MarkFlagRequired("agent")
 	_ = cronCreateCmd.MarkFlagRequired("task")
@@ -120,10 +145,15 @@ func init() {
 	cronCmd.AddCommand(cronDescribeCmd)
 	cronCmd.AddCommand(cronSetChannelCmd)
 	rootCmd.AddCommand(cronCmd)
+	getCmd.AddCommand(getJobsCmd)
 }
 
 func getScheduler() *scheduler.Scheduler {
-	s := scheduler.NewScheduler(config.StateDir() + "/scheduler")
+	dir := config.StateDir() + "/scheduler"
+	if cronCentral {
+		dir = controller.SchedulerDir(config.StateDir() + "/controller")
+	}
+	s := scheduler.NewScheduler(dir)
 	_ = s.Load()
 	return s
 }
@@ -131,15 +161,15 @@ func getScheduler() *scheduler.Scheduler {
 func runCronCreate(cmd *cobra.Command, args []string) error {
 	name := args[0]
 
-	ctxMgr := cluster.NewContextManager(config.ConfigDir())
+	ctxMgr := contextManager()
 	clusterName, namespace, err := ctxMgr.RequireCurrent()
 	if err != nil {
 		return err
 	}
 
-	// Validate agent exists
+	// Validate agent exists; controller jobs run on the nodes' agents
 	store := cluster.NewStore(config.StateDir())
-	if !store.AgentBindingExists(clusterName, namespace, cronAgent) {
+	if !cronCentral && !store.AgentBindingExists(clusterName, namespace, cronAgent) {
 		return fmt.Errorf("agent not found: %s\nCreate it with: klaw create agent %s --description \"...\"", cronAgent, cronAgent)
 	}
 
@@ -150,18 +180,42 @@ func runCronCreate(cmd *cobra.Command, args []string) error {
 	if err != nil {
 		return err
 	}
+	level, err := priority.Parse(cronPriority)
+	if err != nil {
+		return err
+	}
+	if cronBatch < 0 {
+		return fmt.Errorf("--batch must not be negative")
+	}
+	if (cronBatch > 0 || len(cronSkip) > 0) && cronChannel == "" {
+		return fmt.Errorf("--batch and --skip-users require --channel")
+	}
+
+	if cronDryRun {
+		job, err := scheduler.NewJob(name, cronSchedule, cronAgent, cronTask, clusterName, namespace)
+		if err != nil {
+			return err
+		}
+		// The ID is assigned when the job is created
+		job.ID = ""
+		job.Priority = level
+		if cronChannel != "" {
+			job.Config = jobChannelConfig()
+		}
+		return printDryRun(fmt.Sprintf("Job '%s' would be scheduled in %s/%s", name, clusterName, namespace), job)
+	}
 
 	job, err := sched.CreateJob(name, cronSchedule, cronAgent, cronTask, clusterName, namespace)
 	if err != nil {
 		return err
 	}
 
-	// Set channel config if provided
-	if cronChannel != "" {
-		if job.Config == nil {
-			job.Config = make(map[string]string)
+	// Set channel config and priority if provided
+	if cronChannel != "" || level != priority.Normal {
+		if cronChannel != "" {
+			job.Config = jobChannelConfig()
 		}
-		job.Config["channel"] = cronChannel
+		job.Priority = level
 		_ = sched.Save()
 	}
 
@@ -173,18 +227,25 @@ func runCronCreate(cmd *cobra.Command, args []string) error {
 	fmt.Printf("  Cron:     %s\n", cron)
 	fmt.Printf("  Agent:    %s\n", job.Agent)
 	fmt.Printf("  Task:     %s\n", truncateStr(job.Task, 50))
+	if job.Priority != priority.Normal {
+		fmt.Printf("  Priority: %s\n", job.Priority)
+	}
 	if job.NextRun != nil {
 		fmt.Printf("  Next Run: %s\n", job.NextRun.Format(time.RFC3339))
 	}
 	fmt.Println()
-	fmt.Println("The job will run automatically when 'klaw start namespace' is running.")
+	if cronCentral {
+		fmt.Printf("The controller will run the job on a node with agent %s.\n", job.Agent)
+	} else {
+		fmt.Println("The job will run automatically when 'klaw start namespace' is running.")
+	}
 	fmt.Printf("Run now: klaw cron run %s\n", job.ID)
 
 	return nil
 }
 
 func runCronList(cmd *cobra.Command, args []string) error {
-	ctxMgr := cluster.NewContextManager(config.ConfigDir())
+	ctxMgr := contextManager()
 	clusterName, namespace, err := ctxMgr.RequireCurrent()
 	if err != nil {
 		return err
@@ -193,6 +254,10 @@ func runCronList(cmd *cobra.Command, args []string) error {
 	sched := getScheduler()
 	jobs := sched.ListJobs(clusterName, namespace)
 
+	if structuredOutput() {
+		return printObject(jobs)
+	}
+
 	if len(jobs) == 0 {
 		fmt.Printf("No scheduled jobs in %s/%s.\n", clusterName, namespace)
 		fmt.Println()
@@ -201,38 +266,25 @@ func runCronList(cmd *cobra.Command, args []string) error {
 		return nil
 	}
 
-	if jsonOut {
-		return json.NewEncoder(os.Stdout).Encode(jobs)
-	}
-
 	fmt.Printf("Scheduled Jobs in %s/%s:\n\n", clusterName, namespace)
 
-	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
-	_, _ = fmt.Fprintln(w, "ID\tNAME\tSCHEDULE\tAGENT\tSTATUS\tNEXT RUN")
-	_, _ = fmt.Fprintln(w, "--\t----\t--------\t-----\t------\t--------")
-
+	t := newTable("ID", "NAME", "SCHEDULE", "AGENT", "STATUS", "NEXT RUN").withWide("CRON", "PRIORITY", "LAST RUN", "RUNS", "FAILED", "TASK")
 	for _, job := range jobs {
-		status := "enabled"
-		if !job.Enabled {
-			status = "disabled"
-		}
+		status := jobStatus(job)
 
 		nextRun := "-"
 		if job.NextRun != nil {
 			nextRun = job.NextRun.Format("Jan 02 15:04")
 		}
-
-		scheduleDesc := scheduler.FormatSchedule(job.Cron)
-		if len(scheduleDesc) > 25 {
-			scheduleDesc = scheduleDesc[:22] + "..."
+		lastRun := "-"
+		if job.LastRun != nil {
+			lastRun = job.LastRun.Format("Jan 02 15:04")
 		}
 
-		_, _ = fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\n",
-			job.ID, job.Name, scheduleDesc, job.Agent, status, nextRun)
+		t.add(job.ID, job.Name, truncateCell(scheduler.FormatSchedule(job.Cron), 25), job.Agent, status, nextRun,
+			job.Cron, job.Priority.String(), lastRun, fmt.Sprint(job.RunCount), fmt.Sprint(job.FailCount), truncateCell(job.Task, 60))
 	}
-	_ = w.Flush()
-
-	return nil
+	return t.print()
 }
 
 func runCronDelete(cmd *cobra.Command, args []string) error {
@@ -330,14 +382,11 @@ func runCronDescribe(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
-	if jsonOut {
-		return json.NewEncoder(os.Stdout).Encode(job)
+	if structuredOutput() {
+		return printObject(job)
 	}
 
-	status := "enabled"
-	if !job.Enabled {
-		status = "disabled"
-	}
+	status := jobStatus(job)
 
 	fmt.Printf("ID:          %s\n", job.ID)
 	fmt.Printf("Name:        %s\n", job.Name)
@@ -346,8 +395,15 @@ func runCronDescribe(cmd *cobra.Command, args []string) error {
 	fmt.Printf("Cron:        %s\n", job.Cron)
 	fmt.Printf("Readable:    %s\n", scheduler.FormatSchedule(job.Cron))
 	fmt.Printf("Agent:       %s\n", job.Agent)
+	fmt.Printf("Priority:    %s\n", job.Priority)
 	if job.Config != nil && job.Config["channel"] != "" {
 		fmt.Printf("Channel:     %s\n", job.Config["channel"])
+		if batch := jobBatchSize(job); batch > 1 {
+			fmt.Printf("Batch:       %d messages\n", batch)
+		}
+		if users := job.Config["skip_users"]; users != "" {
+			fmt.Printf("Skip Users:  %s\n", users)
+		}
 	}
 	fmt.Printf("Cluster:     %s\n", job.Cluster)
 	fmt.Printf("Namespace:   %s\n", job.Namespace)
@@ -402,3 +458,46 @@ func init() {
   #   "monthly"
 `)
 }
+
+// jobStatus is enabled, disabled, or paused by what disabled the job.
+func jobStatus(job *scheduler.Job) string {
+	switch {
+	case job.Enabled:
+		return "enabled"
+	case job.PausedBy != "":
+		return "paused (" + job.PausedBy + ")"
+	}
+	return "disabled"
+}
+
+// jobChannelConfig is the config of a new job reading the --channel
+// messages, in batches of --batch, without those of --skip-users.
+func jobChannelConfig() map[string]string {
+	config := map[string]string{"channel": cronChannel}
+	if cronBatch > 0 {
+		config["batch"] = strconv.Itoa(cronBatch)
+	}
+	if len(cronSkip) > 0 {
+		config["skip_users"] = strings.Join(cronSkip, ",")
+	}
+	return config
+}
+
+// jobBatchSize is how many channel messages a run of the job analyzes in
+// one call; 0 or 1 analyzes each message alone.
+func jobBatchSize(job *scheduler.Job) int {
+	n, _ := strconv.Atoi(job.Config["batch"])
+	return n
+}
+
+// jobSkipUsers is the set of Slack users whose channel messages the job
+// never analyzes, e.g. other integrations posting to the channel.
+func jobSkipUsers(job *scheduler.Job) map[string]bool {
+	users := make(map[string]bool)
+	for _, u := range strings.Split(job.Config["skip_users"], ",") {
+		if u = strings.TrimSpace(u); u != "" {
+			users[u] = true
+		}
+	}
+	return users
+}
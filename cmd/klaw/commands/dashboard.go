@@ -1,12 +1,22 @@
 package commands
 
 import (
+	"context"
 	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
 
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/eachlabs/klaw/internal/agent"
+	"github.com/eachlabs/klaw/internal/channel"
 	"github.com/eachlabs/klaw/internal/cluster"
 	"github.com/eachlabs/klaw/internal/config"
+	"github.com/eachlabs/klaw/internal/controller"
+	"github.com/eachlabs/klaw/internal/memory"
 	"github.com/eachlabs/klaw/internal/scheduler"
+	"github.com/eachlabs/klaw/internal/skill"
+	"github.com/eachlabs/klaw/internal/tool"
 	"github.com/eachlabs/klaw/internal/tui"
 	"github.com/spf13/cobra"
 )
@@ -19,30 +29,65 @@ var dashboardCmd = &cobra.Command{
 
 The dashboard provides:
   📊 Overview      - Stats, agents, channels at a glance
-  🤖 Agents        - Create, view, delete agents
+  🤖 Agents        - Create, view, edit, delete agents
+  ⏰ Jobs          - Create, edit, run, enable/disable scheduled jobs
   📡 Channels      - Manage Slack/Discord connections
   💬 Conversations - View message history
+  💬 Chat          - Talk to an agent, with streamed replies
+  🧩 Skills        - Install skills from the marketplace, uninstall them
   ⚙️  Settings      - Cluster and orchestrator config
 
 Navigation:
-  1-5       Switch tabs
+  1-8       Switch tabs
   Tab       Next tab
   ↑/↓ j/k   Navigate lists
   Enter     View details
-  n         Create new agent
-  d         Delete selected
+  n         Create new agent or job
+  e         Edit selected agent or job (Ctrl+S saves an agent)
+  x         Run selected job now
+  s         Toggle channel or job status
+  d         Delete selected, after confirming
+  u         Undo deleting an agent or channel (for 10s)
+  /         Filter lists; search the marketplace on Skills
+  Esc       Clear the filter, go back
+  i/u       Install or uninstall the selected skill
   r         Refresh data
-  q         Quit`,
+  q         Quit
+
+Colors follow the terminal's background. Set [ui] theme = "dark" or
+"light" in config.toml (or KLAW_THEME) to choose, and override single
+colors under [ui.colors]: accent, on_accent, success, warning, error,
+info, muted, border, text, body, highlight.
+
+With --controller the dashboard connects to a remote controller over gRPC
+and shows the nodes, agents and tasks of the distributed deployment
+instead, read-only:
+  klaw dashboard --controller controller.internal:9090`,
 	RunE: runDashboard,
 }
 
+var dashboardController string
+
 func init() {
+	dashboardCmd.Flags().StringVar(&dashboardController, "controller", "", "Monitor the controller at this address")
 	rootCmd.AddCommand(dashboardCmd)
 }
 
 func runDashboard(cmd *cobra.Command, args []string) error {
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+	if err := tui.SetTheme(cfg.UI.Theme, cfg.UI.Colors); err != nil {
+		return err
+	}
+
+	if dashboardController != "" {
+		return runRemoteDashboard(dashboardController)
+	}
+
 	store := cluster.NewStore(config.StateDir())
-	ctxMgr := cluster.NewContextManager(config.ConfigDir())
+	ctxMgr := contextManager()
 
 	clusterName, namespace, err := ctxMgr.RequireCurrent()
 	if err != nil {
@@ -54,7 +99,16 @@ func runDashboard(cmd *cobra.Command, args []string) error {
 	_ = sched.Load()
 
 	// Create and run dashboard
+	ctx, cancel := context.WithCancel(cmd.Context())
+	defer cancel()
+	rt := newDashboardRuntime(ctx, store, clusterName, namespace)
+	sched.SetJobRunner(rt.runJob)
+
 	m := tui.NewDashboard(store, sched, clusterName, namespace)
+	m.SetChatConnector(rt.chat)
+	m.SetSkills(getSkillLoader(), skill.NewMarketplace(skill.MarketplaceConfig{
+		CacheDir: filepath.Join(config.StateDir(), "cache", "marketplace"),
+	}))
 	p := tea.NewProgram(m, tea.WithAltScreen(), tea.WithMouseCellMotion())
 
 	if _, err := p.Run(); err != nil {
@@ -63,3 +117,159 @@ func runDashboard(cmd *cobra.Command, args []string) error {
 
 	return nil
 }
+
+// runRemoteDashboard monitors the controller at address.
+func runRemoteDashboard(address string) error {
+	client, err := controller.NewClient(address)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = client.Close() }()
+
+	p := tea.NewProgram(tui.NewRemoteDashboard(client), tea.WithAltScreen(), tea.WithMouseCellMotion())
+	if _, err := p.Run(); err != nil {
+		return fmt.Errorf("dashboard error: %w", err)
+	}
+	return nil
+}
+
+// dashboardRuntime runs agent bindings in-process for the dashboard's chat
+// tab and its "run now" jobs, and for klaw serve's REST API, each with its
+// own prompt, model, tools and memory. The provider is set up on first use, so the dashboard opens
+// without one.
+type dashboardRuntime struct {
+	ctx         context.Context
+	store       *cluster.Store
+	clusterName string
+	namespace   string
+	source      string // of the tool calls in the audit trail
+
+	once      sync.Once
+	cfg       *config.Config
+	providers *providerPool
+	tools     *tool.Registry
+	workDir   string
+	err       error
+}
+
+func newDashboardRuntime(ctx context.Context, store *cluster.Store, clusterName, namespace string) *dashboardRuntime {
+	return &dashboardRuntime{ctx: ctx, store: store, clusterName: clusterName, namespace: namespace, source: "dashboard"}
+}
+
+func (r *dashboardRuntime) setup() error {
+	r.once.Do(func() {
+		if r.cfg, r.err = config.Load(); r.err != nil {
+			return
+		}
+		providerName, model := defaultProviderModel(r.cfg, "", "")
+		prov, err := buildProvider(r.cfg, providerName, model)
+		if err != nil {
+			r.err = err
+			return
+		}
+		r.providers = newProviderPool(r.cfg, providerName, model, prov)
+
+		if r.workDir, err = os.Getwd(); err != nil {
+			r.workDir = "."
+		}
+		r.tools = tool.DefaultRegistry(r.workDir)
+		r.tools.Register(namespaceSkillTool(r.clusterName, r.namespace))
+		facts := memory.NewFactStore(r.cfg.WorkspaceDir())
+		facts.SetLimits(memory.LimitsFromConfig(r.cfg))
+		for _, t := range tool.MemoryTools(facts) {
+			r.tools.Register(t)
+		}
+	})
+	return r.err
+}
+
+// agentConfig returns the configuration of an agent binding.
+func (r *dashboardRuntime) agentConfig(ctx context.Context, agentName string) (agent.Config, error) {
+	if err := r.setup(); err != nil {
+		return agent.Config{}, err
+	}
+	ab, err := r.store.GetAgentBinding(r.clusterName, r.namespace, agentName)
+	if err != nil {
+		return agent.Config{}, err
+	}
+	prov, err := r.providers.forAgent(ab)
+	if err != nil {
+		return agent.Config{}, err
+	}
+	agentTools, err := agentToolRegistry(r.cfg, r.tools, ab, r.workDir)
+	if err != nil {
+		return agent.Config{}, err
+	}
+	skillConfig, err := r.store.AgentSkillConfig(ab)
+	if err != nil {
+		return agent.Config{}, err
+	}
+	return agent.Config{
+		Provider:      prov,
+		Tools:         agentTools,
+		SystemPrompt:  agentPrompt(ctx, r.cfg.WorkspaceDir(), ab, ""),
+		MaxIterations: r.cfg.Defaults.MaxIterations,
+		SkillConfig:   skillConfig,
+		AgentName:     ab.Name,
+		Context:       agent.ContextConfig{MaxContextTokens: r.cfg.Defaults.MaxContextTokens},
+		Secrets:       secretMasker(r.cfg),
+		Guardrails:    namespaceGuardrails(r.store, r.clusterName, r.namespace),
+		Audit:         storeAudit{store: r.store, cluster: r.clusterName, namespace: r.namespace, source: r.source},
+		Transcripts:   transcriptRecorder(r.cfg, r.clusterName, r.namespace),
+	}, nil
+}
+
+// chat starts a conversation with an agent for the chat tab.
+func (r *dashboardRuntime) chat(agentName string) (*tui.ChatSession, error) {
+	chatCtx, cancel := context.WithCancel(r.ctx)
+	agentCfg, err := r.agentConfig(chatCtx, agentName)
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+	tuiChan := channel.NewTUIChannel()
+	agentCfg.Channel = tuiChan
+	ag := agent.New(agentCfg)
+	go func() {
+		if err := tuiChan.Start(chatCtx); err != nil {
+			return
+		}
+		_ = ag.Run(chatCtx)
+	}()
+
+	return &tui.ChatSession{
+		Input:  tuiChan.UserInput(),
+		Output: tuiChatMessages(chatCtx, tuiChan.TUIOutput(), nil),
+		Close: func() {
+			cancel()
+			_ = tuiChan.Stop()
+		},
+	}, nil
+}
+
+// runJob runs a scheduled job's task with its agent.
+func (r *dashboardRuntime) runJob(ctx context.Context, job *scheduler.Job) (string, error) {
+	return r.runOnce(ctx, job.Agent, job.Task, nil)
+}
+
+// runOnce runs an agent on a single prompt. usage, if set, records the
+// tokens the agent spends.
+func (r *dashboardRuntime) runOnce(ctx context.Context, agentName, prompt string, usage agent.UsageRecorder) (string, error) {
+	agentCfg, err := r.agentConfig(ctx, agentName)
+	if err != nil {
+		return "", err
+	}
+	return agent.RunOnce(ctx, agent.RunOnceConfig{
+		Provider:     agentCfg.Provider,
+		Tools:        agentCfg.Tools,
+		SystemPrompt: agentCfg.SystemPrompt,
+		Prompt:       prompt,
+		MaxTokens:    8192,
+		SkillConfig:  agentCfg.SkillConfig,
+		AgentName:    agentCfg.AgentName,
+		Usage:        usage,
+		Secrets:      agentCfg.Secrets,
+		Guardrails:   agentCfg.Guardrails,
+		Audit:        agentCfg.Audit,
+	})
+}
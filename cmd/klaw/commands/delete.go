@@ -7,6 +7,7 @@ import (
 
 	"github.com/eachlabs/klaw/internal/cluster"
 	"github.com/eachlabs/klaw/internal/config"
+	"github.com/eachlabs/klaw/internal/history"
 	"github.com/spf13/cobra"
 )
 
@@ -19,12 +20,14 @@ var deleteCmd = &cobra.Command{
 Resources:
   server     Stop a running server
   session    Delete a session
+  conversation  Delete an agent conversation history
   channel    Remove a channel configuration`,
 }
 
 func init() {
 	deleteCmd.AddCommand(deleteServerCmd)
 	deleteCmd.AddCommand(deleteSessionCmd)
+	deleteCmd.AddCommand(deleteConversationCmd)
 	deleteCmd.AddCommand(deleteChannelCmd)
 }
 
@@ -60,6 +63,37 @@ var deleteSessionCmd = &cobra.Command{
 	},
 }
 
+var deleteConversationCmd = &cobra.Command{
+	Use:     "conversation <id>",
+	Aliases: []string{"conv"},
+	Short:   "Delete an agent conversation history",
+	Args:    cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg, err := config.Load()
+		if err != nil {
+			return fmt.Errorf("failed to load config: %w", err)
+		}
+		store, err := history.OpenFromConfig(cfg)
+		if err != nil {
+			return err
+		}
+
+		msgs, err := store.Get(args[0])
+		if err != nil {
+			return err
+		}
+		if msgs == nil {
+			return fmt.Errorf("conversation not found: %s", args[0])
+		}
+		if err := store.Delete(args[0]); err != nil {
+			return fmt.Errorf("failed to delete conversation: %w", err)
+		}
+
+		fmt.Printf("Deleted conversation: %s\n", args[0])
+		return nil
+	},
+}
+
 var deleteChannelCmd = &cobra.Command{
 	Use:     "channel <name>",
 	Aliases: []string{"ch"},
@@ -69,7 +103,7 @@ var deleteChannelCmd = &cobra.Command{
 		name := args[0]
 
 		store := cluster.NewStore(config.StateDir())
-		ctxMgr := cluster.NewContextManager(config.ConfigDir())
+		ctxMgr := contextManager()
 
 		clusterName, namespace, err := ctxMgr.RequireCurrent()
 		if err != nil {
@@ -1,11 +1,12 @@
 package commands
 
 import (
-	"encoding/json"
 	"fmt"
-	"os"
 
+	"github.com/eachlabs/klaw/internal/cluster"
 	"github.com/eachlabs/klaw/internal/config"
+	"github.com/eachlabs/klaw/internal/history"
+	"github.com/eachlabs/klaw/internal/provider"
 	"github.com/eachlabs/klaw/internal/session"
 	"github.com/spf13/cobra"
 )
@@ -18,13 +19,15 @@ var describeCmd = &cobra.Command{
 Resources:
   server     Server details
   session    Session transcript and metadata
+  conversation  Agent conversation transcript (e.g. a Slack thread)
   model      Model capabilities and pricing
-  channel    Channel configuration`,
+  channel    Channel binding or configuration`,
 }
 
 func init() {
 	describeCmd.AddCommand(describeServerCmd)
 	describeCmd.AddCommand(describeSessionCmd)
+	describeCmd.AddCommand(describeConversationCmd)
 	describeCmd.AddCommand(describeModelCmd)
 	describeCmd.AddCommand(describeChannelCmd)
 }
@@ -55,10 +58,8 @@ var describeSessionCmd = &cobra.Command{
 			return err
 		}
 
-		if jsonOut {
-			enc := json.NewEncoder(os.Stdout)
-			enc.SetIndent("", "  ")
-			return enc.Encode(sess)
+		if structuredOutput() {
+			return printObject(sess)
 		}
 
 		// Print metadata
@@ -79,36 +80,74 @@ var describeSessionCmd = &cobra.Command{
 		fmt.Printf("Updated: %s\n", sess.UpdatedAt.Format("2006-01-02 15:04:05"))
 		fmt.Printf("Messages: %d\n", len(sess.Messages))
 
-		// Print transcript
-		if len(sess.Messages) > 0 {
-			fmt.Println("\n--- Transcript ---")
-			for _, msg := range sess.Messages {
-				role := msg.Role
-				content := msg.Content
-
-				// Handle tool results
-				if msg.ToolResult != nil {
-					fmt.Printf("\n[tool_result]: %s\n", truncateContent(msg.ToolResult.Content, 200))
-					continue
-				}
-
-				// Handle tool calls
-				if len(msg.ToolCalls) > 0 {
-					fmt.Printf("\n[%s]: %s\n", role, truncateContent(content, 200))
-					for _, tc := range msg.ToolCalls {
-						fmt.Printf("  -> tool_call: %s\n", tc.Name)
-					}
-					continue
-				}
-
-				fmt.Printf("\n[%s]: %s\n", role, truncateContent(content, 200))
-			}
+		printTranscript(sess.Messages)
+		return nil
+	},
+}
+
+var describeConversationCmd = &cobra.Command{
+	Use:     "conversation <id>",
+	Aliases: []string{"conv"},
+	Short:   "Show an agent conversation transcript",
+	Args:    cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg, err := config.Load()
+		if err != nil {
+			return fmt.Errorf("failed to load config: %w", err)
+		}
+		store, err := history.OpenFromConfig(cfg)
+		if err != nil {
+			return err
 		}
 
+		msgs, err := store.Get(args[0])
+		if err != nil {
+			return err
+		}
+		if msgs == nil {
+			return fmt.Errorf("conversation not found: %s", args[0])
+		}
+
+		if structuredOutput() {
+			return printObject(msgs)
+		}
+
+		fmt.Printf("Conversation: %s\n", args[0])
+		fmt.Printf("Messages: %d\n", len(msgs))
+		printTranscript(msgs)
 		return nil
 	},
 }
 
+// printTranscript prints messages with tool calls and results abbreviated.
+func printTranscript(msgs []provider.Message) {
+	if len(msgs) == 0 {
+		return
+	}
+	fmt.Println("\n--- Transcript ---")
+	for _, msg := range msgs {
+		role := msg.Role
+		content := msg.Content
+
+		// Handle tool results
+		if msg.ToolResult != nil {
+			fmt.Printf("\n[tool_result]: %s\n", truncateContent(msg.ToolResult.Content, 200))
+			continue
+		}
+
+		// Handle tool calls
+		if len(msg.ToolCalls) > 0 {
+			fmt.Printf("\n[%s]: %s\n", role, truncateContent(content, 200))
+			for _, tc := range msg.ToolCalls {
+				fmt.Printf("  -> tool_call: %s\n", tc.Name)
+			}
+			continue
+		}
+
+		fmt.Printf("\n[%s]: %s\n", role, truncateContent(content, 200))
+	}
+}
+
 // truncateContent truncates content for display
 func truncateContent(s string, max int) string {
 	if len(s) <= max {
@@ -163,10 +202,8 @@ var describeModelCmd = &cobra.Command{
 			return fmt.Errorf("unknown model: %s", modelID)
 		}
 
-		if jsonOut {
-			enc := json.NewEncoder(os.Stdout)
-			enc.SetIndent("", "  ")
-			return enc.Encode(model)
+		if structuredOutput() {
+			return printObject(model)
 		}
 
 		fmt.Printf("Model: %s\n", model.ID)
@@ -193,11 +230,17 @@ type modelDetail struct {
 }
 
 var describeChannelCmd = &cobra.Command{
-	Use:     "channel <type>",
+	Use:     "channel <name|type>",
 	Aliases: []string{"ch"},
 	Short:   "Show channel details",
-	Args:    cobra.ExactArgs(1),
+	Long: `Show a channel of the current namespace, created with klaw create
+channel, or a channel type configured in config.toml.`,
+	Args: cobra.ExactArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
+		if binding := currentChannelBinding(args[0]); binding != nil {
+			return describeChannelBinding(binding)
+		}
+
 		channelType := args[0]
 
 		if channelType == "terminal" {
@@ -217,10 +260,8 @@ var describeChannelCmd = &cobra.Command{
 			return fmt.Errorf("channel not configured: %s", channelType)
 		}
 
-		if jsonOut {
-			enc := json.NewEncoder(os.Stdout)
-			enc.SetIndent("", "  ")
-			return enc.Encode(ch)
+		if structuredOutput() {
+			return printObject(ch)
 		}
 
 		fmt.Printf("Channel: %s\n", channelType)
@@ -233,3 +274,52 @@ var describeChannelCmd = &cobra.Command{
 		return nil
 	},
 }
+
+// currentChannelBinding returns the channel binding of the current
+// namespace named name, or nil if there is none.
+func currentChannelBinding(name string) *cluster.ChannelBinding {
+	clusterName, namespace, err := contextManager().RequireCurrent()
+	if err != nil {
+		return nil
+	}
+	binding, err := cluster.NewStore(config.StateDir()).GetChannelBinding(clusterName, namespace, name)
+	if err != nil {
+		return nil
+	}
+	return binding
+}
+
+func describeChannelBinding(b *cluster.ChannelBinding) error {
+	if structuredOutput() {
+		return printObject(b)
+	}
+
+	fmt.Printf("Channel: %s\n", b.Name)
+	fmt.Printf("Type: %s\n", b.Type)
+	fmt.Printf("Namespace: %s/%s\n", b.Cluster, b.Namespace)
+	fmt.Printf("Status: %s\n", b.Status)
+	if b.Health != nil {
+		health := b.Health.State
+		if b.Health.Error != "" {
+			health += " (" + b.Health.Error + ")"
+		}
+		fmt.Printf("Health: %s\n", health)
+	}
+	for _, key := range []string{"bot_token", "app_token", "token", "secret"} {
+		if v := b.Config[key]; v != "" {
+			fmt.Printf("%s: %s\n", key, maskToken(v))
+		}
+	}
+	if b.Type == "slack" {
+		if _, err := bindingThreadRetention(b); err != nil {
+			return err
+		}
+		retention := b.Config["thread_retention"]
+		if retention == "" {
+			retention = "1h (default)"
+		}
+		fmt.Printf("Thread retention: %s\n", retention)
+	}
+	fmt.Printf("Created: %s\n", b.CreatedAt.Format("2006-01-02 15:04"))
+	return nil
+}
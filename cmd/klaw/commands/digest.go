@@ -0,0 +1,268 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/eachlabs/klaw/internal/cluster"
+	"github.com/eachlabs/klaw/internal/config"
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	namespaceDigestCmd.AddCommand(namespaceDigestSetCmd)
+	namespaceDigestCmd.AddCommand(namespaceDigestShowCmd)
+	namespaceDigestCmd.AddCommand(namespaceDigestClearCmd)
+	namespaceDigestCmd.AddCommand(namespaceDigestPreviewCmd)
+	namespaceCmd.AddCommand(namespaceDigestCmd)
+}
+
+// --- klaw namespace digest ---
+
+var namespaceDigestCmd = &cobra.Command{
+	Use:   "digest",
+	Short: "Manage the daily activity digest of a namespace",
+	Long: `Post a summary of the namespace's last 24 hours to a Slack channel every
+day: messages handled per agent, cron jobs run and failed, errors, and
+cost. klaw start posts the digest at the set time; preview prints it now.
+
+Examples:
+  klaw namespace digest set --channel C0123456789 --at 09:00
+  klaw namespace digest show
+  klaw namespace digest preview
+  klaw namespace digest clear`,
+}
+
+var (
+	digestNamespace string
+	digestChannel   string
+	digestAt        string
+)
+
+// digestTarget resolves the cluster and namespace for the digest commands.
+func digestTarget() (string, string, error) {
+	clusterName, namespace, err := contextManager().RequireCurrent()
+	if err != nil {
+		return "", "", err
+	}
+	if digestNamespace != "" {
+		namespace = digestNamespace
+	}
+	return clusterName, namespace, nil
+}
+
+var namespaceDigestSetCmd = &cobra.Command{
+	Use:   "set",
+	Short: "Schedule the daily digest of a namespace",
+	Long:  `Schedule the daily digest of a namespace. Only the flags given are changed.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		store := cluster.NewStore(config.StateDir())
+		clusterName, namespace, err := digestTarget()
+		if err != nil {
+			return err
+		}
+
+		digest, err := store.NamespaceDigest(clusterName, namespace)
+		if err != nil {
+			return err
+		}
+		if digest == nil {
+			digest = &cluster.DigestConfig{At: "09:00"}
+		}
+
+		flags := cmd.Flags()
+		if flags.Changed("channel") {
+			digest.Channel = digestChannel
+		}
+		if flags.Changed("at") {
+			digest.At = digestAt
+		}
+		if digest.Channel == "" {
+			return fmt.Errorf("--channel is required")
+		}
+		at, err := cluster.DigestTime(digest.At)
+		if err != nil {
+			return err
+		}
+		digest.At = at.Format("15:04")
+
+		if err := store.SetNamespaceDigest(clusterName, namespace, digest); err != nil {
+			return err
+		}
+		fmt.Printf("Digest of namespace '%s' is posted to %s daily at %s while klaw start runs.\n", namespace, digest.Channel, digest.At)
+		return nil
+	},
+}
+
+var namespaceDigestShowCmd = &cobra.Command{
+	Use:   "show",
+	Short: "Show the digest schedule of a namespace",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		store := cluster.NewStore(config.StateDir())
+		clusterName, namespace, err := digestTarget()
+		if err != nil {
+			return err
+		}
+
+		digest, err := store.NamespaceDigest(clusterName, namespace)
+		if err != nil {
+			return err
+		}
+		if structuredOutput() {
+			return printObject(digest)
+		}
+		if digest == nil {
+			fmt.Printf("No digest for namespace '%s'.\n", namespace)
+			return nil
+		}
+		fmt.Printf("Channel:     %s\n", digest.Channel)
+		fmt.Printf("At:          %s\n", digest.At)
+		lastSent := digest.LastSent
+		if lastSent == "" {
+			lastSent = "(never)"
+		}
+		fmt.Printf("Last sent:   %s\n", lastSent)
+		return nil
+	},
+}
+
+var namespaceDigestClearCmd = &cobra.Command{
+	Use:   "clear",
+	Short: "Stop the daily digest of a namespace",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		store := cluster.NewStore(config.StateDir())
+		clusterName, namespace, err := digestTarget()
+		if err != nil {
+			return err
+		}
+
+		if err := store.SetNamespaceDigest(clusterName, namespace, nil); err != nil {
+			return err
+		}
+		fmt.Printf("Digest removed from namespace '%s'.\n", namespace)
+		return nil
+	},
+}
+
+var namespaceDigestPreviewCmd = &cobra.Command{
+	Use:   "preview",
+	Short: "Print the digest of the last 24 hours",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		store := cluster.NewStore(config.StateDir())
+		clusterName, namespace, err := digestTarget()
+		if err != nil {
+			return err
+		}
+
+		now := time.Now()
+		digest, err := store.BuildDigest(clusterName, namespace, now.Add(-24*time.Hour), now)
+		if err != nil {
+			return err
+		}
+		if structuredOutput() {
+			return printObject(digest)
+		}
+		fmt.Print(digestText(digest))
+		return nil
+	},
+}
+
+func init() {
+	namespaceDigestCmd.PersistentFlags().StringVarP(&digestNamespace, "namespace", "n", "", "namespace (uses current if not set)")
+	namespaceDigestSetCmd.Flags().StringVar(&digestChannel, "channel", "", "Slack channel ID to post the digest to")
+	namespaceDigestSetCmd.Flags().StringVar(&digestAt, "at", "09:00", "local time of day to post at (HH:MM)")
+}
+
+// digestText formats a digest as a Slack message.
+func digestText(d *cluster.Digest) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "*Daily digest of %s/%s* (%s – %s)\n\n", d.Cluster, d.Namespace,
+		d.Since.Format("Jan 2 15:04"), d.Until.Format("Jan 2 15:04"))
+
+	messages := 0
+	for _, a := range d.Agents {
+		messages += a.Messages
+	}
+	fmt.Fprintf(&b, "Messages handled: %d\n", messages)
+	for _, a := range d.Agents {
+		if a.Messages == 0 && a.JobRuns == 0 && a.Errors == 0 && a.Cost == 0 {
+			continue
+		}
+		line := fmt.Sprintf("• %s: %d messages", a.Name, a.Messages)
+		if a.JobRuns > 0 {
+			line += fmt.Sprintf(", %d job runs", a.JobRuns)
+		}
+		if a.Errors > 0 {
+			line += fmt.Sprintf(", %d errors", a.Errors)
+		}
+		line += fmt.Sprintf(", $%.2f", a.Cost)
+		b.WriteString(line + "\n")
+	}
+
+	fmt.Fprintf(&b, "\nJobs run: %d", d.JobRuns)
+	if d.JobsFail > 0 {
+		fmt.Fprintf(&b, " (%d failed)", d.JobsFail)
+	}
+	b.WriteString("\n")
+	names := make([]string, 0, len(d.FailedJobs))
+	for name := range d.FailedJobs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		fmt.Fprintf(&b, "• %s: %s\n", name, truncateStr(d.FailedJobs[name], 120))
+	}
+
+	fmt.Fprintf(&b, "Errors: %d\n", d.Errors)
+	fmt.Fprintf(&b, "Cost: $%.2f (%d tokens)\n", d.Cost, d.Tokens)
+	return b.String()
+}
+
+// digestWatcher posts a namespace's daily digest while klaw start runs.
+type digestWatcher struct {
+	store     *cluster.Store
+	cluster   string
+	namespace string
+	post      func(channelID, text string) error
+}
+
+func (w *digestWatcher) run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		if err := w.check(time.Now()); err != nil {
+			fmt.Printf("Warning: digest: %v\n", err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// check posts the digest of the last 24 hours once it is due. A digest
+// that fails to post is not retried until the next day.
+func (w *digestWatcher) check(now time.Time) error {
+	digest, err := w.store.NamespaceDigest(w.cluster, w.namespace)
+	if err != nil || digest == nil || !digest.Due(now) {
+		return err
+	}
+
+	digest.LastSent = now.Format("2006-01-02")
+	if err := w.store.SetNamespaceDigest(w.cluster, w.namespace, digest); err != nil {
+		return err
+	}
+	d, err := w.store.BuildDigest(w.cluster, w.namespace, now.Add(-24*time.Hour), now)
+	if err != nil {
+		return err
+	}
+	if err := w.post(digest.Channel, digestText(d)); err != nil {
+		return fmt.Errorf("failed to post to %s: %w", digest.Channel, err)
+	}
+	fmt.Printf("[%s] Posted the daily digest to %s\n", now.Format("15:04:05"), digest.Channel)
+	return nil
+}
@@ -9,8 +9,13 @@ import (
 	"os"
 	"time"
 
+	"github.com/eachlabs/klaw/internal/agent"
 	"github.com/eachlabs/klaw/internal/config"
+	"github.com/eachlabs/klaw/internal/controller"
 	"github.com/eachlabs/klaw/internal/controller/pb"
+	"github.com/eachlabs/klaw/internal/priority"
+	"github.com/eachlabs/klaw/internal/session"
+	"github.com/eachlabs/klaw/internal/tool"
 	"github.com/spf13/cobra"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials/insecure"
@@ -22,6 +27,12 @@ var (
 	dispatchWait       bool
 	dispatchTimeout    int
 	dispatchUseGRPC    bool
+	dispatchSchema     string
+	dispatchRetries    int
+	dispatchQuiet      bool
+	dispatchSession    string
+	dispatchPriority   string
+	dispatchFollow     bool
 )
 
 var dispatchCmd = &cobra.Command{
@@ -34,7 +45,41 @@ The controller will route the task to the node running the agent.
 Examples:
   klaw dispatch researcher "Find the latest AI news"
   klaw dispatch coder "Write a hello world in Go" --wait
-  klaw dispatch writer "Draft an email" --controller localhost:9090`,
+  klaw dispatch writer "Draft an email" --controller localhost:9090
+  klaw dispatch analyst "Summarize open incidents" --schema incidents.schema.json
+
+With --schema the agent must answer with JSON matching the JSON schema
+file. Replies that don't validate are sent back for repair, and only the
+validated JSON is printed to stdout.
+
+For scripts and CI pipelines, --quiet prints only the result, and -o json
+prints the task ID, status, result, error and duration as one JSON object.
+The exit code tells how the task ended:
+  0  the task completed (or was dispatched, with --wait=false)
+  1  the task could not be dispatched, e.g. the controller is unreachable
+  2  the agent failed or rejected the task
+  3  no result within --timeout
+
+  klaw dispatch coder "Review the diff" -q --timeout 120 > review.md
+  klaw dispatch analyst "Summarize incidents" -o json | jq -r .result
+
+With --session, successive calls share a history: each task is sent with
+the earlier tasks and results of the session, which is created on first
+use. Manage sessions with klaw session list/show/delete.
+
+  klaw dispatch coder "Write a CLI that greets the user" --session greeter
+  klaw dispatch coder "Now add a --name flag" --session greeter
+
+Nodes run a few tasks at once and queue the rest. --priority (low, normal,
+high or a number) orders the queue; queued tasks rise a level for every
+minute they wait, so low-priority tasks still run.
+
+  klaw dispatch oncall "Triage the failing deploy" --priority high
+
+--follow prints the tool calls of the agent and their results as the node
+runs them, then the result. With --quiet or -o json they go to stderr.
+
+  klaw dispatch coder "Fix the failing test" --follow`,
 	Args: cobra.ExactArgs(2),
 	RunE: runDispatch,
 }
@@ -43,8 +88,14 @@ func init() {
 	dispatchCmd.Flags().StringVar(&dispatchController, "controller", "localhost:9090", "Controller address")
 	dispatchCmd.Flags().StringVar(&dispatchToken, "token", "", "Authentication token")
 	dispatchCmd.Flags().BoolVar(&dispatchWait, "wait", true, "Wait for task completion")
-	dispatchCmd.Flags().IntVar(&dispatchTimeout, "timeout", 300, "Timeout in seconds")
+	dispatchCmd.Flags().IntVar(&dispatchTimeout, "timeout", 300, "Seconds to wait for the result")
 	dispatchCmd.Flags().BoolVar(&dispatchUseGRPC, "grpc", true, "Use gRPC protocol (default: true)")
+	dispatchCmd.Flags().StringVar(&dispatchSchema, "schema", "", "JSON schema file the result must match")
+	dispatchCmd.Flags().IntVar(&dispatchRetries, "schema-retries", 2, "Repair attempts for results that don't match --schema")
+	dispatchCmd.Flags().BoolVarP(&dispatchQuiet, "quiet", "q", false, "Print only the final result")
+	dispatchCmd.Flags().StringVar(&dispatchPriority, "priority", "normal", "Task priority: low, normal, high or a number")
+	dispatchCmd.Flags().StringVar(&dispatchSession, "session", "", "Named session whose earlier tasks and results the task is sent with")
+	dispatchCmd.Flags().BoolVarP(&dispatchFollow, "follow", "f", false, "Print the tool calls of the task as they run")
 
 	rootCmd.AddCommand(dispatchCmd)
 }
@@ -57,9 +108,10 @@ type DispatchMessage struct {
 	Token string `json:"token,omitempty"`
 
 	// Task dispatch
-	Agent  string `json:"agent,omitempty"`
-	Prompt string `json:"prompt,omitempty"`
-	TaskID string `json:"task_id,omitempty"`
+	Agent    string `json:"agent,omitempty"`
+	Prompt   string `json:"prompt,omitempty"`
+	TaskID   string `json:"task_id,omitempty"`
+	Priority int    `json:"priority,omitempty"`
 
 	// Response
 	Status string `json:"status,omitempty"`
@@ -70,6 +122,23 @@ type DispatchMessage struct {
 func runDispatch(cmd *cobra.Command, args []string) error {
 	agentName := args[0]
 	prompt := args[1]
+	if _, err := outputFormat(); err != nil {
+		return err
+	}
+	level, err := priority.Parse(dispatchPriority)
+	if err != nil {
+		return err
+	}
+	if dispatchFollow {
+		if !dispatchWait || !dispatchUseGRPC {
+			return fmt.Errorf("--follow requires --wait and gRPC")
+		}
+		if dispatchSchema != "" {
+			return fmt.Errorf("--follow cannot be combined with --schema")
+		}
+	}
+	// Failures of the task itself are not usage errors
+	cmd.SilenceUsage = true
 
 	// Load token from config if not provided
 	if dispatchToken == "" {
@@ -82,26 +151,197 @@ func runDispatch(cmd *cobra.Command, args []string) error {
 		}
 	}
 
-	fmt.Printf("📤 Dispatching task to agent: %s\n", agentName)
-	fmt.Printf("   Controller: %s\n", dispatchController)
-	fmt.Printf("   Protocol:   %s\n", map[bool]string{true: "gRPC", false: "TCP/JSON"}[dispatchUseGRPC])
-	fmt.Println()
+	// A task of a session is sent with the session's earlier exchanges
+	task := prompt
+	var sessions *session.Manager
+	if dispatchSession != "" {
+		if !dispatchWait {
+			return fmt.Errorf("--session requires --wait")
+		}
+		sessions = openDispatchSession(dispatchSession, agentName)
+		task = sessionTask(sessions.Messages(), prompt)
+	}
+
+	if dispatchVerbose() && dispatchSchema == "" {
+		fmt.Printf("📤 Dispatching task to agent: %s\n", agentName)
+		fmt.Printf("   Controller: %s\n", dispatchController)
+		fmt.Printf("   Protocol:   %s\n", map[bool]string{true: "gRPC", false: "TCP/JSON"}[dispatchUseGRPC])
+		if sessions != nil {
+			fmt.Printf("   Session:    %s (%d messages)\n", dispatchSession, len(sessions.Messages()))
+		}
+		fmt.Println()
+	}
+
+	started := time.Now()
+	var res *dispatchResult
+	switch {
+	case dispatchSchema != "":
+		res, err = runDispatchStructured(agentName, task, level)
+	case dispatchFollow:
+		res, err = runDispatchFollow(agentName, task, level)
+	case dispatchUseGRPC:
+		res, err = runDispatchGRPC(agentName, task, level)
+	default:
+		res, err = runDispatchTCP(agentName, task, level)
+	}
+	if err != nil {
+		return err
+	}
+	res.Agent = agentName
+	res.DurationMS = time.Since(started).Milliseconds()
+	if sessions != nil && res.Status == dispatchCompleted {
+		if err := recordDispatch(sessions, prompt, res.Result); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: failed to save session %s: %v\n", dispatchSession, err)
+		}
+	}
+	return reportDispatch(res)
+}
 
-	if dispatchUseGRPC {
-		return runDispatchGRPC(agentName, prompt)
+// Statuses of a dispatch result
+const (
+	dispatchCompleted  = "completed"
+	dispatchDispatched = "dispatched" // not waited for
+	dispatchFailed     = "failed"
+	dispatchTimedOut   = "timeout"
+)
+
+// Exit codes of klaw dispatch besides 0 and 1, which means the task could
+// not be dispatched
+const (
+	exitAgentError = 2
+	exitTimeout    = 3
+)
+
+// dispatchResult is the outcome of a dispatched task, as printed with
+// -o json.
+type dispatchResult struct {
+	TaskID     string `json:"task_id,omitempty"`
+	Agent      string `json:"agent"`
+	Status     string `json:"status"`
+	Result     string `json:"result,omitempty"`
+	Error      string `json:"error,omitempty"`
+	DurationMS int64  `json:"duration_ms"`
+}
+
+// err returns the error klaw dispatch exits with for the result.
+func (r *dispatchResult) err() error {
+	switch r.Status {
+	case dispatchFailed:
+		return &exitError{code: exitAgentError, err: fmt.Errorf("task failed: %s", r.Error)}
+	case dispatchTimedOut:
+		return &exitError{code: exitTimeout, err: fmt.Errorf("task timed out after %ds", dispatchTimeout)}
 	}
+	return nil
+}
 
-	return runDispatchTCP(agentName, prompt)
+// dispatchVerbose reports whether progress should be printed: not with
+// --quiet or -o json|yaml.
+func dispatchVerbose() bool {
+	return !dispatchQuiet && !structuredOutput()
 }
 
-func runDispatchGRPC(agentName, prompt string) error {
+// dispatchDeadline bounds a dispatch call. It leaves the controller time
+// to answer that the task timed out.
+func dispatchDeadline() time.Duration {
+	return time.Duration(dispatchTimeout)*time.Second + 10*time.Second
+}
+
+// reportDispatch prints the result and returns the error to exit with.
+func reportDispatch(res *dispatchResult) error {
+	switch {
+	case structuredOutput():
+		if err := printObject(res); err != nil {
+			return err
+		}
+	case dispatchQuiet || dispatchSchema != "":
+		if res.Status == dispatchCompleted {
+			fmt.Println(res.Result)
+		}
+	default:
+		switch res.Status {
+		case dispatchDispatched:
+			fmt.Println("\nTask dispatched. Use 'klaw get tasks' to check status.")
+		case dispatchCompleted:
+			fmt.Println("\n✅ Task completed!")
+			fmt.Println()
+			fmt.Println("Result:")
+			fmt.Println("───────────────────────────────────────")
+			fmt.Println(res.Result)
+			fmt.Println("───────────────────────────────────────")
+		}
+	}
+	return res.err()
+}
+
+// grpcDispatchResult converts the controller's answer to a dispatch.
+func grpcDispatchResult(resp *pb.DispatchTaskResponse) *dispatchResult {
+	res := &dispatchResult{TaskID: resp.TaskId, Status: resp.Status, Result: resp.Result, Error: resp.Error}
+	switch {
+	case resp.Status == dispatchTimedOut:
+	case resp.Error != "":
+		res.Status = dispatchFailed
+	case resp.Status != dispatchCompleted && resp.Status != dispatchDispatched:
+		res.Status = dispatchFailed
+		res.Error = "task ended with status " + resp.Status
+	}
+	return res
+}
+
+// controllerAgentRunner runs agents through the configured controller for
+// agent_dispatch. It returns nil when no controller is configured.
+func controllerAgentRunner(cfg *config.Config) tool.AgentRunFunc {
+	if cfg.Controller == nil || cfg.Controller.Address == "" {
+		return nil
+	}
+	address, token := cfg.Controller.Address, cfg.Controller.Token
+	return func(ctx context.Context, agentName, task string) (string, error) {
+		conn, err := grpc.NewClient(address, grpc.WithTransportCredentials(insecure.NewCredentials()))
+		if err != nil {
+			return "", fmt.Errorf("failed to connect to controller: %w", err)
+		}
+		defer func() { _ = conn.Close() }()
+
+		timeout := 300
+		if deadline, ok := ctx.Deadline(); ok {
+			timeout = int(time.Until(deadline).Seconds())
+		}
+		resp, err := pb.NewControllerServiceClient(conn).DispatchTask(ctx, &pb.DispatchTaskRequest{
+			Token:          token,
+			AgentName:      agentName,
+			Prompt:         task,
+			Wait:           true,
+			TimeoutSeconds: int32(timeout),
+		})
+		if err != nil {
+			return "", fmt.Errorf("dispatch failed: %w", err)
+		}
+		if resp.Error != "" {
+			return "", fmt.Errorf("dispatch failed: %s", resp.Error)
+		}
+		if resp.Status != "completed" {
+			return "", fmt.Errorf("task %s %s", resp.TaskId, resp.Status)
+		}
+		return resp.Result, nil
+	}
+}
+
+// dispatchMetadata carries the task priority to the controller, which
+// the gRPC dispatch request has no field for.
+func dispatchMetadata(level priority.Level) map[string]string {
+	if level == priority.Normal {
+		return nil
+	}
+	return map[string]string{controller.MetaPriority: level.String()}
+}
+
+func runDispatchGRPC(agentName, prompt string, level priority.Level) (*dispatchResult, error) {
 	// Connect via gRPC
-	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(dispatchTimeout)*time.Second)
+	ctx, cancel := context.WithTimeout(context.Background(), dispatchDeadline())
 	defer cancel()
 
 	conn, err := grpc.NewClient(dispatchController, grpc.WithTransportCredentials(insecure.NewCredentials()))
 	if err != nil {
-		return fmt.Errorf("failed to connect to controller: %w", err)
+		return nil, fmt.Errorf("failed to connect to controller: %w", err)
 	}
 	defer func() { _ = conn.Close() }()
 
@@ -112,44 +352,176 @@ func runDispatchGRPC(agentName, prompt string) error {
 		Token:          dispatchToken,
 		AgentName:      agentName,
 		Prompt:         prompt,
+		Metadata:       dispatchMetadata(level),
 		Wait:           dispatchWait,
 		TimeoutSeconds: int32(dispatchTimeout),
 	})
 	if err != nil {
-		return fmt.Errorf("dispatch failed: %w", err)
+		if ctx.Err() == context.DeadlineExceeded {
+			return &dispatchResult{Status: dispatchTimedOut, Error: "task timed out"}, nil
+		}
+		return nil, fmt.Errorf("dispatch failed: %w", err)
+	}
+	if resp.TaskId != "" && dispatchVerbose() {
+		fmt.Printf("✅ Task created: %s\n", resp.TaskId)
+	}
+	return grpcDispatchResult(resp), nil
+}
+
+// runDispatchFollow dispatches a task without waiting, then follows it on
+// the controller's task stream, printing its tool calls until the result
+// comes.
+func runDispatchFollow(agentName, prompt string, level priority.Level) (*dispatchResult, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), dispatchDeadline())
+	defer cancel()
+
+	conn, err := grpc.NewClient(dispatchController, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to controller: %w", err)
 	}
+	defer func() { _ = conn.Close() }()
 
+	client := pb.NewControllerServiceClient(conn)
+	resp, err := client.DispatchTask(ctx, &pb.DispatchTaskRequest{
+		Token:          dispatchToken,
+		AgentName:      agentName,
+		Prompt:         prompt,
+		Metadata:       dispatchMetadata(level),
+		TimeoutSeconds: int32(dispatchTimeout),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("dispatch failed: %w", err)
+	}
 	if resp.Error != "" {
-		return fmt.Errorf("dispatch failed: %s", resp.Error)
+		return grpcDispatchResult(resp), nil
+	}
+	out := os.Stdout
+	if !dispatchVerbose() {
+		out = os.Stderr
+	} else {
+		fmt.Printf("✅ Task created: %s\n\n", resp.TaskId)
 	}
 
-	fmt.Printf("✅ Task created: %s\n", resp.TaskId)
+	stream, err := client.TaskStream(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to follow task: %w", err)
+	}
+	err = stream.Send(&pb.TaskMessage{Type: "follow", TaskId: resp.TaskId, Metadata: map[string]string{"token": dispatchToken}})
+	if err != nil {
+		return nil, fmt.Errorf("failed to follow task: %w", err)
+	}
+	timedOut := &dispatchResult{TaskID: resp.TaskId, Status: dispatchTimedOut, Error: "task timed out"}
+	for {
+		msg, err := stream.Recv()
+		if err != nil {
+			if ctx.Err() == context.DeadlineExceeded {
+				return timedOut, nil
+			}
+			return nil, fmt.Errorf("failed to follow task: %w", err)
+		}
+		switch msg.Type {
+		case controller.EventToolCall, controller.EventToolResult:
+			printToolEvent(out, controller.ToolEventFromMessage(msg))
+		case "progress":
+			fmt.Fprintf(out, "⏳ %s\n", msg.Status)
+		case "result":
+			return grpcDispatchResult(&pb.DispatchTaskResponse{
+				TaskId: resp.TaskId,
+				Status: dispatchCompleted,
+				Result: msg.Result,
+				Error:  msg.Error,
+			}), nil
+		}
+	}
+}
 
-	if !dispatchWait {
-		fmt.Println("\nTask dispatched. Use 'klaw get tasks' to check status.")
-		return nil
+// printToolEvent prints a tool call or its result on one line.
+func printToolEvent(out *os.File, ev controller.TaskEvent) {
+	if ev.Type == controller.EventToolCall {
+		fmt.Fprintf(out, "🔧 %s %s\n", ev.Tool, truncateLine(ev.Input, 100))
+		return
+	}
+	icon := "↳"
+	if ev.IsError {
+		icon = "✗"
 	}
+	fmt.Fprintf(out, "   %s %s\n", icon, truncateLine(ev.Output, 100))
+}
 
-	switch resp.Status {
-	case "completed":
-		fmt.Println("\n✅ Task completed!")
-		fmt.Println()
-		fmt.Println("Result:")
-		fmt.Println("───────────────────────────────────────")
-		fmt.Println(resp.Result)
-		fmt.Println("───────────────────────────────────────")
-	case "timeout":
-		return fmt.Errorf("task timed out")
+// runDispatchStructured dispatches a task whose result must match the
+// --schema file, re-dispatching with a repair prompt while it doesn't.
+// Progress goes to stderr so stdout holds only the JSON result.
+func runDispatchStructured(agentName, prompt string, level priority.Level) (*dispatchResult, error) {
+	if !dispatchWait || !dispatchUseGRPC {
+		return nil, fmt.Errorf("--schema requires --wait and gRPC")
+	}
+	schema, err := os.ReadFile(dispatchSchema)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read schema: %w", err)
+	}
+	if !json.Valid(schema) {
+		return nil, fmt.Errorf("schema %s is not valid JSON", dispatchSchema)
 	}
 
-	return nil
+	ctx, cancel := context.WithTimeout(context.Background(), dispatchDeadline())
+	defer cancel()
+
+	conn, err := grpc.NewClient(dispatchController, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to controller: %w", err)
+	}
+	defer func() { _ = conn.Close() }()
+	client := pb.NewControllerServiceClient(conn)
+
+	if !dispatchQuiet {
+		fmt.Fprintf(os.Stderr, "📤 Dispatching task to agent: %s (schema: %s)\n", agentName, dispatchSchema)
+	}
+
+	task := prompt + agent.StructuredOutputPrompt(schema)
+	for attempt := 0; ; attempt++ {
+		resp, err := client.DispatchTask(ctx, &pb.DispatchTaskRequest{
+			Token:          dispatchToken,
+			AgentName:      agentName,
+			Prompt:         task,
+			Metadata:       dispatchMetadata(level),
+			Wait:           true,
+			TimeoutSeconds: int32(dispatchTimeout),
+		})
+		if err != nil {
+			if ctx.Err() == context.DeadlineExceeded {
+				return &dispatchResult{Status: dispatchTimedOut, Error: "task timed out"}, nil
+			}
+			return nil, fmt.Errorf("dispatch failed: %w", err)
+		}
+		res := grpcDispatchResult(resp)
+		if res.Status != dispatchCompleted {
+			return res, nil
+		}
+
+		result, verr := agent.ValidateOutput(schema, resp.Result)
+		if verr == nil {
+			res.Result = result
+			return res, nil
+		}
+		if attempt >= dispatchRetries {
+			res.Status = dispatchFailed
+			res.Error = fmt.Sprintf("result does not match schema after %d attempts: %v", attempt+1, verr)
+			return res, nil
+		}
+		if !dispatchQuiet {
+			fmt.Fprintf(os.Stderr, "⚠ Task %s: %v, asking for a repair\n", resp.TaskId, verr)
+		}
+
+		// Each task runs fresh, so the repair prompt carries the original task
+		task = prompt + agent.StructuredOutputPrompt(schema) + "\n\n" + agent.RepairPrompt(resp.Result, verr)
+	}
 }
 
-func runDispatchTCP(agentName, prompt string) error {
+func runDispatchTCP(agentName, prompt string, level priority.Level) (*dispatchResult, error) {
 	// Connect to controller
 	conn, err := net.DialTimeout("tcp", dispatchController, 10*time.Second)
 	if err != nil {
-		return fmt.Errorf("failed to connect to controller: %w", err)
+		return nil, fmt.Errorf("failed to connect to controller: %w", err)
 	}
 	defer func() { _ = conn.Close() }()
 
@@ -158,43 +530,47 @@ func runDispatchTCP(agentName, prompt string) error {
 
 	// Send dispatch request
 	err = encoder.Encode(&DispatchMessage{
-		Type:   "dispatch",
-		Token:  dispatchToken,
-		Agent:  agentName,
-		Prompt: prompt,
+		Type:     "dispatch",
+		Token:    dispatchToken,
+		Agent:    agentName,
+		Prompt:   prompt,
+		Priority: int(level),
 	})
 	if err != nil {
-		return fmt.Errorf("failed to send dispatch request: %w", err)
+		return nil, fmt.Errorf("failed to send dispatch request: %w", err)
 	}
 
 	// Read response
 	line, err := reader.ReadBytes('\n')
 	if err != nil {
-		return fmt.Errorf("failed to read response: %w", err)
+		return nil, fmt.Errorf("failed to read response: %w", err)
 	}
 
 	var resp DispatchMessage
 	if err := json.Unmarshal(line, &resp); err != nil {
-		return fmt.Errorf("failed to parse response: %w", err)
+		return nil, fmt.Errorf("failed to parse response: %w", err)
 	}
 
 	if resp.Type == "error" {
-		return fmt.Errorf("dispatch failed: %s", resp.Error)
+		return &dispatchResult{Status: dispatchFailed, Error: resp.Error}, nil
 	}
 
 	if resp.Type != "task_created" {
-		return fmt.Errorf("unexpected response: %s", resp.Type)
+		return nil, fmt.Errorf("unexpected response: %s", resp.Type)
 	}
 
-	fmt.Printf("✅ Task created: %s\n", resp.TaskID)
-
+	if dispatchVerbose() {
+		fmt.Printf("✅ Task created: %s\n", resp.TaskID)
+	}
+	res := &dispatchResult{TaskID: resp.TaskID, Status: dispatchDispatched}
 	if !dispatchWait {
-		fmt.Println("\nTask dispatched. Use 'klaw get tasks' to check status.")
-		return nil
+		return res, nil
 	}
 
 	// Wait for completion
-	fmt.Println("\n⏳ Waiting for completion...")
+	if dispatchVerbose() {
+		fmt.Println("\n⏳ Waiting for completion...")
+	}
 
 	// Set read deadline
 	_ = conn.SetReadDeadline(time.Now().Add(time.Duration(dispatchTimeout) * time.Second))
@@ -204,9 +580,10 @@ func runDispatchTCP(agentName, prompt string) error {
 		line, err := reader.ReadBytes('\n')
 		if err != nil {
 			if os.IsTimeout(err) {
-				return fmt.Errorf("timeout waiting for task completion")
+				res.Status, res.Error = dispatchTimedOut, "timeout waiting for task completion"
+				return res, nil
 			}
-			return fmt.Errorf("connection lost: %w", err)
+			return nil, fmt.Errorf("connection lost: %w", err)
 		}
 
 		var update DispatchMessage
@@ -216,19 +593,17 @@ func runDispatchTCP(agentName, prompt string) error {
 
 		switch update.Type {
 		case "task_completed":
-			fmt.Println("\n✅ Task completed!")
-			fmt.Println()
-			fmt.Println("Result:")
-			fmt.Println("───────────────────────────────────────")
-			fmt.Println(update.Result)
-			fmt.Println("───────────────────────────────────────")
-			return nil
+			res.Status, res.Result = dispatchCompleted, update.Result
+			return res, nil
 
 		case "task_failed":
-			return fmt.Errorf("task failed: %s", update.Error)
+			res.Status, res.Error = dispatchFailed, update.Error
+			return res, nil
 
 		case "task_progress":
-			fmt.Printf("   %s\n", update.Status)
+			if dispatchVerbose() {
+				fmt.Printf("   %s\n", update.Status)
+			}
 		}
 	}
 }
@@ -249,6 +624,22 @@ func runGetTasks(cmd *cobra.Command, args []string) error {
 	// Read tasks from controller store
 	dataDir := config.StateDir() + "/controller"
 
+	// Read tasks file
+	var tasks []*controller.Task
+	data, err := os.ReadFile(dataDir + "/tasks.json")
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	if err == nil {
+		if err := json.Unmarshal(data, &tasks); err != nil {
+			return err
+		}
+	}
+
+	if structuredOutput() {
+		return printObject(tasks)
+	}
+
 	// Check if controller data exists
 	if _, err := os.Stat(dataDir); os.IsNotExist(err) {
 		fmt.Println("No controller data found.")
@@ -256,64 +647,39 @@ func runGetTasks(cmd *cobra.Command, args []string) error {
 		return nil
 	}
 
-	// Read tasks file
-	tasksFile := dataDir + "/tasks.json"
-	data, err := os.ReadFile(tasksFile)
-	if err != nil {
-		if os.IsNotExist(err) {
-			fmt.Println("No tasks found.")
-			return nil
-		}
-		return err
-	}
-
-	var tasks []map[string]interface{}
-	if err := json.Unmarshal(data, &tasks); err != nil {
-		return err
-	}
-
 	if len(tasks) == 0 {
 		fmt.Println("No tasks found.")
 		return nil
 	}
 
-	if jsonOut {
-		return json.NewEncoder(os.Stdout).Encode(tasks)
-	}
-
 	fmt.Printf("Tasks (%d):\n\n", len(tasks))
-	fmt.Println("ID        AGENT           STATUS      CREATED")
-	fmt.Println("--        -----           ------      -------")
 
+	t := newTable("ID", "AGENT", "STATUS", "CREATED").withWide("TYPE", "PRIORITY", "NODE", "FINISHED", "PROMPT", "ERROR")
 	for _, task := range tasks {
-		id := task["id"]
-		agentName := task["agent_name"]
-		status := task["status"]
-		created := task["created_at"]
-
 		// Format status with icons
-		statusStr := fmt.Sprintf("%v", status)
-		switch statusStr {
+		status := task.Status
+		switch status {
 		case "completed":
-			statusStr = "✅ completed"
+			status = "✅ completed"
 		case "failed":
-			statusStr = "❌ failed"
+			status = "❌ failed"
 		case "dispatched":
-			statusStr = "🚀 dispatched"
+			status = "🚀 dispatched"
 		case "pending":
-			statusStr = "⏳ pending"
+			status = "⏳ pending"
+		case "queued":
+			status = "🕐 queued"
+		case "running":
+			status = "🔄 running"
 		}
 
-		// Parse and format time
-		createdStr := ""
-		if created != nil {
-			if ts, err := time.Parse(time.RFC3339, created.(string)); err == nil {
-				createdStr = ts.Format("15:04:05")
-			}
+		finished := ""
+		if task.FinishedAt != nil {
+			finished = task.FinishedAt.Format("15:04:05")
 		}
 
-		fmt.Printf("%-9v %-15v %-13s %s\n", id, agentName, statusStr, createdStr)
+		t.add(task.ID, task.AgentName, status, task.CreatedAt.Format("15:04:05"),
+			task.Type, priority.Level(task.Priority).String(), task.NodeID, finished, truncateCell(task.Prompt, 50), truncateCell(task.Error, 40))
 	}
-
-	return nil
+	return t.print()
 }
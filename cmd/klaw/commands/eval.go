@@ -0,0 +1,237 @@
+package commands
+
+import (
+	"cmp"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/eachlabs/klaw/internal/agent"
+	"github.com/eachlabs/klaw/internal/cluster"
+	"github.com/eachlabs/klaw/internal/config"
+	"github.com/eachlabs/klaw/internal/eval"
+	"github.com/eachlabs/klaw/internal/memory"
+	"github.com/eachlabs/klaw/internal/tool"
+	"github.com/spf13/cobra"
+)
+
+var (
+	evalAgent         string
+	evalProvider      string
+	evalModel         string
+	evalJudgeProvider string
+	evalJudgeModel    string
+	evalWorkDir       string
+)
+
+var evalCmd = &cobra.Command{
+	Use:   "eval <suite.yaml>...",
+	Short: "Run evaluation suites against an agent",
+	Long: `Run evaluation suites against an agent of the current namespace, so
+prompt and skill changes can be regression-tested.
+
+A suite is a YAML file of test cases. Each case is a prompt, the tools the
+agent may call, and assertions about its reply and the tools it called:
+
+  name: support
+  agent: support            # agent binding; omit for the default agent
+  model: claude-sonnet-4-20250514
+  cases:
+    - name: refund
+      prompt: Refund order 42, it arrived broken
+      allowed_tools: [order_lookup, refund_create]
+      expect:
+        - tool: refund_create
+        - contains: refund
+        - regex: 'order #?42'
+        - not_contains: sorry
+        - no_tool: bash
+        - judge: The reply confirms the refund and apologizes once
+
+contains and not_contains ignore case. judge assertions are scored by an
+LLM judge, by default the provider and model of the agent. Calls of tools
+outside allowed_tools are blocked and fail the case. The other tools run
+for real in --workdir.
+
+The command fails when a case fails.
+
+Examples:
+  klaw eval evals/support.yaml
+  klaw eval evals/*.yaml --model claude-opus-4-20250514
+  klaw eval evals/support.yaml --judge-model claude-opus-4-20250514 -o json`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: runEval,
+}
+
+func init() {
+	evalCmd.Flags().StringVar(&evalAgent, "agent", "", "Agent to evaluate (default: the suite's agent)")
+	evalCmd.Flags().StringVar(&evalProvider, "provider", "", "Provider to run the agent with (default: the suite's, or the agent's)")
+	evalCmd.Flags().StringVar(&evalModel, "model", "", "Model to run the agent with (default: the suite's, or the agent's)")
+	evalCmd.Flags().StringVar(&evalJudgeProvider, "judge-provider", "", "Provider of the judge (default: the agent's)")
+	evalCmd.Flags().StringVar(&evalJudgeModel, "judge-model", "", "Model of the judge (default: the agent's)")
+	evalCmd.Flags().StringVar(&evalWorkDir, "workdir", "", "Working directory of the tools (default: current directory)")
+
+	rootCmd.AddCommand(evalCmd)
+}
+
+func runEval(cmd *cobra.Command, args []string) error {
+	suites := make([]*eval.Suite, 0, len(args))
+	for _, path := range args {
+		s, err := eval.Load(path)
+		if err != nil {
+			return err
+		}
+		suites = append(suites, s)
+	}
+
+	clusterName, namespace, err := contextManager().RequireCurrent()
+	if err != nil {
+		return err
+	}
+	store := cluster.NewStore(config.StateDir())
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+	workDir := evalWorkDir
+	if workDir == "" {
+		if workDir, err = os.Getwd(); err != nil {
+			return err
+		}
+	}
+
+	providerName, model := defaultProviderModel(cfg, "", "")
+	prov, err := buildProvider(cfg, providerName, model)
+	if err != nil {
+		return err
+	}
+	providers := newProviderPool(cfg, providerName, model, prov)
+	tools := tool.DefaultRegistry(workDir)
+
+	// Failed cases are not usage errors
+	cmd.SilenceUsage = true
+	var reports []*eval.Report
+	failed, total := 0, 0
+	for _, s := range suites {
+		run, runProvider, err := evalAgentConfig(cmd, s, store, cfg, providers, tools, clusterName, namespace, workDir)
+		if err != nil {
+			return fmt.Errorf("%s: %w", s.Name, err)
+		}
+		evalCfg := eval.Config{Agent: run}
+		if s.NeedsJudge() {
+			// The judge runs on the agent's provider and model unless set
+			judgeName, judgeModel := cmp.Or(evalJudgeProvider, runProvider), evalJudgeModel
+			if judgeModel == "" && judgeName == runProvider {
+				judgeModel = run.Model
+			}
+			if judgeModel == "" {
+				_, judgeModel = defaultProviderModel(cfg, judgeName, "")
+			}
+			if evalCfg.Judge, err = providers.get(judgeName, judgeModel); err != nil {
+				return fmt.Errorf("judge: %w", err)
+			}
+			evalCfg.JudgeModel = judgeModel
+		}
+
+		if !structuredOutput() {
+			agentName := run.AgentName
+			if agentName == "" {
+				agentName = "default"
+			}
+			fmt.Printf("Suite %s (agent %s, model %s)\n", s.Name, agentName, run.Model)
+			evalCfg.OnCase = printEvalCase
+		}
+		report := eval.Run(cmd.Context(), s, evalCfg)
+		reports = append(reports, report)
+		failed += report.Failed
+		total += len(report.Cases)
+		if !structuredOutput() {
+			fmt.Printf("%d/%d cases passed, score %.0f%%, %s\n\n", report.Passed, len(report.Cases), report.Score*100, report.Duration.Round(time.Second))
+		}
+	}
+
+	if structuredOutput() {
+		if err := printObject(reports); err != nil {
+			return err
+		}
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d of %d eval cases failed", failed, total)
+	}
+	return nil
+}
+
+// evalAgentConfig returns how the cases of a suite run, and the name of
+// their provider: with the prompt, tools, skill config, provider and model
+// of its agent, unless overridden by the flags or the suite.
+func evalAgentConfig(cmd *cobra.Command, s *eval.Suite, store *cluster.Store, cfg *config.Config, providers *providerPool, tools *tool.Registry, clusterName, namespace, workDir string) (agent.RunOnceConfig, string, error) {
+	run := agent.RunOnceConfig{
+		Tools:         tools,
+		MaxTokens:     8192,
+		MaxIterations: cfg.Defaults.MaxIterations,
+		Secrets:       secretMasker(cfg),
+		Audit:         storeAudit{store: store, cluster: clusterName, namespace: namespace, source: "eval"},
+	}
+
+	var ab *cluster.AgentBinding
+	if name := cmp.Or(evalAgent, s.Agent); name != "" {
+		var err error
+		if ab, err = store.GetAgentBinding(clusterName, namespace, name); err != nil {
+			return run, "", err
+		}
+		if run.Tools, err = agentToolRegistry(cfg, tools, ab, workDir); err != nil {
+			return run, "", err
+		}
+		if run.SkillConfig, err = store.AgentSkillConfig(ab); err != nil {
+			return run, "", err
+		}
+		run.AgentName = ab.Name
+		run.SystemPrompt = agentPrompt(cmd.Context(), cfg.WorkspaceDir(), ab, "")
+	} else {
+		ws, err := memory.NewFileMemory(cfg.WorkspaceDir()).LoadWorkspace(cmd.Context())
+		if err != nil {
+			ws = &memory.Workspace{}
+		}
+		run.SystemPrompt = memory.BuildSystemPrompt(ws)
+	}
+
+	name, model := providers.resolve(ab)
+	if p := cmp.Or(evalProvider, s.Provider); p != "" {
+		name, model = defaultProviderModel(cfg, p, "")
+	}
+	if m := cmp.Or(evalModel, s.Model); m != "" {
+		model = m
+	}
+	prov, err := providers.get(name, model)
+	if err != nil {
+		return run, "", err
+	}
+	run.Provider, run.Model = prov, model
+	return run, name, nil
+}
+
+// printEvalCase shows the outcome of a case as it finishes.
+func printEvalCase(res *eval.CaseResult) {
+	mark := "✓"
+	if !res.Passed {
+		mark = "✗"
+	}
+	fmt.Printf("  %s %s (%s)\n", mark, res.Name, res.Duration.Round(100*time.Millisecond))
+	if res.Passed {
+		return
+	}
+	if res.Error != "" {
+		fmt.Printf("      error: %s\n", res.Error)
+	}
+	for _, c := range res.Checks {
+		if c.Passed {
+			continue
+		}
+		if c.Detail != "" {
+			fmt.Printf("      ✗ %s: %s\n", c.Assertion, c.Detail)
+		} else {
+			fmt.Printf("      ✗ %s\n", c.Assertion)
+		}
+	}
+	fmt.Printf("      reply: %s\n", truncateStr(res.Reply, 200))
+}
@@ -1,7 +1,6 @@
 package commands
 
 import (
-	"encoding/json"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -11,6 +10,7 @@ import (
 
 	"github.com/eachlabs/klaw/internal/cluster"
 	"github.com/eachlabs/klaw/internal/config"
+	"github.com/eachlabs/klaw/internal/history"
 	"github.com/eachlabs/klaw/internal/runtime"
 	"github.com/eachlabs/klaw/internal/session"
 	"github.com/spf13/cobra"
@@ -24,22 +24,35 @@ var getCmd = &cobra.Command{
 
 Resources:
   agents           Configured agents
+  jobs             Scheduled jobs
+  nodes            Nodes connected to the controller
+  tasks            Tasks dispatched by the controller
+  clusters, namespaces
   servers, srv     Running server instances
   sessions, sess   Chat sessions
+  conversations    Agent conversations (e.g. Slack threads)
   models           Available models
   channels, ch     Configured channels
   memory, mem      Memory files
   tools            Available tools
 
+Output:
+  -o json          JSON, for scripts
+  -o yaml          YAML
+  -o wide          Table with more columns and untruncated values
+
 Examples:
   klaw get agents
   klaw list agents
-  klaw ls agents`,
+  klaw ls agents
+  klaw get jobs -o wide
+  klaw get agents -o json | jq -r '.[].name'`,
 }
 
 func init() {
 	getCmd.AddCommand(getServersCmd)
 	getCmd.AddCommand(getSessionsCmd)
+	getCmd.AddCommand(getConversationsCmd)
 	getCmd.AddCommand(getModelsCmd)
 	getCmd.AddCommand(getChannelsCmd)
 	getCmd.AddCommand(getMemoryCmd)
@@ -63,8 +76,8 @@ var getServersCmd = &cobra.Command{
 			return nil
 		}
 
-		if jsonOut {
-			return json.NewEncoder(os.Stdout).Encode(containers)
+		if structuredOutput() {
+			return printObject(containers)
 		}
 
 		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
@@ -82,34 +95,46 @@ var getSessionsCmd = &cobra.Command{
 	Aliases: []string{"sess", "session"},
 	Short:   "List chat sessions",
 	RunE: func(cmd *cobra.Command, args []string) error {
-		mgr := session.NewManager()
-		sessions, err := mgr.List()
+		sessions, err := session.NewManager().List()
 		if err != nil {
 			return err
 		}
+		return printSessions(sessions)
+	},
+}
 
-		if len(sessions) == 0 {
-			fmt.Println("No sessions found.")
-			fmt.Println("Start a new session with: klaw chat")
-			return nil
+var getConversationsCmd = &cobra.Command{
+	Use:     "conversations",
+	Aliases: []string{"conv", "conversation"},
+	Short:   "List agent conversations",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg, err := config.Load()
+		if err != nil {
+			return fmt.Errorf("failed to load config: %w", err)
+		}
+		store, err := history.OpenFromConfig(cfg)
+		if err != nil {
+			return err
+		}
+		convs, err := store.List()
+		if err != nil {
+			return err
+		}
+
+		if structuredOutput() {
+			return printObject(convs)
 		}
 
-		if jsonOut {
-			enc := json.NewEncoder(os.Stdout)
-			enc.SetIndent("", "  ")
-			return enc.Encode(sessions)
+		if len(convs) == 0 {
+			fmt.Println("No conversations found.")
+			return nil
 		}
 
 		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
-		_, _ = fmt.Fprintln(w, "ID\tNAME\tMODEL\tMESSAGES\tUPDATED")
-		for _, s := range sessions {
-			name := s.Name
-			if name == "" {
-				name = "-"
-			}
-			model := truncateModel(s.Model, 25)
-			updated := s.UpdatedAt.Format("2006-01-02 15:04")
-			_, _ = fmt.Fprintf(w, "%s\t%s\t%s\t%d\t%s\n", s.ID, name, model, len(s.Messages), updated)
+		_, _ = fmt.Fprintln(w, "ID\tMESSAGES\tUPDATED\tLAST MESSAGE")
+		for _, c := range convs {
+			preview := strings.ReplaceAll(c.Preview, "\n", " ")
+			_, _ = fmt.Fprintf(w, "%s\t%d\t%s\t%s\n", c.ID, c.Messages, c.UpdatedAt.Format("2006-01-02 15:04"), truncateStr(preview, 60))
 		}
 		return w.Flush()
 	},
@@ -135,10 +160,8 @@ var getModelsCmd = &cobra.Command{
 			{ID: "claude-3-5-haiku-20241022", Provider: "anthropic", Description: "Fast, efficient"},
 		}
 
-		if jsonOut {
-			enc := json.NewEncoder(os.Stdout)
-			enc.SetIndent("", "  ")
-			return enc.Encode(models)
+		if structuredOutput() {
+			return printObject(models)
 		}
 
 		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
@@ -162,7 +185,7 @@ var getChannelsCmd = &cobra.Command{
 	Short:   "List channels in current namespace",
 	RunE: func(cmd *cobra.Command, args []string) error {
 		store := cluster.NewStore(config.StateDir())
-		ctxMgr := cluster.NewContextManager(config.ConfigDir())
+		ctxMgr := contextManager()
 
 		clusterName, namespace, err := ctxMgr.GetCurrent()
 		if err != nil {
@@ -192,10 +215,8 @@ var getChannelsCmd = &cobra.Command{
 				})
 			}
 
-			if jsonOut {
-				enc := json.NewEncoder(os.Stdout)
-				enc.SetIndent("", "  ")
-				return enc.Encode(channels)
+			if structuredOutput() {
+				return printObject(channels)
 			}
 
 			fmt.Println("No cluster selected. Showing legacy channels from config.")
@@ -216,25 +237,33 @@ var getChannelsCmd = &cobra.Command{
 			return err
 		}
 
+		if structuredOutput() {
+			return printObject(bindings)
+		}
+
 		if len(bindings) == 0 {
 			fmt.Printf("No channels in %s/%s.\n", clusterName, namespace)
 			fmt.Println("Create one with: klaw create channel <type> --name <name>")
 			return nil
 		}
 
-		if jsonOut {
-			return json.NewEncoder(os.Stdout).Encode(bindings)
-		}
-
 		fmt.Printf("Channels in %s/%s:\n\n", clusterName, namespace)
 
-		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
-		_, _ = fmt.Fprintln(w, "NAME\tTYPE\tSTATUS\tCREATED")
+		t := newTable("NAME", "TYPE", "STATUS", "HEALTH", "CREATED").withWide("ERROR", "TOKENS")
 		for _, ch := range bindings {
-			_, _ = fmt.Fprintf(w, "%s\t%s\t%s\t%s\n",
-				ch.Name, ch.Type, ch.Status, ch.CreatedAt.Format("2006-01-02 15:04"))
+			var tokens []string
+			for _, key := range []string{"bot_token", "app_token", "token", "secret"} {
+				if v := ch.Config[key]; v != "" {
+					tokens = append(tokens, key+"="+maskToken(v))
+				}
+			}
+			health, healthErr := "-", ""
+			if ch.Health != nil {
+				health, healthErr = ch.Health.State, ch.Health.Error
+			}
+			t.add(ch.Name, ch.Type, ch.Status, health, ch.CreatedAt.Format("2006-01-02 15:04"), truncateCell(healthErr, 60), strings.Join(tokens, " "))
 		}
-		return w.Flush()
+		return t.print()
 	},
 }
 
@@ -291,10 +320,8 @@ var getMemoryCmd = &cobra.Command{
 			return nil
 		}
 
-		if jsonOut {
-			enc := json.NewEncoder(os.Stdout)
-			enc.SetIndent("", "  ")
-			return enc.Encode(files)
+		if structuredOutput() {
+			return printObject(files)
 		}
 
 		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
@@ -314,33 +341,6 @@ type memoryInfo struct {
 var getToolsCmd = &cobra.Command{
 	Use:     "tools",
 	Aliases: []string{"tool"},
-	Short:   "List available tools",
-	RunE: func(cmd *cobra.Command, args []string) error {
-		tools := []toolInfo{
-			{Name: "bash", Description: "Execute shell commands"},
-			{Name: "read", Description: "Read file contents"},
-			{Name: "write", Description: "Write file contents"},
-			{Name: "edit", Description: "Edit files with string replacement"},
-			{Name: "glob", Description: "Find files by pattern"},
-			{Name: "grep", Description: "Search file contents"},
-		}
-
-		if jsonOut {
-			enc := json.NewEncoder(os.Stdout)
-			enc.SetIndent("", "  ")
-			return enc.Encode(tools)
-		}
-
-		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
-		_, _ = fmt.Fprintln(w, "TOOL\tDESCRIPTION")
-		for _, t := range tools {
-			_, _ = fmt.Fprintf(w, "%s\t%s\n", t.Name, t.Description)
-		}
-		return w.Flush()
-	},
-}
-
-type toolInfo struct {
-	Name        string `json:"name"`
-	Description string `json:"description"`
+	Short:   "List available tools (same as 'klaw tools list')",
+	RunE:    runToolsList,
 }
@@ -0,0 +1,312 @@
+package commands
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/eachlabs/klaw/internal/agent"
+	"github.com/eachlabs/klaw/internal/cluster"
+	"github.com/eachlabs/klaw/internal/config"
+	"github.com/eachlabs/klaw/internal/guardrail"
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	namespaceGuardrailsCmd.AddCommand(namespaceGuardrailsSetCmd)
+	namespaceGuardrailsCmd.AddCommand(namespaceGuardrailsShowCmd)
+	namespaceGuardrailsCmd.AddCommand(namespaceGuardrailsClearCmd)
+	namespaceGuardrailsCmd.AddCommand(namespaceGuardrailsCheckCmd)
+	namespaceCmd.AddCommand(namespaceGuardrailsCmd)
+}
+
+// --- klaw namespace guardrails ---
+
+var namespaceGuardrailsCmd = &cobra.Command{
+	Use:     "guardrails",
+	Aliases: []string{"guardrail"},
+	Short:   "Manage the namespace guardrails",
+	Long: `Set guardrails for the agents of a namespace. Tool calls are checked before
+they run and replies before they are sent:
+
+  --deny-command     regex matched against bash commands
+  --blocked-url      regex matched against URLs in tool arguments
+  --max-file-size    bytes the read, edit and write tools may handle
+  --forbidden-topic  case-insensitive regex matched against replies
+
+A blocked tool call returns an explanation to the agent instead of running.
+A reply that touches a forbidden topic is withheld. klaw start picks up
+changes without a restart.
+
+Examples:
+  klaw namespace guardrails set --deny-command 'rm\s+-rf\s+/' --deny-command '\bgit\s+push\s+--force'
+  klaw namespace guardrails set --blocked-url '^https?://([a-z0-9-]+\.)*internal\.example\.com'
+  klaw namespace guardrails set --max-file-size 1048576 --forbidden-topic 'salary|compensation'
+  klaw namespace guardrails check --command 'rm -rf /'
+  klaw namespace guardrails show
+  klaw namespace guardrails clear`,
+}
+
+var (
+	guardrailsNamespace       string
+	guardrailsDenyCommands    []string
+	guardrailsBlockedURLs     []string
+	guardrailsMaxFileSize     int64
+	guardrailsForbiddenTopics []string
+
+	guardrailsCheckCommand string
+	guardrailsCheckURL     string
+	guardrailsCheckText    string
+	guardrailsCheckFile    string
+)
+
+// guardrailsTarget resolves the cluster and namespace for the guardrails commands.
+func guardrailsTarget() (string, string, error) {
+	clusterName, namespace, err := contextManager().RequireCurrent()
+	if err != nil {
+		return "", "", err
+	}
+	if guardrailsNamespace != "" {
+		namespace = guardrailsNamespace
+	}
+	return clusterName, namespace, nil
+}
+
+var namespaceGuardrailsSetCmd = &cobra.Command{
+	Use:   "set",
+	Short: "Set guardrails for a namespace",
+	Long:  `Set guardrails for a namespace. Only the flags given are changed; a list flag replaces the whole list.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		store := cluster.NewStore(config.StateDir())
+		clusterName, namespace, err := guardrailsTarget()
+		if err != nil {
+			return err
+		}
+
+		p, err := store.NamespaceGuardrails(clusterName, namespace)
+		if err != nil {
+			return err
+		}
+		if p == nil {
+			p = &guardrail.Policy{}
+		}
+
+		flags := cmd.Flags()
+		if flags.Changed("deny-command") {
+			p.DenyCommands = guardrailsDenyCommands
+		}
+		if flags.Changed("blocked-url") {
+			p.BlockedURLs = guardrailsBlockedURLs
+		}
+		if flags.Changed("max-file-size") {
+			p.MaxFileSize = guardrailsMaxFileSize
+		}
+		if flags.Changed("forbidden-topic") {
+			p.ForbiddenTopics = guardrailsForbiddenTopics
+		}
+		if p.MaxFileSize < 0 {
+			return fmt.Errorf("--max-file-size must not be negative")
+		}
+		if _, err := guardrail.Compile(p); err != nil {
+			return err
+		}
+
+		if err := store.SetNamespaceGuardrails(clusterName, namespace, p); err != nil {
+			return err
+		}
+		fmt.Printf("Guardrails updated for namespace '%s'.\n", namespace)
+		return nil
+	},
+}
+
+var namespaceGuardrailsShowCmd = &cobra.Command{
+	Use:   "show",
+	Short: "Show the guardrails of a namespace",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		store := cluster.NewStore(config.StateDir())
+		clusterName, namespace, err := guardrailsTarget()
+		if err != nil {
+			return err
+		}
+
+		p, err := store.NamespaceGuardrails(clusterName, namespace)
+		if err != nil {
+			return err
+		}
+
+		if structuredOutput() {
+			return printObject(p)
+		}
+
+		if p == nil {
+			fmt.Printf("No guardrails for namespace '%s'.\n", namespace)
+			return nil
+		}
+		fmt.Printf("Deny commands:    %s\n", patternList(p.DenyCommands))
+		fmt.Printf("Blocked URLs:     %s\n", patternList(p.BlockedURLs))
+		if p.MaxFileSize > 0 {
+			fmt.Printf("Max file size:    %d bytes\n", p.MaxFileSize)
+		} else {
+			fmt.Printf("Max file size:    (no limit)\n")
+		}
+		fmt.Printf("Forbidden topics: %s\n", patternList(p.ForbiddenTopics))
+		return nil
+	},
+}
+
+// patternList shows a list of patterns.
+func patternList(patterns []string) string {
+	if len(patterns) == 0 {
+		return "(none)"
+	}
+	quoted := make([]string, len(patterns))
+	for i, p := range patterns {
+		quoted[i] = fmt.Sprintf("%#q", p)
+	}
+	return strings.Join(quoted, ", ")
+}
+
+var namespaceGuardrailsClearCmd = &cobra.Command{
+	Use:   "clear",
+	Short: "Remove the guardrails of a namespace",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		store := cluster.NewStore(config.StateDir())
+		clusterName, namespace, err := guardrailsTarget()
+		if err != nil {
+			return err
+		}
+
+		if err := store.SetNamespaceGuardrails(clusterName, namespace, nil); err != nil {
+			return err
+		}
+		fmt.Printf("Guardrails removed from namespace '%s'.\n", namespace)
+		return nil
+	},
+}
+
+var namespaceGuardrailsCheckCmd = &cobra.Command{
+	Use:   "check",
+	Short: "Explain whether the guardrails block a command, URL, file or text",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		store := cluster.NewStore(config.StateDir())
+		clusterName, namespace, err := guardrailsTarget()
+		if err != nil {
+			return err
+		}
+
+		p, err := store.NamespaceGuardrails(clusterName, namespace)
+		if err != nil {
+			return err
+		}
+		engine, err := guardrail.Compile(p)
+		if err != nil {
+			return err
+		}
+
+		var checks []guardrailCheck
+		if guardrailsCheckCommand != "" {
+			checks = append(checks, guardrailCheck{"command", guardrailsCheckCommand, engine.CheckTool("bash", toolInput("command", guardrailsCheckCommand), "")})
+		}
+		if guardrailsCheckURL != "" {
+			checks = append(checks, guardrailCheck{"url", guardrailsCheckURL, engine.CheckURL(guardrailsCheckURL)})
+		}
+		if guardrailsCheckFile != "" {
+			checks = append(checks, guardrailCheck{"file", guardrailsCheckFile, engine.CheckTool("read", toolInput("path", guardrailsCheckFile), "")})
+		}
+		if guardrailsCheckText != "" {
+			checks = append(checks, guardrailCheck{"text", guardrailsCheckText, engine.CheckText(guardrailsCheckText)})
+		}
+		if len(checks) == 0 {
+			return fmt.Errorf("nothing to check: use --command, --url, --file or --text")
+		}
+
+		if structuredOutput() {
+			return printObject(checks)
+		}
+		for _, c := range checks {
+			if c.Violation == nil {
+				fmt.Printf("✓ %s allowed: %s\n", c.Kind, c.Value)
+				continue
+			}
+			fmt.Printf("✗ %s %v\n", c.Kind, c.Violation)
+		}
+		return nil
+	},
+}
+
+// guardrailCheck is the outcome of klaw namespace guardrails check.
+type guardrailCheck struct {
+	Kind      string               `json:"kind"`
+	Value     string               `json:"value"`
+	Violation *guardrail.Violation `json:"violation,omitempty"`
+}
+
+// toolInput is the JSON input of a tool call with a single argument.
+func toolInput(key, value string) json.RawMessage {
+	input, _ := json.Marshal(map[string]string{key: value})
+	return input
+}
+
+func init() {
+	namespaceGuardrailsCmd.PersistentFlags().StringVarP(&guardrailsNamespace, "namespace", "n", "", "namespace (uses current if not set)")
+	namespaceGuardrailsSetCmd.Flags().StringArrayVar(&guardrailsDenyCommands, "deny-command", nil, "Regex of denied bash commands (repeatable)")
+	namespaceGuardrailsSetCmd.Flags().StringArrayVar(&guardrailsBlockedURLs, "blocked-url", nil, "Regex of blocked URLs (repeatable)")
+	namespaceGuardrailsSetCmd.Flags().Int64Var(&guardrailsMaxFileSize, "max-file-size", 0, "Largest file in bytes a tool may read or write (0 = no limit)")
+	namespaceGuardrailsSetCmd.Flags().StringArrayVar(&guardrailsForbiddenTopics, "forbidden-topic", nil, "Case-insensitive regex of topics replies must not touch (repeatable)")
+	namespaceGuardrailsCheckCmd.Flags().StringVar(&guardrailsCheckCommand, "command", "", "Bash command to check")
+	namespaceGuardrailsCheckCmd.Flags().StringVar(&guardrailsCheckURL, "url", "", "URL to check")
+	namespaceGuardrailsCheckCmd.Flags().StringVar(&guardrailsCheckFile, "file", "", "File to check against the size limit")
+	namespaceGuardrailsCheckCmd.Flags().StringVar(&guardrailsCheckText, "text", "", "Reply text to check")
+}
+
+// namespaceGuardrails compiles the guardrails of a namespace. Errors are
+// printed as warnings; the namespace then runs without guardrails.
+func namespaceGuardrails(store *cluster.Store, clusterName, namespace string) *guardrail.Engine {
+	p, err := store.NamespaceGuardrails(clusterName, namespace)
+	if err != nil {
+		fmt.Printf("Warning: guardrails of namespace %s: %v\n", namespace, err)
+		return nil
+	}
+	engine, err := guardrail.Compile(p)
+	if err != nil {
+		fmt.Printf("Warning: guardrails of namespace %s: %v\n", namespace, err)
+		return nil
+	}
+	return engine
+}
+
+// watchGuardrails reloads the guardrails of a namespace into ag, so changes
+// apply to a running agent.
+func watchGuardrails(ctx context.Context, ag *agent.Agent, store *cluster.Store, clusterName, namespace string, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			ag.SetGuardrails(namespaceGuardrails(store, clusterName, namespace))
+		}
+	}
+}
+
+// guardrailsLine summarizes the guardrails for the startup banner.
+func guardrailsLine(e *guardrail.Engine) string {
+	p := e.Policy()
+	var parts []string
+	if n := len(p.DenyCommands); n > 0 {
+		parts = append(parts, fmt.Sprintf("%d denied commands", n))
+	}
+	if n := len(p.BlockedURLs); n > 0 {
+		parts = append(parts, fmt.Sprintf("%d blocked URLs", n))
+	}
+	if p.MaxFileSize > 0 {
+		parts = append(parts, fmt.Sprintf("files up to %d bytes", p.MaxFileSize))
+	}
+	if n := len(p.ForbiddenTopics); n > 0 {
+		parts = append(parts, fmt.Sprintf("%d forbidden topics", n))
+	}
+	return strings.Join(parts, ", ")
+}
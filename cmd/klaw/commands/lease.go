@@ -0,0 +1,37 @@
+package commands
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/eachlabs/klaw/internal/config"
+	"github.com/eachlabs/klaw/internal/lease"
+)
+
+// instanceID identifies this process among the replicas of a deployment.
+func instanceID() string {
+	host, _ := os.Hostname()
+	return fmt.Sprintf("%s-%d", host, os.Getpid())
+}
+
+// openLeases opens the conversation lease store of the [lease] config, or
+// returns nil when it is not set.
+func openLeases(cfg *config.Config) (lease.Store, error) {
+	if cfg.Lease.URL == "" {
+		return nil, nil
+	}
+	store, err := lease.Open(cfg.Lease.URL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open lease store: %w", err)
+	}
+	return store, nil
+}
+
+// leaseTTL is how long a conversation lease lasts after its last activity.
+func leaseTTL(cfg *config.Config) time.Duration {
+	if cfg.Lease.TTL > 0 {
+		return time.Duration(cfg.Lease.TTL) * time.Second
+	}
+	return 2 * time.Minute
+}
@@ -0,0 +1,132 @@
+package commands
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/eachlabs/klaw/internal/cluster"
+	"github.com/eachlabs/klaw/internal/config"
+	"github.com/eachlabs/klaw/internal/locale"
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	namespaceLocaleCmd.AddCommand(namespaceLocaleSetCmd)
+	namespaceLocaleCmd.AddCommand(namespaceLocaleShowCmd)
+	namespaceLocaleCmd.AddCommand(namespaceLocaleClearCmd)
+	namespaceCmd.AddCommand(namespaceLocaleCmd)
+
+	namespaceLocaleCmd.PersistentFlags().StringVarP(&localeNamespace, "namespace", "n", "", "namespace (uses current if not set)")
+}
+
+// localeCatalog returns the built-in locale templates with the overrides
+// of the locales directory. Broken overrides are skipped with a warning.
+func localeCatalog() *locale.Catalog {
+	c, err := locale.New(filepath.Join(config.ConfigDir(), "locales"))
+	if err != nil {
+		fmt.Printf("Warning: locales: %v\n", err)
+		return nil
+	}
+	return c
+}
+
+// --- klaw namespace locale ---
+
+var namespaceLocaleCmd = &cobra.Command{
+	Use:   "locale",
+	Short: "Manage the language of a namespace",
+	Long: `Set the language klaw replies in for a namespace: its help, errors and
+status messages, and the instructions given to the main agent. Slack users
+whose Slack language klaw has templates for get replies in their own
+language; the namespace language is used for everyone else.
+
+Languages are built in for en and tr. Add a language, or change the texts
+of one, with a <lang>.toml file in ~/.klaw/locales; keys it leaves out are
+taken from the built-in templates.
+
+Examples:
+  klaw namespace locale set tr
+  klaw namespace locale show
+  klaw namespace locale clear`,
+}
+
+var localeNamespace string
+
+// localeTarget resolves the cluster and namespace for the locale commands.
+func localeTarget() (string, string, error) {
+	clusterName, namespace, err := contextManager().RequireCurrent()
+	if err != nil {
+		return "", "", err
+	}
+	if localeNamespace != "" {
+		namespace = localeNamespace
+	}
+	return clusterName, namespace, nil
+}
+
+var namespaceLocaleSetCmd = &cobra.Command{
+	Use:   "set <lang>",
+	Short: "Set the language of a namespace",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		store := cluster.NewStore(config.StateDir())
+		clusterName, namespace, err := localeTarget()
+		if err != nil {
+			return err
+		}
+
+		catalog := localeCatalog()
+		lang := locale.Normalize(args[0])
+		if !catalog.Has(lang) {
+			return fmt.Errorf("no templates for language %q (available: %s)", args[0], strings.Join(catalog.Languages(), ", "))
+		}
+		if err := store.SetNamespaceLocale(clusterName, namespace, lang); err != nil {
+			return err
+		}
+		fmt.Printf("Namespace '%s' replies in %s. Restart klaw start to apply.\n", namespace, lang)
+		return nil
+	},
+}
+
+var namespaceLocaleShowCmd = &cobra.Command{
+	Use:   "show",
+	Short: "Show the language of a namespace",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		store := cluster.NewStore(config.StateDir())
+		clusterName, namespace, err := localeTarget()
+		if err != nil {
+			return err
+		}
+
+		lang := store.NamespaceLocale(clusterName, namespace)
+		languages := localeCatalog().Languages()
+		if structuredOutput() {
+			return printObject(map[string]any{"namespace": namespace, "locale": lang, "available": languages})
+		}
+		if lang == "" {
+			lang = locale.Default + " (default)"
+		}
+		fmt.Printf("Locale:      %s\n", lang)
+		fmt.Printf("Available:   %s\n", strings.Join(languages, ", "))
+		return nil
+	},
+}
+
+var namespaceLocaleClearCmd = &cobra.Command{
+	Use:   "clear",
+	Short: "Reset the language of a namespace to the default",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		store := cluster.NewStore(config.StateDir())
+		clusterName, namespace, err := localeTarget()
+		if err != nil {
+			return err
+		}
+
+		if err := store.SetNamespaceLocale(clusterName, namespace, ""); err != nil {
+			return err
+		}
+		fmt.Printf("Namespace '%s' replies in %s.\n", namespace, locale.Default)
+		return nil
+	},
+}
@@ -0,0 +1,339 @@
+package commands
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"net"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"sort"
+	"strconv"
+	"syscall"
+	"time"
+
+	"github.com/eachlabs/klaw/internal/analytics"
+	"github.com/eachlabs/klaw/internal/cluster"
+	"github.com/eachlabs/klaw/internal/config"
+	"github.com/eachlabs/klaw/internal/observe"
+	"github.com/spf13/cobra"
+)
+
+var (
+	logsFollow     bool
+	logsTail       int
+	logsRedactions bool
+)
+
+var logsCmd = &cobra.Command{
+	Use:   "logs [agent]",
+	Short: "Show an agent's activity",
+	Long: `Show what agents are doing: messages in, responses out, tool calls
+and their results, and errors.
+
+The activity is read from the klaw start process of the current namespace.
+With -f the command keeps streaming new activity until interrupted. When
+klaw start is not running, the namespace's message logs are shown instead.
+
+Examples:
+  klaw logs                    # recent activity of all agents
+  klaw logs coder -f           # follow the coder agent
+  klaw logs coder --tail 100   # last 100 events
+  klaw logs -f -o json         # events as JSON lines
+  klaw logs --redactions       # what [redaction] masked in the message logs
+  klaw logs stats --since 7d   # what users ask, by intent and sentiment`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runLogs,
+}
+
+func init() {
+	logsCmd.Flags().BoolVarP(&logsFollow, "follow", "f", false, "Keep streaming new activity")
+	logsCmd.Flags().IntVar(&logsTail, "tail", 20, "Number of recent events to show first")
+	logsCmd.Flags().BoolVar(&logsRedactions, "redactions", false, "Count the redactions in the message logs, by detector")
+
+	logsStatsCmd.Flags().StringVar(&logsStatsSince, "since", "30d", "How far back to look: a duration such as 24h or 7d, or a date")
+	logsStatsCmd.Flags().StringVar(&logsStatsAgent, "agent", "", "Only messages handled by this agent")
+	logsCmd.AddCommand(logsStatsCmd)
+
+	rootCmd.AddCommand(logsCmd)
+}
+
+var (
+	logsStatsSince string
+	logsStatsAgent string
+)
+
+var logsStatsCmd = &cobra.Command{
+	Use:   "stats",
+	Short: "Show what users ask, by intent and sentiment",
+	Long: `Count the messages of the namespace's message logs by intent and
+sentiment. Messages are tagged in the background of klaw start when
+[analytics] is enabled in config.toml:
+
+  [analytics]
+  enabled = true
+  model = "claude-3-5-haiku-20241022"
+  intents = ["question", "task", "bug_report", "feedback", "other"]
+
+Messages logged before tagging was enabled, or not tagged yet, are
+counted as untagged.
+
+Examples:
+  klaw logs stats
+  klaw logs stats --since 7d --agent support
+  klaw logs stats -o json`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		clusterName, namespace, err := contextManager().RequireCurrent()
+		if err != nil {
+			return err
+		}
+		since, err := parseSince(logsStatsSince, time.Now())
+		if err != nil {
+			return err
+		}
+
+		logs, err := cluster.NewStore(config.StateDir()).ListMessageLogs(clusterName, namespace, since)
+		if err != nil {
+			return err
+		}
+		if logsStatsAgent != "" {
+			logs = slices.DeleteFunc(logs, func(l *cluster.MessageLog) bool { return l.Agent != logsStatsAgent })
+		}
+		stats := analytics.Summarize(logs)
+
+		if structuredOutput() {
+			return printObject(stats)
+		}
+		if stats.Tagged == 0 {
+			fmt.Printf("No tagged messages in %s/%s since %s (%d untagged).\n", clusterName, namespace, since.Format("2006-01-02 15:04"), stats.Messages)
+			fmt.Println("Enable [analytics] in config.toml to tag messages while klaw start runs.")
+			return nil
+		}
+
+		fmt.Printf("%d of %d messages tagged since %s\n\n", stats.Tagged, stats.Messages, since.Format("2006-01-02 15:04"))
+		t := newTable("INTENT", "MESSAGES", "SHARE", "POSITIVE", "NEUTRAL", "NEGATIVE")
+		for _, s := range stats.Intents {
+			t.add(
+				s.Intent,
+				strconv.Itoa(s.Messages),
+				fmt.Sprintf("%.0f%%", float64(s.Messages)/float64(stats.Tagged)*100),
+				strconv.Itoa(s.Positive),
+				strconv.Itoa(s.Neutral),
+				strconv.Itoa(s.Negative),
+			)
+		}
+		return t.print()
+	},
+}
+
+// eventSocketPath returns the event socket of the klaw start process
+// serving a namespace.
+func eventSocketPath(clusterName, namespace string) string {
+	return filepath.Join(config.StateDir(), "run", clusterName+"-"+namespace+".sock")
+}
+
+func runLogs(cmd *cobra.Command, args []string) error {
+	if _, err := outputFormat(); err != nil {
+		return err
+	}
+	clusterName, namespace, err := contextManager().RequireCurrent()
+	if err != nil {
+		return err
+	}
+	f := observe.EventFilter{Tail: logsTail, Follow: logsFollow}
+	if len(args) > 0 {
+		f.Agent = args[0]
+	}
+	if logsRedactions {
+		return printRedactions(clusterName, namespace, f.Agent)
+	}
+
+	ctx, stop := signal.NotifyContext(cmd.Context(), stopSignals...)
+	defer stop()
+
+	var printErr error
+	err = observe.StreamEvents(ctx, eventSocketPath(clusterName, namespace), f, func(ev observe.Event) {
+		if printErr == nil {
+			printErr = printEvent(ev)
+		}
+	})
+	if err != nil && isNotRunning(err) {
+		if logsFollow {
+			return fmt.Errorf("klaw start is not running for %s/%s; start it to follow agent activity", clusterName, namespace)
+		}
+		return printStoredLogs(clusterName, namespace, f)
+	}
+	if err != nil {
+		return err
+	}
+	return printErr
+}
+
+// isNotRunning reports whether dialing the event socket failed because no
+// process is listening on it. Windows reports a missing socket with errors
+// of its own, so any failure to dial counts there.
+func isNotRunning(err error) bool {
+	var opErr *net.OpError
+	if runtime.GOOS == "windows" && errors.As(err, &opErr) && opErr.Op == "dial" {
+		return true
+	}
+	return errors.Is(err, os.ErrNotExist) || errors.Is(err, syscall.ECONNREFUSED)
+}
+
+// printEvent prints an event as one line, as a JSON line with -o json, or
+// as a YAML document with -o yaml.
+func printEvent(ev observe.Event) error {
+	if structuredOutput() {
+		if format, _ := outputFormat(); format == outputYAML {
+			fmt.Println("---")
+			return printObject(ev)
+		}
+		return json.NewEncoder(os.Stdout).Encode(ev)
+	}
+
+	var detail string
+	switch ev.Kind {
+	case observe.EventMessage:
+		detail = "← " + ev.Content
+	case observe.EventResponse:
+		detail = "→ " + ev.Content
+	case observe.EventToolCall:
+		detail = fmt.Sprintf("⚙ %s %s", ev.Tool, ev.Content)
+	case observe.EventToolResult:
+		status := "ok"
+		if ev.IsError {
+			status = "error"
+		}
+		detail = fmt.Sprintf("⚙ %s %s: %s", ev.Tool, status, ev.Content)
+	case observe.EventError:
+		detail = "✗ " + ev.Content
+	default:
+		detail = ev.Content
+	}
+	_, err := fmt.Printf("%s  %-12s %s\n", ev.Time.Local().Format("15:04:05"), ev.Agent, truncateCell(detail, 160))
+	return err
+}
+
+// logChannels returns the channels with message logs in a namespace: its
+// channel bindings and the REST API.
+func logChannels(store *cluster.Store, clusterName, namespace string) ([]string, error) {
+	channels := []string{"api"}
+	bindings, err := store.ListChannelBindings(clusterName, namespace)
+	if err != nil {
+		return nil, err
+	}
+	for _, cb := range bindings {
+		channels = append(channels, cb.Name)
+	}
+	return channels, nil
+}
+
+// printRedactions prints how many matches of each redaction detector were
+// masked in the namespace's message logs.
+func printRedactions(clusterName, namespace, agentName string) error {
+	store := cluster.NewStore(config.StateDir())
+	channels, err := logChannels(store, clusterName, namespace)
+	if err != nil {
+		return err
+	}
+
+	type redactionCount struct {
+		Detector   string `json:"detector"`
+		Redactions int    `json:"redactions"`
+		Messages   int    `json:"messages"`
+	}
+	counts := make(map[string]*redactionCount)
+	for _, ch := range channels {
+		logs, err := store.GetMessageLogs(clusterName, namespace, ch, math.MaxInt)
+		if err != nil {
+			return err
+		}
+		for _, l := range logs {
+			if agentName != "" && agentName != l.Agent {
+				continue
+			}
+			for detector, n := range l.Redactions {
+				c, ok := counts[detector]
+				if !ok {
+					c = &redactionCount{Detector: detector}
+					counts[detector] = c
+				}
+				c.Redactions += n
+				c.Messages++
+			}
+		}
+	}
+
+	rows := make([]*redactionCount, 0, len(counts))
+	for _, c := range counts {
+		rows = append(rows, c)
+	}
+	sort.Slice(rows, func(i, j int) bool { return rows[i].Detector < rows[j].Detector })
+
+	if structuredOutput() {
+		return printObject(rows)
+	}
+	if len(rows) == 0 {
+		fmt.Println("No redactions recorded.")
+		return nil
+	}
+	t := newTable("DETECTOR", "REDACTIONS", "MESSAGES")
+	for _, c := range rows {
+		t.add(c.Detector, strconv.Itoa(c.Redactions), strconv.Itoa(c.Messages))
+	}
+	return t.print()
+}
+
+// printStoredLogs prints the latest messages of the namespace's channels
+// and the REST API as message and response events.
+func printStoredLogs(clusterName, namespace string, f observe.EventFilter) error {
+	store := cluster.NewStore(config.StateDir())
+	channels, err := logChannels(store, clusterName, namespace)
+	if err != nil {
+		return err
+	}
+
+	var logs []*cluster.MessageLog
+	for _, ch := range channels {
+		chLogs, err := store.GetMessageLogs(clusterName, namespace, ch, f.Tail)
+		if err != nil {
+			return err
+		}
+		for _, l := range chLogs {
+			if f.Agent == "" || f.Agent == l.Agent {
+				logs = append(logs, l)
+			}
+		}
+	}
+	sort.SliceStable(logs, func(i, j int) bool { return logs[i].Timestamp.Before(logs[j].Timestamp) })
+
+	var events []observe.Event
+	for _, l := range logs {
+		events = append(events, observe.Event{Time: l.Timestamp, Agent: l.Agent, Kind: observe.EventMessage, Content: l.Content})
+		if l.Response != "" {
+			events = append(events, observe.Event{Time: l.Timestamp, Agent: l.Agent, Kind: observe.EventResponse, Content: l.Response})
+		}
+	}
+	if len(events) > f.Tail {
+		events = events[len(events)-f.Tail:]
+	}
+
+	if !structuredOutput() {
+		if len(events) == 0 {
+			fmt.Println("No activity recorded.")
+			return nil
+		}
+		fmt.Fprintf(os.Stderr, "klaw start is not running for %s/%s; showing message logs\n", clusterName, namespace)
+	}
+	for _, ev := range events {
+		if err := printEvent(ev); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,479 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/eachlabs/klaw/internal/cluster"
+	"github.com/eachlabs/klaw/internal/config"
+	"github.com/eachlabs/klaw/internal/memory"
+	"github.com/spf13/cobra"
+)
+
+var (
+	memoryAgentName  string
+	memoryPromptOnly bool
+	memoryLimit      int
+	memoryTags       []string
+	memoryPin        bool
+	memoryShared     bool
+	memoryID         string
+	memoryOlderThan  string
+	memoryAll        bool
+	memoryYes        bool
+)
+
+var memoryCmd = &cobra.Command{
+	Use:   "memory",
+	Short: "Inspect and curate what agents remember",
+	Long: `Inspect and curate the memory of the main agent or, with --agent, of
+an agent binding in the current namespace: saved facts, daily conversation
+summaries, semantic memory and the pinned notes in MEMORY.md.
+
+Examples:
+  klaw memory show --agent support
+  klaw memory show --agent support --prompt
+  klaw memory search "pricing page" --agent support
+  klaw memory add "Refunds need a ticket number" --agent support --tag refunds
+  klaw memory add "The company is called Acme" --shared
+  klaw memory clear --agent support --older-than 90d
+  klaw memory clear --agent support --id 1a2b3c4d`,
+}
+
+var memoryShowCmd = &cobra.Command{
+	Use:   "show",
+	Short: "Show an agent's memory and its system prompt contribution",
+	Args:  cobra.NoArgs,
+	RunE:  runMemoryShow,
+}
+
+var memorySearchCmd = &cobra.Command{
+	Use:   "search <query>",
+	Short: "Search an agent's facts, summaries and semantic memory",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runMemorySearch,
+}
+
+var memoryAddCmd = &cobra.Command{
+	Use:   "add <text>",
+	Short: "Save a fact, or pin a note to MEMORY.md",
+	Long: `Save a fact the agent recalls with memory_recall, or with --pin add a
+note to the agent's MEMORY.md, which is part of its system prompt. --shared
+pins the note to the namespace's shared memory instead.`,
+	Args: cobra.ExactArgs(1),
+	RunE: runMemoryAdd,
+}
+
+var memoryClearCmd = &cobra.Command{
+	Use:   "clear",
+	Short: "Remove facts, summaries and semantic memories",
+	Long: `Remove one fact (--id), everything older than a given age
+(--older-than 30d), or everything (--all) the agent remembers. Pinned notes
+in MEMORY.md are kept; edit the file to change them.`,
+	Args: cobra.NoArgs,
+	RunE: runMemoryClear,
+}
+
+func init() {
+	for _, c := range []*cobra.Command{memoryShowCmd, memorySearchCmd, memoryAddCmd, memoryClearCmd} {
+		c.Flags().StringVarP(&memoryAgentName, "agent", "a", "", "Agent binding (default: the main agent)")
+	}
+	memoryShowCmd.Flags().BoolVar(&memoryPromptOnly, "prompt", false, "Only print the rendered system prompt contribution")
+	memorySearchCmd.Flags().IntVar(&memoryLimit, "limit", 10, "Maximum results per source")
+	memoryAddCmd.Flags().StringArrayVarP(&memoryTags, "tag", "t", nil, "Tag the fact (repeatable)")
+	memoryAddCmd.Flags().BoolVar(&memoryPin, "pin", false, "Add to MEMORY.md instead of saving a fact")
+	memoryAddCmd.Flags().BoolVar(&memoryShared, "shared", false, "Pin to the namespace's shared memory")
+	memoryClearCmd.Flags().StringVar(&memoryID, "id", "", "Remove the fact with this ID")
+	memoryClearCmd.Flags().StringVar(&memoryOlderThan, "older-than", "", "Remove what is older than this age (e.g. 30d, 12h)")
+	memoryClearCmd.Flags().BoolVar(&memoryAll, "all", false, "Remove everything the agent remembers")
+	memoryClearCmd.Flags().BoolVarP(&memoryYes, "yes", "y", false, "Don't ask for confirmation")
+
+	memoryCmd.AddCommand(memoryShowCmd)
+	memoryCmd.AddCommand(memorySearchCmd)
+	memoryCmd.AddCommand(memoryAddCmd)
+	memoryCmd.AddCommand(memoryClearCmd)
+	rootCmd.AddCommand(memoryCmd)
+}
+
+// memoryScope is the agent whose memory a command works on.
+type memoryScope struct {
+	cfg       *config.Config
+	agent     string                // owner of facts and summaries
+	binding   *cluster.AgentBinding // nil for the main agent
+	cluster   string                // empty without a current context
+	namespace string
+	dir       string // directory of the agent's MEMORY.md
+}
+
+// resolveMemoryScope returns the scope selected by --agent.
+func resolveMemoryScope() (*memoryScope, error) {
+	cfg, err := config.Load()
+	if err != nil {
+		return nil, err
+	}
+	clusterName, namespace, ctxErr := contextManager().RequireCurrent()
+
+	if memoryAgentName == "" || memoryAgentName == memory.DefaultAgent {
+		s := &memoryScope{cfg: cfg, agent: memory.DefaultAgent, dir: cfg.WorkspaceDir()}
+		if ctxErr == nil {
+			s.cluster, s.namespace = clusterName, namespace
+		}
+		return s, nil
+	}
+	if ctxErr != nil {
+		return nil, ctxErr
+	}
+	ab, err := cluster.NewStore(config.StateDir()).GetAgentBinding(clusterName, namespace, memoryAgentName)
+	if err != nil {
+		return nil, err
+	}
+	return &memoryScope{
+		cfg:       cfg,
+		agent:     ab.Name,
+		binding:   ab,
+		cluster:   clusterName,
+		namespace: namespace,
+		dir:       memory.AgentDir(cfg.WorkspaceDir(), ab.Name),
+	}, nil
+}
+
+// prompt renders the memory section of the agent's system prompt.
+func (s *memoryScope) prompt(ctx context.Context) string {
+	if s.binding != nil {
+		return memory.AgentMemoryPrompt(ctx, s.cfg.WorkspaceDir(), s.cluster, s.namespace, s.agent, s.binding.SharedMemory)
+	}
+	return memory.MainMemoryPrompt(ctx, s.cfg.WorkspaceDir(), s.cluster, s.namespace)
+}
+
+// sharesNamespaceMemory reports whether the agent sees the shared memory.
+func (s *memoryScope) sharesNamespaceMemory() bool {
+	if s.binding != nil {
+		return s.binding.SharedMemory
+	}
+	return s.cluster != ""
+}
+
+// stores opens the agent's facts and episodic memory, indexed in semantic
+// memory when a [memory] backend is configured. close releases them.
+func (s *memoryScope) stores() (facts *memory.FactStore, episodic *memory.EpisodicMemory, semantic *memory.SemanticMemory, close func()) {
+	ws := s.cfg.WorkspaceDir()
+	facts = memory.NewFactStore(ws)
+	facts.SetLimits(memory.LimitsFromConfig(s.cfg))
+	episodic = memory.NewEpisodicMemory(ws, memory.NewJournal(ws), nil)
+	semantic, err := memory.OpenFromConfig(s.cfg)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: semantic memory: %v\n", err)
+		semantic = nil
+	}
+	if semantic == nil {
+		return facts, episodic, nil, func() {}
+	}
+	facts.SetIndex(semantic)
+	episodic.SetIndex(semantic)
+	return facts, episodic, semantic, func() { _ = semantic.Close() }
+}
+
+type memoryShowOutput struct {
+	Agent      string        `json:"agent"`
+	MemoryFile string        `json:"memory_file"`
+	Shared     bool          `json:"shared"`
+	Facts      []memory.Fact `json:"facts"`
+	Summaries  []string      `json:"summaries"`
+	Semantic   string        `json:"semantic,omitempty"`
+	Prompt     string        `json:"prompt"`
+}
+
+func runMemoryShow(cmd *cobra.Command, args []string) error {
+	scope, err := resolveMemoryScope()
+	if err != nil {
+		return err
+	}
+	prompt := strings.TrimSpace(scope.prompt(cmd.Context()))
+	if memoryPromptOnly {
+		if prompt == "" {
+			fmt.Println("(no memory in the system prompt)")
+			return nil
+		}
+		fmt.Println(prompt)
+		return nil
+	}
+
+	facts, episodic, _, closeStores := scope.stores()
+	defer closeStores()
+	factList, err := facts.List(scope.agent)
+	if err != nil {
+		return err
+	}
+	summaries, err := episodic.Summaries(scope.agent, time.Time{})
+	if err != nil {
+		return err
+	}
+
+	out := memoryShowOutput{
+		Agent:      scope.agent,
+		MemoryFile: filepath.Join(scope.dir, "MEMORY.md"),
+		Shared:     scope.sharesNamespaceMemory(),
+		Facts:      factList,
+		Summaries:  []string{},
+		Semantic:   scope.cfg.Memory.Backend,
+		Prompt:     prompt,
+	}
+	if out.Facts == nil {
+		out.Facts = []memory.Fact{}
+	}
+	for _, s := range summaries {
+		out.Summaries = append(out.Summaries, s.Date.Format("2006-01-02"))
+	}
+	if structuredOutput() {
+		return printObject(out)
+	}
+
+	fmt.Printf("Agent:      %s\n", out.Agent)
+	fmt.Printf("Memory:     %s (%s)\n", out.MemoryFile, describeMemoryFile(out.MemoryFile))
+	if out.Shared {
+		fmt.Printf("Shared:     %s\n", memory.NamespaceDir(scope.cfg.WorkspaceDir(), scope.cluster, scope.namespace))
+	} else {
+		fmt.Println("Shared:     no")
+	}
+	fmt.Printf("Semantic:   %s\n", valueOrNone(out.Semantic))
+	if len(out.Summaries) > 0 {
+		fmt.Printf("Summaries:  %d days (latest %s)\n", len(out.Summaries), out.Summaries[0])
+	} else {
+		fmt.Println("Summaries:  (none)")
+	}
+	fmt.Printf("Facts:      %d\n", len(factList))
+	if len(factList) > 0 {
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		_, _ = fmt.Fprintln(w, "  ID\tSAVED\tFACT")
+		for _, f := range factList {
+			text := f.Content
+			if len(f.Tags) > 0 {
+				text += " [" + strings.Join(f.Tags, ", ") + "]"
+			}
+			_, _ = fmt.Fprintf(w, "  %s\t%s\t%s\n", f.ID, f.CreatedAt.Format("2006-01-02"), text)
+		}
+		_ = w.Flush()
+	}
+
+	fmt.Println("")
+	fmt.Println("System prompt contribution:")
+	if prompt == "" {
+		fmt.Println("  (none)")
+	} else {
+		fmt.Println(prompt)
+	}
+	return nil
+}
+
+// describeMemoryFile summarizes a MEMORY.md file.
+func describeMemoryFile(path string) string {
+	data, err := os.ReadFile(path)
+	if err != nil || len(strings.TrimSpace(string(data))) == 0 {
+		return "empty"
+	}
+	lines := strings.Count(strings.TrimRight(string(data), "\n"), "\n") + 1
+	return fmt.Sprintf("%d lines", lines)
+}
+
+func runMemorySearch(cmd *cobra.Command, args []string) error {
+	scope, err := resolveMemoryScope()
+	if err != nil {
+		return err
+	}
+	query := args[0]
+	facts, episodic, semantic, closeStores := scope.stores()
+	defer closeStores()
+
+	found := false
+	factList, err := facts.Search(scope.agent, query, memoryLimit)
+	if err != nil {
+		return err
+	}
+	if len(factList) > 0 {
+		found = true
+		fmt.Println("Facts:")
+		for _, f := range factList {
+			fmt.Printf("  [%s] %s (%s)\n", f.ID, f.Content, f.CreatedAt.Format("2006-01-02"))
+		}
+	}
+
+	summaries, err := episodic.Summaries(scope.agent, time.Time{})
+	if err != nil {
+		return err
+	}
+	words := strings.Fields(strings.ToLower(query))
+	var summaryLines []string
+	for _, s := range summaries {
+		for _, line := range strings.Split(s.Content, "\n") {
+			lower := strings.ToLower(line)
+			for _, w := range words {
+				if strings.Contains(lower, w) {
+					summaryLines = append(summaryLines, fmt.Sprintf("  %s: %s", s.Date.Format("2006-01-02"), strings.TrimSpace(line)))
+					break
+				}
+			}
+		}
+	}
+	if len(summaryLines) > 0 {
+		found = true
+		if len(summaryLines) > memoryLimit {
+			summaryLines = summaryLines[:memoryLimit]
+		}
+		fmt.Println("Summaries:")
+		fmt.Println(strings.Join(summaryLines, "\n"))
+	}
+
+	if semantic != nil {
+		matches, err := semantic.Recall(cmd.Context(), scope.agent, query, memoryLimit, 0)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: semantic search: %v\n", err)
+		} else if len(matches) > 0 {
+			found = true
+			fmt.Println("Semantic matches:")
+			for _, m := range matches {
+				fmt.Printf("  %.2f  (%s, %s) %s\n", m.Score, m.CreatedAt.Format("2006-01-02"), m.Kind, truncateLine(m.Content, 120))
+			}
+		}
+	}
+
+	if !found {
+		fmt.Printf("Nothing in %s's memory matches %q\n", scope.agent, query)
+	}
+	return nil
+}
+
+// truncateLine puts s on one line of at most max characters.
+func truncateLine(s string, max int) string {
+	s = strings.Join(strings.Fields(s), " ")
+	if r := []rune(s); len(r) > max {
+		return string(r[:max]) + "..."
+	}
+	return s
+}
+
+func runMemoryAdd(cmd *cobra.Command, args []string) error {
+	scope, err := resolveMemoryScope()
+	if err != nil {
+		return err
+	}
+
+	if memoryPin || memoryShared {
+		dir := scope.dir
+		if memoryShared {
+			if scope.cluster == "" {
+				return fmt.Errorf("no current namespace: run 'klaw config use-cluster' first")
+			}
+			dir = memory.NamespaceDir(scope.cfg.WorkspaceDir(), scope.cluster, scope.namespace)
+		}
+		if err := memory.AppendNote(dir, args[0]); err != nil {
+			return err
+		}
+		fmt.Printf("✅ Pinned to %s\n", filepath.Join(dir, "MEMORY.md"))
+		if memoryShared && scope.binding != nil && !scope.binding.SharedMemory {
+			fmt.Printf("Note: %s doesn't see shared memory; run 'klaw agent memory share %s'\n", scope.agent, scope.agent)
+		}
+		return nil
+	}
+
+	facts, _, _, closeStores := scope.stores()
+	defer closeStores()
+	fact, err := facts.Add(scope.agent, args[0], memoryTags)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("✅ Saved fact [%s] for %s: %s\n", fact.ID, scope.agent, fact.Content)
+	return nil
+}
+
+func runMemoryClear(cmd *cobra.Command, args []string) error {
+	set := 0
+	for _, given := range []bool{memoryID != "", memoryOlderThan != "", memoryAll} {
+		if given {
+			set++
+		}
+	}
+	if set != 1 {
+		return fmt.Errorf("specify one of --id, --older-than or --all")
+	}
+
+	scope, err := resolveMemoryScope()
+	if err != nil {
+		return err
+	}
+	facts, episodic, semantic, closeStores := scope.stores()
+	defer closeStores()
+
+	if memoryID != "" {
+		if err := facts.Delete(scope.agent, memoryID); err != nil {
+			return err
+		}
+		fmt.Printf("✅ Removed fact %s from %s\n", memoryID, scope.agent)
+		return nil
+	}
+
+	before := time.Now()
+	what := "everything"
+	if memoryOlderThan != "" {
+		age, err := parseAge(memoryOlderThan)
+		if err != nil {
+			return err
+		}
+		before = before.Add(-age)
+		what = "everything older than " + memoryOlderThan
+	}
+
+	if !memoryYes {
+		fmt.Printf("Remove %s %s remembers (facts, daily summaries, semantic memory)? [y/N] ", what, scope.agent)
+		var answer string
+		_, _ = fmt.Scanln(&answer)
+		if answer != "y" && answer != "Y" {
+			fmt.Println("Cancelled.")
+			return nil
+		}
+	}
+
+	removedFacts, err := facts.Prune(scope.agent, before)
+	if err != nil {
+		return err
+	}
+	removedSummaries, err := episodic.Prune(scope.agent, before)
+	if err != nil {
+		return err
+	}
+	if semantic != nil {
+		if err := semantic.Prune(cmd.Context(), scope.agent, before); err != nil {
+			return fmt.Errorf("semantic memory: %w", err)
+		}
+	}
+
+	fmt.Printf("✅ Removed %d facts and %d daily summaries from %s\n", removedFacts, removedSummaries, scope.agent)
+	if semantic != nil {
+		fmt.Println("   Semantic memory pruned as well")
+	}
+	fmt.Printf("   Pinned notes are kept in %s\n", filepath.Join(scope.dir, "MEMORY.md"))
+	return nil
+}
+
+// parseAge parses an age such as "30d", "2w" or any Go duration ("12h").
+func parseAge(s string) (time.Duration, error) {
+	if n, unit := strings.TrimRight(s, "dw"), strings.TrimLeft(s, "0123456789"); n != s && (unit == "d" || unit == "w") {
+		days, err := strconv.Atoi(n)
+		if err == nil && days > 0 {
+			if unit == "w" {
+				days *= 7
+			}
+			return time.Duration(days) * 24 * time.Hour, nil
+		}
+	}
+	d, err := time.ParseDuration(s)
+	if err != nil || d <= 0 {
+		return 0, fmt.Errorf("invalid age %q (use e.g. 30d, 2w or 12h)", s)
+	}
+	return d, nil
+}
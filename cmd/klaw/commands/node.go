@@ -1,26 +1,31 @@
 package commands
 
 import (
+	"cmp"
 	"context"
 	"fmt"
 	"os"
 	"os/signal"
-	"syscall"
+	"time"
 
 	"github.com/eachlabs/klaw/internal/agent"
 	"github.com/eachlabs/klaw/internal/cluster"
 	"github.com/eachlabs/klaw/internal/config"
+	"github.com/eachlabs/klaw/internal/controller"
+	"github.com/eachlabs/klaw/internal/memory"
 	"github.com/eachlabs/klaw/internal/node"
 	"github.com/eachlabs/klaw/internal/provider"
+	"github.com/eachlabs/klaw/internal/redact"
 	"github.com/eachlabs/klaw/internal/tool"
 	"github.com/spf13/cobra"
 )
 
 var (
-	nodeToken   string
-	nodeName    string
-	nodeLabels  map[string]string
-	nodeUseGRPC bool
+	nodeToken    string
+	nodeName     string
+	nodeLabels   map[string]string
+	nodeUseGRPC  bool
+	nodeMaxTasks int
 )
 
 var nodeCmd = &cobra.Command{
@@ -59,7 +64,12 @@ var nodeStartCmd = &cobra.Command{
 This will:
 1. Connect to the controller
 2. Register all agents in the current namespace
-3. Listen for tasks and execute them`,
+3. Listen for tasks and execute them
+
+The node runs up to --max-tasks tasks at once. Further tasks are queued by
+priority, and the controller sees each task as queued, then running.
+
+  klaw node start controller:9090 --max-tasks 8`,
 	RunE: runNodeStart,
 }
 
@@ -84,6 +94,7 @@ func init() {
 	nodeStartCmd.Flags().StringVar(&nodeToken, "token", "", "Authentication token")
 	nodeStartCmd.Flags().StringVar(&nodeName, "name", "", "Node name (default: hostname)")
 	nodeStartCmd.Flags().BoolVar(&nodeUseGRPC, "grpc", true, "Use gRPC protocol (default: true)")
+	nodeStartCmd.Flags().IntVar(&nodeMaxTasks, "max-tasks", node.DefaultMaxTasks, "Tasks to run at once; more are queued by priority (-1 for no limit)")
 
 	nodeCmd.AddCommand(nodeJoinCmd)
 	nodeCmd.AddCommand(nodeStartCmd)
@@ -145,7 +156,7 @@ func runNodeStart(cmd *cobra.Command, args []string) error {
 
 	// Get cluster context
 	store := cluster.NewStore(config.StateDir())
-	ctxMgr := cluster.NewContextManager(config.ConfigDir())
+	ctxMgr := contextManager()
 	clusterName, namespace, err := ctxMgr.RequireCurrent()
 	if err != nil {
 		return err
@@ -174,6 +185,7 @@ func runNodeStart(cmd *cobra.Command, args []string) error {
 		Token:          nodeToken,
 		Labels:         nodeLabels,
 		DataDir:        nodeDataDir,
+		MaxTasks:       nodeMaxTasks,
 	}
 
 	var client node.NodeClient
@@ -185,16 +197,13 @@ func runNodeStart(cmd *cobra.Command, args []string) error {
 		client = node.NewClient(clientCfg)
 	}
 
-	// Set up agent runner
-	client.SetAgentRunner(func(ctx context.Context, agentName, prompt string) (string, error) {
-		// Get agent config
-		agentBinding, err := store.GetAgentBinding(clusterName, namespace, agentName)
-		if err != nil {
-			return "", fmt.Errorf("agent not found: %s", agentName)
-		}
+	// Agents on this node keep their long-term memory in the node workspace
+	facts := memory.NewFactStore(cfg.WorkspaceDir())
 
-		// Create provider
-		model := agentBinding.Model
+	// Agent runtimes are built on first use and kept warm between tasks
+	workDir, _ := os.Getwd()
+	runtimes := newRuntimePool(func(ab *cluster.AgentBinding) (*agentRuntime, error) {
+		model := ab.Model
 		if model == "" {
 			model = cfg.Defaults.Model
 		}
@@ -207,20 +216,44 @@ func runNodeStart(cmd *cobra.Command, args []string) error {
 			Model:  model,
 		})
 		if err != nil {
-			return "", err
+			return nil, err
 		}
 
-		// Create tools
-		workDir, _ := os.Getwd()
-		tools := tool.DefaultRegistry(workDir)
+		registry := tool.DefaultRegistry(workDir)
+		registry.Register(namespaceSkillTool(ab.Cluster, ab.Namespace))
+		for _, t := range tool.MemoryTools(facts) {
+			registry.Register(t)
+		}
+		tools, err := agentToolRegistry(cfg, registry, ab, workDir)
+		if err != nil {
+			return nil, err
+		}
+		return &agentRuntime{provider: prov, providerName: "anthropic", model: model, tools: tools}, nil
+	})
+
+	// Set up agent runner
+	client.SetAgentRunner(func(ctx context.Context, agentName, prompt string, report func(controller.TaskEvent)) (string, error) {
+		// Get agent config
+		agentBinding, err := store.GetAgentBinding(clusterName, namespace, agentName)
+		if err != nil {
+			return "", fmt.Errorf("agent not found: %s", agentName)
+		}
+		rt, err := runtimes.get(agentBinding)
+		if err != nil {
+			return "", err
+		}
 
-		// Run agent
+		// Run agent, reporting its tool calls to the controller
+		secrets := secretMasker(cfg)
 		result, err := agent.RunOnce(ctx, agent.RunOnceConfig{
-			Provider:     prov,
-			Tools:        tools,
-			SystemPrompt: agentBinding.SystemPrompt,
+			Provider:     rt.provider,
+			Tools:        rt.tools,
+			SystemPrompt: rt.systemPrompt(ctx, cfg.WorkspaceDir(), ""),
 			Prompt:       prompt,
 			MaxTokens:    8192,
+			AgentName:    agentBinding.Name,
+			Secrets:      secrets,
+			Hooks:        []agent.Hook{toolEventHook(secrets, report)},
 		})
 
 		if err != nil {
@@ -273,13 +306,18 @@ func runNodeStart(cmd *cobra.Command, args []string) error {
 	fmt.Printf("Protocol:   %s\n", protocol)
 	fmt.Printf("Cluster:    %s/%s\n", clusterName, namespace)
 	fmt.Printf("Agents:     %d\n", len(agents))
+	if nodeMaxTasks < 0 {
+		fmt.Println("Max tasks:  unlimited")
+	} else {
+		fmt.Printf("Max tasks:  %d\n", cmp.Or(nodeMaxTasks, node.DefaultMaxTasks))
+	}
 	fmt.Println()
 	fmt.Println("Waiting for tasks... (Ctrl+C to stop)")
 	fmt.Println()
 
 	// Handle signals
 	sigCh := make(chan os.Signal, 1)
-	signal.Notify(sigCh, syscall.SIGINT, syscall.SIGTERM)
+	signal.Notify(sigCh, stopSignals...)
 
 	<-sigCh
 	fmt.Println("\n👋 Stopping node...")
@@ -298,3 +336,24 @@ func runNodeLeave(cmd *cobra.Command, args []string) error {
 	fmt.Println("Node leave not yet implemented.")
 	return nil
 }
+
+// toolEventHook reports the tool calls and results of a node's task, with
+// their credentials masked.
+func toolEventHook(secrets *redact.Redactor, report func(controller.TaskEvent)) agent.Hook {
+	if secrets == nil {
+		secrets, _ = redact.New(redact.Secrets, nil)
+	}
+	return agent.HookFunc(func(ctx context.Context, ev *agent.HookEvent) error {
+		switch ev.Point {
+		case agent.HookPreTool:
+			report(controller.TaskEvent{Time: time.Now(), Type: controller.EventToolCall, Tool: ev.Tool.Name, Input: secrets.String(string(ev.Tool.Input))})
+		case agent.HookPostTool:
+			out := controller.TaskEvent{Time: time.Now(), Type: controller.EventToolResult, Tool: ev.Tool.Name}
+			if ev.Result != nil {
+				out.Output, out.IsError = secrets.String(ev.Result.Content), ev.Result.IsError
+			}
+			report(out)
+		}
+		return nil
+	})
+}
@@ -0,0 +1,153 @@
+package commands
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"reflect"
+	"strings"
+	"text/tabwriter"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Output formats of -o/--output
+const (
+	outputTable = ""
+	outputWide  = "wide"
+	outputJSON  = "json"
+	outputYAML  = "yaml"
+)
+
+var outputFlag string
+
+// outputFormat returns the format selected with -o, or with --json.
+func outputFormat() (string, error) {
+	format := strings.ToLower(outputFlag)
+	switch format {
+	case outputTable, outputWide, outputJSON, outputYAML:
+	case "yml":
+		format = outputYAML
+	default:
+		return "", fmt.Errorf("unknown output format %q (use json, yaml or wide)", outputFlag)
+	}
+	if jsonOut && format == outputTable {
+		format = outputJSON
+	}
+	return format, nil
+}
+
+// structuredOutput reports whether results should be printed with
+// printObject rather than as text. An unknown format counts as structured,
+// so that printObject reports it.
+func structuredOutput() bool {
+	format, err := outputFormat()
+	return err != nil || format == outputJSON || format == outputYAML
+}
+
+// wideOutput reports whether tables should show their wide columns and
+// untruncated values.
+func wideOutput() bool {
+	format, _ := outputFormat()
+	return format == outputWide
+}
+
+// printObject prints v as JSON or YAML. Field names are the JSON ones in
+// both formats, and a nil list prints as an empty one.
+func printObject(v any) error {
+	format, err := outputFormat()
+	if err != nil {
+		return err
+	}
+	return printObjectAs(format, v)
+}
+
+// printObjectAs prints v as YAML, or as JSON for any other format.
+func printObjectAs(format string, v any) error {
+	if rv := reflect.ValueOf(v); rv.Kind() == reflect.Slice && rv.IsNil() {
+		v = []any{}
+	}
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return err
+	}
+	if format != outputYAML {
+		_, err = fmt.Println(string(data))
+		return err
+	}
+
+	var generic any
+	if err := json.Unmarshal(data, &generic); err != nil {
+		return err
+	}
+	out, err := yaml.Marshal(generic)
+	if err != nil {
+		return err
+	}
+	_, err = os.Stdout.Write(out)
+	return err
+}
+
+// printDryRun shows the object a --dry-run command would have written: as
+// selected with -o, or as YAML below a note.
+func printDryRun(what string, v any) error {
+	if structuredOutput() {
+		return printObject(v)
+	}
+	fmt.Printf("%s (dry run, nothing was written):\n\n", what)
+	return printObjectAs(outputYAML, v)
+}
+
+// table prints rows in aligned columns. Wide columns follow the others and
+// are only shown with -o wide.
+type table struct {
+	headers []string
+	wide    []string
+	rows    [][]string
+}
+
+func newTable(headers ...string) *table {
+	return &table{headers: headers}
+}
+
+// withWide adds columns shown only with -o wide.
+func (t *table) withWide(headers ...string) *table {
+	t.wide = headers
+	return t
+}
+
+// add adds a row with a value for every column, wide ones included.
+func (t *table) add(values ...string) {
+	t.rows = append(t.rows, values)
+}
+
+func (t *table) print() error {
+	n := len(t.headers)
+	headers := t.headers
+	if wideOutput() {
+		n += len(t.wide)
+		headers = append(append([]string{}, t.headers...), t.wide...)
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	_, _ = fmt.Fprintln(w, strings.Join(headers, "\t"))
+	for _, row := range t.rows {
+		cells := make([]string, n)
+		copy(cells, row)
+		for i, cell := range cells {
+			if cell == "" {
+				cells[i] = "-"
+			}
+		}
+		_, _ = fmt.Fprintln(w, strings.Join(cells, "\t"))
+	}
+	return w.Flush()
+}
+
+// truncateCell shortens a table value unless -o wide is given.
+func truncateCell(s string, max int) string {
+	if wideOutput() {
+		return strings.ReplaceAll(s, "\n", " ")
+	}
+	return truncateStr(s, max)
+}
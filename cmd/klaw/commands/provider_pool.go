@@ -0,0 +1,91 @@
+package commands
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/eachlabs/klaw/internal/cluster"
+	"github.com/eachlabs/klaw/internal/config"
+	"github.com/eachlabs/klaw/internal/provider"
+)
+
+// providerPool hands out provider clients per provider and model, creating
+// them on first use, so agent bindings run on their own model.
+type providerPool struct {
+	cfg          *config.Config
+	defaultName  string
+	defaultModel string
+
+	mu      sync.Mutex
+	clients map[string]provider.Provider // "<provider>/<model>" -> client
+}
+
+// newProviderPool creates a pool whose default client is prov.
+func newProviderPool(cfg *config.Config, name, model string, prov provider.Provider) *providerPool {
+	return &providerPool{
+		cfg:          cfg,
+		defaultName:  name,
+		defaultModel: model,
+		clients:      map[string]provider.Provider{name + "/" + model: prov},
+	}
+}
+
+// get returns the client for a provider and model.
+func (p *providerPool) get(name, model string) (provider.Provider, error) {
+	key := name + "/" + model
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if prov, ok := p.clients[key]; ok {
+		return prov, nil
+	}
+	prov, err := buildProvider(p.cfg, name, model)
+	if err != nil {
+		return nil, fmt.Errorf("provider %s (model %s): %w", name, model, err)
+	}
+	p.clients[key] = prov
+	return prov, nil
+}
+
+// resolve returns the provider and model an agent binding runs with. A
+// binding without a provider uses the default one; its model is kept only
+// if that provider can serve it (OpenRouter and each::labs models are
+// "vendor/model", Anthropic ones are not).
+func (p *providerPool) resolve(ab *cluster.AgentBinding) (name, model string) {
+	name, model = p.defaultName, p.defaultModel
+	if ab == nil {
+		return name, model
+	}
+	if ab.Provider != "" {
+		name = ab.Provider
+		if ab.Model != "" {
+			model = ab.Model
+		} else if name != p.defaultName {
+			model = ""
+			if provCfg, ok := p.cfg.Provider[name]; ok {
+				model = provCfg.Model
+			}
+		}
+		return name, model
+	}
+	if ab.Model != "" && modelFitsProvider(name, ab.Model) {
+		model = ab.Model
+	}
+	return name, model
+}
+
+// forAgent returns the client for an agent binding.
+func (p *providerPool) forAgent(ab *cluster.AgentBinding) (provider.Provider, error) {
+	return p.get(p.resolve(ab))
+}
+
+func modelFitsProvider(name, model string) bool {
+	switch name {
+	case "anthropic":
+		return !strings.Contains(model, "/")
+	case "openrouter", "eachlabs":
+		return strings.Contains(model, "/")
+	default:
+		return true
+	}
+}
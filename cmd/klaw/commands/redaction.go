@@ -0,0 +1,62 @@
+package commands
+
+import (
+	"fmt"
+
+	"github.com/eachlabs/klaw/internal/config"
+	"github.com/eachlabs/klaw/internal/redact"
+)
+
+// newRedactor returns the redactor of [redaction], or nil when it is
+// disabled.
+func newRedactor(cfg *config.Config) (*redact.Redactor, error) {
+	rc := cfg.Redaction
+	if !rc.Enabled {
+		return nil, nil
+	}
+	r, err := redact.New(redactionDetectors(rc), redactPatterns(rc.Patterns))
+	if err != nil {
+		return nil, fmt.Errorf("invalid [redaction]: %w", err)
+	}
+	return r, nil
+}
+
+// redactionDetectors returns the built-in detectors [redaction] uses,
+// all of them unless some are named.
+func redactionDetectors(rc config.RedactionConfig) []string {
+	if len(rc.Detectors) > 0 {
+		return rc.Detectors
+	}
+	return redact.Detectors
+}
+
+// secretMasker returns the masker of credentials in tool output set up in
+// [tools.secrets]; nil selects the built-in detectors.
+func secretMasker(cfg *config.Config) *redact.Redactor {
+	sc := cfg.Tools.Secrets
+	if sc.Mode == "off" {
+		r, _ := redact.New(nil, nil)
+		return r
+	}
+	if len(sc.Detectors) == 0 && len(sc.Patterns) == 0 {
+		return nil
+	}
+	detectors := sc.Detectors
+	if len(detectors) == 0 {
+		detectors = redact.Secrets
+	}
+	r, err := redact.New(detectors, redactPatterns(sc.Patterns))
+	if err != nil {
+		fmt.Printf("Warning: invalid [tools.secrets]: %v; using the built-in detectors\n", err)
+		return nil
+	}
+	return r
+}
+
+func redactPatterns(patterns []config.RedactionPattern) []redact.Pattern {
+	out := make([]redact.Pattern, len(patterns))
+	for i, p := range patterns {
+		out[i] = redact.Pattern{Name: p.Name, Regex: p.Regex, Replacement: p.Replacement}
+	}
+	return out
+}
@@ -0,0 +1,232 @@
+package commands
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/eachlabs/klaw/internal/agent"
+	"github.com/eachlabs/klaw/internal/config"
+	"github.com/eachlabs/klaw/internal/tool"
+	"github.com/spf13/cobra"
+)
+
+var (
+	replayTurn    int
+	replayList    bool
+	replayExec    bool
+	replayWorkDir string
+)
+
+var replayCmd = &cobra.Command{
+	Use:   "replay [conversation-id]",
+	Short: "Re-run a recorded turn against a mock provider",
+	Long: `Re-run a recorded turn of a conversation. The model's recorded responses
+are given back by a mock provider, so the turn takes the same steps without
+calling the provider, and each request is compared with the recorded one.
+
+By default tool calls are answered with their recorded results and nothing
+is executed. With --exec the tools run for real in --workdir, to reproduce
+what they did (e.g. which file was deleted); the replay then reports where
+their results, and so the following requests, differ from the recording.
+
+Turns are recorded when [history] transcripts = true is set in the config.
+
+Examples:
+  klaw replay --list                               # conversations with transcripts
+  klaw replay C0123456789:1712345678.000100 --list # turns of a conversation
+  klaw replay C0123456789:1712345678.000100        # replay its last turn
+  klaw replay C0123456789:1712345678.000100 --turn 2 --exec --workdir /tmp/repro`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runReplay,
+}
+
+func init() {
+	replayCmd.Flags().IntVar(&replayTurn, "turn", 0, "Turn to replay, counting from 1 (default: the last)")
+	replayCmd.Flags().BoolVar(&replayList, "list", false, "List conversations, or the turns of a conversation")
+	replayCmd.Flags().BoolVar(&replayExec, "exec", false, "Run the tools instead of returning their recorded results")
+	replayCmd.Flags().StringVar(&replayWorkDir, "workdir", "", "Working directory of the tools with --exec (default: current directory)")
+
+	rootCmd.AddCommand(replayCmd)
+}
+
+// transcriptStore is where the transcripts of a namespace are kept.
+func transcriptStore(clusterName, namespace string) *agent.TranscriptStore {
+	return &agent.TranscriptStore{Dir: filepath.Join(config.StateDir(), "transcripts", clusterName, namespace)}
+}
+
+// transcriptRecorder returns the recorder of turn transcripts, or nil
+// unless [history] transcripts is enabled.
+func transcriptRecorder(cfg *config.Config, clusterName, namespace string) agent.TranscriptRecorder {
+	if !cfg.History.Transcripts {
+		return nil
+	}
+	return transcriptStore(clusterName, namespace)
+}
+
+func runReplay(cmd *cobra.Command, args []string) error {
+	clusterName, namespace, err := contextManager().RequireCurrent()
+	if err != nil {
+		return err
+	}
+	store := transcriptStore(clusterName, namespace)
+
+	if len(args) == 0 {
+		if !replayList {
+			return fmt.Errorf("give a conversation ID, or --list to list them")
+		}
+		ids, err := store.Conversations()
+		if err != nil {
+			return err
+		}
+		if structuredOutput() {
+			return printObject(ids)
+		}
+		if len(ids) == 0 {
+			fmt.Printf("No transcripts in %s/%s. Set [history] transcripts = true to record them.\n", clusterName, namespace)
+			return nil
+		}
+		for _, id := range ids {
+			fmt.Println(id)
+		}
+		return nil
+	}
+
+	transcripts, err := store.List(args[0])
+	if err != nil {
+		return err
+	}
+	if len(transcripts) == 0 {
+		return fmt.Errorf("no transcripts for conversation %q", args[0])
+	}
+	if replayList {
+		return printTranscripts(transcripts)
+	}
+
+	n := len(transcripts)
+	if replayTurn != 0 {
+		n = replayTurn
+	}
+	if n < 1 || n > len(transcripts) {
+		return fmt.Errorf("--turn must be between 1 and %d", len(transcripts))
+	}
+	t := transcripts[n-1]
+
+	var tools *tool.Registry
+	if replayExec {
+		workDir := replayWorkDir
+		if workDir == "" {
+			if workDir, err = os.Getwd(); err != nil {
+				return err
+			}
+		}
+		tools = tool.DefaultRegistry(workDir)
+	}
+
+	res, err := agent.Replay(cmd.Context(), t, agent.ReplayConfig{Tools: tools})
+	if err != nil {
+		return err
+	}
+	if structuredOutput() {
+		return printObject(res)
+	}
+	printReplay(t, n, res)
+	return nil
+}
+
+// printTranscripts lists the recorded turns of a conversation.
+func printTranscripts(transcripts []*agent.Transcript) error {
+	if structuredOutput() {
+		return printObject(transcripts)
+	}
+	t := newTable("TURN", "STARTED", "AGENT", "STEPS", "TOOLS", "MESSAGE").withWide("DURATION", "ERROR")
+	for i, tr := range transcripts {
+		tools := 0
+		for _, step := range tr.Steps {
+			tools += len(step.Tools)
+		}
+		t.add(
+			strconv.Itoa(i+1),
+			tr.StartedAt.Local().Format("2006-01-02 15:04:05"),
+			tr.Agent,
+			strconv.Itoa(len(tr.Steps)),
+			strconv.Itoa(tools),
+			truncateCell(tr.Message, 50),
+			tr.Duration.Round(time.Millisecond).String(),
+			tr.Error,
+		)
+	}
+	return t.print()
+}
+
+// printReplay shows the steps of a replay next to the recording.
+func printReplay(t *agent.Transcript, turn int, res *agent.ReplayResult) {
+	agentName := t.Agent
+	if agentName == "" {
+		agentName = "default"
+	}
+	fmt.Printf("Replaying turn %d of %s (agent %s, %s)\n", turn, t.ConversationID, agentName, t.StartedAt.Local().Format("2006-01-02 15:04:05"))
+	fmt.Printf("User: %s\n\n", truncateStr(t.Message, 200))
+
+	for i, step := range res.Steps {
+		mark := "✓ request matches the recording"
+		if !step.Matches {
+			mark = "✗ request differs from the recording"
+		}
+		fmt.Printf("Step %d  %s\n", i+1, mark)
+		var recorded []agent.TranscriptTool
+		if i < len(t.Steps) {
+			recorded = t.Steps[i].Tools
+		}
+		for j, tt := range step.Tools {
+			fmt.Printf("  → %s %s\n", tt.Name, truncateStr(compactInput(tt.Input), 120))
+			if replayExec && j < len(recorded) && (recorded[j].Result != tt.Result || recorded[j].IsError != tt.IsError) {
+				fmt.Printf("    recorded: %s\n", toolOutcome(recorded[j].Result, recorded[j].IsError))
+				fmt.Printf("    replayed: %s  ✗ differs\n", toolOutcome(tt.Result, tt.IsError))
+				continue
+			}
+			fmt.Printf("    %s\n", toolOutcome(tt.Result, tt.IsError))
+		}
+	}
+	fmt.Println()
+
+	if res.Error != "" {
+		fmt.Printf("Error: %s\n", res.Error)
+		if t.Error != "" {
+			fmt.Printf("Recorded error: %s\n", t.Error)
+		}
+	} else {
+		fmt.Printf("Reply: %s\n", truncateStr(res.Reply, 300))
+	}
+	if res.Diverged < 0 {
+		fmt.Println("Result: the replay matches the recording.")
+	} else {
+		fmt.Printf("Result: the replay diverged at step %d.\n", res.Diverged+1)
+	}
+}
+
+// compactInput shows tool input on one line.
+func compactInput(input json.RawMessage) string {
+	var buf bytes.Buffer
+	if err := json.Compact(&buf, input); err != nil {
+		return string(input)
+	}
+	return buf.String()
+}
+
+// toolOutcome summarizes the result of a tool call.
+func toolOutcome(result string, isError bool) string {
+	status := "ok"
+	if isError {
+		status = "error"
+	}
+	if result = strings.TrimSpace(result); result == "" {
+		return status
+	}
+	return status + ": " + truncateStr(result, 120)
+}
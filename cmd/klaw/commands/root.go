@@ -1,8 +1,12 @@
 package commands
 
 import (
+	"errors"
 	"fmt"
 
+	"github.com/eachlabs/klaw/internal/cluster"
+	"github.com/eachlabs/klaw/internal/config"
+	"github.com/eachlabs/klaw/internal/tool"
 	"github.com/spf13/cobra"
 )
 
@@ -10,6 +14,9 @@ var (
 	cfgFile string
 	verbose bool
 	jsonOut bool
+
+	clusterFlag   string
+	namespaceFlag string
 )
 
 var rootCmd = &cobra.Command{
@@ -23,13 +30,22 @@ kubectl-style commands for AI:
   klaw create <resource> Create a resource
   klaw delete <resource> Delete a resource
   klaw describe <resource> Show resource details
-  klaw config            Manage configuration`,
+  klaw config            Manage configuration
+
+Commands work on the current cluster and namespace (klaw config
+current-context). Use --cluster and -n/--namespace to target another one
+for a single command:
+  klaw get agents -n marketing
+  klaw cron list --cluster acme-corp -n sales`,
 }
 
 func init() {
 	rootCmd.PersistentFlags().StringVar(&cfgFile, "config", "", "config file (default: ~/.klaw/config.toml)")
 	rootCmd.PersistentFlags().BoolVarP(&verbose, "verbose", "v", false, "verbose output")
-	rootCmd.PersistentFlags().BoolVar(&jsonOut, "json", false, "output as JSON")
+	rootCmd.PersistentFlags().BoolVar(&jsonOut, "json", false, "output as JSON (same as -o json)")
+	rootCmd.PersistentFlags().StringVarP(&outputFlag, "output", "o", "", "output format: json, yaml or wide")
+	rootCmd.PersistentFlags().StringVar(&clusterFlag, "cluster", "", "cluster to use instead of the current one")
+	rootCmd.PersistentFlags().StringVarP(&namespaceFlag, "namespace", "n", "", "namespace to use instead of the current one")
 
 	// Add commands
 	rootCmd.AddCommand(chatCmd)
@@ -43,11 +59,38 @@ func init() {
 	rootCmd.AddCommand(upgradeCmd)
 }
 
+// contextManager returns the context manager with the --cluster and
+// --namespace flags applied.
+func contextManager() *cluster.ContextManager {
+	ctxMgr := cluster.NewContextManager(config.ConfigDir())
+	ctxMgr.Override(clusterFlag, namespaceFlag)
+	return ctxMgr
+}
+
 func Execute(ver string) error {
 	version = ver
+	defer tool.CloseBrowsers()
 	return rootCmd.Execute()
 }
 
+// exitError makes klaw exit with a code other than 1.
+type exitError struct {
+	code int
+	err  error
+}
+
+func (e *exitError) Error() string { return e.err.Error() }
+func (e *exitError) Unwrap() error { return e.err }
+
+// ExitCode returns the exit code for an error returned by Execute.
+func ExitCode(err error) int {
+	var exitErr *exitError
+	if errors.As(err, &exitErr) {
+		return exitErr.code
+	}
+	return 1
+}
+
 var version string
 
 var versionCmd = &cobra.Command{
@@ -0,0 +1,649 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"text/tabwriter"
+
+	"github.com/eachlabs/klaw/internal/agent"
+	"github.com/eachlabs/klaw/internal/channel"
+	"github.com/eachlabs/klaw/internal/cluster"
+	"github.com/eachlabs/klaw/internal/config"
+	"github.com/eachlabs/klaw/internal/orchestrator"
+	"github.com/eachlabs/klaw/internal/provider"
+	"github.com/spf13/cobra"
+)
+
+var (
+	routingMatch       string
+	routingAgent       string
+	routingMode        string
+	routingDefault     string
+	routingManual      bool
+	routingMinAccuracy float64
+)
+
+var routingCmd = &cobra.Command{
+	Use:   "routing",
+	Short: "Manage message routing between agents",
+	Long: `Manage how klaw start routes Slack messages to the agents of the
+current namespace.
+
+Modes:
+  rules     routing rules, then agent triggers, then the default agent
+  ai        an LLM picks the agent from their descriptions
+  hybrid    rules and triggers first, then the LLM
+  disabled  only @agent messages are routed
+
+Examples:
+  klaw routing add --match "deploy|rollback" --agent devops
+  klaw routing list
+  klaw routing test "please deploy api"
+  klaw routing eval routing-fixtures.yaml
+  klaw routing set --mode hybrid --default support
+  klaw routing remove 1`,
+}
+
+var routingAddCmd = &cobra.Command{
+	Use:   "add",
+	Short: "Add a routing rule",
+	Long: `Add a rule that routes messages matching a regular expression
+(case-insensitive) to an agent. Rules are tried in order.`,
+	Args: cobra.NoArgs,
+	RunE: runRoutingAdd,
+}
+
+var routingListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "Show routing settings and rules",
+	RunE:  runRoutingList,
+}
+
+var routingRemoveCmd = &cobra.Command{
+	Use:   "remove <rule-number>",
+	Short: "Remove a routing rule",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runRoutingRemove,
+}
+
+var routingSetCmd = &cobra.Command{
+	Use:   "set",
+	Short: "Change the routing mode, default agent or @agent routing",
+	Args:  cobra.NoArgs,
+	RunE:  runRoutingSet,
+}
+
+var routingTestCmd = &cobra.Command{
+	Use:   "test <message>",
+	Short: "Show which agent a message would be routed to, and why",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runRoutingTest,
+}
+
+var routingEvalCmd = &cobra.Command{
+	Use:   "eval <fixtures.yaml>",
+	Short: "Check routing against messages with known agents",
+	Long: `Route each message of a fixtures file with the current routing settings
+and report how many reached the expected agent, so rule, trigger, mode and
+classifier changes can be checked before they ship.
+
+The fixtures file is a YAML list of messages and the agent each must be
+routed to; "klaw" is the main agent:
+
+  - message: please roll back the api deploy
+    agent: devops
+  - message: "@support where is my invoice?"
+    agent: support
+  - message: good morning
+    agent: klaw
+
+In ai and hybrid modes each message not matched by a rule calls the AI
+classifier. The command fails when the accuracy is below --min-accuracy.
+
+Examples:
+  klaw routing eval routing-fixtures.yaml
+  klaw routing eval routing-fixtures.yaml --min-accuracy 0.9 -o json`,
+	Args: cobra.ExactArgs(1),
+	RunE: runRoutingEval,
+}
+
+func init() {
+	routingAddCmd.Flags().StringVarP(&routingMatch, "match", "m", "", "Regular expression to match (required)")
+	routingAddCmd.Flags().StringVarP(&routingAgent, "agent", "a", "", "Agent to route matching messages to (required)")
+	_ = routingAddCmd.MarkFlagRequired("match")
+	_ = routingAddCmd.MarkFlagRequired("agent")
+
+	routingSetCmd.Flags().StringVar(&routingMode, "mode", "", "Routing mode: rules, ai, hybrid or disabled")
+	routingSetCmd.Flags().StringVar(&routingDefault, "default", "", "Agent for messages nothing else matches (\"-\" to clear)")
+	routingSetCmd.Flags().BoolVar(&routingManual, "manual", true, "Allow @agent routing")
+
+	routingEvalCmd.Flags().Float64Var(&routingMinAccuracy, "min-accuracy", 1, "Fail when fewer than this share of the messages (0 to 1) reach their agent")
+
+	routingCmd.AddCommand(routingAddCmd)
+	routingCmd.AddCommand(routingListCmd)
+	routingCmd.AddCommand(routingRemoveCmd)
+	routingCmd.AddCommand(routingSetCmd)
+	routingCmd.AddCommand(routingTestCmd)
+	routingCmd.AddCommand(routingEvalCmd)
+	rootCmd.AddCommand(routingCmd)
+}
+
+// routingConfig loads the orchestrator config of the current namespace.
+func routingConfig() (*cluster.Store, *cluster.Namespace, error) {
+	ctxMgr := contextManager()
+	clusterName, namespace, err := ctxMgr.RequireCurrent()
+	if err != nil {
+		return nil, nil, err
+	}
+	store := cluster.NewStore(config.StateDir())
+	ns, err := store.GetNamespace(clusterName, namespace)
+	if err != nil {
+		return nil, nil, err
+	}
+	if ns.Orchestrator == nil {
+		ns.Orchestrator = defaultOrchestratorConfig()
+	}
+	return store, ns, nil
+}
+
+// defaultOrchestratorConfig is used by namespaces without one: only @agent
+// messages are routed.
+func defaultOrchestratorConfig() *cluster.OrchestratorConfig {
+	return &cluster.OrchestratorConfig{Mode: "disabled", AllowManual: true}
+}
+
+func runRoutingAdd(cmd *cobra.Command, args []string) error {
+	store, ns, err := routingConfig()
+	if err != nil {
+		return err
+	}
+	if _, err := regexp.Compile("(?i)" + routingMatch); err != nil {
+		return fmt.Errorf("invalid --match pattern: %w", err)
+	}
+	if !store.AgentBindingExists(ns.Cluster, ns.Name, routingAgent) {
+		return fmt.Errorf("agent not found: %s\nCreate it with: klaw create agent %s --description \"...\"", routingAgent, routingAgent)
+	}
+
+	// The first rule turns rule-based routing on
+	orch := ns.Orchestrator
+	enabled := false
+	if orch.Mode == "" || (orch.Mode == "disabled" && len(orch.Rules) == 0) {
+		orch.Mode = "rules"
+		enabled = true
+	}
+	orch.Rules = append(orch.Rules, cluster.RoutingRule{Match: routingMatch, Agent: routingAgent})
+	if err := store.UpdateNamespaceOrchestrator(ns.Cluster, ns.Name, orch); err != nil {
+		return err
+	}
+
+	fmt.Printf("✅ Rule #%d added: /%s/ → %s\n", len(orch.Rules), routingMatch, routingAgent)
+	if enabled {
+		fmt.Println("Routing mode set to rules.")
+	}
+	if orch.Mode == "ai" || orch.Mode == "disabled" {
+		fmt.Printf("Note: rules are not used in %s mode. Enable them with: klaw routing set --mode hybrid\n", orch.Mode)
+	}
+	return nil
+}
+
+func runRoutingList(cmd *cobra.Command, args []string) error {
+	store, ns, err := routingConfig()
+	if err != nil {
+		return err
+	}
+	orch := ns.Orchestrator
+
+	if structuredOutput() {
+		return printObject(orch)
+	}
+
+	fmt.Printf("Routing in %s/%s:\n\n", ns.Cluster, ns.Name)
+	fmt.Printf("  Mode:          %s\n", orch.Mode)
+	fmt.Printf("  Default agent: %s\n", valueOrNone(orch.DefaultAgent))
+	fmt.Printf("  @agent:        %v\n", orch.AllowManual)
+	fmt.Println()
+
+	if len(orch.Rules) == 0 {
+		fmt.Println("No routing rules. Add one with:")
+		fmt.Println("  klaw routing add --match \"deploy|rollback\" --agent devops")
+	} else {
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		_, _ = fmt.Fprintln(w, "#\tMATCH\tAGENT")
+		_, _ = fmt.Fprintln(w, "-\t-----\t-----")
+		for i, rule := range orch.Rules {
+			_, _ = fmt.Fprintf(w, "%d\t%s\t%s\n", i+1, rule.Match, rule.Agent)
+		}
+		_ = w.Flush()
+	}
+
+	agents, _ := store.ListAgentBindings(ns.Cluster, ns.Name)
+	var triggered []*cluster.AgentBinding
+	for _, ab := range agents {
+		if len(ab.Triggers) > 0 {
+			triggered = append(triggered, ab)
+		}
+	}
+	if len(triggered) > 0 {
+		fmt.Println()
+		fmt.Println("Agent triggers:")
+		for _, ab := range triggered {
+			fmt.Printf("  %s: %s\n", ab.Name, strings.Join(ab.Triggers, ", "))
+		}
+	}
+	return nil
+}
+
+func runRoutingRemove(cmd *cobra.Command, args []string) error {
+	store, ns, err := routingConfig()
+	if err != nil {
+		return err
+	}
+	orch := ns.Orchestrator
+	n, err := strconv.Atoi(args[0])
+	if err != nil || n < 1 || n > len(orch.Rules) {
+		return fmt.Errorf("no rule #%s (see klaw routing list)", args[0])
+	}
+
+	rule := orch.Rules[n-1]
+	orch.Rules = append(orch.Rules[:n-1], orch.Rules[n:]...)
+	if err := store.UpdateNamespaceOrchestrator(ns.Cluster, ns.Name, orch); err != nil {
+		return err
+	}
+	fmt.Printf("✅ Removed rule #%d: /%s/ → %s\n", n, rule.Match, rule.Agent)
+	return nil
+}
+
+func runRoutingSet(cmd *cobra.Command, args []string) error {
+	store, ns, err := routingConfig()
+	if err != nil {
+		return err
+	}
+	orch := ns.Orchestrator
+
+	if cmd.Flags().Changed("mode") {
+		switch routingMode {
+		case "rules", "ai", "hybrid", "disabled":
+			orch.Mode = routingMode
+		default:
+			return fmt.Errorf("invalid mode %q (use rules, ai, hybrid or disabled)", routingMode)
+		}
+	}
+	if cmd.Flags().Changed("default") {
+		switch {
+		case routingDefault == "-":
+			orch.DefaultAgent = ""
+		case !store.AgentBindingExists(ns.Cluster, ns.Name, routingDefault):
+			return fmt.Errorf("agent not found: %s", routingDefault)
+		default:
+			orch.DefaultAgent = routingDefault
+		}
+	}
+	if cmd.Flags().Changed("manual") {
+		orch.AllowManual = routingManual
+	}
+
+	if err := store.UpdateNamespaceOrchestrator(ns.Cluster, ns.Name, orch); err != nil {
+		return err
+	}
+	fmt.Printf("✅ Routing: mode %s, default agent %s, @agent %v\n", orch.Mode, valueOrNone(orch.DefaultAgent), orch.AllowManual)
+	fmt.Println("Restart klaw start to apply.")
+	return nil
+}
+
+func runRoutingTest(cmd *cobra.Command, args []string) error {
+	store, ns, err := routingConfig()
+	if err != nil {
+		return err
+	}
+	orch := ns.Orchestrator
+	agents, err := store.ListAgentBindings(ns.Cluster, ns.Name)
+	if err != nil {
+		return err
+	}
+
+	classifier, err := routingClassifier(orch.Mode)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Message: %s\n", args[0])
+	fmt.Printf("Mode:    %s\n", orch.Mode)
+
+	router := newBindingRouter(store, ns.Cluster, ns.Name, agents, classifier, nil)
+	if router == nil {
+		fmt.Println("Agent:   klaw (main agent)")
+		if len(agents) == 0 {
+			fmt.Println("Reason:  the namespace has no agents")
+		} else {
+			fmt.Println("Reason:  routing is disabled and @agent is not allowed")
+		}
+		return nil
+	}
+
+	decision, err := router.decide(cmd.Context(), router.orch.ParseMessage(args[0]))
+	if err != nil {
+		return err
+	}
+	agentName := "klaw (main agent)"
+	reason := decision.Reason
+	if ab := router.binding(decision); ab != nil {
+		agentName = ab.Name
+	} else if len(decision.Agents) == 1 {
+		reason += fmt.Sprintf("; agent %s does not exist", decision.Agents[0])
+	} else if len(decision.Agents) > 1 {
+		reason += "; several agents at once are handled by the main agent"
+	}
+	fmt.Printf("Agent:   %s\n", agentName)
+	fmt.Printf("Via:     %s\n", decision.Via)
+	fmt.Printf("Reason:  %s\n", reason)
+	return nil
+}
+
+func runRoutingEval(cmd *cobra.Command, args []string) error {
+	fixtures, err := orchestrator.LoadFixtures(args[0])
+	if err != nil {
+		return err
+	}
+	store, ns, err := routingConfig()
+	if err != nil {
+		return err
+	}
+	agents, err := store.ListAgentBindings(ns.Cluster, ns.Name)
+	if err != nil {
+		return err
+	}
+	classifier, err := routingClassifier(ns.Orchestrator.Mode)
+	if err != nil {
+		return err
+	}
+
+	// Route as klaw start does: messages no binding is picked for, and
+	// all messages when there is no router, go to the main agent
+	router := newBindingRouter(store, ns.Cluster, ns.Name, agents, classifier, nil)
+	report := orchestrator.EvaluateFixtures(cmd.Context(), fixtures, func(ctx context.Context, message string) (string, *orchestrator.Decision, error) {
+		if router == nil {
+			return orchestrator.MainAgent, &orchestrator.Decision{Via: orchestrator.ViaDefault, Reason: "routing is off"}, nil
+		}
+		decision, err := router.decide(ctx, router.orch.ParseMessage(message))
+		if err != nil {
+			return "", nil, err
+		}
+		if ab := router.binding(decision); ab != nil {
+			return ab.Name, decision, nil
+		}
+		return orchestrator.MainAgent, decision, nil
+	})
+
+	// A low accuracy is not a usage error
+	cmd.SilenceUsage = true
+	if structuredOutput() {
+		if err := printObject(report); err != nil {
+			return err
+		}
+	} else if err := printRoutingEval(ns, report); err != nil {
+		return err
+	}
+	if report.Accuracy < routingMinAccuracy {
+		return fmt.Errorf("routing accuracy %.1f%% is below %.1f%%", report.Accuracy*100, routingMinAccuracy*100)
+	}
+	return nil
+}
+
+// printRoutingEval shows the misrouted messages and the accuracy per agent.
+func printRoutingEval(ns *cluster.Namespace, report *orchestrator.FixtureReport) error {
+	fmt.Printf("Routing in %s/%s (mode %s)\n\n", ns.Cluster, ns.Name, ns.Orchestrator.Mode)
+
+	if report.Passed < report.Total {
+		t := newTable("MESSAGE", "EXPECTED", "GOT", "VIA").withWide("REASON")
+		for _, r := range report.Results {
+			if r.Passed {
+				continue
+			}
+			got := r.Got
+			if got == "" {
+				got = "(error)"
+			}
+			t.add(truncateCell(r.Message, 50), r.Agent, got, r.Via, r.Reason)
+		}
+		if err := t.print(); err != nil {
+			return err
+		}
+		fmt.Println()
+	}
+
+	t := newTable("AGENT", "EXPECTED", "CORRECT", "MISROUTED")
+	for _, a := range report.Agents {
+		t.add(a.Agent, strconv.Itoa(a.Expected), strconv.Itoa(a.Correct), strconv.Itoa(a.Misrouted))
+	}
+	if err := t.print(); err != nil {
+		return err
+	}
+	fmt.Printf("\nAccuracy: %d/%d (%.1f%%)\n", report.Passed, report.Total, report.Accuracy*100)
+	return nil
+}
+
+// routingClassifier returns the provider AI routing runs on, the one klaw
+// start would use, or nil when mode does not use one or it is unavailable.
+func routingClassifier(mode string) (provider.Provider, error) {
+	if mode != "ai" && mode != "hybrid" {
+		return nil, nil
+	}
+	cfg, err := config.Load()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load config: %w", err)
+	}
+	name, model := defaultProviderModel(cfg, "", "")
+	prov, err := buildProvider(cfg, name, model)
+	if err != nil {
+		fmt.Printf("Warning: AI classifier unavailable: %v\n\n", err)
+		return nil, nil
+	}
+	return prov, nil
+}
+
+func valueOrNone(s string) string {
+	if s == "" {
+		return "(none)"
+	}
+	return s
+}
+
+// newOrchestrator builds the orchestrator of a namespace from its stored
+// config and agent bindings.
+func newOrchestrator(cfg *cluster.OrchestratorConfig, bindings []*cluster.AgentBinding, classifier provider.Provider) *orchestrator.Orchestrator {
+	rules := make([]orchestrator.RoutingRule, len(cfg.Rules))
+	for i, r := range cfg.Rules {
+		rules[i] = orchestrator.RoutingRule{Match: r.Match, Agent: r.Agent}
+	}
+	orch := orchestrator.New(orchestrator.Config{
+		Mode:         cfg.Mode,
+		DefaultAgent: cfg.DefaultAgent,
+		AllowManual:  cfg.AllowManual,
+		Rules:        rules,
+		Provider:     classifier,
+	})
+	for _, ab := range bindings {
+		orch.RegisterAgent(&orchestrator.AgentConfig{
+			Name:         ab.Name,
+			Description:  ab.Description,
+			SystemPrompt: ab.SystemPrompt,
+			Tools:        ab.Tools,
+			Model:        ab.Model,
+			Triggers:     ab.Triggers,
+		})
+	}
+	return orch
+}
+
+// bindingRouter routes the messages of a channel to the agent bindings of
+// a namespace. A thread stays with the agent that took its first message
+// until another one is addressed with @agent; messages no binding is picked
+// for go to the main agent.
+type bindingRouter struct {
+	orch       *orchestrator.Orchestrator
+	mode       string
+	manualOnly bool // route @agent messages only
+	store      *cluster.Store
+	cluster    string
+	namespace  string
+	logChannel string // channel name message logs are kept under
+	bindings   map[string]*cluster.AgentBinding
+	profile    func(ab *cluster.AgentBinding) (*agent.Profile, error)
+
+	mu     sync.Mutex
+	routed map[string]*orchestrator.Decision // thread conversation -> decision
+	logMu  sync.Mutex                        // serializes message log writes
+}
+
+// newBindingRouter returns a router for the namespace, or nil when it has
+// no agents or routing is off. Without an orchestrator config only @agent
+// messages are routed.
+func newBindingRouter(store *cluster.Store, clusterName, namespace string, bindings []*cluster.AgentBinding, classifier provider.Provider, profile func(*cluster.AgentBinding) (*agent.Profile, error)) *bindingRouter {
+	if len(bindings) == 0 {
+		return nil
+	}
+	orchCfg := defaultOrchestratorConfig()
+	if ns, err := store.GetNamespace(clusterName, namespace); err == nil && ns.Orchestrator != nil {
+		orchCfg = ns.Orchestrator
+	}
+	manualOnly := orchCfg.Mode == "" || orchCfg.Mode == "disabled"
+
+	// Channels that pin their messages to an agent need a router even
+	// when routing is off
+	pinned := false
+	logChannel := "slack"
+	channels, _ := store.ListChannelBindings(clusterName, namespace)
+	for _, cb := range channels {
+		if cb.Config["agent"] != "" {
+			pinned = true
+		}
+	}
+	for _, cb := range channels {
+		if cb.Type == "slack" {
+			logChannel = cb.Name
+			break
+		}
+	}
+	if manualOnly && !orchCfg.AllowManual && !pinned {
+		return nil
+	}
+
+	byName := make(map[string]*cluster.AgentBinding, len(bindings))
+	for _, ab := range bindings {
+		byName[ab.Name] = ab
+	}
+
+	return &bindingRouter{
+		orch:       newOrchestrator(orchCfg, bindings, classifier),
+		mode:       orchCfg.Mode,
+		manualOnly: manualOnly,
+		store:      store,
+		cluster:    clusterName,
+		namespace:  namespace,
+		logChannel: logChannel,
+		bindings:   byName,
+		profile:    profile,
+		routed:     make(map[string]*orchestrator.Decision),
+	}
+}
+
+// viaPinned is the routing method of messages pinned to an agent by their
+// channel.
+const viaPinned = "pinned"
+
+// Route implements agent.Router.
+func (r *bindingRouter) Route(ctx context.Context, conversationID string, msg *channel.Message) (*agent.Profile, error) {
+	// Messages pinned to an agent by their channel, such as alerts for
+	// the SRE agent
+	if name, _ := msg.Metadata[channel.MetaAgent].(string); name != "" {
+		ab := r.bindings[name]
+		if ab == nil {
+			return nil, fmt.Errorf("%w: %s", orchestrator.ErrAgentNotFound, name)
+		}
+		r.log(msg, ab, viaPinned)
+		return r.profile(ab)
+	}
+
+	threadTS, _ := msg.Metadata["thread_ts"].(string)
+	parsed := r.orch.ParseMessage(msg.Content)
+	manual := parsed.TargetAgent != "" && r.orch.AllowsManual()
+
+	r.mu.Lock()
+	decision, ok := r.routed[conversationID]
+	r.mu.Unlock()
+	if !ok || threadTS == "" || manual {
+		var err error
+		decision, err = r.decide(ctx, parsed)
+		if err != nil {
+			return nil, err
+		}
+		if manual {
+			msg.Content = parsed.Content
+		}
+		if threadTS != "" {
+			r.mu.Lock()
+			r.routed[conversationID] = decision
+			r.mu.Unlock()
+		}
+	}
+
+	ab := r.binding(decision)
+	r.log(msg, ab, decision.Via)
+	if ab == nil {
+		return nil, nil
+	}
+	return r.profile(ab)
+}
+
+// decide routes a new message. Only an unknown @agent is an error; other
+// messages nothing is picked for get a decision without agents.
+func (r *bindingRouter) decide(ctx context.Context, parsed *orchestrator.ParsedMessage) (*orchestrator.Decision, error) {
+	if parsed.TargetAgent != "" && r.orch.AllowsManual() {
+		return r.orch.Explain(ctx, parsed)
+	}
+	if r.manualOnly {
+		return &orchestrator.Decision{Via: orchestrator.ViaDefault, Reason: "only @agent messages are routed"}, nil
+	}
+	decision, err := r.orch.Explain(ctx, parsed)
+	if err != nil {
+		return &orchestrator.Decision{Via: orchestrator.ViaDefault, Reason: err.Error()}, nil
+	}
+	return decision, nil
+}
+
+// binding returns the agent binding a decision picked, or nil for the main
+// agent.
+func (r *bindingRouter) binding(decision *orchestrator.Decision) *cluster.AgentBinding {
+	if len(decision.Agents) != 1 {
+		return nil
+	}
+	return r.bindings[decision.Agents[0]]
+}
+
+// log records the routing of msg in the namespace message log.
+func (r *bindingRouter) log(msg *channel.Message, ab *cluster.AgentBinding, via string) {
+	name := "klaw"
+	if ab != nil {
+		name = ab.Name
+	}
+	user, _ := msg.Metadata["user"].(string)
+	channelID, _ := msg.Metadata["channel"].(string)
+	r.logMu.Lock()
+	defer r.logMu.Unlock()
+	err := r.store.AppendMessageLog(r.cluster, r.namespace, r.logChannel, &cluster.MessageLog{
+		ID:        msg.ID,
+		Channel:   channelID,
+		User:      user,
+		Agent:     name,
+		Content:   msg.Content,
+		RoutedVia: via,
+	})
+	if err != nil {
+		fmt.Printf("Warning: message log: %v\n", err)
+	}
+}
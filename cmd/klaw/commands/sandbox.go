@@ -0,0 +1,59 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/eachlabs/klaw/internal/agent"
+	"github.com/eachlabs/klaw/internal/config"
+	"github.com/eachlabs/klaw/internal/runtime"
+)
+
+// isolationMode is the isolation of agent turns: the flag, else [sandbox]
+// isolation.
+func isolationMode(cfg *config.Config, flag string) (string, error) {
+	mode := flag
+	if mode == "" {
+		mode = cfg.Sandbox.Isolation
+	}
+	switch mode {
+	case "", "none":
+		return "none", nil
+	case "container":
+		return mode, nil
+	default:
+		return "", fmt.Errorf("unknown isolation: %s (use none or container)", mode)
+	}
+}
+
+// openSandbox creates the container sandbox of the [sandbox] config, with
+// the engine checked and the image pulled.
+func openSandbox(ctx context.Context, cfg *config.Config) (*runtime.ContainerSandbox, error) {
+	sb := runtime.NewContainerSandbox(runtime.SandboxConfig{
+		Engine:      cfg.Sandbox.Engine,
+		Image:       cfg.Sandbox.Image,
+		Memory:      cfg.Sandbox.Memory,
+		CPUs:        cfg.Sandbox.CPUs,
+		PidsLimit:   cfg.Sandbox.PidsLimit,
+		Network:     cfg.Sandbox.Network,
+		IdleTimeout: time.Duration(cfg.Sandbox.IdleTimeout) * time.Second,
+		Instance:    instanceID(),
+	})
+	fmt.Printf("Preparing sandbox image %s...\n", sb.Image())
+	if err := sb.Prepare(ctx); err != nil {
+		return nil, fmt.Errorf("container isolation: %w", err)
+	}
+	return sb, nil
+}
+
+// sandboxReleaseHook removes the containers of a conversation when its turn
+// ends.
+func sandboxReleaseHook(sb *runtime.ContainerSandbox) agent.Hook {
+	return agent.HookFunc(func(ctx context.Context, ev *agent.HookEvent) error {
+		if ev.Point == agent.HookPostMessage || ev.Point == agent.HookError {
+			sb.Release(ev.ConversationID)
+		}
+		return nil
+	})
+}
@@ -3,14 +3,17 @@ package commands
 import (
 	"context"
 	"fmt"
+	"net"
 	"os"
 	"os/signal"
 	"path/filepath"
-	"syscall"
+	"strconv"
 
+	"github.com/eachlabs/klaw/internal/cluster"
 	"github.com/eachlabs/klaw/internal/config"
 	"github.com/eachlabs/klaw/internal/memory"
 	"github.com/eachlabs/klaw/internal/provider"
+	"github.com/eachlabs/klaw/internal/scheduler"
 	"github.com/eachlabs/klaw/internal/server"
 	"github.com/eachlabs/klaw/internal/skill"
 	"github.com/eachlabs/klaw/internal/tool"
@@ -20,23 +23,38 @@ import (
 var (
 	servePort     int
 	serveHost     string
+	serveListen   string
 	serveModel    string
 	serveProvider string
 )
 
 var serveCmd = &cobra.Command{
 	Use:   "serve",
-	Short: "Start OpenAI-compatible HTTP gateway",
-	Long: `Start the klaw OpenAI-compatible API server.
+	Short: "Start the HTTP API and OpenAI-compatible gateway",
+	Long: `Start the klaw HTTP server.
 
 This exposes klaw agents as an OpenAI Chat Completions API.
 Any OpenAI-compatible client can connect and use klaw agents.
 
+It also serves a REST API for the current namespace, so other services
+can integrate without shelling out:
+  POST /api/v1/dispatch          Send a message to an agent
+                                 {"agent": "coder", "message": "..."}
+  GET  /api/v1/agents[/{name}]   List or show agents
+  GET  /api/v1/jobs[/{id}]       List or show cron jobs
+  POST /api/v1/jobs/{id}/run     Run a job now
+  GET  /api/v1/sessions[/{id}]   List or show chat sessions
+  GET  /api/v1/logs              Recent messages (?channel=, ?limit=)
+
+REST API requests must send "Authorization: Bearer <key>" with a key from
+[server] api_keys in config.toml or KLAW_API_KEY.
+
 Required environment variables:
   ANTHROPIC_API_KEY or OPENROUTER_API_KEY or EACHLABS_API_KEY
 
 Examples:
   klaw serve
+  klaw serve --listen :8080
   klaw serve --port 8080
   klaw serve -p anthropic -m claude-sonnet-4-20250514`,
 	RunE: runServe,
@@ -45,6 +63,7 @@ Examples:
 func init() {
 	serveCmd.Flags().IntVar(&servePort, "port", 0, "port to listen on (default: from config or 8080)")
 	serveCmd.Flags().StringVar(&serveHost, "host", "", "host to bind to (default: from config or 127.0.0.1)")
+	serveCmd.Flags().StringVar(&serveListen, "listen", "", "address to listen on, e.g. :8080 (overrides --host and --port)")
 	serveCmd.Flags().StringVarP(&serveModel, "model", "m", "", "model to use")
 	serveCmd.Flags().StringVarP(&serveProvider, "provider", "p", "", "provider: anthropic, openrouter, eachlabs")
 	rootCmd.AddCommand(serveCmd)
@@ -107,8 +126,11 @@ func runServe(cmd *cobra.Command, args []string) error {
 	skillsDir := filepath.Join(config.ConfigDir(), "skills")
 	bootstrapSkills(skillsDir, cfg)
 
-	// Create skill loader (used by server for per-model skill resolution)
-	loader := skill.NewSkillLoader(skillsDir)
+	// Create skill loader (used by server for per-model skill resolution),
+	// resolving skills the way agents of the current namespace do
+	clusterName, namespace, _ := contextManager().GetCurrent()
+	loader := skill.NewScopedSkillLoader(skill.ScopeDirs(config.ConfigDir(), clusterName, namespace)...)
+	tools.Register(namespaceSkillTool(clusterName, namespace))
 
 	// Create memory and system prompt (base — skills are appended per-model at request time)
 	mem := memory.NewFileMemory(cfg.WorkspaceDir())
@@ -127,6 +149,16 @@ func runServe(cmd *cobra.Command, args []string) error {
 	if servePort != 0 {
 		port = servePort
 	}
+	if serveListen != "" {
+		h, p, err := net.SplitHostPort(serveListen)
+		if err != nil {
+			return fmt.Errorf("invalid --listen address %q: %w", serveListen, err)
+		}
+		if port, err = strconv.Atoi(p); err != nil {
+			return fmt.Errorf("invalid --listen port %q", p)
+		}
+		host = h
+	}
 
 	// Build OpenAI config — if config has [openai] section use it, otherwise create default
 	openaiCfg := server.OpenAIConfig{
@@ -181,8 +213,35 @@ func runServe(cmd *cobra.Command, args []string) error {
 	ctx, cancel := context.WithCancel(cmd.Context())
 	defer cancel()
 
+	// REST API for the current namespace
+	store := cluster.NewStore(config.StateDir())
+	redactor, err := newRedactor(cfg)
+	if err != nil {
+		return err
+	}
+	store.SetRedactor(redactor)
+	clusterName, namespace, ctxErr := contextManager().RequireCurrent()
+	if ctxErr == nil {
+		sched := scheduler.NewScheduler(config.StateDir() + "/scheduler")
+		_ = sched.Load()
+		rt := newDashboardRuntime(ctx, store, clusterName, namespace)
+		rt.source = "api"
+		sched.SetJobRunner(rt.runJob)
+		usage := storeUsage{store: store, cluster: clusterName, namespace: namespace, model: model, source: "api"}
+		srv.EnableAPI(server.APIConfig{
+			Keys:      cfg.Server.APIKeys,
+			Store:     store,
+			Scheduler: sched,
+			Cluster:   clusterName,
+			Namespace: namespace,
+			Dispatch: func(ctx context.Context, agentName, message string) (string, error) {
+				return rt.runOnce(ctx, agentName, message, usage)
+			},
+		})
+	}
+
 	sigCh := make(chan os.Signal, 1)
-	signal.Notify(sigCh, syscall.SIGINT, syscall.SIGTERM)
+	signal.Notify(sigCh, stopSignals...)
 
 	go func() {
 		<-sigCh
@@ -200,6 +259,14 @@ func runServe(cmd *cobra.Command, args []string) error {
 	fmt.Printf("Endpoint:  http://%s:%d/v1/chat/completions\n", host, port)
 	fmt.Printf("Models:    http://%s:%d/v1/models\n", host, port)
 	fmt.Printf("Health:    http://%s:%d/health\n", host, port)
+	switch {
+	case ctxErr != nil:
+		fmt.Println("REST API:  off (no cluster selected; run: klaw use cluster <name>)")
+	case len(cfg.Server.APIKeys) == 0:
+		fmt.Println("REST API:  locked (set [server] api_keys or KLAW_API_KEY)")
+	default:
+		fmt.Printf("REST API:  http://%s:%d/api/v1 (%s/%s)\n", host, port, clusterName, namespace)
+	}
 	fmt.Println("")
 	fmt.Println("Models:")
 	for id := range openaiCfg.Models {
@@ -0,0 +1,169 @@
+package commands
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/eachlabs/klaw/internal/provider"
+	"github.com/eachlabs/klaw/internal/session"
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	sessionCmd.AddCommand(sessionListCmd)
+	sessionCmd.AddCommand(sessionShowCmd)
+	sessionCmd.AddCommand(sessionDeleteCmd)
+	rootCmd.AddCommand(sessionCmd)
+}
+
+var sessionCmd = &cobra.Command{
+	Use:     "session",
+	Aliases: []string{"sessions"},
+	Short:   "Manage chat and dispatch sessions",
+	Long: `Manage the sessions of klaw chat and klaw dispatch --session.
+
+A dispatch session keeps the tasks and results of successive klaw dispatch
+calls with the same --session name, and each task is sent with the earlier
+ones, so that a script can run a workflow in steps.
+
+Examples:
+  klaw dispatch researcher "Find the latest AI news" --session news
+  klaw dispatch researcher "Summarize the top three" --session news
+  klaw session list
+  klaw session show news
+  klaw session delete news`,
+}
+
+var sessionListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List sessions",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		sessions, err := session.NewManager().List()
+		if err != nil {
+			return err
+		}
+		return printSessions(sessions)
+	},
+}
+
+var sessionShowCmd = &cobra.Command{
+	Use:   "show <id-or-name>",
+	Short: "Show the messages of a session",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		sess, err := session.NewManager().Find(args[0])
+		if err != nil {
+			return err
+		}
+		if structuredOutput() {
+			return printObject(sess)
+		}
+
+		fmt.Printf("ID:        %s\n", sess.ID)
+		if sess.Name != "" {
+			fmt.Printf("Name:      %s\n", sess.Name)
+		}
+		if sess.Agent != "" {
+			fmt.Printf("Agent:     %s\n", sess.Agent)
+		}
+		if sess.Model != "" {
+			fmt.Printf("Model:     %s\n", sess.Model)
+		}
+		fmt.Printf("Messages:  %d\n", len(sess.Messages))
+		fmt.Printf("Created:   %s\n", sess.CreatedAt.Format("2006-01-02 15:04"))
+		fmt.Printf("Updated:   %s\n", sess.UpdatedAt.Format("2006-01-02 15:04"))
+		for _, m := range sess.Messages {
+			if m.Content == "" {
+				continue
+			}
+			fmt.Printf("\n[%s]\n%s\n", m.Role, m.Content)
+		}
+		return nil
+	},
+}
+
+var sessionDeleteCmd = &cobra.Command{
+	Use:   "delete <id-or-name>",
+	Short: "Delete a session",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		mgr := session.NewManager()
+		sess, err := mgr.Find(args[0])
+		if err != nil {
+			return err
+		}
+		if err := mgr.Delete(sess.ID); err != nil {
+			return err
+		}
+		fmt.Printf("Session '%s' deleted.\n", args[0])
+		return nil
+	},
+}
+
+// printSessions prints sessions as a table, or as a list with -o json|yaml.
+func printSessions(sessions []*session.Session) error {
+	if structuredOutput() {
+		return printObject(sessions)
+	}
+	if len(sessions) == 0 {
+		fmt.Println("No sessions found.")
+		fmt.Println("Start a new session with: klaw chat")
+		return nil
+	}
+
+	t := newTable("ID", "NAME", "MODEL", "MESSAGES", "UPDATED").withWide("AGENT", "CREATED")
+	for _, s := range sessions {
+		t.add(s.ID, s.Name, truncateModel(s.Model, 25), strconv.Itoa(len(s.Messages)), s.UpdatedAt.Format("2006-01-02 15:04"),
+			s.Agent, s.CreatedAt.Format("2006-01-02 15:04"))
+	}
+	return t.print()
+}
+
+// dispatchSessionMaxChars bounds the earlier exchanges sent with a task of
+// a dispatch session; the oldest are left out first.
+const dispatchSessionMaxChars = 60000
+
+// openDispatchSession loads the dispatch session with the given name, or
+// starts it.
+func openDispatchSession(name, agentName string) *session.Manager {
+	mgr := session.NewManager()
+	if _, err := mgr.Find(name); err == nil {
+		return mgr
+	}
+	wd, _ := os.Getwd()
+	mgr.New("", "", agentName, "", wd)
+	mgr.SetName(name)
+	return mgr
+}
+
+// sessionTask returns the task sent for prompt: prompt, preceded by the
+// earlier exchanges of the session, since each dispatched task runs fresh.
+func sessionTask(history []provider.Message, prompt string) string {
+	var exchanges []string
+	size := 0
+	for i := len(history) - 1; i >= 0; i-- {
+		m := history[i]
+		entry := fmt.Sprintf("[%s]\n%s", m.Role, m.Content)
+		if size+len(entry) > dispatchSessionMaxChars {
+			break
+		}
+		size += len(entry)
+		exchanges = append([]string{entry}, exchanges...)
+	}
+	if len(exchanges) == 0 {
+		return prompt
+	}
+	return "Earlier tasks and results of this session, oldest first:\n\n" +
+		strings.Join(exchanges, "\n\n") + "\n\nCurrent task:\n" + prompt
+}
+
+// recordDispatch adds a completed task and its result to the session.
+func recordDispatch(mgr *session.Manager, prompt, result string) error {
+	messages := append(mgr.Messages(),
+		provider.Message{Role: "user", Content: prompt},
+		provider.Message{Role: "assistant", Content: result})
+	mgr.SetMessages(messages)
+	return mgr.ForceSave()
+}
@@ -0,0 +1,87 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/eachlabs/klaw/internal/config"
+)
+
+// defaultShutdownTimeout is how long running turns and jobs get to finish
+// when [defaults] shutdown_timeout is not set.
+const defaultShutdownTimeout = 60 * time.Second
+
+// drainer stops taking new work and waits for the work in flight, like
+// agent.Agent and scheduler.Scheduler.
+type drainer interface {
+	Drain(ctx context.Context) error
+}
+
+// shutdownTimeout returns the configured drain deadline.
+func shutdownTimeout(cfg *config.Config) time.Duration {
+	if cfg.Defaults.ShutdownTimeout > 0 {
+		return time.Duration(cfg.Defaults.ShutdownTimeout) * time.Second
+	}
+	return defaultShutdownTimeout
+}
+
+// stopSignals stop klaw: Ctrl+C, and SIGTERM, which Go also delivers on
+// Windows when the console window is closed or the user logs off.
+var stopSignals = []os.Signal{os.Interrupt, syscall.SIGTERM}
+
+// drainOnSignal shuts down gracefully on SIGINT or SIGTERM: new messages
+// and jobs are no longer taken, the turns and job runs in flight get up to
+// timeout to finish, and then cancel stops what is left. A second signal
+// cancels at once. onSignal, if set, runs when the first signal arrives.
+func drainOnSignal(cancel context.CancelFunc, timeout time.Duration, onSignal func(), drainers ...drainer) {
+	sigCh := make(chan os.Signal, 2)
+	signal.Notify(sigCh, stopSignals...)
+
+	go func() {
+		<-sigCh
+		fmt.Printf("\nShutting down: waiting up to %s for running turns and jobs (Ctrl+C again to stop now)...\n", timeout)
+		if onSignal != nil {
+			onSignal()
+		}
+
+		ctx, stop := context.WithTimeout(context.Background(), timeout)
+		defer stop()
+		done := make(chan error, 1)
+		go func() { done <- drainAll(ctx, drainers) }()
+
+		select {
+		case err := <-done:
+			if err != nil {
+				fmt.Printf("Shutdown timeout reached after %s, stopping the remaining turns and jobs\n", timeout)
+			}
+		case <-sigCh:
+			fmt.Println("Stopping now")
+		}
+		cancel()
+	}()
+}
+
+// drainAll drains the drainers in parallel and returns the first error.
+func drainAll(ctx context.Context, drainers []drainer) error {
+	var wg sync.WaitGroup
+	errs := make([]error, len(drainers))
+	for i, d := range drainers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			errs[i] = d.Drain(ctx)
+		}()
+	}
+	wg.Wait()
+	for _, err := range errs {
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
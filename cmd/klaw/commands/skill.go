@@ -14,6 +14,7 @@ import (
 
 	"github.com/eachlabs/klaw/internal/config"
 	"github.com/eachlabs/klaw/internal/skill"
+	"github.com/eachlabs/klaw/internal/tool"
 	"github.com/spf13/cobra"
 )
 
@@ -30,15 +31,22 @@ var skillCmd = &cobra.Command{
 
 Skills are SKILL.md files that teach agents how to perform tasks.
 
+Skills are installed in a scope with --scope: global (the default), the
+current cluster, or the current namespace. An agent sees the skills of its
+namespace, then of its cluster, then the global ones, the most specific
+scope winning when several have a skill of the same name.
+
 Registry: github.com/eachlabs/klaw-skills
 
 Examples:
   klaw skill list                 # List installed skills
   klaw skill browse               # Browse registry
+  klaw skill search browser       # Search the skills.sh marketplace
   klaw skill install image-gen    # Install from registry
   klaw skill push my-skill        # Push to registry (PR)
   klaw skill show web-search      # Show skill content
-  klaw skill create my-skill      # Create a new skill`,
+  klaw skill create my-skill      # Create a new skill
+  klaw skill install image-gen --scope namespace  # Only for this namespace`,
 }
 
 var skillListCmd = &cobra.Command{
@@ -53,6 +61,24 @@ var skillBrowseCmd = &cobra.Command{
 	RunE:  runSkillBrowse,
 }
 
+var skillSearchCategory string
+
+var skillSearchCmd = &cobra.Command{
+	Use:   "search [query]",
+	Short: "Search the skills.sh marketplace",
+	Long: `Search the skills.sh marketplace by name, description, or tag.
+
+Results are cached locally (ETag) and fall back to the cached or built-in
+catalog when the marketplace is unreachable.
+
+Examples:
+  klaw skill search browser
+  klaw skill search postgres --category database
+  klaw skill search --category search`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runSkillSearch,
+}
+
 var skillInstallCmd = &cobra.Command{
 	Use:   "install <skill-name>",
 	Short: "Install a skill from the registry",
@@ -98,6 +124,7 @@ var skillDeleteCmd = &cobra.Command{
 func init() {
 	skillCmd.AddCommand(skillListCmd)
 	skillCmd.AddCommand(skillBrowseCmd)
+	skillCmd.AddCommand(skillSearchCmd)
 	skillCmd.AddCommand(skillInstallCmd)
 	skillCmd.AddCommand(skillPushCmd)
 	skillCmd.AddCommand(skillShowCmd)
@@ -105,17 +132,81 @@ func init() {
 	skillCmd.AddCommand(skillEditCmd)
 	skillCmd.AddCommand(skillDeleteCmd)
 	rootCmd.AddCommand(skillCmd)
+
+	skillSearchCmd.Flags().StringVar(&skillSearchCategory, "category", "", "Filter by category (e.g. browser, search, database)")
+	for _, c := range []*cobra.Command{skillInstallCmd, skillPushCmd, skillCreateCmd, skillEditCmd, skillDeleteCmd} {
+		c.Flags().StringVar(&skillScope, "scope", skill.ScopeGlobal, "Skill scope: namespace, cluster or global")
+	}
 }
 
+var skillScope string
+
+// getSkillLoader returns a loader resolving skills the way the agents of
+// the current namespace do, or global skills only without a cluster.
 func getSkillLoader() *skill.SkillLoader {
-	return skill.NewSkillLoader(config.ConfigDir() + "/skills")
+	clusterName, namespace, _ := contextManager().GetCurrent()
+	return skill.NewScopedSkillLoader(skill.ScopeDirs(config.ConfigDir(), clusterName, namespace)...)
 }
 
-func runSkillList(cmd *cobra.Command, args []string) error {
-	loader := getSkillLoader()
-	skills, err := loader.ListSkills()
+// namespaceSkillTool returns the skill tool of the agents of a namespace,
+// which sees the skills of its scopes and installs skills in the namespace.
+func namespaceSkillTool(clusterName, namespace string) *tool.SkillTool {
+	return tool.NewScopedSkillTool(skill.ScopeDirs(config.ConfigDir(), clusterName, namespace)...)
+}
+
+// skillScopeDir returns the skills directory of a scope for the current
+// cluster and namespace.
+func skillScopeDir(scope string) (string, error) {
+	if scope == "" || scope == skill.ScopeGlobal {
+		return skill.ScopeDir(config.ConfigDir(), scope, "", "")
+	}
+	clusterName, namespace, err := contextManager().RequireCurrent()
 	if err != nil {
-		return err
+		return "", err
+	}
+	return skill.ScopeDir(config.ConfigDir(), scope, clusterName, namespace)
+}
+
+// skillTargetPath returns the SKILL.md of an existing skill for edit and
+// push: the one agents of the current namespace use, unless --scope is set.
+func skillTargetPath(cmd *cobra.Command, name string) (string, error) {
+	if !cmd.Flags().Changed("scope") {
+		return getSkillLoader().SkillPath(name), nil
+	}
+	skillsDir, err := skillScopeDir(skillScope)
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(skillsDir, name, "SKILL.md"), nil
+}
+
+func runSkillList(cmd *cobra.Command, args []string) error {
+	clusterName, namespace, _ := contextManager().GetCurrent()
+
+	type installedSkill struct {
+		name, scope, path string
+		shadowed          bool
+	}
+	var skills []installedSkill
+	seen := make(map[string]bool)
+	for _, scope := range skill.Scopes {
+		dir, err := skill.ScopeDir(config.ConfigDir(), scope, clusterName, namespace)
+		if err != nil {
+			continue
+		}
+		names, err := skill.ListDir(dir)
+		if err != nil {
+			return err
+		}
+		for _, name := range names {
+			skills = append(skills, installedSkill{
+				name:     name,
+				scope:    scope,
+				path:     filepath.Join(dir, name, "SKILL.md"),
+				shadowed: seen[name],
+			})
+			seen[name] = true
+		}
 	}
 
 	if len(skills) == 0 {
@@ -131,13 +222,15 @@ func runSkillList(cmd *cobra.Command, args []string) error {
 	fmt.Println()
 
 	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
-	_, _ = fmt.Fprintln(w, "NAME\tPATH")
-	_, _ = fmt.Fprintln(w, "----\t----")
+	_, _ = fmt.Fprintln(w, "NAME\tSCOPE\tPATH")
+	_, _ = fmt.Fprintln(w, "----\t-----\t----")
 
-	skillsDir := config.ConfigDir() + "/skills"
-	for _, name := range skills {
-		path := filepath.Join(skillsDir, name, "SKILL.md")
-		_, _ = fmt.Fprintf(w, "%s\t%s\n", name, path)
+	for _, s := range skills {
+		scope := s.scope
+		if s.shadowed {
+			scope += " (shadowed)"
+		}
+		_, _ = fmt.Fprintf(w, "%s\t%s\t%s\n", s.name, scope, s.path)
 	}
 	_ = w.Flush()
 
@@ -208,6 +301,45 @@ func runSkillBrowse(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+func runSkillSearch(cmd *cobra.Command, args []string) error {
+	query := ""
+	if len(args) > 0 {
+		query = args[0]
+	}
+	if query == "" && skillSearchCategory == "" {
+		return fmt.Errorf("provide a query or --category")
+	}
+
+	market := skill.NewMarketplace(skill.MarketplaceConfig{
+		CacheDir: filepath.Join(config.StateDir(), "cache", "marketplace"),
+	})
+	result, err := market.Search(query, skillSearchCategory)
+	if err != nil {
+		return err
+	}
+
+	if structuredOutput() {
+		return printObject(result)
+	}
+
+	if result.Offline {
+		fmt.Println("(marketplace unreachable, showing built-in catalog)")
+		fmt.Println()
+	}
+
+	if len(result.Skills) == 0 {
+		fmt.Println("No skills found.")
+		return nil
+	}
+
+	for _, s := range result.Skills {
+		fmt.Print(skill.FormatSkillCard(s))
+		fmt.Println()
+	}
+	fmt.Printf("%d result(s). Install: klaw skill install <name>\n", result.Total)
+	return nil
+}
+
 type skillIndex struct {
 	Skills []struct {
 		Name        string `json:"name"`
@@ -254,7 +386,10 @@ func runSkillInstall(cmd *cobra.Command, args []string) error {
 	name := strings.ToLower(strings.TrimSpace(args[0]))
 	name = strings.ReplaceAll(name, " ", "-")
 
-	skillsDir := config.ConfigDir() + "/skills"
+	skillsDir, err := skillScopeDir(skillScope)
+	if err != nil {
+		return err
+	}
 	skillDir := filepath.Join(skillsDir, name)
 	skillPath := filepath.Join(skillDir, "SKILL.md")
 
@@ -317,8 +452,10 @@ func downloadSkillContent(name string) ([]byte, error) {
 
 func runSkillPush(cmd *cobra.Command, args []string) error {
 	name := args[0]
-	skillsDir := config.ConfigDir() + "/skills"
-	skillPath := filepath.Join(skillsDir, name, "SKILL.md")
+	skillPath, err := skillTargetPath(cmd, name)
+	if err != nil {
+		return err
+	}
 
 	// Check if skill exists
 	if _, err := os.Stat(skillPath); os.IsNotExist(err) {
@@ -420,7 +557,10 @@ func runSkillShow(cmd *cobra.Command, args []string) error {
 
 func runSkillCreate(cmd *cobra.Command, args []string) error {
 	name := args[0]
-	skillsDir := config.ConfigDir() + "/skills"
+	skillsDir, err := skillScopeDir(skillScope)
+	if err != nil {
+		return err
+	}
 	skillDir := filepath.Join(skillsDir, name)
 	skillPath := filepath.Join(skillDir, "SKILL.md")
 
@@ -485,8 +625,10 @@ Use this skill when the user wants to:
 
 func runSkillEdit(cmd *cobra.Command, args []string) error {
 	name := args[0]
-	skillsDir := config.ConfigDir() + "/skills"
-	skillPath := filepath.Join(skillsDir, name, "SKILL.md")
+	skillPath, err := skillTargetPath(cmd, name)
+	if err != nil {
+		return err
+	}
 
 	// Check if exists
 	if _, err := os.Stat(skillPath); os.IsNotExist(err) {
@@ -513,12 +655,15 @@ func runSkillEdit(cmd *cobra.Command, args []string) error {
 
 func runSkillDelete(cmd *cobra.Command, args []string) error {
 	name := args[0]
-	skillsDir := config.ConfigDir() + "/skills"
+	skillsDir, err := skillScopeDir(skillScope)
+	if err != nil {
+		return err
+	}
 	skillDir := filepath.Join(skillsDir, name)
 
 	// Check if exists
 	if _, err := os.Stat(skillDir); os.IsNotExist(err) {
-		return fmt.Errorf("skill '%s' not found", name)
+		return fmt.Errorf("skill '%s' not found in %s scope", name, skillScope)
 	}
 
 	// Confirm deletion
@@ -6,12 +6,12 @@ import (
 	"os"
 	"os/signal"
 	"strings"
-	"syscall"
 
 	"github.com/eachlabs/klaw/internal/agent"
 	"github.com/eachlabs/klaw/internal/channel"
 	"github.com/eachlabs/klaw/internal/cluster"
 	"github.com/eachlabs/klaw/internal/config"
+	"github.com/eachlabs/klaw/internal/history"
 	"github.com/eachlabs/klaw/internal/memory"
 	"github.com/eachlabs/klaw/internal/provider"
 	"github.com/eachlabs/klaw/internal/skill"
@@ -206,8 +206,9 @@ func runSlack(cmd *cobra.Command, args []string) error {
 
 	// Load agent configuration and skills
 	store := cluster.NewStore(config.StateDir())
-	ctxMgr := cluster.NewContextManager(config.ConfigDir())
+	ctxMgr := contextManager()
 	clusterName, namespace, _ := ctxMgr.RequireCurrent()
+	tools.Register(namespaceSkillTool(clusterName, namespace))
 
 	// Get all agents and their skills
 	agents, _ := store.ListAgentBindings(clusterName, namespace)
@@ -245,64 +246,9 @@ func runSlack(cmd *cobra.Command, args []string) error {
 		systemPrompt = systemPrompt + "\n\n# Available Skills\n\n" + strings.Join(skillPrompts, "\n\n")
 	}
 
-	// Add Slack-specific instructions for concise responses
-	slackInstructions := `
-
-# Slack Communication Guidelines
-
-You are communicating through Slack. Follow these rules:
-
-1. **Be concise**: Keep responses short and to the point. No walls of text.
-2. **No tool details**: Never mention tool calls or technical operations. Just provide results.
-3. **Direct answers**: Answer directly without preamble like "Let me check that".
-4. **Use formatting**: Use Slack formatting (*bold*, bullet points) sparingly.
-
-# Scheduled Tasks (IMPORTANT)
-
-When a user mentions time-based recurring tasks like "her 5 dakikada", "every hour", "daily", etc:
-
-1. **USE cron_create tool** - This is MANDATORY for scheduled tasks
-2. Do NOT just create an agent - agents don't run automatically on a schedule
-3. Create a cron job that triggers the agent
-
-CRITICAL - Channel parameter for cron jobs:
-- Every message starts with [Context: channel=XXXXX] - this is the current Slack channel ID
-- When user says "bu kanalı", "this channel", "kanalı takip et", "monitor here" -> YOU MUST pass this channel ID to cron_create
-- Example: User in channel C0A8KUEBT3M says "her dakika bu kanalı kontrol et"
-  -> Call cron_create with channel="C0A8KUEBT3M" (from the context)
-- If user wants a general task not related to channel monitoring -> omit channel parameter
-
-Example with channel monitoring:
-User: "Her 5 dakikada bu kanaldaki mesajları analiz et"
--> cron_create with channel parameter set to current channel ID
-
-Example without channel:
-User: "Her gün saat 9'da hava durumunu söyle"
--> cron_create WITHOUT channel parameter
-
-# Agent Management
-
-When a user requests a task that requires specialized expertise:
-
-1. **FIRST check existing agents** with agent_list tool
-2. **If agent exists with same/similar name**:
-   - Ask "X agent'ı zaten var, onu güncelleyeyim mi yoksa yeni mi oluşturayım?"
-   - Or update the existing one if the request is clearly an update
-3. **If no suitable agent exists**: Create a new one
-
-# Asking Clarifying Questions (IMPORTANT)
-
-Before creating agents or cron jobs, ASK about:
-- **Evaluation criteria**: "Lead'leri neye göre değerlendirelim? (şirket büyüklüğü, sektör, teknoloji kullanımı?)"
-- **Output format**: "Sonuçları nasıl raporlayayım?"
-- **Exclusions**: Confirm exclusions user mentioned
-
-Key points:
-- Check existing agents BEFORE creating new ones
-- Use cron_create for ANY scheduled/recurring task
-- Ask 1-2 clarifying questions about evaluation criteria
-- Don't assume - ASK
-`
+	// Add Slack instructions, in the namespace's language
+	texts, language := localeCatalog(), store.NamespaceLocale(clusterName, namespace)
+	slackInstructions := texts.Text(language, "prompt.slack_guidelines", nil) + texts.Text(language, "prompt.slack_instructions", nil)
 	systemPrompt = systemPrompt + slackInstructions
 
 	// Create Slack channel
@@ -313,14 +259,46 @@ Key points:
 	if err != nil {
 		return fmt.Errorf("failed to create Slack channel: %w", err)
 	}
+	slackChan.SetLocale(texts, language)
+
+	// Conversation histories, persisted per thread
+	histories, err := history.OpenFromConfig(cfg)
+	if err != nil {
+		return fmt.Errorf("failed to open conversation history: %w", err)
+	}
+
+	// Hooks of the default agent profile
+	hooks, err := agentHooks(cfg, cfg.Defaults.Agent)
+	if err != nil {
+		return err
+	}
 
 	// Create agent
 	ag := agent.New(agent.Config{
-		Provider:     prov,
-		Channel:      slackChan,
-		Tools:        tools,
-		Memory:       mem,
-		SystemPrompt: systemPrompt,
+		Provider:      prov,
+		Channel:       withRateLimit(slackChan, cfg, "slack"),
+		Tools:         tools,
+		Memory:        mem,
+		History:       histories,
+		SystemPrompt:  systemPrompt,
+		MaxConcurrent: cfg.Defaults.MaxConcurrent,
+		MaxIterations: cfg.Defaults.MaxIterations,
+		Hooks:         hooks,
+		Secrets:       secretMasker(cfg),
+		Guardrails:    namespaceGuardrails(store, clusterName, namespace),
+		Audit:         storeAudit{store: store, cluster: clusterName, namespace: namespace, source: "slack"},
+		Transcripts:   transcriptRecorder(cfg, clusterName, namespace),
+		Locale:        texts,
+		Language:      language,
+		Context:       agent.ContextConfig{MaxContextTokens: cfg.Defaults.MaxContextTokens},
+		Cost: agent.CostConfig{
+			MaxTurnTokens:         cfg.Defaults.MaxTurnTokens,
+			MaxTurnCost:           cfg.Defaults.MaxTurnCost,
+			MaxConversationTurns:  cfg.Defaults.MaxConversationTurns,
+			MaxConversationTokens: cfg.Defaults.MaxConversationTokens,
+			MaxConversationCost:   cfg.Defaults.MaxConversationCost,
+			Handoff:               cfg.Defaults.ConversationHandoff,
+		},
 	})
 
 	// Handle signals
@@ -328,7 +306,7 @@ Key points:
 	defer cancel()
 
 	sigCh := make(chan os.Signal, 1)
-	signal.Notify(sigCh, syscall.SIGINT, syscall.SIGTERM)
+	signal.Notify(sigCh, stopSignals...)
 
 	go func() {
 		<-sigCh
@@ -1,21 +1,29 @@
 package commands
 
 import (
+	"cmp"
 	"context"
 	"fmt"
+	"log/slog"
 	"os"
-	"os/signal"
+	"path/filepath"
 	"strconv"
 	"strings"
-	"syscall"
 	"time"
 
 	"github.com/eachlabs/klaw/internal/agent"
+	"github.com/eachlabs/klaw/internal/analytics"
+	"github.com/eachlabs/klaw/internal/bus"
 	"github.com/eachlabs/klaw/internal/channel"
 	"github.com/eachlabs/klaw/internal/cluster"
 	"github.com/eachlabs/klaw/internal/config"
+	"github.com/eachlabs/klaw/internal/history"
 	"github.com/eachlabs/klaw/internal/memory"
+	"github.com/eachlabs/klaw/internal/notify"
+	"github.com/eachlabs/klaw/internal/observe"
 	"github.com/eachlabs/klaw/internal/provider"
+	"github.com/eachlabs/klaw/internal/redact"
+	"github.com/eachlabs/klaw/internal/runtime"
 	"github.com/eachlabs/klaw/internal/scheduler"
 	"github.com/eachlabs/klaw/internal/server"
 	"github.com/eachlabs/klaw/internal/skill"
@@ -24,33 +32,75 @@ import (
 )
 
 var (
-	startModel    string
-	startProvider string
+	startModel     string
+	startProvider  string
+	startRole      string
+	startJobs      bool
+	startIsolation string
 )
 
 var startCmd = &cobra.Command{
 	Use:   "start",
-	Short: "Start klaw (Slack bot + scheduler)",
+	Short: "Start klaw (channels + scheduler)",
 	Long: `Start klaw with all components:
-- Slack bot for receiving messages
+- A channel for each active channel binding of the namespace
+- Slack bot from SLACK_BOT_TOKEN and SLACK_APP_TOKEN, when set
 - Scheduler for cron jobs
 - All configured agents
-
-Required environment variables:
-  SLACK_BOT_TOKEN  - Slack bot token (xoxb-...)
-  SLACK_APP_TOKEN  - Slack app token (xapp-...)
+- The daily digest of the namespace, when set with klaw namespace digest
+- Webhooks of [[webhooks]] in config.toml, notified of cron jobs that
+  succeed or fail and of budgets exceeded
+- Intent and sentiment tagging of the message logs, when [analytics] is
+  enabled (see klaw logs stats)
+
+Channel bindings are activated with s in klaw dashboard. The connection
+state of each one is shown by klaw get channels while klaw start runs.
+
+To scale out, run the channels and the agent in separate processes
+connected by the message bus set in [bus] url (NATS or Redis):
+  --role gateway  runs the channels and publishes incoming messages
+  --role worker   runs the agent on the messages, replying to the gateway
+Workers share the messages between them. Run cron jobs in one worker with
+--jobs, and keep histories in a store the workers share. A conversation's
+messages may be handled by different workers, so approvals and stop
+requests reach its turn only with a single worker.
+
+Replicas of klaw start can instead share the channels themselves, such as
+one Slack app, with [lease] url set to a directory they share (file://) or
+Redis. Each conversation is then answered by one replica, the holder of
+its lease; when that replica stops, another takes over once the lease
+expires ([lease] ttl). Keep histories in a store the replicas share, and
+cron jobs in one of them.
+
+With --isolation container (or [sandbox] isolation), the bash commands of
+agent turns run in containers of podman or docker instead of on the host,
+with the agent's working directory mounted at /workspace and the memory,
+CPU and process limits of [sandbox]. Each conversation gets a container
+that is removed when its turn ends; the image is pulled on start when it
+is missing. The file tools keep working on the host, confined to the
+working directory.
+
+Environment variables:
+  SLACK_BOT_TOKEN  - Slack bot token (xoxb-...), optional with channel bindings
+  SLACK_APP_TOKEN  - Slack app token (xapp-...), optional with channel bindings
   ANTHROPIC_API_KEY or OPENROUTER_API_KEY
 
 Examples:
   klaw start
   klaw start -p anthropic
-  klaw start -m claude-sonnet-4-20250514`,
+  klaw start -m claude-sonnet-4-20250514
+  klaw start --role gateway
+  klaw start --role worker --jobs
+  klaw start --isolation container`,
 	RunE: runStart,
 }
 
 func init() {
 	startCmd.Flags().StringVarP(&startModel, "model", "m", "", "model to use")
 	startCmd.Flags().StringVarP(&startProvider, "provider", "p", "", "provider: anthropic, openrouter, eachlabs")
+	startCmd.Flags().StringVar(&startRole, "role", "", "run only the channels (gateway) or only the agent (worker), connected by [bus]")
+	startCmd.Flags().BoolVar(&startJobs, "jobs", false, "run cron jobs in this worker (with --role worker)")
+	startCmd.Flags().StringVar(&startIsolation, "isolation", "", "none or container: run the bash commands of agent turns in containers ([sandbox])")
 	rootCmd.AddCommand(startCmd)
 }
 
@@ -61,69 +111,60 @@ func runStart(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("failed to load config: %w", err)
 	}
 
-	// Get Slack tokens
-	botToken := os.Getenv("SLACK_BOT_TOKEN")
-	appToken := os.Getenv("SLACK_APP_TOKEN")
-
-	if botToken == "" || appToken == "" {
-		fmt.Println("ERROR: Slack tokens not set")
-		fmt.Println("")
-		fmt.Println("Set environment variables:")
-		fmt.Println("  export SLACK_BOT_TOKEN=xoxb-...")
-		fmt.Println("  export SLACK_APP_TOKEN=xapp-...")
-		return fmt.Errorf("slack tokens required")
+	switch startRole {
+	case "":
+	case "gateway":
+		return runGateway(cmd, cfg)
+	case "worker":
+	default:
+		return fmt.Errorf("unknown role: %s (use gateway or worker)", startRole)
 	}
-
-	// Determine provider
-	var prov provider.Provider
-	providerName := startProvider
-
-	if providerName == "" {
-		if os.Getenv("ANTHROPIC_API_KEY") != "" {
-			providerName = "anthropic"
-		} else if os.Getenv("OPENROUTER_API_KEY") != "" {
-			providerName = "openrouter"
-		} else if os.Getenv("EACHLABS_API_KEY") != "" {
-			providerName = "eachlabs"
-		} else if name := firstCustomProvider(cfg); name != "" {
-			providerName = name
-		} else {
-			providerName = "anthropic"
-		}
+	worker := startRole == "worker"
+	isolation, err := isolationMode(cfg, startIsolation)
+	if err != nil {
+		return err
 	}
 
-	// Determine model
-	model := startModel
-	if model == "" {
-		if provCfg, ok := cfg.Provider[providerName]; ok && provCfg.Model != "" {
-			model = provCfg.Model
-		}
-	}
-	if model == "" {
-		switch providerName {
-		case "openrouter":
-			model = "anthropic/claude-sonnet-4"
-		case "eachlabs":
-			model = "anthropic/claude-sonnet-4-5"
-		default:
-			model = "claude-sonnet-4-20250514"
-		}
-	}
+	// Determine provider and model
+	var prov provider.Provider
+	providerName, model := defaultProviderModel(cfg, startProvider, startModel)
 
-	// Create provider
+	// Create provider; agent bindings with their own model get their own
+	// client on first use
 	prov, err = buildProvider(cfg, providerName, model)
 	if err != nil {
 		return err
 	}
+	providers := newProviderPool(cfg, providerName, model, prov)
 
 	// Get context
-	ctxMgr := cluster.NewContextManager(config.ConfigDir())
+	ctxMgr := contextManager()
 	clusterName, namespace, _ := ctxMgr.RequireCurrent()
 	if clusterName == "" {
 		clusterName = "default"
 		namespace = "default"
 	}
 
+	// Traces of agent turns, when [tracing] is enabled
+	stopTracing, err := setupTracing(cmd.Context(), cfg)
+	if err != nil {
+		return err
+	}
+	defer stopTracing()
+
+	// Personal data and keys are masked in the message logs and the debug
+	// log when [redaction] is enabled
+	redactor, err := newRedactor(cfg)
+	if err != nil {
+		return err
+	}
+	logger, closeLog, err := newLogger(cfg, redactor)
+	if err != nil {
+		return err
+	}
+	defer closeLog()
+	secrets := secretMasker(cfg)
+
 	// Get working directory
 	workDir, err := os.Getwd()
 	if err != nil {
@@ -136,8 +177,96 @@ func runStart(cmd *cobra.Command, args []string) error {
 		fmt.Printf("Warning: failed to load scheduler: %v\n", err)
 	}
 
+	// Channels: the namespace's active channel bindings, and Slack from
+	// the environment. A worker gets its messages from the bus instead.
+	store := cluster.NewStore(config.StateDir())
+	store.SetRedactor(redactor)
+	var channels *channelSet
+	var agentChannel channel.Channel
+	var busPrefix string
+	if worker {
+		b, prefix, err := openBus(cfg, clusterName, namespace)
+		if err != nil {
+			return err
+		}
+		defer func() { _ = b.Close() }()
+		busPrefix = prefix
+		channels = newChannelSet(cfg, store, clusterName, namespace)
+		agentChannel = bus.NewChannel(b, prefix, busGroup(cfg))
+	} else {
+		if channels, err = openChannels(cfg, store, clusterName, namespace); err != nil {
+			return err
+		}
+		defer channels.stopped()
+		agentChannel = channels.mux
+	}
+
+	// Slack threads idle for an hour are archived with a summary, and
+	// restored when someone replies
+	channels.summarizeThreads(agent.DailySummarizer(prov))
+
 	// Create tools with shared scheduler
 	tools := tool.DefaultRegistryWithScheduler(workDir, sched)
+	tools.Register(namespaceSkillTool(clusterName, namespace))
+
+	// With container isolation bash runs in the sandbox; WithRoot below
+	// and the agent bindings mount their own directories in it
+	var sandbox *runtime.ContainerSandbox
+	if isolation == "container" {
+		if sandbox, err = openSandbox(cmd.Context(), cfg); err != nil {
+			return err
+		}
+		defer sandbox.Close()
+		tools.Register(tool.NewSandboxBash(sandbox, workDir))
+	}
+
+	// With an agents root set, the main agent's file tools are confined to
+	// a directory of its own, like those of the agent bindings
+	if dir := cfg.AgentWorkDir(clusterName, namespace, ""); dir != "" {
+		if tools, err = tools.WithRoot(dir); err != nil {
+			return err
+		}
+		workDir = dir
+	}
+
+	// Long-term memory tools (facts are kept per agent), indexed for
+	// semantic recall when a [memory] backend is configured
+	semantic, err := memory.OpenFromConfig(cfg)
+	if err != nil {
+		return fmt.Errorf("failed to open semantic memory: %w", err)
+	}
+	facts := memory.NewFactStore(cfg.WorkspaceDir())
+	facts.SetLimits(memory.LimitsFromConfig(cfg))
+	if semantic != nil {
+		defer func() { _ = semantic.Close() }()
+		facts.SetIndex(semantic)
+	}
+	for _, t := range tool.MemoryTools(facts) {
+		tools.Register(t)
+	}
+
+	// Daily summaries of each agent's conversations
+	journal, episodic := newEpisodicMemory(cfg, prov, semantic)
+	tools.Register(tool.NewMemoryHistory(episodic))
+
+	// Slack tools bound to the Slack channel running
+	for _, t := range tool.SlackTools(channels.slackAPI()) {
+		tools.Register(t)
+	}
+
+	// Agent-to-agent delegation, through the controller when configured
+	runAgent := localAgentRunner(providers, tools, clusterName, namespace, workDir)
+	tools.Register(tool.NewAgentDispatchTool(runAgent, controllerAgentRunner(cfg)))
+	for _, t := range tool.NewBackgroundTasks(workDir, runAgent).Tools() {
+		tools.Register(t)
+	}
+	tools.Register(tool.NewAgentUpdateTool(agentBootstrapFunc(prov)))
+
+	// Image generation whenever an EachLabs key is available; results are
+	// uploaded to the thread
+	if img := tool.NewImageGenerate(); img.Configured() {
+		tools.Register(img)
+	}
 
 	// Create memory
 	mem := memory.NewFileMemory(cfg.WorkspaceDir())
@@ -147,12 +276,16 @@ func runStart(cmd *cobra.Command, args []string) error {
 	if err != nil {
 		ws = &memory.Workspace{}
 	}
-	systemPrompt := memory.BuildSystemPrompt(ws)
+	// The main agent sees the namespace's shared memory; agent bindings
+	// get their own memory instead of the workspace's
+	basePrompt := memory.BuildSystemPrompt(ws) + memory.NamespaceMemoryPrompt(cmd.Context(), cfg.WorkspaceDir(), clusterName, namespace)
+	identityPrompt := memory.IdentityPrompt(ws)
 
 	// Load skills from SKILL.md files
-	store := cluster.NewStore(config.StateDir())
 	agents, _ := store.ListAgentBindings(clusterName, namespace)
-	skillLoader := skill.NewSkillLoader(config.ConfigDir() + "/skills")
+	// Namespace skills shadow cluster skills, which shadow global ones
+	skillDirs := skill.ScopeDirs(config.ConfigDir(), clusterName, namespace)
+	skillLoader := skill.NewScopedSkillLoader(skillDirs...)
 
 	// Default skills that all agents have
 	defaultSkills := []string{"find-skills"}
@@ -168,6 +301,25 @@ func runStart(cmd *cobra.Command, args []string) error {
 		}
 	}
 
+	// The main agent gets the namespace skill config only; the config of
+	// each agent binding reaches that agent's runtime alone.
+	namespaceSkillConfig, err := store.NamespaceSkillConfig(clusterName, namespace)
+	if err != nil {
+		fmt.Printf("Warning: namespace skill config: %v\n", err)
+		namespaceSkillConfig = make(map[string]map[string]string)
+	}
+
+	// Runtimes of the agent bindings for routed messages and cron runs,
+	// built on first use and kept warm. Their tools are the ones registered
+	// so far, without the skill tools of other agents added below.
+	agentBase := tool.NewRegistry()
+	for _, t := range tools.All() {
+		agentBase.Register(t)
+	}
+	runtimes := newAgentRuntimes(providers, store, agentBase, workDir, func(skills []string) string {
+		return skillLoader.GetSkillsPrompt(append(defaultSkills, skills...))
+	})
+
 	// Add agent-specific skills
 	for _, ag := range agents {
 		for _, skillName := range ag.Skills {
@@ -179,101 +331,146 @@ func runStart(cmd *cobra.Command, args []string) error {
 		}
 	}
 
-	// Load skill prompts from SKILL.md files
-	skillsPrompt := skillLoader.GetSkillsPrompt(skillNames)
-	if skillsPrompt != "" {
-		systemPrompt = systemPrompt + skillsPrompt
+	// The main agent also gets the tools of every agent's skills
+	for _, t := range withSkillTools(tool.NewRegistry(), skillNames, workDir).All() {
+		if _, exists := tools.Get(t.Name()); !exists {
+			tools.Register(t)
+		}
 	}
 
-	// Add Slack instructions
-	slackInstructions := `
-
-# Slack Communication Guidelines
-
-You are communicating through Slack. Follow these rules:
-
-1. **Be concise**: Keep responses short and to the point.
-2. **No tool details**: Never mention tool calls. Just provide results.
-3. **Direct answers**: Answer directly without preamble.
-
-# Scheduled Tasks (IMPORTANT)
-
-When a user mentions time-based recurring tasks like "her 5 dakikada", "every hour", "daily":
-
-1. **USE cron_create tool** - MANDATORY for scheduled tasks
-2. Do NOT just create an agent - agents don't run automatically
-3. Create a cron job that triggers the agent
-
-CRITICAL - Channel parameter for cron jobs:
-- Every message starts with [Context: channel=XXXXX] - this is the current Slack channel ID
-- When user says "bu kanalı", "this channel", "kanalı takip et", "monitor here" -> YOU MUST pass this channel ID to cron_create
-- Example: User in channel C0A8KUEBT3M says "her dakika bu kanalı kontrol et"
-  -> Call cron_create with channel="C0A8KUEBT3M" (from the context)
-- If user wants a general task not related to channel monitoring -> omit channel parameter
+	// Add Slack instructions, in the namespace's language; routed agents
+	// get the guidelines only
+	slackGuidelines := channels.texts.Text(channels.language, "prompt.slack_guidelines", nil)
+	slackInstructions := slackGuidelines + channels.texts.Text(channels.language, "prompt.slack_instructions", nil)
 
-Example with channel monitoring:
-User: "Her 5 dakikada bu kanaldaki mesajları analiz et"
--> cron_create with channel parameter set to current channel
-
-Example without channel:
-User: "Her gün saat 9'da hava durumunu söyle"
--> cron_create WITHOUT channel parameter
-
-# Agent Management
+	// buildSystemPrompt assembles the prompt from workspace, SKILL.md files
+	// and Slack instructions; it is re-run when skills change on disk.
+	buildSystemPrompt := func() string {
+		return basePrompt + skillLoader.GetSkillsPrompt(skillNames) + slackInstructions
+	}
+	systemPrompt := buildSystemPrompt()
 
-1. **FIRST check existing agents** with agent_list tool
-2. **If agent exists**: Ask to update or create new
-3. **If no suitable agent**: Create new one
+	// Conversation histories, persisted per thread
+	histories, err := history.OpenFromConfig(cfg)
+	if err != nil {
+		return fmt.Errorf("failed to open conversation history: %w", err)
+	}
+	defer func() { _ = history.Close(histories) }()
 
-# Clarifying Questions
+	// Conversation leases, when replicas share the channels
+	leases, err := openLeases(cfg)
+	if err != nil {
+		return err
+	}
+	if leases != nil {
+		defer func() { _ = leases.Close() }()
+	}
 
-Before creating agents or cron jobs, ASK about:
-- Evaluation criteria
-- Output format
-- Exclusions
+	// Outbound webhooks of job and budget events
+	notifier, err := notify.FromConfig(cfg)
+	if err != nil {
+		return err
+	}
+	defer notifier.Wait()
 
-# NEVER GIVE UP - Research & Learn
+	// Hooks of the default agent profile
+	hooks, err := agentHooks(cfg, cfg.Defaults.Agent)
+	if err != nil {
+		return err
+	}
 
-CRITICAL: You must NEVER say "I can't do this" or "I don't have this capability" without trying!
+	// Agent activity for klaw logs -f; the event hook goes last so that it
+	// sees what the shell hooks rewrote
+	defaultAgent := cfg.Defaults.Agent
+	if defaultAgent == "" {
+		defaultAgent = "default"
+	}
+	events := observe.NewEventHub()
+	eventHook := agent.EventHook(events, defaultAgent)
+	hooks = append(hooks, eventHook)
+
+	// Failed turns for the daily digest
+	hooks = append(hooks, agent.HookFunc(func(ctx context.Context, ev *agent.HookEvent) error {
+		if ev.Point == agent.HookError {
+			recordActivity(store, &cluster.ActivityRecord{
+				Cluster: clusterName, Namespace: namespace, Kind: cluster.ActivityTurnError, Agent: ev.Agent, Error: ev.Content,
+			})
+		}
+		return nil
+	}))
 
-When faced with a task you don't immediately know how to do:
+	// Containers of a conversation are removed when its turn ends
+	if sandbox != nil {
+		hooks = append(hooks, sandboxReleaseHook(sandbox))
+	}
 
-1. **Check your skills**: Use skill action=list to see what you know
-2. **Search for skills**: Use skill action=install name=<relevant-skill>
-3. **Research online**: Use web_fetch to learn how to do it
-4. **Create a skill**: If no skill exists, research and create one yourself
-5. **Try multiple approaches**: If one method fails, try another
-6. **Break it down**: Complex tasks can be split into smaller steps
+	// Counters and latencies for Prometheus, when [metrics] listen is set
+	var prom *observe.Prometheus
+	if cfg.Metrics.Listen != "" {
+		prom = observe.NewPrometheus()
+	}
 
-Examples:
-- "Generate an image" -> Find/install image generation skill, then use it
-- "Analyze Facebook ads" -> Check facebook-ads skill, follow its instructions
-- "Create a video" -> Search for video generation skill, install it, use it
-
-You are a capable AI that can LEARN and ADAPT. Use your tools to extend your abilities!
-`
-	systemPrompt = systemPrompt + slackInstructions
-
-	// Create Slack channel
-	slackChan, err := channel.NewSlackChannel(channel.SlackConfig{
-		BotToken: botToken,
-		AppToken: appToken,
+	// Route messages to the namespace's agents when an orchestrator is
+	// configured; each runs with its own prompt, tools, skills and model
+	router := newBindingRouter(store, clusterName, namespace, agents, prov, func(ab *cluster.AgentBinding) (*agent.Profile, error) {
+		rt, err := runtimes.get(ab)
+		if err != nil {
+			return nil, err
+		}
+		return &agent.Profile{
+			Name:         ab.Name,
+			SystemPrompt: rt.systemPrompt(cmd.Context(), cfg.WorkspaceDir(), identityPrompt) + slackGuidelines,
+			Provider:     rt.provider,
+			Model:        rt.model,
+			Tools:        rt.tools,
+			SkillConfig:  rt.skillConfig,
+		}, nil
 	})
-	if err != nil {
-		return fmt.Errorf("failed to create Slack channel: %w", err)
+	var agentRouter agent.Router
+	if router != nil {
+		agentRouter = router
 	}
 
 	// Create agent
 	ag := agent.New(agent.Config{
-		Provider:     prov,
-		Channel:      slackChan,
-		Tools:        tools,
-		Memory:       mem,
-		SystemPrompt: systemPrompt,
+		Provider:      prov,
+		Channel:       agentChannel,
+		Tools:         tools,
+		Memory:        mem,
+		History:       histories,
+		SystemPrompt:  systemPrompt,
+		SkillConfig:   namespaceSkillConfig,
+		MaxConcurrent: cfg.Defaults.MaxConcurrent,
+		MaxIterations: cfg.Defaults.MaxIterations,
+		Hooks:         hooks,
+		Router:        agentRouter,
+		Logger:        logger,
+		Secrets:       secrets,
+		Guardrails:    namespaceGuardrails(store, clusterName, namespace),
+		Audit:         storeAudit{store: store, cluster: clusterName, namespace: namespace, source: "slack"},
+		Transcripts:   transcriptRecorder(cfg, clusterName, namespace),
+		Leases:        leases,
+		LeaseOwner:    instanceID(),
+		LeaseTTL:      leaseTTL(cfg),
+		Usage:         storeUsage{store: store, cluster: clusterName, namespace: namespace, model: model, source: "slack"},
+		Prometheus:    prom,
+		Locale:        channels.texts,
+		Language:      channels.language,
+		Recall:        recallConfig(cfg, semantic),
+		Journal:       journal,
+		Context:       agent.ContextConfig{MaxContextTokens: cfg.Defaults.MaxContextTokens},
+		Cost: agent.CostConfig{
+			MaxTurnTokens:         cfg.Defaults.MaxTurnTokens,
+			MaxTurnCost:           cfg.Defaults.MaxTurnCost,
+			MaxConversationTurns:  cfg.Defaults.MaxConversationTurns,
+			MaxConversationTokens: cfg.Defaults.MaxConversationTokens,
+			MaxConversationCost:   cfg.Defaults.MaxConversationCost,
+			Handoff:               cfg.Defaults.ConversationHandoff,
+		},
 	})
 
-	// Set job runner - this runs the agent for cron jobs
-	sched.SetJobRunner(func(ctx context.Context, job *scheduler.Job) (string, error) {
+	// Job runner - this runs the agent for cron jobs
+	runJob := func(ctx context.Context, job *scheduler.Job) (string, error) {
 		fmt.Printf("\n")
 		fmt.Printf("╭─────────────────────────────────────────╮\n")
 		fmt.Printf("│  🕐 CRON JOB RUNNING                    │\n")
@@ -282,6 +479,21 @@ You are a capable AI that can LEARN and ADAPT. Use your tools to extend your abi
 		fmt.Printf("  Agent: %s\n", job.Agent)
 		fmt.Printf("  Task:  %s\n", job.Task)
 
+		jobSkillConfig := namespaceSkillConfig
+		jobTools := tools
+		jobProv := prov
+		jobModel := model
+		jobPrompt := ag.SystemPrompt()
+		if ab, err := store.GetAgentBinding(clusterName, namespace, job.Agent); err == nil {
+			rt, err := runtimes.get(ab)
+			if err != nil {
+				return "", err
+			}
+			jobSkillConfig, jobTools, jobProv, jobModel = rt.skillConfig, rt.tools, rt.provider, rt.model
+			fmt.Printf("  Model: %s (%s)\n", rt.model, rt.providerName)
+			jobPrompt = rt.systemPrompt(ctx, cfg.WorkspaceDir(), identityPrompt) + slackInstructions
+		}
+
 		// Read channel messages if configured
 		var channelID string
 		if job.Config != nil {
@@ -289,20 +501,32 @@ You are a capable AI that can LEARN and ADAPT. Use your tools to extend your abi
 		}
 
 		var messages []channel.ChannelMessage
+		var slackChan *channel.SlackChannel
 		if channelID != "" {
 			fmt.Printf("  Channel: %s\n", channelID)
 
-			// Get messages since previous run (stored by scheduler before updating LastRun)
-			since := time.Now().Add(-5 * time.Minute)
-			if prevRunStr, ok := job.Config["_previousRun"]; ok {
-				if prevUnix, err := strconv.ParseInt(prevRunStr, 10, 64); err == nil {
-					since = time.Unix(prevUnix, 0)
-				}
+			// job.Config["binding"] picks the Slack channel when several run
+			var err error
+			if slackChan, err = channels.slackChannel(job.Config["binding"]); err != nil {
+				return "", err
 			}
-			fmt.Printf("  Since: %s\n", since.Format("15:04:05"))
 
-			var err error
-			messages, err = slackChan.GetChannelHistory(channelID, since, 50)
+			// Get the messages after the last one a run processed; the first
+			// run gets those since the previous run (stored by scheduler
+			// before updating LastRun)
+			if job.Cursor != "" {
+				fmt.Printf("  After: %s\n", job.Cursor)
+				messages, err = slackChan.GetChannelHistoryAfter(channelID, job.Cursor, 50)
+			} else {
+				since := time.Now().Add(-5 * time.Minute)
+				if prevRunStr, ok := job.Config["_previousRun"]; ok {
+					if prevUnix, err := strconv.ParseInt(prevRunStr, 10, 64); err == nil {
+						since = time.Unix(prevUnix, 0)
+					}
+				}
+				fmt.Printf("  Since: %s\n", since.Format("15:04:05"))
+				messages, err = slackChan.GetChannelHistory(channelID, since, 50)
+			}
 			if err != nil {
 				fmt.Printf("  Error reading channel: %v\n", err)
 			} else {
@@ -314,20 +538,80 @@ You are a capable AI that can LEARN and ADAPT. Use your tools to extend your abi
 		}
 		fmt.Printf("\n")
 
-		// Check if we should skip already-replied messages (default: true)
-		skipReplied := job.Config == nil || job.Config["skip_replied"] != "false"
+		// Check if we should skip already-replied messages (default: true);
+		// messages after the cursor are newer than any reply of a run
+		skipReplied := job.Cursor == "" && (job.Config == nil || job.Config["skip_replied"] != "false")
 
-		// Filter out messages that already have bot replies (if enabled)
+		// Messages the job processed before, e.g. before a restart
+		seen, err := sched.Seen(job.ID)
+		if err != nil {
+			return "", fmt.Errorf("failed to read processed messages: %w", err)
+		}
+		// Messages of this run that were processed or filtered out
+		done := make(map[string]bool)
+		markSeen := func(msg channel.ChannelMessage, result string) {
+			if msg.SlackTS == "" {
+				return
+			}
+			done[msg.SlackTS] = true
+			if err := seen.Add(msg.SlackTS, result); err != nil {
+				fmt.Printf("  Warning: failed to record processed message: %v\n", err)
+			}
+		}
+
+		// The next run goes on after the messages of this one, up to the
+		// first one that failed so that it is tried again
+		defer func() {
+			var cursor string
+			for _, msg := range messages {
+				if msg.SlackTS == "" {
+					continue
+				}
+				if !done[msg.SlackTS] {
+					if cursor == "" && job.Cursor == "" {
+						// Without a cursor the next run would read from
+						// this run on, past the failed message
+						cursor = slackTSBefore(msg.SlackTS)
+					}
+					break
+				}
+				cursor = msg.SlackTS
+			}
+			if cursor == "" {
+				return
+			}
+			if err := sched.SetCursor(job.ID, cursor); err != nil {
+				fmt.Printf("  Warning: failed to save the channel cursor: %v\n", err)
+			}
+		}()
+
+		// Filter out processed messages, those of skipped users and those
+		// that already have bot replies (if enabled)
+		skipUsers := jobSkipUsers(job)
 		var newMessages []channel.ChannelMessage
-		var skippedReplied int
+		var skippedSeen, skippedUsers, skippedReplied int
 		for _, msg := range messages {
-			if skipReplied && msg.SlackTS != "" && slackChan.HasBotReply(channelID, msg.SlackTS) {
+			switch {
+			case msg.SlackTS != "" && seen.Has(msg.SlackTS):
+				skippedSeen++
+				done[msg.SlackTS] = true
+			case skipUsers[msg.User]:
+				skippedUsers++
+				done[msg.SlackTS] = true
+			case skipReplied && msg.SlackTS != "" && slackChan.HasBotReply(channelID, msg.SlackTS):
 				skippedReplied++
-				continue
+				markSeen(msg, "")
+			default:
+				newMessages = append(newMessages, msg)
 			}
-			newMessages = append(newMessages, msg)
 		}
 
+		if skippedSeen > 0 {
+			fmt.Printf("  Skipped %d messages (already processed)\n", skippedSeen)
+		}
+		if skippedUsers > 0 {
+			fmt.Printf("  Skipped %d messages (skipped users)\n", skippedUsers)
+		}
 		if skippedReplied > 0 {
 			fmt.Printf("  Skipped %d messages (already replied)\n", skippedReplied)
 		}
@@ -339,12 +623,47 @@ You are a capable AI that can LEARN and ADAPT. Use your tools to extend your abi
 
 		fmt.Printf("  New messages to process: %d\n", len(newMessages))
 
-		// Process each message individually - let the AI decide what to do
+		run := func(prompt string) (string, error) {
+			return agent.RunOnce(ctx, agent.RunOnceConfig{
+				Provider:     jobProv,
+				Tools:        jobTools,
+				SystemPrompt: jobPrompt,
+				Prompt:       prompt,
+				SkillConfig:  jobSkillConfig,
+				AgentName:    job.Agent,
+				Model:        jobModel,
+				Usage:        storeUsage{store: store, cluster: clusterName, namespace: namespace, model: jobModel, source: "job"},
+				Prometheus:   prom,
+				Secrets:      secrets,
+				Guardrails:   ag.Guardrails(),
+				Audit:        storeAudit{store: store, cluster: clusterName, namespace: namespace, source: "job"},
+				Hooks:        []agent.Hook{eventHook},
+			})
+		}
+
+		// Post as thread reply if AI decided to respond (not SKIP)
 		var results []string
-		for _, msg := range newMessages {
+		handle := func(msg channel.ChannelMessage, result string) {
+			markSeen(msg, result)
+			if result != "" && strings.TrimSpace(strings.ToUpper(result)) != "SKIP" {
+				if len(result) > 1000 {
+					result = result[:1000] + "..."
+				}
+				if msg.SlackTS != "" {
+					_ = slackChan.PostThreadReply(channelID, msg.SlackTS, result)
+					fmt.Printf("  ✓ Replied to: %s\n", msg.Text[:min(30, len(msg.Text))])
+				}
+				results = append(results, result)
+			} else {
+				fmt.Printf("  ⊘ Skipped (not relevant): %s\n", msg.Text[:min(30, len(msg.Text))])
+			}
+		}
+
+		// Process each message individually - let the AI decide what to do
+		analyze := func(msg channel.ChannelMessage) {
 			// Build prompt for this specific message
 			var prompt strings.Builder
-			prompt.WriteString("You are running as a SCHEDULED CRON JOB. Do NOT create new cron jobs or agents.\n")
+			prompt.WriteString(cronJobPreamble)
 			prompt.WriteString("Your task:\n")
 			prompt.WriteString(job.Task)
 			prompt.WriteString("\n\nMessage to process:\n")
@@ -352,49 +671,116 @@ You are a capable AI that can LEARN and ADAPT. Use your tools to extend your abi
 			prompt.WriteString("\n\nIf this message is relevant to your task, respond with your analysis. If not relevant, respond with exactly: SKIP")
 
 			// Execute with agent
-			result, err := agent.RunOnce(ctx, agent.RunOnceConfig{
-				Provider:     prov,
-				Tools:        tools,
-				SystemPrompt: systemPrompt,
-				Prompt:       prompt.String(),
-			})
+			result, err := run(prompt.String())
 			if err != nil {
 				fmt.Printf("  ❌ Error analyzing %s: %v\n", msg.Text[:min(30, len(msg.Text))], err)
-				continue
+				return
 			}
+			handle(msg, result)
+		}
 
-			// Post as thread reply if AI decided to respond (not SKIP)
-			if result != "" && strings.TrimSpace(strings.ToUpper(result)) != "SKIP" {
-				if len(result) > 1000 {
-					result = result[:1000] + "..."
+		// With a batch size, messages are analyzed in batches of one call
+		// each, and one by one when the verdicts of a batch can't be read
+		batch := jobBatchSize(job)
+		for len(newMessages) > 0 {
+			chunk := newMessages[:min(max(batch, 1), len(newMessages))]
+			newMessages = newMessages[len(chunk):]
+			if len(chunk) > 1 {
+				texts := make([]string, len(chunk))
+				for i, msg := range chunk {
+					texts[i] = msg.Text
 				}
-				if msg.SlackTS != "" {
-					_ = slackChan.PostThreadReply(channelID, msg.SlackTS, result)
-					fmt.Printf("  ✓ Replied to: %s\n", msg.Text[:min(30, len(msg.Text))])
+				output, err := run(cronJobPreamble + agent.BatchPrompt(job.Task, texts))
+				if err != nil {
+					fmt.Printf("  ❌ Error analyzing a batch of %d messages: %v\n", len(chunk), err)
+					continue
 				}
-				results = append(results, result)
-			} else {
-				fmt.Printf("  ⊘ Skipped (not relevant): %s\n", msg.Text[:min(30, len(msg.Text))])
+				verdicts, err := agent.ParseVerdicts(output, len(chunk))
+				if err == nil {
+					for i, v := range verdicts {
+						if v.Skip {
+							v.Reply = ""
+						}
+						handle(chunk[i], v.Reply)
+					}
+					continue
+				}
+				fmt.Printf("  ⚠️  Unreadable verdicts on a batch of %d messages (%v), analyzing them one by one\n", len(chunk), err)
+			}
+			for _, msg := range chunk {
+				analyze(msg)
 			}
 		}
 
 		fmt.Printf("  ✓ Completed (%d analyzed)\n", len(results))
 		return strings.Join(results, "\n---\n"), nil
+	}
+	sched.SetJobRunner(func(ctx context.Context, job *scheduler.Job) (string, error) {
+		// Jobs share the agent's workers with messages, in order of priority
+		release, err := ag.AcquireSlot(ctx, job.Priority)
+		if err != nil {
+			return "", err
+		}
+		result, err := runJob(ctx, job)
+		release()
+		prom.RecordJobRun(job.Name, err != nil)
+		rec := &cluster.ActivityRecord{Cluster: clusterName, Namespace: namespace, Kind: cluster.ActivityJobRun, Agent: job.Agent, Job: job.Name}
+		ev := notify.Event{Type: notify.EventJobSucceeded, Cluster: clusterName, Namespace: namespace, Agent: job.Agent, Job: job.Name, Result: result,
+			Message: fmt.Sprintf("Job %s of agent %s succeeded", job.Name, job.Agent)}
+		if err != nil {
+			rec.Error = err.Error()
+			ev.Type, ev.Error = notify.EventJobFailed, err.Error()
+			ev.Message = fmt.Sprintf("Job %s of agent %s failed", job.Name, job.Agent)
+		}
+		recordActivity(store, rec)
+		notifier.Notify(ev)
+		return result, err
 	})
 
-	// Handle signals
+	// Handle signals: drain the agent and the scheduler before stopping
 	ctx, cancel := context.WithCancel(cmd.Context())
 	defer cancel()
 
-	sigCh := make(chan os.Signal, 1)
-	signal.Notify(sigCh, syscall.SIGINT, syscall.SIGTERM)
+	drainOnSignal(cancel, shutdownTimeout(cfg), func() {
+		if counts := redactor.Counts(); len(counts) > 0 {
+			fmt.Printf("Redacted:  %s\n", redact.Summary(counts))
+		}
+	}, ag, sched)
+
+	// Hot-reload skills: rebuild the system prompt when a used SKILL.md changes
+	skillWatcher := skill.NewScopedWatcher(skillDirs, 2*time.Second, func(changed []string) {
+		var affected []string
+		for _, name := range changed {
+			if skillSet[name] {
+				affected = append(affected, name)
+			}
+		}
+		if len(affected) == 0 {
+			return
+		}
+		ag.SetSystemPrompt(buildSystemPrompt())
+		runtimes.reset()
+		fmt.Printf("[%s] Skills reloaded: %s\n", time.Now().Format("15:04:05"), strings.Join(affected, ", "))
+	})
+	go skillWatcher.Run(ctx)
+	if sandbox != nil {
+		go sandbox.Run(ctx)
+	}
 
 	go func() {
-		<-sigCh
-		fmt.Println("\nShutting down...")
-		cancel()
+		if err := events.Serve(ctx, eventSocketPath(clusterName, namespace)); err != nil {
+			fmt.Printf("Warning: event socket: %v\n", err)
+		}
 	}()
 
+	if prom != nil {
+		go func() {
+			if err := prom.Serve(cfg.Metrics.Listen); err != nil {
+				fmt.Printf("Warning: metrics endpoint: %v\n", err)
+			}
+		}()
+	}
+
 	// Start OpenAI-compatible gateway if enabled
 	if cfg.OpenAI.Enabled {
 		providerMap := map[string]provider.Provider{
@@ -443,13 +829,57 @@ You are a capable AI that can LEARN and ADAPT. Use your tools to extend your abi
 		fmt.Printf("OpenAI-compatible API: http://%s:%d/v1/chat/completions\n", cfg.Server.Host, cfg.Server.Port)
 	}
 
-	// Start Slack channel
-	if err := slackChan.Start(ctx); err != nil {
-		return fmt.Errorf("failed to start Slack channel: %w", err)
+	// Start channels
+	if err := agentChannel.Start(ctx); err != nil {
+		return fmt.Errorf("failed to start channels: %w", err)
+	}
+
+	// Start scheduler; of the workers, only those with --jobs run jobs
+	if !worker || startJobs {
+		_ = sched.Start(ctx)
 	}
 
-	// Start scheduler
-	_ = sched.Start(ctx)
+	// Alert, and pause cron jobs, when the namespace's budget is exceeded
+	budgets := &budgetWatcher{store: store, sched: sched, cluster: clusterName, namespace: namespace, alert: channels.slackAPI().PostMessage, notify: notifier}
+	go budgets.run(ctx, 5*time.Minute)
+
+	// Post the namespace's daily digest, when scheduled
+	digests := &digestWatcher{store: store, cluster: clusterName, namespace: namespace, post: channels.slackAPI().PostMessage}
+	go digests.run(ctx, time.Minute)
+
+	// Start, stop and restart channels as their bindings change
+	if !worker {
+		go channels.run(ctx, 10*time.Second)
+	}
+
+	// Apply changes to the namespace's guardrails while running
+	go watchGuardrails(ctx, ag, store, clusterName, namespace, 30*time.Second)
+
+	// Summarize finished days of the journal in the background
+	go episodic.Run(ctx, time.Hour, func(err error) {
+		fmt.Printf("Warning: daily summaries: %v\n", err)
+	})
+
+	// Keep facts and MEMORY.md files within the configured limits
+	compactor := memory.NewCompactor(cfg.WorkspaceDir(), facts, memory.LimitsFromConfig(cfg), agent.DailySummarizer(prov))
+	go compactor.Run(ctx, time.Hour, func(err error) {
+		fmt.Printf("Warning: memory compaction: %v\n", err)
+	})
+
+	// Tag the message logs with intents and sentiments for klaw logs stats
+	var analyticsModel string
+	if cfg.Analytics.Enabled {
+		analyticsModel = cmp.Or(cfg.Analytics.Model, model)
+		classifier, err := providers.get(cmp.Or(cfg.Analytics.Provider, providerName), analyticsModel)
+		if err != nil {
+			return fmt.Errorf("analytics: %w", err)
+		}
+		tagger := analytics.NewTagger(store, clusterName, namespace, analytics.ClassifyFunc(agent.DailySummarizer(classifier)), cfg.Analytics.Intents)
+		interval := time.Duration(cmp.Or(cfg.Analytics.Interval, 300)) * time.Second
+		go tagger.Run(ctx, interval, func(err error) {
+			fmt.Printf("Warning: message tagging: %v\n", err)
+		})
+	}
 
 	// Print startup info
 	fmt.Println("╭─────────────────────────────────────────╮")
@@ -459,6 +889,34 @@ You are a capable AI that can LEARN and ADAPT. Use your tools to extend your abi
 	fmt.Printf("Provider:  %s\n", providerName)
 	fmt.Printf("Model:     %s\n", model)
 	fmt.Printf("Namespace: %s/%s\n", clusterName, namespace)
+	if router != nil {
+		mode := router.mode
+		if router.manualOnly {
+			mode = "@agent only"
+		}
+		fmt.Printf("Routing:   %s\n", mode)
+	}
+	if semantic != nil {
+		fmt.Printf("Memory:    semantic recall (%s)\n", cfg.Memory.Backend)
+	}
+	if prom != nil {
+		fmt.Printf("Metrics:   http://%s/metrics\n", cfg.Metrics.Listen)
+	}
+	if rl := cfg.Channel["slack"].RateLimit; rl.PerUser > 0 || rl.PerChannel > 0 {
+		fmt.Printf("Limits:    %s\n", rateLimitLine(rl))
+	}
+	if guardrails := ag.Guardrails(); guardrails != nil {
+		fmt.Printf("Guards:    %s\n", guardrailsLine(guardrails))
+	}
+	if redactor != nil {
+		fmt.Printf("Redaction: %s\n", strings.Join(redactionDetectors(cfg.Redaction), ", "))
+	}
+	if sandbox != nil {
+		fmt.Printf("Isolation: containers (%s)\n", sandbox.Image())
+	}
+	if analyticsModel != "" {
+		fmt.Printf("Analytics: intent and sentiment tagging (%s)\n", analyticsModel)
+	}
 	fmt.Println("")
 
 	// Show agents
@@ -484,8 +942,21 @@ You are a capable AI that can LEARN and ADAPT. Use your tools to extend your abi
 		fmt.Println("")
 	}
 
-	fmt.Println("Slack bot active. Listening for messages...")
-	fmt.Println("Scheduler running. Cron jobs will execute automatically.")
+	if worker {
+		fmt.Printf("Worker:    %s (group %s) on %s\n", bus.InSubject(busPrefix), busGroup(cfg), redactURL(cfg.Bus.URL))
+		fmt.Println("")
+	} else {
+		printChannels(channels)
+	}
+	if leases != nil {
+		fmt.Printf("Leases:    %s as %s (ttl %s)\n", redactURL(cfg.Lease.URL), instanceID(), leaseTTL(cfg))
+		fmt.Println("")
+	}
+
+	fmt.Println("Listening for messages...")
+	if !worker || startJobs {
+		fmt.Println("Scheduler running. Cron jobs will execute automatically.")
+	}
 	fmt.Println("")
 	fmt.Println("Press Ctrl+C to stop")
 	fmt.Println("")
@@ -493,3 +964,134 @@ You are a capable AI that can LEARN and ADAPT. Use your tools to extend your abi
 	// Run agent
 	return ag.Run(ctx)
 }
+
+// cronJobPreamble opens the prompts of the channel messages a cron job
+// analyzes.
+const cronJobPreamble = "You are running as a SCHEDULED CRON JOB. Do NOT create new cron jobs or agents.\n"
+
+// slackTSBefore returns the Slack timestamp one microsecond before ts, a
+// cursor whose next read starts at ts.
+func slackTSBefore(ts string) string {
+	secs, micros, _ := strings.Cut(ts, ".")
+	n, err := strconv.ParseInt(secs+fmt.Sprintf("%06s", micros), 10, 64)
+	if err != nil || n <= 0 {
+		return ""
+	}
+	n--
+	return fmt.Sprintf("%d.%06d", n/1e6, n%1e6)
+}
+
+// defaultProviderModel picks the provider and model klaw runs with when
+// the flags leave them empty: the first provider with an API key in the
+// environment, then its configured or built-in default model.
+func defaultProviderModel(cfg *config.Config, providerName, model string) (string, string) {
+	if providerName == "" {
+		if os.Getenv("ANTHROPIC_API_KEY") != "" {
+			providerName = "anthropic"
+		} else if os.Getenv("OPENROUTER_API_KEY") != "" {
+			providerName = "openrouter"
+		} else if os.Getenv("EACHLABS_API_KEY") != "" {
+			providerName = "eachlabs"
+		} else if name := firstCustomProvider(cfg); name != "" {
+			providerName = name
+		} else {
+			providerName = "anthropic"
+		}
+	}
+
+	if model == "" {
+		if provCfg, ok := cfg.Provider[providerName]; ok && provCfg.Model != "" {
+			model = provCfg.Model
+		}
+	}
+	if model == "" {
+		switch providerName {
+		case "openrouter":
+			model = "anthropic/claude-sonnet-4"
+		case "eachlabs":
+			model = "anthropic/claude-sonnet-4-5"
+		default:
+			model = "claude-sonnet-4-20250514"
+		}
+	}
+	return providerName, model
+}
+
+// setupTracing starts exporting spans as configured in [tracing]. The
+// returned function flushes the spans not yet sent.
+func setupTracing(ctx context.Context, cfg *config.Config) (func(), error) {
+	tc := cfg.Tracing
+	if !tc.Enabled {
+		return func() {}, nil
+	}
+	shutdown, err := observe.SetupTracing(ctx, observe.TracingOptions{
+		Endpoint:    tc.Endpoint,
+		Insecure:    tc.Insecure,
+		Headers:     tc.Headers,
+		ServiceName: tc.ServiceName,
+		SampleRatio: tc.SampleRatio,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to set up tracing: %w", err)
+	}
+	return func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		_ = shutdown(ctx)
+	}, nil
+}
+
+// newLogger opens the debug log of [logging] file, redacted by r. Without
+// a file nothing is logged. The returned function closes the file.
+func newLogger(cfg *config.Config, r *redact.Redactor) (*observe.Logger, func(), error) {
+	if cfg.Logging.File == "" {
+		return nil, func() {}, nil
+	}
+	if err := os.MkdirAll(filepath.Dir(cfg.Logging.File), 0755); err != nil {
+		return nil, nil, err
+	}
+	f, err := os.OpenFile(cfg.Logging.File, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0600)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to open log file: %w", err)
+	}
+	logger := observe.NewLogger(cfg.Logging.Level, f)
+	logger.Logger = slog.New(r.Handler(logger.Handler()))
+	return logger, func() { _ = f.Close() }, nil
+}
+
+// recordActivity keeps an activity record in the cluster store.
+func recordActivity(store *cluster.Store, rec *cluster.ActivityRecord) {
+	if err := store.AppendActivity(rec); err != nil {
+		fmt.Printf("Warning: failed to record activity: %v\n", err)
+	}
+}
+
+// storeUsage keeps the usage of provider requests in the cluster store,
+// where the dashboard and usage reports read it.
+type storeUsage struct {
+	store     *cluster.Store
+	cluster   string
+	namespace string
+	model     string // for requests made with the provider's default model
+	source    string
+}
+
+func (u storeUsage) RecordUsage(agentName, model string, usage provider.Usage, cost float64) {
+	if model == "" {
+		model = u.model
+		cost = agent.EstimateCost(model, usage.InputTokens, usage.OutputTokens)
+	}
+	err := u.store.AppendUsage(&cluster.UsageRecord{
+		Cluster:      u.cluster,
+		Namespace:    u.namespace,
+		Agent:        agentName,
+		Model:        model,
+		Source:       u.source,
+		InputTokens:  usage.InputTokens,
+		OutputTokens: usage.OutputTokens,
+		Cost:         cost,
+	})
+	if err != nil {
+		fmt.Printf("Warning: failed to record usage: %v\n", err)
+	}
+}
@@ -0,0 +1,201 @@
+package commands
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/eachlabs/klaw/internal/cluster"
+	"github.com/eachlabs/klaw/internal/config"
+	"github.com/eachlabs/klaw/internal/memory"
+	"github.com/eachlabs/klaw/internal/skill"
+	"github.com/eachlabs/klaw/internal/tool"
+	"github.com/spf13/cobra"
+)
+
+var (
+	toolsAgent  string
+	toolsSchema bool
+)
+
+var toolsCmd = &cobra.Command{
+	Use:   "tools",
+	Short: "Inspect the tools available to agents",
+}
+
+var toolsListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List builtin, skill and MCP tools",
+	Long: `List the tools agents can use, with where each comes from.
+
+Without --agent, all builtin tools and the tools of every known skill are listed.
+With --agent, only the tools that agent runs with are listed.
+MCP servers of remote skills are started briefly to discover their tools.
+
+Examples:
+  klaw tools list
+  klaw tools list --agent researcher
+  klaw tools list --agent researcher --schema
+  klaw tools list --json`,
+	RunE: runToolsList,
+}
+
+func init() {
+	toolsListCmd.Flags().StringVar(&toolsAgent, "agent", "", "only show tools available to this agent")
+	toolsListCmd.Flags().BoolVar(&toolsSchema, "schema", false, "include input schemas")
+	toolsCmd.AddCommand(toolsListCmd)
+	rootCmd.AddCommand(toolsCmd)
+}
+
+type toolInfo struct {
+	Name        string          `json:"name"`
+	Source      string          `json:"source"`
+	Description string          `json:"description"`
+	Schema      json.RawMessage `json:"schema,omitempty"`
+}
+
+func runToolsList(cmd *cobra.Command, args []string) error {
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+	workDir, err := os.Getwd()
+	if err != nil {
+		workDir = "."
+	}
+
+	skillsDir := config.StateDir() + "/skills"
+	skillReg := skill.NewRegistry(skillsDir)
+
+	var ab *cluster.AgentBinding
+	var skillNames []string
+	if toolsAgent != "" {
+		ctxMgr := contextManager()
+		clusterName, namespace, err := ctxMgr.RequireCurrent()
+		if err != nil {
+			return err
+		}
+		ab, err = cluster.NewStore(config.StateDir()).GetAgentBinding(clusterName, namespace, toolsAgent)
+		if err != nil {
+			return fmt.Errorf("agent not found: %s", toolsAgent)
+		}
+		skillNames = ab.Skills
+	} else {
+		for _, s := range skillReg.List() {
+			skillNames = append(skillNames, s.Name)
+		}
+		if entries, err := os.ReadDir(filepath.Join(skillsDir, "remote")); err == nil {
+			for _, e := range entries {
+				if e.IsDir() {
+					skillNames = append(skillNames, e.Name())
+				}
+			}
+		}
+		sort.Strings(skillNames)
+	}
+
+	var tools []toolInfo
+	seen := make(map[string]bool)
+	add := func(t tool.Tool, source string) {
+		if seen[t.Name()] {
+			return
+		}
+		seen[t.Name()] = true
+		info := toolInfo{Name: t.Name(), Source: source, Description: t.Description()}
+		if toolsSchema || structuredOutput() {
+			info.Schema = t.Schema()
+		}
+		tools = append(tools, info)
+	}
+
+	for _, t := range builtinTools(cfg, workDir).All() {
+		add(t, "builtin")
+	}
+	for _, name := range skillNames {
+		skillTools, _ := skillReg.GetToolsForSkills([]string{name}, workDir)
+		for _, t := range skillTools.All() {
+			add(t, "skill:"+name)
+		}
+	}
+
+	// MCP-based skills expose their tools only once the server runs
+	mcp := skill.NewMCPManager(skillReg)
+	defer mcp.StopAll()
+	for _, name := range skillNames {
+		if _, err := skillReg.GetMCPConfig(name); err != nil {
+			continue
+		}
+		ctx, cancel := context.WithTimeout(cmd.Context(), 20*time.Second)
+		mcpTools, err := mcp.GetToolsForSkill(ctx, name)
+		cancel()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: could not list MCP tools of %s: %v\n", name, err)
+			continue
+		}
+		for _, t := range mcpTools {
+			add(t, "mcp:"+name)
+		}
+	}
+
+	sort.Slice(tools, func(i, j int) bool {
+		if tools[i].Source != tools[j].Source {
+			return tools[i].Source < tools[j].Source
+		}
+		return tools[i].Name < tools[j].Name
+	})
+
+	if structuredOutput() {
+		return printObject(tools)
+	}
+
+	if ab != nil {
+		fmt.Printf("Tools for agent %s (skills: %s)\n", ab.Name, joinOrNone(ab.Skills))
+		if ab.Policy != nil {
+			fmt.Printf("Policy: %s\n", describeToolPolicy(ab.Policy))
+		}
+		fmt.Println()
+	}
+
+	if toolsSchema {
+		for _, t := range tools {
+			fmt.Printf("%s (%s)\n", t.Name, t.Source)
+			fmt.Printf("  %s\n", strings.ReplaceAll(t.Description, "\n", "\n  "))
+			fmt.Printf("  Schema: %s\n\n", compactJSON(t.Schema))
+		}
+		return nil
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	_, _ = fmt.Fprintln(w, "TOOL\tSOURCE\tDESCRIPTION")
+	for _, t := range tools {
+		desc, _, _ := strings.Cut(t.Description, "\n")
+		_, _ = fmt.Fprintf(w, "%s\t%s\t%s\n", t.Name, t.Source, truncateStr(desc, 80))
+	}
+	return w.Flush()
+}
+
+// builtinTools returns the tools klaw registers for every agent, without
+// channel-bound tools such as slack_*.
+func builtinTools(cfg *config.Config, workDir string) *tool.Registry {
+	tools := tool.DefaultRegistry(workDir)
+	for _, t := range tool.MemoryTools(memory.NewFactStore(cfg.WorkspaceDir())) {
+		tools.Register(t)
+	}
+	tools.Register(tool.NewAgentDispatchTool(nil, nil))
+	return tools
+}
+
+func compactJSON(raw json.RawMessage) string {
+	var v interface{}
+	if err := json.Unmarshal(raw, &v); err != nil {
+		return string(raw)
+	}
+	data, _ := json.Marshal(v)
+	return string(data)
+}
@@ -0,0 +1,172 @@
+package commands
+
+import (
+	"cmp"
+	"encoding/csv"
+	"fmt"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/eachlabs/klaw/internal/cluster"
+	"github.com/eachlabs/klaw/internal/config"
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	usageCmd.Flags().StringVar(&usageGroupBy, "group-by", "agent", "Group the usage by agent, namespace or model")
+	usageCmd.Flags().StringVar(&usageSince, "since", "30d", "How far back to look: a duration such as 24h or 30d, or a date")
+	usageCmd.Flags().StringVar(&usageFormat, "format", "table", "Report format: table or csv")
+	rootCmd.AddCommand(usageCmd)
+}
+
+// --- klaw usage ---
+
+var (
+	usageGroupBy string
+	usageSince   string
+	usageFormat  string
+)
+
+var usageCmd = &cobra.Command{
+	Use:   "usage",
+	Short: "Report token usage and cost",
+	Long: `Report the token usage and estimated cost of the provider requests made
+by klaw start, chat, serve and the dashboard, grouped by agent, namespace
+or model. Grouped by agent or model, the report covers the current
+namespace; grouped by namespace, every namespace of the current cluster.
+
+Use --format csv to import the report into a spreadsheet, e.g. to allocate
+the monthly cost across teams.
+
+Examples:
+  klaw usage
+  klaw usage --group-by model --since 7d
+  klaw usage --group-by namespace --since 2024-06-01 --format csv > usage.csv`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if usageFormat != "table" && usageFormat != "csv" {
+			return fmt.Errorf("invalid --format %q: use table or csv", usageFormat)
+		}
+		key, ok := usageGroupKeys[usageGroupBy]
+		if !ok {
+			return fmt.Errorf("invalid --group-by %q: use agent, namespace or model", usageGroupBy)
+		}
+		since, err := parseSince(usageSince, time.Now())
+		if err != nil {
+			return err
+		}
+
+		store := cluster.NewStore(config.StateDir())
+		clusterName, namespace, err := contextManager().RequireCurrent()
+		if err != nil {
+			return err
+		}
+		namespaces := []string{namespace}
+		if usageGroupBy == "namespace" {
+			all, err := store.ListNamespaces(clusterName)
+			if err != nil {
+				return err
+			}
+			namespaces = nil
+			for _, ns := range all {
+				namespaces = append(namespaces, ns.Name)
+			}
+		}
+
+		var records []*cluster.UsageRecord
+		for _, ns := range namespaces {
+			recs, err := store.ListUsage(clusterName, ns, since)
+			if err != nil {
+				return err
+			}
+			records = append(records, recs...)
+		}
+		rows := groupUsage(records, key)
+
+		switch {
+		case structuredOutput():
+			return printObject(rows)
+		case usageFormat == "csv":
+			return writeUsageCSV(rows)
+		case len(rows) == 0:
+			fmt.Printf("No usage since %s.\n", since.Format("2006-01-02 15:04"))
+			return nil
+		}
+
+		var total usageRow
+		t := newTable(strings.ToUpper(usageGroupBy), "REQUESTS", "INPUT TOKENS", "OUTPUT TOKENS", "COST")
+		for _, row := range rows {
+			t.add(row.Group, strconv.Itoa(row.Requests), strconv.Itoa(row.InputTokens), strconv.Itoa(row.OutputTokens), fmt.Sprintf("$%.2f", row.Cost))
+			total.add(row)
+		}
+		t.add("TOTAL", strconv.Itoa(total.Requests), strconv.Itoa(total.InputTokens), strconv.Itoa(total.OutputTokens), fmt.Sprintf("$%.2f", total.Cost))
+		return t.print()
+	},
+}
+
+// usageGroupKeys are the values of --group-by, by the group of a record.
+var usageGroupKeys = map[string]func(*cluster.UsageRecord) string{
+	"agent":     func(rec *cluster.UsageRecord) string { return cmp.Or(rec.Agent, "(default)") },
+	"namespace": func(rec *cluster.UsageRecord) string { return rec.Namespace },
+	"model":     func(rec *cluster.UsageRecord) string { return cmp.Or(rec.Model, "(unknown)") },
+}
+
+// usageRow is the usage of a group in the report.
+type usageRow struct {
+	Group        string  `json:"group"`
+	Requests     int     `json:"requests"`
+	InputTokens  int     `json:"input_tokens"`
+	OutputTokens int     `json:"output_tokens"`
+	Cost         float64 `json:"cost"`
+}
+
+func (r *usageRow) add(o usageRow) {
+	r.Requests += o.Requests
+	r.InputTokens += o.InputTokens
+	r.OutputTokens += o.OutputTokens
+	r.Cost += o.Cost
+}
+
+// groupUsage sums the records by the group key returns, costliest group
+// first.
+func groupUsage(records []*cluster.UsageRecord, key func(*cluster.UsageRecord) string) []usageRow {
+	groups := make(map[string]*usageRow)
+	for _, rec := range records {
+		g := key(rec)
+		row, ok := groups[g]
+		if !ok {
+			row = &usageRow{Group: g}
+			groups[g] = row
+		}
+		row.add(usageRow{Requests: 1, InputTokens: rec.InputTokens, OutputTokens: rec.OutputTokens, Cost: rec.Cost})
+	}
+
+	rows := make([]usageRow, 0, len(groups))
+	for _, row := range groups {
+		rows = append(rows, *row)
+	}
+	slices.SortFunc(rows, func(a, b usageRow) int {
+		return cmp.Or(cmp.Compare(b.Cost, a.Cost), cmp.Compare(a.Group, b.Group))
+	})
+	return rows
+}
+
+// writeUsageCSV writes the report with a header row, the cost to six
+// decimals so that allocations add up.
+func writeUsageCSV(rows []usageRow) error {
+	w := csv.NewWriter(os.Stdout)
+	_ = w.Write([]string{usageGroupBy, "requests", "input_tokens", "output_tokens", "cost"})
+	for _, row := range rows {
+		_ = w.Write([]string{
+			row.Group,
+			strconv.Itoa(row.Requests),
+			strconv.Itoa(row.InputTokens),
+			strconv.Itoa(row.OutputTokens),
+			strconv.FormatFloat(row.Cost, 'f', 6, 64),
+		})
+	}
+	w.Flush()
+	return w.Error()
+}
@@ -10,6 +10,6 @@ var version = "dev"
 
 func main() {
 	if err := commands.Execute(version); err != nil {
-		os.Exit(1)
+		os.Exit(commands.ExitCode(err))
 	}
 }
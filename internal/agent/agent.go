@@ -4,17 +4,26 @@ package agent
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/eachlabs/klaw/internal/channel"
+	"github.com/eachlabs/klaw/internal/guardrail"
+	"github.com/eachlabs/klaw/internal/history"
+	"github.com/eachlabs/klaw/internal/lease"
+	"github.com/eachlabs/klaw/internal/locale"
 	"github.com/eachlabs/klaw/internal/memory"
 	"github.com/eachlabs/klaw/internal/observe"
+	"github.com/eachlabs/klaw/internal/priority"
 	"github.com/eachlabs/klaw/internal/provider"
+	"github.com/eachlabs/klaw/internal/redact"
 	"github.com/eachlabs/klaw/internal/session"
 	"github.com/eachlabs/klaw/internal/tool"
+	"go.opentelemetry.io/otel/trace"
 )
 
 // Agent coordinates the conversation between user, LLM, and tools.
@@ -26,18 +35,41 @@ type Agent struct {
 	sessionManager *session.Manager
 
 	systemPrompt  string
+	promptMu      sync.RWMutex
+	historyMu     sync.RWMutex                   // guards history
 	history       []provider.Message            // Default history for single-conversation channels
-	histories     map[string][]provider.Message  // Per-conversation histories (for multi-thread channels like Slack)
+	histories     history.Store                  // Per-conversation histories (for multi-thread channels like Slack)
+	conversations *conversationLocks // one turn at a time per conversation
 	maxTokens     int
 	maxIterations int
+	slots         *priority.Limiter              // bounds the turns of Run and the work of AcquireSlot
+	dispatcher    *dispatcher                    // set while Run is active
+	drain         chan struct{}                  // closed by Drain to stop Run taking messages
+	drainOnce     sync.Once
+	drained       chan struct{}                  // closed when Run has finished its turns after Drain
 	model         string
 	contextMgr    *ContextManager
 	costTracker   *CostTracker
+	usage         UsageRecorder
 	reflection    ReflectionConfig
 	planner       PlannerConfig
 	approval      ApprovalConfig
+	recall        RecallConfig
+	journal       *memory.Journal
+	skillConfig   map[string]map[string]string
+	agentName     string
+	hooks         hookChain
+	router        Router
 	logger        *observe.Logger
 	metrics       *observe.Metrics
+	prom          *observe.Prometheus
+	secrets       *redact.Redactor
+	guardrails    atomic.Pointer[guardrail.Engine]
+	audit         ToolAuditor
+	transcripts   TranscriptRecorder
+	leases        *leases // nil unless replicas share the channel
+	texts         *locale.Catalog
+	language      string
 }
 
 // Config holds agent configuration.
@@ -48,17 +80,36 @@ type Config struct {
 	Memory         memory.Memory
 	SessionManager *session.Manager
 	InitialHistory []provider.Message
+	History        history.Store // per-conversation histories; default: in memory
 	SystemPrompt   string
 	MaxTokens      int
 	MaxIterations  int
+	MaxConcurrent  int // conversations handled in parallel by Run; default: 8
 	Model          string
 	Context        ContextConfig
 	Cost           CostConfig
 	Reflection     ReflectionConfig
 	Planner        PlannerConfig
 	Approval       ApprovalConfig
+	Recall         RecallConfig                 // semantic memory recalled into each turn
+	Journal        *memory.Journal              // records finished exchanges for daily summaries
+	SkillConfig    map[string]map[string]string // per-skill values injected into tools
+	AgentName      string                       // agent binding name, scopes per-agent tool state
+	Hooks          []Hook                       // run around messages and tool calls, in order
+	Router         Router                       // picks the agent profile per message; default: this agent
+	Usage          UsageRecorder                // receives the usage of each provider request
 	Logger         *observe.Logger
 	Metrics        *observe.Metrics
+	Prometheus     *observe.Prometheus // records turns, tool calls and provider errors for /metrics
+	Secrets        *redact.Redactor    // masks credentials in tool results; default: the redact.Secrets detectors
+	Guardrails     *guardrail.Engine   // guardrails checked before tool calls and replies
+	Audit          ToolAuditor         // receives every tool call
+	Transcripts    TranscriptRecorder  // receives the transcript of every turn, for replay
+	Leases         lease.Store         // when set, Run answers only the conversations whose lease it holds
+	LeaseOwner     string              // identifies this agent among the replicas sharing Leases
+	LeaseTTL       time.Duration       // how long a lease lasts after a conversation's last activity; default: 2 minutes
+	Locale         *locale.Catalog     // templates of the agent's status replies; default: the built-in ones
+	Language       string              // language of the replies to messages without a channel.MetaLocale; default: en
 }
 
 // New creates a new agent.
@@ -73,6 +124,11 @@ func New(cfg Config) *Agent {
 		maxIterations = 50
 	}
 
+	maxConcurrent := cfg.MaxConcurrent
+	if maxConcurrent <= 0 {
+		maxConcurrent = 8
+	}
+
 	logger := cfg.Logger
 	if logger == nil {
 		logger = observe.Nop()
@@ -83,31 +139,55 @@ func New(cfg Config) *Agent {
 	}
 
 	// Use initial history if provided (for session resume)
-	history := cfg.InitialHistory
-	if history == nil {
-		history = make([]provider.Message, 0)
+	initialHistory := cfg.InitialHistory
+	if initialHistory == nil {
+		initialHistory = make([]provider.Message, 0)
+	}
+	histories := cfg.History
+	if histories == nil {
+		histories = history.NewMemoryStore()
 	}
 
-	return &Agent{
+	a := &Agent{
 		provider:       cfg.Provider,
 		channel:        cfg.Channel,
 		tools:          cfg.Tools,
 		memory:         cfg.Memory,
 		sessionManager: cfg.SessionManager,
 		systemPrompt:   cfg.SystemPrompt,
-		history:        history,
-		histories:      make(map[string][]provider.Message),
+		history:        initialHistory,
+		histories:      histories,
+		conversations:  newConversationLocks(),
+		drain:          make(chan struct{}),
+		drained:        make(chan struct{}),
 		maxTokens:      maxTokens,
 		maxIterations:  maxIterations,
+		slots:          priority.NewLimiter(maxConcurrent, priority.DefaultAging),
 		model:          cfg.Model,
 		contextMgr:     NewContextManager(cfg.Context),
 		costTracker:    NewCostTracker(cfg.Cost),
+		usage:          cfg.Usage,
 		reflection:     cfg.Reflection,
 		planner:        cfg.Planner,
 		approval:       cfg.Approval,
+		recall:         cfg.Recall,
+		journal:        cfg.Journal,
+		skillConfig:    cfg.SkillConfig,
+		agentName:      cfg.AgentName,
+		hooks:          cfg.Hooks,
+		router:         cfg.Router,
 		logger:         logger,
 		metrics:        metrics,
+		prom:           cfg.Prometheus,
+		secrets:        secretsOrDefault(cfg.Secrets),
+		audit:          cfg.Audit,
+		transcripts:    cfg.Transcripts,
+		texts:          cfg.Locale,
+		language:       cfg.Language,
 	}
+	a.guardrails.Store(cfg.Guardrails)
+	a.leases = newLeases(a, cfg.Leases, cfg.LeaseOwner, cfg.LeaseTTL)
+	return a
 }
 
 // Run starts the agent loop.
@@ -123,41 +203,182 @@ func (a *Agent) Run(ctx context.Context) error {
 		Content: "klaw ready. Type /help for commands, /exit to quit.\n",
 	})
 
+	// Conversations run in parallel; messages of one conversation in order
+	d := newDispatcher(a)
+	a.dispatcher = d
+	defer a.leases.releaseAll()
+
 	for {
 		select {
 		case <-ctx.Done():
 			return ctx.Err()
+		case <-a.drain:
+			// Turns in flight and messages queued behind them still run
+			d.wg.Wait()
+			close(a.drained)
+			return nil
 		case msg, ok := <-a.channel.Receive():
 			if !ok {
+				d.wg.Wait()
 				return nil
 			}
-			if err := a.handleMessage(ctx, msg); err != nil {
-				// Send error to channel so user sees it
-				_ = a.channel.Send(ctx, &channel.Message{
-					Role:    "error",
-					Content: err.Error(),
-				})
-			}
+			d.dispatch(ctx, msg)
 		}
 	}
 }
 
-func (a *Agent) handleMessage(ctx context.Context, msg *channel.Message) error {
+// Drain makes Run stop taking new messages and waits until the turns
+// already running or queued have finished, and Run has returned. If ctx is
+// done first, Drain returns its error; cancelling Run's context then stops
+// the remaining turns.
+func (a *Agent) Drain(ctx context.Context) error {
+	a.drainOnce.Do(func() { close(a.drain) })
+	select {
+	case <-a.drained:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// handleAndReport handles a message as one turn of its conversation and
+// sends any error there so the user sees it. The turn is framed by turn
+// start/end events; a cancelled turn is reported as stopped.
+func (a *Agent) handleAndReport(ctx context.Context, msg *channel.Message) {
+	ctx, span := a.startTurnSpan(ctx, msg)
+	var err error
+	defer func() { observe.EndSpan(span, err) }()
+
+	replyCtx := a.withReply(context.WithoutCancel(ctx), msg)
+	out := a.out(replyCtx)
+	_ = out.Send(replyCtx, turnEvent(channel.EventTurnStart))
+	defer func() { _ = out.Send(replyCtx, turnEvent(channel.EventTurnEnd)) }()
+
+	err = a.handleMessage(ctx, msg)
+	if err != nil && ctx.Err() == nil {
+		_ = a.hooks.run(replyCtx, &HookEvent{Point: HookError, Agent: a.agentName, ConversationID: a.getConversationID(msg), Content: err.Error()})
+	}
+	var agentErr *AgentError
+	switch {
+	case ctx.Err() != nil:
+		a.closeToolCalls(a.getConversationID(msg))
+		// Flush whatever was streamed before the stop
+		_ = out.Send(replyCtx, &channel.Message{Role: "assistant", IsDone: true})
+		_ = out.Send(replyCtx, &channel.Message{Role: "assistant", Content: a.text(msg, "agent.stopped", nil)})
+	case errors.As(err, &agentErr) && (agentErr.Code == ErrMaxIterations || agentErr.Code == ErrBudgetExceed):
+		_ = out.Send(replyCtx, &channel.Message{Role: "assistant", Content: a.limitText(msg, agentErr)})
+	case errors.As(err, &agentErr) && agentErr.Code == ErrRouting:
+		_ = out.Send(replyCtx, &channel.Message{Role: "assistant", Content: a.text(msg, "agent.routing_failed", map[string]any{"Error": agentErr.Cause})})
+	case err != nil:
+		_ = out.Send(replyCtx, &channel.Message{
+			Role:    "error",
+			Content: err.Error(),
+		})
+	}
+}
+
+func turnEvent(event string) *channel.Message {
+	return &channel.Message{Role: "system", Metadata: map[string]any{"event": event}}
+}
+
+// closeToolCalls answers tool calls left open by a cancelled turn, so the
+// conversation history stays valid for the next request.
+func (a *Agent) closeToolCalls(conversationID string) {
+	history := a.getHistory(conversationID)
+	if len(history) == 0 {
+		return
+	}
+	last := history[len(history)-1]
+	if last.Role != "assistant" || len(last.ToolCalls) == 0 {
+		return
+	}
+	for _, tc := range last.ToolCalls {
+		history = append(history, provider.Message{
+			Role: "user",
+			ToolResult: &provider.ToolResult{
+				ToolUseID: tc.ID,
+				Content:   "Cancelled by user",
+				IsError:   true,
+			},
+		})
+	}
+	a.setHistory(conversationID, history)
+}
+
+// requestApproval asks the user of a conversation to approve a tool call.
+func (a *Agent) requestApproval(ctx context.Context, conversationID string, tc provider.ToolCall) (bool, error) {
+	if a.dispatcher != nil {
+		return a.dispatcher.awaitApproval(ctx, conversationID, tc)
+	}
+	return RequestApproval(ctx, a.out(ctx), tc)
+}
+
+func (a *Agent) handleMessage(ctx context.Context, msg *channel.Message) (err error) {
 	// Get conversation ID from metadata (for per-thread history)
 	conversationID := a.getConversationID(msg)
 
+	// Turns of the same conversation run one after another
+	unlock := a.conversations.lock(conversationID)
+	defer unlock()
+
+	turnStart := time.Now()
+	turnAgent := a.agentName
+	defer func() {
+		a.prom.RecordTurn(metricsAgent(turnAgent), turnStatus(ctx, err), time.Since(turnStart))
+	}()
+
+	// Replies of this turn go to the conversation of msg, and its tools
+	// know the conversation
+	ctx = a.withReply(ctx, msg)
+	ctx = tool.WithConversationID(ctx, conversationID)
+
+	// Pick the agent that handles this turn
+	ctx, err = a.route(ctx, conversationID, msg)
+	if err != nil {
+		return err
+	}
+	p := a.profile(ctx)
+	turnAgent = p.Name
+	if p.Name != "" {
+		trace.SpanFromContext(ctx).SetAttributes(observe.AttrAgent.String(p.Name))
+	}
+
+	// Conversations closed at their limits are not answered
+	if a.costTracker.startConversationTurn(conversationID) {
+		return a.out(ctx).Send(ctx, &channel.Message{Role: "assistant", Content: a.closedNotice()})
+	}
+
 	// Get or create history for this conversation
 	history := a.getHistory(conversationID)
 
+	// Let hooks check or rewrite the message
+	ev := &HookEvent{Point: HookPreMessage, Agent: p.Name, ConversationID: conversationID, Content: msg.Content}
+	if err := a.hooks.run(ctx, ev); err != nil {
+		return &AgentError{Code: ErrHookRejected, Message: "message rejected", Cause: err}
+	}
+
+	// Add memories related to the message to this turn's system prompt
+	userContent := ev.Content
+	p.SystemPrompt += a.recallPrompt(ctx, p, userContent)
+
 	// Build message content with context
-	content := msg.Content
+	content := userContent
 	if msg.Metadata != nil {
 		// Add context info so LLM knows the current channel
 		if channelID, ok := msg.Metadata["channel"].(string); ok && channelID != "" {
-			content = fmt.Sprintf("[Context: channel=%s]\n\n%s", channelID, content)
+			if lang, _ := msg.Metadata[channel.MetaLocale].(string); lang != "" {
+				content = fmt.Sprintf("[Context: channel=%s, language=%s]\n\n%s", channelID, lang, content)
+			} else {
+				content = fmt.Sprintf("[Context: channel=%s]\n\n%s", channelID, content)
+			}
 		}
 	}
 
+	// Record the turn for replay
+	transcript := a.startTranscript(conversationID, p, history, content)
+	var reply string
+	defer func() { transcript.finish(a.transcripts, reply, err) }()
+
 	// Add user message to history
 	history = append(history, provider.Message{
 		Role:    "user",
@@ -166,7 +387,7 @@ func (a *Agent) handleMessage(ctx context.Context, msg *channel.Message) error {
 	a.setHistory(conversationID, history)
 
 	// Build tool definitions
-	toolDefs := a.buildToolDefinitions()
+	toolDefs := buildToolDefinitions(p.Tools)
 
 	// Inject planning prompt on first message if enabled
 	if a.planner.Enabled {
@@ -175,54 +396,79 @@ func (a *Agent) handleMessage(ctx context.Context, msg *channel.Message) error {
 	}
 
 	toolCallsSinceReflection := 0
+	budget := a.costTracker.newTurnBudget()
+
+	// retryOverflow compacts the history once when the provider rejects the
+	// prompt as too long, and reports whether to retry the request.
+	forcedCompaction := false
+	retryOverflow := func(err error) bool {
+		if forcedCompaction || !isContextLimitError(err) {
+			return false
+		}
+		forcedCompaction = true
+		return len(a.compactHistory(ctx, p, conversationID, history)) < len(history)
+	}
 
 	// Keep processing until we get a final response (no tool calls)
 	for iteration := 0; iteration < a.maxIterations; iteration++ {
+		// Stop between steps once the turn is cancelled
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		// Check budgets before making a provider call
+		if err := a.costTracker.CheckBudget(); err != nil {
+			return a.endTurn(conversationID, err)
+		}
+		if err := budget.check(); err != nil {
+			return a.endTurn(conversationID, err)
+		}
+		if err := a.costTracker.checkConversation(conversationID); err != nil {
+			reply = a.closeConversation(ctx, p, conversationID, toolDefs, err)
+			return nil
+		}
+
 		// Get latest history for this conversation
 		history = a.getHistory(conversationID)
 
 		// Check if context needs compaction
-		if a.contextMgr.NeedsCompaction(history) {
-			_ = a.channel.Send(ctx, &channel.Message{
-				Role:      "assistant",
-				Content:   "Compacting context...\n",
-				IsPartial: true,
-			})
-			compacted, err := a.contextMgr.Compact(ctx, a.provider, a.systemPrompt, history)
-			if err == nil {
-				history = compacted
-				a.setHistory(conversationID, history)
-			}
-		}
-
-		// Check budget before making a provider call
-		if err := a.costTracker.CheckBudget(); err != nil {
-			return err
+		if a.contextMgr.NeedsCompactionFor(conversationID, p.SystemPrompt, history) {
+			history = a.compactHistory(ctx, p, conversationID, history)
 		}
 
 		req := &provider.ChatRequest{
-			System:    a.systemPrompt,
+			System:    p.SystemPrompt,
 			Messages:  history,
 			Tools:     toolDefs,
 			MaxTokens: a.maxTokens,
 		}
+		transcript.request(req)
 
 		// Stream response
-		events, err := a.provider.Stream(ctx, req)
+		streamCtx, span := startProviderSpan(ctx, p.Provider, p.Model)
+		events, err := p.Provider.Stream(streamCtx, req)
 		if err != nil {
-			return &AgentError{Code: ErrProvider, Message: "API error", Cause: err}
+			transcript.response("", nil, nil, err)
+			observe.EndSpan(span, err)
+			a.prom.RecordProviderError(p.Provider.Name())
+			if retryOverflow(err) {
+				iteration--
+				continue
+			}
+			return providerError("API error", err)
 		}
 
 		// Collect response
 		var textContent strings.Builder
 		var toolCalls []provider.ToolCall
 		var streamErr error
+		var usage *provider.Usage
 
 		for event := range events {
 			switch event.Type {
 			case "text":
 				textContent.WriteString(event.Text)
-				_ = a.channel.Send(ctx, &channel.Message{
+				_ = a.out(ctx).Send(ctx, &channel.Message{
 					Role:      "assistant",
 					Content:   event.Text,
 					IsPartial: true,
@@ -235,26 +481,42 @@ func (a *Agent) handleMessage(ctx context.Context, msg *channel.Message) error {
 				streamErr = event.Error
 
 			case "stop":
+				usage = event.Usage
 				if event.Usage != nil {
+					setUsageAttributes(span, *event.Usage)
 					a.contextMgr.RecordUsage(*event.Usage)
-					a.costTracker.Record(a.model, event.Usage.InputTokens, event.Usage.OutputTokens)
+					a.contextMgr.RecordConversation(conversationID, len(history), event.Usage.InputTokens)
+					cost := a.costTracker.Record(p.Model, event.Usage.InputTokens, event.Usage.OutputTokens)
+					budget.add(event.Usage.InputTokens+event.Usage.OutputTokens, cost)
+					a.costTracker.recordConversation(conversationID, event.Usage.InputTokens+event.Usage.OutputTokens, cost)
 					a.metrics.RecordRequest("default", event.Usage.InputTokens, event.Usage.OutputTokens)
+					if a.usage != nil {
+						a.usage.RecordUsage(p.Name, p.Model, *event.Usage, cost)
+					}
 					a.logger.Debug("provider response",
-						"model", a.model,
+						"model", p.Model,
 						"input_tokens", event.Usage.InputTokens,
 						"output_tokens", event.Usage.OutputTokens,
 						"cost", a.costTracker.Summary(),
 					)
 				}
-				_ = a.channel.Send(ctx, &channel.Message{
+				_ = a.out(ctx).Send(ctx, &channel.Message{
 					Role:   "assistant",
 					IsDone: true,
 				})
 			}
 		}
 
+		span.SetAttributes(observe.AttrToolCalls.Int(len(toolCalls)))
+		observe.EndSpan(span, streamErr)
+		transcript.response(textContent.String(), toolCalls, usage, streamErr)
 		if streamErr != nil {
-			return &AgentError{Code: ErrProvider, Message: "stream error", Cause: streamErr}
+			a.prom.RecordProviderError(p.Provider.Name())
+			if retryOverflow(streamErr) {
+				iteration--
+				continue
+			}
+			return providerError("stream error", streamErr)
 		}
 
 		// Add assistant response to history
@@ -268,6 +530,13 @@ func (a *Agent) handleMessage(ctx context.Context, msg *channel.Message) error {
 
 		// If no tool calls, we're done
 		if len(toolCalls) == 0 {
+			reply = assistantMsg.Content
+			ev := &HookEvent{Point: HookPostMessage, Agent: p.Name, ConversationID: conversationID, Content: assistantMsg.Content}
+			if err := a.hooks.run(ctx, ev); err != nil {
+				a.logger.Warn("post_message hook failed", "conversation", conversationID, "error", err)
+			}
+			a.rememberTurn(ctx, p, conversationID, userContent, assistantMsg.Content)
+
 			// Update session with cost data and force save
 			if a.sessionManager != nil {
 				if sess := a.sessionManager.Session(); sess != nil {
@@ -295,13 +564,14 @@ func (a *Agent) handleMessage(ctx context.Context, msg *channel.Message) error {
 			a.showToolStart(ctx, tc)
 
 			if a.approval.NeedsApproval(tc.Name) {
-				approved, err := RequestApproval(ctx, a.channel, tc)
+				approved, err := a.requestApproval(ctx, conversationID, tc)
 				if err != nil {
 					return &AgentError{Code: ErrToolExec, Message: "approval request failed", Cause: err}
 				}
 				if !approved {
 					states[i].approved = false
 					states[i].result = &tool.Result{Content: "Denied by user", IsError: true}
+					auditToolCall(a.audit, a.secrets, p.Name, conversationID, tc, states[i].result, 0)
 				}
 			}
 		}
@@ -316,9 +586,17 @@ func (a *Agent) handleMessage(ctx context.Context, msg *channel.Message) error {
 			go func(idx int) {
 				defer wg.Done()
 				toolStart := time.Now()
-				states[idx].result = a.executeTool(ctx, states[idx].tc)
+				toolCtx, span := startToolSpan(ctx, states[idx].tc.Name)
+				ev := HookEvent{Agent: p.Name, ConversationID: conversationID, Tool: &states[idx].tc}
+				states[idx].result = a.hooks.executeTool(toolCtx, ev, func(tc provider.ToolCall) *tool.Result {
+					return a.executeTool(toolCtx, tc)
+				})
+				maskSecrets(a.secrets, a.logger, states[idx].tc.Name, states[idx].result)
+				endToolSpan(span, states[idx].result)
 				toolDuration := time.Since(toolStart)
+				auditToolCall(a.audit, a.secrets, p.Name, conversationID, states[idx].tc, states[idx].result, toolDuration)
 				a.metrics.RecordToolCall("default", states[idx].tc.Name)
+				a.prom.RecordTool(states[idx].tc.Name, states[idx].result.IsError, toolDuration)
 				a.logger.Debug("tool executed",
 					"tool", states[idx].tc.Name,
 					"duration_ms", toolDuration.Milliseconds(),
@@ -331,6 +609,7 @@ func (a *Agent) handleMessage(ctx context.Context, msg *channel.Message) error {
 		// Phase 3: Collect results in original order
 		for _, s := range states {
 			a.showToolResult(ctx, s.result)
+			transcript.tool(s.tc, s.result)
 			history = append(history, provider.Message{
 				Role: "user",
 				ToolResult: &provider.ToolResult{
@@ -353,10 +632,163 @@ func (a *Agent) handleMessage(ctx context.Context, msg *channel.Message) error {
 		// Continue loop to get next response after tool results
 	}
 
-	return &AgentError{
+	return a.endTurn(conversationID, &AgentError{
 		Code:    ErrMaxIterations,
 		Message: fmt.Sprintf("reached maximum iterations (%d)", a.maxIterations),
+	})
+}
+
+// endTurn ends a turn that ran into a limit. The limit note is added to the
+// history so the model knows its work was cut short when the user replies.
+func (a *Agent) endTurn(conversationID string, err error) error {
+	history := a.getHistory(conversationID)
+	history = append(history, provider.Message{
+		Role:    "assistant",
+		Content: limitNote(err),
+	})
+	a.setHistory(conversationID, history)
+	return err
+}
+
+// limitText is the reply to msg for a turn stopped by a limit, in the
+// user's language.
+func (a *Agent) limitText(msg *channel.Message, err *AgentError) string {
+	key := "agent.limit_budget"
+	if err.Code == ErrMaxIterations {
+		key = "agent.limit_steps"
+	}
+	return a.text(msg, key, map[string]any{"Reason": err.Message})
+}
+
+// text renders a status reply to msg in the language of its user, or else
+// in the agent's language.
+func (a *Agent) text(msg *channel.Message, key string, data any) string {
+	lang, _ := msg.Metadata[channel.MetaLocale].(string)
+	if lang == "" || !a.texts.Has(lang) {
+		lang = a.language
+	}
+	return a.texts.Text(lang, key, data)
+}
+
+// limitNote is the note added to the history for a turn stopped by a
+// limit. It is for the model, so it is not localized.
+func limitNote(err error) string {
+	limit, reason := "budget", err.Error()
+	var agentErr *AgentError
+	if errors.As(err, &agentErr) {
+		reason = agentErr.Message
+		if agentErr.Code == ErrMaxIterations {
+			limit = "step limit"
+		}
+	}
+	return fmt.Sprintf("I hit my %s for this turn (%s) and stopped. Reply to let me continue.", limit, reason)
+}
+
+// closeConversation ends a conversation that reached its limits: instead
+// of answering, the model sums the conversation up and ends it politely,
+// or hands it to a human. It returns the closing message.
+func (a *Agent) closeConversation(ctx context.Context, p *Profile, conversationID string, tools []provider.ToolDefinition, limit error) string {
+	a.costTracker.closeConversation(conversationID)
+	history := a.getHistory(conversationID)
+	handoff := a.costTracker.config.Handoff
+
+	var text strings.Builder
+	resp, err := p.Provider.Chat(ctx, &provider.ChatRequest{
+		System:    p.SystemPrompt + "\n\n" + closingPrompt(limit, handoff),
+		Messages:  history,
+		Tools:     tools,
+		MaxTokens: a.maxTokens,
+	})
+	if err != nil {
+		a.logger.Warn("failed to sum up closed conversation", "conversation", conversationID, "error", err)
+	} else {
+		cost := a.costTracker.Record(p.Model, resp.Usage.InputTokens, resp.Usage.OutputTokens)
+		if a.usage != nil {
+			a.usage.RecordUsage(p.Name, p.Model, resp.Usage, cost)
+		}
+		for _, block := range resp.Content {
+			if block.Type == "text" {
+				text.WriteString(block.Text)
+			}
+		}
+	}
+	closing := strings.TrimSpace(text.String())
+	if closing == "" {
+		closing = a.closedNotice()
+	} else if handoff != "" && !strings.Contains(closing, handoff) {
+		closing += "\n\n" + handoff
+	}
+
+	a.setHistory(conversationID, append(history, provider.Message{Role: "assistant", Content: closing}))
+	_ = a.out(ctx).Send(ctx, &channel.Message{Role: "assistant", Content: closing})
+	return closing
+}
+
+// closingPrompt asks the model for the last message of a conversation that
+// reached its limits.
+func closingPrompt(limit error, handoff string) string {
+	reason := limit.Error()
+	var agentErr *AgentError
+	if errors.As(limit, &agentErr) {
+		reason = agentErr.Message
+	}
+	prompt := fmt.Sprintf("This conversation has reached its limit (%s) and ends with your next message. "+
+		"Do not continue the work or call tools. Briefly sum up what was asked, what was done and what is left, ", reason)
+	if handoff != "" {
+		return prompt + fmt.Sprintf("for the person taking over, then politely tell the user that %s will take it from here.", handoff)
+	}
+	return prompt + "then politely tell the user the conversation ends here and that they can start a new one to continue."
+}
+
+// closedNotice is the reply to messages of a closed conversation.
+func (a *Agent) closedNotice() string {
+	if handoff := a.costTracker.config.Handoff; handoff != "" {
+		return fmt.Sprintf("This conversation has reached its limit; %s has been asked to take over.", handoff)
+	}
+	return "This conversation has reached its limit. Start a new one to continue."
+}
+
+// compactHistory summarizes the oldest turns of a conversation and stores
+// the shortened history. On failure the history is returned unchanged.
+func (a *Agent) compactHistory(ctx context.Context, p *Profile, conversationID string, history []provider.Message) []provider.Message {
+	_ = a.out(ctx).Send(ctx, &channel.Message{
+		Role:      "assistant",
+		Content:   "Compacting context...\n",
+		IsPartial: true,
+	})
+	compacted, err := a.contextMgr.Compact(ctx, p.Provider, p.SystemPrompt, history)
+	if err != nil {
+		a.logger.Warn("context compaction failed", "conversation", conversationID, "error", err)
+		return history
+	}
+	a.logger.Debug("context compacted",
+		"conversation", conversationID,
+		"messages_before", len(history),
+		"messages_after", len(compacted),
+	)
+	a.contextMgr.ResetConversation(conversationID)
+	a.setHistory(conversationID, compacted)
+	return compacted
+}
+
+// providerError wraps a provider failure, flagging context overflows.
+func providerError(message string, err error) *AgentError {
+	if isContextLimitError(err) {
+		return &AgentError{Code: ErrContextLimit, Message: "conversation exceeds the model context", Cause: err}
+	}
+	return &AgentError{Code: ErrProvider, Message: message, Cause: err}
+}
+
+// isContextLimitError reports whether a provider error means the prompt
+// did not fit the model's context window.
+func isContextLimitError(err error) bool {
+	msg := strings.ToLower(err.Error())
+	for _, s := range []string{"prompt is too long", "context_length_exceeded", "maximum context length", "context window", "too many tokens"} {
+		if strings.Contains(msg, s) {
+			return true
+		}
 	}
+	return false
 }
 
 // getConversationID extracts a unique conversation identifier from message metadata
@@ -382,18 +814,26 @@ func (a *Agent) getConversationID(msg *channel.Message) string {
 // getHistory returns the history for a specific conversation
 func (a *Agent) getHistory(conversationID string) []provider.Message {
 	if conversationID == "default" {
+		a.historyMu.RLock()
+		defer a.historyMu.RUnlock()
 		return a.history
 	}
-	if history, ok := a.histories[conversationID]; ok {
-		return history
+	msgs, err := a.histories.Get(conversationID)
+	if err != nil {
+		a.logger.Warn("failed to load conversation history", "conversation", conversationID, "error", err)
+	}
+	if msgs == nil {
+		return make([]provider.Message, 0)
 	}
-	return make([]provider.Message, 0)
+	return msgs
 }
 
 // setHistory sets the history for a specific conversation
 func (a *Agent) setHistory(conversationID string, history []provider.Message) {
 	if conversationID == "default" {
+		a.historyMu.Lock()
 		a.history = history
+		a.historyMu.Unlock()
 		// Sync to session manager if available
 		if a.sessionManager != nil {
 			a.sessionManager.SetMessages(history)
@@ -401,7 +841,9 @@ func (a *Agent) setHistory(conversationID string, history []provider.Message) {
 		}
 		return
 	}
-	a.histories[conversationID] = history
+	if err := a.histories.Set(conversationID, history); err != nil {
+		a.logger.Warn("failed to save conversation history", "conversation", conversationID, "error", err)
+	}
 }
 
 func (a *Agent) showToolStart(ctx context.Context, tc provider.ToolCall) {
@@ -413,7 +855,7 @@ func (a *Agent) showToolStart(ctx context.Context, tc provider.ToolCall) {
 			toolDesc = fmt.Sprintf("bash: %s", truncate(params.Command, 60))
 		}
 	}
-	_ = a.channel.Send(ctx, &channel.Message{
+	_ = a.out(ctx).Send(ctx, &channel.Message{
 		Role:      "assistant",
 		Content:   fmt.Sprintf("\n╭─ %s\n", toolDesc),
 		IsPartial: true,
@@ -422,7 +864,7 @@ func (a *Agent) showToolStart(ctx context.Context, tc provider.ToolCall) {
 
 func (a *Agent) showToolResult(ctx context.Context, result *tool.Result) {
 	if result.IsError {
-		_ = a.channel.Send(ctx, &channel.Message{
+		_ = a.out(ctx).Send(ctx, &channel.Message{
 			Role:      "assistant",
 			Content:   fmt.Sprintf("│ ERROR: %s\n╰─\n", truncate(result.Content, 500)),
 			IsPartial: true,
@@ -438,26 +880,44 @@ func (a *Agent) showToolResult(ctx context.Context, result *tool.Result) {
 			_, _ = fmt.Fprintf(&output, "│ %s\n", line)
 		}
 		output.WriteString("╰─\n")
-		_ = a.channel.Send(ctx, &channel.Message{
+		_ = a.out(ctx).Send(ctx, &channel.Message{
 			Role:      "assistant",
 			Content:   output.String(),
 			IsPartial: true,
 		})
 	}
+
+	if len(result.Attachments) > 0 {
+		attachments := make([]channel.Attachment, 0, len(result.Attachments))
+		for _, att := range result.Attachments {
+			attachments = append(attachments, channel.Attachment{Name: att.Name, MimeType: att.MimeType, Data: att.Data})
+		}
+		_ = a.out(ctx).Send(ctx, &channel.Message{
+			Role:        "assistant",
+			IsPartial:   true,
+			Attachments: attachments,
+		})
+	}
 }
 
 func (a *Agent) executeTool(ctx context.Context, tc provider.ToolCall) *tool.Result {
-	t, ok := a.tools.Get(tc.Name)
+	p := a.profile(ctx)
+	t, ok := p.Tools.Get(tc.Name)
 	if !ok {
 		return &tool.Result{
 			Content: fmt.Sprintf("unknown tool: %s", tc.Name),
 			IsError: true,
 		}
 	}
+	if blocked := checkToolGuardrails(a.Guardrails(), a.logger, tc, t); blocked != nil {
+		return blocked
+	}
 
 	// Execute with timeout
 	ctx, cancel := context.WithTimeout(ctx, 2*time.Minute)
 	defer cancel()
+	ctx = tool.WithSkillConfig(ctx, p.SkillConfig)
+	ctx = tool.WithAgentName(ctx, p.Name)
 
 	result, err := t.Execute(ctx, tc.Input)
 	if err != nil {
@@ -470,8 +930,8 @@ func (a *Agent) executeTool(ctx context.Context, tc provider.ToolCall) *tool.Res
 	return result
 }
 
-func (a *Agent) buildToolDefinitions() []provider.ToolDefinition {
-	tools := a.tools.All()
+func buildToolDefinitions(registry *tool.Registry) []provider.ToolDefinition {
+	tools := registry.All()
 	defs := make([]provider.ToolDefinition, len(tools))
 
 	for i, t := range tools {
@@ -494,23 +954,43 @@ func (a *Agent) RunOnce(ctx context.Context) error {
 		if !ok {
 			return nil
 		}
-		return a.handleMessage(ctx, msg)
+		ctx, span := a.startTurnSpan(ctx, msg)
+		err := a.handleMessage(ctx, msg)
+		observe.EndSpan(span, err)
+		return err
 	}
 }
 
+// SystemPrompt returns the current system prompt.
+func (a *Agent) SystemPrompt() string {
+	a.promptMu.RLock()
+	defer a.promptMu.RUnlock()
+	return a.systemPrompt
+}
+
+// SetSystemPrompt replaces the system prompt; it applies from the next turn.
+func (a *Agent) SetSystemPrompt(prompt string) {
+	a.promptMu.Lock()
+	a.systemPrompt = prompt
+	a.promptMu.Unlock()
+}
+
 // ClearHistory clears the conversation history.
 func (a *Agent) ClearHistory() {
+	a.historyMu.Lock()
 	a.history = make([]provider.Message, 0)
+	a.historyMu.Unlock()
+	a.contextMgr.ResetConversation("default")
 }
 
 // History returns the current conversation history.
 func (a *Agent) History() []provider.Message {
-	return a.history
+	return a.getHistory("default")
 }
 
 // HistoryJSON returns the conversation history as JSON.
 func (a *Agent) HistoryJSON() ([]byte, error) {
-	return json.MarshalIndent(a.history, "", "  ")
+	return json.MarshalIndent(a.getHistory("default"), "", "  ")
 }
 
 func truncate(s string, max int) string {
@@ -530,10 +1010,35 @@ type RunOnceConfig struct {
 	Prompt        string
 	MaxTokens     int
 	MaxIterations int
+	SkillConfig   map[string]map[string]string
+	AgentName     string
+	Model         string              // model of Provider, for usage records
+	Usage         UsageRecorder       // receives the usage of each provider request
+	Prometheus    *observe.Prometheus // records tool calls and provider errors for /metrics
+	Secrets       *redact.Redactor    // masks credentials in tool results; default: the redact.Secrets detectors
+	Guardrails    *guardrail.Engine   // guardrails checked before tool calls and the result
+	Audit         ToolAuditor         // receives every tool call
+	Hooks         []Hook
+
+	// OutputSchema, when set, makes the result a JSON document matching
+	// this JSON schema. Invalid replies are sent back for repair up to
+	// SchemaRetries times (default 2).
+	OutputSchema  json.RawMessage
+	SchemaRetries int
 }
 
 // RunOnce runs an agent with a single prompt and returns the result.
 func RunOnce(ctx context.Context, cfg RunOnceConfig) (string, error) {
+	ctx, span := observe.Tracer().Start(ctx, "klaw.run", trace.WithAttributes(observe.AttrAgent.String(cfg.AgentName)))
+	result, err := runOnce(ctx, cfg)
+	if err != nil && ctx.Err() == nil {
+		_ = hookChain(cfg.Hooks).run(ctx, &HookEvent{Point: HookError, Agent: cfg.AgentName, Content: err.Error()})
+	}
+	observe.EndSpan(span, err)
+	return result, err
+}
+
+func runOnce(ctx context.Context, cfg RunOnceConfig) (string, error) {
 	maxTokens := cfg.MaxTokens
 	if maxTokens == 0 {
 		maxTokens = 8192
@@ -542,6 +1047,11 @@ func RunOnce(ctx context.Context, cfg RunOnceConfig) (string, error) {
 	if maxIterations == 0 {
 		maxIterations = 20
 	}
+	schemaRetries := cfg.SchemaRetries
+	if schemaRetries == 0 {
+		schemaRetries = 2
+	}
+	secrets := secretsOrDefault(cfg.Secrets)
 
 	// Build tool definitions
 	tools := cfg.Tools.All()
@@ -554,24 +1064,66 @@ func RunOnce(ctx context.Context, cfg RunOnceConfig) (string, error) {
 		}
 	}
 
+	ctx = tool.WithSkillConfig(ctx, cfg.SkillConfig)
+	ctx = tool.WithAgentName(ctx, cfg.AgentName)
+
+	hooks := hookChain(cfg.Hooks)
+	ev := &HookEvent{Point: HookPreMessage, Agent: cfg.AgentName, Content: cfg.Prompt}
+	if err := hooks.run(ctx, ev); err != nil {
+		return "", &AgentError{Code: ErrHookRejected, Message: "prompt rejected", Cause: err}
+	}
+
 	// Build messages
+	prompt := ev.Content
+	if len(cfg.OutputSchema) > 0 {
+		prompt += StructuredOutputPrompt(cfg.OutputSchema)
+	}
 	messages := []provider.Message{
-		{Role: "user", Content: cfg.Prompt},
+		{Role: "user", Content: prompt},
 	}
 
 	var result strings.Builder
 
+	// finish reports whether text completes the run. Output that does not
+	// match the schema is sent back for repair while retries remain.
+	finish := func(text string) (bool, error) {
+		if len(cfg.OutputSchema) == 0 {
+			result.WriteString(text)
+			return true, nil
+		}
+		valid, err := ValidateOutput(cfg.OutputSchema, text)
+		if err == nil {
+			result.WriteString(valid)
+			return true, nil
+		}
+		if schemaRetries == 0 {
+			return true, &AgentError{Code: ErrInvalidOutput, Message: "output does not match schema", Cause: err}
+		}
+		schemaRetries--
+		messages = append(messages, provider.Message{Role: "user", Content: RepairPrompt(text, err)})
+		return false, nil
+	}
+	done := false
+
 	for i := 0; i < maxIterations; i++ {
 		// Call provider
-		resp, err := cfg.Provider.Chat(ctx, &provider.ChatRequest{
+		chatCtx, span := startProviderSpan(ctx, cfg.Provider, cfg.Model)
+		resp, err := cfg.Provider.Chat(chatCtx, &provider.ChatRequest{
 			System:    cfg.SystemPrompt,
 			Messages:  messages,
 			Tools:     toolDefs,
 			MaxTokens: maxTokens,
 		})
 		if err != nil {
+			observe.EndSpan(span, err)
+			cfg.Prometheus.RecordProviderError(cfg.Provider.Name())
 			return "", fmt.Errorf("chat failed: %w", err)
 		}
+		setUsageAttributes(span, resp.Usage)
+		span.End()
+		if cfg.Usage != nil {
+			cfg.Usage.RecordUsage(cfg.AgentName, cfg.Model, resp.Usage, EstimateCost(cfg.Model, resp.Usage.InputTokens, resp.Usage.OutputTokens))
+		}
 
 		// Process response
 		var textContent strings.Builder
@@ -597,8 +1149,15 @@ func RunOnce(ctx context.Context, cfg RunOnceConfig) (string, error) {
 
 		// If no tool calls, we're done
 		if len(toolCalls) == 0 {
-			result.WriteString(textContent.String())
-			break
+			ok, err := finish(textContent.String())
+			if err != nil {
+				return "", err
+			}
+			if ok {
+				done = true
+				break
+			}
+			continue
 		}
 
 		// Execute tools in parallel
@@ -615,20 +1174,28 @@ func RunOnce(ctx context.Context, cfg RunOnceConfig) (string, error) {
 			wg.Add(1)
 			go func(idx int, tc provider.ToolCall) {
 				defer wg.Done()
-				t, ok := cfg.Tools.Get(tc.Name)
-				if !ok {
-					results[idx].content = fmt.Sprintf("Tool not found: %s", tc.Name)
-					results[idx].isError = true
-					return
-				}
-				toolResult, err := t.Execute(ctx, tc.Input)
-				if err != nil {
-					results[idx].content = fmt.Sprintf("Error: %v", err)
-					results[idx].isError = true
-				} else {
-					results[idx].content = toolResult.Content
-					results[idx].isError = toolResult.IsError
-				}
+				toolStart := time.Now()
+				toolCtx, span := startToolSpan(ctx, tc.Name)
+				toolResult := hooks.executeTool(toolCtx, HookEvent{Agent: cfg.AgentName, Tool: &tc}, func(tc provider.ToolCall) *tool.Result {
+					t, ok := cfg.Tools.Get(tc.Name)
+					if !ok {
+						return &tool.Result{Content: fmt.Sprintf("Tool not found: %s", tc.Name), IsError: true}
+					}
+					if blocked := checkToolGuardrails(cfg.Guardrails, nil, tc, t); blocked != nil {
+						return blocked
+					}
+					r, err := t.Execute(toolCtx, tc.Input)
+					if err != nil {
+						return &tool.Result{Content: fmt.Sprintf("Error: %v", err), IsError: true}
+					}
+					return r
+				})
+				maskSecrets(secrets, nil, tc.Name, toolResult)
+				endToolSpan(span, toolResult)
+				auditToolCall(cfg.Audit, secrets, cfg.AgentName, "", tc, toolResult, time.Since(toolStart))
+				cfg.Prometheus.RecordTool(tc.Name, toolResult.IsError, time.Since(toolStart))
+				results[idx].content = toolResult.Content
+				results[idx].isError = toolResult.IsError
 			}(j, tc)
 		}
 		wg.Wait()
@@ -645,12 +1212,23 @@ func RunOnce(ctx context.Context, cfg RunOnceConfig) (string, error) {
 			})
 		}
 
-		// Check stop reason
-		if resp.StopReason == "end_turn" {
+		// Check stop reason; structured output waits for a reply to the
+		// tool results
+		if resp.StopReason == "end_turn" && len(cfg.OutputSchema) == 0 {
 			result.WriteString(textContent.String())
+			done = true
 			break
 		}
 	}
 
-	return result.String(), nil
+	if !done && len(cfg.OutputSchema) > 0 {
+		return "", &AgentError{
+			Code:    ErrInvalidOutput,
+			Message: fmt.Sprintf("no structured output after %d iterations", maxIterations),
+		}
+	}
+
+	// Post-message hooks may only observe the result
+	_ = hooks.run(ctx, &HookEvent{Point: HookPostMessage, Agent: cfg.AgentName, Content: result.String()})
+	return checkReplyGuardrails(cfg.Guardrails, result.String()), nil
 }
@@ -6,9 +6,12 @@ import (
 	"errors"
 	"fmt"
 	"strings"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/eachlabs/klaw/internal/channel"
+	"github.com/eachlabs/klaw/internal/lease"
 	"github.com/eachlabs/klaw/internal/provider"
 	"github.com/eachlabs/klaw/internal/tool"
 )
@@ -595,6 +598,133 @@ func TestHandleMessage_MaxIterations(t *testing.T) {
 	if agentErr.Code != ErrMaxIterations {
 		t.Errorf("expected ErrMaxIterations, got %s", agentErr.Code)
 	}
+
+	// The limit note keeps the history valid for the next message
+	history := ag.History()
+	if last := history[len(history)-1]; last.Role != "assistant" || !strings.Contains(last.Content, "step limit") {
+		t.Errorf("expected limit note at end of history, got %+v", last)
+	}
+}
+
+func TestHandleMessage_TurnBudget(t *testing.T) {
+	reg := tool.NewRegistry()
+	reg.Register(&echoTool{})
+
+	for name, cost := range map[string]CostConfig{
+		"tokens": {MaxTurnTokens: 40},          // 15 tokens per call
+		"cost":   {MaxTurnCost: 0.000_000_001}, // any call
+	} {
+		t.Run(name, func(t *testing.T) {
+			ch := newTestChannel()
+			ag := New(Config{Provider: &infiniteToolProvider{}, Channel: ch, Tools: reg, Cost: cost})
+
+			ag.handleAndReport(context.Background(), &channel.Message{Role: "user", Content: "loop forever"})
+
+			var note string
+			for len(ch.sent) > 0 {
+				if msg := <-ch.sent; strings.HasPrefix(msg.Content, "I hit my budget") {
+					note = msg.Content
+				}
+			}
+			if note == "" {
+				t.Fatal("expected a budget reply")
+			}
+
+			// Each call's tool results are kept; the next turn starts fresh
+			history := ag.History()
+			if last := history[len(history)-1]; last.Content != note {
+				t.Errorf("expected budget note at end of history, got %+v", last)
+			}
+			if len(history) > 2*3+2 {
+				t.Errorf("turn ran past its budget: %d messages", len(history))
+			}
+		})
+	}
+}
+
+func TestHandleMessage_ConversationLimits(t *testing.T) {
+	for name, cost := range map[string]CostConfig{
+		"turns":  {MaxConversationTurns: 2},
+		"tokens": {MaxConversationTokens: 200}, // 150 tokens per call
+		"cost":   {MaxConversationCost: 0.002}, // $0.00105 per call
+	} {
+		t.Run(name, func(t *testing.T) {
+			cost.Handoff = "@oncall"
+			ch := newTestChannel()
+			prov := &mockChatProvider{resp: &provider.ChatResponse{Content: []provider.ContentBlock{{Type: "text", Text: "Hello!"}}}}
+			ag := New(Config{Provider: prov, Channel: ch, Tools: tool.NewRegistry(), Model: "claude-sonnet-4-20250514", Cost: cost})
+
+			thread := map[string]any{"channel": "C1", "thread_ts": "1.1"}
+			send := func(content string) {
+				t.Helper()
+				if err := ag.handleMessage(context.Background(), &channel.Message{Role: "user", Content: content, Metadata: thread}); err != nil {
+					t.Fatalf("handleMessage(%q) error: %v", content, err)
+				}
+			}
+			send("one")
+			send("two")
+			if n := len(ag.getHistory("C1:1.1")); n != 4 {
+				t.Fatalf("expected two answered turns, got %d messages", n)
+			}
+
+			// The third turn sums up and hands the conversation over
+			send("three")
+			history := ag.getHistory("C1:1.1")
+			if last := history[len(history)-1]; len(history) != 6 || last.Content != "Hello!\n\n@oncall" {
+				t.Fatalf("expected a closing message mentioning the handoff, got %+v", history)
+			}
+
+			// Later messages get a notice and leave the history alone
+			for len(ch.sent) > 0 {
+				<-ch.sent
+			}
+			send("four")
+			if n := len(ag.getHistory("C1:1.1")); n != 6 {
+				t.Errorf("closed conversation should not grow, got %d messages", n)
+			}
+			if msg := <-ch.sent; !strings.Contains(msg.Content, "@oncall has been asked to take over") {
+				t.Errorf("expected the closed notice, got %q", msg.Content)
+			}
+
+			// Other conversations are not affected
+			if err := ag.handleMessage(context.Background(), &channel.Message{Role: "user", Content: "hi", Metadata: map[string]any{"channel": "C1", "thread_ts": "2.2"}}); err != nil {
+				t.Fatal(err)
+			}
+			if n := len(ag.getHistory("C1:2.2")); n != 2 {
+				t.Errorf("expected another conversation to be answered, got %d messages", n)
+			}
+		})
+	}
+}
+
+// usageLog records the usage reported to a UsageRecorder.
+type usageLog struct {
+	records []string
+}
+
+func (u *usageLog) RecordUsage(agentName, model string, usage provider.Usage, cost float64) {
+	u.records = append(u.records, fmt.Sprintf("%s %s %d/%d %.6f", agentName, model, usage.InputTokens, usage.OutputTokens, cost))
+}
+
+func TestHandleMessage_RecordsUsage(t *testing.T) {
+	usage := &usageLog{}
+	ag := New(Config{
+		Provider:  &mockChatProvider{resp: &provider.ChatResponse{Content: []provider.ContentBlock{{Type: "text", Text: "Hello!"}}}},
+		Channel:   newTestChannel(),
+		Tools:     tool.NewRegistry(),
+		Model:     "claude-sonnet-4-20250514",
+		AgentName: "support",
+		Usage:     usage,
+	})
+
+	if err := ag.handleMessage(context.Background(), &channel.Message{Role: "user", Content: "hi"}); err != nil {
+		t.Fatalf("handleMessage error: %v", err)
+	}
+	// 100 input and 50 output tokens at $3/$15 per million
+	want := "support claude-sonnet-4-20250514 100/50 0.001050"
+	if len(usage.records) != 1 || usage.records[0] != want {
+		t.Errorf("usage records = %q, want [%q]", usage.records, want)
+	}
 }
 
 func TestHandleMessage_StreamError(t *testing.T) {
@@ -698,6 +828,383 @@ func TestHandleMessage_ConversationID(t *testing.T) {
 	}
 }
 
+func TestHandleMessage_ConcurrentConversations(t *testing.T) {
+	ch := newTestChannel()
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		for {
+			select {
+			case <-ch.sent:
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	ag := New(Config{
+		Provider: &slowProvider{mockChatProvider{resp: &provider.ChatResponse{
+			Content: []provider.ContentBlock{{Type: "text", Text: "ok"}},
+		}}},
+		Channel: ch,
+		Tools:   tool.NewRegistry(),
+	})
+
+	threads := []string{"1.1", "2.2", "3.3"}
+	const turns = 10
+	var wg sync.WaitGroup
+	for _, ts := range threads {
+		for i := 0; i < turns; i++ {
+			wg.Add(1)
+			go func(ts string) {
+				defer wg.Done()
+				_ = ag.handleMessage(context.Background(), &channel.Message{
+					Role:     "user",
+					Content:  "hi",
+					Metadata: map[string]any{"channel": "C1", "thread_ts": ts},
+				})
+			}(ts)
+		}
+	}
+	wg.Wait()
+
+	for _, ts := range threads {
+		if got := len(ag.getHistory("C1:" + ts)); got != 2*turns {
+			t.Errorf("thread %s: expected %d messages, got %d", ts, 2*turns, got)
+		}
+	}
+}
+
+func TestRun_ConversationsInParallel(t *testing.T) {
+	ch := newTestChannel()
+	release := make(chan struct{})
+	prov := &gatedProvider{gate: release}
+
+	ag := New(Config{Provider: prov, Channel: ch, Tools: tool.NewRegistry()})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() { _ = ag.Run(ctx) }()
+	<-ch.sent // welcome
+
+	// The slow thread blocks in the provider; the fast one must still finish
+	ch.incoming <- &channel.Message{Role: "user", Content: "slow", Metadata: map[string]any{"channel": "C1", "thread_ts": "1.1"}}
+	ch.incoming <- &channel.Message{Role: "user", Content: "fast", Metadata: map[string]any{"channel": "C1", "thread_ts": "2.2"}}
+
+	deadline := time.After(2 * time.Second)
+	for {
+		select {
+		case msg := <-ch.sent:
+			if msg.IsDone {
+				if msg.Metadata["thread_ts"] != "2.2" {
+					t.Fatalf("expected the fast thread to finish first, got %v", msg.Metadata)
+				}
+				close(release)
+				return
+			}
+		case <-deadline:
+			t.Fatal("fast conversation was blocked by the slow one")
+		}
+	}
+}
+
+func TestRun_HighPriorityBypassesWorkerLimit(t *testing.T) {
+	ch := newTestChannel()
+	release := make(chan struct{})
+	defer close(release)
+	prov := &gatedProvider{gate: release}
+
+	ag := New(Config{Provider: prov, Channel: ch, Tools: tool.NewRegistry(), MaxConcurrent: 1})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() { _ = ag.Run(ctx) }()
+	<-ch.sent // welcome
+
+	// The slow thread takes the only worker; the alert must not wait for it
+	ch.incoming <- &channel.Message{Role: "user", Content: "slow", Metadata: map[string]any{"channel": "C1", "thread_ts": "1.1"}}
+
+	deadline := time.After(2 * time.Second)
+	for {
+		select {
+		case msg := <-ch.sent:
+			if msg.Metadata["event"] == channel.EventTurnStart && msg.Metadata["thread_ts"] == "1.1" {
+				ch.incoming <- &channel.Message{Role: "user", Content: "normal", Metadata: map[string]any{"channel": "C1", "thread_ts": "2.2"}}
+				ch.incoming <- &channel.Message{Role: "user", Content: "alert", Metadata: map[string]any{"channel": "C1", "thread_ts": "3.3", channel.MetaPriority: channel.PriorityHigh}}
+			}
+			if msg.IsDone {
+				if msg.Metadata["thread_ts"] != "3.3" {
+					t.Fatalf("expected the high-priority thread to finish first, got %v", msg.Metadata)
+				}
+				return
+			}
+		case <-deadline:
+			t.Fatal("high-priority message waited for a worker")
+		}
+	}
+}
+
+func TestRun_ApprovalRoutedToConversation(t *testing.T) {
+	ch := newTestChannel()
+	calls := 0
+	prov := &sequentialProvider{
+		callCount: &calls,
+		responses: []*provider.ChatResponse{
+			{Content: []provider.ContentBlock{{Type: "tool_use", ToolUse: &provider.ToolCall{ID: "t1", Name: "echo", Input: json.RawMessage(`{"msg":"ran"}`)}}}},
+			{Content: []provider.ContentBlock{{Type: "text", Text: "done"}}},
+		},
+	}
+	tools := tool.NewRegistry()
+	tools.Register(&echoTool{})
+
+	ag := New(Config{
+		Provider: prov,
+		Channel:  ch,
+		Tools:    tools,
+		Approval: ApprovalConfig{Enabled: true, RequireApproval: []string{"echo"}},
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() { _ = ag.Run(ctx) }()
+	<-ch.sent // welcome
+
+	route := map[string]any{"channel": "C1", "thread_ts": "1.1"}
+	ch.incoming <- &channel.Message{Role: "user", Content: "run echo", Metadata: route}
+
+	deadline := time.After(2 * time.Second)
+	for {
+		select {
+		case msg := <-ch.sent:
+			if strings.Contains(msg.Content, "requires approval") {
+				if msg.Metadata["thread_ts"] != "1.1" {
+					t.Errorf("approval prompt not routed to its thread: %v", msg.Metadata)
+				}
+				ch.incoming <- &channel.Message{Role: "user", Content: "yes", Metadata: route}
+			}
+			if msg.IsDone && calls == 2 {
+				history := ag.getHistory("C1:1.1")
+				if len(history) != 4 || history[2].ToolResult == nil || history[2].ToolResult.Content != "ran" {
+					t.Errorf("expected approved tool run, got %+v", history)
+				}
+				return
+			}
+		case <-deadline:
+			t.Fatal("turn did not complete")
+		}
+	}
+}
+
+func TestRun_StopConversation(t *testing.T) {
+	ch := newTestChannel()
+	prov := &gatedProvider{gate: make(chan struct{})}
+
+	ag := New(Config{Provider: prov, Channel: ch, Tools: tool.NewRegistry()})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() { _ = ag.Run(ctx) }()
+	<-ch.sent // welcome
+
+	ch.incoming <- &channel.Message{Role: "user", Content: "slow", Metadata: map[string]any{"channel": "C1", "thread_ts": "1.1"}}
+
+	var replies []string
+	deadline := time.After(2 * time.Second)
+	for {
+		select {
+		case msg := <-ch.sent:
+			switch {
+			case msg.Metadata["event"] == channel.EventTurnStart:
+				// Stopping another thread leaves the turn running
+				ch.incoming <- &channel.Message{Role: "user", Metadata: map[string]any{"channel": "C1", "thread_ts": "2.2", "command": channel.CommandStop}}
+				// A stop without a thread covers every thread of the channel
+				ch.incoming <- &channel.Message{Role: "user", Metadata: map[string]any{"channel": "C1", "command": channel.CommandStop}}
+			case msg.Metadata["event"] == channel.EventTurnEnd:
+				if msg.Metadata["thread_ts"] != "1.1" {
+					t.Errorf("turn end not routed to its thread: %v", msg.Metadata)
+				}
+				want := []string{"Nothing is running to stop.", "⏹ Stopped."}
+				if strings.Join(replies, "|") != strings.Join(want, "|") {
+					t.Errorf("expected replies %q, got %q", want, replies)
+				}
+				return
+			case msg.Content != "" && !msg.IsPartial:
+				replies = append(replies, msg.Content)
+			}
+		case <-deadline:
+			t.Fatal("turn was not stopped")
+		}
+	}
+}
+
+func TestRun_DrainFinishesInFlightTurns(t *testing.T) {
+	ch := newTestChannel()
+	release := make(chan struct{})
+	prov := &gatedProvider{gate: release}
+
+	ag := New(Config{Provider: prov, Channel: ch, Tools: tool.NewRegistry()})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	ran := make(chan error, 1)
+	go func() { ran <- ag.Run(ctx) }()
+	<-ch.sent // welcome
+
+	ch.incoming <- &channel.Message{Role: "user", Content: "slow", Metadata: map[string]any{"channel": "C1", "thread_ts": "1.1"}}
+	for msg := range ch.sent {
+		if msg.Metadata["event"] == channel.EventTurnStart {
+			break
+		}
+	}
+
+	drained := make(chan error, 1)
+	go func() { drained <- ag.Drain(context.Background()) }()
+	go func() {
+		for range ch.sent {
+		}
+	}()
+	select {
+	case <-drained:
+		t.Fatal("Drain returned while a turn was running")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	close(release)
+	select {
+	case err := <-drained:
+		if err != nil {
+			t.Fatal(err)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("Drain did not return after the turn finished")
+	}
+	if err := <-ran; err != nil {
+		t.Errorf("Run = %v, want nil after a drain", err)
+	}
+	if history := ag.getHistory("C1:1.1"); len(history) != 2 || history[1].Content != "ok" {
+		t.Errorf("expected the turn to complete, got %+v", history)
+	}
+}
+
+func TestRun_LeasedConversationsAnsweredByOneReplica(t *testing.T) {
+	leases := lease.NewMemoryStore()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	type replica struct {
+		ag  *Agent
+		ch  *testChannel
+		ran chan error
+	}
+	start := func(owner string) replica {
+		ch := newTestChannel()
+		ag := New(Config{
+			Provider:   &gatedProvider{},
+			Channel:    ch,
+			Tools:      tool.NewRegistry(),
+			Leases:     leases,
+			LeaseOwner: owner,
+		})
+		r := replica{ag: ag, ch: ch, ran: make(chan error, 1)}
+		go func() { r.ran <- ag.Run(ctx) }()
+		<-ch.sent // welcome
+		return r
+	}
+	a, b := start("a"), start("b")
+
+	msg := func(content string) *channel.Message {
+		return &channel.Message{Role: "user", Content: content, Metadata: map[string]any{"channel": "C1", "thread_ts": "1.1"}}
+	}
+	// turnEnded waits for the end of a turn on ch.
+	turnEnded := func(ch *testChannel) {
+		t.Helper()
+		timeout := time.After(2 * time.Second)
+		for ended := false; !ended; {
+			select {
+			case m := <-ch.sent:
+				ended = m.Metadata["event"] == channel.EventTurnEnd
+			case <-timeout:
+				t.Fatal("no turn ended")
+			}
+		}
+	}
+
+	// a gets the first message first and takes the lease; both then get
+	// every message, as from a shared Slack app
+	a.ch.incoming <- msg("one")
+	turnEnded(a.ch)
+	b.ch.incoming <- msg("one")
+	a.ch.incoming <- msg("two")
+	b.ch.incoming <- msg("two")
+	turnEnded(a.ch)
+	time.Sleep(20 * time.Millisecond)
+	if n := len(a.ag.getHistory("C1:1.1")); n != 4 {
+		t.Fatalf("expected a to answer both messages, got %d messages", n)
+	}
+	if n := len(b.ag.getHistory("C1:1.1")); n != 0 || len(b.ch.sent) != 0 {
+		t.Fatalf("expected b to leave the conversation to a, got %d messages", n)
+	}
+
+	// Stopping a releases its leases, and b takes the conversation over
+	if err := a.ag.Drain(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	<-a.ran
+	b.ch.incoming <- msg("three")
+	turnEnded(b.ch)
+	if n := len(b.ag.getHistory("C1:1.1")); n != 2 {
+		t.Fatalf("expected b to answer after a stopped, got %d messages", n)
+	}
+}
+
+func TestCloseToolCalls(t *testing.T) {
+	ag := New(Config{Provider: &infiniteToolProvider{}, Channel: newTestChannel(), Tools: tool.NewRegistry()})
+	ag.setHistory("C1", []provider.Message{
+		{Role: "user", Content: "run it"},
+		{Role: "assistant", ToolCalls: []provider.ToolCall{{ID: "t1", Name: "bash"}, {ID: "t2", Name: "read"}}},
+	})
+
+	ag.closeToolCalls("C1")
+
+	history := ag.getHistory("C1")
+	if len(history) != 4 || history[3].ToolResult == nil || history[3].ToolResult.ToolUseID != "t2" || !history[3].ToolResult.IsError {
+		t.Fatalf("expected cancelled results for both calls, got %+v", history)
+	}
+
+	ag.closeToolCalls("C1")
+	if len(ag.getHistory("C1")) != 4 {
+		t.Error("closed history should be left unchanged")
+	}
+}
+
+func TestConversationLocks(t *testing.T) {
+	locks := newConversationLocks()
+
+	unlockA := locks.lock("a")
+	unlockB := locks.lock("b") // other conversations are not blocked
+
+	acquired := make(chan struct{})
+	go func() {
+		unlock := locks.lock("a")
+		close(acquired)
+		unlock()
+	}()
+
+	select {
+	case <-acquired:
+		t.Fatal("second turn of a conversation must wait")
+	case <-time.After(20 * time.Millisecond):
+	}
+
+	unlockA()
+	<-acquired
+	unlockB()
+
+	if len(locks.locks) != 0 {
+		t.Errorf("expected released locks to be dropped, %d left", len(locks.locks))
+	}
+}
+
 func TestGetConversationID(t *testing.T) {
 	ag := New(Config{
 		Provider: &mockChatProvider{},
@@ -867,6 +1374,40 @@ func (p *infiniteToolProvider) Stream(_ context.Context, _ *provider.ChatRequest
 	return ch, nil
 }
 
+// gatedProvider blocks requests whose last message is "slow" until gate closes.
+type gatedProvider struct {
+	gate chan struct{}
+}
+
+func (p *gatedProvider) Name() string     { return "gated" }
+func (p *gatedProvider) Models() []string { return []string{"test"} }
+func (p *gatedProvider) Chat(_ context.Context, _ *provider.ChatRequest) (*provider.ChatResponse, error) {
+	return &provider.ChatResponse{}, nil
+}
+func (p *gatedProvider) Stream(ctx context.Context, req *provider.ChatRequest) (<-chan provider.StreamEvent, error) {
+	if last := req.Messages[len(req.Messages)-1]; strings.HasSuffix(last.Content, "slow") {
+		select {
+		case <-p.gate:
+		case <-ctx.Done():
+		}
+	}
+	ch := make(chan provider.StreamEvent, 2)
+	ch <- provider.StreamEvent{Type: "text", Text: "ok"}
+	ch <- provider.StreamEvent{Type: "stop"}
+	close(ch)
+	return ch, nil
+}
+
+// slowProvider delays each response so concurrent turns overlap.
+type slowProvider struct {
+	mockChatProvider
+}
+
+func (p *slowProvider) Stream(ctx context.Context, req *provider.ChatRequest) (<-chan provider.StreamEvent, error) {
+	time.Sleep(2 * time.Millisecond)
+	return p.mockChatProvider.Stream(ctx, req)
+}
+
 // errorStreamProvider always returns a stream error.
 type errorStreamProvider struct {
 	err error
@@ -885,3 +1426,29 @@ func (p *errorStreamProvider) Stream(_ context.Context, _ *provider.ChatRequest)
 	}()
 	return ch, nil
 }
+
+func TestAgent_TextLanguage(t *testing.T) {
+	ag := New(Config{Provider: provider.NewMock(), Channel: newTestChannel(), Tools: tool.NewRegistry(), Language: "tr"})
+
+	for _, tc := range []struct {
+		locale string
+		want   string
+	}{
+		{"", "⏹ Durduruldu."},   // the agent's language
+		{"en", "⏹ Stopped."},    // the user's language
+		{"de", "⏹ Durduruldu."}, // no templates for the user's language
+	} {
+		msg := &channel.Message{Metadata: map[string]any{}}
+		if tc.locale != "" {
+			msg.Metadata[channel.MetaLocale] = tc.locale
+		}
+		if got := ag.text(msg, "agent.stopped", nil); got != tc.want {
+			t.Errorf("locale %q: expected %q, got %q", tc.locale, tc.want, got)
+		}
+	}
+
+	limit := ag.limitText(&channel.Message{}, &AgentError{Code: ErrMaxIterations, Message: "reached maximum iterations (3)"})
+	if !strings.Contains(limit, "adım sınırıma") || !strings.Contains(limit, "(3)") {
+		t.Errorf("expected the Turkish step limit note, got %q", limit)
+	}
+}
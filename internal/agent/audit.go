@@ -0,0 +1,44 @@
+package agent
+
+import (
+	"time"
+
+	"github.com/eachlabs/klaw/internal/provider"
+	"github.com/eachlabs/klaw/internal/redact"
+	"github.com/eachlabs/klaw/internal/tool"
+)
+
+// ToolAuditor receives every tool call an agent makes, e.g. to keep an
+// audit trail of the commands it ran.
+type ToolAuditor interface {
+	RecordToolCall(call ToolCall)
+}
+
+// ToolCall is a finished tool call as reported to a ToolAuditor. Input and
+// Result have their credentials masked.
+type ToolCall struct {
+	Agent          string
+	ConversationID string
+	Tool           string
+	Input          string
+	Result         *tool.Result
+	Duration       time.Duration
+}
+
+// auditToolCall reports a finished tool call to auditor.
+func auditToolCall(auditor ToolAuditor, secrets *redact.Redactor, agentName, conversationID string, tc provider.ToolCall, result *tool.Result, duration time.Duration) {
+	if auditor == nil {
+		return
+	}
+	if result == nil {
+		result = &tool.Result{}
+	}
+	auditor.RecordToolCall(ToolCall{
+		Agent:          agentName,
+		ConversationID: conversationID,
+		Tool:           tc.Name,
+		Input:          secrets.String(string(tc.Input)),
+		Result:         result,
+		Duration:       duration,
+	})
+}
@@ -0,0 +1,69 @@
+package agent
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/eachlabs/klaw/internal/channel"
+	"github.com/eachlabs/klaw/internal/provider"
+	"github.com/eachlabs/klaw/internal/tool"
+)
+
+// auditLog records the tool calls reported to a ToolAuditor.
+type auditLog struct {
+	mu    sync.Mutex
+	calls []ToolCall
+}
+
+func (l *auditLog) RecordToolCall(call ToolCall) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.calls = append(l.calls, call)
+}
+
+func TestHandleMessage_AuditsToolCalls(t *testing.T) {
+	callCount := 0
+	prov := &sequentialProvider{
+		responses: []*provider.ChatResponse{
+			{Content: []provider.ContentBlock{
+				{Type: "tool_use", ToolUse: &provider.ToolCall{
+					ID: "tc1", Name: "echo", Input: json.RawMessage(`{"msg":"GITHUB_TOKEN=abc123def456"}`),
+				}},
+				{Type: "tool_use", ToolUse: &provider.ToolCall{
+					ID: "tc2", Name: "missing", Input: json.RawMessage(`{}`),
+				}},
+			}},
+			{Content: []provider.ContentBlock{{Type: "text", Text: "Done"}}},
+		},
+		callCount: &callCount,
+	}
+	reg := tool.NewRegistry()
+	reg.Register(&echoTool{})
+	audit := &auditLog{}
+	ag := New(Config{Provider: prov, Channel: newTestChannel(), Tools: reg, AgentName: "coder", Audit: audit})
+
+	if err := ag.handleMessage(context.Background(), &channel.Message{Role: "user", Content: "go", Metadata: map[string]any{"channel": "C1"}}); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(audit.calls) != 2 {
+		t.Fatalf("audited %d calls, want 2", len(audit.calls))
+	}
+	byTool := map[string]ToolCall{}
+	for _, c := range audit.calls {
+		byTool[c.Tool] = c
+	}
+	echo := byTool["echo"]
+	if echo.Agent != "coder" || echo.ConversationID == "" || echo.Result.IsError {
+		t.Errorf("echo call = %+v", echo)
+	}
+	if strings.Contains(echo.Input, "abc123def456") {
+		t.Errorf("secret in audited input: %s", echo.Input)
+	}
+	if missing := byTool["missing"]; !missing.Result.IsError {
+		t.Errorf("missing tool call = %+v", missing)
+	}
+}
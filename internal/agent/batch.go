@@ -0,0 +1,69 @@
+package agent
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// MessageVerdict is the analysis of one message of a batch: a reply, or
+// Skip when the message is not relevant to the task.
+type MessageVerdict struct {
+	Index int    `json:"index"` // 1-based position of the message in the batch
+	Skip  bool   `json:"skip"`
+	Reply string `json:"reply,omitempty"`
+}
+
+var verdictsSchema = json.RawMessage(`{"type": "array", "items": {"type": "object", "required": ["index", "skip"], "properties": {"index": {"type": "integer"}, "skip": {"type": "boolean"}, "reply": {"type": "string"}}}}`)
+
+// BatchPrompt asks for a verdict on each of several messages in a single
+// reply, to be read with ParseVerdicts.
+func BatchPrompt(task string, messages []string) string {
+	var b strings.Builder
+	b.WriteString("Your task:\n")
+	b.WriteString(task)
+	b.WriteString("\n\nMessages to process:\n")
+	for i, m := range messages {
+		fmt.Fprintf(&b, "\n[%d] %s\n", i+1, m)
+	}
+	b.WriteString("\nJudge each message on its own. For a message relevant to your task, set skip to false and put your analysis in reply; otherwise set skip to true. ")
+	b.WriteString("Give exactly one entry per message, with index the number of the message.")
+	b.WriteString(StructuredOutputPrompt(verdictsSchema))
+	return b.String()
+}
+
+// ParseVerdicts reads the verdicts on a batch of n messages from a reply to
+// BatchPrompt, in message order. It fails unless every message has exactly
+// one verdict and every verdict that doesn't skip has a reply.
+func ParseVerdicts(output string, n int) ([]MessageVerdict, error) {
+	doc, err := ValidateOutput(verdictsSchema, output)
+	if err != nil {
+		return nil, err
+	}
+	var parsed []MessageVerdict
+	if err := json.Unmarshal([]byte(doc), &parsed); err != nil {
+		return nil, err
+	}
+
+	verdicts := make([]MessageVerdict, n)
+	seen := make([]bool, n)
+	for _, v := range parsed {
+		if v.Index < 1 || v.Index > n {
+			return nil, fmt.Errorf("verdict for unknown message %d", v.Index)
+		}
+		if seen[v.Index-1] {
+			return nil, fmt.Errorf("several verdicts for message %d", v.Index)
+		}
+		if !v.Skip && strings.TrimSpace(v.Reply) == "" {
+			return nil, fmt.Errorf("verdict for message %d has no reply", v.Index)
+		}
+		seen[v.Index-1] = true
+		verdicts[v.Index-1] = v
+	}
+	for i, ok := range seen {
+		if !ok {
+			return nil, fmt.Errorf("no verdict for message %d", i+1)
+		}
+	}
+	return verdicts, nil
+}
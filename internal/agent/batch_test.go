@@ -0,0 +1,46 @@
+package agent
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestBatchPrompt(t *testing.T) {
+	prompt := BatchPrompt("Flag outages", []string{"api is down", "lunch?"})
+	for _, want := range []string{"Flag outages", "[1] api is down", "[2] lunch?", "JSON"} {
+		if !strings.Contains(prompt, want) {
+			t.Errorf("prompt misses %q:\n%s", want, prompt)
+		}
+	}
+}
+
+func TestParseVerdicts(t *testing.T) {
+	output := "```json\n[{\"index\": 2, \"skip\": true}, {\"index\": 1, \"skip\": false, \"reply\": \"Paging on-call\"}]\n```"
+	verdicts, err := ParseVerdicts(output, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if verdicts[0].Skip || verdicts[0].Reply != "Paging on-call" || !verdicts[1].Skip {
+		t.Errorf("verdicts = %+v", verdicts)
+	}
+
+	tests := []struct {
+		name    string
+		output  string
+		wantErr string
+	}{
+		{"not json", "SKIP", "not valid JSON"},
+		{"missing", `[{"index": 1, "skip": true}]`, "no verdict for message 2"},
+		{"duplicate", `[{"index": 1, "skip": true}, {"index": 1, "skip": true}]`, "several verdicts for message 1"},
+		{"out of range", `[{"index": 1, "skip": true}, {"index": 3, "skip": true}]`, "unknown message 3"},
+		{"empty reply", `[{"index": 1, "skip": true}, {"index": 2, "skip": false}]`, "message 2 has no reply"},
+		{"wrong type", `[{"index": "1", "skip": true}]`, "expected integer"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := ParseVerdicts(tt.output, 2); err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("expected error containing %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
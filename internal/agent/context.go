@@ -3,10 +3,15 @@ package agent
 import (
 	"context"
 	"fmt"
+	"strings"
+	"sync"
 
 	"github.com/eachlabs/klaw/internal/provider"
 )
 
+// summaryPrefix marks the synopsis message that replaces compacted turns.
+const summaryPrefix = "[Previous conversation summary]"
+
 // ContextConfig controls context window management.
 type ContextConfig struct {
 	MaxContextTokens    int     // default: 200000 (Claude)
@@ -16,10 +21,20 @@ type ContextConfig struct {
 
 // ContextManager tracks token usage and triggers compaction.
 type ContextManager struct {
-	config      ContextConfig
-	totalInput  int
-	totalOutput int
-	turnCount   int
+	config ContextConfig
+
+	mu            sync.Mutex
+	totalInput    int
+	totalOutput   int
+	turnCount     int
+	conversations map[string]conversationTokens
+}
+
+// conversationTokens is the prompt size the provider last reported for a
+// conversation and how many history messages that prompt contained.
+type conversationTokens struct {
+	inputTokens int
+	messages    int
 }
 
 // NewContextManager creates a context manager with the given config.
@@ -33,7 +48,7 @@ func NewContextManager(cfg ContextConfig) *ContextManager {
 	if cfg.ReserveTokens == 0 {
 		cfg.ReserveTokens = 8192
 	}
-	return &ContextManager{config: cfg}
+	return &ContextManager{config: cfg, conversations: make(map[string]conversationTokens)}
 }
 
 // EstimateTokens returns a rough token count for a message list (~4 chars/token).
@@ -53,31 +68,80 @@ func (cm *ContextManager) EstimateTokens(msgs []provider.Message) int {
 
 // RecordUsage updates cumulative token counts.
 func (cm *ContextManager) RecordUsage(u provider.Usage) {
+	cm.mu.Lock()
+	defer cm.mu.Unlock()
 	cm.totalInput += u.InputTokens
 	cm.totalOutput += u.OutputTokens
 	cm.turnCount++
 }
 
+// RecordConversation remembers the input tokens the provider reported for a
+// request that carried the first messages entries of a conversation's history.
+func (cm *ContextManager) RecordConversation(conversationID string, messages, inputTokens int) {
+	if inputTokens <= 0 {
+		return
+	}
+	cm.mu.Lock()
+	defer cm.mu.Unlock()
+	cm.conversations[conversationID] = conversationTokens{inputTokens: inputTokens, messages: messages}
+}
+
+// ResetConversation forgets the measured size of a conversation, e.g. after
+// its history was compacted or cleared.
+func (cm *ContextManager) ResetConversation(conversationID string) {
+	cm.mu.Lock()
+	defer cm.mu.Unlock()
+	delete(cm.conversations, conversationID)
+}
+
+// ConversationTokens returns the best known prompt size of a conversation:
+// the last size reported by the provider plus an estimate for messages added
+// since, or a full estimate (including the system prompt) if nothing was
+// reported yet.
+func (cm *ContextManager) ConversationTokens(conversationID, systemPrompt string, msgs []provider.Message) int {
+	cm.mu.Lock()
+	measured, ok := cm.conversations[conversationID]
+	cm.mu.Unlock()
+
+	if ok && measured.messages <= len(msgs) {
+		return measured.inputTokens + cm.EstimateTokens(msgs[measured.messages:])
+	}
+	return len(systemPrompt)/4 + cm.EstimateTokens(msgs)
+}
+
 // NeedsCompaction returns true if the estimated context size exceeds the threshold.
 func (cm *ContextManager) NeedsCompaction(msgs []provider.Message) bool {
-	estimated := cm.EstimateTokens(msgs)
-	threshold := int(float64(cm.config.MaxContextTokens-cm.config.ReserveTokens) * cm.config.CompactionThreshold)
-	return estimated > threshold
+	return cm.EstimateTokens(msgs) > cm.threshold()
+}
+
+// NeedsCompactionFor is NeedsCompaction for a tracked conversation, using the
+// token counts reported by the provider where available.
+func (cm *ContextManager) NeedsCompactionFor(conversationID, systemPrompt string, msgs []provider.Message) bool {
+	return cm.ConversationTokens(conversationID, systemPrompt, msgs) > cm.threshold()
+}
+
+func (cm *ContextManager) threshold() int {
+	return int(float64(cm.config.MaxContextTokens-cm.config.ReserveTokens) * cm.config.CompactionThreshold)
 }
 
 // Compact summarizes the middle portion of history to reduce token usage.
 // It keeps the first user message and the last 6 messages verbatim,
-// then summarizes the middle section via the provider.
+// then summarizes the middle section via the provider. The kept tail never
+// starts with a tool result, so tool calls stay paired with their results,
+// and a synopsis from an earlier compaction is folded into the new one.
 func (cm *ContextManager) Compact(ctx context.Context, prov provider.Provider, systemPrompt string, msgs []provider.Message) ([]provider.Message, error) {
 	if len(msgs) <= 8 {
 		return msgs, nil // too short to compact
 	}
 
-	keepStart := 1  // first user message
-	keepEnd := 6    // last N messages
+	keepStart := 1 // first user message
+	keepEnd := 6   // last N messages
 
 	middleStart := keepStart
 	middleEnd := len(msgs) - keepEnd
+	for middleEnd > middleStart && msgs[middleEnd].ToolResult != nil {
+		middleEnd-- // keep the tool call that produced this result
+	}
 
 	if middleEnd <= middleStart {
 		return msgs, nil
@@ -87,6 +151,8 @@ func (cm *ContextManager) Compact(ctx context.Context, prov provider.Provider, s
 	var summaryContent string
 	for _, m := range msgs[middleStart:middleEnd] {
 		switch {
+		case m.Role == "user" && strings.HasPrefix(m.Content, summaryPrefix):
+			summaryContent += fmt.Sprintf("[earlier summary]\n%s\n", strings.TrimSpace(strings.TrimPrefix(m.Content, summaryPrefix)))
 		case m.ToolResult != nil:
 			summaryContent += fmt.Sprintf("[tool result] %s\n", truncateForSummary(m.ToolResult.Content, 200))
 		case len(m.ToolCalls) > 0:
@@ -123,11 +189,11 @@ func (cm *ContextManager) Compact(ctx context.Context, prov provider.Provider, s
 	}
 
 	// Build compacted history
-	compacted := make([]provider.Message, 0, 2+keepEnd)
+	compacted := make([]provider.Message, 0, 2+len(msgs)-middleEnd)
 	compacted = append(compacted, msgs[0]) // first user message
 	compacted = append(compacted, provider.Message{
 		Role:    "user",
-		Content: fmt.Sprintf("[Previous conversation summary]\n%s", summaryText),
+		Content: fmt.Sprintf("%s\n%s", summaryPrefix, summaryText),
 	})
 	compacted = append(compacted, msgs[middleEnd:]...) // last N messages
 
@@ -136,6 +202,8 @@ func (cm *ContextManager) Compact(ctx context.Context, prov provider.Provider, s
 
 // Usage returns cumulative token usage stats.
 func (cm *ContextManager) Usage() (input, output, turns int) {
+	cm.mu.Lock()
+	defer cm.mu.Unlock()
 	return cm.totalInput, cm.totalOutput, cm.turnCount
 }
 
@@ -3,6 +3,8 @@ package agent
 import (
 	"context"
 	"encoding/json"
+	"fmt"
+	"strings"
 	"testing"
 
 	"github.com/eachlabs/klaw/internal/provider"
@@ -229,6 +231,113 @@ func TestCompactWithToolResults(t *testing.T) {
 	}
 }
 
+func TestConversationTokens(t *testing.T) {
+	cm := NewContextManager(ContextConfig{MaxContextTokens: 10000, CompactionThreshold: 0.5, ReserveTokens: 1000})
+
+	msgs := []provider.Message{
+		{Role: "user", Content: "hello"},
+		{Role: "assistant", Content: "hi"},
+	}
+	if cm.NeedsCompactionFor("a", "", msgs) {
+		t.Error("fresh conversation should not need compaction")
+	}
+
+	// The provider reports a large prompt (e.g. big tool schemas) for "a" only
+	cm.RecordConversation("a", len(msgs), 4500)
+	msgs = append(msgs, provider.Message{Role: "user", Content: strings.Repeat("x", 400)})
+	if got := cm.ConversationTokens("a", "", msgs); got != 4600 {
+		t.Errorf("expected 4500 measured + 100 estimated, got %d", got)
+	}
+	if !cm.NeedsCompactionFor("a", "", msgs) {
+		t.Error("expected compaction from measured usage")
+	}
+	if cm.NeedsCompactionFor("b", "", msgs) {
+		t.Error("usage of one conversation must not affect another")
+	}
+
+	cm.ResetConversation("a")
+	if cm.NeedsCompactionFor("a", "", msgs) {
+		t.Error("expected estimate after reset")
+	}
+}
+
+func TestCompactKeepsToolPairs(t *testing.T) {
+	cm := NewContextManager(ContextConfig{})
+
+	msgs := []provider.Message{{Role: "user", Content: "start"}}
+	for i := 0; i < 5; i++ {
+		id := fmt.Sprintf("t%d", i)
+		msgs = append(msgs,
+			provider.Message{Role: "assistant", ToolCalls: []provider.ToolCall{{ID: id, Name: "bash", Input: json.RawMessage(`{}`)}}},
+			provider.Message{Role: "user", ToolResult: &provider.ToolResult{ToolUseID: id, Content: "ok"}},
+		)
+	}
+
+	mock := &mockChatProvider{resp: &provider.ChatResponse{Content: []provider.ContentBlock{{Type: "text", Text: "summary"}}}}
+	result, err := cm.Compact(context.Background(), mock, "", msgs)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	calls := map[string]bool{}
+	for _, m := range result[2:] {
+		for _, tc := range m.ToolCalls {
+			calls[tc.ID] = true
+		}
+		if m.ToolResult != nil && !calls[m.ToolResult.ToolUseID] {
+			t.Errorf("tool result %s kept without its tool call", m.ToolResult.ToolUseID)
+		}
+	}
+}
+
+func TestCompactFoldsPreviousSummary(t *testing.T) {
+	cm := NewContextManager(ContextConfig{})
+
+	long := strings.Repeat("decided to use postgres ", 40)
+	msgs := []provider.Message{
+		{Role: "user", Content: "start"},
+		{Role: "user", Content: summaryPrefix + "\n" + long},
+	}
+	for i := 0; i < 8; i++ {
+		msgs = append(msgs, provider.Message{Role: "assistant", Content: "a"}, provider.Message{Role: "user", Content: "u"})
+	}
+
+	var prompt string
+	mock := &recordingProvider{mockChatProvider: mockChatProvider{
+		resp: &provider.ChatResponse{Content: []provider.ContentBlock{{Type: "text", Text: "new summary"}}},
+	}, prompt: &prompt}
+	result, err := cm.Compact(context.Background(), mock, "", msgs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(prompt, strings.TrimSpace(long)) {
+		t.Error("earlier summary should be passed to the summarizer in full")
+	}
+	if !strings.HasPrefix(result[1].Content, summaryPrefix) || strings.Count(result[1].Content, summaryPrefix) != 1 {
+		t.Errorf("expected a single synopsis message, got %q", result[1].Content)
+	}
+}
+
+// recordingProvider captures the last Chat prompt.
+type recordingProvider struct {
+	mockChatProvider
+	prompt *string
+}
+
+func (r *recordingProvider) Chat(ctx context.Context, req *provider.ChatRequest) (*provider.ChatResponse, error) {
+	*r.prompt = req.Messages[len(req.Messages)-1].Content
+	return r.mockChatProvider.Chat(ctx, req)
+}
+
+func TestIsContextLimitError(t *testing.T) {
+	if !isContextLimitError(fmt.Errorf("400: prompt is too long: 210000 tokens > 200000 maximum")) {
+		t.Error("expected Anthropic overflow to be detected")
+	}
+	if isContextLimitError(fmt.Errorf("rate limited")) {
+		t.Error("unexpected overflow detection")
+	}
+}
+
 func TestTruncateForSummary(t *testing.T) {
 	short := "short text"
 	if truncateForSummary(short, 100) != short {
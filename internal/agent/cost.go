@@ -1,11 +1,25 @@
 package agent
 
-import "fmt"
+import (
+	"fmt"
+	"sync"
+
+	"github.com/eachlabs/klaw/internal/provider"
+)
 
 // CostConfig controls budget enforcement.
 type CostConfig struct {
 	MaxSessionCost float64 // 0 = unlimited
 	WarnThreshold  float64 // fraction of budget that triggers a warning (e.g. 0.8)
+	MaxTurnTokens  int     // tokens (input + output) one turn may use, 0 = unlimited
+	MaxTurnCost    float64 // cost one turn may incur, 0 = unlimited
+
+	// Per-conversation limits, counted since the agent started. A
+	// conversation reaching one is summarized and closed.
+	MaxConversationTurns  int     // turns one conversation may take, 0 = unlimited
+	MaxConversationTokens int     // tokens one conversation may use, 0 = unlimited
+	MaxConversationCost   float64 // cost one conversation may incur, 0 = unlimited
+	Handoff               string  // who is asked to take over a closed conversation, e.g. a Slack mention; empty = nobody
 }
 
 // ModelCost holds per-million-token pricing for a model.
@@ -14,13 +28,30 @@ type ModelCost struct {
 	OutputPerMillion float64
 }
 
+// UsageRecorder receives the token usage and estimated cost of each
+// provider request, e.g. to keep usage records for reports.
+type UsageRecorder interface {
+	RecordUsage(agentName, model string, usage provider.Usage, cost float64)
+}
+
 // CostTracker tracks session cost and enforces budgets.
 type CostTracker struct {
 	config      CostConfig
+	mu          sync.Mutex
 	sessionCost float64
 	totalInput  int
 	totalOutput int
 	costTable   map[string]ModelCost
+
+	conversations map[string]*conversationSpend
+}
+
+// conversationSpend is what one conversation has used.
+type conversationSpend struct {
+	turns  int
+	tokens int
+	cost   float64
+	closed bool // closed at its limits; later messages are not answered
 }
 
 // DefaultCostTable returns known model pricing (per million tokens).
@@ -45,30 +76,44 @@ func DefaultCostTable() map[string]ModelCost {
 // NewCostTracker creates a cost tracker.
 func NewCostTracker(cfg CostConfig) *CostTracker {
 	return &CostTracker{
-		config:    cfg,
-		costTable: DefaultCostTable(),
+		config:        cfg,
+		costTable:     DefaultCostTable(),
+		conversations: make(map[string]*conversationSpend),
 	}
 }
 
 // Record adds a usage record and returns the incremental cost.
 func (ct *CostTracker) Record(model string, input, output int) float64 {
+	ct.mu.Lock()
+	defer ct.mu.Unlock()
 	ct.totalInput += input
 	ct.totalOutput += output
 
-	pricing, ok := ct.costTable[model]
+	cost := estimateCost(ct.costTable, model, input, output)
+	ct.sessionCost += cost
+	return cost
+}
+
+// EstimateCost returns the cost of a request to model at the default
+// pricing.
+func EstimateCost(model string, input, output int) float64 {
+	return estimateCost(DefaultCostTable(), model, input, output)
+}
+
+func estimateCost(table map[string]ModelCost, model string, input, output int) float64 {
+	pricing, ok := table[model]
 	if !ok {
 		// Unknown model — use a conservative estimate
 		pricing = ModelCost{InputPerMillion: 3.0, OutputPerMillion: 15.0}
 	}
-
-	cost := float64(input)/1_000_000*pricing.InputPerMillion +
+	return float64(input)/1_000_000*pricing.InputPerMillion +
 		float64(output)/1_000_000*pricing.OutputPerMillion
-	ct.sessionCost += cost
-	return cost
 }
 
 // CheckBudget returns an error if the session cost exceeds the budget.
 func (ct *CostTracker) CheckBudget() error {
+	ct.mu.Lock()
+	defer ct.mu.Unlock()
 	if ct.config.MaxSessionCost <= 0 {
 		return nil
 	}
@@ -81,8 +126,104 @@ func (ct *CostTracker) CheckBudget() error {
 	return nil
 }
 
+// turnBudget tracks the spend of a single turn against the per-turn limits.
+type turnBudget struct {
+	config CostConfig
+	tokens int
+	cost   float64
+}
+
+// newTurnBudget starts tracking a turn.
+func (ct *CostTracker) newTurnBudget() *turnBudget {
+	return &turnBudget{config: ct.config}
+}
+
+func (b *turnBudget) add(tokens int, cost float64) {
+	b.tokens += tokens
+	b.cost += cost
+}
+
+// check returns an error once the turn has used up its budget.
+func (b *turnBudget) check() error {
+	if b.config.MaxTurnTokens > 0 && b.tokens >= b.config.MaxTurnTokens {
+		return &AgentError{
+			Code:    ErrBudgetExceed,
+			Message: fmt.Sprintf("turn used %d tokens, limit is %d", b.tokens, b.config.MaxTurnTokens),
+		}
+	}
+	if b.config.MaxTurnCost > 0 && b.cost >= b.config.MaxTurnCost {
+		return &AgentError{
+			Code:    ErrBudgetExceed,
+			Message: fmt.Sprintf("turn cost $%.4f, limit is $%.2f", b.cost, b.config.MaxTurnCost),
+		}
+	}
+	return nil
+}
+
+// conversation returns the spend of a conversation. ct.mu must be held.
+func (ct *CostTracker) conversation(id string) *conversationSpend {
+	spend, ok := ct.conversations[id]
+	if !ok {
+		spend = &conversationSpend{}
+		ct.conversations[id] = spend
+	}
+	return spend
+}
+
+// startConversationTurn counts a turn of the conversation and reports
+// whether the conversation has been closed.
+func (ct *CostTracker) startConversationTurn(id string) (closed bool) {
+	ct.mu.Lock()
+	defer ct.mu.Unlock()
+	spend := ct.conversation(id)
+	if spend.closed {
+		return true
+	}
+	spend.turns++
+	return false
+}
+
+// recordConversation adds the usage of a request to its conversation.
+func (ct *CostTracker) recordConversation(id string, tokens int, cost float64) {
+	ct.mu.Lock()
+	defer ct.mu.Unlock()
+	spend := ct.conversation(id)
+	spend.tokens += tokens
+	spend.cost += cost
+}
+
+// checkConversation returns an error once the conversation has used up
+// its limits.
+func (ct *CostTracker) checkConversation(id string) error {
+	ct.mu.Lock()
+	defer ct.mu.Unlock()
+	spend := ct.conversation(id)
+	cfg := ct.config
+	var reason string
+	switch {
+	case cfg.MaxConversationTurns > 0 && spend.turns > cfg.MaxConversationTurns:
+		reason = fmt.Sprintf("conversation reached its limit of %d turns", cfg.MaxConversationTurns)
+	case cfg.MaxConversationTokens > 0 && spend.tokens >= cfg.MaxConversationTokens:
+		reason = fmt.Sprintf("conversation used %d tokens, limit is %d", spend.tokens, cfg.MaxConversationTokens)
+	case cfg.MaxConversationCost > 0 && spend.cost >= cfg.MaxConversationCost:
+		reason = fmt.Sprintf("conversation cost $%.4f, limit is $%.2f", spend.cost, cfg.MaxConversationCost)
+	default:
+		return nil
+	}
+	return &AgentError{Code: ErrConversationLimit, Message: reason}
+}
+
+// closeConversation marks a conversation as closed at its limits.
+func (ct *CostTracker) closeConversation(id string) {
+	ct.mu.Lock()
+	defer ct.mu.Unlock()
+	ct.conversation(id).closed = true
+}
+
 // IsNearBudget returns true if cost has passed the warning threshold.
 func (ct *CostTracker) IsNearBudget() bool {
+	ct.mu.Lock()
+	defer ct.mu.Unlock()
 	if ct.config.MaxSessionCost <= 0 || ct.config.WarnThreshold <= 0 {
 		return false
 	}
@@ -91,11 +232,15 @@ func (ct *CostTracker) IsNearBudget() bool {
 
 // SessionCost returns the total session cost so far.
 func (ct *CostTracker) SessionCost() float64 {
+	ct.mu.Lock()
+	defer ct.mu.Unlock()
 	return ct.sessionCost
 }
 
 // Summary returns a human-readable cost summary.
 func (ct *CostTracker) Summary() string {
+	ct.mu.Lock()
+	defer ct.mu.Unlock()
 	inK := float64(ct.totalInput) / 1000
 	outK := float64(ct.totalOutput) / 1000
 	return fmt.Sprintf("$%.4f (%.1fk in / %.1fk out)", ct.sessionCost, inK, outK)
@@ -0,0 +1,94 @@
+package agent
+
+import (
+	"fmt"
+	"strings"
+)
+
+// PromptDiff returns a unified line diff between two system prompts, with
+// three lines of context around each change. It is empty when they match.
+func PromptDiff(old, new string) string {
+	if old == new {
+		return ""
+	}
+	a := strings.Split(old, "\n")
+	b := strings.Split(new, "\n")
+
+	// lcs[i][j] is the longest common subsequence of a[i:] and b[j:]
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	type line struct {
+		op   byte // ' ', '-' or '+'
+		text string
+		a, b int // line numbers before the line, in old and new
+	}
+	var lines []line
+	i, j := 0, 0
+	for i < len(a) || j < len(b) {
+		switch {
+		case i < len(a) && j < len(b) && a[i] == b[j]:
+			lines = append(lines, line{' ', a[i], i, j})
+			i++
+			j++
+		case i < len(a) && (j == len(b) || lcs[i+1][j] >= lcs[i][j+1]):
+			lines = append(lines, line{'-', a[i], i, j})
+			i++
+		default:
+			lines = append(lines, line{'+', b[j], i, j})
+			j++
+		}
+	}
+
+	const context = 3
+	var sb strings.Builder
+	for start := 0; start < len(lines); {
+		// Find the next change and the end of its hunk
+		first := start
+		for first < len(lines) && lines[first].op == ' ' {
+			first++
+		}
+		if first == len(lines) {
+			break
+		}
+		from := max(first-context, start)
+		to, unchanged := first, 0
+		for k := first; k < len(lines) && unchanged <= 2*context; k++ {
+			if lines[k].op == ' ' {
+				unchanged++
+			} else {
+				to, unchanged = k, 0
+			}
+		}
+		to = min(to+context+1, len(lines))
+
+		oldCount, newCount := 0, 0
+		for _, l := range lines[from:to] {
+			if l.op != '+' {
+				oldCount++
+			}
+			if l.op != '-' {
+				newCount++
+			}
+		}
+		fmt.Fprintf(&sb, "@@ -%d,%d +%d,%d @@\n", lines[from].a+1, oldCount, lines[from].b+1, newCount)
+		for _, l := range lines[from:to] {
+			sb.WriteByte(l.op)
+			sb.WriteString(l.text)
+			sb.WriteByte('\n')
+		}
+		start = to
+	}
+	return sb.String()
+}
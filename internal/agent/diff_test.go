@@ -0,0 +1,28 @@
+package agent
+
+import "testing"
+
+func TestPromptDiff(t *testing.T) {
+	if got := PromptDiff("same\ntext", "same\ntext"); got != "" {
+		t.Errorf("identical prompts should have no diff, got %q", got)
+	}
+
+	old := "# Role\nYou write code.\n\n1\n2\n3\n4\n5\n6\n7\n8\n9\n# Limits\nNo deploys."
+	new := "# Role\nYou write and review code.\n\n1\n2\n3\n4\n5\n6\n7\n8\n9\n# Limits\nNo deploys.\nNo force pushes."
+	want := `@@ -1,5 +1,5 @@
+ # Role
+-You write code.
++You write and review code.
+ 
+ 1
+ 2
+@@ -12,3 +12,4 @@
+ 9
+ # Limits
+ No deploys.
++No force pushes.
+`
+	if got := PromptDiff(old, new); got != want {
+		t.Errorf("unexpected diff:\n%s\nwant:\n%s", got, want)
+	}
+}
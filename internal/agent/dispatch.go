@@ -0,0 +1,255 @@
+package agent
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/eachlabs/klaw/internal/channel"
+	"github.com/eachlabs/klaw/internal/observe"
+	"github.com/eachlabs/klaw/internal/priority"
+	"github.com/eachlabs/klaw/internal/provider"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// routeKeys are the message metadata keys channels use to address a reply,
+// the key a channel.Mux uses to pick the channel it goes to, the bus
+// subject of the gateway it goes back to, and the language it is shown in.
+var routeKeys = []string{"channel", "thread_ts", channel.MetaBinding, channel.MetaReplyTo, channel.MetaLocale}
+
+// replyChannel tags every message sent during a turn with the route of the
+// message being answered, so channels serving several conversations at once
+// (Slack threads) deliver it to the right place.
+type replyChannel struct {
+	channel.Channel
+	route      map[string]any
+	guardrails *guardrailBuffer // set when replies are checked against forbidden topics
+}
+
+func (r *replyChannel) Send(ctx context.Context, msg *channel.Message) error {
+	for k, v := range r.route {
+		if msg.Metadata == nil {
+			msg.Metadata = make(map[string]any, len(r.route))
+		}
+		if _, ok := msg.Metadata[k]; !ok {
+			msg.Metadata[k] = v
+		}
+	}
+	if r.guardrails != nil {
+		return r.guardrails.send(ctx, tracedChannel{r.Channel}, msg)
+	}
+	return tracedChannel{r.Channel}.Send(ctx, msg)
+}
+
+// tracedChannel traces the messages sent through a channel.
+type tracedChannel struct {
+	channel.Channel
+}
+
+func (t tracedChannel) Send(ctx context.Context, msg *channel.Message) error {
+	// Streamed text and turn events would be a span per chunk
+	if msg.IsPartial || msg.Role == "system" {
+		return t.Channel.Send(ctx, msg)
+	}
+	ctx, span := observe.Tracer().Start(ctx, "klaw.channel.send", trace.WithSpanKind(trace.SpanKindProducer),
+		trace.WithAttributes(observe.AttrChannel.String(t.Channel.Name())))
+	err := t.Channel.Send(ctx, msg)
+	observe.EndSpan(span, err)
+	return err
+}
+
+type replyChannelKey struct{}
+
+// withReply returns a context whose turn replies are routed like msg.
+func (a *Agent) withReply(ctx context.Context, msg *channel.Message) context.Context {
+	route := make(map[string]any)
+	for _, k := range routeKeys {
+		if v, ok := msg.Metadata[k]; ok {
+			route[k] = v
+		}
+	}
+	rc := &replyChannel{Channel: a.channel, route: route}
+	if e := a.Guardrails(); e.ChecksText() {
+		rc.guardrails = &guardrailBuffer{engine: e}
+	}
+	return context.WithValue(ctx, replyChannelKey{}, rc)
+}
+
+// out returns the channel replies of the current turn go to.
+func (a *Agent) out(ctx context.Context) channel.Channel {
+	if rc, ok := ctx.Value(replyChannelKey{}).(*replyChannel); ok {
+		return rc
+	}
+	return a.channel
+}
+
+// dispatcher runs turns of different conversations in parallel, bounded by
+// the agent's worker limit, while messages of one conversation are handled
+// in order. Turns waiting for a worker start in order of their message's
+// priority; high-priority messages are not bounded by the limit.
+type dispatcher struct {
+	agent *Agent
+	wg    sync.WaitGroup
+
+	mu        sync.Mutex
+	queues    map[string][]*channel.Message // pending messages per active conversation
+	approvals map[string]chan *channel.Message
+	running   map[string]context.CancelFunc // cancels the current turn per conversation
+}
+
+func newDispatcher(a *Agent) *dispatcher {
+	return &dispatcher{
+		agent:     a,
+		queues:    make(map[string][]*channel.Message),
+		approvals: make(map[string]chan *channel.Message),
+		running:   make(map[string]context.CancelFunc),
+	}
+}
+
+// dispatch queues msg for its conversation, starting a worker if the
+// conversation is idle. Replies to a pending approval prompt are handed to
+// the waiting turn instead, and stop commands cancel the running turn.
+// Messages of conversations leased by another replica are left to it.
+func (d *dispatcher) dispatch(ctx context.Context, msg *channel.Message) {
+	conversationID := d.agent.getConversationID(msg)
+	if !d.agent.leases.own(ctx, conversationID) {
+		return
+	}
+
+	if cmd, _ := msg.Metadata["command"].(string); cmd == channel.CommandStop {
+		d.stop(ctx, msg)
+		return
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if wait, ok := d.approvals[conversationID]; ok {
+		delete(d.approvals, conversationID)
+		wait <- msg
+		return
+	}
+
+	if queue, active := d.queues[conversationID]; active {
+		d.queues[conversationID] = append(queue, msg)
+		return
+	}
+	d.queues[conversationID] = []*channel.Message{msg}
+
+	d.wg.Add(1)
+	go d.work(ctx, conversationID)
+}
+
+// work handles the queued messages of one conversation until it is drained.
+func (d *dispatcher) work(ctx context.Context, conversationID string) {
+	defer d.wg.Done()
+	defer d.agent.leases.hold(ctx, conversationID)()
+	for {
+		d.mu.Lock()
+		queue := d.queues[conversationID]
+		if len(queue) == 0 {
+			delete(d.queues, conversationID)
+			d.mu.Unlock()
+			return
+		}
+		msg := queue[0]
+		d.queues[conversationID] = queue[1:]
+		turnCtx, cancel := context.WithCancel(ctx)
+		d.running[conversationID] = cancel
+		d.mu.Unlock()
+
+		if level := messagePriority(msg); level >= priority.High {
+			// Urgent messages don't wait for a slot
+			d.agent.handleAndReport(turnCtx, msg)
+		} else if d.agent.slots.Acquire(turnCtx, level) == nil {
+			d.agent.handleAndReport(turnCtx, msg)
+			d.agent.slots.Release()
+		}
+
+		d.mu.Lock()
+		delete(d.running, conversationID)
+		d.mu.Unlock()
+		cancel()
+
+		if ctx.Err() != nil {
+			d.mu.Lock()
+			delete(d.queues, conversationID)
+			d.mu.Unlock()
+			return
+		}
+	}
+}
+
+// messagePriority returns the priority of msg from its MetaPriority; an
+// unset or unknown priority is normal.
+func messagePriority(msg *channel.Message) priority.Level {
+	value, _ := msg.Metadata[channel.MetaPriority].(string)
+	level, _ := priority.Parse(value)
+	return level
+}
+
+// AcquireSlot waits for a worker for work of the agent done outside Run,
+// e.g. a cron job, so it shares the worker limit with Run's turns in order
+// of priority. The returned function releases the worker.
+func (a *Agent) AcquireSlot(ctx context.Context, level priority.Level) (release func(), err error) {
+	if err := a.slots.Acquire(ctx, level); err != nil {
+		return nil, err
+	}
+	return a.slots.Release, nil
+}
+
+// stop cancels the running turn of the stop message's conversation. A stop
+// without a thread covers every conversation in the channel. Messages queued
+// behind the turn are still handled.
+func (d *dispatcher) stop(ctx context.Context, msg *channel.Message) {
+	conversationID := d.agent.getConversationID(msg)
+	threadTS, _ := msg.Metadata["thread_ts"].(string)
+
+	d.mu.Lock()
+	stopped := 0
+	for id, cancel := range d.running {
+		if id == conversationID || (threadTS == "" && strings.HasPrefix(id, conversationID+":")) {
+			cancel()
+			stopped++
+		}
+	}
+	d.mu.Unlock()
+
+	if stopped == 0 {
+		ctx = d.agent.withReply(ctx, msg)
+		_ = d.agent.out(ctx).Send(ctx, &channel.Message{
+			Role:    "assistant",
+			Content: d.agent.text(msg, "agent.nothing_to_stop", nil),
+		})
+	}
+}
+
+// awaitApproval asks for approval of tc in the conversation and waits for
+// the next message of that conversation as the answer.
+func (d *dispatcher) awaitApproval(ctx context.Context, conversationID string, tc provider.ToolCall) (bool, error) {
+	wait := make(chan *channel.Message, 1)
+	d.mu.Lock()
+	d.approvals[conversationID] = wait
+	d.mu.Unlock()
+	defer func() {
+		d.mu.Lock()
+		if d.approvals[conversationID] == wait {
+			delete(d.approvals, conversationID)
+		}
+		d.mu.Unlock()
+	}()
+
+	_ = d.agent.out(ctx).Send(ctx, &channel.Message{
+		Role:    "assistant",
+		Content: fmt.Sprintf("\n⚠ Tool '%s' requires approval. Execute? [y/N]: ", tc.Name),
+	})
+
+	select {
+	case <-ctx.Done():
+		return false, ctx.Err()
+	case msg := <-wait:
+		response := strings.TrimSpace(strings.ToLower(msg.Content))
+		return response == "y" || response == "yes", nil
+	}
+}
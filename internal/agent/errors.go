@@ -6,11 +6,15 @@ import "fmt"
 type ErrorCode string
 
 const (
-	ErrMaxIterations ErrorCode = "max_iterations"
-	ErrProvider      ErrorCode = "provider_error"
-	ErrToolExec      ErrorCode = "tool_execution"
-	ErrContextLimit  ErrorCode = "context_limit"
-	ErrBudgetExceed  ErrorCode = "budget_exceeded"
+	ErrMaxIterations     ErrorCode = "max_iterations"
+	ErrProvider          ErrorCode = "provider_error"
+	ErrToolExec          ErrorCode = "tool_execution"
+	ErrContextLimit      ErrorCode = "context_limit"
+	ErrBudgetExceed      ErrorCode = "budget_exceeded"
+	ErrInvalidOutput     ErrorCode = "invalid_output"
+	ErrHookRejected      ErrorCode = "hook_rejected"
+	ErrRouting           ErrorCode = "routing_failed"
+	ErrConversationLimit ErrorCode = "conversation_limit"
 )
 
 // AgentError is a structured error with a machine-readable code.
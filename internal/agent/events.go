@@ -0,0 +1,73 @@
+package agent
+
+import (
+	"context"
+	"sync"
+
+	"github.com/eachlabs/klaw/internal/observe"
+)
+
+// maxEventContent caps the tool output copied into an event.
+const maxEventContent = 2000
+
+// eventHook publishes what passes its hook points as observe events.
+type eventHook struct {
+	hub          *observe.EventHub
+	defaultAgent string
+
+	mu     sync.Mutex
+	agents map[string]string // agent handling each conversation's turn
+}
+
+// EventHook publishes the agent's activity to hub: messages in, replies
+// out, tool calls and their results, and errors. Add it after other hooks
+// so that it sees what they rewrote. Events of the agent's own profile,
+// which has no name, are attributed to defaultAgent.
+func EventHook(hub *observe.EventHub, defaultAgent string) Hook {
+	return &eventHook{hub: hub, defaultAgent: defaultAgent, agents: make(map[string]string)}
+}
+
+func (h *eventHook) Run(ctx context.Context, ev *HookEvent) error {
+	agentName := ev.Agent
+	if agentName == "" {
+		agentName = h.defaultAgent
+	}
+
+	h.mu.Lock()
+	switch ev.Point {
+	case HookPreMessage:
+		h.agents[ev.ConversationID] = agentName
+	case HookPostMessage, HookError:
+		// Errors are reported with the agent's own name; the turn may have
+		// been routed to another one
+		if routed, ok := h.agents[ev.ConversationID]; ok {
+			agentName = routed
+		}
+		delete(h.agents, ev.ConversationID)
+	}
+	h.mu.Unlock()
+
+	out := observe.Event{Agent: agentName, Conversation: ev.ConversationID}
+	switch ev.Point {
+	case HookPreMessage:
+		out.Kind, out.Content = observe.EventMessage, ev.Content
+	case HookPostMessage:
+		out.Kind, out.Content = observe.EventResponse, ev.Content
+	case HookError:
+		out.Kind, out.Content, out.IsError = observe.EventError, ev.Content, true
+	case HookPreTool:
+		out.Kind, out.Tool, out.Content = observe.EventToolCall, ev.Tool.Name, string(ev.Tool.Input)
+	case HookPostTool:
+		out.Kind, out.Tool = observe.EventToolResult, ev.Tool.Name
+		if ev.Result != nil {
+			out.Content, out.IsError = ev.Result.Content, ev.Result.IsError
+			if len(out.Content) > maxEventContent {
+				out.Content = out.Content[:maxEventContent] + "..."
+			}
+		}
+	default:
+		return nil
+	}
+	h.hub.Publish(out)
+	return nil
+}
@@ -0,0 +1,96 @@
+package agent
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/eachlabs/klaw/internal/channel"
+	"github.com/eachlabs/klaw/internal/guardrail"
+	"github.com/eachlabs/klaw/internal/observe"
+	"github.com/eachlabs/klaw/internal/provider"
+	"github.com/eachlabs/klaw/internal/tool"
+)
+
+// SetGuardrails replaces the guardrails checked before tool calls and replies;
+// nil removes them.
+func (a *Agent) SetGuardrails(e *guardrail.Engine) {
+	a.guardrails.Store(e)
+}
+
+// Guardrails returns the guardrails of the agent, or nil.
+func (a *Agent) Guardrails() *guardrail.Engine {
+	return a.guardrails.Load()
+}
+
+// checkToolGuardrails returns the result of a tool call of t the guardrails
+// block, or nil when the call may run.
+func checkToolGuardrails(e *guardrail.Engine, logger *observe.Logger, tc provider.ToolCall, t tool.Tool) *tool.Result {
+	v := e.CheckTool(tc.Name, tc.Input, tool.WorkDir(t))
+	if v == nil {
+		return nil
+	}
+	fmt.Printf("[guardrails] blocked %s: %v\n", tc.Name, v)
+	if logger != nil {
+		logger.Info("tool call blocked by guardrails", "tool", tc.Name, "rule", v.Rule, "pattern", v.Pattern)
+	}
+	return &tool.Result{Content: v.Explain(), IsError: true}
+}
+
+// checkReplyGuardrails returns reply, or a note in its place when it touches a
+// forbidden topic.
+func checkReplyGuardrails(e *guardrail.Engine, reply string) string {
+	if v := e.CheckText(reply); v != nil {
+		fmt.Printf("[guardrails] withheld reply: %v\n", v)
+		return guardrail.BlockedReply(v)
+	}
+	return reply
+}
+
+// guardrailBuffer holds back the streamed text of a reply until it is done, so
+// the reply can be checked against forbidden topics before it is sent.
+type guardrailBuffer struct {
+	engine *guardrail.Engine
+
+	mu   sync.Mutex
+	text strings.Builder
+}
+
+// send sends msg through ch, buffering streamed text and replacing replies
+// that touch a forbidden topic.
+func (b *guardrailBuffer) send(ctx context.Context, ch channel.Channel, msg *channel.Message) error {
+	if msg.Role != "assistant" || isToolDisplay(msg.Content) {
+		return ch.Send(ctx, msg)
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	switch {
+	case msg.IsPartial:
+		b.text.WriteString(msg.Content)
+		return nil
+	case msg.IsDone:
+		text := b.text.String()
+		b.text.Reset()
+		if text != "" {
+			if reply := checkReplyGuardrails(b.engine, text); reply != text {
+				return ch.Send(ctx, &channel.Message{Role: "assistant", Content: reply, Metadata: msg.Metadata})
+			}
+			partial := *msg
+			partial.Content, partial.IsPartial, partial.IsDone = text, true, false
+			if err := ch.Send(ctx, &partial); err != nil {
+				return err
+			}
+		}
+		return ch.Send(ctx, msg)
+	default:
+		msg.Content = checkReplyGuardrails(b.engine, msg.Content)
+		return ch.Send(ctx, msg)
+	}
+}
+
+// isToolDisplay reports whether content shows a tool call or its output
+// rather than model text.
+func isToolDisplay(content string) bool {
+	return strings.HasPrefix(content, "\n╭─ ") || strings.HasPrefix(content, "│ ") || strings.HasPrefix(content, "╰─")
+}
@@ -0,0 +1,64 @@
+package agent
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/eachlabs/klaw/internal/channel"
+	"github.com/eachlabs/klaw/internal/guardrail"
+	"github.com/eachlabs/klaw/internal/provider"
+	"github.com/eachlabs/klaw/internal/tool"
+)
+
+func TestHandleMessage_Guardrails(t *testing.T) {
+	e, err := guardrail.Compile(&guardrail.Policy{
+		BlockedURLs:     []string{`internal\.example\.com`},
+		ForbiddenTopics: []string{`salary`},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	callCount := 0
+	prov := &sequentialProvider{
+		responses: []*provider.ChatResponse{
+			{Content: []provider.ContentBlock{
+				{Type: "tool_use", ToolUse: &provider.ToolCall{
+					ID: "tc1", Name: "echo", Input: json.RawMessage(`{"msg":"https://internal.example.com/payroll"}`),
+				}},
+			}},
+			{Content: []provider.ContentBlock{{Type: "text", Text: "Alice's salary is 100k"}}},
+		},
+		callCount: &callCount,
+	}
+	reg := tool.NewRegistry()
+	reg.Register(&echoTool{})
+	ch := newTestChannel()
+	ag := New(Config{Provider: prov, Channel: ch, Tools: reg, Guardrails: e})
+
+	if err := ag.handleMessage(context.Background(), &channel.Message{Role: "user", Content: "payroll?"}); err != nil {
+		t.Fatal(err)
+	}
+
+	result := ag.History()[2].ToolResult
+	if !result.IsError || !strings.Contains(result.Content, "blocked by guardrail (blocked_urls)") {
+		t.Errorf("tool result = %+v", result)
+	}
+
+	close(ch.sent)
+	var replies []string
+	for msg := range ch.sent {
+		if msg.Role == "assistant" && !isToolDisplay(msg.Content) {
+			replies = append(replies, msg.Content)
+		}
+	}
+	all := strings.Join(replies, "\n")
+	if strings.Contains(all, "100k") {
+		t.Errorf("forbidden reply was sent: %q", all)
+	}
+	if !strings.Contains(all, "reply was withheld") {
+		t.Errorf("no withheld note in %q", all)
+	}
+}
@@ -0,0 +1,188 @@
+package agent
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/eachlabs/klaw/internal/provider"
+	"github.com/eachlabs/klaw/internal/tool"
+)
+
+// HookPoint is a place in the agent loop where hooks run.
+type HookPoint string
+
+const (
+	HookPreMessage  HookPoint = "pre_message"  // before a user message is handled
+	HookPostMessage HookPoint = "post_message" // after the final reply of a turn
+	HookPreTool     HookPoint = "pre_tool"     // before a tool call runs
+	HookPostTool    HookPoint = "post_tool"    // after a tool call returned
+	HookError       HookPoint = "error"        // after a turn failed
+)
+
+// HookEvent describes what a hook is called for. Hooks may change it:
+//   - pre_message: Content rewrites the user message
+//   - pre_tool: Tool.Input rewrites the tool arguments
+//   - post_tool: Result rewrites what the model sees
+//
+// Changes made in post_message are ignored, as the reply was already sent.
+// In error, Content is the error message.
+type HookEvent struct {
+	Point          HookPoint
+	Agent          string
+	ConversationID string
+	Content        string
+	Tool           *provider.ToolCall
+	Result         *tool.Result
+}
+
+// Hook runs at every hook point. An error from a pre_message hook rejects
+// the message, one from a pre_tool hook blocks the tool call and one from a
+// post_tool hook withholds the tool output from the model. Errors of
+// post_message hooks are logged, those of error hooks ignored. Tool hooks of parallel tool calls run
+// concurrently.
+type Hook interface {
+	Run(ctx context.Context, ev *HookEvent) error
+}
+
+// HookFunc adapts a function to a Hook.
+type HookFunc func(ctx context.Context, ev *HookEvent) error
+
+func (f HookFunc) Run(ctx context.Context, ev *HookEvent) error { return f(ctx, ev) }
+
+// hookChain runs hooks in order, stopping at the first error.
+type hookChain []Hook
+
+func (h hookChain) run(ctx context.Context, ev *HookEvent) error {
+	for _, hook := range h {
+		if err := hook.Run(ctx, ev); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// executeTool runs a tool call between the pre_tool and post_tool hooks.
+func (h hookChain) executeTool(ctx context.Context, ev HookEvent, run func(provider.ToolCall) *tool.Result) *tool.Result {
+	tc := *ev.Tool
+	ev.Point, ev.Tool = HookPreTool, &tc
+	if err := h.run(ctx, &ev); err != nil {
+		return &tool.Result{Content: fmt.Sprintf("Blocked by hook: %v", err), IsError: true}
+	}
+
+	ev.Point, ev.Result = HookPostTool, run(tc)
+	if err := h.run(ctx, &ev); err != nil {
+		return &tool.Result{Content: fmt.Sprintf("Output withheld by hook: %v", err), IsError: true}
+	}
+	if ev.Result == nil {
+		return &tool.Result{}
+	}
+	return ev.Result
+}
+
+// ShellHook runs a shell command at one hook point. The event is passed as
+// JSON on stdin and in KLAW_HOOK_EVENT. A non-zero exit fails the hook with
+// the command's stderr as reason; JSON written to stdout replaces the
+// event's content, tool input or result ({"content": ..., "input": ...,
+// "result": {"content": ..., "is_error": ...}}).
+type ShellHook struct {
+	Point   HookPoint
+	Command string
+	Tools   []string      // tool hooks only run for these tools; empty = all
+	Timeout time.Duration // default 10s
+}
+
+type shellHookResult struct {
+	Content string `json:"content"`
+	IsError bool   `json:"is_error"`
+}
+
+type shellHookInput struct {
+	Event          HookPoint          `json:"event"`
+	Agent          string             `json:"agent,omitempty"`
+	ConversationID string             `json:"conversation_id,omitempty"`
+	Content        string             `json:"content,omitempty"`
+	Tool           *provider.ToolCall `json:"tool,omitempty"`
+	Result         *shellHookResult   `json:"result,omitempty"`
+}
+
+type shellHookOutput struct {
+	Content *string          `json:"content"`
+	Input   json.RawMessage  `json:"input"`
+	Result  *shellHookResult `json:"result"`
+}
+
+func (s *ShellHook) Run(ctx context.Context, ev *HookEvent) error {
+	if ev.Point != s.Point || !s.matchesTool(ev) {
+		return nil
+	}
+
+	timeout := s.Timeout
+	if timeout == 0 {
+		timeout = 10 * time.Second
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	in := shellHookInput{
+		Event:          ev.Point,
+		Agent:          ev.Agent,
+		ConversationID: ev.ConversationID,
+		Content:        ev.Content,
+		Tool:           ev.Tool,
+	}
+	if ev.Result != nil {
+		in.Result = &shellHookResult{Content: ev.Result.Content, IsError: ev.Result.IsError}
+	}
+	data, err := json.Marshal(in)
+	if err != nil {
+		return err
+	}
+
+	var stdout, stderr bytes.Buffer
+	cmd := tool.ShellCommand(ctx, s.Command)
+	cmd.Stdin = bytes.NewReader(data)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	cmd.Env = append(os.Environ(), "KLAW_HOOK_EVENT="+string(ev.Point))
+	if err := cmd.Run(); err != nil {
+		if reason := strings.TrimSpace(stderr.String()); reason != "" {
+			return fmt.Errorf("%s", reason)
+		}
+		return fmt.Errorf("hook %q failed: %w", s.Command, err)
+	}
+
+	if len(bytes.TrimSpace(stdout.Bytes())) == 0 {
+		return nil
+	}
+	var out shellHookOutput
+	if err := json.Unmarshal(stdout.Bytes(), &out); err != nil {
+		return fmt.Errorf("hook %q wrote invalid JSON: %w", s.Command, err)
+	}
+	if out.Content != nil {
+		ev.Content = *out.Content
+	}
+	if len(out.Input) > 0 && ev.Tool != nil {
+		ev.Tool.Input = out.Input
+	}
+	if out.Result != nil && ev.Result != nil {
+		ev.Result = &tool.Result{Content: out.Result.Content, IsError: out.Result.IsError, Attachments: ev.Result.Attachments}
+	}
+	return nil
+}
+
+func (s *ShellHook) matchesTool(ev *HookEvent) bool {
+	if len(s.Tools) == 0 || ev.Tool == nil {
+		return true
+	}
+	for _, name := range s.Tools {
+		if name == ev.Tool.Name {
+			return true
+		}
+	}
+	return false
+}
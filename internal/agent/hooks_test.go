@@ -0,0 +1,169 @@
+package agent
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/eachlabs/klaw/internal/channel"
+	"github.com/eachlabs/klaw/internal/observe"
+	"github.com/eachlabs/klaw/internal/provider"
+	"github.com/eachlabs/klaw/internal/tool"
+)
+
+func TestHooks_AgentLoop(t *testing.T) {
+	calls := 0
+	prov := &sequentialProvider{
+		callCount: &calls,
+		responses: []*provider.ChatResponse{
+			{Content: []provider.ContentBlock{
+				{Type: "tool_use", ToolUse: &provider.ToolCall{ID: "t1", Name: "echo", Input: json.RawMessage(`{"msg":"token=secret"}`)}},
+				{Type: "tool_use", ToolUse: &provider.ToolCall{ID: "t2", Name: "bash", Input: json.RawMessage(`{}`)}},
+			}},
+			{Content: []provider.ContentBlock{{Type: "text", Text: "done"}}},
+		},
+	}
+	tools := tool.NewRegistry()
+	tools.Register(&echoTool{})
+
+	var mu sync.Mutex
+	var seen []HookPoint
+	hook := HookFunc(func(_ context.Context, ev *HookEvent) error {
+		mu.Lock()
+		seen = append(seen, ev.Point)
+		mu.Unlock()
+		switch ev.Point {
+		case HookPreMessage:
+			ev.Content = strings.ToUpper(ev.Content)
+		case HookPreTool:
+			if ev.Tool.Name == "bash" {
+				return errors.New("bash is not allowed")
+			}
+		case HookPostTool:
+			ev.Result.Content = strings.ReplaceAll(ev.Result.Content, "secret", "[REDACTED]")
+		}
+		return nil
+	})
+
+	ag := New(Config{Provider: prov, Channel: newTestChannel(), Tools: tools, Hooks: []Hook{hook}})
+	if err := ag.handleMessage(context.Background(), &channel.Message{Role: "user", Content: "run it"}); err != nil {
+		t.Fatal(err)
+	}
+
+	history := ag.History()
+	if history[0].Content != "RUN IT" {
+		t.Errorf("pre_message should rewrite the message, got %q", history[0].Content)
+	}
+	if got := history[2].ToolResult.Content; got != "token=[REDACTED]" {
+		t.Errorf("post_tool should redact the result, got %q", got)
+	}
+	if got := history[3].ToolResult; !got.IsError || !strings.Contains(got.Content, "bash is not allowed") {
+		t.Errorf("pre_tool should block bash, got %+v", got)
+	}
+	if seen[0] != HookPreMessage || seen[len(seen)-1] != HookPostMessage {
+		t.Errorf("unexpected hook order: %v", seen)
+	}
+}
+
+func TestHooks_RejectMessage(t *testing.T) {
+	hook := HookFunc(func(_ context.Context, ev *HookEvent) error {
+		if ev.Point == HookPreMessage {
+			return fmt.Errorf("no")
+		}
+		return nil
+	})
+	ag := New(Config{Provider: &infiniteToolProvider{}, Channel: newTestChannel(), Tools: tool.NewRegistry(), Hooks: []Hook{hook}})
+
+	err := ag.handleMessage(context.Background(), &channel.Message{Role: "user", Content: "hi"})
+	var agentErr *AgentError
+	if !errors.As(err, &agentErr) || agentErr.Code != ErrHookRejected {
+		t.Fatalf("expected rejected message, got %v", err)
+	}
+	if len(ag.History()) != 0 {
+		t.Error("rejected message should not be added to history")
+	}
+}
+
+func TestShellHook(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("rewrites tool input", func(t *testing.T) {
+		h := &ShellHook{Point: HookPreTool, Command: `echo '{"input": {"msg": "changed"}}'`}
+		ev := &HookEvent{Point: HookPreTool, Tool: &provider.ToolCall{Name: "echo", Input: json.RawMessage(`{"msg":"x"}`)}}
+		if err := h.Run(ctx, ev); err != nil {
+			t.Fatal(err)
+		}
+		if string(ev.Tool.Input) != `{"msg": "changed"}` {
+			t.Errorf("input not rewritten: %s", ev.Tool.Input)
+		}
+	})
+
+	t.Run("receives event on stdin", func(t *testing.T) {
+		h := &ShellHook{Point: HookPostTool, Command: `grep -q '"content":"secret"' && echo '{"result": {"content": "hidden"}}'`}
+		ev := &HookEvent{Point: HookPostTool, Tool: &provider.ToolCall{Name: "read"}, Result: &tool.Result{Content: "secret"}}
+		if err := h.Run(ctx, ev); err != nil {
+			t.Fatal(err)
+		}
+		if ev.Result.Content != "hidden" {
+			t.Errorf("result not rewritten: %q", ev.Result.Content)
+		}
+	})
+
+	t.Run("non-zero exit fails with stderr", func(t *testing.T) {
+		h := &ShellHook{Point: HookPreMessage, Command: `echo "contains PII" >&2; exit 1`}
+		err := h.Run(ctx, &HookEvent{Point: HookPreMessage, Content: "hi"})
+		if err == nil || err.Error() != "contains PII" {
+			t.Errorf("expected stderr as error, got %v", err)
+		}
+	})
+
+	t.Run("skips other events and tools", func(t *testing.T) {
+		h := &ShellHook{Point: HookPreTool, Command: "exit 1", Tools: []string{"bash"}}
+		if err := h.Run(ctx, &HookEvent{Point: HookPreMessage}); err != nil {
+			t.Errorf("other event should be skipped: %v", err)
+		}
+		if err := h.Run(ctx, &HookEvent{Point: HookPreTool, Tool: &provider.ToolCall{Name: "read"}}); err != nil {
+			t.Errorf("other tool should be skipped: %v", err)
+		}
+	})
+}
+
+func TestEventHook(t *testing.T) {
+	hub := observe.NewEventHub()
+	hook := EventHook(hub, "main")
+	ctx := context.Background()
+
+	_ = hook.Run(ctx, &HookEvent{Point: HookPreMessage, Agent: "coder", ConversationID: "c1", Content: "hi"})
+	_ = hook.Run(ctx, &HookEvent{Point: HookPreTool, Agent: "coder", Tool: &provider.ToolCall{Name: "bash", Input: json.RawMessage(`{"cmd":"ls"}`)}})
+	_ = hook.Run(ctx, &HookEvent{Point: HookPostTool, Agent: "coder", Tool: &provider.ToolCall{Name: "bash"}, Result: &tool.Result{Content: strings.Repeat("x", 3000), IsError: true}})
+	// The agent reports errors under its own, empty, name
+	_ = hook.Run(ctx, &HookEvent{Point: HookError, ConversationID: "c1", Content: "boom"})
+	_ = hook.Run(ctx, &HookEvent{Point: HookPostMessage, ConversationID: "c2", Content: "bye"})
+
+	events := hub.Recent(observe.EventFilter{}, 10)
+	want := []struct{ kind, agent string }{
+		{observe.EventMessage, "coder"},
+		{observe.EventToolCall, "coder"},
+		{observe.EventToolResult, "coder"},
+		{observe.EventError, "coder"},
+		{observe.EventResponse, "main"},
+	}
+	if len(events) != len(want) {
+		t.Fatalf("expected %d events, got %+v", len(want), events)
+	}
+	for i, w := range want {
+		if events[i].Kind != w.kind || events[i].Agent != w.agent {
+			t.Errorf("event %d: expected %s from %s, got %s from %s", i, w.kind, w.agent, events[i].Kind, events[i].Agent)
+		}
+	}
+	if events[1].Tool != "bash" || events[1].Content != `{"cmd":"ls"}` {
+		t.Errorf("tool call not recorded: %+v", events[1])
+	}
+	if !events[2].IsError || len(events[2].Content) != maxEventContent+3 {
+		t.Errorf("tool result should be truncated and marked as error: %d bytes", len(events[2].Content))
+	}
+}
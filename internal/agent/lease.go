@@ -0,0 +1,109 @@
+package agent
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/eachlabs/klaw/internal/lease"
+)
+
+// defaultLeaseTTL is how long a conversation's lease lasts after its last
+// activity when Config.LeaseTTL is not set.
+const defaultLeaseTTL = 2 * time.Minute
+
+// leases decides which conversations this agent answers when replicas
+// share a channel: the ones whose lease it holds. A lease is taken on a
+// conversation's first message, renewed by every message and while a turn
+// runs, and lapses ttl after the last activity, so another replica takes
+// the conversation over once this one is gone.
+type leases struct {
+	store  lease.Store
+	owner  string
+	ttl    time.Duration
+	agent  *Agent
+	mu     sync.Mutex
+	leased map[string]struct{} // conversations whose lease was taken, released on shutdown
+}
+
+func newLeases(a *Agent, store lease.Store, owner string, ttl time.Duration) *leases {
+	if store == nil {
+		return nil
+	}
+	if ttl <= 0 {
+		ttl = defaultLeaseTTL
+	}
+	return &leases{store: store, owner: owner, ttl: ttl, agent: a, leased: make(map[string]struct{})}
+}
+
+// own takes or renews the lease on the conversation and reports whether
+// this agent answers it. When the store fails, the agent answers anyway:
+// a reply twice beats none.
+func (l *leases) own(ctx context.Context, conversationID string) bool {
+	if l == nil {
+		return true
+	}
+	ok, err := l.store.Acquire(ctx, conversationID, l.owner, l.ttl)
+	if err != nil {
+		l.agent.logger.Warn("failed to take conversation lease", "conversation", conversationID, "error", err)
+		return true
+	}
+	if !ok {
+		l.agent.logger.Debug("conversation leased by another replica", "conversation", conversationID)
+		return false
+	}
+	l.mu.Lock()
+	l.leased[conversationID] = struct{}{}
+	l.mu.Unlock()
+	return true
+}
+
+// hold renews the lease on the conversation until the returned function is
+// called, for turns outlasting the ttl.
+func (l *leases) hold(ctx context.Context, conversationID string) func() {
+	if l == nil {
+		return func() {}
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		ticker := time.NewTicker(l.ttl / 3)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				l.own(ctx, conversationID)
+			}
+		}
+	}()
+	return func() {
+		cancel()
+		<-done
+	}
+}
+
+// releaseAll releases the leases this agent took, so other replicas take
+// its conversations over without waiting for them to lapse.
+func (l *leases) releaseAll() {
+	if l == nil {
+		return
+	}
+	l.mu.Lock()
+	ids := make([]string, 0, len(l.leased))
+	for id := range l.leased {
+		ids = append(ids, id)
+	}
+	l.leased = make(map[string]struct{})
+	l.mu.Unlock()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	for _, id := range ids {
+		if err := l.store.Release(ctx, id, l.owner); err != nil {
+			l.agent.logger.Warn("failed to release conversation lease", "conversation", id, "error", err)
+		}
+	}
+}
@@ -0,0 +1,42 @@
+package agent
+
+import "sync"
+
+// conversationLocks serializes turns within a conversation while letting
+// different conversations run concurrently.
+type conversationLocks struct {
+	mu    sync.Mutex
+	locks map[string]*conversationLock
+}
+
+type conversationLock struct {
+	mu   sync.Mutex
+	refs int // holders and waiters; the entry is dropped at zero
+}
+
+func newConversationLocks() *conversationLocks {
+	return &conversationLocks{locks: make(map[string]*conversationLock)}
+}
+
+// lock blocks until the conversation is free and returns the unlock func.
+func (c *conversationLocks) lock(conversationID string) (unlock func()) {
+	c.mu.Lock()
+	l, ok := c.locks[conversationID]
+	if !ok {
+		l = &conversationLock{}
+		c.locks[conversationID] = l
+	}
+	l.refs++
+	c.mu.Unlock()
+
+	l.mu.Lock()
+	return func() {
+		l.mu.Unlock()
+		c.mu.Lock()
+		l.refs--
+		if l.refs == 0 {
+			delete(c.locks, conversationID)
+		}
+		c.mu.Unlock()
+	}
+}
@@ -0,0 +1,23 @@
+package agent
+
+import "context"
+
+// metricsAgent is the agent label of metrics; the agent's own profile has
+// no name.
+func metricsAgent(name string) string {
+	if name == "" {
+		return "default"
+	}
+	return name
+}
+
+// turnStatus is the outcome of a turn for metrics: ok, error or stopped.
+func turnStatus(ctx context.Context, err error) string {
+	switch {
+	case ctx.Err() != nil:
+		return "stopped"
+	case err != nil:
+		return "error"
+	}
+	return "ok"
+}
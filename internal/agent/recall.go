@@ -0,0 +1,101 @@
+package agent
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/eachlabs/klaw/internal/memory"
+	"github.com/eachlabs/klaw/internal/provider"
+)
+
+// RecallConfig adds memories similar to each message to the turn's context
+// and remembers finished exchanges.
+type RecallConfig struct {
+	Memory   *memory.SemanticMemory // nil disables recall
+	TopK     int                    // memories added per turn (default: 5)
+	MinScore float64                // minimum similarity (default: 0.3)
+}
+
+// memoryAgent is the name memories of profile p are kept under, matching
+// the owner of facts saved with memory_store.
+func memoryAgent(p *Profile) string {
+	if p.Name != "" {
+		return p.Name
+	}
+	return memory.DefaultAgent
+}
+
+// recallPrompt returns a system prompt section with the memories of p most
+// similar to content, or "" when there are none.
+func (a *Agent) recallPrompt(ctx context.Context, p *Profile, content string) string {
+	if a.recall.Memory == nil {
+		return ""
+	}
+	topK := a.recall.TopK
+	if topK <= 0 {
+		topK = 5
+	}
+	minScore := a.recall.MinScore
+	if minScore <= 0 {
+		minScore = 0.3
+	}
+
+	memories, err := a.recall.Memory.Recall(ctx, memoryAgent(p), content, topK, minScore)
+	if err != nil {
+		a.logger.Warn("memory recall failed", "agent", memoryAgent(p), "error", err)
+		return ""
+	}
+	if len(memories) == 0 {
+		return ""
+	}
+
+	var sb strings.Builder
+	sb.WriteString("\n\n# Relevant Memory\n\nRecalled from earlier conversations and saved facts; use what helps, ignore the rest:\n")
+	for _, m := range memories {
+		fmt.Fprintf(&sb, "- (%s, %s) %s\n", m.CreatedAt.Format("2006-01-02"), m.Kind, truncate(m.Content, 1000))
+	}
+	return sb.String()
+}
+
+// rememberTurn keeps a finished exchange in the journal and semantic memory.
+func (a *Agent) rememberTurn(ctx context.Context, p *Profile, conversationID, userContent, reply string) {
+	if strings.TrimSpace(reply) == "" {
+		return
+	}
+	if a.journal != nil {
+		ep := memory.Episode{Agent: memoryAgent(p), ConversationID: conversationID, User: userContent, Reply: reply}
+		if err := a.journal.Append(ep); err != nil {
+			a.logger.Warn("failed to record turn in journal", "conversation", conversationID, "error", err)
+		}
+	}
+	if a.recall.Memory == nil {
+		return
+	}
+	exchange := fmt.Sprintf("User: %s\nAssistant: %s", userContent, reply)
+	if err := a.recall.Memory.Remember(ctx, memoryAgent(p), memory.KindConversation, "", exchange); err != nil {
+		a.logger.Warn("failed to remember turn", "conversation", conversationID, "error", err)
+	}
+}
+
+// DailySummarizer returns the summarizer of episodic memory, of memory
+// compaction and of archived Slack threads, backed by prov. It also tags
+// the message logs for conversation analytics.
+func DailySummarizer(prov provider.Provider) memory.SummarizeFunc {
+	return func(ctx context.Context, prompt string) (string, error) {
+		resp, err := prov.Chat(ctx, &provider.ChatRequest{
+			Messages:  []provider.Message{{Role: "user", Content: prompt}},
+			MaxTokens: 4096,
+		})
+		if err != nil {
+			return "", err
+		}
+		var sb strings.Builder
+		for _, block := range resp.Content {
+			if block.Type == "text" {
+				sb.WriteString(block.Text)
+			}
+		}
+		return sb.String(), nil
+	}
+}
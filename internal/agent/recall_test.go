@@ -0,0 +1,102 @@
+package agent
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/eachlabs/klaw/internal/channel"
+	"github.com/eachlabs/klaw/internal/memory"
+	"github.com/eachlabs/klaw/internal/provider"
+	"github.com/eachlabs/klaw/internal/tool"
+)
+
+// keywordEmbedder marks which of a few keywords a text mentions.
+type keywordEmbedder struct{}
+
+func (keywordEmbedder) Embed(_ context.Context, texts []string) ([][]float32, error) {
+	keywords := []string{"pricing", "deploy", "lunch"}
+	vectors := make([][]float32, len(texts))
+	for i, text := range texts {
+		vectors[i] = make([]float32, len(keywords))
+		for j, k := range keywords {
+			if strings.Contains(strings.ToLower(text), k) {
+				vectors[i][j] = 1
+			}
+		}
+	}
+	return vectors, nil
+}
+
+// systemRecorder captures the system prompt of each streamed request.
+type systemRecorder struct {
+	mockChatProvider
+	prompts []string
+}
+
+func (r *systemRecorder) Stream(ctx context.Context, req *provider.ChatRequest) (<-chan provider.StreamEvent, error) {
+	r.prompts = append(r.prompts, req.System)
+	return r.mockChatProvider.Stream(ctx, req)
+}
+
+func TestHandleMessage_Recall(t *testing.T) {
+	store, err := memory.NewSQLiteVectorStore(filepath.Join(t.TempDir(), "vectors.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	mem := memory.NewSemanticMemory(keywordEmbedder{}, store)
+	defer func() { _ = mem.Close() }()
+	ctx := context.Background()
+	if err := mem.Remember(ctx, "support", memory.KindFact, "f1", "The pricing page lists three plans"); err != nil {
+		t.Fatal(err)
+	}
+
+	prov := &systemRecorder{mockChatProvider: mockChatProvider{
+		resp: &provider.ChatResponse{Content: []provider.ContentBlock{{Type: "text", Text: "We moved it to Thursday."}}},
+	}}
+	journal := memory.NewJournal(t.TempDir())
+	ag := New(Config{
+		Provider:     prov,
+		Channel:      newTestChannel(),
+		Tools:        tool.NewRegistry(),
+		SystemPrompt: "base",
+		AgentName:    "support",
+		Recall:       RecallConfig{Memory: mem},
+		Journal:      journal,
+	})
+
+	if err := ag.handleMessage(ctx, &channel.Message{Role: "user", Content: "What did we say about pricing?"}); err != nil {
+		t.Fatal(err)
+	}
+	if len(prov.prompts) != 1 || !strings.Contains(prov.prompts[0], "The pricing page lists three plans") {
+		t.Fatalf("expected the pricing fact in the system prompt, got %q", prov.prompts)
+	}
+	if ag.SystemPrompt() != "base" {
+		t.Errorf("recall should not change the agent's prompt, got %q", ag.SystemPrompt())
+	}
+
+	if err := ag.handleMessage(ctx, &channel.Message{Role: "user", Content: "When is the deploy?"}); err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(prov.prompts[1], "pricing") {
+		t.Errorf("unrelated memory was recalled: %q", prov.prompts[1])
+	}
+
+	// Finished turns are remembered
+	got, err := mem.Recall(ctx, "support", "deploy", 5, 0.5)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 1 || got[0].Kind != memory.KindConversation || !strings.Contains(got[0].Content, "Thursday") {
+		t.Errorf("expected the deploy exchange to be remembered, got %+v", got)
+	}
+	episodes, err := journal.Day(time.Now())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(episodes) != 2 || episodes[1].Agent != "support" || episodes[1].User != "When is the deploy?" {
+		t.Errorf("expected both turns in the journal, got %+v", episodes)
+	}
+}
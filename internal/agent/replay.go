@@ -0,0 +1,190 @@
+package agent
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sync"
+
+	"github.com/eachlabs/klaw/internal/channel"
+	"github.com/eachlabs/klaw/internal/provider"
+	"github.com/eachlabs/klaw/internal/tool"
+)
+
+// ReplayConfig configures a replay.
+type ReplayConfig struct {
+	// Tools runs the tool calls of the replay. Nil answers each call with
+	// its recorded result, so nothing is executed.
+	Tools *tool.Registry
+}
+
+// ReplayResult is the outcome of a replay.
+type ReplayResult struct {
+	Steps []ReplayStep
+	Reply string
+	Error string
+
+	// Diverged is the index of the first step whose request differed from
+	// the recording, or -1 when the replay matched it.
+	Diverged int
+}
+
+// ReplayStep is a step of a replay.
+type ReplayStep struct {
+	Request   []provider.Message
+	Matches   bool // the request equals the recorded one
+	ToolCalls []provider.ToolCall
+	Tools     []TranscriptTool // the results of the replay's tool calls
+}
+
+// Replay re-executes the turn of t with the agent loop against a mock
+// provider that gives the recorded responses.
+func Replay(ctx context.Context, t *Transcript, cfg ReplayConfig) (*ReplayResult, error) {
+	responses := make([]*provider.ChatResponse, 0, len(t.Steps))
+	for i, step := range t.Steps {
+		if step.Response == nil {
+			break // the recorded turn failed here; the replay does too
+		}
+		responses = append(responses, t.Steps[i].Response)
+	}
+	mock := provider.NewMock(responses...)
+
+	tools := cfg.Tools
+	if tools == nil {
+		tools = recordedTools(t)
+	}
+	ag := New(Config{
+		Provider:       mock,
+		Channel:        replayChannel{},
+		Tools:          tools,
+		InitialHistory: append([]provider.Message(nil), t.History...),
+		SystemPrompt:   t.System,
+		MaxTokens:      t.MaxTokens,
+		MaxIterations:  len(t.Steps) + 1,
+		AgentName:      t.Agent,
+		Model:          t.Model,
+	})
+
+	res := &ReplayResult{Diverged: -1}
+	if err := ag.handleMessage(ctx, &channel.Message{Role: "user", Content: t.Message}); err != nil {
+		res.Error = err.Error()
+	}
+
+	history := ag.History()
+	for i, req := range mock.Requests() {
+		step := ReplayStep{Request: req.Messages}
+		if i < len(t.Steps) {
+			step.Matches = reflect.DeepEqual(normalize(req.Messages), normalize(t.Steps[i].Request))
+		}
+		if !step.Matches && res.Diverged < 0 {
+			res.Diverged = i
+		}
+		if i < len(responses) {
+			for _, block := range responses[i].Content {
+				if block.ToolUse != nil {
+					step.ToolCalls = append(step.ToolCalls, *block.ToolUse)
+				}
+			}
+		}
+		step.Tools = toolResults(history, step.ToolCalls)
+		res.Steps = append(res.Steps, step)
+	}
+	if len(mock.Requests()) != len(t.Steps) && res.Diverged < 0 {
+		res.Diverged = min(len(mock.Requests()), len(t.Steps))
+	}
+	if n := len(history); res.Error == "" && n > 0 && history[n-1].Role == "assistant" {
+		res.Reply = history[n-1].Content
+	}
+	return res, nil
+}
+
+// normalize makes messages comparable across a JSON round trip, which
+// turns empty slices into nil and reformats tool inputs.
+func normalize(msgs []provider.Message) []provider.Message {
+	data, _ := json.Marshal(msgs)
+	var out []provider.Message
+	_ = json.Unmarshal(data, &out)
+	for i := range out {
+		for j := range out[i].ToolCalls {
+			out[i].ToolCalls[j].Input = compactJSON(out[i].ToolCalls[j].Input)
+		}
+	}
+	return out
+}
+
+func compactJSON(raw json.RawMessage) json.RawMessage {
+	var v any
+	if json.Unmarshal(raw, &v) != nil {
+		return raw
+	}
+	out, _ := json.Marshal(v)
+	return out
+}
+
+// toolResults finds the results of calls in history.
+func toolResults(history []provider.Message, calls []provider.ToolCall) []TranscriptTool {
+	var out []TranscriptTool
+	for _, tc := range calls {
+		tt := TranscriptTool{ID: tc.ID, Name: tc.Name, Input: tc.Input}
+		for _, m := range history {
+			if m.ToolResult != nil && m.ToolResult.ToolUseID == tc.ID {
+				tt.Result, tt.IsError = m.ToolResult.Content, m.ToolResult.IsError
+			}
+		}
+		out = append(out, tt)
+	}
+	return out
+}
+
+// recordedTools returns tools that answer each call with the result
+// recorded for the same tool and input.
+func recordedTools(t *Transcript) *tool.Registry {
+	reg := tool.NewRegistry()
+	results := &recordedResults{queue: make(map[string][]TranscriptTool)}
+	for _, step := range t.Steps {
+		for _, tt := range step.Tools {
+			key := tt.Name + "\x00" + string(compactJSON(tt.Input))
+			results.queue[key] = append(results.queue[key], tt)
+		}
+	}
+	for _, def := range t.Tools {
+		reg.Register(&recordedTool{def: def, results: results})
+	}
+	return reg
+}
+
+type recordedResults struct {
+	mu    sync.Mutex
+	queue map[string][]TranscriptTool // by tool name and input
+}
+
+type recordedTool struct {
+	def     provider.ToolDefinition
+	results *recordedResults
+}
+
+func (t *recordedTool) Name() string            { return t.def.Name }
+func (t *recordedTool) Description() string     { return t.def.Description }
+func (t *recordedTool) Schema() json.RawMessage { return t.def.InputSchema }
+
+func (t *recordedTool) Execute(ctx context.Context, params json.RawMessage) (*tool.Result, error) {
+	t.results.mu.Lock()
+	defer t.results.mu.Unlock()
+	key := t.def.Name + "\x00" + string(compactJSON(params))
+	queue := t.results.queue[key]
+	if len(queue) == 0 {
+		return &tool.Result{Content: fmt.Sprintf("replay: no recorded result for %s %s", t.def.Name, params), IsError: true}, nil
+	}
+	t.results.queue[key] = queue[1:]
+	return &tool.Result{Content: queue[0].Result, IsError: queue[0].IsError}, nil
+}
+
+// replayChannel discards what the agent sends during a replay.
+type replayChannel struct{}
+
+func (replayChannel) Start(context.Context) error                  { return nil }
+func (replayChannel) Stop() error                                  { return nil }
+func (replayChannel) Name() string                                 { return "replay" }
+func (replayChannel) Receive() <-chan *channel.Message             { return nil }
+func (replayChannel) Send(context.Context, *channel.Message) error { return nil }
@@ -0,0 +1,87 @@
+package agent
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/eachlabs/klaw/internal/channel"
+	"github.com/eachlabs/klaw/internal/provider"
+	"github.com/eachlabs/klaw/internal/tool"
+)
+
+// upperTool is an echo tool with a different output, to make a replay
+// diverge.
+type upperTool struct{ echoTool }
+
+func (u *upperTool) Execute(_ context.Context, _ json.RawMessage) (*tool.Result, error) {
+	return &tool.Result{Content: "SOMETHING ELSE"}, nil
+}
+
+func TestReplay(t *testing.T) {
+	store := &TranscriptStore{Dir: t.TempDir()}
+	callCount := 0
+	prov := &sequentialProvider{
+		responses: []*provider.ChatResponse{
+			{Content: []provider.ContentBlock{
+				{Type: "text", Text: "Checking."},
+				{Type: "tool_use", ToolUse: &provider.ToolCall{ID: "tc1", Name: "echo", Input: json.RawMessage(`{"msg": "hello"}`)}},
+			}},
+			{Content: []provider.ContentBlock{{Type: "text", Text: "It said hello."}}},
+		},
+		callCount: &callCount,
+	}
+	reg := tool.NewRegistry()
+	reg.Register(&echoTool{})
+	ag := New(Config{
+		Provider:       prov,
+		Channel:        newTestChannel(),
+		Tools:          reg,
+		AgentName:      "coder",
+		InitialHistory: []provider.Message{{Role: "user", Content: "hi"}, {Role: "assistant", Content: "Hello!"}},
+		Transcripts:    store,
+	})
+	if err := ag.handleMessage(context.Background(), &channel.Message{Role: "user", Content: "run echo"}); err != nil {
+		t.Fatal(err)
+	}
+
+	transcripts, err := store.List("default")
+	if err != nil || len(transcripts) != 1 {
+		t.Fatalf("List = %d transcripts, %v", len(transcripts), err)
+	}
+	tr := transcripts[0]
+	if tr.Agent != "coder" || len(tr.History) != 2 || len(tr.Steps) != 2 || tr.Reply != "It said hello." {
+		t.Fatalf("transcript = %+v", tr)
+	}
+	if got := tr.Steps[0].Tools; len(got) != 1 || got[0].Result != "hello" {
+		t.Errorf("recorded tools = %+v", got)
+	}
+
+	// Recorded tool results: the replay takes the same steps
+	res, err := Replay(context.Background(), tr, ReplayConfig{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Diverged != -1 || res.Reply != "It said hello." || len(res.Steps) != 2 {
+		t.Errorf("replay = %+v", res)
+	}
+	for i, step := range res.Steps {
+		if !step.Matches {
+			t.Errorf("step %d differs from the recording", i+1)
+		}
+	}
+
+	// Tools that behave differently change the second request
+	changed := tool.NewRegistry()
+	changed.Register(&upperTool{})
+	res, err = Replay(context.Background(), tr, ReplayConfig{Tools: changed})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Diverged != 1 {
+		t.Errorf("diverged at %d, want 1", res.Diverged)
+	}
+	if got := res.Steps[0].Tools; len(got) != 1 || got[0].Result != "SOMETHING ELSE" {
+		t.Errorf("replayed tools = %+v", got)
+	}
+}
@@ -0,0 +1,82 @@
+package agent
+
+import (
+	"context"
+
+	"github.com/eachlabs/klaw/internal/channel"
+	"github.com/eachlabs/klaw/internal/observe"
+	"github.com/eachlabs/klaw/internal/provider"
+	"github.com/eachlabs/klaw/internal/tool"
+)
+
+// Profile is the agent a turn runs as. Empty fields fall back to the
+// agent's own configuration.
+type Profile struct {
+	Name         string
+	SystemPrompt string
+	Provider     provider.Provider
+	Model        string
+	Tools        *tool.Registry
+	SkillConfig  map[string]map[string]string
+}
+
+// Router picks the profile that handles a message, so one channel can be
+// served by several agents. A nil profile keeps the agent's own.
+type Router interface {
+	Route(ctx context.Context, conversationID string, msg *channel.Message) (*Profile, error)
+}
+
+type profileKey struct{}
+
+// route resolves the profile of a turn and stores it in the context.
+func (a *Agent) route(ctx context.Context, conversationID string, msg *channel.Message) (context.Context, error) {
+	if a.router == nil {
+		return ctx, nil
+	}
+	routeCtx, span := observe.Tracer().Start(ctx, "klaw.route")
+	p, err := a.router.Route(routeCtx, conversationID, msg)
+	if p != nil && p.Name != "" {
+		span.SetAttributes(observe.AttrAgent.String(p.Name))
+	}
+	observe.EndSpan(span, err)
+	if err != nil {
+		return ctx, &AgentError{Code: ErrRouting, Message: "could not route message", Cause: err}
+	}
+	if p == nil {
+		return ctx, nil
+	}
+	return context.WithValue(ctx, profileKey{}, p), nil
+}
+
+// profile returns the profile of the current turn with defaults filled in.
+func (a *Agent) profile(ctx context.Context) *Profile {
+	p := &Profile{
+		Name:         a.agentName,
+		SystemPrompt: a.SystemPrompt(),
+		Provider:     a.provider,
+		Model:        a.model,
+		Tools:        a.tools,
+		SkillConfig:  a.skillConfig,
+	}
+	routed, ok := ctx.Value(profileKey{}).(*Profile)
+	if !ok {
+		return p
+	}
+	if routed.Name != "" {
+		p.Name = routed.Name
+	}
+	if routed.SystemPrompt != "" {
+		p.SystemPrompt = routed.SystemPrompt
+	}
+	if routed.Provider != nil {
+		p.Provider = routed.Provider
+		p.Model = routed.Model
+	}
+	if routed.Tools != nil {
+		p.Tools = routed.Tools
+	}
+	if routed.SkillConfig != nil {
+		p.SkillConfig = routed.SkillConfig
+	}
+	return p
+}
@@ -0,0 +1,91 @@
+package agent
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/eachlabs/klaw/internal/channel"
+	"github.com/eachlabs/klaw/internal/provider"
+	"github.com/eachlabs/klaw/internal/tool"
+)
+
+type routerFunc func(ctx context.Context, conversationID string, msg *channel.Message) (*Profile, error)
+
+func (f routerFunc) Route(ctx context.Context, conversationID string, msg *channel.Message) (*Profile, error) {
+	return f(ctx, conversationID, msg)
+}
+
+func TestHandleMessage_RoutedProfile(t *testing.T) {
+	mainCalls, routedCalls := 0, 0
+	mainProv := &sequentialProvider{callCount: &mainCalls}
+	routedProv := &sequentialProvider{
+		callCount: &routedCalls,
+		responses: []*provider.ChatResponse{
+			{Content: []provider.ContentBlock{
+				{Type: "tool_use", ToolUse: &provider.ToolCall{ID: "t1", Name: "echo", Input: json.RawMessage(`{"msg":"hi"}`)}},
+			}},
+			{Content: []provider.ContentBlock{{Type: "text", Text: "done"}}},
+		},
+	}
+	routedTools := tool.NewRegistry()
+	routedTools.Register(&echoTool{})
+
+	ag := New(Config{
+		Provider: mainProv,
+		Channel:  newTestChannel(),
+		Tools:    tool.NewRegistry(),
+		Router: routerFunc(func(_ context.Context, _ string, msg *channel.Message) (*Profile, error) {
+			if msg.Content != "for coder" {
+				return nil, nil
+			}
+			return &Profile{Name: "coder", Provider: routedProv, Tools: routedTools}, nil
+		}),
+	})
+
+	if err := ag.handleMessage(context.Background(), &channel.Message{Role: "user", Content: "for coder"}); err != nil {
+		t.Fatal(err)
+	}
+	if routedCalls != 2 || mainCalls != 0 {
+		t.Errorf("routed turn should use the routed provider, got main=%d routed=%d", mainCalls, routedCalls)
+	}
+	if got := ag.History()[2].ToolResult; got == nil || got.IsError {
+		t.Errorf("routed turn should use the routed tools, got %+v", got)
+	}
+
+	if err := ag.handleMessage(context.Background(), &channel.Message{Role: "user", Content: "anything"}); err != nil {
+		t.Fatal(err)
+	}
+	if mainCalls != 1 {
+		t.Errorf("unrouted turn should use the agent's provider, got %d calls", mainCalls)
+	}
+}
+
+func TestHandleAndReport_RoutingError(t *testing.T) {
+	ch := newTestChannel()
+	ag := New(Config{
+		Provider: &infiniteToolProvider{},
+		Channel:  ch,
+		Tools:    tool.NewRegistry(),
+		Router: routerFunc(func(context.Context, string, *channel.Message) (*Profile, error) {
+			return nil, errors.New("agent not found: nobody (available: coder, writer)")
+		}),
+	})
+
+	ag.handleAndReport(context.Background(), &channel.Message{Role: "user", Content: "@nobody hi"})
+
+	var reply string
+	for len(ch.sent) > 0 {
+		if msg := <-ch.sent; msg.Role == "assistant" {
+			reply = msg.Content
+		}
+	}
+	if !strings.Contains(reply, "available: coder, writer") {
+		t.Errorf("expected the routing error as reply, got %q", reply)
+	}
+	if len(ag.History()) != 0 {
+		t.Error("unrouted message should not be added to history")
+	}
+}
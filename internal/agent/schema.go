@@ -0,0 +1,176 @@
+package agent
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// jsonSchema is the subset of JSON Schema that structured output is checked
+// against: type, properties, required, additionalProperties, items and enum.
+type jsonSchema struct {
+	Type                 any                    `json:"type"` // string or list of strings
+	Properties           map[string]*jsonSchema `json:"properties"`
+	Required             []string               `json:"required"`
+	AdditionalProperties *bool                  `json:"additionalProperties"`
+	Items                *jsonSchema            `json:"items"`
+	Enum                 []any                  `json:"enum"`
+}
+
+// ValidateOutput extracts the JSON document from a model reply (which may be
+// wrapped in a code fence) and checks it against schema. It returns the
+// compacted document.
+func ValidateOutput(schema json.RawMessage, output string) (string, error) {
+	var s jsonSchema
+	if err := json.Unmarshal(schema, &s); err != nil {
+		return "", fmt.Errorf("invalid schema: %w", err)
+	}
+
+	text := extractJSON(output)
+	var doc any
+	if err := json.Unmarshal([]byte(text), &doc); err != nil {
+		return "", fmt.Errorf("output is not valid JSON: %w", err)
+	}
+	if err := s.validate("$", doc); err != nil {
+		return "", err
+	}
+
+	compact, err := json.Marshal(doc)
+	if err != nil {
+		return "", err
+	}
+	return string(compact), nil
+}
+
+// StructuredOutputPrompt is the instruction appended to a prompt that must
+// be answered with JSON matching schema.
+func StructuredOutputPrompt(schema json.RawMessage) string {
+	return fmt.Sprintf("\n\nRespond with ONLY a JSON document matching this JSON schema, with no prose before or after it:\n%s", schema)
+}
+
+// RepairPrompt asks the model to correct output that failed validation.
+func RepairPrompt(output string, err error) string {
+	return fmt.Sprintf("Your previous response did not match the required JSON schema: %v\n\nPrevious response:\n%s\n\nReply with ONLY the corrected JSON document.", err, output)
+}
+
+// extractJSON strips a surrounding ``` fence and any text around the
+// outermost JSON object or array.
+func extractJSON(output string) string {
+	text := strings.TrimSpace(output)
+	if start := strings.Index(text, "```"); start >= 0 {
+		rest := text[start+3:]
+		if nl := strings.Index(rest, "\n"); nl >= 0 {
+			rest = rest[nl+1:]
+		}
+		if end := strings.Index(rest, "```"); end >= 0 {
+			return strings.TrimSpace(rest[:end])
+		}
+	}
+	if start := strings.IndexAny(text, "{["); start > 0 {
+		if end := strings.LastIndexAny(text, "}]"); end > start {
+			return text[start : end+1]
+		}
+	}
+	return text
+}
+
+func (s *jsonSchema) validate(path string, v any) error {
+	if len(s.Enum) > 0 && !inEnum(s.Enum, v) {
+		return fmt.Errorf("%s: value %v is not one of the allowed values", path, v)
+	}
+
+	if types := s.types(); len(types) > 0 {
+		actual := jsonType(v)
+		ok := false
+		for _, t := range types {
+			if t == actual || (t == "number" && actual == "integer") {
+				ok = true
+				break
+			}
+		}
+		if !ok {
+			return fmt.Errorf("%s: expected %s, got %s", path, strings.Join(types, " or "), actual)
+		}
+	}
+
+	switch val := v.(type) {
+	case map[string]any:
+		for _, name := range s.Required {
+			if _, ok := val[name]; !ok {
+				return fmt.Errorf("%s: missing required property %q", path, name)
+			}
+		}
+		keys := make([]string, 0, len(val))
+		for k := range val {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			prop, ok := s.Properties[k]
+			if !ok {
+				if s.AdditionalProperties != nil && !*s.AdditionalProperties {
+					return fmt.Errorf("%s: unexpected property %q", path, k)
+				}
+				continue
+			}
+			if err := prop.validate(path+"."+k, val[k]); err != nil {
+				return err
+			}
+		}
+	case []any:
+		if s.Items != nil {
+			for i, item := range val {
+				if err := s.Items.validate(fmt.Sprintf("%s[%d]", path, i), item); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return nil
+}
+
+func (s *jsonSchema) types() []string {
+	switch t := s.Type.(type) {
+	case string:
+		return []string{t}
+	case []any:
+		var types []string
+		for _, v := range t {
+			if name, ok := v.(string); ok {
+				types = append(types, name)
+			}
+		}
+		return types
+	}
+	return nil
+}
+
+func jsonType(v any) string {
+	switch val := v.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case float64:
+		if val == float64(int64(val)) {
+			return "integer"
+		}
+		return "number"
+	case string:
+		return "string"
+	case []any:
+		return "array"
+	default:
+		return "object"
+	}
+}
+
+func inEnum(enum []any, v any) bool {
+	for _, e := range enum {
+		if fmt.Sprint(e) == fmt.Sprint(v) && jsonType(e) == jsonType(v) {
+			return true
+		}
+	}
+	return false
+}
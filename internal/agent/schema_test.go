@@ -0,0 +1,98 @@
+package agent
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/eachlabs/klaw/internal/provider"
+	"github.com/eachlabs/klaw/internal/tool"
+)
+
+var testSchema = json.RawMessage(`{
+	"type": "object",
+	"required": ["status", "items"],
+	"additionalProperties": false,
+	"properties": {
+		"status": {"type": "string", "enum": ["ok", "failed"]},
+		"count": {"type": "integer"},
+		"items": {"type": "array", "items": {"type": "string"}}
+	}
+}`)
+
+func TestValidateOutput(t *testing.T) {
+	tests := []struct {
+		name    string
+		output  string
+		want    string
+		wantErr string
+	}{
+		{"plain", `{"status": "ok", "items": ["a"]}`, `{"items":["a"],"status":"ok"}`, ""},
+		{"fenced", "Here you go:\n```json\n{\"status\": \"ok\", \"items\": []}\n```", `{"items":[],"status":"ok"}`, ""},
+		{"surrounding prose", `Result: {"status": "failed", "items": [], "count": 2} done`, `{"count":2,"items":[],"status":"failed"}`, ""},
+		{"not json", "all good", "", "not valid JSON"},
+		{"missing required", `{"status": "ok"}`, "", `missing required property "items"`},
+		{"wrong type", `{"status": "ok", "items": [1]}`, "", "$.items[0]: expected string, got integer"},
+		{"not in enum", `{"status": "maybe", "items": []}`, "", "$.status"},
+		{"integer", `{"status": "ok", "items": [], "count": 1.5}`, "", "expected integer, got number"},
+		{"extra property", `{"status": "ok", "items": [], "extra": true}`, "", `unexpected property "extra"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ValidateOutput(testSchema, tt.output)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("expected error containing %q, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want {
+				t.Errorf("got %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRunOnce_OutputSchemaRepair(t *testing.T) {
+	text := func(s string) *provider.ChatResponse {
+		return &provider.ChatResponse{Content: []provider.ContentBlock{{Type: "text", Text: s}}}
+	}
+
+	calls := 0
+	result, err := RunOnce(context.Background(), RunOnceConfig{
+		Provider: &sequentialProvider{callCount: &calls, responses: []*provider.ChatResponse{
+			text("Everything went fine."),
+			text(`{"status": "ok", "items": ["deploy"]}`),
+		}},
+		Tools:        tool.NewRegistry(),
+		Prompt:       "report",
+		OutputSchema: testSchema,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if calls != 2 || result != `{"items":["deploy"],"status":"ok"}` {
+		t.Errorf("expected repaired output after 2 calls, got %q after %d", result, calls)
+	}
+
+	calls = 0
+	_, err = RunOnce(context.Background(), RunOnceConfig{
+		Provider:      &sequentialProvider{callCount: &calls, responses: []*provider.ChatResponse{text("no"), text("still no")}},
+		Tools:         tool.NewRegistry(),
+		Prompt:        "report",
+		OutputSchema:  testSchema,
+		SchemaRetries: 1,
+	})
+	var agentErr *AgentError
+	if !errors.As(err, &agentErr) || agentErr.Code != ErrInvalidOutput {
+		t.Fatalf("expected invalid output error, got %v", err)
+	}
+	if calls != 2 {
+		t.Errorf("expected 1 repair attempt, got %d calls", calls)
+	}
+}
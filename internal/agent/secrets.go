@@ -0,0 +1,35 @@
+package agent
+
+import (
+	"github.com/eachlabs/klaw/internal/observe"
+	"github.com/eachlabs/klaw/internal/redact"
+	"github.com/eachlabs/klaw/internal/tool"
+)
+
+// defaultSecrets masks the built-in secret patterns in tool results when
+// no masker is configured.
+var defaultSecrets, _ = redact.New(redact.Secrets, nil)
+
+// secretsOrDefault returns r, or the default masker when r is nil.
+func secretsOrDefault(r *redact.Redactor) *redact.Redactor {
+	if r == nil {
+		return defaultSecrets
+	}
+	return r
+}
+
+// maskSecrets masks credentials in the output of a tool call before it is
+// shown, added to the history and sent to the provider.
+func maskSecrets(r *redact.Redactor, logger *observe.Logger, toolName string, result *tool.Result) {
+	if result == nil {
+		return
+	}
+	content, counts := r.Redact(result.Content)
+	if counts == nil {
+		return
+	}
+	result.Content = content
+	if logger != nil {
+		logger.Info("secrets masked in tool output", "tool", toolName, "redactions", redact.Summary(counts))
+	}
+}
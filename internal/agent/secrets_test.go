@@ -0,0 +1,60 @@
+package agent
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/eachlabs/klaw/internal/channel"
+	"github.com/eachlabs/klaw/internal/provider"
+	"github.com/eachlabs/klaw/internal/redact"
+	"github.com/eachlabs/klaw/internal/tool"
+)
+
+func TestHandleMessage_MasksSecrets(t *testing.T) {
+	output := "DB_PASSWORD=hunter22\nDATABASE_URL=postgres://app:s3cret@db:5432/app"
+	newAgent := func(secrets *redact.Redactor) *Agent {
+		callCount := 0
+		prov := &sequentialProvider{
+			responses: []*provider.ChatResponse{
+				{Content: []provider.ContentBlock{
+					{Type: "tool_use", ToolUse: &provider.ToolCall{
+						ID: "tc1", Name: "echo", Input: json.RawMessage(`{"msg":` + jsonString(output) + `}`),
+					}},
+				}},
+				{Content: []provider.ContentBlock{{Type: "text", Text: "Done"}}},
+			},
+			callCount: &callCount,
+		}
+		reg := tool.NewRegistry()
+		reg.Register(&echoTool{})
+		return New(Config{Provider: prov, Channel: newTestChannel(), Tools: reg, Secrets: secrets})
+	}
+
+	ag := newAgent(nil)
+	if err := ag.handleMessage(context.Background(), &channel.Message{Role: "user", Content: "cat .env"}); err != nil {
+		t.Fatal(err)
+	}
+	got := ag.History()[2].ToolResult.Content
+	if strings.Contains(got, "hunter22") || strings.Contains(got, "s3cret") {
+		t.Errorf("secrets reached the history: %q", got)
+	}
+	if !strings.Contains(got, "DB_PASSWORD=[REDACTED:env_secret]") || !strings.Contains(got, "postgres://app:[REDACTED:url_password]@db") {
+		t.Errorf("unexpected masking: %q", got)
+	}
+
+	off, _ := redact.New(nil, nil)
+	ag = newAgent(off)
+	if err := ag.handleMessage(context.Background(), &channel.Message{Role: "user", Content: "cat .env"}); err != nil {
+		t.Fatal(err)
+	}
+	if got := ag.History()[2].ToolResult.Content; got != output {
+		t.Errorf("masking off changed the output: %q", got)
+	}
+}
+
+func jsonString(s string) string {
+	b, _ := json.Marshal(s)
+	return string(b)
+}
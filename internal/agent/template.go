@@ -0,0 +1,192 @@
+package agent
+
+import (
+	"embed"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/BurntSushi/toml"
+)
+
+//go:embed templates/*.toml
+var builtinTemplates embed.FS
+
+// Template is a reusable agent profile new agents can start from.
+type Template struct {
+	Name         string          `toml:"name"`
+	Description  string          `toml:"description"`
+	SystemPrompt string          `toml:"system_prompt"`
+	Model        string          `toml:"model"`
+	Skills       []string        `toml:"skills"`
+	Tools        []string        `toml:"tools"`
+	Triggers     []string        `toml:"triggers"`
+	Policy       *TemplatePolicy `toml:"policy"`
+	Source       string          `toml:"-"` // "builtin", a file path or a registry URL
+}
+
+// TemplatePolicy is the tool policy an agent created from a template gets.
+type TemplatePolicy struct {
+	AllowCommands  []string `toml:"allow_commands"`
+	DenyCommands   []string `toml:"deny_commands"`
+	WritablePaths  []string `toml:"writable_paths"`
+	AllowedDomains []string `toml:"allowed_domains"`
+}
+
+// TemplateLoader finds agent templates. A template name is looked up, in
+// order, as a .toml file path, in the templates directory, as the
+// AGENT.toml of an installed skill and among the built-in templates;
+// "org/name" templates are fetched from the skills registry.
+type TemplateLoader struct {
+	templatesDir string
+	skillsDir    string
+}
+
+// NewTemplateLoader creates a template loader.
+func NewTemplateLoader(templatesDir, skillsDir string) *TemplateLoader {
+	return &TemplateLoader{templatesDir: templatesDir, skillsDir: skillsDir}
+}
+
+// Load returns the template called name.
+func (l *TemplateLoader) Load(name string) (*Template, error) {
+	if strings.HasSuffix(name, ".toml") {
+		return loadTemplateFile(name)
+	}
+	for _, p := range []string{
+		filepath.Join(l.templatesDir, name+".toml"),
+		filepath.Join(l.skillsDir, name, "AGENT.toml"),
+	} {
+		if _, err := os.Stat(p); err == nil {
+			return loadTemplateFile(p)
+		}
+	}
+	if t, err := loadBuiltinTemplate(name); err == nil {
+		return t, nil
+	}
+	if strings.Contains(name, "/") {
+		t, err := l.fetch(name)
+		if err != nil {
+			return nil, fmt.Errorf("template %s: %w", name, err)
+		}
+		return t, nil
+	}
+	return nil, fmt.Errorf("template not found: %s (run 'klaw get templates' to list them)", name)
+}
+
+// List returns the local and built-in templates, local ones first. A local
+// template hides a built-in one of the same name.
+func (l *TemplateLoader) List() ([]*Template, error) {
+	var templates []*Template
+	seen := make(map[string]bool)
+	add := func(t *Template) {
+		if !seen[t.Name] {
+			seen[t.Name] = true
+			templates = append(templates, t)
+		}
+	}
+
+	files, _ := filepath.Glob(filepath.Join(l.templatesDir, "*.toml"))
+	skillFiles, _ := filepath.Glob(filepath.Join(l.skillsDir, "*", "AGENT.toml"))
+	orgSkillFiles, _ := filepath.Glob(filepath.Join(l.skillsDir, "*", "*", "AGENT.toml"))
+	for _, p := range append(append(files, skillFiles...), orgSkillFiles...) {
+		t, err := loadTemplateFile(p)
+		if err != nil {
+			return nil, err
+		}
+		add(t)
+	}
+
+	entries, err := builtinTemplates.ReadDir("templates")
+	if err != nil {
+		return nil, err
+	}
+	for _, e := range entries {
+		t, err := loadBuiltinTemplate(strings.TrimSuffix(e.Name(), ".toml"))
+		if err != nil {
+			return nil, err
+		}
+		add(t)
+	}
+	return templates, nil
+}
+
+// fetch downloads AGENT.toml of a skill from the skills registry and keeps
+// it with the skill so later loads are local.
+func (l *TemplateLoader) fetch(name string) (*Template, error) {
+	urls := []string{
+		fmt.Sprintf("https://skills.sh/%s/AGENT.toml", name),
+		fmt.Sprintf("https://raw.githubusercontent.com/eachlabs/klaw-skills/main/%s/AGENT.toml", name),
+	}
+
+	client := &http.Client{Timeout: 30 * time.Second}
+	var lastErr error
+	for _, url := range urls {
+		resp, err := client.Get(url)
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		data, err := io.ReadAll(resp.Body)
+		_ = resp.Body.Close()
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		if resp.StatusCode != http.StatusOK {
+			lastErr = fmt.Errorf("HTTP %d from %s", resp.StatusCode, url)
+			continue
+		}
+
+		t, err := parseTemplate(data, url)
+		if err != nil {
+			return nil, err
+		}
+		dir := filepath.Join(l.skillsDir, name)
+		if err := os.MkdirAll(dir, 0755); err == nil {
+			_ = os.WriteFile(filepath.Join(dir, "AGENT.toml"), data, 0644)
+		}
+		return t, nil
+	}
+	return nil, lastErr
+}
+
+func loadTemplateFile(p string) (*Template, error) {
+	data, err := os.ReadFile(p)
+	if err != nil {
+		return nil, err
+	}
+	t, err := parseTemplate(data, p)
+	if err != nil {
+		return nil, err
+	}
+	if t.Name == "" {
+		t.Name = strings.TrimSuffix(filepath.Base(p), ".toml")
+		if t.Name == "AGENT" {
+			t.Name = filepath.Base(filepath.Dir(p)) // skill directory
+		}
+	}
+	return t, nil
+}
+
+func loadBuiltinTemplate(name string) (*Template, error) {
+	data, err := builtinTemplates.ReadFile(path.Join("templates", name+".toml"))
+	if err != nil {
+		return nil, err
+	}
+	return parseTemplate(data, "builtin")
+}
+
+func parseTemplate(data []byte, source string) (*Template, error) {
+	var t Template
+	if err := toml.Unmarshal(data, &t); err != nil {
+		return nil, fmt.Errorf("invalid template %s: %w", source, err)
+	}
+	t.SystemPrompt = strings.TrimSpace(t.SystemPrompt)
+	t.Source = source
+	return &t, nil
+}
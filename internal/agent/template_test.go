@@ -0,0 +1,77 @@
+package agent
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestTemplateLoader(t *testing.T) {
+	dir := t.TempDir()
+	templatesDir := filepath.Join(dir, "templates")
+	skillsDir := filepath.Join(dir, "skills")
+	for p, content := range map[string]string{
+		filepath.Join(templatesDir, "sre-oncall.toml"):   "description = \"our oncall\"\n",
+		filepath.Join(skillsDir, "triage", "AGENT.toml"): "description = \"from a skill\"\nskills = [\"triage\"]\n",
+	} {
+		if err := os.MkdirAll(filepath.Dir(p), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(p, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	l := NewTemplateLoader(templatesDir, skillsDir)
+
+	builtin, err := l.Load("support-triage")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if builtin.Source != "builtin" || builtin.SystemPrompt == "" || len(builtin.Triggers) == 0 {
+		t.Errorf("incomplete built-in template: %+v", builtin)
+	}
+
+	local, err := l.Load("sre-oncall")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if local.Name != "sre-oncall" || local.Description != "our oncall" {
+		t.Errorf("local template should override the built-in one, got %+v", local)
+	}
+
+	fromSkill, err := l.Load("triage")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fromSkill.Name != "triage" || fromSkill.Description != "from a skill" {
+		t.Errorf("unexpected skill template: %+v", fromSkill)
+	}
+
+	if _, err := l.Load("missing"); err == nil {
+		t.Error("expected an error for an unknown template")
+	}
+
+	all, err := l.List()
+	if err != nil {
+		t.Fatal(err)
+	}
+	names := make(map[string]string)
+	for _, tmpl := range all {
+		names[tmpl.Name] = tmpl.Description
+	}
+	if len(all) != 4 || names["sre-oncall"] != "our oncall" || names["triage"] == "" {
+		t.Errorf("unexpected template list: %v", names)
+	}
+}
+
+func TestBuiltinTemplatesHaveValidPolicies(t *testing.T) {
+	all, err := NewTemplateLoader(t.TempDir(), t.TempDir()).List()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, tmpl := range all {
+		if tmpl.Description == "" || tmpl.SystemPrompt == "" {
+			t.Errorf("template %s needs a description and system prompt", tmpl.Name)
+		}
+	}
+}
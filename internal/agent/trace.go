@@ -0,0 +1,62 @@
+package agent
+
+import (
+	"context"
+
+	"github.com/eachlabs/klaw/internal/channel"
+	"github.com/eachlabs/klaw/internal/observe"
+	"github.com/eachlabs/klaw/internal/provider"
+	"github.com/eachlabs/klaw/internal/tool"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// startTurnSpan starts the span of the turn answering msg. The spans of
+// routing, provider calls, tool calls and replies are its children.
+func (a *Agent) startTurnSpan(ctx context.Context, msg *channel.Message) (context.Context, trace.Span) {
+	attrs := []attribute.KeyValue{observe.AttrConversation.String(a.getConversationID(msg))}
+	if a.agentName != "" {
+		attrs = append(attrs, observe.AttrAgent.String(a.agentName))
+	}
+	if a.channel != nil {
+		attrs = append(attrs, observe.AttrChannel.String(a.channel.Name()))
+	}
+	if channelID, _ := msg.Metadata["channel"].(string); channelID != "" {
+		attrs = append(attrs, observe.AttrChannelID.String(channelID))
+	}
+	if threadTS, _ := msg.Metadata["thread_ts"].(string); threadTS != "" {
+		attrs = append(attrs, observe.AttrThread.String(threadTS))
+	}
+	return observe.Tracer().Start(ctx, "klaw.message", trace.WithSpanKind(trace.SpanKindServer), trace.WithAttributes(attrs...))
+}
+
+// startProviderSpan starts the span of one provider request.
+func startProviderSpan(ctx context.Context, prov provider.Provider, model string) (context.Context, trace.Span) {
+	return observe.Tracer().Start(ctx, "klaw.provider", trace.WithSpanKind(trace.SpanKindClient), trace.WithAttributes(
+		observe.AttrProvider.String(prov.Name()),
+		observe.AttrModel.String(model),
+	))
+}
+
+// setUsageAttributes adds the token usage of a provider request to its span.
+func setUsageAttributes(span trace.Span, usage provider.Usage) {
+	span.SetAttributes(
+		observe.AttrInputTokens.Int(usage.InputTokens),
+		observe.AttrOutputTokens.Int(usage.OutputTokens),
+	)
+}
+
+// startToolSpan starts the span of a tool call.
+func startToolSpan(ctx context.Context, name string) (context.Context, trace.Span) {
+	return observe.Tracer().Start(ctx, "klaw.tool", trace.WithAttributes(observe.AttrTool.String(name)))
+}
+
+// endToolSpan marks a failed tool call and ends its span.
+func endToolSpan(span trace.Span, result *tool.Result) {
+	if result != nil && result.IsError {
+		span.SetAttributes(observe.AttrIsError.Bool(true))
+		span.SetStatus(codes.Error, truncate(result.Content, 200))
+	}
+	span.End()
+}
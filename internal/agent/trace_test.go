@@ -0,0 +1,80 @@
+package agent
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/eachlabs/klaw/internal/channel"
+	"github.com/eachlabs/klaw/internal/observe"
+	"github.com/eachlabs/klaw/internal/provider"
+	"github.com/eachlabs/klaw/internal/tool"
+	"go.opentelemetry.io/otel"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestHandleAndReport_Spans(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	prev := otel.GetTracerProvider()
+	otel.SetTracerProvider(tp)
+	defer otel.SetTracerProvider(prev)
+
+	callCount := 0
+	prov := &sequentialProvider{
+		responses: []*provider.ChatResponse{
+			{Content: []provider.ContentBlock{
+				{Type: "tool_use", ToolUse: &provider.ToolCall{ID: "tc1", Name: "echo", Input: json.RawMessage(`{"msg":"hi"}`)}},
+			}},
+			{Content: []provider.ContentBlock{{Type: "text", Text: "Done"}}},
+		},
+		callCount: &callCount,
+	}
+	reg := tool.NewRegistry()
+	reg.Register(&echoTool{})
+	ag := New(Config{Provider: prov, Channel: newTestChannel(), Tools: reg, Model: "test-model"})
+
+	msg := &channel.Message{Role: "user", Content: "run echo", Metadata: map[string]any{"channel": "C1", "thread_ts": "1.1"}}
+	ag.handleAndReport(context.Background(), msg)
+
+	spans := recorder.Ended()
+	var turn sdktrace.ReadOnlySpan
+	counts := make(map[string]int)
+	for _, s := range spans {
+		counts[s.Name()]++
+		if s.Name() == "klaw.message" {
+			turn = s
+		}
+	}
+	if turn == nil {
+		t.Fatalf("no klaw.message span among %v", counts)
+	}
+	if counts["klaw.provider"] != 2 || counts["klaw.tool"] != 1 || counts["klaw.channel.send"] == 0 {
+		t.Errorf("span counts = %v", counts)
+	}
+
+	attrs := make(map[string]string)
+	for _, kv := range turn.Attributes() {
+		attrs[string(kv.Key)] = kv.Value.Emit()
+	}
+	if attrs[string(observe.AttrConversation)] != "C1:1.1" || attrs[string(observe.AttrThread)] != "1.1" || attrs[string(observe.AttrChannel)] != "test" {
+		t.Errorf("turn attributes = %v", attrs)
+	}
+
+	for _, s := range spans {
+		if s.Name() == "klaw.message" {
+			continue
+		}
+		if s.Parent().SpanID() != turn.SpanContext().SpanID() {
+			t.Errorf("%s span is not a child of the turn", s.Name())
+		}
+		if s.Name() == "klaw.provider" {
+			for _, kv := range s.Attributes() {
+				if kv.Key == observe.AttrInputTokens && kv.Value.AsInt64() != 10 {
+					t.Errorf("input tokens = %d, want 10", kv.Value.AsInt64())
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,223 @@
+package agent
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/eachlabs/klaw/internal/provider"
+	"github.com/eachlabs/klaw/internal/tool"
+)
+
+// Transcript is the full record of one turn: the provider requests and
+// responses and the tool calls in between. A transcript can be replayed
+// with Replay.
+type Transcript struct {
+	ConversationID string                    `json:"conversation_id"`
+	Agent          string                    `json:"agent,omitempty"`
+	Model          string                    `json:"model,omitempty"`
+	StartedAt      time.Time                 `json:"started_at"`
+	Duration       time.Duration             `json:"duration"`
+	System         string                    `json:"system"`
+	Tools          []provider.ToolDefinition `json:"tools,omitempty"`
+	MaxTokens      int                       `json:"max_tokens,omitempty"`
+	History        []provider.Message        `json:"history"` // before the turn
+	Message        string                    `json:"message"` // as sent to the provider
+	Steps          []TranscriptStep          `json:"steps"`
+	Reply          string                    `json:"reply,omitempty"`
+	Error          string                    `json:"error,omitempty"`
+}
+
+// TranscriptStep is one provider request of a turn, its response and the
+// tool calls it made.
+type TranscriptStep struct {
+	Request  []provider.Message     `json:"request"`
+	Response *provider.ChatResponse `json:"response,omitempty"`
+	Error    string                 `json:"error,omitempty"`
+	Tools    []TranscriptTool       `json:"tools,omitempty"`
+}
+
+// TranscriptTool is the input and output of a tool call.
+type TranscriptTool struct {
+	ID      string          `json:"id"`
+	Name    string          `json:"name"`
+	Input   json.RawMessage `json:"input"`
+	Result  string          `json:"result"`
+	IsError bool            `json:"is_error,omitempty"`
+}
+
+// TranscriptRecorder receives the transcript of each finished turn.
+type TranscriptRecorder interface {
+	RecordTranscript(t *Transcript)
+}
+
+// turnTranscript builds the transcript of a turn; a nil *turnTranscript
+// records nothing.
+type turnTranscript struct {
+	t *Transcript
+}
+
+func (a *Agent) startTranscript(conversationID string, p *Profile, history []provider.Message, content string) *turnTranscript {
+	if a.transcripts == nil {
+		return nil
+	}
+	return &turnTranscript{t: &Transcript{
+		ConversationID: conversationID,
+		Agent:          p.Name,
+		Model:          p.Model,
+		StartedAt:      time.Now(),
+		System:         p.SystemPrompt,
+		MaxTokens:      a.maxTokens,
+		History:        append([]provider.Message(nil), history...),
+		Message:        content,
+	}}
+}
+
+// request starts a step with the request about to be sent.
+func (tt *turnTranscript) request(req *provider.ChatRequest) {
+	if tt == nil {
+		return
+	}
+	tt.t.Tools = req.Tools
+	tt.t.Steps = append(tt.t.Steps, TranscriptStep{Request: append([]provider.Message(nil), req.Messages...)})
+}
+
+func (tt *turnTranscript) step() *TranscriptStep {
+	return &tt.t.Steps[len(tt.t.Steps)-1]
+}
+
+// response records the response, or error, of the current step.
+func (tt *turnTranscript) response(text string, toolCalls []provider.ToolCall, usage *provider.Usage, err error) {
+	if tt == nil || len(tt.t.Steps) == 0 {
+		return
+	}
+	if err != nil {
+		tt.step().Error = err.Error()
+		return
+	}
+	resp := &provider.ChatResponse{}
+	if text != "" {
+		resp.Content = append(resp.Content, provider.ContentBlock{Type: "text", Text: text})
+	}
+	for i := range toolCalls {
+		resp.Content = append(resp.Content, provider.ContentBlock{Type: "tool_use", ToolUse: &toolCalls[i]})
+	}
+	if usage != nil {
+		resp.Usage = *usage
+	}
+	tt.step().Response = resp
+}
+
+// tool records a tool call of the current step.
+func (tt *turnTranscript) tool(tc provider.ToolCall, result *tool.Result) {
+	if tt == nil || len(tt.t.Steps) == 0 {
+		return
+	}
+	tt.step().Tools = append(tt.step().Tools, TranscriptTool{
+		ID: tc.ID, Name: tc.Name, Input: tc.Input, Result: result.Content, IsError: result.IsError,
+	})
+}
+
+// finish hands the transcript to the recorder.
+func (tt *turnTranscript) finish(r TranscriptRecorder, reply string, err error) {
+	if tt == nil {
+		return
+	}
+	tt.t.Duration = time.Since(tt.t.StartedAt)
+	tt.t.Reply = reply
+	if err != nil {
+		tt.t.Error = err.Error()
+	}
+	r.RecordTranscript(tt.t)
+}
+
+// TranscriptStore keeps transcripts as JSON files, one directory per
+// conversation.
+type TranscriptStore struct {
+	Dir string
+}
+
+func (s *TranscriptStore) conversationDir(conversationID string) string {
+	return filepath.Join(s.Dir, url.PathEscape(conversationID))
+}
+
+// Save writes t to the store.
+func (s *TranscriptStore) Save(t *Transcript) error {
+	dir := s.conversationDir(t.ConversationID)
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(t, "", "  ")
+	if err != nil {
+		return err
+	}
+	name := t.StartedAt.UTC().Format("20060102T150405.000000000Z") + ".json"
+	return os.WriteFile(filepath.Join(dir, name), data, 0600)
+}
+
+// RecordTranscript saves t, printing a warning when that fails.
+func (s *TranscriptStore) RecordTranscript(t *Transcript) {
+	if err := s.Save(t); err != nil {
+		fmt.Printf("Warning: failed to save transcript: %v\n", err)
+	}
+}
+
+// List returns the transcripts of a conversation, oldest first.
+func (s *TranscriptStore) List(conversationID string) ([]*Transcript, error) {
+	dir := s.conversationDir(conversationID)
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, fmt.Errorf("no transcripts for conversation %q", conversationID)
+		}
+		return nil, err
+	}
+	var names []string
+	for _, e := range entries {
+		if !e.IsDir() && strings.HasSuffix(e.Name(), ".json") {
+			names = append(names, e.Name())
+		}
+	}
+	sort.Strings(names)
+
+	transcripts := make([]*Transcript, 0, len(names))
+	for _, name := range names {
+		data, err := os.ReadFile(filepath.Join(dir, name))
+		if err != nil {
+			return nil, err
+		}
+		var t Transcript
+		if err := json.Unmarshal(data, &t); err != nil {
+			return nil, fmt.Errorf("%s: %w", name, err)
+		}
+		transcripts = append(transcripts, &t)
+	}
+	return transcripts, nil
+}
+
+// Conversations returns the IDs of the conversations with transcripts.
+func (s *TranscriptStore) Conversations() ([]string, error) {
+	entries, err := os.ReadDir(s.Dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	var ids []string
+	for _, e := range entries {
+		if !e.IsDir() {
+			continue
+		}
+		if id, err := url.PathUnescape(e.Name()); err == nil {
+			ids = append(ids, id)
+		}
+	}
+	sort.Strings(ids)
+	return ids, nil
+}
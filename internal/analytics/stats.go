@@ -0,0 +1,59 @@
+package analytics
+
+import (
+	"cmp"
+	"slices"
+
+	"github.com/eachlabs/klaw/internal/cluster"
+)
+
+// Stats counts tagged messages by intent and sentiment.
+type Stats struct {
+	Messages int           `json:"messages"`
+	Tagged   int           `json:"tagged"`
+	Intents  []IntentStats `json:"intents"`
+}
+
+// IntentStats counts the messages of an intent by sentiment.
+type IntentStats struct {
+	Intent   string `json:"intent"`
+	Messages int    `json:"messages"`
+	Positive int    `json:"positive"`
+	Neutral  int    `json:"neutral"`
+	Negative int    `json:"negative"`
+}
+
+// Summarize counts logs by intent, most frequent first, and by sentiment.
+// Untagged messages only count towards Messages.
+func Summarize(logs []*cluster.MessageLog) *Stats {
+	stats := &Stats{Messages: len(logs), Intents: []IntentStats{}}
+	byIntent := make(map[string]*IntentStats)
+	for _, l := range logs {
+		if l.Intent == "" {
+			continue
+		}
+		stats.Tagged++
+		s, ok := byIntent[l.Intent]
+		if !ok {
+			s = &IntentStats{Intent: l.Intent}
+			byIntent[l.Intent] = s
+		}
+		s.Messages++
+		switch l.Sentiment {
+		case "positive":
+			s.Positive++
+		case "negative":
+			s.Negative++
+		default:
+			s.Neutral++
+		}
+	}
+
+	for _, s := range byIntent {
+		stats.Intents = append(stats.Intents, *s)
+	}
+	slices.SortFunc(stats.Intents, func(a, b IntentStats) int {
+		return cmp.Or(cmp.Compare(b.Messages, a.Messages), cmp.Compare(a.Intent, b.Intent))
+	})
+	return stats
+}
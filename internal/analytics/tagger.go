@@ -0,0 +1,183 @@
+// Package analytics tags the message logs with the intent and sentiment of
+// each message, so teams can see what users ask their agents.
+package analytics
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/eachlabs/klaw/internal/cluster"
+)
+
+// DefaultIntents are the intent categories used when none are configured.
+var DefaultIntents = []string{"question", "task", "bug_report", "feature_request", "feedback", "complaint", "other"}
+
+// Sentiments are the sentiments a message is tagged with.
+var Sentiments = []string{"positive", "neutral", "negative"}
+
+const (
+	// batchSize is the number of messages classified per request.
+	batchSize = 20
+
+	// maxMessageChars is the length of a message sent for classification;
+	// the intent shows in its start.
+	maxMessageChars = 500
+
+	// lookback is how far back untagged messages are tagged, so enabling
+	// the tagger does not classify the whole history.
+	lookback = 7 * 24 * time.Hour
+)
+
+// ClassifyFunc completes a prompt, with a cheap model.
+type ClassifyFunc func(ctx context.Context, prompt string) (string, error)
+
+// Tagger tags the untagged messages of a namespace's message logs.
+type Tagger struct {
+	store     *cluster.Store
+	cluster   string
+	namespace string
+	classify  ClassifyFunc
+	intents   []string
+}
+
+// NewTagger creates a tagger of a namespace's messages. Without intents,
+// DefaultIntents are used.
+func NewTagger(store *cluster.Store, clusterName, namespace string, classify ClassifyFunc, intents []string) *Tagger {
+	if len(intents) == 0 {
+		intents = DefaultIntents
+	}
+	return &Tagger{store: store, cluster: clusterName, namespace: namespace, classify: classify, intents: intents}
+}
+
+// Run tags the untagged messages every interval until ctx is done. Errors
+// are passed to onError; the messages are tried again on the next run.
+func (t *Tagger) Run(ctx context.Context, interval time.Duration, onError func(error)) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		if _, err := t.TagPending(ctx); err != nil && ctx.Err() == nil && onError != nil {
+			onError(err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// TagPending tags the messages of the last week that have no intent yet,
+// and returns how many it tagged.
+func (t *Tagger) TagPending(ctx context.Context) (int, error) {
+	untagged, err := t.store.UntaggedMessageLogs(t.cluster, t.namespace, time.Now().Add(-lookback))
+	if err != nil {
+		return 0, err
+	}
+
+	tagged := 0
+	var errs []error
+	for channel, logs := range untagged {
+		for batch := range slices.Chunk(logs, batchSize) {
+			if ctx.Err() != nil {
+				return tagged, ctx.Err()
+			}
+			tags, err := t.tagBatch(ctx, batch)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("%s: %w", channel, err))
+				break
+			}
+			if err := t.store.TagMessageLogs(t.cluster, t.namespace, channel, tags); err != nil {
+				errs = append(errs, fmt.Errorf("%s: %w", channel, err))
+				break
+			}
+			tagged += len(tags)
+		}
+	}
+	return tagged, errors.Join(errs...)
+}
+
+// tagBatch classifies messages in one request.
+func (t *Tagger) tagBatch(ctx context.Context, logs []*cluster.MessageLog) ([]cluster.MessageTag, error) {
+	out, err := t.classify(ctx, t.prompt(logs))
+	if err != nil {
+		return nil, err
+	}
+	results, err := parseTags(out)
+	if err != nil {
+		return nil, err
+	}
+
+	var tags []cluster.MessageTag
+	for _, r := range results {
+		if r.N < 1 || r.N > len(logs) {
+			continue
+		}
+		l := logs[r.N-1]
+		tags = append(tags, cluster.MessageTag{
+			ID:        l.ID,
+			Timestamp: l.Timestamp,
+			Intent:    normalize(r.Intent, t.intents, "other"),
+			Sentiment: normalize(r.Sentiment, Sentiments, "neutral"),
+		})
+	}
+	return tags, nil
+}
+
+// prompt asks for the intent and sentiment of numbered messages.
+func (t *Tagger) prompt(logs []*cluster.MessageLog) string {
+	var sb strings.Builder
+	sb.WriteString("Classify each message users sent to an assistant by its intent and its sentiment.\n\n")
+	fmt.Fprintf(&sb, "Intents: %s\n", strings.Join(t.intents, ", "))
+	fmt.Fprintf(&sb, "Sentiments: %s\n\n", strings.Join(Sentiments, ", "))
+	sb.WriteString(`Reply with only a JSON array with an object per message, e.g. [{"n": 1, "intent": "question", "sentiment": "neutral"}].` + "\n\nMessages:\n")
+	for i, l := range logs {
+		content := strings.Join(strings.Fields(l.Content), " ")
+		if len(content) > maxMessageChars {
+			content = content[:maxMessageChars] + "..."
+		}
+		fmt.Fprintf(&sb, "%d. %s\n", i+1, content)
+	}
+	return sb.String()
+}
+
+type tagResult struct {
+	N         int    `json:"n"`
+	Intent    string `json:"intent"`
+	Sentiment string `json:"sentiment"`
+}
+
+// parseTags reads the JSON array of a classification, ignoring text
+// around it such as a code fence.
+func parseTags(out string) ([]tagResult, error) {
+	start, end := strings.Index(out, "["), strings.LastIndex(out, "]")
+	if start < 0 || end < start {
+		return nil, fmt.Errorf("no tags in classification: %q", truncate(out, 200))
+	}
+	var results []tagResult
+	if err := json.Unmarshal([]byte(out[start:end+1]), &results); err != nil {
+		return nil, fmt.Errorf("invalid classification: %w", err)
+	}
+	return results, nil
+}
+
+// normalize returns the value of allowed v names, ignoring case, spaces
+// and dashes, or fallback.
+func normalize(v string, allowed []string, fallback string) string {
+	v = strings.NewReplacer(" ", "_", "-", "_").Replace(strings.ToLower(strings.TrimSpace(v)))
+	if slices.Contains(allowed, v) {
+		return v
+	}
+	return fallback
+}
+
+func truncate(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	return s[:n] + "..."
+}
@@ -0,0 +1,101 @@
+package analytics
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/eachlabs/klaw/internal/cluster"
+)
+
+func TestTagger_TagPending(t *testing.T) {
+	store := cluster.NewStore(t.TempDir())
+	for i, content := range []string{"How do I rotate the API key?", "The deploy failed again, this is broken", "Thanks, that worked!"} {
+		err := store.AppendMessageLog("acme", "support", "slack-bot", &cluster.MessageLog{ID: string(rune('a' + i)), Agent: "klaw", Content: content})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	calls := 0
+	classify := func(ctx context.Context, prompt string) (string, error) {
+		calls++
+		if !strings.Contains(prompt, "3. Thanks, that worked!") {
+			t.Errorf("expected numbered messages in prompt:\n%s", prompt)
+		}
+		return "```json\n" + `[{"n": 1, "intent": "Question", "sentiment": "neutral"},
+{"n": 2, "intent": "bug report", "sentiment": "negative"},
+{"n": 3, "intent": "gratitude", "sentiment": "positive"},
+{"n": 9, "intent": "question", "sentiment": "neutral"}]` + "\n```", nil
+	}
+	tagger := NewTagger(store, "acme", "support", classify, nil)
+
+	n, err := tagger.TagPending(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != 3 {
+		t.Errorf("tagged %d messages, want 3", n)
+	}
+
+	logs, err := store.ListMessageLogs("acme", "support", time.Now().Add(-time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, l := range logs {
+		got = append(got, l.Intent+"/"+l.Sentiment)
+	}
+	if want := "question/neutral,bug_report/negative,other/positive"; strings.Join(got, ",") != want {
+		t.Errorf("tags = %v, want %s", got, want)
+	}
+
+	// Tagged messages are not classified again
+	if n, err := tagger.TagPending(context.Background()); err != nil || n != 0 {
+		t.Errorf("second run tagged %d (%v), want 0", n, err)
+	}
+	if calls != 1 {
+		t.Errorf("classified %d times, want 1", calls)
+	}
+}
+
+func TestTagger_TagPendingInvalidReply(t *testing.T) {
+	store := cluster.NewStore(t.TempDir())
+	if err := store.AppendMessageLog("acme", "support", "api", &cluster.MessageLog{ID: "a", Content: "hi"}); err != nil {
+		t.Fatal(err)
+	}
+	classify := func(ctx context.Context, prompt string) (string, error) {
+		return "I cannot classify these.", nil
+	}
+	if _, err := NewTagger(store, "acme", "support", classify, nil).TagPending(context.Background()); err == nil {
+		t.Error("expected an error for a reply without tags")
+	}
+	untagged, _ := store.UntaggedMessageLogs("acme", "support", time.Now().Add(-time.Hour))
+	if len(untagged["api"]) != 1 {
+		t.Errorf("expected the message to stay untagged, got %v", untagged)
+	}
+}
+
+func TestSummarize(t *testing.T) {
+	stats := Summarize([]*cluster.MessageLog{
+		{Intent: "question", Sentiment: "neutral"},
+		{Intent: "bug_report", Sentiment: "negative"},
+		{Intent: "question", Sentiment: "positive"},
+		{Intent: "bug_report", Sentiment: "negative"},
+		{Intent: "question", Sentiment: "negative"},
+		{Content: "not tagged yet"},
+	})
+	if stats.Messages != 6 || stats.Tagged != 5 {
+		t.Errorf("messages = %d, tagged = %d, want 6 and 5", stats.Messages, stats.Tagged)
+	}
+	if len(stats.Intents) != 2 {
+		t.Fatalf("expected 2 intents, got %+v", stats.Intents)
+	}
+	if q := stats.Intents[0]; q.Intent != "question" || q.Messages != 3 || q.Positive != 1 || q.Neutral != 1 || q.Negative != 1 {
+		t.Errorf("unexpected question stats: %+v", q)
+	}
+	if b := stats.Intents[1]; b.Intent != "bug_report" || b.Negative != 2 {
+		t.Errorf("unexpected bug_report stats: %+v", b)
+	}
+}
@@ -0,0 +1,60 @@
+// Package bus carries channel messages between the processes of a klaw
+// deployment: gateways run the channels and publish what users send, and
+// workers run the agent, consume those messages and publish the replies
+// back to the gateway the conversation came from.
+package bus
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/url"
+)
+
+// Handler receives the data of a message.
+type Handler func(data []byte)
+
+// Bus publishes messages to subjects and delivers them to subscribers.
+type Bus interface {
+	// Publish sends data to the subscribers of subject.
+	Publish(ctx context.Context, subject string, data []byte) error
+
+	// Subscribe calls handler with each message published to subject
+	// until ctx is done. Subscribers sharing a non-empty group split the
+	// messages between them, each message going to one of them; without a
+	// group every subscriber gets every message. Messages are handled one
+	// at a time, in order.
+	Subscribe(ctx context.Context, subject, group string, handler Handler) error
+
+	// Close closes the connection.
+	Close() error
+}
+
+// Open connects to the bus at rawURL: nats://[user:pass@]host[:port] or
+// redis://[:password@]host[:port][/db].
+func Open(rawURL string) (Bus, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid bus url: %w", err)
+	}
+	switch u.Scheme {
+	case "nats":
+		return DialNATS(u)
+	case "redis":
+		return DialRedis(u)
+	default:
+		return nil, fmt.Errorf("unknown bus: %q (use nats:// or redis://)", rawURL)
+	}
+}
+
+// hostPort returns the host:port of u, with port as the default port.
+func hostPort(u *url.URL, port string) string {
+	if u.Port() != "" {
+		return u.Host
+	}
+	host := u.Hostname()
+	if host == "" {
+		host = "localhost"
+	}
+	return net.JoinHostPort(host, port)
+}
@@ -0,0 +1,222 @@
+package bus
+
+import (
+	"bufio"
+	"context"
+	"net"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/eachlabs/klaw/internal/channel"
+)
+
+// memBus is an in-process Bus.
+type memBus struct {
+	mu   sync.Mutex
+	subs map[string][]chan []byte
+}
+
+func newMemBus() *memBus {
+	return &memBus{subs: make(map[string][]chan []byte)}
+}
+
+func (b *memBus) Publish(ctx context.Context, subject string, data []byte) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for _, ch := range b.subs[subject] {
+		ch <- data
+	}
+	return nil
+}
+
+func (b *memBus) Subscribe(ctx context.Context, subject, group string, handler Handler) error {
+	ch := make(chan []byte, 16)
+	b.mu.Lock()
+	b.subs[subject] = append(b.subs[subject], ch)
+	b.mu.Unlock()
+	go func() {
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case data := <-ch:
+				handler(data)
+			}
+		}
+	}()
+	return nil
+}
+
+func (b *memBus) Close() error { return nil }
+
+// testChannel is a channel fed and read by the test.
+type testChannel struct {
+	in   chan *channel.Message
+	sent chan *channel.Message
+}
+
+func (c *testChannel) Start(ctx context.Context) error  { return nil }
+func (c *testChannel) Receive() <-chan *channel.Message { return c.in }
+func (c *testChannel) Stop() error                      { return nil }
+func (c *testChannel) Name() string                     { return "test" }
+func (c *testChannel) Send(ctx context.Context, m *channel.Message) error {
+	c.sent <- m
+	return nil
+}
+
+func TestGatewayWorkerRoundTrip(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	b := newMemBus()
+	tc := &testChannel{in: make(chan *channel.Message), sent: make(chan *channel.Message, 1)}
+	gw := &Gateway{Bus: b, Channel: tc, Prefix: "klaw.test", ID: "gw1"}
+	go func() { _ = gw.Run(ctx) }()
+
+	worker := NewChannel(b, "klaw.test", "workers")
+	if err := worker.Start(ctx); err != nil {
+		t.Fatal(err)
+	}
+	// Starting twice must not subscribe twice
+	if err := worker.Start(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	// Wait for the gateway to subscribe before sending
+	time.Sleep(20 * time.Millisecond)
+	tc.in <- &channel.Message{ID: "1", Role: "user", Content: "hi", Metadata: map[string]any{"channel": "C1"}}
+
+	var got *channel.Message
+	select {
+	case got = <-worker.Receive():
+	case <-time.After(2 * time.Second):
+		t.Fatal("worker got no message")
+	}
+	if got.Content != "hi" || got.Metadata["channel"] != "C1" {
+		t.Fatalf("unexpected message %+v", got)
+	}
+	if got.Metadata[channel.MetaReplyTo] != "klaw.test.out.gw1" {
+		t.Fatalf("reply_to = %v", got.Metadata[channel.MetaReplyTo])
+	}
+
+	reply := &channel.Message{Role: "assistant", Content: "hello", Metadata: got.Metadata}
+	if err := worker.Send(ctx, reply); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case sent := <-tc.sent:
+		if sent.Content != "hello" || sent.Metadata["channel"] != "C1" {
+			t.Fatalf("unexpected reply %+v", sent)
+		}
+		if _, ok := sent.Metadata[channel.MetaReplyTo]; ok {
+			t.Fatal("reply_to should be stripped from the reply")
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("gateway got no reply")
+	}
+
+	select {
+	case extra := <-worker.Receive():
+		t.Fatalf("message delivered twice: %+v", extra)
+	case <-time.After(50 * time.Millisecond):
+	}
+}
+
+func TestWorkerDropsUnroutedMessages(t *testing.T) {
+	b := newMemBus()
+	worker := NewChannel(b, "klaw.test", "workers")
+	if err := worker.Send(context.Background(), &channel.Message{Content: "welcome"}); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestStreamEntries(t *testing.T) {
+	reply := []any{
+		[]any{"stream", []any{
+			[]any{"1-0", []any{"data", "hello"}},
+			[]any{"2-0", []any{"x", "y", "data", ""}},
+		}},
+	}
+	entries := streamEntries(reply)
+	if len(entries) != 2 {
+		t.Fatalf("expected 2 entries, got %+v", entries)
+	}
+	if entries[0].id != "1-0" || string(entries[0].data) != "hello" {
+		t.Errorf("entry 0 = %+v", entries[0])
+	}
+	if entries[1].id != "2-0" || string(entries[1].data) != "" {
+		t.Errorf("entry 1 = %+v", entries[1])
+	}
+	if entries := streamEntries(nil); len(entries) != 0 {
+		t.Errorf("expected no entries for a timed out read, got %+v", entries)
+	}
+}
+
+func TestNATS(t *testing.T) {
+	client, server := net.Pipe()
+	defer server.Close()
+
+	srv := bufio.NewReader(server)
+	readLine := func() string {
+		line, err := srv.ReadString('\n')
+		if err != nil {
+			t.Errorf("server read: %v", err)
+		}
+		return strings.TrimRight(line, "\r\n")
+	}
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		_, _ = server.Write([]byte("INFO {\"server_id\":\"test\"}\r\n"))
+		if line := readLine(); !strings.HasPrefix(line, "CONNECT ") {
+			t.Errorf("expected CONNECT, got %q", line)
+		}
+		if line := readLine(); line != "PING" {
+			t.Errorf("expected PING, got %q", line)
+		}
+		_, _ = server.Write([]byte("PONG\r\n"))
+	}()
+
+	n, err := newNATS(client, map[string]any{"name": "klaw"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer n.Close()
+	<-done
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	got := make(chan string, 1)
+	go func() {
+		if err := n.Subscribe(ctx, "klaw.in", "workers", func(data []byte) { got <- string(data) }); err != nil {
+			t.Error(err)
+		}
+	}()
+	if line := readLine(); line != "SUB klaw.in workers 1" {
+		t.Fatalf("expected SUB, got %q", line)
+	}
+
+	_, _ = server.Write([]byte("PING\r\nMSG klaw.in 1 5\r\nhello\r\n"))
+	if line := readLine(); line != "PONG" {
+		t.Fatalf("expected PONG, got %q", line)
+	}
+	select {
+	case data := <-got:
+		if data != "hello" {
+			t.Fatalf("got %q", data)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("no message delivered")
+	}
+
+	go func() { _ = n.Publish(context.Background(), "klaw.out.gw1", []byte("reply")) }()
+	if line := readLine(); line != "PUB klaw.out.gw1 5" {
+		t.Fatalf("expected PUB, got %q", line)
+	}
+	if line := readLine(); line != "reply" {
+		t.Fatalf("expected payload, got %q", line)
+	}
+}
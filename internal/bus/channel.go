@@ -0,0 +1,158 @@
+package bus
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+
+	"github.com/eachlabs/klaw/internal/channel"
+)
+
+// envelope is a user message on the bus, with the subject its replies go
+// to.
+type envelope struct {
+	Message *channel.Message `json:"message"`
+	ReplyTo string           `json:"reply_to"`
+}
+
+// InSubject is the subject gateways publish user messages to.
+func InSubject(prefix string) string {
+	return prefix + ".in"
+}
+
+// ReplySubject is the subject of the replies to the gateway id.
+func ReplySubject(prefix, id string) string {
+	return prefix + ".out." + id
+}
+
+// Gateway connects a channel to the workers: it publishes what the channel
+// receives and sends the replies the workers publish back through it.
+type Gateway struct {
+	Bus     Bus
+	Channel channel.Channel
+	Prefix  string // subject prefix shared with the workers
+	ID      string // unique among the gateways of Prefix
+}
+
+// Run starts the channel and relays its messages until ctx is done or the
+// channel closes.
+func (g *Gateway) Run(ctx context.Context) error {
+	replyTo := ReplySubject(g.Prefix, g.ID)
+	err := g.Bus.Subscribe(ctx, replyTo, "", func(data []byte) {
+		var msg channel.Message
+		if err := json.Unmarshal(data, &msg); err != nil {
+			fmt.Printf("Warning: bus: invalid reply: %v\n", err)
+			return
+		}
+		if err := g.Channel.Send(ctx, &msg); err != nil {
+			fmt.Printf("Warning: bus: reply to %s: %v\n", g.Channel.Name(), err)
+		}
+	})
+	if err != nil {
+		return err
+	}
+	if err := g.Channel.Start(ctx); err != nil {
+		return fmt.Errorf("failed to start channel: %w", err)
+	}
+
+	in := InSubject(g.Prefix)
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case msg, ok := <-g.Channel.Receive():
+			if !ok {
+				return nil
+			}
+			data, err := json.Marshal(envelope{Message: msg, ReplyTo: replyTo})
+			if err != nil {
+				fmt.Printf("Warning: bus: %v\n", err)
+				continue
+			}
+			if err := g.Bus.Publish(ctx, in, data); err != nil {
+				fmt.Printf("Warning: bus: message not delivered to the workers: %v\n", err)
+			}
+		}
+	}
+}
+
+// Channel is the channel of a worker: it receives the user messages
+// gateways publish, shared with the other workers of its group, and
+// publishes each reply to the gateway the message came from.
+type Channel struct {
+	bus    Bus
+	prefix string
+	group  string
+	out    chan *channel.Message
+
+	mu      sync.Mutex
+	started bool
+}
+
+// NewChannel returns the worker channel for the gateways of prefix.
+func NewChannel(b Bus, prefix, group string) *Channel {
+	return &Channel{bus: b, prefix: prefix, group: group, out: make(chan *channel.Message)}
+}
+
+// Start subscribes to the user messages. Starting again does nothing.
+func (c *Channel) Start(ctx context.Context) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.started {
+		return nil
+	}
+	c.started = true
+	return c.bus.Subscribe(ctx, InSubject(c.prefix), c.group, func(data []byte) {
+		var env envelope
+		if err := json.Unmarshal(data, &env); err != nil || env.Message == nil {
+			fmt.Printf("Warning: bus: invalid message: %v\n", err)
+			return
+		}
+		msg := env.Message
+		if msg.Metadata == nil {
+			msg.Metadata = make(map[string]any, 1)
+		}
+		msg.Metadata[channel.MetaReplyTo] = env.ReplyTo
+		// Handing the message over is the acknowledgement
+		select {
+		case c.out <- msg:
+		case <-ctx.Done():
+		}
+	})
+}
+
+// Send publishes msg to the gateway of the message it answers. Messages
+// that answer none, such as a welcome, are dropped.
+func (c *Channel) Send(ctx context.Context, msg *channel.Message) error {
+	replyTo, _ := msg.Metadata[channel.MetaReplyTo].(string)
+	if replyTo == "" {
+		return nil
+	}
+	reply := *msg
+	reply.Metadata = make(map[string]any, len(msg.Metadata))
+	for k, v := range msg.Metadata {
+		if k != channel.MetaReplyTo {
+			reply.Metadata[k] = v
+		}
+	}
+	data, err := json.Marshal(&reply)
+	if err != nil {
+		return err
+	}
+	return c.bus.Publish(ctx, replyTo, data)
+}
+
+// Receive returns the user messages given to this worker.
+func (c *Channel) Receive() <-chan *channel.Message {
+	return c.out
+}
+
+// Stop does nothing; the subscription ends with the context of Start.
+func (c *Channel) Stop() error {
+	return nil
+}
+
+func (c *Channel) Name() string {
+	return "bus"
+}
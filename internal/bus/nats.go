@@ -0,0 +1,246 @@
+package bus
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// NATS is a Bus on a NATS server, speaking its text protocol. Groups are
+// NATS queue groups. Delivery is at most once: messages published while no
+// subscriber is connected are lost.
+type NATS struct {
+	conn net.Conn
+	r    *bufio.Reader
+
+	wmu sync.Mutex // guards writes to conn
+
+	mu     sync.Mutex
+	nextID int
+	subs   map[string]*natsSub // by subscription ID
+	err    error               // why the connection ended
+	closed bool
+}
+
+type natsSub struct {
+	msgs chan []byte
+	done chan struct{} // closed when the subscription ends
+}
+
+// DialNATS connects to the NATS server of u.
+func DialNATS(u *url.URL) (*NATS, error) {
+	conn, err := net.DialTimeout("tcp", hostPort(u, "4222"), 10*time.Second)
+	if err != nil {
+		return nil, fmt.Errorf("nats: %w", err)
+	}
+	connect := map[string]any{"verbose": false, "pedantic": false, "name": "klaw"}
+	if u.User != nil {
+		if pass, ok := u.User.Password(); ok {
+			connect["user"], connect["pass"] = u.User.Username(), pass
+		} else {
+			connect["auth_token"] = u.User.Username()
+		}
+	}
+	n, err := newNATS(conn, connect)
+	if err != nil {
+		_ = conn.Close()
+		return nil, err
+	}
+	return n, nil
+}
+
+// newNATS handshakes on conn and starts reading from it.
+func newNATS(conn net.Conn, connect map[string]any) (*NATS, error) {
+	n := &NATS{
+		conn: conn,
+		r:    bufio.NewReader(conn),
+		subs: make(map[string]*natsSub),
+	}
+
+	_ = conn.SetDeadline(time.Now().Add(10 * time.Second))
+	line, err := n.readLine()
+	if err != nil {
+		return nil, fmt.Errorf("nats: %w", err)
+	}
+	if !strings.HasPrefix(line, "INFO ") {
+		return nil, fmt.Errorf("nats: unexpected greeting %q", line)
+	}
+	opts, _ := json.Marshal(connect)
+	if err := n.write("CONNECT " + string(opts) + "\r\nPING\r\n"); err != nil {
+		return nil, fmt.Errorf("nats: %w", err)
+	}
+	for {
+		line, err := n.readLine()
+		if err != nil {
+			return nil, fmt.Errorf("nats: %w", err)
+		}
+		if line == "PONG" {
+			break
+		}
+		if strings.HasPrefix(line, "-ERR") {
+			return nil, fmt.Errorf("nats: %s", strings.TrimSpace(strings.TrimPrefix(line, "-ERR")))
+		}
+	}
+	_ = conn.SetDeadline(time.Time{})
+
+	go n.read()
+	return n, nil
+}
+
+func (n *NATS) readLine() (string, error) {
+	line, err := n.r.ReadString('\n')
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimRight(line, "\r\n"), nil
+}
+
+func (n *NATS) write(s string) error {
+	n.wmu.Lock()
+	defer n.wmu.Unlock()
+	_, err := io.WriteString(n.conn, s)
+	return err
+}
+
+// read handles what the server sends until the connection ends.
+func (n *NATS) read() {
+	err := n.readLoop()
+	n.mu.Lock()
+	if !n.closed {
+		n.err = err
+		fmt.Printf("Warning: nats connection lost: %v\n", err)
+	}
+	for id, sub := range n.subs {
+		close(sub.msgs)
+		delete(n.subs, id)
+	}
+	n.mu.Unlock()
+}
+
+func (n *NATS) readLoop() error {
+	for {
+		line, err := n.readLine()
+		if err != nil {
+			return err
+		}
+		switch {
+		case strings.HasPrefix(line, "MSG "):
+			// MSG <subject> <sid> [reply-to] <#bytes>
+			fields := strings.Fields(line)
+			if len(fields) < 4 {
+				return fmt.Errorf("malformed %q", line)
+			}
+			size, err := strconv.Atoi(fields[len(fields)-1])
+			if err != nil {
+				return fmt.Errorf("malformed %q", line)
+			}
+			data := make([]byte, size+2) // with the trailing \r\n
+			if _, err := io.ReadFull(n.r, data); err != nil {
+				return err
+			}
+			n.mu.Lock()
+			sub := n.subs[fields[2]]
+			n.mu.Unlock()
+			if sub != nil {
+				select {
+				case sub.msgs <- data[:size]:
+				case <-sub.done:
+				}
+			}
+		case line == "PING":
+			if err := n.write("PONG\r\n"); err != nil {
+				return err
+			}
+		case strings.HasPrefix(line, "-ERR"):
+			fmt.Printf("Warning: nats: %s\n", strings.TrimSpace(strings.TrimPrefix(line, "-ERR")))
+		}
+	}
+}
+
+// Publish sends data to subject.
+func (n *NATS) Publish(ctx context.Context, subject string, data []byte) error {
+	if err := n.alive(); err != nil {
+		return err
+	}
+	n.wmu.Lock()
+	defer n.wmu.Unlock()
+	if deadline, ok := ctx.Deadline(); ok {
+		_ = n.conn.SetWriteDeadline(deadline)
+		defer func() { _ = n.conn.SetWriteDeadline(time.Time{}) }()
+	}
+	buf := make([]byte, 0, len(subject)+len(data)+24)
+	buf = append(buf, "PUB "+subject+" "+strconv.Itoa(len(data))+"\r\n"...)
+	buf = append(buf, data...)
+	buf = append(buf, "\r\n"...)
+	_, err := n.conn.Write(buf)
+	return err
+}
+
+// Subscribe delivers the messages of subject to handler until ctx is done.
+func (n *NATS) Subscribe(ctx context.Context, subject, group string, handler Handler) error {
+	if err := n.alive(); err != nil {
+		return err
+	}
+	n.mu.Lock()
+	n.nextID++
+	id := strconv.Itoa(n.nextID)
+	sub := &natsSub{msgs: make(chan []byte, 256), done: make(chan struct{})}
+	n.subs[id] = sub
+	n.mu.Unlock()
+
+	cmd := "SUB " + subject + " " + id + "\r\n"
+	if group != "" {
+		cmd = "SUB " + subject + " " + group + " " + id + "\r\n"
+	}
+	if err := n.write(cmd); err != nil {
+		return err
+	}
+
+	go func() {
+		for {
+			select {
+			case <-ctx.Done():
+				_ = n.write("UNSUB " + id + "\r\n")
+				n.mu.Lock()
+				delete(n.subs, id)
+				n.mu.Unlock()
+				close(sub.done)
+				return
+			case data, ok := <-sub.msgs:
+				if !ok {
+					return
+				}
+				handler(data)
+			}
+		}
+	}()
+	return nil
+}
+
+func (n *NATS) alive() error {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	if n.closed {
+		return fmt.Errorf("nats: connection closed")
+	}
+	if n.err != nil {
+		return fmt.Errorf("nats: connection lost: %w", n.err)
+	}
+	return nil
+}
+
+// Close closes the connection.
+func (n *NATS) Close() error {
+	n.mu.Lock()
+	n.closed = true
+	n.mu.Unlock()
+	return n.conn.Close()
+}
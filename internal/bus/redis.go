@@ -0,0 +1,166 @@
+package bus
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/eachlabs/klaw/internal/redis"
+)
+
+// redisMaxLen caps each stream, trimming the oldest messages.
+const redisMaxLen = 10000
+
+// redisBlock is how long a read waits for messages before checking
+// whether the subscription has ended.
+const redisBlock = 2 * time.Second
+
+// Redis is a Bus on Redis streams, one stream per subject. Groups are
+// consumer groups: a message is acknowledged once its handler returns, so
+// messages published while the workers are down wait in the stream.
+// Subscribers without a group only see messages published after they
+// subscribed.
+type Redis struct {
+	u *url.URL
+
+	mu   sync.Mutex // guards conn, used for publishing
+	conn *redis.Conn
+}
+
+// DialRedis connects to the Redis server of u.
+func DialRedis(u *url.URL) (*Redis, error) {
+	conn, err := redis.Dial(u)
+	if err != nil {
+		return nil, err
+	}
+	return &Redis{u: u, conn: conn}, nil
+}
+
+// Publish appends data to the stream of subject.
+func (r *Redis) Publish(ctx context.Context, subject string, data []byte) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if deadline, ok := ctx.Deadline(); ok {
+		_ = r.conn.SetDeadline(deadline)
+		defer func() { _ = r.conn.SetDeadline(time.Time{}) }()
+	}
+	_, err := r.conn.Do("XADD", subject, "MAXLEN", "~", strconv.Itoa(redisMaxLen), "*", "data", string(data))
+	return err
+}
+
+// Subscribe reads the stream of subject on a connection of its own.
+func (r *Redis) Subscribe(ctx context.Context, subject, group string, handler Handler) error {
+	conn, err := redis.Dial(r.u)
+	if err != nil {
+		return err
+	}
+
+	if group == "" {
+		go func() {
+			defer func() { _ = conn.Close() }()
+			consume(ctx, conn, subject, handler)
+		}()
+		return nil
+	}
+
+	if _, err := conn.Do("XGROUP", "CREATE", subject, group, "$", "MKSTREAM"); err != nil && !strings.HasPrefix(err.Error(), "BUSYGROUP") {
+		_ = conn.Close()
+		return fmt.Errorf("redis: %w", err)
+	}
+	go func() {
+		defer func() { _ = conn.Close() }()
+		consumeGroup(ctx, conn, subject, group, handler)
+	}()
+	return nil
+}
+
+// Close closes the connection used for publishing.
+func (r *Redis) Close() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.conn.Close()
+}
+
+// streamEntry is a message of a stream.
+type streamEntry struct {
+	id   string
+	data []byte
+}
+
+// streamEntries returns the entries of an XREAD or XREADGROUP reply for
+// one stream: [[stream, [[id, [field, value, ...]], ...]]].
+func streamEntries(reply any) []streamEntry {
+	streams, _ := reply.([]any)
+	var entries []streamEntry
+	for _, s := range streams {
+		stream, _ := s.([]any)
+		if len(stream) != 2 {
+			continue
+		}
+		items, _ := stream[1].([]any)
+		for _, it := range items {
+			item, _ := it.([]any)
+			if len(item) != 2 {
+				continue
+			}
+			id, _ := item[0].(string)
+			fields, _ := item[1].([]any)
+			e := streamEntry{id: id}
+			for i := 0; i+1 < len(fields); i += 2 {
+				if k, _ := fields[i].(string); k == "data" {
+					v, _ := fields[i+1].(string)
+					e.data = []byte(v)
+				}
+			}
+			entries = append(entries, e)
+		}
+	}
+	return entries
+}
+
+// consume delivers the messages added to a stream after now.
+func consume(ctx context.Context, c *redis.Conn, subject string, handler Handler) {
+	last := "$"
+	block := strconv.Itoa(int(redisBlock / time.Millisecond))
+	for ctx.Err() == nil {
+		reply, err := c.Do("XREAD", "COUNT", "100", "BLOCK", block, "STREAMS", subject, last)
+		if err != nil {
+			if ctx.Err() == nil {
+				fmt.Printf("Warning: redis %s: %v\n", subject, err)
+			}
+			return
+		}
+		for _, e := range streamEntries(reply) {
+			handler(e.data)
+			last = e.id
+		}
+	}
+}
+
+// consumeGroup delivers the messages of a stream given to this consumer
+// of group, acknowledging each once handled.
+func consumeGroup(ctx context.Context, c *redis.Conn, subject, group string, handler Handler) {
+	host, _ := os.Hostname()
+	consumer := fmt.Sprintf("%s-%d", host, os.Getpid())
+	block := strconv.Itoa(int(redisBlock / time.Millisecond))
+	for ctx.Err() == nil {
+		reply, err := c.Do("XREADGROUP", "GROUP", group, consumer, "COUNT", "10", "BLOCK", block, "STREAMS", subject, ">")
+		if err != nil {
+			if ctx.Err() == nil {
+				fmt.Printf("Warning: redis %s: %v\n", subject, err)
+			}
+			return
+		}
+		for _, e := range streamEntries(reply) {
+			handler(e.data)
+			if _, err := c.Do("XACK", subject, group, e.id); err != nil {
+				fmt.Printf("Warning: redis %s: %v\n", subject, err)
+			}
+		}
+	}
+}
@@ -0,0 +1,305 @@
+package channel
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// AlertConfig holds alert webhook channel configuration.
+type AlertConfig struct {
+	Listen       string // address of the webhook server (default ":8091")
+	Path         string // URL path prefix; PagerDuty posts to <path>/pagerduty, Opsgenie to <path>/opsgenie (default "/alerts")
+	Secret       string // PagerDuty signing secret, and the X-Klaw-Secret header Opsgenie sends
+	Agent        string // agent that investigates the alerts; empty for the default agent
+	SlackChannel string // Slack channel ID summaries are posted to when the alert names none
+}
+
+// incidentTTL is how long an incident's Slack channel is remembered for
+// the replies of its investigation.
+const incidentTTL = 24 * time.Hour
+
+// AlertChannel receives PagerDuty and Opsgenie alert webhooks and turns
+// each new incident into a high-priority message for the SRE agent. The
+// agent's investigation summary is posted to the incident's Slack channel.
+// Each incident is a conversation: Metadata["channel"] is
+// "alert:<source>" and Metadata["thread_ts"] the incident ID.
+type AlertChannel struct {
+	*webhookChannel
+
+	cfg  AlertConfig
+	post func(channelID, text string) error
+
+	// Incidents being investigated, per channel:thread_ts
+	mu        sync.Mutex
+	incidents map[string]*incident
+}
+
+// incident is an alert under investigation.
+type incident struct {
+	title        string
+	url          string
+	slackChannel string
+	at           time.Time
+}
+
+// NewAlertChannel creates a new alert webhook channel.
+func NewAlertChannel(cfg AlertConfig) (*AlertChannel, error) {
+	if cfg.Secret == "" {
+		return nil, fmt.Errorf("webhook secret is required")
+	}
+	if cfg.Listen == "" {
+		cfg.Listen = ":8091"
+	}
+	if cfg.Path == "" {
+		cfg.Path = "/alerts"
+	}
+	cfg.Path = strings.TrimSuffix(cfg.Path, "/")
+
+	a := &AlertChannel{
+		cfg:       cfg,
+		incidents: make(map[string]*incident),
+	}
+	mux := http.NewServeMux()
+	mux.HandleFunc(cfg.Path+"/pagerduty", a.handlePagerDuty)
+	mux.HandleFunc(cfg.Path+"/opsgenie", a.handleOpsgenie)
+	a.webhookChannel = newWebhookChannel("alerts", cfg.Listen, "alert:", mux, a.reply)
+	return a, nil
+}
+
+// SetPoster sets the function investigation summaries are posted to Slack
+// with. Without it, summaries are printed.
+func (a *AlertChannel) SetPoster(post func(channelID, text string) error) {
+	a.post = post
+}
+
+// pagerDutyWebhook is the part of a PagerDuty V3 webhook the channel reads.
+type pagerDutyWebhook struct {
+	Event struct {
+		EventType string `json:"event_type"`
+		Data      struct {
+			ID      string `json:"id"`
+			Number  int    `json:"number"`
+			Title   string `json:"title"`
+			HTMLURL string `json:"html_url"`
+			Urgency string `json:"urgency"`
+			Service struct {
+				Summary string `json:"summary"`
+			} `json:"service"`
+			Priority *struct {
+				Summary string `json:"summary"`
+			} `json:"priority"`
+		} `json:"data"`
+	} `json:"event"`
+}
+
+func (a *AlertChannel) handlePagerDuty(w http.ResponseWriter, r *http.Request) {
+	body, ok := readWebhook(w, r)
+	if !ok {
+		return
+	}
+	if !validPagerDutySignature(a.cfg.Secret, body, r.Header.Get("X-PagerDuty-Signature")) {
+		http.Error(w, "invalid signature", http.StatusUnauthorized)
+		return
+	}
+	var hook pagerDutyWebhook
+	if err := json.Unmarshal(body, &hook); err != nil {
+		http.Error(w, "invalid payload", http.StatusBadRequest)
+		return
+	}
+	w.WriteHeader(http.StatusAccepted)
+
+	if hook.Event.EventType != "incident.triggered" {
+		return
+	}
+	d := hook.Event.Data
+	details := map[string]string{"service": d.Service.Summary, "urgency": d.Urgency}
+	if d.Priority != nil {
+		details["priority"] = d.Priority.Summary
+	}
+	a.dispatch("pagerduty", d.ID, fmt.Sprintf("#%d %s", d.Number, d.Title), d.HTMLURL, "", details)
+}
+
+// validPagerDutySignature checks the X-PagerDuty-Signature header of a
+// delivery, which lists a signature per signing secret in use.
+func validPagerDutySignature(secret string, body []byte, header string) bool {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	want := mac.Sum(nil)
+	for _, sig := range strings.Split(header, ",") {
+		sig, ok := strings.CutPrefix(strings.TrimSpace(sig), "v1=")
+		if !ok {
+			continue
+		}
+		if got, err := hex.DecodeString(sig); err == nil && hmac.Equal(got, want) {
+			return true
+		}
+	}
+	return false
+}
+
+// opsgenieWebhook is the part of an Opsgenie webhook the channel reads.
+type opsgenieWebhook struct {
+	Action string `json:"action"`
+	Alert  struct {
+		AlertID     string            `json:"alertId"`
+		Message     string            `json:"message"`
+		Description string            `json:"description"`
+		Priority    string            `json:"priority"`
+		Entity      string            `json:"entity"`
+		Tags        []string          `json:"tags"`
+		Details     map[string]string `json:"details"`
+	} `json:"alert"`
+}
+
+func (a *AlertChannel) handleOpsgenie(w http.ResponseWriter, r *http.Request) {
+	body, ok := readWebhook(w, r)
+	if !ok {
+		return
+	}
+	// Opsgenie doesn't sign deliveries; the webhook integration sends the
+	// secret as a custom header
+	if subtle.ConstantTimeCompare([]byte(r.Header.Get("X-Klaw-Secret")), []byte(a.cfg.Secret)) != 1 {
+		http.Error(w, "invalid secret", http.StatusUnauthorized)
+		return
+	}
+	var hook opsgenieWebhook
+	if err := json.Unmarshal(body, &hook); err != nil {
+		http.Error(w, "invalid payload", http.StatusBadRequest)
+		return
+	}
+	w.WriteHeader(http.StatusAccepted)
+
+	if hook.Action != "Create" {
+		return
+	}
+	al := hook.Alert
+	details := map[string]string{"priority": al.Priority, "entity": al.Entity}
+	if len(al.Tags) > 0 {
+		details["tags"] = strings.Join(al.Tags, ",")
+	}
+	title := al.Message
+	if al.Description != "" {
+		title += "\n\n" + al.Description
+	}
+	for k, v := range al.Details {
+		if k != "slack_channel" {
+			details[k] = v
+		}
+	}
+	a.dispatch("opsgenie", al.AlertID, title, "", al.Details["slack_channel"], details)
+}
+
+// dispatch sends a new incident to the agent. slackChannel overrides the
+// configured Slack channel.
+func (a *AlertChannel) dispatch(source, id, title, url, slackChannel string, details map[string]string) {
+	if id == "" {
+		return
+	}
+	if slackChannel == "" {
+		slackChannel = a.cfg.SlackChannel
+	}
+	channel := "alert:" + source
+	threadKey := channel + ":" + id
+
+	a.mu.Lock()
+	for key, inc := range a.incidents {
+		if time.Since(inc.at) > incidentTTL {
+			delete(a.incidents, key)
+		}
+	}
+	if _, dup := a.incidents[threadKey]; dup {
+		// A redelivery of an incident already under investigation
+		a.mu.Unlock()
+		return
+	}
+	summary, _, _ := strings.Cut(title, "\n")
+	a.incidents[threadKey] = &incident{title: summary, url: url, slackChannel: slackChannel, at: time.Now()}
+	a.mu.Unlock()
+
+	header := fmt.Sprintf("[Alert from %s: id=%s", source, id)
+	for _, k := range sortedKeys(details) {
+		if details[k] != "" {
+			header += fmt.Sprintf(" %s=%s", k, details[k])
+		}
+	}
+	if url != "" {
+		header += " url=" + url
+	}
+	header += "]"
+
+	fmt.Printf("[alerts] %s incident %s: %s\n", source, id, summary)
+	metadata := map[string]any{
+		"channel":      channel,
+		"thread_ts":    id,
+		"user":         source,
+		MetaPriority:   PriorityHigh,
+		"alert_source": source,
+		"alert_id":     id,
+		"alert_url":    url,
+	}
+	if a.cfg.Agent != "" {
+		metadata[MetaAgent] = a.cfg.Agent
+	}
+	msg := &Message{
+		ID:        uuid.New().String(),
+		Role:      "user",
+		Content:   header + "\n\nIncident triggered: " + title + "\n\nInvestigate this alert and reply with a short summary: likely cause, impact, and next steps.",
+		Timestamp: time.Now(),
+		Metadata:  metadata,
+	}
+	a.deliver(msg)
+}
+
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// reply posts the agent's investigation summary to the Slack channel of
+// the incident.
+func (a *AlertChannel) reply(ctx context.Context, channel, threadTS, text string, failed bool) error {
+	a.mu.Lock()
+	inc := a.incidents[channel+":"+threadTS]
+	a.mu.Unlock()
+	if inc == nil {
+		// Not an incident under investigation
+		return nil
+	}
+
+	if failed {
+		return a.postSummary(inc, fmt.Sprintf(":x: Investigation of *%s* failed: %s", inc.title, text))
+	}
+	title := "*" + inc.title + "*"
+	if inc.url != "" {
+		title = fmt.Sprintf("*<%s|%s>*", inc.url, inc.title)
+	}
+	return a.postSummary(inc, fmt.Sprintf(":rotating_light: %s\n\n%s", title, text))
+}
+
+// postSummary posts text to the incident's Slack channel.
+func (a *AlertChannel) postSummary(inc *incident, text string) error {
+	if a.post == nil || inc.slackChannel == "" {
+		fmt.Printf("[alerts] %s\n", text)
+		return nil
+	}
+	if err := a.post(inc.slackChannel, text); err != nil {
+		return fmt.Errorf("failed to post to %s: %w", inc.slackChannel, err)
+	}
+	return nil
+}
@@ -35,4 +35,43 @@ type Message struct {
 	// For streaming assistant responses
 	IsPartial bool
 	IsDone    bool
+
+	// Files to deliver alongside the message (channels without file
+	// support ignore them).
+	Attachments []Attachment
+}
+
+// Attachment is a file sent with a message.
+type Attachment struct {
+	Name     string
+	MimeType string
+	Data     []byte
+}
+
+// Metadata["command"] values of control messages a channel sends to the
+// agent instead of user input.
+const (
+	// CommandStop cancels the running turn of the message's conversation.
+	// Without a thread_ts, every conversation in the channel is stopped.
+	CommandStop = "stop"
+)
+
+// Metadata["event"] values of "system" messages the agent sends around each
+// turn. Channels that have no use for them ignore "system" messages.
+const (
+	EventTurnStart = "turn_start"
+	EventTurnEnd   = "turn_end"
+)
+
+// Connection states reported through HealthReporter.
+const (
+	HealthConnecting = "connecting"
+	HealthConnected  = "connected"
+	HealthError      = "error"
+)
+
+// HealthReporter is implemented by channels that report changes of their
+// connection to the service behind them. detail explains an error.
+type HealthReporter interface {
+	OnHealth(func(state, detail string))
 }
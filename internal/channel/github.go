@@ -0,0 +1,296 @@
+package channel
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// GitHubConfig holds GitHub webhook channel configuration.
+type GitHubConfig struct {
+	Listen string // address of the webhook server (default ":8090")
+	Path   string // URL path of the webhook (default "/github")
+	Secret string // webhook secret the X-Hub-Signature-256 header is checked against
+	Token  string // token replies are posted as issue comments with; without it replies are dropped
+	APIURL string // GitHub API base URL (default "https://api.github.com")
+}
+
+// GitHubChannel receives GitHub webhooks and turns issue and pull request
+// events into agent messages. Each issue or pull request is a
+// conversation: Metadata["channel"] is "github:<owner>/<repo>" and
+// Metadata["thread_ts"] its number. Replies are posted as comments on it.
+type GitHubChannel struct {
+	*webhookChannel
+
+	cfg    GitHubConfig
+	client *http.Client
+	login  string // the token's user, whose own comments are ignored
+}
+
+// NewGitHubChannel creates a new GitHub webhook channel.
+func NewGitHubChannel(cfg GitHubConfig) (*GitHubChannel, error) {
+	if cfg.Secret == "" {
+		return nil, fmt.Errorf("webhook secret is required")
+	}
+	if cfg.Listen == "" {
+		cfg.Listen = ":8090"
+	}
+	if cfg.Path == "" {
+		cfg.Path = "/github"
+	}
+	if cfg.APIURL == "" {
+		cfg.APIURL = "https://api.github.com"
+	}
+	cfg.APIURL = strings.TrimSuffix(cfg.APIURL, "/")
+
+	g := &GitHubChannel{
+		cfg:    cfg,
+		client: &http.Client{Timeout: 30 * time.Second},
+	}
+	mux := http.NewServeMux()
+	mux.HandleFunc(cfg.Path, g.handleWebhook)
+	g.webhookChannel = newWebhookChannel("github", cfg.Listen, "github:", mux, g.reply)
+	g.setup = g.authenticate
+	return g, nil
+}
+
+// authenticate looks up the token's user before the server starts.
+func (g *GitHubChannel) authenticate(ctx context.Context) error {
+	if g.cfg.Token == "" {
+		return nil
+	}
+	login, err := g.tokenLogin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to authenticate: %w", err)
+	}
+	g.login = login
+	return nil
+}
+
+// tokenLogin returns the login of the user the token belongs to.
+func (g *GitHubChannel) tokenLogin(ctx context.Context) (string, error) {
+	var user struct {
+		Login string `json:"login"`
+	}
+	if err := g.api(ctx, http.MethodGet, "/user", nil, &user); err != nil {
+		return "", err
+	}
+	return user.Login, nil
+}
+
+// githubUser is the user object of webhook payloads.
+type githubUser struct {
+	Login string `json:"login"`
+	Type  string `json:"type"` // "User" or "Bot"
+}
+
+// githubIssue is the issue or pull request object of webhook payloads.
+type githubIssue struct {
+	Number  int        `json:"number"`
+	Title   string     `json:"title"`
+	Body    string     `json:"body"`
+	HTMLURL string     `json:"html_url"`
+	User    githubUser `json:"user"`
+	Labels  []struct {
+		Name string `json:"name"`
+	} `json:"labels"`
+	PullRequest *struct{} `json:"pull_request"` // set on issues that are pull requests
+}
+
+// githubEvent is the part of webhook payloads the channel reads.
+type githubEvent struct {
+	Action  string       `json:"action"`
+	Issue   *githubIssue `json:"issue"`
+	PullReq *githubIssue `json:"pull_request"`
+	Comment *struct {
+		Body    string     `json:"body"`
+		HTMLURL string     `json:"html_url"`
+		User    githubUser `json:"user"`
+	} `json:"comment"`
+	Repository struct {
+		FullName string `json:"full_name"`
+	} `json:"repository"`
+	Sender            githubUser  `json:"sender"`
+	RequestedReviewer *githubUser `json:"requested_reviewer"`
+}
+
+func (g *GitHubChannel) handleWebhook(w http.ResponseWriter, r *http.Request) {
+	body, ok := readWebhook(w, r)
+	if !ok {
+		return
+	}
+	if !validGitHubSignature(g.cfg.Secret, body, r.Header.Get("X-Hub-Signature-256")) {
+		http.Error(w, "invalid signature", http.StatusUnauthorized)
+		return
+	}
+
+	var ev githubEvent
+	if err := json.Unmarshal(body, &ev); err != nil {
+		http.Error(w, "invalid payload", http.StatusBadRequest)
+		return
+	}
+
+	// Answer right away; GitHub gives up on deliveries after 10 seconds
+	w.WriteHeader(http.StatusAccepted)
+
+	msg := g.eventMessage(r.Header.Get("X-GitHub-Event"), &ev)
+	if msg == nil {
+		return
+	}
+	fmt.Printf("[github] %s %s#%v\n", msg.Metadata["github_event"], ev.Repository.FullName, msg.Metadata["thread_ts"])
+	g.deliver(msg)
+}
+
+// eventMessage turns a webhook event into a message for the agent, or
+// returns nil for events the channel does not handle.
+func (g *GitHubChannel) eventMessage(event string, ev *githubEvent) *Message {
+	// Ignore the channel's own comments and other bots, which would loop
+	if ev.Sender.Type == "Bot" || (g.login != "" && ev.Sender.Login == g.login) {
+		return nil
+	}
+
+	var issue *githubIssue
+	var kind, text string
+	switch {
+	case event == "issues" && ev.Action == "opened" && ev.Issue != nil:
+		issue = ev.Issue
+		kind = "issue_opened"
+		text = fmt.Sprintf("%s opened issue #%d: %s\n\n%s", ev.Sender.Login, issue.Number, issue.Title, issue.Body)
+
+	case event == "issue_comment" && ev.Action == "created" && ev.Issue != nil && ev.Comment != nil:
+		issue = ev.Issue
+		kind = "issue_comment"
+		what := "issue"
+		if issue.PullRequest != nil {
+			kind = "pr_comment"
+			what = "pull request"
+		}
+		text = fmt.Sprintf("%s commented on %s #%d (%s):\n\n%s", ev.Sender.Login, what, issue.Number, issue.Title, ev.Comment.Body)
+
+	case event == "pull_request" && ev.Action == "review_requested" && ev.PullReq != nil:
+		issue = ev.PullReq
+		kind = "review_requested"
+		reviewer := ""
+		if ev.RequestedReviewer != nil {
+			reviewer = ev.RequestedReviewer.Login
+		}
+		text = fmt.Sprintf("%s requested a review from %s on pull request #%d: %s\n\n%s",
+			ev.Sender.Login, reviewer, issue.Number, issue.Title, issue.Body)
+
+	default:
+		return nil
+	}
+
+	labels := make([]string, 0, len(issue.Labels))
+	for _, l := range issue.Labels {
+		labels = append(labels, l.Name)
+	}
+	header := fmt.Sprintf("[GitHub %s: repo=%s number=%d url=%s", kind, ev.Repository.FullName, issue.Number, issue.HTMLURL)
+	if len(labels) > 0 {
+		header += " labels=" + strings.Join(labels, ",")
+	}
+	header += "]"
+
+	return &Message{
+		ID:        uuid.New().String(),
+		Role:      "user",
+		Content:   header + "\n\n" + strings.TrimSpace(text),
+		Timestamp: time.Now(),
+		Metadata: map[string]any{
+			"channel":      "github:" + ev.Repository.FullName,
+			"thread_ts":    strconv.Itoa(issue.Number),
+			"user":         ev.Sender.Login,
+			"github_event": kind,
+			"github_repo":  ev.Repository.FullName,
+			"github_issue": issue.Number,
+			"github_title": issue.Title,
+			"github_url":   issue.HTMLURL,
+		},
+	}
+}
+
+// validGitHubSignature checks the X-Hub-Signature-256 header of a delivery.
+func validGitHubSignature(secret string, body []byte, header string) bool {
+	sig, ok := strings.CutPrefix(header, "sha256=")
+	if !ok {
+		return false
+	}
+	got, err := hex.DecodeString(sig)
+	if err != nil {
+		return false
+	}
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return hmac.Equal(got, mac.Sum(nil))
+}
+
+// reply posts the agent's answer as a comment on the issue or pull
+// request of the conversation.
+func (g *GitHubChannel) reply(ctx context.Context, channel, threadTS, text string, failed bool) error {
+	repo := strings.TrimPrefix(channel, "github:")
+	if failed {
+		// Errors stay out of public issues
+		fmt.Printf("[github] error in %s#%s: %s\n", repo, threadTS, text)
+		return nil
+	}
+	return g.comment(ctx, repo, threadTS, text)
+}
+
+// comment posts text as a comment on an issue or pull request.
+func (g *GitHubChannel) comment(ctx context.Context, repo, number, text string) error {
+	if g.cfg.Token == "" {
+		fmt.Printf("[github] no token set, dropping reply to %s#%s\n", repo, number)
+		return nil
+	}
+	path := fmt.Sprintf("/repos/%s/issues/%s/comments", repo, number)
+	if err := g.api(ctx, http.MethodPost, path, map[string]string{"body": text}, nil); err != nil {
+		return fmt.Errorf("failed to comment on %s#%s: %w", repo, number, err)
+	}
+	return nil
+}
+
+// api calls the GitHub REST API, decoding the response into out if set.
+func (g *GitHubChannel) api(ctx context.Context, method, path string, in, out any) error {
+	var body io.Reader
+	if in != nil {
+		data, err := json.Marshal(in)
+		if err != nil {
+			return err
+		}
+		body = bytes.NewReader(data)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, g.cfg.APIURL+path, body)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/vnd.github+json")
+	req.Header.Set("Authorization", "Bearer "+g.cfg.Token)
+	if in != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := g.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		data, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("GitHub API %s: %s", resp.Status, strings.TrimSpace(string(data)))
+	}
+	if out != nil {
+		return json.NewDecoder(resp.Body).Decode(out)
+	}
+	return nil
+}
@@ -0,0 +1,276 @@
+package channel
+
+import (
+	"bytes"
+	"context"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// JiraConfig holds Jira webhook channel configuration.
+type JiraConfig struct {
+	Listen string // address of the webhook server (default ":8092")
+	Path   string // URL path of the webhook (default "/jira")
+	Secret string // webhook secret, checked against X-Hub-Signature or a ?secret= query parameter
+	URL    string // Jira site URL replies are posted to, e.g. https://acme.atlassian.net
+	Email  string // account email for Jira Cloud API tokens; empty for personal access tokens
+	Token  string // API token replies are posted as comments with; without it replies are dropped
+	Agent  string // agent that triages the tickets; empty for the default agent
+}
+
+// JiraChannel receives Jira webhooks and turns newly created issues into
+// agent messages, so an agent can triage them. Each issue is a
+// conversation: Metadata["channel"] is "jira:<project>" and
+// Metadata["thread_ts"] the issue key. Replies are posted as comments on it.
+type JiraChannel struct {
+	*webhookChannel
+
+	cfg     JiraConfig
+	client  *http.Client
+	account string // the token's account, whose own issues are ignored
+}
+
+// NewJiraChannel creates a new Jira webhook channel.
+func NewJiraChannel(cfg JiraConfig) (*JiraChannel, error) {
+	if cfg.Secret == "" {
+		return nil, fmt.Errorf("webhook secret is required")
+	}
+	if cfg.Token != "" && cfg.URL == "" {
+		return nil, fmt.Errorf("jira site URL is required to post replies")
+	}
+	if cfg.Listen == "" {
+		cfg.Listen = ":8092"
+	}
+	if cfg.Path == "" {
+		cfg.Path = "/jira"
+	}
+	cfg.URL = strings.TrimSuffix(cfg.URL, "/")
+
+	j := &JiraChannel{
+		cfg:    cfg,
+		client: &http.Client{Timeout: 30 * time.Second},
+	}
+	mux := http.NewServeMux()
+	mux.HandleFunc(cfg.Path, j.handleWebhook)
+	j.webhookChannel = newWebhookChannel("jira", cfg.Listen, "jira:", mux, j.reply)
+	j.setup = j.authenticate
+	return j, nil
+}
+
+// authenticate looks up the token's account before the server starts.
+func (j *JiraChannel) authenticate(ctx context.Context) error {
+	if j.cfg.Token == "" {
+		return nil
+	}
+	account, err := j.myself(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to authenticate: %w", err)
+	}
+	j.account = account
+	return nil
+}
+
+// jiraUser is a user of webhook payloads and the API. Jira Cloud
+// identifies users by account ID, Jira Data Center by name.
+type jiraUser struct {
+	AccountID   string `json:"accountId"`
+	Name        string `json:"name"`
+	DisplayName string `json:"displayName"`
+}
+
+func (u *jiraUser) id() string {
+	if u.AccountID != "" {
+		return u.AccountID
+	}
+	return u.Name
+}
+
+// myself returns the account the token belongs to.
+func (j *JiraChannel) myself(ctx context.Context) (string, error) {
+	var user jiraUser
+	if err := j.api(ctx, http.MethodGet, "/rest/api/2/myself", nil, &user); err != nil {
+		return "", err
+	}
+	return user.id(), nil
+}
+
+// jiraEvent is the part of webhook payloads the channel reads.
+type jiraEvent struct {
+	WebhookEvent string    `json:"webhookEvent"`
+	User         *jiraUser `json:"user"`
+	Issue        *struct {
+		Key    string `json:"key"`
+		Fields struct {
+			Summary     string `json:"summary"`
+			Description string `json:"description"`
+			IssueType   struct {
+				Name string `json:"name"`
+			} `json:"issuetype"`
+			Priority *struct {
+				Name string `json:"name"`
+			} `json:"priority"`
+			Project struct {
+				Key string `json:"key"`
+			} `json:"project"`
+			Labels   []string  `json:"labels"`
+			Reporter *jiraUser `json:"reporter"`
+		} `json:"fields"`
+	} `json:"issue"`
+}
+
+func (j *JiraChannel) handleWebhook(w http.ResponseWriter, r *http.Request) {
+	body, ok := readWebhook(w, r)
+	if !ok {
+		return
+	}
+	// Jira Cloud signs deliveries of webhooks that have a secret like
+	// GitHub does; Data Center and automation rules can only pass it in
+	// the URL
+	if !validGitHubSignature(j.cfg.Secret, body, r.Header.Get("X-Hub-Signature")) &&
+		subtle.ConstantTimeCompare([]byte(r.URL.Query().Get("secret")), []byte(j.cfg.Secret)) != 1 {
+		http.Error(w, "invalid signature", http.StatusUnauthorized)
+		return
+	}
+
+	var ev jiraEvent
+	if err := json.Unmarshal(body, &ev); err != nil {
+		http.Error(w, "invalid payload", http.StatusBadRequest)
+		return
+	}
+	w.WriteHeader(http.StatusAccepted)
+
+	msg := j.eventMessage(&ev)
+	if msg == nil {
+		return
+	}
+	fmt.Printf("[jira] issue created: %s\n", msg.Metadata["thread_ts"])
+	j.deliver(msg)
+}
+
+// eventMessage turns a webhook event into a message for the agent, or
+// returns nil for events the channel does not handle.
+func (j *JiraChannel) eventMessage(ev *jiraEvent) *Message {
+	if ev.WebhookEvent != "jira:issue_created" || ev.Issue == nil || ev.Issue.Key == "" {
+		return nil
+	}
+	// Ignore issues the agent opened itself with jira_create
+	if j.account != "" && ev.User != nil && ev.User.id() == j.account {
+		return nil
+	}
+
+	issue := ev.Issue
+	f := issue.Fields
+	reporter := ""
+	if f.Reporter != nil {
+		reporter = f.Reporter.DisplayName
+	} else if ev.User != nil {
+		reporter = ev.User.DisplayName
+	}
+
+	header := fmt.Sprintf("[Jira issue_created: key=%s project=%s type=%s", issue.Key, f.Project.Key, f.IssueType.Name)
+	if f.Priority != nil {
+		header += " priority=" + f.Priority.Name
+	}
+	if len(f.Labels) > 0 {
+		header += " labels=" + strings.Join(f.Labels, ",")
+	}
+	issueURL := ""
+	if j.cfg.URL != "" {
+		issueURL = j.cfg.URL + "/browse/" + issue.Key
+		header += " url=" + issueURL
+	}
+	header += "]"
+	text := fmt.Sprintf("%s created %s: %s\n\n%s", reporter, issue.Key, f.Summary, f.Description)
+
+	metadata := map[string]any{
+		"channel":      "jira:" + f.Project.Key,
+		"thread_ts":    issue.Key,
+		"user":         reporter,
+		"jira_project": f.Project.Key,
+		"jira_issue":   issue.Key,
+		"jira_title":   f.Summary,
+		"jira_url":     issueURL,
+	}
+	if j.cfg.Agent != "" {
+		metadata[MetaAgent] = j.cfg.Agent
+	}
+	return &Message{
+		ID:        uuid.New().String(),
+		Role:      "user",
+		Content:   header + "\n\n" + strings.TrimSpace(text),
+		Timestamp: time.Now(),
+		Metadata:  metadata,
+	}
+}
+
+// reply posts the agent's answer as a comment on the issue of the
+// conversation.
+func (j *JiraChannel) reply(ctx context.Context, channel, key, text string, failed bool) error {
+	if failed {
+		// Errors stay out of tickets
+		fmt.Printf("[jira] error in %s: %s\n", key, text)
+		return nil
+	}
+	return j.comment(ctx, key, text)
+}
+
+// comment posts text as a comment on an issue.
+func (j *JiraChannel) comment(ctx context.Context, key, text string) error {
+	if j.cfg.Token == "" {
+		fmt.Printf("[jira] no token set, dropping reply to %s\n", key)
+		return nil
+	}
+	path := "/rest/api/2/issue/" + url.PathEscape(key) + "/comment"
+	if err := j.api(ctx, http.MethodPost, path, map[string]string{"body": text}, nil); err != nil {
+		return fmt.Errorf("failed to comment on %s: %w", key, err)
+	}
+	return nil
+}
+
+// api calls the Jira REST API, decoding the response into out if set.
+func (j *JiraChannel) api(ctx context.Context, method, path string, in, out any) error {
+	var body io.Reader
+	if in != nil {
+		data, err := json.Marshal(in)
+		if err != nil {
+			return err
+		}
+		body = bytes.NewReader(data)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, j.cfg.URL+path, body)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+	if j.cfg.Email != "" {
+		req.Header.Set("Authorization", "Basic "+base64.StdEncoding.EncodeToString([]byte(j.cfg.Email+":"+j.cfg.Token)))
+	} else {
+		req.Header.Set("Authorization", "Bearer "+j.cfg.Token)
+	}
+	if in != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := j.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		data, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("Jira API %s: %s", resp.Status, strings.TrimSpace(string(data)))
+	}
+	if out != nil {
+		return json.NewDecoder(resp.Body).Decode(out)
+	}
+	return nil
+}
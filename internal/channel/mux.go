@@ -0,0 +1,239 @@
+package channel
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// MetaBinding is the metadata key of the channel a message came from, and
+// of the channel a reply goes to, when several channels are served by a Mux.
+const MetaBinding = "binding"
+
+// MetaReplyTo is the metadata key of the bus subject the replies to a
+// message go to, when it came through a message bus.
+const MetaReplyTo = "reply_to"
+
+// MetaPriority is the metadata key of a message's priority: low, normal
+// (the default), high or a number, see priority.Parse. Messages waiting for
+// a worker are handled in order of priority; those with PriorityHigh are
+// handled right away, even when every worker is busy.
+const MetaPriority = "priority"
+
+// PriorityHigh is the MetaPriority value of urgent messages, e.g. alerts.
+const PriorityHigh = "high"
+
+// MetaAgent is the metadata key of the agent a message is meant for,
+// bypassing routing.
+const MetaAgent = "agent"
+
+// MetaLocale is the metadata key of the language of the user who sent a
+// message, e.g. "tr", when the channel knows it. Replies carry it too.
+const MetaLocale = "locale"
+
+// Mux serves several channels as one. Messages received from a channel are
+// tagged with its name in Metadata["binding"], and a message sent with that
+// key is delivered to the named channel only; one without it goes to all.
+// Channels can be added and removed while the Mux is running.
+type Mux struct {
+	out chan *Message
+
+	mu       sync.Mutex
+	ctx      context.Context // set by Start
+	channels map[string]*muxEntry
+}
+
+type muxEntry struct {
+	ch     Channel
+	cancel context.CancelFunc // stops forwarding; nil until started
+}
+
+// NewMux returns an empty Mux.
+func NewMux() *Mux {
+	return &Mux{
+		out:      make(chan *Message, 100),
+		channels: make(map[string]*muxEntry),
+	}
+}
+
+// Add adds ch under name. If the Mux is running, ch is started right away.
+func (m *Mux) Add(name string, ch Channel) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if _, ok := m.channels[name]; ok {
+		return fmt.Errorf("channel %q already added", name)
+	}
+	e := &muxEntry{ch: ch}
+	if m.ctx != nil {
+		if err := m.start(name, e); err != nil {
+			return err
+		}
+	}
+	m.channels[name] = e
+	return nil
+}
+
+// Remove stops the channel added under name and removes it. Removing an
+// unknown name is not an error.
+func (m *Mux) Remove(name string) error {
+	m.mu.Lock()
+	e, ok := m.channels[name]
+	delete(m.channels, name)
+	m.mu.Unlock()
+	if !ok {
+		return nil
+	}
+	if e.cancel != nil {
+		e.cancel()
+	}
+	return e.ch.Stop()
+}
+
+// Get returns the channel added under name.
+func (m *Mux) Get(name string) (Channel, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	e, ok := m.channels[name]
+	if !ok {
+		return nil, false
+	}
+	return e.ch, true
+}
+
+// Names returns the names of the channels, sorted.
+func (m *Mux) Names() []string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	names := make([]string, 0, len(m.channels))
+	for name := range m.channels {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Start starts the channels added so far, and those added later.
+func (m *Mux) Start(ctx context.Context) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.ctx != nil {
+		return nil
+	}
+	m.ctx = ctx
+	for name, e := range m.channels {
+		if err := m.start(name, e); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// start starts e and forwards its messages; m.mu is held.
+func (m *Mux) start(name string, e *muxEntry) error {
+	ctx, cancel := context.WithCancel(m.ctx)
+	if err := e.ch.Start(ctx); err != nil {
+		cancel()
+		return fmt.Errorf("%s: %w", name, err)
+	}
+	e.cancel = cancel
+	go m.forward(ctx, name, e.ch.Receive())
+	return nil
+}
+
+func (m *Mux) forward(ctx context.Context, name string, in <-chan *Message) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case msg, ok := <-in:
+			if !ok {
+				return
+			}
+			if msg.Metadata == nil {
+				msg.Metadata = make(map[string]any, 1)
+			}
+			msg.Metadata[MetaBinding] = name
+			select {
+			case m.out <- msg:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}
+}
+
+// Send delivers msg to the channel named in its metadata, or to every
+// channel when it names none.
+func (m *Mux) Send(ctx context.Context, msg *Message) error {
+	name, _ := msg.Metadata[MetaBinding].(string)
+	m.mu.Lock()
+	var targets []Channel
+	if name != "" {
+		e, ok := m.channels[name]
+		if !ok {
+			m.mu.Unlock()
+			return fmt.Errorf("channel %q is not running", name)
+		}
+		targets = []Channel{e.ch}
+	} else {
+		for _, e := range m.channels {
+			targets = append(targets, e.ch)
+		}
+	}
+	m.mu.Unlock()
+
+	var firstErr error
+	for _, ch := range targets {
+		if err := ch.Send(ctx, msg); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
+
+// Receive returns the messages of all channels.
+func (m *Mux) Receive() <-chan *Message {
+	return m.out
+}
+
+// Stop stops all channels.
+func (m *Mux) Stop() error {
+	m.mu.Lock()
+	entries := make([]*muxEntry, 0, len(m.channels))
+	for _, e := range m.channels {
+		entries = append(entries, e)
+	}
+	m.mu.Unlock()
+
+	var firstErr error
+	for _, e := range entries {
+		if e.cancel != nil {
+			e.cancel()
+		}
+		if err := e.ch.Stop(); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
+
+// Name returns the names of the kinds of channels served, e.g. "slack".
+func (m *Mux) Name() string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	seen := make(map[string]bool)
+	var kinds []string
+	for _, e := range m.channels {
+		if kind := e.ch.Name(); !seen[kind] {
+			seen[kind] = true
+			kinds = append(kinds, kind)
+		}
+	}
+	if len(kinds) == 0 {
+		return "mux"
+	}
+	sort.Strings(kinds)
+	return strings.Join(kinds, ",")
+}
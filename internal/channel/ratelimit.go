@@ -0,0 +1,177 @@
+package channel
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// Default replies to throttled messages.
+const (
+	userThrottleMessage    = "You're sending messages faster than I can answer them. Please wait a minute before sending more."
+	channelThrottleMessage = "This channel is sending messages faster than I can answer them. Please wait a minute before sending more."
+)
+
+// RateLimit caps the messages accepted from one user and from one channel
+// (the "user" and "channel" metadata of a message) within a window.
+type RateLimit struct {
+	PerUser    int           // 0 = no limit
+	PerChannel int           // 0 = no limit
+	Window     time.Duration // default: one minute
+	Message    string        // reply to a throttled message; default: a note to wait
+}
+
+// Enabled reports whether any limit is set.
+func (l RateLimit) Enabled() bool {
+	return l.PerUser > 0 || l.PerChannel > 0
+}
+
+// RateLimited is a channel whose incoming messages are rate limited per
+// user and per channel. A message over a limit is dropped; the sender is
+// told to slow down once per window.
+type RateLimited struct {
+	Channel
+	limit RateLimit
+	out   chan *Message
+	once  sync.Once
+
+	mu       sync.Mutex
+	sent     map[string][]time.Time // accepted messages by "user:" and "channel:" key
+	notified map[string]time.Time   // last throttle reply by key
+	now      func() time.Time
+}
+
+// NewRateLimited wraps ch with limit.
+func NewRateLimited(ch Channel, limit RateLimit) *RateLimited {
+	if limit.Window <= 0 {
+		limit.Window = time.Minute
+	}
+	return &RateLimited{
+		Channel:  ch,
+		limit:    limit,
+		out:      make(chan *Message, 100),
+		sent:     make(map[string][]time.Time),
+		notified: make(map[string]time.Time),
+		now:      time.Now,
+	}
+}
+
+// Start starts the wrapped channel and the filtering of its messages.
+func (r *RateLimited) Start(ctx context.Context) error {
+	if err := r.Channel.Start(ctx); err != nil {
+		return err
+	}
+	r.once.Do(func() { go r.forward(ctx) })
+	return nil
+}
+
+// Receive returns the messages within the limits.
+func (r *RateLimited) Receive() <-chan *Message {
+	return r.out
+}
+
+func (r *RateLimited) forward(ctx context.Context) {
+	defer close(r.out)
+	in := r.Channel.Receive()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case msg, ok := <-in:
+			if !ok {
+				return
+			}
+			if allowed, reply := r.allow(msg); !allowed {
+				if reply != "" {
+					r.throttled(ctx, msg, reply)
+				}
+				continue
+			}
+			select {
+			case r.out <- msg:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}
+}
+
+// allow records msg when it is within the limits. Otherwise it returns the
+// reply to send, which is empty when the sender was already told to slow
+// down in this window.
+func (r *RateLimited) allow(msg *Message) (bool, string) {
+	// Control messages, such as stop, are never throttled
+	if _, ok := msg.Metadata["command"]; ok {
+		return true, ""
+	}
+	user, _ := msg.Metadata["user"].(string)
+	channelID, _ := msg.Metadata["channel"].(string)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	now := r.now()
+	check := []struct {
+		key   string
+		limit int
+		reply string
+	}{
+		{"user:" + user, r.limit.PerUser, userThrottleMessage},
+		{"channel:" + channelID, r.limit.PerChannel, channelThrottleMessage},
+	}
+	for i, c := range check {
+		if c.limit <= 0 || c.key == "user:" || c.key == "channel:" {
+			check[i].key = ""
+			continue
+		}
+		if len(r.recent(c.key, now)) < c.limit {
+			continue
+		}
+		if last, ok := r.notified[c.key]; ok && now.Sub(last) < r.limit.Window {
+			return false, ""
+		}
+		r.notified[c.key] = now
+		if r.limit.Message != "" {
+			return false, r.limit.Message
+		}
+		return false, c.reply
+	}
+	for _, c := range check {
+		if c.key != "" {
+			r.sent[c.key] = append(r.sent[c.key], now)
+		}
+	}
+	return true, ""
+}
+
+// recent drops the times of key outside the window and returns the rest.
+func (r *RateLimited) recent(key string, now time.Time) []time.Time {
+	times := r.sent[key]
+	i := 0
+	for i < len(times) && now.Sub(times[i]) >= r.limit.Window {
+		i++
+	}
+	times = times[i:]
+	if len(times) == 0 {
+		delete(r.sent, key)
+		delete(r.notified, key)
+		return nil
+	}
+	r.sent[key] = times
+	return times
+}
+
+// throttled replies to a dropped message in its conversation.
+func (r *RateLimited) throttled(ctx context.Context, msg *Message, reply string) {
+	user, _ := msg.Metadata["user"].(string)
+	channelID, _ := msg.Metadata["channel"].(string)
+	fmt.Printf("[%s] rate limit: dropped message from %s in %s\n", r.Name(), user, channelID)
+
+	metadata := map[string]any{}
+	for _, key := range []string{"channel", "thread_ts"} {
+		if v, ok := msg.Metadata[key]; ok {
+			metadata[key] = v
+		}
+	}
+	_ = r.Channel.Send(ctx, &Message{Role: "assistant", Content: reply, Timestamp: r.now(), Metadata: metadata})
+}
@@ -1,12 +1,16 @@
 package channel
 
 import (
+	"bytes"
+	"cmp"
 	"context"
 	"fmt"
+	"slices"
 	"strings"
 	"sync"
 	"time"
 
+	"github.com/eachlabs/klaw/internal/locale"
 	"github.com/google/uuid"
 	"github.com/slack-go/slack"
 	"github.com/slack-go/slack/slackevents"
@@ -35,6 +39,16 @@ type AgentInfo struct {
 type ThreadHistory struct {
 	Messages   []ThreadMessage
 	LastActive time.Time
+	Summary    string // of the conversation, set when it was archived
+}
+
+// ThreadArchive keeps the histories of threads that went idle, so that a
+// reply to one days later continues the conversation. See
+// SlackChannel.SetThreadArchive.
+type ThreadArchive interface {
+	ArchiveThread(threadKey string, history *ThreadHistory) error
+	// RestoreThread returns the archived history, or nil if there is none
+	RestoreThread(threadKey string) (*ThreadHistory, error)
 }
 
 // ThreadMessage represents a message in thread history
@@ -63,18 +77,34 @@ type SlackChannel struct {
 	// Track ALL threads where bot was mentioned (channel:thread_ts -> history)
 	activeThreads map[string]*ThreadHistory
 
-	// Buffer for streaming
-	streamBuffer  strings.Builder
-	lastMessageTS string
+	// Buffers for streaming, per channel:thread_ts
+	streamBuffers map[string]*strings.Builder
+
+	// "Working" messages with a Stop button, per channel:thread_ts
+	workingMessages map[string]string
 
 	// Agent management
 	agentManager AgentManager
+
+	// Connection state changes, see OnHealth
+	onHealth func(state, detail string)
+
+	// Texts of help and errors, see SetLocale; Slack languages of users
+	texts       *locale.Catalog
+	language    string
+	userLocales map[string]string
+
+	// Threads idle for threadRetention are archived, see SetThreadArchive
+	threadRetention time.Duration
+	archive         ThreadArchive
+	summarize       func(ctx context.Context, prompt string) (string, error)
 }
 
 // SlackConfig holds Slack configuration.
 type SlackConfig struct {
-	BotToken string // xoxb-...
-	AppToken string // xapp-...
+	BotToken        string        // xoxb-...
+	AppToken        string        // xapp-...
+	ThreadRetention time.Duration // how long a thread stays active after its last message (default 1h)
 }
 
 // NewSlackChannel creates a new Slack channel.
@@ -106,6 +136,11 @@ func NewSlackChannel(cfg SlackConfig) (*SlackChannel, error) {
 		messages:      make(chan *Message, 10),
 		done:          make(chan struct{}),
 		activeThreads: make(map[string]*ThreadHistory),
+		streamBuffers: make(map[string]*strings.Builder),
+
+		workingMessages: make(map[string]string),
+		userLocales:     make(map[string]string),
+		threadRetention: cmp.Or(cfg.ThreadRetention, DefaultThreadRetention),
 	}, nil
 }
 
@@ -118,6 +153,71 @@ func (s *SlackChannel) SetAgentManager(am AgentManager) {
 	s.agentManager = am
 }
 
+// SetLocale sets the templates of help and error texts, and the language
+// of users whose Slack language has no templates.
+func (s *SlackChannel) SetLocale(c *locale.Catalog, lang string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.texts = c
+	s.language = lang
+}
+
+// userLanguage returns the Slack language of a user, e.g. "tr", when there
+// are templates for it, or "". Languages are looked up once per user.
+func (s *SlackChannel) userLanguage(userID string) string {
+	if userID == "" {
+		return ""
+	}
+	s.mu.Lock()
+	lang, ok := s.userLocales[userID]
+	texts := s.texts
+	s.mu.Unlock()
+	if !ok {
+		if u, err := s.client.GetUserInfo(userID); err == nil {
+			lang = locale.Normalize(u.Locale)
+		}
+		s.mu.Lock()
+		s.userLocales[userID] = lang
+		s.mu.Unlock()
+	}
+	if lang == "" || !texts.Has(lang) {
+		return ""
+	}
+	return lang
+}
+
+// withLocale tags the metadata of a message with the language of its user.
+func (s *SlackChannel) withLocale(metadata map[string]any, userID string) map[string]any {
+	if lang := s.userLanguage(userID); lang != "" {
+		metadata[MetaLocale] = lang
+	}
+	return metadata
+}
+
+// text renders a help or error text in lang, or else in the language set
+// with SetLocale.
+func (s *SlackChannel) text(lang, key string, data any) string {
+	s.mu.Lock()
+	texts := s.texts
+	if lang == "" {
+		lang = s.language
+	}
+	s.mu.Unlock()
+	return texts.Text(lang, key, data)
+}
+
+// OnHealth sets the function told about changes of the Socket Mode
+// connection. It must be set before Start.
+func (s *SlackChannel) OnHealth(f func(state, detail string)) {
+	s.onHealth = f
+}
+
+func (s *SlackChannel) reportHealth(state, detail string) {
+	if s.onHealth != nil {
+		s.onHealth(state, detail)
+	}
+}
+
 func (s *SlackChannel) Start(ctx context.Context) error {
 	s.mu.Lock()
 	if s.started {
@@ -132,40 +232,129 @@ func (s *SlackChannel) Start(ctx context.Context) error {
 
 	// Run socket client
 	go func() {
-		if err := s.socketClient.Run(); err != nil {
+		if err := s.socketClient.RunContext(ctx); err != nil && ctx.Err() == nil {
 			fmt.Printf("Slack socket error: %v\n", err)
+			s.reportHealth(HealthError, err.Error())
 		}
 	}()
 
-	// Cleanup old threads periodically (every hour, remove threads older than 24h)
+	// Archive threads idle for longer than the retention
 	go s.cleanupOldThreads(ctx)
 
 	return nil
 }
 
+// DefaultThreadRetention is how long a thread stays active after its last
+// message, unless SlackConfig sets another.
+const DefaultThreadRetention = time.Hour
+
+// SetThreadArchive makes the channel archive threads that went idle,
+// instead of forgetting them, and restore them when someone replies.
+// summarize, if set, writes the summary the archive is kept with.
+func (s *SlackChannel) SetThreadArchive(archive ThreadArchive, summarize func(ctx context.Context, prompt string) (string, error)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.archive = archive
+	s.summarize = summarize
+}
+
 func (s *SlackChannel) cleanupOldThreads(ctx context.Context) {
-	ticker := time.NewTicker(10 * time.Minute)
+	ticker := time.NewTicker(min(10*time.Minute, s.threadRetention))
 	defer ticker.Stop()
 
-	maxAge := 1 * time.Hour
-
 	for {
 		select {
 		case <-ctx.Done():
+			s.archiveThreads(func(*ThreadHistory) bool { return true }, false)
 			return
 		case <-s.done:
 			return
 		case <-ticker.C:
-			s.mu.Lock()
 			now := time.Now()
-			for key, history := range s.activeThreads {
-				if now.Sub(history.LastActive) > maxAge {
-					delete(s.activeThreads, key)
-				}
+			s.archiveThreads(func(h *ThreadHistory) bool { return now.Sub(h.LastActive) > s.threadRetention }, true)
+		}
+	}
+}
+
+// archiveThreads removes the active threads that match and, with an
+// archive set, archives them, summarized when summarize is true.
+func (s *SlackChannel) archiveThreads(match func(*ThreadHistory) bool, summarize bool) {
+	idle := make(map[string]*ThreadHistory)
+	s.mu.Lock()
+	for key, history := range s.activeThreads {
+		if match(history) {
+			idle[key] = history
+			delete(s.activeThreads, key)
+		}
+	}
+	archive, summarizer := s.archive, s.summarize
+	s.mu.Unlock()
+
+	if archive == nil {
+		return
+	}
+	for key, history := range idle {
+		if summarize && summarizer != nil && len(history.Messages) > 1 {
+			ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+			if summary, err := summarizer(ctx, threadSummaryPrompt(history)); err == nil {
+				history.Summary = strings.TrimSpace(summary)
+			} else {
+				fmt.Printf("[slack] Failed to summarize thread %s: %v\n", key, err)
 			}
-			s.mu.Unlock()
+			cancel()
+		}
+		if err := archive.ArchiveThread(key, history); err != nil {
+			fmt.Printf("[slack] Failed to archive thread %s: %v\n", key, err)
+		}
+	}
+}
+
+// restoreThread makes the archived history of threadKey, if there is one,
+// active again. It reports whether the thread is active.
+func (s *SlackChannel) restoreThread(threadKey string) bool {
+	s.mu.Lock()
+	_, active := s.activeThreads[threadKey]
+	archive := s.archive
+	s.mu.Unlock()
+	if active {
+		return true
+	}
+	if archive == nil {
+		return false
+	}
+
+	history, err := archive.RestoreThread(threadKey)
+	if err != nil {
+		fmt.Printf("[slack] Failed to restore thread %s: %v\n", threadKey, err)
+		return false
+	}
+	if history == nil {
+		return false
+	}
+	history.LastActive = time.Now()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.activeThreads[threadKey]; !ok {
+		s.activeThreads[threadKey] = history
+		fmt.Printf("[slack] Restored archived thread %s (%d messages)\n", threadKey, len(history.Messages))
+	}
+	return true
+}
+
+// threadSummaryPrompt asks for the summary an idle thread is archived with.
+func threadSummaryPrompt(history *ThreadHistory) string {
+	var sb strings.Builder
+	sb.WriteString("Summarize this Slack conversation between a user and an assistant in a few sentences. " +
+		"Keep the facts, decisions and open questions needed to continue it later. Reply with the summary only.\n\n")
+	for _, msg := range history.Messages {
+		if msg.Role == "user" {
+			fmt.Fprintf(&sb, "User: %s\n", msg.Content)
+		} else {
+			fmt.Fprintf(&sb, "Assistant: %s\n", msg.Content)
 		}
 	}
+	return sb.String()
 }
 
 // buildContextFromHistory creates a context string from thread history
@@ -175,7 +364,6 @@ func (s *SlackChannel) buildContextFromHistory(history *ThreadHistory) string {
 	}
 
 	var sb strings.Builder
-	sb.WriteString("Previous conversation in this thread:\n\n")
 
 	// Include last 10 messages (excluding the current one which is the last)
 	start := 0
@@ -183,6 +371,12 @@ func (s *SlackChannel) buildContextFromHistory(history *ThreadHistory) string {
 		start = len(history.Messages) - 11
 	}
 
+	// The summary of an archived thread covers the messages left out
+	if history.Summary != "" && start > 0 {
+		fmt.Fprintf(&sb, "Summary of the earlier conversation in this thread:\n%s\n\n", history.Summary)
+	}
+	sb.WriteString("Previous conversation in this thread:\n\n")
+
 	for i := start; i < len(history.Messages)-1; i++ {
 		msg := history.Messages[i]
 		if msg.Role == "user" {
@@ -254,12 +448,19 @@ func (s *SlackChannel) handleEvents(ctx context.Context) {
 
 			case socketmode.EventTypeConnecting:
 				fmt.Println("[slack] Connecting to Slack...")
+				s.reportHealth(HealthConnecting, "")
 
 			case socketmode.EventTypeConnected:
 				fmt.Println("[slack] Connected to Slack!")
+				s.reportHealth(HealthConnected, "")
 
 			case socketmode.EventTypeConnectionError:
 				fmt.Println("[slack] Connection error!")
+				detail := "connection error"
+				if err, ok := evt.Data.(error); ok {
+					detail = err.Error()
+				}
+				s.reportHealth(HealthError, detail)
 
 			case socketmode.EventTypeHello:
 				fmt.Println("[slack] Received hello from Slack")
@@ -307,6 +508,7 @@ func (s *SlackChannel) handleMention(ev *slackevents.AppMentionEvent) {
 	// Track this thread as active
 	threadKey := fmt.Sprintf("%s:%s", ev.Channel, threadTS)
 	fmt.Printf("[slack] handleMention: creating/updating thread key: %s\n", threadKey)
+	s.restoreThread(threadKey)
 
 	s.mu.Lock()
 	s.currentChannel = ev.Channel
@@ -338,12 +540,12 @@ func (s *SlackChannel) handleMention(ev *slackevents.AppMentionEvent) {
 		Role:      "user",
 		Content:   text,
 		Timestamp: time.Now(),
-		Metadata: map[string]any{
+		Metadata: s.withLocale(map[string]any{
 			"channel":   ev.Channel,
 			"thread_ts": threadTS,
 			"user":      ev.User,
 			"history":   contextMessages,
-		},
+		}, ev.User),
 	}
 }
 
@@ -366,6 +568,7 @@ func (s *SlackChannel) handleMessage(ev *slackevents.MessageEvent) {
 		// Check if this thread is one we're tracking
 		threadKey := fmt.Sprintf("%s:%s", ev.Channel, ev.ThreadTimeStamp)
 		fmt.Printf("[slack] Checking thread key: %s\n", threadKey)
+		s.restoreThread(threadKey)
 
 		s.mu.Lock()
 		history, isTrackedThread := s.activeThreads[threadKey]
@@ -403,13 +606,13 @@ func (s *SlackChannel) handleMessage(ev *slackevents.MessageEvent) {
 			Role:      "user",
 			Content:   text,
 			Timestamp: time.Now(),
-			Metadata: map[string]any{
+			Metadata: s.withLocale(map[string]any{
 				"channel":   ev.Channel,
 				"thread_ts": ev.ThreadTimeStamp,
 				"user":      ev.User,
 				"is_reply":  true,
 				"history":   contextMessages,
-			},
+			}, ev.User),
 		}
 		return
 	}
@@ -417,6 +620,7 @@ func (s *SlackChannel) handleMessage(ev *slackevents.MessageEvent) {
 	// Handle DMs
 	if ev.ChannelType == "im" {
 		threadKey := fmt.Sprintf("%s:dm", ev.Channel)
+		s.restoreThread(threadKey)
 
 		s.mu.Lock()
 		s.currentChannel = ev.Channel
@@ -439,16 +643,20 @@ func (s *SlackChannel) handleMessage(ev *slackevents.MessageEvent) {
 		contextMessages := s.buildContextFromHistory(history)
 		s.mu.Unlock()
 
+		metadata := s.withLocale(map[string]any{
+			"channel": ev.Channel,
+			"user":    ev.User,
+			"history": contextMessages,
+		}, ev.User)
+		if ev.ThreadTimeStamp != "" {
+			metadata["thread_ts"] = ev.ThreadTimeStamp
+		}
 		s.messages <- &Message{
 			ID:        uuid.New().String(),
 			Role:      "user",
 			Content:   text,
 			Timestamp: time.Now(),
-			Metadata: map[string]any{
-				"channel": ev.Channel,
-				"user":    ev.User,
-				"history": contextMessages,
-			},
+			Metadata:  metadata,
 		}
 	}
 }
@@ -466,11 +674,15 @@ func (s *SlackChannel) handleSlashCommand(cmd slack.SlashCommand) {
 	if len(parts) > 0 {
 		switch parts[0] {
 		case "help", "":
-			s.sendHelp(cmd.ChannelID)
+			s.sendHelp(cmd.ChannelID, cmd.UserID)
 			return
 
 		case "agents":
-			s.listAgents(cmd.ChannelID)
+			s.listAgents(cmd.ChannelID, cmd.UserID)
+			return
+
+		case "stop":
+			s.requestStop(cmd.ChannelID, "", cmd.UserID)
 			return
 
 		case "spawn":
@@ -486,14 +698,14 @@ func (s *SlackChannel) handleSlashCommand(cmd slack.SlashCommand) {
 
 		case "delete":
 			if len(parts) > 2 && parts[1] == "agent" {
-				s.deleteAgent(cmd.ChannelID, parts[2])
+				s.deleteAgent(cmd.ChannelID, parts[2], cmd.UserID)
 				return
 			}
 		}
 	}
 
 	if text == "" {
-		s.sendHelp(cmd.ChannelID)
+		s.sendHelp(cmd.ChannelID, cmd.UserID)
 		return
 	}
 
@@ -507,41 +719,44 @@ func (s *SlackChannel) handleSlashCommand(cmd slack.SlashCommand) {
 		Role:      "user",
 		Content:   text,
 		Timestamp: time.Now(),
-		Metadata: map[string]any{
+		Metadata: s.withLocale(map[string]any{
 			"channel": cmd.ChannelID,
 			"user":    cmd.UserID,
-		},
+		}, cmd.UserID),
 	}
 }
 
-func (s *SlackChannel) sendHelp(channelID string) {
+// sendHelp posts the help of /klaw in the language of the user.
+func (s *SlackChannel) sendHelp(channelID, userID string) {
+	lang := s.userLanguage(userID)
 	blocks := []slack.Block{
 		slack.NewHeaderBlock(
-			slack.NewTextBlockObject("plain_text", "🤖 Klaw - AI Employee", true, false),
+			slack.NewTextBlockObject("plain_text", s.text(lang, "slack.help.title", nil), true, false),
 		),
 		slack.NewSectionBlock(
-			slack.NewTextBlockObject("mrkdwn", "*Talk to agents:*\n`/klaw <message>` - Auto-route to best agent\n`/klaw @coder fix this bug` - Direct to specific agent", false, false),
+			slack.NewTextBlockObject("mrkdwn", s.text(lang, "slack.help.talk", nil), false, false),
 			nil, nil,
 		),
 		slack.NewDividerBlock(),
 		slack.NewSectionBlock(
-			slack.NewTextBlockObject("mrkdwn", "*Manage agents:*\n`/klaw spawn` - Create new agent (quick)\n`/klaw agents` - List all agents\n`/klaw delete agent <name>` - Delete agent", false, false),
+			slack.NewTextBlockObject("mrkdwn", s.text(lang, "slack.help.manage", nil), false, false),
 			nil, nil,
 		),
 		slack.NewDividerBlock(),
 		slack.NewActionBlock(
 			"help_actions",
-			slack.NewButtonBlockElement("create_agent_btn", "create_agent", slack.NewTextBlockObject("plain_text", "➕ Spawn Agent", true, false)).WithStyle(slack.StylePrimary),
-			slack.NewButtonBlockElement("list_agents_btn", "list_agents", slack.NewTextBlockObject("plain_text", "📋 List Agents", true, false)),
+			slack.NewButtonBlockElement("create_agent_btn", "create_agent", slack.NewTextBlockObject("plain_text", s.text(lang, "slack.help.spawn_button", nil), true, false)).WithStyle(slack.StylePrimary),
+			slack.NewButtonBlockElement("list_agents_btn", "list_agents", slack.NewTextBlockObject("plain_text", s.text(lang, "slack.help.list_button", nil), true, false)),
 		),
 	}
 
 	_, _, _ = s.client.PostMessage(channelID, slack.MsgOptionBlocks(blocks...))
 }
 
-func (s *SlackChannel) listAgents(channelID string) {
+func (s *SlackChannel) listAgents(channelID, userID string) {
+	lang := s.userLanguage(userID)
 	if s.agentManager == nil {
-		_, _, _ = s.client.PostMessage(channelID, slack.MsgOptionText("❌ Agent management not configured", false))
+		_, _, _ = s.client.PostMessage(channelID, slack.MsgOptionText(s.text(lang, "slack.no_agent_manager", nil), false))
 		return
 	}
 
@@ -554,7 +769,7 @@ func (s *SlackChannel) listAgents(channelID string) {
 	if len(agents) == 0 {
 		blocks := []slack.Block{
 			slack.NewSectionBlock(
-				slack.NewTextBlockObject("mrkdwn", "No agents configured yet.", false, false),
+				slack.NewTextBlockObject("mrkdwn", s.text(lang, "slack.no_agents", nil), false, false),
 				nil, nil,
 			),
 			slack.NewActionBlock(
@@ -750,7 +965,11 @@ func (s *SlackChannel) handleBlockActions(callback slack.InteractionCallback) {
 			s.openCreateAgentModal(callback.TriggerID)
 
 		case "list_agents_btn":
-			s.listAgents(callback.Channel.ID)
+			s.listAgents(callback.Channel.ID, callback.User.ID)
+
+		case "stop_turn_btn":
+			channelID, threadTS, _ := strings.Cut(action.Value, ":")
+			s.requestStop(channelID, threadTS, callback.User.ID)
 
 		default:
 			// Handle overflow menu actions
@@ -765,7 +984,7 @@ func (s *SlackChannel) handleBlockActions(callback slack.InteractionCallback) {
 				}
 			} else if strings.HasPrefix(action.ActionID, "confirm_delete_") {
 				agentName := strings.TrimPrefix(action.ActionID, "confirm_delete_")
-				s.deleteAgent(callback.Channel.ID, agentName)
+				s.deleteAgent(callback.Channel.ID, agentName, callback.User.ID)
 			}
 		}
 	}
@@ -908,9 +1127,9 @@ func (s *SlackChannel) openEditAgentModal(triggerID, agentName string) {
 	_ = agent
 }
 
-func (s *SlackChannel) deleteAgent(channelID, agentName string) {
+func (s *SlackChannel) deleteAgent(channelID, agentName, userID string) {
 	if s.agentManager == nil {
-		_, _, _ = s.client.PostMessage(channelID, slack.MsgOptionText("❌ Agent management not configured", false))
+		_, _, _ = s.client.PostMessage(channelID, slack.MsgOptionText(s.text(s.userLanguage(userID), "slack.no_agent_manager", nil), false))
 		return
 	}
 
@@ -923,20 +1142,46 @@ func (s *SlackChannel) deleteAgent(channelID, agentName string) {
 	_, _, _ = s.client.PostMessage(channelID, slack.MsgOptionText(fmt.Sprintf("✅ Agent *%s* deleted successfully", agentName), false))
 }
 
+// uploadAttachments uploads files into the current thread.
+func (s *SlackChannel) uploadAttachments(ctx context.Context, channel, threadTS string, attachments []Attachment) {
+	for _, att := range attachments {
+		_, err := s.client.UploadFileV2Context(ctx, slack.UploadFileV2Parameters{
+			Reader:          bytes.NewReader(att.Data),
+			FileSize:        len(att.Data),
+			Filename:        att.Name,
+			Title:           att.Name,
+			Channel:         channel,
+			ThreadTimestamp: threadTS,
+		})
+		if err != nil {
+			fmt.Printf("[slack] Failed to upload %s: %v\n", att.Name, err)
+		}
+	}
+}
+
+// Send delivers a message to the Slack channel and thread given by its
+// "channel" and "thread_ts" metadata, or to the most recent conversation
+// when the message carries no route.
 func (s *SlackChannel) Send(ctx context.Context, msg *Message) error {
 	s.mu.Lock()
 	channel := s.currentChannel
 	threadTS := s.currentTS
 	s.mu.Unlock()
+	if c, _ := msg.Metadata["channel"].(string); c != "" {
+		channel = c
+		threadTS, _ = msg.Metadata["thread_ts"].(string)
+	}
+	threadKey := fmt.Sprintf("%s:%s", channel, threadTS)
 
 	if channel == "" {
 		return fmt.Errorf("no channel set")
 	}
 
 	if msg.Role == "error" {
+		lang, _ := msg.Metadata[MetaLocale].(string)
 		blocks := []slack.Block{
 			slack.NewSectionBlock(
-				slack.NewTextBlockObject("mrkdwn", fmt.Sprintf(":x: *Error*\n%s", msg.Content), false, false),
+				slack.NewTextBlockObject("mrkdwn", s.text(lang, "slack.error", map[string]any{"Error": msg.Content}), false, false),
 				nil, nil,
 			),
 		}
@@ -948,10 +1193,27 @@ func (s *SlackChannel) Send(ctx context.Context, msg *Message) error {
 		return nil
 	}
 
+	if msg.Role == "system" {
+		switch msg.Metadata["event"] {
+		case EventTurnStart:
+			s.postWorking(channel, threadTS, threadKey)
+		case EventTurnEnd:
+			s.clearWorking(channel, threadKey)
+		}
+		return nil
+	}
+
 	if msg.Role != "assistant" {
 		return nil
 	}
 
+	if len(msg.Attachments) > 0 {
+		s.uploadAttachments(ctx, channel, threadTS, msg.Attachments)
+		if msg.Content == "" {
+			return nil
+		}
+	}
+
 	content := msg.Content
 
 	// Skip tool output entirely for Slack - don't show raw tool results
@@ -963,7 +1225,12 @@ func (s *SlackChannel) Send(ctx context.Context, msg *Message) error {
 	if msg.IsPartial {
 		// Streaming text - buffer it
 		s.mu.Lock()
-		s.streamBuffer.WriteString(content)
+		buf := s.streamBuffers[threadKey]
+		if buf == nil {
+			buf = &strings.Builder{}
+			s.streamBuffers[threadKey] = buf
+		}
+		buf.WriteString(content)
 		s.mu.Unlock()
 		return nil
 	}
@@ -971,9 +1238,11 @@ func (s *SlackChannel) Send(ctx context.Context, msg *Message) error {
 	if msg.IsDone {
 		// Send accumulated message
 		s.mu.Lock()
-		text := s.streamBuffer.String()
-		s.streamBuffer.Reset()
-		lastTS := s.lastMessageTS
+		var text string
+		if buf := s.streamBuffers[threadKey]; buf != nil {
+			text = buf.String()
+			delete(s.streamBuffers, threadKey)
+		}
 		s.mu.Unlock()
 
 		if text == "" {
@@ -981,42 +1250,18 @@ func (s *SlackChannel) Send(ctx context.Context, msg *Message) error {
 		}
 
 		// Save to thread history
-		threadKey := fmt.Sprintf("%s:%s", channel, threadTS)
 		s.addAssistantResponse(threadKey, text)
 
-		// Build blocks for Slack
-		blocks := s.buildSlackBlocks(text)
-
-		// Update existing message or post new
-		if lastTS != "" {
-			_, _, _, _ = s.client.UpdateMessage(
-				channel,
-				lastTS,
-				slack.MsgOptionBlocks(blocks...),
-			)
-		} else {
-			_, ts, err := s.client.PostMessage(
-				channel,
-				slack.MsgOptionBlocks(blocks...),
-				slack.MsgOptionTS(threadTS),
-			)
-			if err == nil {
-				s.mu.Lock()
-				s.lastMessageTS = ts
-				s.mu.Unlock()
-			}
-		}
-
-		s.mu.Lock()
-		s.lastMessageTS = ""
-		s.mu.Unlock()
-
+		_, _, _ = s.client.PostMessage(
+			channel,
+			slack.MsgOptionBlocks(s.buildSlackBlocks(text)...),
+			slack.MsgOptionTS(threadTS),
+		)
 		return nil
 	}
 
 	// Complete message - use blocks
 	// Save to thread history
-	threadKey := fmt.Sprintf("%s:%s", channel, threadTS)
 	s.addAssistantResponse(threadKey, msg.Content)
 
 	blocks := s.buildSlackBlocks(msg.Content)
@@ -1030,6 +1275,57 @@ func (s *SlackChannel) Send(ctx context.Context, msg *Message) error {
 }
 
 
+// postWorking posts a "working" message with a Stop button to a thread while
+// the agent handles a turn there.
+func (s *SlackChannel) postWorking(channel, threadTS, threadKey string) {
+	blocks := []slack.Block{
+		slack.NewSectionBlock(
+			slack.NewTextBlockObject("mrkdwn", ":hourglass_flowing_sand: _Working on it..._", false, false),
+			nil,
+			slack.NewAccessory(
+				slack.NewButtonBlockElement("stop_turn_btn", threadKey, slack.NewTextBlockObject("plain_text", "⏹ Stop", true, false)).WithStyle(slack.StyleDanger),
+			),
+		),
+	}
+	_, ts, err := s.client.PostMessage(channel, slack.MsgOptionBlocks(blocks...), slack.MsgOptionTS(threadTS))
+	if err != nil {
+		return
+	}
+	s.mu.Lock()
+	s.workingMessages[threadKey] = ts
+	s.mu.Unlock()
+}
+
+// clearWorking removes the "working" message of a thread once its turn ended.
+func (s *SlackChannel) clearWorking(channel, threadKey string) {
+	s.mu.Lock()
+	ts, ok := s.workingMessages[threadKey]
+	delete(s.workingMessages, threadKey)
+	s.mu.Unlock()
+	if ok {
+		_, _, _ = s.client.DeleteMessage(channel, ts)
+	}
+}
+
+// requestStop asks the agent to cancel the running turn of a thread, or of
+// every conversation in the channel when threadTS is empty.
+func (s *SlackChannel) requestStop(channelID, threadTS, userID string) {
+	metadata := s.withLocale(map[string]any{
+		"channel": channelID,
+		"user":    userID,
+		"command": CommandStop,
+	}, userID)
+	if threadTS != "" {
+		metadata["thread_ts"] = threadTS
+	}
+	s.messages <- &Message{
+		ID:        uuid.New().String(),
+		Role:      "user",
+		Timestamp: time.Now(),
+		Metadata:  metadata,
+	}
+}
+
 // buildSlackBlocks creates rich Slack blocks from text content
 func (s *SlackChannel) buildSlackBlocks(text string) []slack.Block {
 	var blocks []slack.Block
@@ -1240,9 +1536,8 @@ func (s *SlackChannel) Receive() <-chan *Message {
 
 func (s *SlackChannel) Stop() error {
 	s.mu.Lock()
-	defer s.mu.Unlock()
-
 	if !s.started {
+		s.mu.Unlock()
 		return nil
 	}
 
@@ -1251,7 +1546,11 @@ func (s *SlackChannel) Stop() error {
 	default:
 		close(s.done)
 	}
+	s.mu.Unlock()
 
+	// Threads still active are archived as they are, to continue after a
+	// restart
+	s.archiveThreads(func(*ThreadHistory) bool { return true }, false)
 	return nil
 }
 
@@ -1330,6 +1629,56 @@ func (s *SlackChannel) GetChannelHistory(channelID string, since time.Time, limi
 	return messages, nil
 }
 
+// maxHistoryPages bounds the pages of channel history read for a cursor.
+const maxHistoryPages = 10
+
+// GetChannelHistoryAfter retrieves up to limit messages of a Slack channel
+// posted strictly after the message with timestamp cursor, oldest first,
+// without bot messages. Messages beyond the limit are left for the next
+// call with the timestamp of the last one returned.
+func (s *SlackChannel) GetChannelHistoryAfter(channelID, cursor string, limit int) ([]ChannelMessage, error) {
+	if limit <= 0 {
+		limit = 50
+	}
+
+	// Slack lists the newest messages first, so the ones right after the
+	// cursor come on the last page
+	params := &slack.GetConversationHistoryParameters{
+		ChannelID: channelID,
+		Oldest:    cursor,
+		Limit:     200,
+	}
+	var messages []ChannelMessage
+	for page := 0; page < maxHistoryPages; page++ {
+		history, err := s.client.GetConversationHistory(params)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get channel history: %w", err)
+		}
+		for _, msg := range history.Messages {
+			if msg.BotID != "" || msg.User == s.botUserID {
+				continue
+			}
+			msgTs, _ := parseSlackTimestamp(msg.Timestamp)
+			messages = append(messages, ChannelMessage{
+				User:      msg.User,
+				Text:      msg.Text,
+				Timestamp: msgTs,
+				SlackTS:   msg.Timestamp,
+			})
+		}
+		if !history.HasMore || history.ResponseMetaData.NextCursor == "" {
+			break
+		}
+		params.Cursor = history.ResponseMetaData.NextCursor
+	}
+
+	slices.Reverse(messages)
+	if len(messages) > limit {
+		messages = messages[:limit]
+	}
+	return messages, nil
+}
+
 // PostMessage posts a message to a Slack channel
 func (s *SlackChannel) PostMessage(channelID, text string) error {
 	_, _, err := s.client.PostMessage(channelID, slack.MsgOptionText(text, false))
@@ -1342,6 +1691,64 @@ func (s *SlackChannel) PostThreadReply(channelID, threadTS, text string) error {
 	return err
 }
 
+// GetRecentMessages returns the latest messages of a channel, or of a
+// thread when threadTS is set, oldest first. Bot messages are included.
+func (s *SlackChannel) GetRecentMessages(channelID, threadTS string, limit int) ([]ChannelMessage, error) {
+	if limit <= 0 {
+		limit = 20
+	}
+
+	var msgs []slack.Message
+	var err error
+	if threadTS != "" {
+		msgs, _, _, err = s.client.GetConversationReplies(&slack.GetConversationRepliesParameters{
+			ChannelID: channelID,
+			Timestamp: threadTS,
+			Limit:     limit,
+		})
+	} else {
+		var history *slack.GetConversationHistoryResponse
+		history, err = s.client.GetConversationHistory(&slack.GetConversationHistoryParameters{
+			ChannelID: channelID,
+			Limit:     limit,
+		})
+		if err == nil {
+			msgs = history.Messages
+			// History is newest first
+			for i, j := 0, len(msgs)-1; i < j; i, j = i+1, j-1 {
+				msgs[i], msgs[j] = msgs[j], msgs[i]
+			}
+		}
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read messages: %w", err)
+	}
+
+	messages := make([]ChannelMessage, 0, len(msgs))
+	for _, msg := range msgs {
+		ts, _ := parseSlackTimestamp(msg.Timestamp)
+		user := msg.User
+		if msg.BotID != "" || msg.User == s.botUserID {
+			user = "bot"
+		}
+		messages = append(messages, ChannelMessage{
+			User:      user,
+			Text:      msg.Text,
+			Timestamp: ts,
+			SlackTS:   msg.Timestamp,
+		})
+	}
+	return messages, nil
+}
+
+// AddReaction adds an emoji reaction to a message.
+func (s *SlackChannel) AddReaction(channelID, messageTS, emoji string) error {
+	return s.client.AddReaction(strings.Trim(emoji, ":"), slack.ItemRef{
+		Channel:   channelID,
+		Timestamp: messageTS,
+	})
+}
+
 // HasBotReply checks if a message already has a reply from the bot
 func (s *SlackChannel) HasBotReply(channelID, messageTS string) bool {
 	// Get thread replies
@@ -11,7 +11,7 @@ import (
 
 // TUIMessage represents a message for the TUI
 type TUIMessage struct {
-	Role    string // "user", "assistant", "tool", "error", "done"
+	Role    string // "user", "assistant", "partial", "tool", "error", "done"
 	Content string
 	Tool    string
 }
@@ -159,10 +159,14 @@ func (t *TUIChannel) Send(ctx context.Context, msg *Message) error {
 	}
 
 	if msg.IsPartial {
-		// Streaming text
+		// Streaming text, shown as it arrives and sent whole when done
 		t.mu.Lock()
 		if !t.inToolCall {
 			t.streamBuffer.WriteString(content)
+			t.tuiOutput <- TUIMessage{
+				Role:    "partial",
+				Content: content,
+			}
 		}
 		t.mu.Unlock()
 		return nil
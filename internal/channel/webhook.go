@@ -0,0 +1,208 @@
+package channel
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// webhookChannel is the part of the webhook-driven channels (GitHub, Jira,
+// alerts) they have in common: the webhook server, the queue of received
+// messages and the buffering of streamed replies. The channel embedding it
+// serves deliveries with handler and posts the agent's answers with reply.
+type webhookChannel struct {
+	name    string
+	listen  string
+	prefix  string // prefix of the Metadata["channel"] of the channel's conversations
+	handler http.Handler
+	setup   func(ctx context.Context) error // run by Start before listening, may be nil
+
+	// reply posts the agent's answer in a conversation. failed is set
+	// when text is the error the agent failed with.
+	reply func(ctx context.Context, channel, threadTS, text string, failed bool) error
+
+	server *http.Server
+
+	messages chan *Message
+	done     chan struct{}
+
+	mu      sync.Mutex
+	started bool
+
+	// Buffers for streaming, per channel:thread_ts
+	streamBuffers map[string]*strings.Builder
+
+	// Connection state changes, see OnHealth
+	onHealth func(state, detail string)
+}
+
+func newWebhookChannel(name, listen, prefix string, handler http.Handler, reply func(ctx context.Context, channel, threadTS, text string, failed bool) error) *webhookChannel {
+	return &webhookChannel{
+		name:          name,
+		listen:        listen,
+		prefix:        prefix,
+		handler:       handler,
+		reply:         reply,
+		messages:      make(chan *Message, 10),
+		done:          make(chan struct{}),
+		streamBuffers: make(map[string]*strings.Builder),
+	}
+}
+
+func (c *webhookChannel) Name() string {
+	return c.name
+}
+
+// OnHealth sets the function told about changes of the webhook server. It
+// must be set before Start.
+func (c *webhookChannel) OnHealth(f func(state, detail string)) {
+	c.onHealth = f
+}
+
+func (c *webhookChannel) reportHealth(state, detail string) {
+	if c.onHealth != nil {
+		c.onHealth(state, detail)
+	}
+}
+
+func (c *webhookChannel) Start(ctx context.Context) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.started {
+		return nil
+	}
+
+	if c.setup != nil {
+		if err := c.setup(ctx); err != nil {
+			return err
+		}
+	}
+
+	ln, err := net.Listen("tcp", c.listen)
+	if err != nil {
+		return fmt.Errorf("failed to listen on %s: %w", c.listen, err)
+	}
+	c.server = &http.Server{Handler: c.handler, ReadHeaderTimeout: 10 * time.Second}
+	c.started = true
+
+	go func() {
+		c.reportHealth(HealthConnected, "")
+		if err := c.server.Serve(ln); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			c.reportHealth(HealthError, err.Error())
+		}
+	}()
+	go func() {
+		select {
+		case <-ctx.Done():
+			_ = c.Stop()
+		case <-c.done:
+		}
+	}()
+	return nil
+}
+
+// deliver queues a message for the agent, unless the channel stops first.
+func (c *webhookChannel) deliver(msg *Message) {
+	select {
+	case c.messages <- msg:
+	case <-c.done:
+	}
+}
+
+func (c *webhookChannel) Send(ctx context.Context, msg *Message) error {
+	channel, _ := msg.Metadata["channel"].(string)
+	threadTS, _ := msg.Metadata["thread_ts"].(string)
+	if !strings.HasPrefix(channel, c.prefix) || threadTS == "" {
+		// Not a conversation of this channel, e.g. a broadcast to every channel
+		return nil
+	}
+	threadKey := channel + ":" + threadTS
+
+	if msg.Role == "error" {
+		return c.reply(ctx, channel, threadTS, msg.Content, true)
+	}
+	if msg.Role != "assistant" {
+		return nil
+	}
+
+	content := msg.Content
+
+	// Skip tool output - only the agent's answer is posted
+	if strings.HasPrefix(content, "\n╭─ ") || strings.HasPrefix(content, "│ ") || strings.HasPrefix(content, "╰─") {
+		return nil
+	}
+
+	if msg.IsPartial {
+		c.mu.Lock()
+		buf := c.streamBuffers[threadKey]
+		if buf == nil {
+			buf = &strings.Builder{}
+			c.streamBuffers[threadKey] = buf
+		}
+		buf.WriteString(content)
+		c.mu.Unlock()
+		return nil
+	}
+
+	if msg.IsDone {
+		c.mu.Lock()
+		if buf := c.streamBuffers[threadKey]; buf != nil {
+			content = buf.String()
+			delete(c.streamBuffers, threadKey)
+		} else {
+			content = ""
+		}
+		c.mu.Unlock()
+	}
+
+	content = strings.TrimSpace(content)
+	if content == "" {
+		return nil
+	}
+	return c.reply(ctx, channel, threadTS, content, false)
+}
+
+func (c *webhookChannel) Receive() <-chan *Message {
+	return c.messages
+}
+
+func (c *webhookChannel) Stop() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if !c.started {
+		return nil
+	}
+
+	select {
+	case <-c.done:
+		return nil
+	default:
+		close(c.done)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	return c.server.Shutdown(ctx)
+}
+
+// readWebhook reads the body of a webhook delivery, answering the request
+// itself when it cannot be read.
+func readWebhook(w http.ResponseWriter, r *http.Request) ([]byte, bool) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return nil, false
+	}
+	body, err := io.ReadAll(io.LimitReader(r.Body, 5<<20))
+	if err != nil {
+		http.Error(w, "failed to read body", http.StatusBadRequest)
+		return nil, false
+	}
+	return body, true
+}
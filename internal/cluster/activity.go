@@ -0,0 +1,150 @@
+package cluster
+
+import (
+	"bufio"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// --- Activity Records ---
+
+// Activity kinds.
+const (
+	ActivityJobRun    = "job_run"    // a cron job ran
+	ActivityTurnError = "turn_error" // a turn ended with an error
+)
+
+// ActivityRecord is a job run or a failed turn, kept for reports such as
+// the daily digest.
+type ActivityRecord struct {
+	Timestamp time.Time `json:"timestamp"`
+	Cluster   string    `json:"cluster"`
+	Namespace string    `json:"namespace"`
+	Kind      string    `json:"kind"`
+	Agent     string    `json:"agent,omitempty"`
+	Job       string    `json:"job,omitempty"`
+	Error     string    `json:"error,omitempty"` // empty for a successful job run
+}
+
+func (s *Store) activityDir(cluster, namespace string) string {
+	return filepath.Join(s.baseDir, "activity", cluster, namespace)
+}
+
+// AppendActivity adds a record to the namespace's activity log. Records
+// are kept one per line in a file per day, with errors redacted.
+func (s *Store) AppendActivity(rec *ActivityRecord) error {
+	dir := s.activityDir(rec.Cluster, rec.Namespace)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+	if rec.Timestamp.IsZero() {
+		rec.Timestamp = time.Now()
+	}
+	if s.redactor != nil {
+		rec.Error, _ = s.redactor.Redact(rec.Error)
+	}
+
+	data, err := json.Marshal(rec)
+	if err != nil {
+		return err
+	}
+	path := filepath.Join(dir, rec.Timestamp.Format("2006-01-02")+".jsonl")
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	_, err = f.Write(append(data, '\n'))
+	return err
+}
+
+// ListActivity returns the namespace's activity records since the given
+// time, oldest first.
+func (s *Store) ListActivity(cluster, namespace string, since time.Time) ([]*ActivityRecord, error) {
+	dir := s.activityDir(cluster, namespace)
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return []*ActivityRecord{}, nil
+		}
+		return nil, err
+	}
+
+	firstDay := since.Format("2006-01-02")
+	records := []*ActivityRecord{}
+	for _, entry := range entries {
+		day := strings.TrimSuffix(entry.Name(), ".jsonl")
+		if entry.IsDir() || day == entry.Name() || day < firstDay {
+			continue
+		}
+		f, err := os.Open(filepath.Join(dir, entry.Name()))
+		if err != nil {
+			continue
+		}
+		scanner := bufio.NewScanner(f)
+		for scanner.Scan() {
+			var rec ActivityRecord
+			if err := json.Unmarshal(scanner.Bytes(), &rec); err != nil {
+				continue // skip a partly written line
+			}
+			if !rec.Timestamp.Before(since) {
+				records = append(records, &rec)
+			}
+		}
+		f.Close()
+	}
+
+	sort.SliceStable(records, func(i, j int) bool { return records[i].Timestamp.Before(records[j].Timestamp) })
+	return records, nil
+}
+
+// ListMessageLogs returns the logged messages of all the namespace's
+// channels since the given time, oldest first.
+func (s *Store) ListMessageLogs(cluster, namespace string, since time.Time) ([]*MessageLog, error) {
+	channels, err := os.ReadDir(filepath.Join(s.baseDir, "logs", cluster, namespace))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return []*MessageLog{}, nil
+		}
+		return nil, err
+	}
+
+	firstDay := since.Format("2006-01-02")
+	all := []*MessageLog{}
+	for _, ch := range channels {
+		if !ch.IsDir() {
+			continue
+		}
+		dir := s.logsDir(cluster, namespace, ch.Name())
+		files, err := os.ReadDir(dir)
+		if err != nil {
+			continue
+		}
+		for _, file := range files {
+			day := strings.TrimSuffix(file.Name(), ".json")
+			if file.IsDir() || day == file.Name() || day < firstDay {
+				continue
+			}
+			data, err := os.ReadFile(filepath.Join(dir, file.Name()))
+			if err != nil {
+				continue
+			}
+			var logs []*MessageLog
+			if err := json.Unmarshal(data, &logs); err != nil {
+				continue
+			}
+			for _, l := range logs {
+				if !l.Timestamp.Before(since) {
+					all = append(all, l)
+				}
+			}
+		}
+	}
+
+	sort.SliceStable(all, func(i, j int) bool { return all[i].Timestamp.Before(all[j].Timestamp) })
+	return all, nil
+}
@@ -0,0 +1,116 @@
+package cluster
+
+import (
+	"bufio"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// --- Tool Audit Trail ---
+
+// ToolAuditRecord is one tool call made by an agent.
+type ToolAuditRecord struct {
+	Timestamp    time.Time `json:"timestamp"`
+	Cluster      string    `json:"cluster"`
+	Namespace    string    `json:"namespace"`
+	Agent        string    `json:"agent,omitempty"`
+	Conversation string    `json:"conversation,omitempty"`
+	Source       string    `json:"source,omitempty"` // "slack", "job", ...
+	Tool         string    `json:"tool"`
+	Input        string    `json:"input"`  // truncated, credentials masked
+	Status       string    `json:"status"` // "ok" or "error"
+	Error        string    `json:"error,omitempty"`
+	DurationMS   int64     `json:"duration_ms"`
+}
+
+// ToolAuditFilter selects tool audit records. Empty fields match all.
+type ToolAuditFilter struct {
+	Since        time.Time
+	Agent        string
+	Tool         string
+	Conversation string
+	ErrorsOnly   bool
+}
+
+func (f ToolAuditFilter) match(rec *ToolAuditRecord) bool {
+	return !rec.Timestamp.Before(f.Since) &&
+		(f.Agent == "" || rec.Agent == f.Agent) &&
+		(f.Tool == "" || rec.Tool == f.Tool) &&
+		(f.Conversation == "" || rec.Conversation == f.Conversation) &&
+		(!f.ErrorsOnly || rec.Status != "ok")
+}
+
+func (s *Store) auditDir(cluster, namespace string) string {
+	return filepath.Join(s.baseDir, "audit", cluster, namespace, "tools")
+}
+
+// AppendToolAudit adds a record to the namespace's tool audit trail. The
+// trail is append-only: records are kept one per line in a file per day
+// and never rewritten.
+func (s *Store) AppendToolAudit(rec *ToolAuditRecord) error {
+	dir := s.auditDir(rec.Cluster, rec.Namespace)
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return err
+	}
+	if rec.Timestamp.IsZero() {
+		rec.Timestamp = time.Now()
+	}
+
+	data, err := json.Marshal(rec)
+	if err != nil {
+		return err
+	}
+	path := filepath.Join(dir, rec.Timestamp.Format("2006-01-02")+".jsonl")
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	_, err = f.Write(append(data, '\n'))
+	return err
+}
+
+// ListToolAudit returns the namespace's tool audit records matching the
+// filter, oldest first.
+func (s *Store) ListToolAudit(cluster, namespace string, filter ToolAuditFilter) ([]*ToolAuditRecord, error) {
+	dir := s.auditDir(cluster, namespace)
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return []*ToolAuditRecord{}, nil
+		}
+		return nil, err
+	}
+
+	firstDay := filter.Since.Format("2006-01-02")
+	records := []*ToolAuditRecord{}
+	for _, entry := range entries {
+		day := strings.TrimSuffix(entry.Name(), ".jsonl")
+		if entry.IsDir() || day == entry.Name() || day < firstDay {
+			continue
+		}
+		f, err := os.Open(filepath.Join(dir, entry.Name()))
+		if err != nil {
+			continue
+		}
+		scanner := bufio.NewScanner(f)
+		scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+		for scanner.Scan() {
+			var rec ToolAuditRecord
+			if err := json.Unmarshal(scanner.Bytes(), &rec); err != nil {
+				continue // skip a partly written line
+			}
+			if filter.match(&rec) {
+				records = append(records, &rec)
+			}
+		}
+		f.Close()
+	}
+
+	sort.SliceStable(records, func(i, j int) bool { return records[i].Timestamp.Before(records[j].Timestamp) })
+	return records, nil
+}
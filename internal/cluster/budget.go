@@ -0,0 +1,84 @@
+package cluster
+
+import "time"
+
+// --- Budgets ---
+
+// BudgetConfig sets spend thresholds for a namespace. Spend is the
+// estimated cost of the namespace's usage records, in USD.
+type BudgetConfig struct {
+	Daily        float64  `json:"daily,omitempty"`         // per calendar day; 0 = none
+	Monthly      float64  `json:"monthly,omitempty"`       // per calendar month; 0 = none
+	AlertChannel string   `json:"alert_channel,omitempty"` // Slack channel alerted when a threshold is crossed
+	PauseJobs    bool     `json:"pause_jobs,omitempty"`    // pause cron jobs while over budget
+	KeepJobs     []string `json:"keep_jobs,omitempty"`     // essential jobs that keep running
+}
+
+// BudgetStatus is a namespace's spend in the current day and month.
+type BudgetStatus struct {
+	Day          string  `json:"day"`   // 2006-01-02
+	Month        string  `json:"month"` // 2006-01
+	DailySpend   float64 `json:"daily_spend"`
+	MonthlySpend float64 `json:"monthly_spend"`
+	DailyOver    bool    `json:"daily_over,omitempty"`
+	MonthlyOver  bool    `json:"monthly_over,omitempty"`
+}
+
+// Over reports whether a threshold has been reached.
+func (s *BudgetStatus) Over() bool {
+	return s.DailyOver || s.MonthlyOver
+}
+
+// KeepsJob reports whether a job keeps running when the budget is exceeded.
+func (b *BudgetConfig) KeepsJob(name string) bool {
+	for _, keep := range b.KeepJobs {
+		if keep == name {
+			return true
+		}
+	}
+	return false
+}
+
+// SetNamespaceBudget stores the budget of a namespace; nil removes it.
+func (s *Store) SetNamespaceBudget(cluster, namespace string, b *BudgetConfig) error {
+	ns, err := s.GetNamespace(cluster, namespace)
+	if err != nil {
+		return err
+	}
+	ns.Budget = b
+	return s.saveNamespace(ns)
+}
+
+// NamespaceBudget returns the budget of a namespace, or nil. Missing
+// namespaces (e.g. the implicit default) have no budget.
+func (s *Store) NamespaceBudget(cluster, namespace string) (*BudgetConfig, error) {
+	ns, err := s.GetNamespace(cluster, namespace)
+	if err != nil || ns.Budget == nil {
+		return nil, nil
+	}
+	return ns.Budget, nil
+}
+
+// BudgetStatus sums the namespace's spend of the day and month of now and
+// compares it with the budget's thresholds.
+func (s *Store) BudgetStatus(cluster, namespace string, b *BudgetConfig, now time.Time) (*BudgetStatus, error) {
+	monthStart := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, now.Location())
+	dayStart := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	records, err := s.ListUsage(cluster, namespace, monthStart)
+	if err != nil {
+		return nil, err
+	}
+
+	st := &BudgetStatus{Day: now.Format("2006-01-02"), Month: now.Format("2006-01")}
+	for _, rec := range records {
+		st.MonthlySpend += rec.Cost
+		if !rec.Timestamp.Before(dayStart) {
+			st.DailySpend += rec.Cost
+		}
+	}
+	if b != nil {
+		st.DailyOver = b.Daily > 0 && st.DailySpend >= b.Daily
+		st.MonthlyOver = b.Monthly > 0 && st.MonthlySpend >= b.Monthly
+	}
+	return st, nil
+}
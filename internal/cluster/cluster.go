@@ -6,7 +6,11 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"sync"
 	"time"
+
+	"github.com/eachlabs/klaw/internal/guardrail"
+	"github.com/eachlabs/klaw/internal/redact"
 )
 
 // Cluster represents a top-level isolation boundary (per company/organization).
@@ -27,6 +31,21 @@ type Namespace struct {
 	CreatedAt    time.Time           `json:"created_at"`
 	Labels       map[string]string   `json:"labels,omitempty"`
 	Orchestrator *OrchestratorConfig `json:"orchestrator,omitempty"`
+	SMTP         *SMTPConfig         `json:"smtp,omitempty"`
+	Budget       *BudgetConfig       `json:"budget,omitempty"`
+	Digest       *DigestConfig       `json:"digest,omitempty"`
+	Guardrails   *guardrail.Policy   `json:"guardrails,omitempty"`
+	Locale       string              `json:"locale,omitempty"` // language of replies, help and errors, e.g. "tr"
+}
+
+// SMTPConfig is the outgoing mail server shared by a namespace's agents.
+type SMTPConfig struct {
+	Host              string   `json:"host"`
+	Port              int      `json:"port,omitempty"` // default 587; 465 = implicit TLS
+	Username          string   `json:"username,omitempty"`
+	Password          string   `json:"password,omitempty"` // encrypted at rest
+	From              string   `json:"from"`
+	AllowedRecipients []string `json:"allowed_recipients,omitempty"` // addresses or @domains; empty = any
 }
 
 // OrchestratorConfig defines how messages are routed in a namespace.
@@ -51,10 +70,29 @@ type AgentBinding struct {
 	Description  string    `json:"description"`
 	SystemPrompt string    `json:"system_prompt,omitempty"`
 	Model        string    `json:"model,omitempty"`
+	Provider     string    `json:"provider,omitempty"` // provider for Model; empty = the one klaw runs with
 	Tools        []string  `json:"tools,omitempty"`
 	Skills       []string  `json:"skills,omitempty"`   // installed skills (web-search, browser, etc.)
 	Triggers     []string  `json:"triggers,omitempty"` // keywords for routing
 	CreatedAt    time.Time `json:"created_at"`
+
+	// SharedMemory adds the namespace's shared memory to the agent's own.
+	SharedMemory bool `json:"shared_memory,omitempty"`
+
+	// SkillConfig holds per-skill settings (API keys, DSNs), encrypted at rest.
+	SkillConfig map[string]map[string]string `json:"skill_config,omitempty"`
+
+	// Policy restricts what the agent's tools may do.
+	Policy *ToolPolicy `json:"policy,omitempty"`
+}
+
+// ToolPolicy limits an agent's bash commands, writable paths and
+// network destinations.
+type ToolPolicy struct {
+	AllowCommands  []string `json:"allow_commands,omitempty"`  // regexes bash commands must match
+	DenyCommands   []string `json:"deny_commands,omitempty"`   // regexes that block bash commands
+	WritablePaths  []string `json:"writable_paths,omitempty"`  // path prefixes for write/edit
+	AllowedDomains []string `json:"allowed_domains,omitempty"` // hosts for HTTP/fetch/browser tools
 }
 
 // ChannelBinding connects a channel to a namespace.
@@ -65,12 +103,23 @@ type ChannelBinding struct {
 	Namespace string            `json:"namespace"`
 	Config    map[string]string `json:"config"` // tokens, settings
 	CreatedAt time.Time         `json:"created_at"`
-	Status    string            `json:"status"` // active, inactive
+	Status    string            `json:"status"`           // active, inactive
+	Health    *ChannelHealth    `json:"health,omitempty"` // reported by the klaw start running it
+}
+
+// ChannelHealth is the runtime state of a channel binding's connection.
+type ChannelHealth struct {
+	State     string    `json:"state"` // connecting, connected, error, stopped
+	Error     string    `json:"error,omitempty"`
+	UpdatedAt time.Time `json:"updated_at"`
 }
 
 // Store manages cluster, namespace, and channel binding persistence.
 type Store struct {
-	baseDir string
+	baseDir  string
+	redactor *redact.Redactor
+
+	logMu sync.Mutex // serializes rewrites of the message log files
 }
 
 // NewStore creates a new cluster store.
@@ -78,6 +127,12 @@ func NewStore(baseDir string) *Store {
 	return &Store{baseDir: baseDir}
 }
 
+// SetRedactor redacts the content and response of message logs before
+// they are written.
+func (s *Store) SetRedactor(r *redact.Redactor) {
+	s.redactor = r
+}
+
 // --- Cluster Operations ---
 
 func (s *Store) clustersDir() string {
@@ -396,6 +451,18 @@ func (s *Store) DeleteChannelBinding(cluster, namespace, name string) error {
 	return os.Remove(s.channelBindingFile(cluster, namespace, name))
 }
 
+// RestoreChannelBinding saves a deleted channel binding back as it was,
+// keeping its creation time and status.
+func (s *Store) RestoreChannelBinding(cb *ChannelBinding) error {
+	if _, err := os.Stat(s.channelBindingFile(cb.Cluster, cb.Namespace, cb.Name)); err == nil {
+		return fmt.Errorf("channel already exists: %s/%s/%s", cb.Cluster, cb.Namespace, cb.Name)
+	}
+	if err := os.MkdirAll(s.channelBindingsDir(cb.Cluster, cb.Namespace), 0755); err != nil {
+		return err
+	}
+	return s.saveChannelBinding(cb)
+}
+
 func (s *Store) UpdateChannelBindingStatus(cluster, namespace, name, status string) error {
 	cb, err := s.GetChannelBinding(cluster, namespace, name)
 	if err != nil {
@@ -405,6 +472,17 @@ func (s *Store) UpdateChannelBindingStatus(cluster, namespace, name, status stri
 	return s.saveChannelBinding(cb)
 }
 
+// UpdateChannelBindingHealth records the runtime state of a channel
+// binding, leaving its status as it is.
+func (s *Store) UpdateChannelBindingHealth(cluster, namespace, name, state, detail string) error {
+	cb, err := s.GetChannelBinding(cluster, namespace, name)
+	if err != nil {
+		return err
+	}
+	cb.Health = &ChannelHealth{State: state, Error: detail, UpdatedAt: time.Now()}
+	return s.saveChannelBinding(cb)
+}
+
 // --- Agent Binding Operations ---
 
 func (s *Store) agentBindingsDir(cluster, namespace string) string {
@@ -415,7 +493,9 @@ func (s *Store) agentBindingFile(cluster, namespace, name string) string {
 	return filepath.Join(s.agentBindingsDir(cluster, namespace), name+".json")
 }
 
-func (s *Store) CreateAgentBinding(ab *AgentBinding) error {
+// ValidateAgentBinding checks that an agent binding can be created: it is
+// named and its namespace exists.
+func (s *Store) ValidateAgentBinding(ab *AgentBinding) error {
 	if ab.Name == "" || ab.Cluster == "" || ab.Namespace == "" {
 		return fmt.Errorf("agent name, cluster, and namespace required")
 	}
@@ -427,6 +507,13 @@ func (s *Store) CreateAgentBinding(ab *AgentBinding) error {
 	if !s.NamespaceExists(ab.Cluster, ab.Namespace) {
 		return fmt.Errorf("namespace not found: %s/%s", ab.Cluster, ab.Namespace)
 	}
+	return nil
+}
+
+func (s *Store) CreateAgentBinding(ab *AgentBinding) error {
+	if err := s.ValidateAgentBinding(ab); err != nil {
+		return err
+	}
 
 	ab.CreatedAt = time.Now()
 
@@ -495,6 +582,18 @@ func (s *Store) DeleteAgentBinding(cluster, namespace, name string) error {
 	return os.Remove(s.agentBindingFile(cluster, namespace, name))
 }
 
+// RestoreAgentBinding saves a deleted agent binding back as it was,
+// keeping its creation time.
+func (s *Store) RestoreAgentBinding(ab *AgentBinding) error {
+	if s.AgentBindingExists(ab.Cluster, ab.Namespace, ab.Name) {
+		return fmt.Errorf("agent already exists: %s/%s/%s", ab.Cluster, ab.Namespace, ab.Name)
+	}
+	if err := os.MkdirAll(s.agentBindingsDir(ab.Cluster, ab.Namespace), 0755); err != nil {
+		return err
+	}
+	return s.saveAgentBinding(ab)
+}
+
 func (s *Store) UpdateAgentBinding(ab *AgentBinding) error {
 	if ab.Name == "" || ab.Cluster == "" || ab.Namespace == "" {
 		return fmt.Errorf("agent name, cluster, and namespace required")
@@ -537,6 +636,15 @@ type MessageLog struct {
 	Content   string    `json:"content"`
 	Response  string    `json:"response,omitempty"`
 	RoutedVia string    `json:"routed_via"` // "manual", "keyword", "ai"
+
+	// Redactions counts the matches masked in Content and Response, by
+	// detector.
+	Redactions map[string]int `json:"redactions,omitempty"`
+
+	// Intent and Sentiment tag the message for klaw logs stats, once the
+	// analytics tagger has classified it; see TagMessageLogs.
+	Intent    string `json:"intent,omitempty"`
+	Sentiment string `json:"sentiment,omitempty"`
 }
 
 func (s *Store) logsDir(cluster, namespace, channel string) string {
@@ -557,6 +665,15 @@ func (s *Store) AppendMessageLog(cluster, namespace, channel string, msg *Messag
 	}
 
 	msg.Timestamp = time.Now()
+	if s.redactor != nil {
+		var content, response map[string]int
+		msg.Content, content = s.redactor.Redact(msg.Content)
+		msg.Response, response = s.redactor.Redact(msg.Response)
+		msg.Redactions = mergeCounts(content, response)
+	}
+
+	s.logMu.Lock()
+	defer s.logMu.Unlock()
 
 	// Read existing logs
 	logPath := s.logFile(cluster, namespace, channel)
@@ -582,6 +699,19 @@ func (s *Store) AppendMessageLog(cluster, namespace, channel string, msg *Messag
 	return os.WriteFile(logPath, data, 0644)
 }
 
+func mergeCounts(a, b map[string]int) map[string]int {
+	if len(b) == 0 {
+		return a
+	}
+	if a == nil {
+		a = make(map[string]int, len(b))
+	}
+	for name, n := range b {
+		a[name] += n
+	}
+	return a
+}
+
 // GetMessageLogs retrieves recent messages for a channel.
 func (s *Store) GetMessageLogs(cluster, namespace, channel string, limit int) ([]*MessageLog, error) {
 	dir := s.logsDir(cluster, namespace, channel)
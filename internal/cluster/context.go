@@ -16,6 +16,10 @@ type Context struct {
 // ContextManager manages the active cluster/namespace context.
 type ContextManager struct {
 	configDir string
+
+	// Per-invocation overrides of the saved context (--cluster, --namespace)
+	cluster   string
+	namespace string
 }
 
 // NewContextManager creates a context manager.
@@ -81,6 +85,15 @@ func (m *ContextManager) SetNamespace(namespace string) error {
 	return m.Set(ctx)
 }
 
+// Override makes GetCurrent and RequireCurrent return the given cluster
+// and namespace instead of the saved ones, without changing the saved
+// context. Empty values keep the saved selection. Overriding the cluster
+// alone selects its default namespace.
+func (m *ContextManager) Override(cluster, namespace string) {
+	m.cluster = cluster
+	m.namespace = namespace
+}
+
 // GetCurrent returns cluster and namespace, with defaults.
 func (m *ContextManager) GetCurrent() (cluster, namespace string, err error) {
 	ctx, err := m.Get()
@@ -90,6 +103,13 @@ func (m *ContextManager) GetCurrent() (cluster, namespace string, err error) {
 
 	cluster = ctx.CurrentCluster
 	namespace = ctx.CurrentNamespace
+	if m.cluster != "" && m.cluster != cluster {
+		cluster = m.cluster
+		namespace = ""
+	}
+	if m.namespace != "" {
+		namespace = m.namespace
+	}
 
 	if namespace == "" {
 		namespace = "default"
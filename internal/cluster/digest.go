@@ -0,0 +1,161 @@
+package cluster
+
+import (
+	"fmt"
+	"sort"
+	"time"
+)
+
+// --- Daily Digest ---
+
+// DigestConfig schedules a namespace's daily activity digest.
+type DigestConfig struct {
+	Channel  string `json:"channel"`             // Slack channel the digest is posted to
+	At       string `json:"at"`                  // local time of day, 15:04
+	LastSent string `json:"last_sent,omitempty"` // day of the last digest, 2006-01-02
+}
+
+// DigestTime parses a time of day of a digest.
+func DigestTime(at string) (time.Time, error) {
+	t, err := time.Parse("15:04", at)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid time %q (use HH:MM, e.g. 09:00)", at)
+	}
+	return t, nil
+}
+
+// Due reports whether the digest of the day of now is due and not sent.
+func (d *DigestConfig) Due(now time.Time) bool {
+	at, err := DigestTime(d.At)
+	if err != nil {
+		return false
+	}
+	if d.LastSent == now.Format("2006-01-02") {
+		return false
+	}
+	sendAt := time.Date(now.Year(), now.Month(), now.Day(), at.Hour(), at.Minute(), 0, 0, now.Location())
+	return !now.Before(sendAt)
+}
+
+// SetNamespaceDigest stores the digest of a namespace; nil removes it.
+func (s *Store) SetNamespaceDigest(cluster, namespace string, d *DigestConfig) error {
+	ns, err := s.GetNamespace(cluster, namespace)
+	if err != nil {
+		return err
+	}
+	ns.Digest = d
+	return s.saveNamespace(ns)
+}
+
+// NamespaceDigest returns the digest of a namespace, or nil.
+func (s *Store) NamespaceDigest(cluster, namespace string) (*DigestConfig, error) {
+	ns, err := s.GetNamespace(cluster, namespace)
+	if err != nil || ns.Digest == nil {
+		return nil, nil
+	}
+	return ns.Digest, nil
+}
+
+// Digest is a namespace's activity over a period.
+type Digest struct {
+	Cluster   string         `json:"cluster"`
+	Namespace string         `json:"namespace"`
+	Since     time.Time      `json:"since"`
+	Until     time.Time      `json:"until"`
+	Agents    []*DigestAgent `json:"agents"` // by messages handled, most first
+	JobRuns   int            `json:"job_runs"`
+	JobsFail  int            `json:"jobs_failed"`
+	Errors    int            `json:"errors"` // failed turns
+	Cost      float64        `json:"cost"`
+	Tokens    int            `json:"tokens"`
+
+	// FailedJobs are the names of the jobs that failed, with their last
+	// error.
+	FailedJobs map[string]string `json:"failed_jobs,omitempty"`
+}
+
+// DigestAgent is the activity of one agent in a digest.
+type DigestAgent struct {
+	Name     string  `json:"name"`
+	Messages int     `json:"messages"`
+	JobRuns  int     `json:"job_runs"`
+	Errors   int     `json:"errors"`
+	Cost     float64 `json:"cost"`
+}
+
+// BuildDigest sums up the namespace's message logs, activity and usage
+// records from since until until.
+func (s *Store) BuildDigest(cluster, namespace string, since, until time.Time) (*Digest, error) {
+	d := &Digest{Cluster: cluster, Namespace: namespace, Since: since, Until: until}
+	agents := make(map[string]*DigestAgent)
+	agent := func(name string) *DigestAgent {
+		if name == "" {
+			name = "klaw"
+		}
+		a, ok := agents[name]
+		if !ok {
+			a = &DigestAgent{Name: name}
+			agents[name] = a
+		}
+		return a
+	}
+	inPeriod := func(t time.Time) bool { return !t.Before(since) && t.Before(until) }
+
+	logs, err := s.ListMessageLogs(cluster, namespace, since)
+	if err != nil {
+		return nil, err
+	}
+	for _, l := range logs {
+		if inPeriod(l.Timestamp) {
+			agent(l.Agent).Messages++
+		}
+	}
+
+	activity, err := s.ListActivity(cluster, namespace, since)
+	if err != nil {
+		return nil, err
+	}
+	for _, rec := range activity {
+		if !inPeriod(rec.Timestamp) {
+			continue
+		}
+		switch rec.Kind {
+		case ActivityJobRun:
+			d.JobRuns++
+			agent(rec.Agent).JobRuns++
+			if rec.Error != "" {
+				d.JobsFail++
+				if d.FailedJobs == nil {
+					d.FailedJobs = make(map[string]string)
+				}
+				d.FailedJobs[rec.Job] = rec.Error
+			}
+		case ActivityTurnError:
+			d.Errors++
+			agent(rec.Agent).Errors++
+		}
+	}
+
+	usage, err := s.ListUsage(cluster, namespace, since)
+	if err != nil {
+		return nil, err
+	}
+	for _, rec := range usage {
+		if inPeriod(rec.Timestamp) {
+			d.Cost += rec.Cost
+			d.Tokens += rec.InputTokens + rec.OutputTokens
+			agent(rec.Agent).Cost += rec.Cost
+		}
+	}
+
+	for _, a := range agents {
+		d.Agents = append(d.Agents, a)
+	}
+	sort.Slice(d.Agents, func(i, j int) bool {
+		if d.Agents[i].Messages != d.Agents[j].Messages {
+			return d.Agents[i].Messages > d.Agents[j].Messages
+		}
+		return d.Agents[i].Name < d.Agents[j].Name
+	})
+	return d, nil
+}
@@ -0,0 +1,28 @@
+package cluster
+
+import "github.com/eachlabs/klaw/internal/guardrail"
+
+// --- Guardrails ---
+
+// SetNamespaceGuardrails stores the guardrails of a namespace; nil removes them.
+func (s *Store) SetNamespaceGuardrails(cluster, namespace string, p *guardrail.Policy) error {
+	ns, err := s.GetNamespace(cluster, namespace)
+	if err != nil {
+		return err
+	}
+	if p.Empty() {
+		p = nil
+	}
+	ns.Guardrails = p
+	return s.saveNamespace(ns)
+}
+
+// NamespaceGuardrails returns the guardrails of a namespace, or nil. Missing
+// namespaces (e.g. the implicit default) have none.
+func (s *Store) NamespaceGuardrails(cluster, namespace string) (*guardrail.Policy, error) {
+	ns, err := s.GetNamespace(cluster, namespace)
+	if err != nil || ns.Guardrails == nil {
+		return nil, nil
+	}
+	return ns.Guardrails, nil
+}
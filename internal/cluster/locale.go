@@ -0,0 +1,23 @@
+package cluster
+
+// --- Locale ---
+
+// SetNamespaceLocale stores the language of a namespace; "" removes it.
+func (s *Store) SetNamespaceLocale(cluster, namespace, lang string) error {
+	ns, err := s.GetNamespace(cluster, namespace)
+	if err != nil {
+		return err
+	}
+	ns.Locale = lang
+	return s.saveNamespace(ns)
+}
+
+// NamespaceLocale returns the language of a namespace, or "" when none is
+// set. Missing namespaces (e.g. the implicit default) have none.
+func (s *Store) NamespaceLocale(cluster, namespace string) string {
+	ns, err := s.GetNamespace(cluster, namespace)
+	if err != nil {
+		return ""
+	}
+	return ns.Locale
+}
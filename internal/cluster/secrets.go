@@ -0,0 +1,240 @@
+package cluster
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// encPrefix marks values stored encrypted on disk.
+const encPrefix = "enc:"
+
+// --- Agent Skill Config Operations ---
+
+func (s *Store) secretKeyFile() string {
+	return filepath.Join(s.baseDir, "secret.key")
+}
+
+// secretKey loads the store encryption key, creating it on first use.
+func (s *Store) secretKey() ([]byte, error) {
+	data, err := os.ReadFile(s.secretKeyFile())
+	if err == nil {
+		key, err := base64.StdEncoding.DecodeString(strings.TrimSpace(string(data)))
+		if err != nil || len(key) != 32 {
+			return nil, fmt.Errorf("invalid secret key: %s", s.secretKeyFile())
+		}
+		return key, nil
+	}
+	if !os.IsNotExist(err) {
+		return nil, err
+	}
+
+	key := make([]byte, 32)
+	if _, err := rand.Read(key); err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(s.baseDir, 0755); err != nil {
+		return nil, err
+	}
+	if err := os.WriteFile(s.secretKeyFile(), []byte(base64.StdEncoding.EncodeToString(key)), 0600); err != nil {
+		return nil, err
+	}
+	return key, nil
+}
+
+func (s *Store) encryptValue(plain string) (string, error) {
+	key, err := s.secretKey()
+	if err != nil {
+		return "", err
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return "", err
+	}
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return "", err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", err
+	}
+	sealed := gcm.Seal(nonce, nonce, []byte(plain), nil)
+	return encPrefix + base64.StdEncoding.EncodeToString(sealed), nil
+}
+
+func (s *Store) decryptValue(value string) (string, error) {
+	if !strings.HasPrefix(value, encPrefix) {
+		return value, nil // stored in plain text (hand-edited)
+	}
+	sealed, err := base64.StdEncoding.DecodeString(strings.TrimPrefix(value, encPrefix))
+	if err != nil {
+		return "", err
+	}
+	key, err := s.secretKey()
+	if err != nil {
+		return "", err
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return "", err
+	}
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return "", err
+	}
+	if len(sealed) < gcm.NonceSize() {
+		return "", fmt.Errorf("ciphertext too short")
+	}
+	plain, err := gcm.Open(nil, sealed[:gcm.NonceSize()], sealed[gcm.NonceSize():], nil)
+	if err != nil {
+		return "", err
+	}
+	return string(plain), nil
+}
+
+// SetAgentSkillConfig stores an encrypted skill config value on an agent.
+func (s *Store) SetAgentSkillConfig(cluster, namespace, name, skill, key, value string) error {
+	if skill == "" || key == "" {
+		return fmt.Errorf("skill and key required")
+	}
+
+	ab, err := s.GetAgentBinding(cluster, namespace, name)
+	if err != nil {
+		return err
+	}
+
+	enc, err := s.encryptValue(value)
+	if err != nil {
+		return fmt.Errorf("failed to encrypt value: %w", err)
+	}
+
+	if ab.SkillConfig == nil {
+		ab.SkillConfig = make(map[string]map[string]string)
+	}
+	if ab.SkillConfig[skill] == nil {
+		ab.SkillConfig[skill] = make(map[string]string)
+	}
+	ab.SkillConfig[skill][key] = enc
+
+	return s.saveAgentBinding(ab)
+}
+
+// UnsetAgentSkillConfig removes a skill config value from an agent.
+func (s *Store) UnsetAgentSkillConfig(cluster, namespace, name, skill, key string) error {
+	ab, err := s.GetAgentBinding(cluster, namespace, name)
+	if err != nil {
+		return err
+	}
+
+	values, ok := ab.SkillConfig[skill]
+	if !ok {
+		return fmt.Errorf("no config for skill: %s", skill)
+	}
+	if _, ok := values[key]; !ok {
+		return fmt.Errorf("config key not set: %s.%s", skill, key)
+	}
+	delete(values, key)
+	if len(values) == 0 {
+		delete(ab.SkillConfig, skill)
+	}
+
+	return s.saveAgentBinding(ab)
+}
+
+// AgentSkillConfig returns the decrypted skill config of an agent, layered
+// over the defaults of its namespace.
+func (s *Store) AgentSkillConfig(ab *AgentBinding) (map[string]map[string]string, error) {
+	result, err := s.NamespaceSkillConfig(ab.Cluster, ab.Namespace)
+	if err != nil {
+		return nil, err
+	}
+	for skill, values := range ab.SkillConfig {
+		if result[skill] == nil {
+			result[skill] = make(map[string]string, len(values))
+		}
+		for key, value := range values {
+			plain, err := s.decryptValue(value)
+			if err != nil {
+				return nil, fmt.Errorf("failed to decrypt %s.%s: %w", skill, key, err)
+			}
+			result[skill][key] = plain
+		}
+	}
+	return result, nil
+}
+
+// NamespaceSkillConfig returns skill config defaults derived from namespace
+// settings (currently the SMTP server, exposed as the "email" skill).
+func (s *Store) NamespaceSkillConfig(cluster, namespace string) (map[string]map[string]string, error) {
+	result := make(map[string]map[string]string)
+
+	smtp, err := s.NamespaceSMTP(cluster, namespace)
+	if err != nil || smtp == nil {
+		return result, err
+	}
+
+	email := map[string]string{
+		"host": smtp.Host,
+		"from": smtp.From,
+	}
+	if smtp.Port != 0 {
+		email["port"] = fmt.Sprintf("%d", smtp.Port)
+	}
+	if smtp.Username != "" {
+		email["username"] = smtp.Username
+		email["password"] = smtp.Password
+	}
+	if len(smtp.AllowedRecipients) > 0 {
+		email["allowed_recipients"] = strings.Join(smtp.AllowedRecipients, ",")
+	}
+	result["email"] = email
+	return result, nil
+}
+
+// SetNamespaceSMTP stores the SMTP config of a namespace, encrypting the password.
+func (s *Store) SetNamespaceSMTP(cluster, namespace string, cfg *SMTPConfig) error {
+	ns, err := s.GetNamespace(cluster, namespace)
+	if err != nil {
+		return err
+	}
+
+	if cfg != nil {
+		stored := *cfg
+		if stored.Password != "" {
+			enc, err := s.encryptValue(stored.Password)
+			if err != nil {
+				return fmt.Errorf("failed to encrypt password: %w", err)
+			}
+			stored.Password = enc
+		}
+		cfg = &stored
+	}
+	ns.SMTP = cfg
+
+	return s.saveNamespace(ns)
+}
+
+// NamespaceSMTP returns the decrypted SMTP config of a namespace, or nil.
+// Missing namespaces (e.g. the implicit default) have no SMTP config.
+func (s *Store) NamespaceSMTP(cluster, namespace string) (*SMTPConfig, error) {
+	ns, err := s.GetNamespace(cluster, namespace)
+	if err != nil || ns.SMTP == nil {
+		return nil, nil
+	}
+
+	cfg := *ns.SMTP
+	if cfg.Password != "" {
+		plain, err := s.decryptValue(cfg.Password)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decrypt SMTP password: %w", err)
+		}
+		cfg.Password = plain
+	}
+	return &cfg, nil
+}
@@ -0,0 +1,115 @@
+package cluster
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// --- Message Tags ---
+
+// MessageTag is the intent and sentiment of a logged message, identified by
+// its ID and timestamp.
+type MessageTag struct {
+	ID        string
+	Timestamp time.Time
+	Intent    string
+	Sentiment string
+}
+
+// UntaggedMessageLogs returns the logged messages since the given time
+// that have no intent yet, by log channel, oldest first.
+func (s *Store) UntaggedMessageLogs(cluster, namespace string, since time.Time) (map[string][]*MessageLog, error) {
+	channels, err := os.ReadDir(filepath.Join(s.baseDir, "logs", cluster, namespace))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return map[string][]*MessageLog{}, nil
+		}
+		return nil, err
+	}
+
+	firstDay := since.Format("2006-01-02")
+	untagged := make(map[string][]*MessageLog)
+	for _, ch := range channels {
+		if !ch.IsDir() {
+			continue
+		}
+		dir := s.logsDir(cluster, namespace, ch.Name())
+		files, err := os.ReadDir(dir)
+		if err != nil {
+			continue
+		}
+		for _, file := range files {
+			day := strings.TrimSuffix(file.Name(), ".json")
+			if file.IsDir() || day == file.Name() || day < firstDay {
+				continue
+			}
+			data, err := os.ReadFile(filepath.Join(dir, file.Name()))
+			if err != nil {
+				continue
+			}
+			var logs []*MessageLog
+			if err := json.Unmarshal(data, &logs); err != nil {
+				continue
+			}
+			for _, l := range logs {
+				if l.Intent == "" && !l.Timestamp.Before(since) {
+					untagged[ch.Name()] = append(untagged[ch.Name()], l)
+				}
+			}
+		}
+	}
+	return untagged, nil
+}
+
+// TagMessageLogs sets the intent and sentiment of messages of a log
+// channel. Tags of messages no longer in the log are ignored.
+func (s *Store) TagMessageLogs(cluster, namespace, channel string, tags []MessageTag) error {
+	byDay := make(map[string][]MessageTag)
+	for _, t := range tags {
+		day := t.Timestamp.Format("2006-01-02")
+		byDay[day] = append(byDay[day], t)
+	}
+
+	s.logMu.Lock()
+	defer s.logMu.Unlock()
+
+	for day, dayTags := range byDay {
+		path := filepath.Join(s.logsDir(cluster, namespace, channel), day+".json")
+		data, err := os.ReadFile(path)
+		if err != nil {
+			if os.IsNotExist(err) {
+				continue
+			}
+			return err
+		}
+		var logs []*MessageLog
+		if err := json.Unmarshal(data, &logs); err != nil {
+			return err
+		}
+
+		changed := false
+		for _, l := range logs {
+			for _, t := range dayTags {
+				if l.ID == t.ID && l.Timestamp.Equal(t.Timestamp) {
+					l.Intent, l.Sentiment = t.Intent, t.Sentiment
+					changed = true
+				}
+			}
+		}
+		if !changed {
+			continue
+		}
+
+		data, err = json.MarshalIndent(logs, "", "  ")
+		if err != nil {
+			return err
+		}
+		if err := os.WriteFile(path, data, 0644); err != nil {
+			return err
+		}
+	}
+	return nil
+}
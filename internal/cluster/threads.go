@@ -0,0 +1,70 @@
+package cluster
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// --- Archived Threads ---
+
+// ArchivedThread is the history of a Slack thread (or DM) that went idle,
+// kept so that a reply days later continues the conversation.
+type ArchivedThread struct {
+	Cluster    string            `json:"cluster"`
+	Namespace  string            `json:"namespace"`
+	Channel    string            `json:"channel"` // name of the channel that served the thread
+	Key        string            `json:"key"`     // <slack channel>:<thread ts>, or <slack channel>:dm
+	Summary    string            `json:"summary,omitempty"`
+	Messages   []ArchivedMessage `json:"messages"`
+	LastActive time.Time         `json:"last_active"`
+	ArchivedAt time.Time         `json:"archived_at"`
+}
+
+// ArchivedMessage is a message of an archived thread.
+type ArchivedMessage struct {
+	Role    string `json:"role"` // user or assistant
+	Content string `json:"content"`
+	User    string `json:"user,omitempty"`
+}
+
+// threadFileName makes a channel name or thread key a file name.
+var threadFileName = strings.NewReplacer(":", "_", "/", "_", `\`, "_")
+
+func (s *Store) threadFile(cluster, namespace, channel, key string) string {
+	return filepath.Join(s.baseDir, "threads", cluster, namespace, threadFileName.Replace(channel), threadFileName.Replace(key)+".json")
+}
+
+// ArchiveThread stores an idle thread, replacing an earlier archive of it.
+func (s *Store) ArchiveThread(t *ArchivedThread) error {
+	path := s.threadFile(t.Cluster, t.Namespace, t.Channel, t.Key)
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return err
+	}
+	if t.ArchivedAt.IsZero() {
+		t.ArchivedAt = time.Now()
+	}
+	data, err := json.MarshalIndent(t, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0600)
+}
+
+// GetArchivedThread returns the archive of a thread, or nil if it has none.
+func (s *Store) GetArchivedThread(cluster, namespace, channel, key string) (*ArchivedThread, error) {
+	data, err := os.ReadFile(s.threadFile(cluster, namespace, channel, key))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var t ArchivedThread
+	if err := json.Unmarshal(data, &t); err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
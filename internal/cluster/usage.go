@@ -0,0 +1,136 @@
+package cluster
+
+import (
+	"bufio"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// --- Usage Records ---
+
+// UsageRecord is the token usage and estimated cost of one provider request.
+type UsageRecord struct {
+	Timestamp    time.Time `json:"timestamp"`
+	Cluster      string    `json:"cluster"`
+	Namespace    string    `json:"namespace"`
+	Agent        string    `json:"agent,omitempty"`
+	Model        string    `json:"model,omitempty"`
+	Source       string    `json:"source,omitempty"` // "slack", "job", ...
+	InputTokens  int       `json:"input_tokens"`
+	OutputTokens int       `json:"output_tokens"`
+	Cost         float64   `json:"cost"`
+}
+
+func (s *Store) usageDir(cluster, namespace string) string {
+	return filepath.Join(s.baseDir, "usage", cluster, namespace)
+}
+
+// AppendUsage adds a usage record to the namespace's usage log. Records are
+// kept one per line in a file per day.
+func (s *Store) AppendUsage(rec *UsageRecord) error {
+	dir := s.usageDir(rec.Cluster, rec.Namespace)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+	if rec.Timestamp.IsZero() {
+		rec.Timestamp = time.Now()
+	}
+
+	data, err := json.Marshal(rec)
+	if err != nil {
+		return err
+	}
+	path := filepath.Join(dir, rec.Timestamp.Format("2006-01-02")+".jsonl")
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	_, err = f.Write(append(data, '\n'))
+	return err
+}
+
+// ListUsage returns the namespace's usage records since the given time,
+// oldest first.
+func (s *Store) ListUsage(cluster, namespace string, since time.Time) ([]*UsageRecord, error) {
+	dir := s.usageDir(cluster, namespace)
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return []*UsageRecord{}, nil
+		}
+		return nil, err
+	}
+
+	firstDay := since.Format("2006-01-02")
+	records := []*UsageRecord{}
+	for _, entry := range entries {
+		day := strings.TrimSuffix(entry.Name(), ".jsonl")
+		if entry.IsDir() || day == entry.Name() || day < firstDay {
+			continue
+		}
+		f, err := os.Open(filepath.Join(dir, entry.Name()))
+		if err != nil {
+			continue
+		}
+		scanner := bufio.NewScanner(f)
+		for scanner.Scan() {
+			var rec UsageRecord
+			if err := json.Unmarshal(scanner.Bytes(), &rec); err != nil {
+				continue // skip a partly written line
+			}
+			if !rec.Timestamp.Before(since) {
+				records = append(records, &rec)
+			}
+		}
+		f.Close()
+	}
+
+	sort.SliceStable(records, func(i, j int) bool { return records[i].Timestamp.Before(records[j].Timestamp) })
+	return records, nil
+}
+
+// CountMessagesByDay returns the number of logged messages per day
+// (YYYY-MM-DD) across the namespace's channels, since the given day.
+func (s *Store) CountMessagesByDay(cluster, namespace string, since time.Time) (map[string]int, error) {
+	counts := make(map[string]int)
+	channels, err := os.ReadDir(filepath.Join(s.baseDir, "logs", cluster, namespace))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return counts, nil
+		}
+		return nil, err
+	}
+
+	firstDay := since.Format("2006-01-02")
+	for _, ch := range channels {
+		if !ch.IsDir() {
+			continue
+		}
+		dir := s.logsDir(cluster, namespace, ch.Name())
+		files, err := os.ReadDir(dir)
+		if err != nil {
+			continue
+		}
+		for _, file := range files {
+			day := strings.TrimSuffix(file.Name(), ".json")
+			if file.IsDir() || day == file.Name() || day < firstDay {
+				continue
+			}
+			data, err := os.ReadFile(filepath.Join(dir, file.Name()))
+			if err != nil {
+				continue
+			}
+			var logs []*MessageLog
+			if err := json.Unmarshal(data, &logs); err != nil {
+				continue
+			}
+			counts[day] += len(logs)
+		}
+	}
+	return counts, nil
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"runtime"
 	"strings"
 
 	"github.com/BurntSushi/toml"
@@ -21,9 +22,85 @@ type Config struct {
 	OpenAI       OpenAIConfig                     `toml:"openai"`
 	Controller   *ControllerConfig                `toml:"controller"`
 	Logging      LoggingConfig                    `toml:"logging"`
+	Tools        ToolsConfig                      `toml:"tools"`
+	History      HistoryConfig                    `toml:"history"`
+	Memory       MemoryConfig                     `toml:"memory"`
+	UI           UIConfig                         `toml:"ui"`
+	Tracing      TracingConfig                    `toml:"tracing"`
+	Metrics      MetricsConfig                    `toml:"metrics"`
+	Redaction    RedactionConfig                  `toml:"redaction"`
+	Bus          BusConfig                        `toml:"bus"`
+	Lease        LeaseConfig                      `toml:"lease"`
+	Sandbox      SandboxConfig                    `toml:"sandbox"`
+	Analytics    AnalyticsConfig                  `toml:"analytics"`
+	Webhooks     []WebhookConfig                  `toml:"webhooks"`
 	SkillsAPIKey string                           `toml:"skills_api_key"`
 }
 
+// HistoryConfig selects where agent conversation histories are kept, and
+// whether full turn transcripts are recorded alongside them.
+type HistoryConfig struct {
+	Backend     string `toml:"backend"`     // file (default), sqlite, memory
+	Path        string `toml:"path"`        // directory (file) or database file (sqlite)
+	Transcripts bool   `toml:"transcripts"` // record each turn's provider requests, responses and tool IO for klaw replay
+}
+
+// MemoryConfig enables semantic recall: facts and past conversations are
+// embedded, and the closest matches are added to the context of each turn.
+// It also caps how much is remembered.
+type MemoryConfig struct {
+	Backend           string  `toml:"backend"`            // "" (off), sqlite, qdrant
+	Path              string  `toml:"path"`               // sqlite database (default: workspace/memory/vectors.db)
+	URL               string  `toml:"url"`                // qdrant server, e.g. http://localhost:6333
+	APIKey            string  `toml:"api_key"`            // qdrant API key
+	Collection        string  `toml:"collection"`         // qdrant collection (default: klaw_memory)
+	EmbeddingProvider string  `toml:"embedding_provider"` // provider whose api_key/base_url embed texts (default: openai)
+	EmbeddingModel    string  `toml:"embedding_model"`    // default: text-embedding-3-small
+	TopK              int     `toml:"top_k"`              // memories added per turn (default 5)
+	MinScore          float64 `toml:"min_score"`          // minimum cosine similarity (default 0.3)
+	MaxFacts          int     `toml:"max_facts"`          // facts per agent, least recently used evicted first (default 500, -1: no limit)
+	FactTTLDays       int     `toml:"fact_ttl_days"`      // evict facts unused for this many days (default: never)
+	MaxFileSize       int     `toml:"max_file_size"`      // bytes of a MEMORY.md before it is compacted (default 16384, -1: no limit)
+}
+
+// UIConfig holds settings of the terminal UIs (klaw dashboard, klaw chat).
+type UIConfig struct {
+	Theme  string            `toml:"theme"`  // auto (default: by terminal background), dark, light
+	Colors map[string]string `toml:"colors"` // palette overrides, e.g. accent = "#7C3AED"
+}
+
+// ToolsConfig holds settings for built-in tools.
+type ToolsConfig struct {
+	Search  SearchConfig  `toml:"search"`
+	HTTP    HTTPConfig    `toml:"http"`
+	Secrets SecretsConfig `toml:"secrets"`
+}
+
+// SecretsConfig controls the masking of credentials (private keys, API
+// keys, tokens, passwords in URLs and environment variables) in tool
+// output before it enters the conversation and reaches the provider.
+type SecretsConfig struct {
+	Mode      string             `toml:"mode"`      // mask (default), off
+	Detectors []string           `toml:"detectors"` // built-in: private_key, api_key, jwt, url_password, env_secret (default: all)
+	Patterns  []RedactionPattern `toml:"patterns"`
+}
+
+// HTTPConfig controls the http_get/http_post/http_request tools.
+type HTTPConfig struct {
+	AllowedDomains   []string `toml:"allowed_domains"` // empty = all domains allowed
+	DeniedDomains    []string `toml:"denied_domains"`
+	TimeoutSeconds   int      `toml:"timeout_seconds"`
+	MaxResponseBytes int      `toml:"max_response_bytes"`
+}
+
+// SearchConfig selects and configures the web_search backend.
+type SearchConfig struct {
+	Backend    string `toml:"backend"` // duckduckgo (default), brave, tavily, searxng
+	APIKey     string `toml:"api_key"`
+	URL        string `toml:"url"` // SearXNG instance URL
+	MaxResults int    `toml:"max_results"` // results per web_search call when it sets none (default 5)
+}
+
 // OpenAIConfig holds OpenAI-compatible gateway settings.
 type OpenAIConfig struct {
 	Enabled       bool                       `toml:"enabled"`
@@ -51,9 +128,20 @@ type SkillSource struct {
 
 // AgentInstanceConfig holds per-agent configuration.
 type AgentInstanceConfig struct {
-	Tools           []string `toml:"tools"`
-	MaxIterations   int      `toml:"max_iterations"`
-	RequireApproval []string `toml:"require_approval"`
+	Tools           []string     `toml:"tools"`
+	MaxIterations   int          `toml:"max_iterations"`
+	RequireApproval []string     `toml:"require_approval"`
+	Hooks           []HookConfig `toml:"hooks"`
+}
+
+// HookConfig runs a shell command at a point in the agent loop. The event
+// is passed as JSON on stdin; a non-zero exit rejects it, and JSON printed
+// to stdout rewrites it.
+type HookConfig struct {
+	Event   string   `toml:"event"`   // pre_message, post_message, pre_tool, post_tool, error
+	Command string   `toml:"command"` // run with sh -c
+	Tools   []string `toml:"tools"`   // tool events only fire for these tools (empty = all)
+	Timeout int      `toml:"timeout"` // seconds (default 10)
 }
 
 // ControllerConfig holds controller connection settings.
@@ -64,14 +152,28 @@ type ControllerConfig struct {
 
 // DefaultsConfig holds default settings.
 type DefaultsConfig struct {
-	Model          string  `toml:"model"`
-	Agent          string  `toml:"agent"`
-	MaxSessionCost float64 `toml:"max_session_cost"`
+	Model                 string  `toml:"model"`
+	Agent                 string  `toml:"agent"`
+	MaxSessionCost        float64 `toml:"max_session_cost"`
+	MaxContextTokens      int     `toml:"max_context_tokens"`      // model context window; history is summarized near the limit
+	MaxConcurrent         int     `toml:"max_concurrent"`          // conversations (e.g. Slack threads) handled in parallel
+	MaxIterations         int     `toml:"max_iterations"`          // tool-calling steps per turn (default 50)
+	MaxTurnTokens         int     `toml:"max_turn_tokens"`         // tokens one turn may use, 0 = unlimited
+	MaxTurnCost           float64 `toml:"max_turn_cost"`           // USD one turn may cost, 0 = unlimited
+	MaxConversationTurns  int     `toml:"max_conversation_turns"`  // turns one conversation (e.g. Slack thread) may take, 0 = unlimited
+	MaxConversationTokens int     `toml:"max_conversation_tokens"` // tokens one conversation may use, 0 = unlimited
+	MaxConversationCost   float64 `toml:"max_conversation_cost"`   // USD one conversation may cost, 0 = unlimited
+	ConversationHandoff   string  `toml:"conversation_handoff"`    // who takes over a conversation at its limit, e.g. <!subteam^S123>; empty = the agent ends it
+	ShutdownTimeout       int     `toml:"shutdown_timeout"`        // seconds running turns and jobs get to finish on shutdown (default 60)
 }
 
 // WorkspaceConfig holds workspace settings.
 type WorkspaceConfig struct {
 	Path string `toml:"path"`
+
+	// AgentsRoot, when set, gives each agent a working directory of its
+	// own under it, see AgentWorkDir. Their file tools cannot leave it.
+	AgentsRoot string `toml:"agents_root"`
 }
 
 // ProviderConfig holds LLM provider settings.
@@ -85,15 +187,114 @@ type ProviderConfig struct {
 
 // ChannelConfig holds channel settings.
 type ChannelConfig struct {
-	Enabled bool   `toml:"enabled"`
-	Token   string `toml:"token"`
-	GuildID string `toml:"guild_id"` // Discord
+	Enabled   bool            `toml:"enabled"`
+	Token     string          `toml:"token"`
+	GuildID   string          `toml:"guild_id"` // Discord
+	RateLimit RateLimitConfig `toml:"rate_limit"`
+}
+
+// RateLimitConfig caps the messages a channel accepts per minute from one
+// user and from one channel. Messages over a limit get a short reply
+// instead of an agent turn.
+type RateLimitConfig struct {
+	PerUser    int    `toml:"per_user"`    // 0 = no limit
+	PerChannel int    `toml:"per_channel"` // 0 = no limit
+	Message    string `toml:"message"`     // reply to throttled messages; default: a note to wait
 }
 
 // ServerConfig holds server settings.
 type ServerConfig struct {
 	Port int    `toml:"port"`
 	Host string `toml:"host"`
+
+	// APIKeys are the bearer tokens accepted by klaw serve's REST API.
+	APIKeys []string `toml:"api_keys"`
+}
+
+// TracingConfig exports traces of agent turns (message, routing, provider
+// calls, tool calls and replies) to an OpenTelemetry collector over
+// OTLP/HTTP. The standard OTEL_EXPORTER_OTLP_* variables apply when
+// endpoint is not set.
+type TracingConfig struct {
+	Enabled     bool              `toml:"enabled"`
+	Endpoint    string            `toml:"endpoint"`     // e.g. localhost:4318 or https://otlp.example.com
+	Insecure    bool              `toml:"insecure"`     // plain HTTP for a host:port endpoint
+	Headers     map[string]string `toml:"headers"`      // e.g. an API key of a hosted collector
+	ServiceName string            `toml:"service_name"` // default: klaw
+	SampleRatio float64           `toml:"sample_ratio"` // fraction of turns traced; default: all
+}
+
+// MetricsConfig exposes Prometheus metrics of klaw start: messages, tool
+// calls, provider errors, job runs, and turn and tool latencies.
+type MetricsConfig struct {
+	Listen string `toml:"listen"` // address of the /metrics endpoint, e.g. 127.0.0.1:9464; empty = off
+}
+
+// BusConfig connects the gateways and workers of klaw start through a
+// message bus (klaw start --role gateway / --role worker).
+type BusConfig struct {
+	URL     string `toml:"url"`     // nats://host:4222 or redis://[:password@]host:6379[/db]
+	Subject string `toml:"subject"` // subject or stream prefix (default: klaw.<cluster>.<namespace>)
+	Group   string `toml:"group"`   // queue group the workers share (default: workers)
+}
+
+// LeaseConfig makes replicas of klaw start that share channels answer each
+// conversation from one replica only: the one holding its lease, which
+// lapses when the replica stops renewing it.
+type LeaseConfig struct {
+	URL string `toml:"url"` // file:///shared/dir or redis://[:password@]host:6379[/db]; empty = off
+	TTL int    `toml:"ttl"` // seconds a lease lasts after the conversation's last activity (default 120)
+}
+
+// SandboxConfig runs the bash commands of agent turns in containers
+// (klaw start --isolation container), with the agent's working directory
+// mounted.
+type SandboxConfig struct {
+	Isolation   string `toml:"isolation"`    // none (default) or container
+	Engine      string `toml:"engine"`       // podman (default) or docker
+	Image       string `toml:"image"`        // image the commands run in (default: debian:stable-slim), pulled when missing
+	Memory      string `toml:"memory"`       // memory limit per container (default 1g)
+	CPUs        string `toml:"cpus"`         // CPU limit per container (default 1)
+	PidsLimit   int    `toml:"pids_limit"`   // process limit per container (default 256)
+	Network     string `toml:"network"`      // network mode, e.g. none (default: the engine's)
+	IdleTimeout int    `toml:"idle_timeout"` // seconds before an unused container is removed (default 600)
+}
+
+// AnalyticsConfig enables tagging the messages of the message logs with
+// their intent and sentiment in the background of klaw start, with a cheap
+// model, for klaw logs stats.
+type AnalyticsConfig struct {
+	Enabled  bool     `toml:"enabled"`
+	Provider string   `toml:"provider"` // default: the provider of klaw start
+	Model    string   `toml:"model"`    // e.g. claude-3-5-haiku-20241022 (default: the model of klaw start)
+	Intents  []string `toml:"intents"`  // intent categories (default: question, task, bug_report, feature_request, feedback, complaint, other)
+	Interval int      `toml:"interval"` // seconds between tagging runs (default 300)
+}
+
+// WebhookConfig is an outbound webhook notified of job, task, node and
+// budget events.
+type WebhookConfig struct {
+	Name     string            `toml:"name"`
+	URL      string            `toml:"url"`
+	Format   string            `toml:"format"`   // http (default): JSON of the event; slack: Slack incoming webhook
+	Events   []string          `toml:"events"`   // job_succeeded, job_failed, task_completed, task_failed, node_down, budget_exceeded (default: all)
+	Template string            `toml:"template"` // Go template of the request body (http) or message text (slack)
+	Headers  map[string]string `toml:"headers"`  // extra request headers, e.g. Authorization
+}
+
+// RedactionConfig masks personal data and credentials in the message logs
+// and the debug log before they are written.
+type RedactionConfig struct {
+	Enabled   bool               `toml:"enabled"`
+	Detectors []string           `toml:"detectors"` // email, phone, api_key (default), private_key, jwt, url_password, env_secret
+	Patterns  []RedactionPattern `toml:"patterns"`
+}
+
+// RedactionPattern is a custom redaction rule.
+type RedactionPattern struct {
+	Name        string `toml:"name"`
+	Regex       string `toml:"regex"`
+	Replacement string `toml:"replacement"` // default: [REDACTED:<name>]
 }
 
 // LoggingConfig holds logging settings.
@@ -108,10 +309,21 @@ func Load() (*Config, error) {
 
 	// Try to load from file
 	configPath := ConfigPath()
-	if _, err := os.Stat(configPath); err == nil {
-		if _, err := toml.DecodeFile(configPath, cfg); err != nil {
+	if data, err := os.ReadFile(configPath); err == nil {
+		md, err := toml.Decode(string(data), cfg)
+		if err != nil {
 			return nil, fmt.Errorf("failed to parse config: %w", err)
 		}
+		// Warnings are left to klaw config validate
+		var errs []Issue
+		for _, issue := range validate(cfg, md, data) {
+			if !issue.Warning {
+				errs = append(errs, issue)
+			}
+		}
+		if len(errs) > 0 {
+			return nil, &ValidationError{Path: configPath, Issues: errs}
+		}
 	}
 
 	// Override with environment variables
@@ -131,11 +343,17 @@ func ConfigPath() string {
 	return filepath.Join(StateDir(), "config.toml")
 }
 
-// StateDir returns the klaw state directory.
+// StateDir returns the klaw state directory: ~/.klaw, or %AppData%\klaw
+// on Windows.
 func StateDir() string {
 	if p := os.Getenv("KLAW_STATE_DIR"); p != "" {
 		return p
 	}
+	if runtime.GOOS == "windows" {
+		if dir, err := os.UserConfigDir(); err == nil {
+			return filepath.Join(dir, "klaw")
+		}
+	}
 	home, _ := os.UserHomeDir()
 	return filepath.Join(home, ".klaw")
 }
@@ -153,6 +371,31 @@ func (c *Config) WorkspaceDir() string {
 	return filepath.Join(StateDir(), "workspace")
 }
 
+// AgentWorkDir returns the working directory of an agent's tools under
+// the agents root: <root>/<cluster>/<namespace>/agents/<agent>, or
+// <root>/<cluster>/<namespace>/main for the main agent (agent ""). It
+// returns "" when no agents root is set and agents share the directory
+// klaw was started from.
+func (c *Config) AgentWorkDir(cluster, namespace, agent string) string {
+	if c.Workspace.AgentsRoot == "" {
+		return ""
+	}
+	dir := filepath.Join(c.Workspace.AgentsRoot, pathElem(cluster), pathElem(namespace))
+	if agent == "" {
+		return filepath.Join(dir, "main")
+	}
+	return filepath.Join(dir, "agents", pathElem(agent))
+}
+
+// pathElem makes name safe to use as one element of a path.
+func pathElem(name string) string {
+	name = strings.NewReplacer("/", "_", "\\", "_").Replace(name)
+	if name == "" || name == "." || name == ".." {
+		return "_" + name
+	}
+	return name
+}
+
 // SessionsDir returns the sessions directory.
 func SessionsDir() string {
 	return filepath.Join(StateDir(), "sessions")
@@ -221,27 +464,66 @@ func (c *Config) applyEnv() {
 		c.Defaults.Model = model
 	}
 
+	// REST API key
+	if key := os.Getenv("KLAW_API_KEY"); key != "" {
+		c.Server.APIKeys = append(c.Server.APIKeys, key)
+	}
+
+	// Terminal UI theme
+	if theme := os.Getenv("KLAW_THEME"); theme != "" {
+		c.UI.Theme = theme
+	}
+
 	// Skills API key
 	if key := os.Getenv("KLAW_SKILLS_API_KEY"); key != "" {
 		c.SkillsAPIKey = key
 	}
+
+	// Web search backend
+	if backend := os.Getenv("KLAW_SEARCH_BACKEND"); backend != "" {
+		c.Tools.Search.Backend = backend
+	}
+	for _, sk := range []struct{ backend, env string }{
+		{"brave", "BRAVE_API_KEY"},
+		{"tavily", "TAVILY_API_KEY"},
+	} {
+		key := os.Getenv(sk.env)
+		if key == "" || c.Tools.Search.APIKey != "" {
+			continue
+		}
+		if c.Tools.Search.Backend == "" || c.Tools.Search.Backend == sk.backend {
+			c.Tools.Search.Backend = sk.backend
+			c.Tools.Search.APIKey = key
+		}
+	}
+	if url := os.Getenv("SEARXNG_URL"); url != "" && c.Tools.Search.URL == "" {
+		c.Tools.Search.URL = url
+		if c.Tools.Search.Backend == "" {
+			c.Tools.Search.Backend = "searxng"
+		}
+	}
 }
 
 func (c *Config) expandPaths() {
 	home, _ := os.UserHomeDir()
 
 	expand := func(p string) string {
-		if strings.HasPrefix(p, "~/") {
+		if strings.HasPrefix(p, "~/") || strings.HasPrefix(p, `~\`) {
 			return filepath.Join(home, p[2:])
 		}
 		if strings.HasPrefix(p, "$HOME/") {
 			return filepath.Join(home, p[6:])
 		}
+		if strings.HasPrefix(p, "%USERPROFILE%") {
+			return filepath.Join(home, p[len("%USERPROFILE%"):])
+		}
 		return p
 	}
 
 	c.Workspace.Path = expand(c.Workspace.Path)
+	c.Workspace.AgentsRoot = expand(c.Workspace.AgentsRoot)
 	c.Logging.File = expand(c.Logging.File)
+	c.Memory.Path = expand(c.Memory.Path)
 }
 
 // Save writes the config to file.
@@ -1,8 +1,10 @@
 package config
 
 import (
+	"errors"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 )
 
@@ -41,6 +43,7 @@ func TestLoadFromTOML(t *testing.T) {
 	t.Setenv("KLAW_MODEL", "")
 	t.Setenv("TELEGRAM_BOT_TOKEN", "")
 	t.Setenv("DISCORD_BOT_TOKEN", "")
+	t.Setenv("KLAW_THEME", "")
 
 	dir := t.TempDir()
 	configPath := filepath.Join(dir, "config.toml")
@@ -61,6 +64,12 @@ fallback = "eachlabs"
 tools = ["read", "grep", "web_fetch"]
 max_iterations = 30
 require_approval = ["bash"]
+
+[ui]
+theme = "light"
+
+[ui.colors]
+accent = "#5B21B6"
 `
 	if err := os.WriteFile(configPath, []byte(content), 0644); err != nil {
 		t.Fatalf("failed to write config: %v", err)
@@ -110,6 +119,10 @@ require_approval = ["bash"]
 	if len(agentCfg.RequireApproval) != 1 || agentCfg.RequireApproval[0] != "bash" {
 		t.Errorf("unexpected require_approval: %v", agentCfg.RequireApproval)
 	}
+
+	if cfg.UI.Theme != "light" || cfg.UI.Colors["accent"] != "#5B21B6" {
+		t.Errorf("unexpected ui config: %+v", cfg.UI)
+	}
 }
 
 func TestApplyEnv(t *testing.T) {
@@ -165,6 +178,18 @@ func TestApplyEnv_Discord(t *testing.T) {
 	}
 }
 
+func TestApplyEnv_APIKey(t *testing.T) {
+	cfg := defaultConfig()
+	cfg.Server.APIKeys = []string{"from-config"}
+	t.Setenv("KLAW_API_KEY", "from-env")
+
+	cfg.applyEnv()
+
+	if len(cfg.Server.APIKeys) != 2 || cfg.Server.APIKeys[1] != "from-env" {
+		t.Errorf("api_keys = %v, want [from-config from-env]", cfg.Server.APIKeys)
+	}
+}
+
 func TestExpandPaths(t *testing.T) {
 	home, _ := os.UserHomeDir()
 	cfg := defaultConfig()
@@ -185,6 +210,22 @@ func TestExpandPaths(t *testing.T) {
 	}
 }
 
+func TestExpandPaths_Windows(t *testing.T) {
+	home, _ := os.UserHomeDir()
+	cfg := defaultConfig()
+	cfg.Workspace.Path = `~\workspace`
+	cfg.Memory.Path = `%USERPROFILE%\klaw\vectors.db`
+
+	cfg.expandPaths()
+
+	if want := filepath.Join(home, "workspace"); cfg.Workspace.Path != want {
+		t.Errorf("workspace = %q, want %q", cfg.Workspace.Path, want)
+	}
+	if !strings.HasPrefix(cfg.Memory.Path, home) {
+		t.Errorf("memory path = %q, want it under %q", cfg.Memory.Path, home)
+	}
+}
+
 func TestExpandPaths_NoExpansion(t *testing.T) {
 	cfg := defaultConfig()
 	cfg.Workspace.Path = "/absolute/path"
@@ -279,6 +320,25 @@ func TestWorkspaceDir(t *testing.T) {
 	})
 }
 
+func TestAgentWorkDir(t *testing.T) {
+	cfg := defaultConfig()
+	if dir := cfg.AgentWorkDir("c1", "ns", "coder"); dir != "" {
+		t.Errorf("expected no agent dir without an agents root, got %q", dir)
+	}
+
+	cfg.Workspace.AgentsRoot = "/srv/agents"
+	for _, tc := range []struct{ agent, want string }{
+		{"coder", "/srv/agents/c1/ns/agents/coder"},
+		{"", "/srv/agents/c1/ns/main"},
+		{"../../etc", "/srv/agents/c1/ns/agents/.._.._etc"},
+		{"..", "/srv/agents/c1/ns/agents/_.."},
+	} {
+		if dir := cfg.AgentWorkDir("c1", "ns", tc.agent); dir != tc.want {
+			t.Errorf("agent %q: expected %q, got %q", tc.agent, tc.want, dir)
+		}
+	}
+}
+
 func TestConfigPath_EnvOverride(t *testing.T) {
 	t.Setenv("KLAW_CONFIG", "/custom/config.toml")
 	if ConfigPath() != "/custom/config.toml" {
@@ -317,3 +377,97 @@ func TestLoad_InvalidTOML(t *testing.T) {
 		t.Fatal("expected error for invalid TOML")
 	}
 }
+
+func TestValidateFile(t *testing.T) {
+	dir := t.TempDir()
+	configPath := filepath.Join(dir, "config.toml")
+	content := `[defaults]
+modle = "claude-sonnet-4-20250514"
+
+[provider.anthropic]
+model = "gpt-4o"
+fallback = "ollama"
+
+[provider.ollama]
+model = "llama3"
+
+[history]
+backend = "sqlit"
+
+[agent.coder]
+tools = ["bash"]
+
+[[agent.coder.hooks]]
+event = "pre_tool"
+command = "./check.sh"
+
+[[agent.coder.hooks]]
+event = "on_error"
+command = "./alert.sh"
+
+[telemetry]
+enabled = true
+
+[[webhooks]]
+url = "hooks.slack.com/services/T0/B0/x"
+events = ["job_failed", "node_offline"]
+`
+	_ = os.WriteFile(configPath, []byte(content), 0644)
+
+	issues, err := ValidateFile(configPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{
+		`line 2: defaults.modle: unknown key (did you mean model?)`,
+		`line 5: provider.anthropic.model: "gpt-4o" is not an Anthropic model (e.g. claude-sonnet-4-20250514)`,
+		`line 8: provider.ollama: custom provider needs base_url, e.g. http://localhost:11434/v1`,
+		`line 12: history.backend: unknown value "sqlit" (did you mean sqlite?)`,
+		`line 22: agent.coder.hooks.event: unknown value "on_error" (use pre_message, post_message, pre_tool, post_tool, error)`,
+		`line 25: telemetry: unknown key`,
+		`line 29: webhooks.url: "hooks.slack.com/services/T0/B0/x" is not an http(s) URL`,
+		`line 30: webhooks.events: unknown value "node_offline" (use job_succeeded, job_failed, task_completed, task_failed, node_down, budget_exceeded)`,
+	}
+	if len(issues) != len(want) {
+		t.Fatalf("expected %d issues, got %v", len(want), issues)
+	}
+	for i, w := range want {
+		if issues[i].String() != w {
+			t.Errorf("issue %d:\n got %s\nwant %s", i, issues[i], w)
+		}
+	}
+}
+
+func TestValidateFile_SavedConfig(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), "config.toml")
+	t.Setenv("KLAW_CONFIG", configPath)
+
+	cfg := defaultConfig()
+	cfg.Provider["anthropic"] = ProviderConfig{APIKey: "sk-save"}
+	cfg.Agents["coder"] = AgentInstanceConfig{Hooks: []HookConfig{{Event: "pre_tool", Command: "true"}}}
+	if err := cfg.Save(); err != nil {
+		t.Fatal(err)
+	}
+	issues, err := ValidateFile(configPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(issues) != 0 {
+		t.Errorf("saved config should be valid, got %v", issues)
+	}
+}
+
+func TestLoad_ValidationError(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), "config.toml")
+	_ = os.WriteFile(configPath, []byte("[server]\nprot = 9000\n"), 0644)
+	t.Setenv("KLAW_CONFIG", configPath)
+
+	_, err := Load()
+	var verr *ValidationError
+	if !errors.As(err, &verr) {
+		t.Fatalf("expected a validation error, got %v", err)
+	}
+	if len(verr.Issues) != 1 || verr.Issues[0].Line != 2 || verr.Issues[0].Key != "server.prot" {
+		t.Errorf("unexpected issues: %v", verr.Issues)
+	}
+}
@@ -0,0 +1,479 @@
+package config
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"os"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+	"text/template"
+
+	"github.com/BurntSushi/toml"
+)
+
+// Issue is a problem found in a config file.
+type Issue struct {
+	Line    int    `json:"line,omitempty"` // line of the key in the file, 0 if unknown
+	Key     string `json:"key"`            // dotted key, e.g. provider.anthropic.model
+	Message string `json:"message"`
+	Warning bool   `json:"warning,omitempty"` // the config works, but probably not as intended
+}
+
+func (i Issue) String() string {
+	s := i.Key + ": " + i.Message
+	if i.Line > 0 {
+		s = fmt.Sprintf("line %d: %s", i.Line, s)
+	}
+	if i.Warning {
+		s = "warning: " + s
+	}
+	return s
+}
+
+// ValidationError reports the errors of a config file that fails
+// validation.
+type ValidationError struct {
+	Path   string
+	Issues []Issue
+}
+
+func (e *ValidationError) Error() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "invalid config %s:", e.Path)
+	for _, issue := range e.Issues {
+		b.WriteString("\n  " + issue.String())
+	}
+	b.WriteString("\n\nRun 'klaw config validate' to check the file after fixing it.")
+	return b.String()
+}
+
+// Allowed values of enumerated settings; the empty value, which selects
+// the default, is always allowed.
+var enumValues = map[string][]string{
+	"history.backend":         {"file", "sqlite", "memory"},
+	"memory.backend":          {"sqlite", "qdrant"},
+	"ui.theme":                {"auto", "dark", "light"},
+	"tools.search.backend":    {"duckduckgo", "brave", "tavily", "searxng"},
+	"logging.level":           {"debug", "info", "warn", "error"},
+	"redaction.detectors":     {"email", "phone", "api_key", "private_key", "jwt", "url_password", "env_secret"},
+	"tools.secrets.mode":      {"mask", "off"},
+	"tools.secrets.detectors": {"private_key", "api_key", "jwt", "url_password", "env_secret", "email", "phone"},
+	"agent.*.hooks.event":     {"pre_message", "post_message", "pre_tool", "post_tool", "error"},
+	"webhooks.format":         {"http", "slack"},
+	"webhooks.events":         {"job_succeeded", "job_failed", "task_completed", "task_failed", "node_down", "budget_exceeded"},
+}
+
+// builtinProviders are the providers that need no base_url; their API
+// keys may come from the environment alone.
+var builtinProviders = map[string]bool{"anthropic": true, "openai": true, "openrouter": true, "eachlabs": true}
+
+// ValidateFile checks the config file at path without applying the
+// environment. It returns an error only if the file cannot be read or is
+// not valid TOML.
+func ValidateFile(path string) ([]Issue, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	cfg := defaultConfig()
+	md, err := toml.Decode(string(data), cfg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse config: %w", err)
+	}
+	return validate(cfg, md, data), nil
+}
+
+// validate checks a decoded config against the schema of Config and the
+// allowed values of its settings.
+func validate(cfg *Config, md toml.MetaData, data []byte) []Issue {
+	v := &validator{lines: keyLines(data)}
+
+	// Keys that do not exist in Config; the keys of an unknown table are
+	// left out
+	var unknown []string
+	for _, key := range md.Undecoded() {
+		path := strings.Join(key, ".")
+		parent := false
+		for _, u := range unknown {
+			if strings.HasPrefix(path, u+".") {
+				parent = true
+				break
+			}
+		}
+		if parent {
+			continue
+		}
+		unknown = append(unknown, path)
+		msg := "unknown key"
+		if s := suggest(key[len(key)-1], schemaKeys(key[:len(key)-1])); s != "" {
+			msg += fmt.Sprintf(" (did you mean %s?)", s)
+		}
+		v.errorf(path, 0, "%s", msg)
+	}
+
+	v.enum("history.backend", 0, cfg.History.Backend)
+	v.enum("memory.backend", 0, cfg.Memory.Backend)
+	v.enum("ui.theme", 0, strings.ToLower(cfg.UI.Theme))
+	v.enum("tools.search.backend", 0, cfg.Tools.Search.Backend)
+	v.enum("logging.level", 0, cfg.Logging.Level)
+
+	if cfg.Server.Port < 0 || cfg.Server.Port > 65535 {
+		v.errorf("server.port", 0, "%d is not a valid port", cfg.Server.Port)
+	}
+	if cfg.Memory.Backend == "qdrant" && cfg.Memory.URL == "" {
+		v.errorf("memory.url", 0, "required by the qdrant backend")
+	}
+	if cfg.Memory.MinScore < 0 || cfg.Memory.MinScore > 1 {
+		v.errorf("memory.min_score", 0, "must be between 0 and 1")
+	}
+	if cfg.Tracing.SampleRatio < 0 || cfg.Tracing.SampleRatio > 1 {
+		v.errorf("tracing.sample_ratio", 0, "must be between 0 and 1")
+	}
+	for _, limit := range []struct {
+		key string
+		n   int
+	}{
+		{"defaults.max_concurrent", cfg.Defaults.MaxConcurrent},
+		{"defaults.max_iterations", cfg.Defaults.MaxIterations},
+		{"defaults.max_context_tokens", cfg.Defaults.MaxContextTokens},
+		{"defaults.max_turn_tokens", cfg.Defaults.MaxTurnTokens},
+		{"defaults.max_conversation_turns", cfg.Defaults.MaxConversationTurns},
+		{"defaults.max_conversation_tokens", cfg.Defaults.MaxConversationTokens},
+	} {
+		if limit.n < 0 {
+			v.errorf(limit.key, 0, "must not be negative")
+		}
+	}
+
+	// Models: Anthropic names a model without a vendor, OpenRouter and
+	// each::labs as vendor/model
+	if strings.Contains(cfg.Defaults.Model, "/") {
+		v.warnf("defaults.model", 0, "%q is a vendor/model name, but the default model is used with anthropic; set the model of [provider.openrouter] or [provider.eachlabs] instead", cfg.Defaults.Model)
+	}
+	for _, name := range sortedKeys(cfg.Provider) {
+		p := cfg.Provider[name]
+		prefix := "provider." + name
+		if strings.ContainsAny(p.Model, " \t") {
+			v.errorf(prefix+".model", 0, "%q is not a model name", p.Model)
+		}
+		switch name {
+		case "anthropic":
+			if p.Model != "" && !strings.HasPrefix(p.Model, "claude-") {
+				v.errorf(prefix+".model", 0, "%q is not an Anthropic model (e.g. claude-sonnet-4-20250514)", p.Model)
+			}
+		case "openrouter", "eachlabs":
+			if p.Model != "" && !strings.Contains(p.Model, "/") {
+				v.errorf(prefix+".model", 0, "%q should be vendor/model, e.g. anthropic/claude-sonnet-4", p.Model)
+			}
+		}
+		if !builtinProviders[name] && p.BaseURL == "" && md.IsDefined("provider", name) {
+			v.errorf(prefix, 0, "custom provider needs base_url, e.g. http://localhost:11434/v1")
+		}
+		if p.Fallback != "" {
+			if p.Fallback == name {
+				v.errorf(prefix+".fallback", 0, "provider cannot fall back to itself")
+			} else if _, ok := cfg.Provider[p.Fallback]; !ok && !builtinProviders[p.Fallback] {
+				v.errorf(prefix+".fallback", 0, "unknown provider %q", p.Fallback)
+			}
+		}
+		if p.MaxRetries < 0 {
+			v.errorf(prefix+".max_retries", 0, "must not be negative")
+		}
+	}
+
+	for _, name := range sortedKeys(cfg.Agents) {
+		for i, h := range cfg.Agents[name].Hooks {
+			key := "agent." + name + ".hooks"
+			v.enumAt("agent.*.hooks.event", key+".event", i, h.Event)
+			if h.Event == "" {
+				v.errorf(key+".event", i, "required")
+			}
+			if h.Command == "" {
+				v.errorf(key+".command", i, "required")
+			}
+		}
+	}
+
+	v.redaction("redaction", cfg.Redaction.Detectors, cfg.Redaction.Patterns)
+	v.enum("tools.secrets.mode", 0, cfg.Tools.Secrets.Mode)
+	v.redaction("tools.secrets", cfg.Tools.Secrets.Detectors, cfg.Tools.Secrets.Patterns)
+
+	for _, name := range sortedKeys(cfg.Channel) {
+		rl := cfg.Channel[name].RateLimit
+		prefix := "channel." + name + ".rate_limit"
+		if rl.PerUser < 0 {
+			v.errorf(prefix+".per_user", 0, "must not be negative")
+		}
+		if rl.PerChannel < 0 {
+			v.errorf(prefix+".per_channel", 0, "must not be negative")
+		}
+	}
+
+	for i, w := range cfg.Webhooks {
+		if w.URL == "" {
+			v.errorf("webhooks.url", i, "required")
+		} else if !strings.HasPrefix(w.URL, "http://") && !strings.HasPrefix(w.URL, "https://") {
+			v.errorf("webhooks.url", i, "%q is not an http(s) URL", w.URL)
+		}
+		v.enumAt("webhooks.format", "webhooks.format", i, w.Format)
+		for _, e := range w.Events {
+			v.enumAt("webhooks.events", "webhooks.events", i, e)
+		}
+		if w.Template != "" {
+			// json is a function of webhook templates
+			if _, err := template.New("webhook").Funcs(template.FuncMap{"json": func(any) string { return "" }}).Parse(w.Template); err != nil {
+				v.errorf("webhooks.template", i, "%v", err)
+			}
+		}
+	}
+
+	for _, id := range sortedKeys(cfg.OpenAI.Models) {
+		m := cfg.OpenAI.Models[id]
+		if m.Provider != "" {
+			if _, ok := cfg.Provider[m.Provider]; !ok && !builtinProviders[m.Provider] {
+				v.errorf("openai.models."+id+".provider", 0, "unknown provider %q", m.Provider)
+			}
+		}
+	}
+
+	sort.SliceStable(v.issues, func(i, j int) bool {
+		if v.issues[i].Line == 0 || v.issues[j].Line == 0 {
+			return v.issues[i].Line > v.issues[j].Line
+		}
+		return v.issues[i].Line < v.issues[j].Line
+	})
+	return v.issues
+}
+
+// validator collects issues, locating keys with the lines of the file.
+type validator struct {
+	lines  map[string][]int
+	issues []Issue
+}
+
+// line returns the line of the n-th occurrence of key, or of its closest
+// parent table when the key is not in the file.
+func (v *validator) line(key string, n int) int {
+	for {
+		if lines := v.lines[key]; n < len(lines) {
+			return lines[n]
+		}
+		i := strings.LastIndex(key, ".")
+		if i < 0 {
+			return 0
+		}
+		key = key[:i]
+	}
+}
+
+func (v *validator) errorf(key string, n int, format string, args ...any) {
+	v.issues = append(v.issues, Issue{Line: v.line(key, n), Key: key, Message: fmt.Sprintf(format, args...)})
+}
+
+func (v *validator) warnf(key string, n int, format string, args ...any) {
+	v.issues = append(v.issues, Issue{Line: v.line(key, n), Key: key, Message: fmt.Sprintf(format, args...), Warning: true})
+}
+
+// redaction checks the detectors and patterns of a redaction table.
+func (v *validator) redaction(table string, detectors []string, patterns []RedactionPattern) {
+	for _, d := range detectors {
+		v.enum(table+".detectors", 0, d)
+	}
+	for i, p := range patterns {
+		if p.Name == "" {
+			v.errorf(table+".patterns.name", i, "required")
+		}
+		if p.Regex == "" {
+			v.errorf(table+".patterns.regex", i, "required")
+		} else if _, err := regexp.Compile(p.Regex); err != nil {
+			v.errorf(table+".patterns.regex", i, "%v", err)
+		}
+	}
+}
+
+func (v *validator) enum(key string, n int, value string) {
+	v.enumAt(key, key, n, value)
+}
+
+// enumAt checks value against the allowed values of schemaKey.
+func (v *validator) enumAt(schemaKey, key string, n int, value string) {
+	if value == "" {
+		return
+	}
+	allowed := enumValues[schemaKey]
+	for _, a := range allowed {
+		if value == a {
+			return
+		}
+	}
+	msg := fmt.Sprintf("unknown value %q (use %s)", value, strings.Join(allowed, ", "))
+	if s := suggest(value, allowed); s != "" {
+		msg = fmt.Sprintf("unknown value %q (did you mean %s?)", value, s)
+	}
+	v.errorf(key, n, "%s", msg)
+}
+
+// schemaKeys returns the keys Config allows in the table at path. Map
+// tables, like [provider.<name>], match any name.
+func schemaKeys(path []string) []string {
+	t := reflect.TypeOf(Config{})
+	for _, part := range path {
+		for t.Kind() == reflect.Pointer || t.Kind() == reflect.Slice {
+			t = t.Elem()
+		}
+		switch t.Kind() {
+		case reflect.Map:
+			t = t.Elem()
+		case reflect.Struct:
+			f, ok := fieldByTag(t, part)
+			if !ok {
+				return nil
+			}
+			t = f.Type
+		default:
+			return nil
+		}
+	}
+	for t.Kind() == reflect.Pointer || t.Kind() == reflect.Slice {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct {
+		return nil
+	}
+	var keys []string
+	for i := 0; i < t.NumField(); i++ {
+		if tag := tomlTag(t.Field(i)); tag != "" {
+			keys = append(keys, tag)
+		}
+	}
+	return keys
+}
+
+func fieldByTag(t reflect.Type, tag string) (reflect.StructField, bool) {
+	for i := 0; i < t.NumField(); i++ {
+		if tomlTag(t.Field(i)) == tag {
+			return t.Field(i), true
+		}
+	}
+	return reflect.StructField{}, false
+}
+
+func tomlTag(f reflect.StructField) string {
+	tag, _, _ := strings.Cut(f.Tag.Get("toml"), ",")
+	if tag == "-" {
+		return ""
+	}
+	return tag
+}
+
+// suggest returns the candidate closest to s, if it is close enough to be
+// a typo.
+func suggest(s string, candidates []string) string {
+	best, bestDist := "", 3
+	for _, c := range candidates {
+		if d := editDistance(strings.ToLower(s), c); d < bestDist {
+			best, bestDist = c, d
+		}
+	}
+	return best
+}
+
+// editDistance is the Levenshtein distance of a and b.
+func editDistance(a, b string) int {
+	prev := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		cur := make([]int, len(b)+1)
+		cur[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev = cur
+	}
+	return prev[len(b)]
+}
+
+// keyLines maps the dotted path of every key and table header of a TOML
+// document to the lines it is on, in order. Keys of array tables
+// ([[agent.x.hooks]]) appear once per table.
+func keyLines(data []byte) map[string][]int {
+	lines := make(map[string][]int)
+	table := ""
+	inString := false
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for n := 1; scanner.Scan(); n++ {
+		line := strings.TrimSpace(scanner.Text())
+		// Skip the contents of multi-line strings
+		quotes := strings.Count(line, `"""`) + strings.Count(line, `'''`)
+		if inString {
+			inString = quotes%2 == 0
+			continue
+		}
+		if quotes%2 == 1 {
+			inString = true
+		}
+
+		switch {
+		case line == "" || strings.HasPrefix(line, "#"):
+		case strings.HasPrefix(line, "[["):
+			table = joinKey(strings.Trim(strings.SplitN(line, "]]", 2)[0], "[ "))
+			lines[table] = append(lines[table], n)
+		case strings.HasPrefix(line, "["):
+			table = joinKey(strings.Trim(strings.SplitN(line, "]", 2)[0], "[ "))
+			lines[table] = append(lines[table], n)
+		default:
+			key, _, ok := strings.Cut(line, "=")
+			if !ok {
+				continue
+			}
+			path := joinKey(key)
+			if table != "" {
+				path = table + "." + path
+			}
+			lines[path] = append(lines[path], n)
+		}
+	}
+	return lines
+}
+
+// joinKey normalizes a dotted TOML key, unquoting its parts.
+func joinKey(key string) string {
+	var parts []string
+	var part strings.Builder
+	quote := byte(0)
+	for i := 0; i < len(key); i++ {
+		c := key[i]
+		switch {
+		case quote != 0 && c == quote:
+			quote = 0
+		case quote != 0:
+			part.WriteByte(c)
+		case c == '"' || c == '\'':
+			quote = c
+		case c == '.':
+			parts = append(parts, strings.TrimSpace(part.String()))
+			part.Reset()
+		default:
+			part.WriteByte(c)
+		}
+	}
+	return strings.Join(append(parts, strings.TrimSpace(part.String())), ".")
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,102 @@
+package controller
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Strategies of spreading the tasks of an agent registered on several
+// nodes, its replicas
+const (
+	RoundRobin  = "round-robin"  // each replica in turn
+	LeastLoaded = "least-loaded" // the replica whose node runs the fewest tasks
+)
+
+// nodeTimeout is how long a node may go without a heartbeat before it is
+// reported down and its replicas get no more tasks.
+const nodeTimeout = 60 * time.Second
+
+// ValidateLoadBalancing checks a load-balancing strategy; empty means
+// LeastLoaded.
+func ValidateLoadBalancing(strategy string) error {
+	switch strategy {
+	case "", RoundRobin, LeastLoaded:
+		return nil
+	}
+	return fmt.Errorf("invalid load balancing %q (use %s or %s)", strategy, RoundRobin, LeastLoaded)
+}
+
+// balancer picks the replica of an agent a task is sent to.
+type balancer struct {
+	strategy string
+
+	mu       sync.Mutex
+	next     map[string]int // round-robin position by agent name
+	inflight map[string]int // tasks sent and not finished by node ID
+}
+
+func newBalancer(strategy string) *balancer {
+	if strategy == "" {
+		strategy = LeastLoaded
+	}
+	return &balancer{strategy: strategy, next: make(map[string]int), inflight: make(map[string]int)}
+}
+
+// pick chooses the replica of the named agent to send a task to, skipping
+// replicas not running or on nodes healthy rejects. It returns nil when no
+// replica is left.
+func (b *balancer) pick(agents []*Agent, name string, healthy func(nodeID string) bool) *Agent {
+	var replicas []*Agent
+	for _, a := range agents {
+		if a.Name == name && a.Status == "running" && healthy(a.NodeID) {
+			replicas = append(replicas, a)
+		}
+	}
+	if len(replicas) == 0 {
+		return nil
+	}
+	// The store lists agents in no particular order
+	slices.SortFunc(replicas, func(x, y *Agent) int { return strings.Compare(x.ID, y.ID) })
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	start := b.next[name] % len(replicas)
+	b.next[name]++
+	best := replicas[start]
+	if b.strategy == LeastLoaded {
+		// Ties go to the replica next in turn
+		for i := 1; i < len(replicas); i++ {
+			r := replicas[(start+i)%len(replicas)]
+			if b.inflight[r.NodeID] < b.inflight[best.NodeID] {
+				best = r
+			}
+		}
+	}
+	return best
+}
+
+// started counts a task sent to a node.
+func (b *balancer) started(nodeID string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.inflight[nodeID]++
+}
+
+// finished counts a task of a node done.
+func (b *balancer) finished(nodeID string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.inflight[nodeID] > 0 {
+		b.inflight[nodeID]--
+	}
+}
+
+// forget drops the count of a node that went away with its tasks.
+func (b *balancer) forget(nodeID string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	delete(b.inflight, nodeID)
+}
@@ -0,0 +1,73 @@
+package controller
+
+import (
+	"slices"
+	"testing"
+)
+
+func replicas() []*Agent {
+	return []*Agent{
+		{ID: "c", Name: "coder", NodeID: "n3", Status: "running"},
+		{ID: "a", Name: "coder", NodeID: "n1", Status: "running"},
+		{ID: "b", Name: "coder", NodeID: "n2", Status: "running"},
+		{ID: "d", Name: "writer", NodeID: "n1", Status: "running"},
+		{ID: "e", Name: "coder", NodeID: "n4", Status: "stopped"},
+	}
+}
+
+func allHealthy(string) bool { return true }
+
+func TestBalancer_RoundRobin(t *testing.T) {
+	b := newBalancer(RoundRobin)
+	var got []string
+	for range 4 {
+		got = append(got, b.pick(replicas(), "coder", allHealthy).NodeID)
+	}
+	if want := []string{"n1", "n2", "n3", "n1"}; !slices.Equal(got, want) {
+		t.Errorf("picked %v, want %v", got, want)
+	}
+}
+
+func TestBalancer_LeastLoaded(t *testing.T) {
+	b := newBalancer(LeastLoaded)
+	b.started("n1")
+	b.started("n1")
+	b.started("n2")
+
+	if got := b.pick(replicas(), "coder", allHealthy).NodeID; got != "n3" {
+		t.Errorf("picked %s, want the idle n3", got)
+	}
+	b.started("n3")
+	b.finished("n1")
+	b.finished("n1")
+	if got := b.pick(replicas(), "coder", allHealthy).NodeID; got != "n1" {
+		t.Errorf("picked %s, want n1 after its tasks finished", got)
+	}
+}
+
+func TestBalancer_SkipsUnhealthyNodes(t *testing.T) {
+	b := newBalancer(RoundRobin)
+	healthy := func(nodeID string) bool { return nodeID == "n2" }
+	for range 3 {
+		if got := b.pick(replicas(), "coder", healthy).NodeID; got != "n2" {
+			t.Fatalf("picked %s, want the only healthy n2", got)
+		}
+	}
+	if a := b.pick(replicas(), "coder", func(string) bool { return false }); a != nil {
+		t.Errorf("picked %s without a healthy node", a.NodeID)
+	}
+	if a := b.pick(replicas(), "missing", allHealthy); a != nil {
+		t.Errorf("picked %s for an unknown agent", a.NodeID)
+	}
+}
+
+func TestValidateLoadBalancing(t *testing.T) {
+	for _, s := range []string{"", RoundRobin, LeastLoaded} {
+		if err := ValidateLoadBalancing(s); err != nil {
+			t.Errorf("ValidateLoadBalancing(%q) = %v", s, err)
+		}
+	}
+	if err := ValidateLoadBalancing("random"); err == nil {
+		t.Error("expected an error for an unknown strategy")
+	}
+}
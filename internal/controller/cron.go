@@ -0,0 +1,127 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/eachlabs/klaw/internal/lease"
+	"github.com/eachlabs/klaw/internal/scheduler"
+)
+
+const (
+	// schedulerLease is the lease held by the controller running the
+	// cluster-wide cron jobs; of several controllers sharing a data
+	// directory or lease store, only the holder does.
+	schedulerLease = "klaw/controller/scheduler"
+
+	// schedulerLeaseTTL outlasts the scheduler's minute tick, which renews
+	// it, so another controller takes over within two minutes of a crash.
+	schedulerLeaseTTL = 2 * time.Minute
+
+	// jobTimeout bounds the task of a cron job run.
+	jobTimeout = 10 * time.Minute
+)
+
+// MetaJob is the task metadata key naming the cron job a task runs.
+const MetaJob = "job"
+
+// SchedulerDir is where the cluster-wide cron jobs of a controller with
+// the data directory are kept.
+func SchedulerDir(dataDir string) string {
+	return filepath.Join(dataDir, "scheduler")
+}
+
+// jobMetadata is the metadata of the task of a cron job run.
+func jobMetadata(job *scheduler.Job) map[string]string {
+	return map[string]string{MetaJob: job.Name, MetaPriority: job.Priority.String()}
+}
+
+// taskType is the type of a dispatched task with the metadata.
+func taskType(metadata map[string]string) string {
+	if metadata[MetaJob] != "" {
+		return "cron"
+	}
+	return "message"
+}
+
+// startScheduler runs the cluster-wide cron jobs with run while the
+// controller holds the scheduler lease, until ctx is done.
+func startScheduler(ctx context.Context, cfg ServerConfig, run scheduler.JobRunner) (*scheduler.Scheduler, error) {
+	leases := cfg.Leases
+	if leases == nil {
+		store, err := lease.NewFileStore(filepath.Join(cfg.DataDir, "leases"))
+		if err != nil {
+			return nil, err
+		}
+		leases = store
+	}
+	owner := cfg.InstanceID
+	if owner == "" {
+		host, _ := os.Hostname()
+		owner = fmt.Sprintf("%s-%d", host, os.Getpid())
+	}
+
+	sched := scheduler.NewScheduler(SchedulerDir(cfg.DataDir))
+	if err := sched.Load(); err != nil {
+		return nil, fmt.Errorf("failed to load cron jobs: %w", err)
+	}
+	sched.SetJobRunner(run)
+
+	leader := false
+	sched.SetTickGate(func(ctx context.Context) bool {
+		held, err := leases.Acquire(ctx, schedulerLease, owner, schedulerLeaseTTL)
+		if err != nil {
+			fmt.Printf("⚠️  Scheduler lease: %v\n", err)
+			held = false
+		}
+		if held != leader {
+			leader = held
+			if held {
+				fmt.Println("🕐 Running the cluster-wide cron jobs")
+			} else {
+				fmt.Println("🕐 Another controller runs the cluster-wide cron jobs")
+			}
+		}
+		return held
+	})
+	if err := sched.Start(ctx); err != nil {
+		return nil, err
+	}
+
+	// Hand the jobs over right away when stopping
+	go func() {
+		<-ctx.Done()
+		_ = leases.Release(context.Background(), schedulerLease, owner)
+	}()
+	return sched, nil
+}
+
+// waitTask waits until the task finishes, polling the store, and returns
+// its result.
+func waitTask(ctx context.Context, store Store, taskID string, timeout time.Duration) (string, error) {
+	ticker := time.NewTicker(500 * time.Millisecond)
+	defer ticker.Stop()
+	deadline := time.After(timeout)
+	for {
+		select {
+		case <-ctx.Done():
+			return "", ctx.Err()
+		case <-deadline:
+			return "", fmt.Errorf("task %s timed out", taskID)
+		case <-ticker.C:
+			task, err := store.GetTask(ctx, taskID)
+			if err != nil {
+				return "", err
+			}
+			switch task.Status {
+			case "completed":
+				return task.Result, nil
+			case "failed":
+				return "", fmt.Errorf("task %s failed: %s", taskID, task.Error)
+			}
+		}
+	}
+}
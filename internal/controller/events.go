@@ -0,0 +1,36 @@
+package controller
+
+import (
+	"fmt"
+
+	"github.com/eachlabs/klaw/internal/notify"
+)
+
+// taskEvent returns the webhook event of a finished task.
+func taskEvent(t *Task) notify.Event {
+	ev := notify.Event{
+		Type:    notify.EventTaskCompleted,
+		Agent:   t.AgentName,
+		Task:    t.ID,
+		Node:    t.NodeID,
+		Result:  t.Result,
+		Message: fmt.Sprintf("Task %s of agent %s completed", t.ID, t.AgentName),
+	}
+	if t.Status == "failed" {
+		ev.Type = notify.EventTaskFailed
+		ev.Error = t.Error
+		ev.Message = fmt.Sprintf("Task %s of agent %s failed", t.ID, t.AgentName)
+	}
+	return ev
+}
+
+// nodeDownEvent returns the webhook event of a node that stopped
+// responding or dropped its connection.
+func nodeDownEvent(name, reason string) notify.Event {
+	return notify.Event{
+		Type:    notify.EventNodeDown,
+		Node:    name,
+		Error:   reason,
+		Message: fmt.Sprintf("Node %s is down", name),
+	}
+}
@@ -0,0 +1,138 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/eachlabs/klaw/internal/controller/pb"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+)
+
+// Client queries the state of a remote controller over gRPC.
+type Client struct {
+	address string
+	conn    *grpc.ClientConn
+	client  pb.ControllerServiceClient
+}
+
+// NewClient creates a client for the controller at address. The connection
+// is made on first use.
+func NewClient(address string) (*Client, error) {
+	conn, err := grpc.NewClient(address, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to controller: %w", err)
+	}
+	return &Client{
+		address: address,
+		conn:    conn,
+		client:  pb.NewControllerServiceClient(conn),
+	}, nil
+}
+
+// Address returns the address of the controller.
+func (c *Client) Address() string {
+	return c.address
+}
+
+// Close closes the connection.
+func (c *Client) Close() error {
+	return c.conn.Close()
+}
+
+// ListNodes returns the nodes registered with the controller.
+func (c *Client) ListNodes(ctx context.Context) ([]*Node, error) {
+	resp, err := c.client.ListNodes(ctx, &pb.ListNodesRequest{})
+	if err != nil {
+		return nil, err
+	}
+	nodes := make([]*Node, len(resp.Nodes))
+	for i, n := range resp.Nodes {
+		nodes[i] = nodeFromProto(n)
+	}
+	return nodes, nil
+}
+
+// ListAgents returns the agents running on the controller's nodes.
+func (c *Client) ListAgents(ctx context.Context) ([]*Agent, error) {
+	resp, err := c.client.ListAgents(ctx, &pb.ListAgentsRequest{})
+	if err != nil {
+		return nil, err
+	}
+	agents := make([]*Agent, len(resp.Agents))
+	for i, a := range resp.Agents {
+		agents[i] = agentFromProto(a)
+	}
+	return agents, nil
+}
+
+// ListTasks returns the controller's tasks, newest first, optionally
+// filtered by status and agent name.
+func (c *Client) ListTasks(ctx context.Context, status, agentName string) ([]*Task, error) {
+	resp, err := c.client.ListTasks(ctx, &pb.ListTasksRequest{Status: status, AgentName: agentName})
+	if err != nil {
+		return nil, err
+	}
+	tasks := make([]*Task, len(resp.Tasks))
+	for i, t := range resp.Tasks {
+		tasks[i] = taskFromProto(t)
+	}
+	return tasks, nil
+}
+
+func nodeFromProto(n *pb.Node) *Node {
+	return &Node{
+		ID:       n.Id,
+		Name:     n.Name,
+		Address:  n.Address,
+		Labels:   n.Labels,
+		Status:   n.Status,
+		Version:  n.Version,
+		JoinedAt: time.Unix(n.JoinedAt, 0),
+		LastSeen: time.Unix(n.LastSeen, 0),
+		AgentIDs: n.AgentIds,
+	}
+}
+
+func agentFromProto(a *pb.Agent) *Agent {
+	return &Agent{
+		ID:          a.Id,
+		Name:        a.Name,
+		NodeID:      a.NodeId,
+		Cluster:     a.Cluster,
+		Namespace:   a.Namespace,
+		Description: a.Description,
+		Model:       a.Model,
+		Skills:      a.Skills,
+		Status:      a.Status,
+		CreatedAt:   time.Unix(a.CreatedAt, 0),
+		LastActive:  time.Unix(a.LastActive, 0),
+	}
+}
+
+func taskFromProto(t *pb.Task) *Task {
+	task := &Task{
+		ID:        t.Id,
+		Type:      t.Type,
+		AgentID:   t.AgentId,
+		AgentName: t.AgentName,
+		NodeID:    t.NodeId,
+		Prompt:    t.Prompt,
+		Priority:  int(t.Priority),
+		Status:    t.Status,
+		Result:    t.Result,
+		Error:     t.Error,
+		CreatedAt: time.Unix(t.CreatedAt, 0),
+	}
+	task.Metadata, task.Events = eventsFromMetadata(t.Metadata)
+	if t.StartedAt != 0 {
+		started := time.Unix(t.StartedAt, 0)
+		task.StartedAt = &started
+	}
+	if t.FinishedAt != 0 {
+		finished := time.Unix(t.FinishedAt, 0)
+		task.FinishedAt = &finished
+	}
+	return task
+}
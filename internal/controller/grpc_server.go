@@ -5,10 +5,13 @@ import (
 	"fmt"
 	"io"
 	"net"
+	"sort"
 	"sync"
 	"time"
 
 	"github.com/eachlabs/klaw/internal/controller/pb"
+	"github.com/eachlabs/klaw/internal/priority"
+	"github.com/eachlabs/klaw/internal/scheduler"
 	"github.com/google/uuid"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
@@ -32,6 +35,12 @@ type GRPCServer struct {
 	taskResults   map[string]chan *pb.TaskMessage
 	taskResultsMu sync.RWMutex
 
+	balancer *balancer
+
+	// Followers of running tasks, by task ID
+	taskWatchers   map[string][]chan *pb.TaskMessage
+	taskWatchersMu sync.Mutex
+
 	ctx    context.Context
 	cancel context.CancelFunc
 	wg     sync.WaitGroup
@@ -66,8 +75,11 @@ func NewGRPCServer(cfg ServerConfig) (*GRPCServer, error) {
 		store:       store,
 		nodeStreams: make(map[string]*nodeStream),
 		taskResults: make(map[string]chan *pb.TaskMessage),
-		ctx:         ctx,
-		cancel:      cancel,
+		balancer:    newBalancer(cfg.LoadBalancing),
+
+		taskWatchers: make(map[string][]chan *pb.TaskMessage),
+		ctx:          ctx,
+		cancel:       cancel,
 	}, nil
 }
 
@@ -90,6 +102,12 @@ func (s *GRPCServer) Start() error {
 	s.wg.Add(1)
 	go s.heartbeatChecker()
 
+	if s.config.Scheduler {
+		if _, err := startScheduler(s.ctx, s.config, s.runJob); err != nil {
+			return err
+		}
+	}
+
 	fmt.Printf("🚀 Klaw gRPC Controller started on %s\n", addr)
 	fmt.Println()
 	fmt.Println("Waiting for nodes to connect...")
@@ -146,6 +164,7 @@ func (s *GRPCServer) Heartbeat(ctx context.Context, req *pb.HeartbeatRequest) (*
 	node, err := s.store.GetNode(ctx, req.NodeId)
 	if err == nil {
 		node.LastSeen = time.Now()
+		node.Status = "ready"
 		_ = s.store.SaveNode(ctx, node)
 	}
 
@@ -204,8 +223,16 @@ func (s *GRPCServer) TaskStream(stream pb.ControllerService_TaskStreamServer) er
 		return err
 	}
 
+	// Clients follow the tool calls of a task on its own stream
+	if msg.Type == "follow" {
+		if s.config.AuthToken != "" && msg.Metadata["token"] != s.config.AuthToken {
+			return status.Error(codes.Unauthenticated, "invalid token")
+		}
+		return s.followTask(stream, msg.TaskId)
+	}
+
 	if msg.Type != "connect" {
-		return status.Error(codes.InvalidArgument, "first message must be connect")
+		return status.Error(codes.InvalidArgument, "first message must be connect or follow")
 	}
 
 	nodeID := msg.TaskId // Reusing TaskId field for nodeID in connect message
@@ -223,6 +250,7 @@ func (s *GRPCServer) TaskStream(stream pb.ControllerService_TaskStreamServer) er
 		s.nodeStreamsMu.Lock()
 		delete(s.nodeStreams, nodeID)
 		s.nodeStreamsMu.Unlock()
+		s.balancer.forget(nodeID)
 	}()
 
 	// Handle incoming messages (results from node)
@@ -232,11 +260,21 @@ func (s *GRPCServer) TaskStream(stream pb.ControllerService_TaskStreamServer) er
 			return nil
 		}
 		if err != nil {
+			// The node went away without closing its stream
+			if s.ctx.Err() == nil {
+				name := nodeID
+				if node, err := s.store.GetNode(s.ctx, nodeID); err == nil {
+					name = node.Name
+				}
+				s.config.Notifier.Notify(nodeDownEvent(name, err.Error()))
+			}
 			return err
 		}
 
 		switch msg.Type {
 		case "result":
+			s.balancer.finished(nodeID)
+
 			// Forward result to waiting dispatch
 			s.taskResultsMu.RLock()
 			if ch, ok := s.taskResults[msg.TaskId]; ok {
@@ -248,6 +286,7 @@ func (s *GRPCServer) TaskStream(stream pb.ControllerService_TaskStreamServer) er
 			s.taskResultsMu.RUnlock()
 
 			// Update task in store
+			s.taskWatchersMu.Lock()
 			task, err := s.store.GetTask(s.ctx, msg.TaskId)
 			if err == nil {
 				now := time.Now()
@@ -260,7 +299,16 @@ func (s *GRPCServer) TaskStream(stream pb.ControllerService_TaskStreamServer) er
 					task.Result = msg.Result
 				}
 				_ = s.store.SaveTask(s.ctx, task)
+				s.config.Notifier.Notify(taskEvent(task))
 			}
+			s.notifyWatchers(msg)
+			s.taskWatchersMu.Unlock()
+
+		case EventToolCall, EventToolResult:
+			s.taskWatchersMu.Lock()
+			recordTaskEvent(s.ctx, s.store, msg.TaskId, ToolEventFromMessage(msg))
+			s.notifyWatchers(msg)
+			s.taskWatchersMu.Unlock()
 
 		case "progress":
 			// Forward progress update
@@ -272,6 +320,11 @@ func (s *GRPCServer) TaskStream(stream pb.ControllerService_TaskStreamServer) er
 				}
 			}
 			s.taskResultsMu.RUnlock()
+			updateTaskProgress(s.ctx, s.store, msg.TaskId, msg.Status)
+
+			s.taskWatchersMu.Lock()
+			s.notifyWatchers(msg)
+			s.taskWatchersMu.Unlock()
 
 		case "heartbeat":
 			s.nodeStreamsMu.Lock()
@@ -291,39 +344,31 @@ func (s *GRPCServer) DispatchTask(ctx context.Context, req *pb.DispatchTaskReque
 		return &pb.DispatchTaskResponse{Error: "invalid token"}, nil
 	}
 
-	// Find agent on a connected node
+	// Find a replica of the agent on a healthy node
 	agents, err := s.store.ListAgents(ctx)
 	if err != nil {
 		return &pb.DispatchTaskResponse{Error: err.Error()}, nil
 	}
 
-	s.nodeStreamsMu.RLock()
-	connectedNodes := make(map[string]bool)
-	for nodeID := range s.nodeStreams {
-		connectedNodes[nodeID] = true
-	}
-	s.nodeStreamsMu.RUnlock()
-
-	var agent *Agent
-	for _, a := range agents {
-		if a.Name == req.AgentName && a.Status == "running" && connectedNodes[a.NodeID] {
-			agent = a
-			break
-		}
-	}
-
+	agent := s.balancer.pick(agents, req.AgentName, s.nodeHealthy)
 	if agent == nil {
 		return &pb.DispatchTaskResponse{Error: "agent not found or no connected node running it"}, nil
 	}
 
+	level, err := priority.Parse(req.Metadata[MetaPriority])
+	if err != nil {
+		return &pb.DispatchTaskResponse{Error: err.Error()}, nil
+	}
+
 	// Create task
 	task := &Task{
 		ID:        uuid.New().String()[:8],
-		Type:      "message",
+		Type:      taskType(req.Metadata),
 		AgentID:   agent.ID,
 		AgentName: agent.Name,
 		NodeID:    agent.NodeID,
 		Prompt:    req.Prompt,
+		Priority:  int(level),
 		Status:    "pending",
 		CreatedAt: time.Now(),
 		Metadata:  req.Metadata,
@@ -361,7 +406,14 @@ func (s *GRPCServer) DispatchTask(ctx context.Context, req *pb.DispatchTaskReque
 		}()
 	}
 
-	// Send task to node
+	// Save the task as dispatched before the node can report on it
+	now := time.Now()
+	task.Status = "dispatched"
+	task.StartedAt = &now
+	_ = s.store.SaveTask(ctx, task)
+
+	// Send task to node, counted before its result can come back
+	s.balancer.started(agent.NodeID)
 	err = ns.stream.Send(&pb.TaskMessage{
 		Type:      "task",
 		TaskId:    task.ID,
@@ -370,17 +422,13 @@ func (s *GRPCServer) DispatchTask(ctx context.Context, req *pb.DispatchTaskReque
 		Metadata:  req.Metadata,
 	})
 	if err != nil {
+		s.balancer.finished(agent.NodeID)
 		task.Status = "failed"
 		task.Error = err.Error()
 		_ = s.store.SaveTask(ctx, task)
 		return &pb.DispatchTaskResponse{Error: err.Error()}, nil
 	}
 
-	now := time.Now()
-	task.Status = "dispatched"
-	task.StartedAt = &now
-	_ = s.store.SaveTask(ctx, task)
-
 	if !req.Wait {
 		return &pb.DispatchTaskResponse{
 			TaskId: task.ID,
@@ -394,26 +442,50 @@ func (s *GRPCServer) DispatchTask(ctx context.Context, req *pb.DispatchTaskReque
 		timeout = 5 * time.Minute
 	}
 
-	select {
-	case <-time.After(timeout):
-		return &pb.DispatchTaskResponse{
-			TaskId: task.ID,
-			Status: "timeout",
-			Error:  "task timed out",
-		}, nil
-
-	case msg := <-resultCh:
-		if msg.Type == "result" {
+	// Progress updates of the task come before its result
+	deadline := time.After(timeout)
+	for {
+		select {
+		case <-deadline:
 			return &pb.DispatchTaskResponse{
 				TaskId: task.ID,
-				Status: "completed",
-				Result: msg.Result,
-				Error:  msg.Error,
+				Status: "timeout",
+				Error:  "task timed out",
 			}, nil
+
+		case msg := <-resultCh:
+			if msg.Type == "result" {
+				return &pb.DispatchTaskResponse{
+					TaskId: task.ID,
+					Status: "completed",
+					Result: msg.Result,
+					Error:  msg.Error,
+				}, nil
+			}
 		}
 	}
+}
 
-	return &pb.DispatchTaskResponse{TaskId: task.ID, Status: "unknown"}, nil
+// runJob runs a cluster-wide cron job as a task on a node with its agent.
+func (s *GRPCServer) runJob(ctx context.Context, job *scheduler.Job) (string, error) {
+	resp, err := s.DispatchTask(ctx, &pb.DispatchTaskRequest{
+		Token:          s.config.AuthToken,
+		AgentName:      job.Agent,
+		Prompt:         job.Task,
+		Metadata:       jobMetadata(job),
+		Wait:           true,
+		TimeoutSeconds: int32(jobTimeout / time.Second),
+	})
+	if err != nil {
+		return "", err
+	}
+	if resp.Error != "" {
+		return "", fmt.Errorf("%s", resp.Error)
+	}
+	if resp.Status != "completed" {
+		return "", fmt.Errorf("task %s %s", resp.TaskId, resp.Status)
+	}
+	return resp.Result, nil
 }
 
 func (s *GRPCServer) GetTaskStatus(ctx context.Context, req *pb.GetTaskStatusRequest) (*pb.GetTaskStatusResponse, error) {
@@ -466,10 +538,11 @@ func (s *GRPCServer) ListAgents(ctx context.Context, req *pb.ListAgentsRequest)
 }
 
 func (s *GRPCServer) ListTasks(ctx context.Context, req *pb.ListTasksRequest) (*pb.ListTasksResponse, error) {
-	tasks, err := s.store.ListPendingTasks(ctx)
+	tasks, err := s.store.ListTasks(ctx)
 	if err != nil {
 		return nil, err
 	}
+	sort.Slice(tasks, func(i, j int) bool { return tasks[i].CreatedAt.After(tasks[j].CreatedAt) })
 
 	// Filter
 	var filtered []*Task
@@ -506,11 +579,14 @@ func (s *GRPCServer) heartbeatChecker() {
 		case <-ticker.C:
 			s.nodeStreamsMu.Lock()
 			for nodeID, ns := range s.nodeStreams {
-				if time.Since(ns.lastSeen) > 60*time.Second {
+				if time.Since(ns.lastSeen) > nodeTimeout {
 					fmt.Printf("⚠️  Node not responding: %s\n", nodeID)
 					// Update node status
 					node, err := s.store.GetNode(s.ctx, nodeID)
 					if err == nil {
+						if node.Status != "not-ready" {
+							s.config.Notifier.Notify(nodeDownEvent(node.Name, "no heartbeat for 60s"))
+						}
 						node.Status = "not-ready"
 						_ = s.store.SaveNode(s.ctx, node)
 					}
@@ -521,6 +597,15 @@ func (s *GRPCServer) heartbeatChecker() {
 	}
 }
 
+// nodeHealthy reports whether a node is connected and sent a heartbeat
+// recently enough to be sent tasks.
+func (s *GRPCServer) nodeHealthy(nodeID string) bool {
+	s.nodeStreamsMu.RLock()
+	defer s.nodeStreamsMu.RUnlock()
+	ns, ok := s.nodeStreams[nodeID]
+	return ok && time.Since(ns.lastSeen) <= nodeTimeout
+}
+
 func nodeToProto(n *Node) *pb.Node {
 	return &pb.Node{
 		Id:       n.ID,
@@ -563,7 +648,7 @@ func taskToProto(t *Task) *pb.Task {
 		Status:    t.Status,
 		Result:    t.Result,
 		Error:     t.Error,
-		Metadata:  t.Metadata,
+		Metadata:  eventsMetadata(t),
 		CreatedAt: t.CreatedAt.Unix(),
 	}
 	if t.StartedAt != nil {
@@ -35,6 +35,12 @@ type Message struct {
 	Result string `json:"result,omitempty"`
 	Status string `json:"status,omitempty"`
 
+	Priority int `json:"priority,omitempty"` // a priority.Level; higher runs first
+
+	// Tool event (type = EventToolCall or EventToolResult)
+	Tool  string `json:"tool,omitempty"`
+	Input string `json:"input,omitempty"`
+
 	// Error
 	Error string `json:"error,omitempty"`
 }
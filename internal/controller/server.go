@@ -8,6 +8,10 @@ import (
 	"sync"
 	"time"
 
+	"github.com/eachlabs/klaw/internal/lease"
+	"github.com/eachlabs/klaw/internal/notify"
+	"github.com/eachlabs/klaw/internal/priority"
+	"github.com/eachlabs/klaw/internal/scheduler"
 	"github.com/google/uuid"
 )
 
@@ -25,6 +29,8 @@ type Server struct {
 	taskChans   map[string]chan *Task
 	taskChansMu sync.RWMutex
 
+	balancer *balancer
+
 	ctx    context.Context
 	cancel context.CancelFunc
 	wg     sync.WaitGroup
@@ -54,6 +60,18 @@ type ServerConfig struct {
 	TLSEnabled bool
 	TLSCert    string
 	TLSKey     string
+	Notifier   *notify.Notifier // webhooks of finished tasks and nodes going down
+
+	// LoadBalancing spreads the tasks of agents registered on several
+	// nodes: RoundRobin or LeastLoaded (the default)
+	LoadBalancing string
+
+	// Scheduler runs the cluster-wide cron jobs of SchedulerDir, as tasks,
+	// while this controller holds the scheduler lease of Leases (by
+	// default a lease file in DataDir) as InstanceID.
+	Scheduler  bool
+	Leases     lease.Store
+	InstanceID string
 }
 
 // NewServer creates a new controller server
@@ -82,6 +100,7 @@ func NewServer(cfg ServerConfig) (*Server, error) {
 		store:     store,
 		nodes:     make(map[string]*connectedNode),
 		taskChans: make(map[string]chan *Task),
+		balancer:  newBalancer(cfg.LoadBalancing),
 		ctx:       ctx,
 		cancel:    cancel,
 	}, nil
@@ -100,6 +119,12 @@ func (s *Server) Start() error {
 	s.wg.Add(1)
 	go s.heartbeatChecker()
 
+	if s.config.Scheduler {
+		if _, err := startScheduler(s.ctx, s.config, s.runJob); err != nil {
+			return err
+		}
+	}
+
 	fmt.Printf("🚀 Klaw Controller started on %s\n", addr)
 	fmt.Println()
 	fmt.Println("Waiting for nodes to connect...")
@@ -221,9 +246,13 @@ func (s *Server) handleConnection(conn net.Conn) {
 	delete(s.taskChans, node.ID)
 	close(taskChan)
 	s.taskChansMu.Unlock()
+	s.balancer.forget(node.ID)
 
 	node.Status = "disconnected"
 	_ = s.store.SaveNode(s.ctx, node)
+	if s.ctx.Err() == nil {
+		s.config.Notifier.Notify(nodeDownEvent(node.Name, "disconnected"))
+	}
 
 	fmt.Printf("❌ Node disconnected: %s (%s)\n", node.Name, node.ID)
 }
@@ -234,10 +263,11 @@ func (s *Server) handleNodeSession(node *Node, conn net.Conn, decoder *messageDe
 	go func() {
 		for task := range taskChan {
 			_ = encoder.Encode(&Message{
-				Type:   "task",
-				TaskID: task.ID,
-				Prompt: task.Prompt,
-				Agent:  task.AgentName,
+				Type:     "task",
+				TaskID:   task.ID,
+				Prompt:   task.Prompt,
+				Agent:    task.AgentName,
+				Priority: task.Priority,
 			})
 		}
 	}()
@@ -258,6 +288,7 @@ func (s *Server) handleNodeSession(node *Node, conn net.Conn, decoder *messageDe
 			s.nodesMu.Unlock()
 
 			node.LastSeen = time.Now()
+			node.Status = "ready"
 			_ = s.store.SaveNode(s.ctx, node)
 
 			_ = encoder.Encode(&Message{Type: "heartbeat_ack"})
@@ -297,7 +328,18 @@ func (s *Server) handleNodeSession(node *Node, conn net.Conn, decoder *messageDe
 			_ = s.store.DeleteAgent(s.ctx, msg.AgentID)
 			_ = encoder.Encode(&Message{Type: "agent_deregistered"})
 
+		case "task_progress":
+			updateTaskProgress(s.ctx, s.store, msg.TaskID, msg.Status)
+
+		case EventToolCall, EventToolResult:
+			ev := TaskEvent{Time: time.Now(), Type: msg.Type, Tool: msg.Tool, Input: msg.Input, Output: msg.Result}
+			if msg.Error != "" {
+				ev.Output, ev.IsError = msg.Error, true
+			}
+			recordTaskEvent(s.ctx, s.store, msg.TaskID, ev)
+
 		case "task_result":
+			s.balancer.finished(node.ID)
 			task, err := s.store.GetTask(s.ctx, msg.TaskID)
 			if err == nil {
 				now := time.Now()
@@ -310,11 +352,23 @@ func (s *Server) handleNodeSession(node *Node, conn net.Conn, decoder *messageDe
 					task.Result = msg.Result
 				}
 				_ = s.store.SaveTask(s.ctx, task)
+				s.config.Notifier.Notify(taskEvent(task))
 			}
 		}
 	}
 }
 
+// updateTaskProgress records the status a node reported for a task it
+// queued or started, unless the task already finished.
+func updateTaskProgress(ctx context.Context, store Store, taskID, status string) {
+	task, err := store.GetTask(ctx, taskID)
+	if err != nil || task.FinishedAt != nil || status == "" {
+		return
+	}
+	task.Status = status
+	_ = store.SaveTask(ctx, task)
+}
+
 // heartbeatChecker checks for dead nodes
 func (s *Server) heartbeatChecker() {
 	defer s.wg.Done()
@@ -329,7 +383,10 @@ func (s *Server) heartbeatChecker() {
 		case <-ticker.C:
 			s.nodesMu.Lock()
 			for id, cn := range s.nodes {
-				if time.Since(cn.lastPing) > 60*time.Second {
+				if time.Since(cn.lastPing) > nodeTimeout {
+					if cn.node.Status != "not-ready" {
+						s.config.Notifier.Notify(nodeDownEvent(cn.node.Name, "no heartbeat for 60s"))
+					}
 					cn.node.Status = "not-ready"
 					_ = s.store.SaveNode(s.ctx, cn.node)
 					fmt.Printf("⚠️  Node not responding: %s\n", cn.node.Name)
@@ -343,43 +400,31 @@ func (s *Server) heartbeatChecker() {
 
 // DispatchTask sends a task to the appropriate node
 func (s *Server) DispatchTask(ctx context.Context, agentName, prompt string, metadata map[string]string) (*Task, error) {
-	// Find agent on a connected node
+	// Find a replica of the agent on a healthy node
 	agents, err := s.store.ListAgents(ctx)
 	if err != nil {
 		return nil, err
 	}
 
-	// Get connected nodes
-	s.taskChansMu.RLock()
-	connectedNodes := make(map[string]bool)
-	for nodeID := range s.taskChans {
-		connectedNodes[nodeID] = true
-	}
-	s.taskChansMu.RUnlock()
-
-	var agent *Agent
-	for _, a := range agents {
-		if a.Name == agentName && a.Status == "running" {
-			// Only select if node is connected
-			if connectedNodes[a.NodeID] {
-				agent = a
-				break
-			}
-		}
-	}
-
+	agent := s.balancer.pick(agents, agentName, s.nodeHealthy)
 	if agent == nil {
 		return nil, fmt.Errorf("agent not found or no connected node running it: %s", agentName)
 	}
 
+	level, err := priority.Parse(metadata[MetaPriority])
+	if err != nil {
+		return nil, err
+	}
+
 	// Create task
 	task := &Task{
 		ID:        uuid.New().String()[:8],
-		Type:      "message",
+		Type:      taskType(metadata),
 		AgentID:   agent.ID,
 		AgentName: agent.Name,
 		NodeID:    agent.NodeID,
 		Prompt:    prompt,
+		Priority:  int(level),
 		Status:    "pending",
 		CreatedAt: time.Now(),
 		Metadata:  metadata,
@@ -406,11 +451,30 @@ func (s *Server) DispatchTask(ctx context.Context, agentName, prompt string, met
 	task.StartedAt = &now
 	_ = s.store.SaveTask(ctx, task)
 
+	s.balancer.started(agent.NodeID)
 	taskChan <- task
 
 	return task, nil
 }
 
+// runJob runs a cluster-wide cron job as a task on a node with its agent.
+func (s *Server) runJob(ctx context.Context, job *scheduler.Job) (string, error) {
+	task, err := s.DispatchTask(ctx, job.Agent, job.Task, jobMetadata(job))
+	if err != nil {
+		return "", err
+	}
+	return waitTask(ctx, s.store, task.ID, jobTimeout)
+}
+
+// nodeHealthy reports whether a node is connected and sent a heartbeat
+// recently enough to be sent tasks.
+func (s *Server) nodeHealthy(nodeID string) bool {
+	s.nodesMu.RLock()
+	defer s.nodesMu.RUnlock()
+	cn, ok := s.nodes[nodeID]
+	return ok && time.Since(cn.lastPing) <= nodeTimeout
+}
+
 // GetNodes returns all registered nodes
 func (s *Server) GetNodes(ctx context.Context) ([]*Node, error) {
 	return s.store.ListNodes(ctx)
@@ -423,7 +487,7 @@ func (s *Server) GetAgents(ctx context.Context) ([]*Agent, error) {
 
 // GetTasks returns all tasks
 func (s *Server) GetTasks(ctx context.Context) ([]*Task, error) {
-	return s.store.ListPendingTasks(ctx)
+	return s.store.ListTasks(ctx)
 }
 
 // handleDispatch handles a dispatch request from CLI
@@ -437,7 +501,11 @@ func (s *Server) handleDispatch(conn net.Conn, msg *Message, encoder *messageEnc
 	fmt.Printf("📥 Dispatch request: agent=%s\n", msg.Agent)
 
 	// Dispatch the task
-	task, err := s.DispatchTask(s.ctx, msg.Agent, msg.Prompt, nil)
+	var metadata map[string]string
+	if msg.Priority != 0 {
+		metadata = map[string]string{MetaPriority: priority.Level(msg.Priority).String()}
+	}
+	task, err := s.DispatchTask(s.ctx, msg.Agent, msg.Prompt, metadata)
 	if err != nil {
 		_ = encoder.Encode(&Message{Type: "error", Error: err.Error()})
 		return
@@ -28,6 +28,7 @@ type Store interface {
 
 	// Tasks
 	GetTask(ctx context.Context, id string) (*Task, error)
+	ListTasks(ctx context.Context) ([]*Task, error)
 	ListPendingTasks(ctx context.Context) ([]*Task, error)
 	SaveTask(ctx context.Context, task *Task) error
 	DeleteTask(ctx context.Context, id string) error
@@ -89,6 +90,10 @@ type Agent struct {
 	LastActive   time.Time `json:"last_active"`
 }
 
+// MetaPriority is the task metadata key carrying the priority over gRPC,
+// whose task messages have no priority field.
+const MetaPriority = "priority"
+
 // Task represents a task to be executed by an agent
 type Task struct {
 	ID         string        `json:"id"`
@@ -97,15 +102,16 @@ type Task struct {
 	AgentName  string        `json:"agent_name"`
 	NodeID     string        `json:"node_id"`
 	Prompt     string        `json:"prompt"`
-	Priority   int           `json:"priority"`
+	Priority   int           `json:"priority"` // a priority.Level; higher runs first
 	Timeout    time.Duration `json:"timeout"`
-	Status     string        `json:"status"` // "pending", "dispatched", "running", "completed", "failed"
+	Status     string        `json:"status"` // "pending", "dispatched", "queued", "running", "completed", "failed"
 	Result     string        `json:"result,omitempty"`
 	Error      string        `json:"error,omitempty"`
 	CreatedAt  time.Time     `json:"created_at"`
 	StartedAt  *time.Time    `json:"started_at,omitempty"`
 	FinishedAt *time.Time    `json:"finished_at,omitempty"`
 	Metadata   map[string]string `json:"metadata,omitempty"`
+	Events     []TaskEvent       `json:"events,omitempty"` // tool calls and results, as they happen
 }
 
 // ============================================================================
@@ -316,6 +322,17 @@ func (fs *FileStore) GetTask(ctx context.Context, id string) (*Task, error) {
 	return task, nil
 }
 
+func (fs *FileStore) ListTasks(ctx context.Context) ([]*Task, error) {
+	fs.mu.RLock()
+	defer fs.mu.RUnlock()
+
+	tasks := make([]*Task, 0, len(fs.tasks))
+	for _, t := range fs.tasks {
+		tasks = append(tasks, t)
+	}
+	return tasks, nil
+}
+
 func (fs *FileStore) ListPendingTasks(ctx context.Context) ([]*Task, error) {
 	fs.mu.RLock()
 	defer fs.mu.RUnlock()
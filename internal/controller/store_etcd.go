@@ -190,6 +190,22 @@ func (es *EtcdStore) GetTask(ctx context.Context, id string) (*Task, error) {
 	return &task, nil
 }
 
+func (es *EtcdStore) ListTasks(ctx context.Context) ([]*Task, error) {
+	resp, err := es.client.Get(ctx, tasksPrefix, clientv3.WithPrefix())
+	if err != nil {
+		return nil, err
+	}
+
+	var tasks []*Task
+	for _, kv := range resp.Kvs {
+		var task Task
+		if err := json.Unmarshal(kv.Value, &task); err == nil {
+			tasks = append(tasks, &task)
+		}
+	}
+	return tasks, nil
+}
+
 func (es *EtcdStore) ListPendingTasks(ctx context.Context) ([]*Task, error) {
 	resp, err := es.client.Get(ctx, tasksPrefix, clientv3.WithPrefix())
 	if err != nil {
@@ -0,0 +1,216 @@
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+	"unicode/utf8"
+
+	"github.com/eachlabs/klaw/internal/controller/pb"
+)
+
+// Task message types of the tool calls of a running task, sent by nodes on
+// the task stream and passed on to followers of the task
+const (
+	EventToolCall   = "tool_call"
+	EventToolResult = "tool_result"
+)
+
+// Task stream metadata keys of a tool event, and of the events of a task
+// listed over gRPC, whose messages have no fields for them
+const (
+	metaTool   = "tool"
+	metaInput  = "input"
+	metaEvents = "events"
+)
+
+// maxEventChars bounds the bytes of input and output kept of a tool event.
+const maxEventChars = 2000
+
+// TaskEvent is a tool call or tool result of a task.
+type TaskEvent struct {
+	Time    time.Time `json:"time"`
+	Type    string    `json:"type"` // EventToolCall or EventToolResult
+	Tool    string    `json:"tool"`
+	Input   string    `json:"input,omitempty"`  // of a call
+	Output  string    `json:"output,omitempty"` // of a result
+	IsError bool      `json:"is_error,omitempty"`
+}
+
+// IsToolEvent reports whether a task message type is a tool event.
+func IsToolEvent(msgType string) bool {
+	return msgType == EventToolCall || msgType == EventToolResult
+}
+
+// Truncated returns the event with its input and output cut to the length
+// kept of them.
+func (ev TaskEvent) Truncated() TaskEvent {
+	ev.Input, ev.Output = truncateEvent(ev.Input), truncateEvent(ev.Output)
+	return ev
+}
+
+// ToolEventMessage returns the task stream message of a tool event of a
+// task, truncating its input and output.
+func ToolEventMessage(taskID string, ev TaskEvent) *pb.TaskMessage {
+	ev = ev.Truncated()
+	msg := &pb.TaskMessage{
+		Type:     ev.Type,
+		TaskId:   taskID,
+		Metadata: map[string]string{metaTool: ev.Tool},
+	}
+	if ev.Input != "" {
+		msg.Metadata[metaInput] = ev.Input
+	}
+	if ev.IsError {
+		msg.Error = ev.Output
+	} else {
+		msg.Result = ev.Output
+	}
+	return msg
+}
+
+// ToolEventFromMessage reads the tool event of a task stream message.
+func ToolEventFromMessage(msg *pb.TaskMessage) TaskEvent {
+	ev := TaskEvent{
+		Time:   time.Now(),
+		Type:   msg.Type,
+		Tool:   msg.Metadata[metaTool],
+		Input:  msg.Metadata[metaInput],
+		Output: msg.Result,
+	}
+	if msg.Error != "" {
+		ev.Output, ev.IsError = msg.Error, true
+	}
+	return ev
+}
+
+// truncateEvent cuts s to maxEventChars bytes without splitting a
+// character.
+func truncateEvent(s string) string {
+	if len(s) <= maxEventChars {
+		return s
+	}
+	cut := maxEventChars
+	for cut > 0 && !utf8.RuneStart(s[cut]) {
+		cut--
+	}
+	return s[:cut] + "..."
+}
+
+// recordTaskEvent appends a tool event to its task.
+func recordTaskEvent(ctx context.Context, store Store, taskID string, ev TaskEvent) {
+	task, err := store.GetTask(ctx, taskID)
+	if err != nil || task.FinishedAt != nil {
+		return
+	}
+	task.Events = append(task.Events, ev)
+	_ = store.SaveTask(ctx, task)
+}
+
+// eventsMetadata returns the metadata of a task with its tool events, for
+// listing it over gRPC.
+func eventsMetadata(t *Task) map[string]string {
+	if len(t.Events) == 0 {
+		return t.Metadata
+	}
+	data, err := json.Marshal(t.Events)
+	if err != nil {
+		return t.Metadata
+	}
+	metadata := make(map[string]string, len(t.Metadata)+1)
+	for k, v := range t.Metadata {
+		metadata[k] = v
+	}
+	metadata[metaEvents] = string(data)
+	return metadata
+}
+
+// eventsFromMetadata splits the tool events off the metadata of a task
+// listed over gRPC.
+func eventsFromMetadata(metadata map[string]string) (map[string]string, []TaskEvent) {
+	data, ok := metadata[metaEvents]
+	if !ok {
+		return metadata, nil
+	}
+	var events []TaskEvent
+	_ = json.Unmarshal([]byte(data), &events)
+	rest := make(map[string]string, len(metadata)-1)
+	for k, v := range metadata {
+		if k != metaEvents {
+			rest[k] = v
+		}
+	}
+	return rest, events
+}
+
+// followTask streams the tool events, progress and result of a task to a
+// follower: the events so far, then new ones until the task finishes.
+func (s *GRPCServer) followTask(stream pb.ControllerService_TaskStreamServer, taskID string) error {
+	ch := make(chan *pb.TaskMessage, 64)
+
+	// Events are recorded and passed on under taskWatchersMu, so none is
+	// missed or sent twice between the replay and the live events
+	s.taskWatchersMu.Lock()
+	task, err := s.store.GetTask(s.ctx, taskID)
+	if err == nil && task.FinishedAt == nil {
+		s.taskWatchers[taskID] = append(s.taskWatchers[taskID], ch)
+	}
+	s.taskWatchersMu.Unlock()
+	if err != nil {
+		return stream.Send(&pb.TaskMessage{Type: "result", TaskId: taskID, Error: "task not found"})
+	}
+	defer s.unwatchTask(taskID, ch)
+
+	for _, ev := range task.Events {
+		if err := stream.Send(ToolEventMessage(taskID, ev)); err != nil {
+			return err
+		}
+	}
+	if task.FinishedAt != nil {
+		return stream.Send(&pb.TaskMessage{Type: "result", TaskId: taskID, Result: task.Result, Error: task.Error})
+	}
+
+	for {
+		select {
+		case msg := <-ch:
+			if err := stream.Send(msg); err != nil {
+				return err
+			}
+			if msg.Type == "result" {
+				return nil
+			}
+		case <-stream.Context().Done():
+			return nil
+		case <-s.ctx.Done():
+			return nil
+		}
+	}
+}
+
+// notifyWatchers passes a task message on to the followers of its task.
+// Followers too slow to keep up miss events, but not the result, which
+// the last place of their buffer is kept for. taskWatchersMu must be held.
+func (s *GRPCServer) notifyWatchers(msg *pb.TaskMessage) {
+	for _, ch := range s.taskWatchers[msg.TaskId] {
+		if msg.Type == "result" || len(ch) < cap(ch)-1 {
+			ch <- msg
+		}
+	}
+}
+
+func (s *GRPCServer) unwatchTask(taskID string, ch chan *pb.TaskMessage) {
+	s.taskWatchersMu.Lock()
+	defer s.taskWatchersMu.Unlock()
+	watchers := s.taskWatchers[taskID]
+	for i, w := range watchers {
+		if w == ch {
+			watchers = append(watchers[:i], watchers[i+1:]...)
+			break
+		}
+	}
+	if len(watchers) == 0 {
+		delete(s.taskWatchers, taskID)
+	} else {
+		s.taskWatchers[taskID] = watchers
+	}
+}
@@ -0,0 +1,38 @@
+package controller
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestToolEventMessage_RoundTrip(t *testing.T) {
+	call := TaskEvent{Type: EventToolCall, Tool: "bash", Input: `{"command":"ls"}`}
+	got := ToolEventFromMessage(ToolEventMessage("t1", call))
+	if got.Type != call.Type || got.Tool != call.Tool || got.Input != call.Input || got.IsError {
+		t.Errorf("call came back as %+v", got)
+	}
+
+	failed := TaskEvent{Type: EventToolResult, Tool: "bash", Output: strings.Repeat("é", maxEventChars), IsError: true}
+	got = ToolEventFromMessage(ToolEventMessage("t1", failed))
+	if !got.IsError || len(got.Output) > maxEventChars+3 || !strings.HasSuffix(got.Output, "é...") {
+		t.Errorf("failed result came back as %d bytes, error %v", len(got.Output), got.IsError)
+	}
+}
+
+func TestEventsMetadata_RoundTrip(t *testing.T) {
+	task := &Task{
+		Metadata: map[string]string{MetaPriority: "high"},
+		Events:   []TaskEvent{{Time: time.Unix(100, 0).UTC(), Type: EventToolResult, Tool: "read", Output: "ok"}},
+	}
+	metadata, events := eventsFromMetadata(eventsMetadata(task))
+	if len(metadata) != 1 || metadata[MetaPriority] != "high" {
+		t.Errorf("metadata = %v", metadata)
+	}
+	if len(events) != 1 || events[0] != task.Events[0] {
+		t.Errorf("events = %+v", events)
+	}
+	if _, ok := task.Metadata[metaEvents]; ok {
+		t.Error("eventsMetadata changed the task's metadata")
+	}
+}
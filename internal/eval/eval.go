@@ -0,0 +1,352 @@
+// Package eval runs evaluation suites against an agent: test cases of a
+// prompt and assertions about the reply and the tools used, checked with
+// substrings and regular expressions or scored by an LLM judge, so that
+// prompt and skill changes can be regression-tested.
+package eval
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"regexp"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/eachlabs/klaw/internal/agent"
+	"github.com/eachlabs/klaw/internal/provider"
+	"gopkg.in/yaml.v3"
+)
+
+// Suite is a set of test cases run against one agent.
+type Suite struct {
+	Name     string `yaml:"name" json:"name"`
+	Agent    string `yaml:"agent,omitempty" json:"agent,omitempty"`       // agent binding the cases run against; empty for the default agent
+	Provider string `yaml:"provider,omitempty" json:"provider,omitempty"` // overrides the agent's provider
+	Model    string `yaml:"model,omitempty" json:"model,omitempty"`       // overrides the agent's model
+	Cases    []Case `yaml:"cases" json:"cases"`
+}
+
+// Case is a prompt and what the agent's answer to it must satisfy.
+type Case struct {
+	Name         string      `yaml:"name" json:"name"`
+	Prompt       string      `yaml:"prompt" json:"prompt"`
+	AllowedTools []string    `yaml:"allowed_tools,omitempty" json:"allowed_tools,omitempty"` // nil: all the agent's tools
+	Expect       []Assertion `yaml:"expect" json:"expect"`
+}
+
+// Assertion is one check of a case. Exactly one field is set.
+type Assertion struct {
+	Contains    string `yaml:"contains,omitempty" json:"contains,omitempty"`         // the reply contains the text, ignoring case
+	NotContains string `yaml:"not_contains,omitempty" json:"not_contains,omitempty"` // the reply does not contain the text, ignoring case
+	Regex       string `yaml:"regex,omitempty" json:"regex,omitempty"`               // the reply matches the expression
+	NotRegex    string `yaml:"not_regex,omitempty" json:"not_regex,omitempty"`       // the reply does not match the expression
+	Tool        string `yaml:"tool,omitempty" json:"tool,omitempty"`                 // the tool was called
+	NoTool      string `yaml:"no_tool,omitempty" json:"no_tool,omitempty"`           // the tool was not called
+	Judge       string `yaml:"judge,omitempty" json:"judge,omitempty"`               // the judge finds the reply meets the criterion
+}
+
+// String describes the assertion, e.g. `regex "(?i)refund"`.
+func (a Assertion) String() string {
+	kind, value := a.kind()
+	return fmt.Sprintf("%s %q", kind, value)
+}
+
+func (a Assertion) kind() (kind, value string) {
+	for _, f := range []struct{ kind, value string }{
+		{"contains", a.Contains},
+		{"not_contains", a.NotContains},
+		{"regex", a.Regex},
+		{"not_regex", a.NotRegex},
+		{"tool", a.Tool},
+		{"no_tool", a.NoTool},
+		{"judge", a.Judge},
+	} {
+		if f.value != "" {
+			if kind != "" {
+				return "", ""
+			}
+			kind, value = f.kind, f.value
+		}
+	}
+	return kind, value
+}
+
+// Load reads a suite from a YAML file.
+func Load(path string) (*Suite, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	s, err := Parse(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	if s.Name == "" {
+		s.Name = path
+	}
+	return s, nil
+}
+
+// Parse parses and validates a suite.
+func Parse(data []byte) (*Suite, error) {
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	dec.KnownFields(true)
+	var s Suite
+	if err := dec.Decode(&s); err != nil {
+		return nil, err
+	}
+	if len(s.Cases) == 0 {
+		return nil, fmt.Errorf("suite has no cases")
+	}
+	for i := range s.Cases {
+		c := &s.Cases[i]
+		if c.Name == "" {
+			c.Name = fmt.Sprintf("case %d", i+1)
+		}
+		if strings.TrimSpace(c.Prompt) == "" {
+			return nil, fmt.Errorf("%s: prompt is required", c.Name)
+		}
+		if len(c.Expect) == 0 {
+			return nil, fmt.Errorf("%s: expect has no assertions", c.Name)
+		}
+		for j, a := range c.Expect {
+			kind, value := a.kind()
+			if kind == "" {
+				return nil, fmt.Errorf("%s: assertion %d must set exactly one of contains, not_contains, regex, not_regex, tool, no_tool, judge", c.Name, j+1)
+			}
+			if kind == "regex" || kind == "not_regex" {
+				if _, err := regexp.Compile(value); err != nil {
+					return nil, fmt.Errorf("%s: assertion %d: %w", c.Name, j+1, err)
+				}
+			}
+		}
+	}
+	return &s, nil
+}
+
+// NeedsJudge reports whether any case has a judge assertion.
+func (s *Suite) NeedsJudge() bool {
+	for _, c := range s.Cases {
+		for _, a := range c.Expect {
+			if a.Judge != "" {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Config configures a run.
+type Config struct {
+	// Agent is the agent the cases run with; Prompt is set per case.
+	Agent agent.RunOnceConfig
+
+	// Judge scores judge assertions. Without one they fail.
+	Judge      provider.Provider
+	JudgeModel string
+
+	// OnCase is called after each case, e.g. to show progress.
+	OnCase func(*CaseResult)
+}
+
+// Report is the outcome of a suite.
+type Report struct {
+	Suite    string        `json:"suite"`
+	Agent    string        `json:"agent,omitempty"`
+	Passed   int           `json:"passed"`
+	Failed   int           `json:"failed"`
+	Score    float64       `json:"score"` // share of passed checks, 0 to 1
+	Duration time.Duration `json:"duration"`
+	Cases    []*CaseResult `json:"cases"`
+}
+
+// CaseResult is the outcome of a case.
+type CaseResult struct {
+	Name     string        `json:"name"`
+	Passed   bool          `json:"passed"`
+	Reply    string        `json:"reply"`
+	Tools    []string      `json:"tools,omitempty"` // tools called, in order, including blocked calls
+	Error    string        `json:"error,omitempty"`
+	Checks   []Check       `json:"checks"`
+	Duration time.Duration `json:"duration"`
+}
+
+// Check is the outcome of an assertion.
+type Check struct {
+	Assertion string `json:"assertion"`
+	Passed    bool   `json:"passed"`
+	Detail    string `json:"detail,omitempty"`
+}
+
+// Run runs the cases of s one after another.
+func Run(ctx context.Context, s *Suite, cfg Config) *Report {
+	start := time.Now()
+	report := &Report{Suite: s.Name, Agent: cfg.Agent.AgentName}
+	checks, passedChecks := 0, 0
+	for _, c := range s.Cases {
+		res := runCase(ctx, c, cfg)
+		report.Cases = append(report.Cases, res)
+		if res.Passed {
+			report.Passed++
+		} else {
+			report.Failed++
+		}
+		for _, ch := range res.Checks {
+			checks++
+			if ch.Passed {
+				passedChecks++
+			}
+		}
+		if cfg.OnCase != nil {
+			cfg.OnCase(res)
+		}
+		if ctx.Err() != nil {
+			break
+		}
+	}
+	if checks > 0 {
+		report.Score = float64(passedChecks) / float64(checks)
+	}
+	report.Duration = time.Since(start)
+	return report
+}
+
+func runCase(ctx context.Context, c Case, cfg Config) *CaseResult {
+	start := time.Now()
+	res := &CaseResult{Name: c.Name}
+
+	// Record the tool calls, blocking those the case does not allow
+	var mu sync.Mutex
+	var blocked []string
+	run := cfg.Agent
+	run.Prompt = c.Prompt
+	run.Hooks = append(slices.Clone(cfg.Agent.Hooks), agent.HookFunc(func(ctx context.Context, ev *agent.HookEvent) error {
+		if ev.Point != agent.HookPreTool {
+			return nil
+		}
+		mu.Lock()
+		defer mu.Unlock()
+		res.Tools = append(res.Tools, ev.Tool.Name)
+		if c.AllowedTools != nil && !slices.Contains(c.AllowedTools, ev.Tool.Name) {
+			blocked = append(blocked, ev.Tool.Name)
+			return fmt.Errorf("%s is not allowed in this eval case", ev.Tool.Name)
+		}
+		return nil
+	}))
+
+	reply, err := agent.RunOnce(ctx, run)
+	res.Reply = reply
+	if err != nil {
+		res.Error = err.Error()
+	}
+
+	if c.AllowedTools != nil {
+		allowed := Check{Assertion: fmt.Sprintf("allowed_tools %s", strings.Join(c.AllowedTools, ", ")), Passed: len(blocked) == 0}
+		if !allowed.Passed {
+			allowed.Detail = "called " + strings.Join(blocked, ", ")
+		}
+		res.Checks = append(res.Checks, allowed)
+	}
+	for _, a := range c.Expect {
+		res.Checks = append(res.Checks, check(ctx, a, c, res, cfg))
+	}
+
+	res.Passed = res.Error == ""
+	for _, ch := range res.Checks {
+		res.Passed = res.Passed && ch.Passed
+	}
+	res.Duration = time.Since(start)
+	return res
+}
+
+// check evaluates an assertion against the outcome of a case.
+func check(ctx context.Context, a Assertion, c Case, res *CaseResult, cfg Config) Check {
+	out := Check{Assertion: a.String()}
+	reply := strings.ToLower(res.Reply)
+	switch {
+	case a.Contains != "":
+		out.Passed = strings.Contains(reply, strings.ToLower(a.Contains))
+	case a.NotContains != "":
+		out.Passed = !strings.Contains(reply, strings.ToLower(a.NotContains))
+	case a.Regex != "":
+		out.Passed = regexp.MustCompile(a.Regex).MatchString(res.Reply)
+	case a.NotRegex != "":
+		if m := regexp.MustCompile(a.NotRegex).FindString(res.Reply); m != "" {
+			out.Detail = fmt.Sprintf("matched %q", m)
+		} else {
+			out.Passed = true
+		}
+	case a.Tool != "":
+		out.Passed = slices.Contains(res.Tools, a.Tool)
+	case a.NoTool != "":
+		out.Passed = !slices.Contains(res.Tools, a.NoTool)
+	case a.Judge != "":
+		if cfg.Judge == nil {
+			out.Detail = "no judge configured"
+			break
+		}
+		passed, reason, err := judge(ctx, cfg.Judge, cfg.JudgeModel, c.Prompt, res, a.Judge)
+		if err != nil {
+			out.Detail = err.Error()
+			break
+		}
+		out.Passed, out.Detail = passed, reason
+	}
+	if !out.Passed && out.Detail == "" && (a.Tool != "" || a.NoTool != "") {
+		out.Detail = "tools called: " + strings.Join(res.Tools, ", ")
+		if len(res.Tools) == 0 {
+			out.Detail = "no tools called"
+		}
+	}
+	return out
+}
+
+const judgePrompt = `You are grading an AI agent's answer in an automated test.
+
+Task given to the agent:
+%s
+
+Tools the agent called: %s
+
+Agent's answer:
+%s
+
+Criterion: %s
+
+Does the answer meet the criterion? Answer ONLY with JSON: {"pass": true or false, "reason": "<one sentence>"}`
+
+// judge asks prov whether the reply of a case meets a criterion.
+func judge(ctx context.Context, prov provider.Provider, model, prompt string, res *CaseResult, criterion string) (bool, string, error) {
+	tools := strings.Join(res.Tools, ", ")
+	if tools == "" {
+		tools = "none"
+	}
+	resp, err := prov.Chat(ctx, &provider.ChatRequest{
+		Model:     model,
+		Messages:  []provider.Message{{Role: "user", Content: fmt.Sprintf(judgePrompt, prompt, tools, res.Reply, criterion)}},
+		MaxTokens: 512,
+	})
+	if err != nil {
+		return false, "", fmt.Errorf("judge: %w", err)
+	}
+	var text string
+	for _, block := range resp.Content {
+		if block.Type == "text" {
+			text += block.Text
+		}
+	}
+
+	var verdict struct {
+		Pass   bool   `json:"pass"`
+		Reason string `json:"reason"`
+	}
+	start, end := strings.Index(text, "{"), strings.LastIndex(text, "}")
+	if start < 0 || end < start || json.Unmarshal([]byte(text[start:end+1]), &verdict) != nil {
+		return false, "", fmt.Errorf("judge gave no verdict: %s", strings.TrimSpace(text))
+	}
+	return verdict.Pass, verdict.Reason, nil
+}
@@ -0,0 +1,115 @@
+package eval
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/eachlabs/klaw/internal/agent"
+	"github.com/eachlabs/klaw/internal/provider"
+	"github.com/eachlabs/klaw/internal/tool"
+)
+
+type stubTool struct{ name string }
+
+func (s stubTool) Name() string            { return s.name }
+func (s stubTool) Description() string     { return "stub" }
+func (s stubTool) Schema() json.RawMessage { return json.RawMessage(`{"type":"object"}`) }
+func (s stubTool) Execute(context.Context, json.RawMessage) (*tool.Result, error) {
+	return &tool.Result{Content: "ok"}, nil
+}
+
+func toolUse(name string) *provider.ChatResponse {
+	return &provider.ChatResponse{Content: []provider.ContentBlock{
+		{Type: "tool_use", ToolUse: &provider.ToolCall{ID: name, Name: name, Input: json.RawMessage(`{}`)}},
+	}}
+}
+
+func text(s string) *provider.ChatResponse {
+	return &provider.ChatResponse{Content: []provider.ContentBlock{{Type: "text", Text: s}}}
+}
+
+func TestRun(t *testing.T) {
+	suite, err := Parse([]byte(`
+name: support
+cases:
+  - name: refund
+    prompt: Refund order 42
+    allowed_tools: [order_lookup]
+    expect:
+      - tool: order_lookup
+      - contains: REFUND ISSUED
+      - regex: 'order \d+'
+      - judge: The reply confirms the refund
+  - prompt: Delete my account
+    allowed_tools: [order_lookup]
+    expect:
+      - no_tool: account_delete
+      - not_contains: deleted
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tools := tool.NewRegistry()
+	tools.Register(stubTool{"order_lookup"})
+	tools.Register(stubTool{"account_delete"})
+	judge := provider.NewMock(text(`{"pass": true, "reason": "It says the refund was issued."}`))
+
+	report := Run(context.Background(), suite, Config{
+		Agent: agent.RunOnceConfig{
+			Provider: provider.NewMock(
+				toolUse("order_lookup"), text("Refund issued for order 42."),
+				toolUse("account_delete"), text("Your account was deleted."),
+			),
+			Tools: tools,
+		},
+		Judge: judge,
+	})
+
+	if report.Passed != 1 || report.Failed != 1 || len(report.Cases) != 2 {
+		t.Fatalf("expected 1 passed and 1 failed case, got %+v", report)
+	}
+	refund := report.Cases[0]
+	if !refund.Passed || len(refund.Checks) != 5 {
+		t.Errorf("expected the refund case to pass all 5 checks, got %+v", refund)
+	}
+	if prompt := judge.Requests()[0].Messages[0].Content; !strings.Contains(prompt, "Refund issued for order 42.") || !strings.Contains(prompt, "Tools the agent called: order_lookup") {
+		t.Errorf("judge did not see the reply and tools: %s", prompt)
+	}
+
+	account := report.Cases[1]
+	if account.Name != "case 2" {
+		t.Errorf("expected a default name, got %q", account.Name)
+	}
+	var failed []string
+	for _, c := range account.Checks {
+		if !c.Passed {
+			failed = append(failed, c.Assertion)
+		}
+	}
+	// The disallowed call is blocked but still counts as called
+	want := []string{"allowed_tools order_lookup", `no_tool "account_delete"`, `not_contains "deleted"`}
+	if strings.Join(failed, "|") != strings.Join(want, "|") {
+		t.Errorf("expected failed checks %v, got %v", want, failed)
+	}
+	if report.Score != 5.0/8.0 {
+		t.Errorf("expected score 5/8, got %v", report.Score)
+	}
+}
+
+func TestParse_Errors(t *testing.T) {
+	for _, src := range []string{
+		`cases: []`,
+		`cases: [{prompt: hi}]`,
+		`cases: [{expect: [{contains: x}]}]`,
+		`cases: [{prompt: hi, expect: [{contains: x, regex: y}]}]`,
+		`cases: [{prompt: hi, expect: [{regex: "("}]}]`,
+		`cases: [{prompt: hi, expect: [{equals: x}]}]`,
+	} {
+		if _, err := Parse([]byte(src)); err == nil {
+			t.Errorf("expected an error for %s", src)
+		}
+	}
+}
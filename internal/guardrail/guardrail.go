@@ -0,0 +1,238 @@
+// Package guardrail checks tool calls and replies against the guardrails of a
+// namespace: denied commands, blocked URLs, file size limits and forbidden
+// topics.
+package guardrail
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+)
+
+// Policy is the guardrails configuration of a namespace.
+type Policy struct {
+	DenyCommands    []string `json:"deny_commands,omitempty"`    // regexes matched against bash commands
+	BlockedURLs     []string `json:"blocked_urls,omitempty"`     // regexes matched against URLs in tool arguments
+	MaxFileSize     int64    `json:"max_file_size,omitempty"`    // bytes a tool may read or write; 0 = no limit
+	ForbiddenTopics []string `json:"forbidden_topics,omitempty"` // case-insensitive regexes matched against replies
+}
+
+// Empty reports whether the policy has no rules.
+func (p *Policy) Empty() bool {
+	return p == nil || (len(p.DenyCommands) == 0 && len(p.BlockedURLs) == 0 && p.MaxFileSize == 0 && len(p.ForbiddenTopics) == 0)
+}
+
+// Rules of a policy, as named in violations.
+const (
+	RuleDenyCommand    = "deny_commands"
+	RuleBlockedURL     = "blocked_urls"
+	RuleMaxFileSize    = "max_file_size"
+	RuleForbiddenTopic = "forbidden_topics"
+)
+
+// Violation explains why a tool call or reply was blocked.
+type Violation struct {
+	Rule    string `json:"rule"`
+	Pattern string `json:"pattern,omitempty"` // the regex or limit that matched
+	Match   string `json:"match"`             // what matched it
+	Tool    string `json:"tool,omitempty"`
+}
+
+func (v *Violation) Error() string {
+	var what string
+	switch v.Rule {
+	case RuleDenyCommand:
+		what = fmt.Sprintf("the command %q matches the denied pattern %#q", v.Match, v.Pattern)
+	case RuleBlockedURL:
+		what = fmt.Sprintf("the URL %s matches the blocked pattern %#q", v.Match, v.Pattern)
+	case RuleMaxFileSize:
+		what = fmt.Sprintf("%s is larger than the limit of %s bytes", v.Match, v.Pattern)
+	case RuleForbiddenTopic:
+		what = fmt.Sprintf("%q matches the forbidden topic %#q", v.Match, v.Pattern)
+	default:
+		what = v.Match
+	}
+	return fmt.Sprintf("blocked by guardrail (%s): %s", v.Rule, what)
+}
+
+// Explain is the tool result of a blocked call, telling the model not to
+// work around the rule.
+func (v *Violation) Explain() string {
+	return v.Error() + ". This is a namespace guardrail; do not try to get around it. Tell the user what was blocked and why."
+}
+
+// Engine is a compiled policy. A nil *Engine allows everything.
+type Engine struct {
+	policy   Policy
+	commands []*regexp.Regexp
+	urls     []*regexp.Regexp
+	topics   []*regexp.Regexp
+}
+
+// Compile compiles the regexes of p. A nil or empty policy compiles to a
+// nil engine.
+func Compile(p *Policy) (*Engine, error) {
+	if p.Empty() {
+		return nil, nil
+	}
+	e := &Engine{policy: *p}
+	var err error
+	if e.commands, err = compileAll(RuleDenyCommand, p.DenyCommands, ""); err != nil {
+		return nil, err
+	}
+	if e.urls, err = compileAll(RuleBlockedURL, p.BlockedURLs, ""); err != nil {
+		return nil, err
+	}
+	if e.topics, err = compileAll(RuleForbiddenTopic, p.ForbiddenTopics, "(?i)"); err != nil {
+		return nil, err
+	}
+	return e, nil
+}
+
+func compileAll(rule string, patterns []string, flags string) ([]*regexp.Regexp, error) {
+	res := make([]*regexp.Regexp, len(patterns))
+	for i, p := range patterns {
+		re, err := regexp.Compile(flags + p)
+		if err != nil {
+			return nil, fmt.Errorf("%s: invalid pattern %q: %w", rule, p, err)
+		}
+		res[i] = re
+	}
+	return res, nil
+}
+
+// Policy returns the rules the engine was compiled from.
+func (e *Engine) Policy() Policy {
+	if e == nil {
+		return Policy{}
+	}
+	return e.policy
+}
+
+var urlPattern = regexp.MustCompile(`https?://[^\s"'<>]+`)
+
+// CheckTool checks a tool call before it runs. Relative paths are resolved
+// against dir, the working directory of the tool; the process's one when
+// empty.
+func (e *Engine) CheckTool(name string, input json.RawMessage, dir string) *Violation {
+	if e == nil {
+		return nil
+	}
+	var args map[string]any
+	_ = json.Unmarshal(input, &args)
+
+	if name == "bash" {
+		command, _ := args["command"].(string)
+		if v := e.CheckCommand(command); v != nil {
+			v.Tool = name
+			return v
+		}
+	}
+
+	// URLs anywhere in the arguments: web_fetch, http_*, browser_open,
+	// and curl or wget in bash commands
+	for _, s := range stringValues(args) {
+		for _, u := range urlPattern.FindAllString(s, -1) {
+			if v := e.CheckURL(u); v != nil {
+				v.Tool = name
+				return v
+			}
+		}
+	}
+
+	if e.policy.MaxFileSize > 0 {
+		path, _ := args["path"].(string)
+		switch name {
+		case "write":
+			content, _ := args["content"].(string)
+			if int64(len(content)) > e.policy.MaxFileSize {
+				return e.sizeViolation(name, fmt.Sprintf("the content written to %s (%d bytes)", path, len(content)))
+			}
+		case "read", "edit":
+			if path != "" && !filepath.IsAbs(path) {
+				path = filepath.Join(dir, path)
+			}
+			if info, err := os.Stat(path); err == nil && !info.IsDir() && info.Size() > e.policy.MaxFileSize {
+				return e.sizeViolation(name, fmt.Sprintf("%s (%d bytes)", path, info.Size()))
+			}
+		}
+	}
+	return nil
+}
+
+func (e *Engine) sizeViolation(tool, match string) *Violation {
+	return &Violation{Rule: RuleMaxFileSize, Pattern: fmt.Sprint(e.policy.MaxFileSize), Match: match, Tool: tool}
+}
+
+// CheckCommand checks a shell command against the denied patterns.
+func (e *Engine) CheckCommand(command string) *Violation {
+	if e == nil || command == "" {
+		return nil
+	}
+	for i, re := range e.commands {
+		if re.MatchString(command) {
+			return &Violation{Rule: RuleDenyCommand, Pattern: e.policy.DenyCommands[i], Match: command}
+		}
+	}
+	return nil
+}
+
+// CheckURL checks a URL against the blocked patterns.
+func (e *Engine) CheckURL(url string) *Violation {
+	if e == nil {
+		return nil
+	}
+	for i, re := range e.urls {
+		if re.MatchString(url) {
+			return &Violation{Rule: RuleBlockedURL, Pattern: e.policy.BlockedURLs[i], Match: url}
+		}
+	}
+	return nil
+}
+
+// CheckText checks a reply against the forbidden topics.
+func (e *Engine) CheckText(text string) *Violation {
+	if e == nil || text == "" {
+		return nil
+	}
+	for i, re := range e.topics {
+		if m := re.FindString(text); m != "" {
+			return &Violation{Rule: RuleForbiddenTopic, Pattern: e.policy.ForbiddenTopics[i], Match: m}
+		}
+	}
+	return nil
+}
+
+// stringValues returns the strings in a decoded JSON value.
+func stringValues(v any) []string {
+	switch v := v.(type) {
+	case string:
+		return []string{v}
+	case map[string]any:
+		var out []string
+		for _, item := range v {
+			out = append(out, stringValues(item)...)
+		}
+		return out
+	case []any:
+		var out []string
+		for _, item := range v {
+			out = append(out, stringValues(item)...)
+		}
+		return out
+	}
+	return nil
+}
+
+// ChecksText reports whether replies have to be checked, i.e. whether
+// the policy has forbidden topics.
+func (e *Engine) ChecksText() bool {
+	return e != nil && len(e.topics) > 0
+}
+
+// BlockedReply replaces a reply that touches a forbidden topic.
+func BlockedReply(v *Violation) string {
+	return fmt.Sprintf("⛔ My reply was withheld: it matches the forbidden topic %#q of this namespace's guardrails.", v.Pattern)
+}
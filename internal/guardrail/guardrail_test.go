@@ -0,0 +1,106 @@
+package guardrail
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestCheckTool(t *testing.T) {
+	dir := t.TempDir()
+	big := filepath.Join(dir, "big.log")
+	if err := os.WriteFile(big, make([]byte, 2048), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	small := filepath.Join(dir, "small.txt")
+	if err := os.WriteFile(small, []byte("ok"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	e, err := Compile(&Policy{
+		DenyCommands: []string{`rm\s+-rf\s+/`, `\bgit\s+push\s+--force`},
+		BlockedURLs:  []string{`^https?://([a-z0-9-]+\.)*internal\.example\.com`},
+		MaxFileSize:  1024,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		tool, input string
+		rule        string // "" = allowed
+	}{
+		{"bash", `{"command":"rm -rf / --no-preserve-root"}`, RuleDenyCommand},
+		{"bash", `{"command":"git push --force origin main"}`, RuleDenyCommand},
+		{"bash", `{"command":"rm -rf ./build"}`, ""},
+		{"bash", `{"command":"curl -s https://api.internal.example.com/users"}`, RuleBlockedURL},
+		{"web_fetch", `{"url":"http://internal.example.com/"}`, RuleBlockedURL},
+		{"http_request", `{"request":{"url":"https://wiki.internal.example.com/x"}}`, RuleBlockedURL},
+		{"web_fetch", `{"url":"https://example.com/internal.example.com"}`, ""},
+		{"read", `{"path":` + quote(big) + `}`, RuleMaxFileSize},
+		{"read", `{"path":` + quote(small) + `}`, ""},
+		{"read", `{"path":"big.log"}`, RuleMaxFileSize},
+		{"edit", `{"path":"big.log","old_string":"a","new_string":"b"}`, RuleMaxFileSize},
+		{"read", `{"path":"small.txt"}`, ""},
+		{"write", `{"path":"out.txt","content":"` + strings.Repeat("x", 2000) + `"}`, RuleMaxFileSize},
+		{"write", `{"path":"out.txt","content":"short"}`, ""},
+	}
+	for _, tt := range tests {
+		v := e.CheckTool(tt.tool, json.RawMessage(tt.input), dir)
+		switch {
+		case tt.rule == "" && v != nil:
+			t.Errorf("%s %s: unexpectedly blocked: %v", tt.tool, tt.input, v)
+		case tt.rule != "" && v == nil:
+			t.Errorf("%s %s: not blocked", tt.tool, tt.input)
+		case v != nil && (v.Rule != tt.rule || v.Tool != tt.tool):
+			t.Errorf("%s %s: got %s/%s, want %s/%s", tt.tool, tt.input, v.Rule, v.Tool, tt.rule, tt.tool)
+		}
+	}
+}
+
+func TestCheckText(t *testing.T) {
+	e, err := Compile(&Policy{ForbiddenTopics: []string{`salar(y|ies)`, `\bcompetitor pricing\b`}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !e.ChecksText() {
+		t.Error("ChecksText = false with forbidden topics")
+	}
+	v := e.CheckText("Here are the SALARIES of the team.")
+	if v == nil || v.Rule != RuleForbiddenTopic || v.Match != "SALARIES" {
+		t.Fatalf("CheckText = %+v", v)
+	}
+	if !strings.Contains(v.Explain(), "forbidden topic") || !strings.Contains(BlockedReply(v), "salar(y|ies)") {
+		t.Errorf("explanation = %q, reply = %q", v.Explain(), BlockedReply(v))
+	}
+	if v := e.CheckText("The deploy finished."); v != nil {
+		t.Errorf("unexpectedly blocked: %v", v)
+	}
+}
+
+func TestCompile(t *testing.T) {
+	if e, err := Compile(nil); e != nil || err != nil {
+		t.Errorf("Compile(nil) = %v, %v", e, err)
+	}
+	if e, err := Compile(&Policy{}); e != nil || err != nil {
+		t.Errorf("Compile(empty) = %v, %v", e, err)
+	}
+	if _, err := Compile(&Policy{BlockedURLs: []string{"("}}); err == nil || !strings.Contains(err.Error(), RuleBlockedURL) {
+		t.Errorf("expected blocked_urls error, got %v", err)
+	}
+
+	var e *Engine
+	if v := e.CheckTool("bash", json.RawMessage(`{"command":"rm -rf /"}`), ""); v != nil {
+		t.Errorf("nil engine blocked a call: %v", v)
+	}
+	if e.ChecksText() {
+		t.Error("nil engine checks text")
+	}
+}
+
+func quote(s string) string {
+	b, _ := json.Marshal(s)
+	return string(b)
+}
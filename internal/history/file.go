@@ -0,0 +1,125 @@
+package history
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/eachlabs/klaw/internal/provider"
+)
+
+// FileStore keeps one JSON file per conversation in a directory. Files are
+// replaced atomically, so several processes can read the same directory.
+type FileStore struct {
+	dir string
+	mu  sync.Mutex
+}
+
+type fileConversation struct {
+	ID        string             `json:"id"`
+	Messages  []provider.Message `json:"messages"`
+	UpdatedAt time.Time          `json:"updated_at"`
+}
+
+// NewFileStore creates a file store in dir.
+func NewFileStore(dir string) *FileStore {
+	return &FileStore{dir: dir}
+}
+
+// path maps a conversation ID to a file name; IDs such as "C123:1700.1"
+// are escaped so they are valid on every platform.
+func (s *FileStore) path(id string) string {
+	return filepath.Join(s.dir, url.QueryEscape(id)+".json")
+}
+
+func (s *FileStore) read(path string) (*fileConversation, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var c fileConversation
+	if err := json.Unmarshal(data, &c); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	return &c, nil
+}
+
+func (s *FileStore) Get(id string) ([]provider.Message, error) {
+	c, err := s.read(s.path(id))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return c.Messages, nil
+}
+
+func (s *FileStore) Set(id string, msgs []provider.Message) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := os.MkdirAll(s.dir, 0755); err != nil {
+		return fmt.Errorf("failed to create history dir: %w", err)
+	}
+	data, err := json.Marshal(fileConversation{ID: id, Messages: msgs, UpdatedAt: time.Now()})
+	if err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(s.dir, ".tmp-*")
+	if err != nil {
+		return err
+	}
+	if _, err := tmp.Write(data); err != nil {
+		_ = tmp.Close()
+		_ = os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		_ = os.Remove(tmp.Name())
+		return err
+	}
+	return os.Rename(tmp.Name(), s.path(id))
+}
+
+func (s *FileStore) Delete(id string) error {
+	if err := os.Remove(s.path(id)); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+func (s *FileStore) List() ([]Conversation, error) {
+	entries, err := os.ReadDir(s.dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	var convs []Conversation
+	for _, e := range entries {
+		if e.IsDir() || !strings.HasSuffix(e.Name(), ".json") {
+			continue
+		}
+		c, err := s.read(filepath.Join(s.dir, e.Name()))
+		if err != nil {
+			continue // removed or being written concurrently
+		}
+		convs = append(convs, Conversation{
+			ID:        c.ID,
+			Messages:  len(c.Messages),
+			Preview:   preview(c.Messages),
+			UpdatedAt: c.UpdatedAt,
+		})
+	}
+	sortConversations(convs)
+	return convs, nil
+}
@@ -0,0 +1,157 @@
+// Package history stores agent conversation histories.
+package history
+
+import (
+	"fmt"
+	"io"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/eachlabs/klaw/internal/config"
+	"github.com/eachlabs/klaw/internal/provider"
+)
+
+// Store persists conversation histories keyed by conversation ID (for
+// Slack, "<channel>:<thread_ts>").
+type Store interface {
+	// Get returns the messages of a conversation, or nil if it is unknown.
+	Get(id string) ([]provider.Message, error)
+	// Set replaces the messages of a conversation.
+	Set(id string, msgs []provider.Message) error
+	// Delete removes a conversation. Deleting an unknown ID is not an error.
+	Delete(id string) error
+	// List returns all conversations, most recently updated first.
+	List() ([]Conversation, error)
+}
+
+// Conversation describes a stored conversation.
+type Conversation struct {
+	ID        string    `json:"id"`
+	Messages  int       `json:"messages"`
+	Preview   string    `json:"preview,omitempty"` // last user message
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// Open returns the store for a backend: "memory", "file" (default) or
+// "sqlite". An empty path uses the default location in the state dir.
+func Open(backend, path string) (Store, error) {
+	switch backend {
+	case "memory":
+		return NewMemoryStore(), nil
+	case "", "file":
+		if path == "" {
+			path = filepath.Join(config.StateDir(), "conversations")
+		}
+		return NewFileStore(path), nil
+	case "sqlite":
+		if path == "" {
+			path = filepath.Join(config.StateDir(), "conversations.db")
+		}
+		return NewSQLiteStore(path)
+	default:
+		return nil, fmt.Errorf("unknown history backend: %s (use memory, file or sqlite)", backend)
+	}
+}
+
+// OpenFromConfig opens the store selected by the [history] config section.
+func OpenFromConfig(cfg *config.Config) (Store, error) {
+	return Open(cfg.History.Backend, cfg.History.Path)
+}
+
+// Close closes s if it holds resources, such as a database connection.
+// The other stores write each Set through and have nothing to flush.
+func Close(s Store) error {
+	if c, ok := s.(io.Closer); ok {
+		return c.Close()
+	}
+	return nil
+}
+
+// preview returns the start of the last user text message.
+func preview(msgs []provider.Message) string {
+	for i := len(msgs) - 1; i >= 0; i-- {
+		m := msgs[i]
+		if m.Role == "user" && m.ToolResult == nil && m.Content != "" {
+			content := m.Content
+			if strings.HasPrefix(content, "[Context:") {
+				if _, rest, ok := strings.Cut(content, "\n\n"); ok {
+					content = rest
+				}
+			}
+			s := []rune(content)
+			if len(s) > 80 {
+				return string(s[:80]) + "..."
+			}
+			return string(s)
+		}
+	}
+	return ""
+}
+
+func sortConversations(convs []Conversation) {
+	sort.Slice(convs, func(i, j int) bool { return convs[i].UpdatedAt.After(convs[j].UpdatedAt) })
+}
+
+// --- memory ---
+
+// MemoryStore keeps histories in process memory.
+type MemoryStore struct {
+	mu            sync.RWMutex
+	conversations map[string]memoryConversation
+}
+
+type memoryConversation struct {
+	msgs      []provider.Message
+	updatedAt time.Time
+}
+
+// NewMemoryStore creates an in-memory store.
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{conversations: make(map[string]memoryConversation)}
+}
+
+func (s *MemoryStore) Get(id string) ([]provider.Message, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	c, ok := s.conversations[id]
+	if !ok {
+		return nil, nil
+	}
+	return append([]provider.Message(nil), c.msgs...), nil
+}
+
+func (s *MemoryStore) Set(id string, msgs []provider.Message) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.conversations[id] = memoryConversation{
+		msgs:      append([]provider.Message(nil), msgs...),
+		updatedAt: time.Now(),
+	}
+	return nil
+}
+
+func (s *MemoryStore) Delete(id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.conversations, id)
+	return nil
+}
+
+func (s *MemoryStore) List() ([]Conversation, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	convs := make([]Conversation, 0, len(s.conversations))
+	for id, c := range s.conversations {
+		convs = append(convs, Conversation{
+			ID:        id,
+			Messages:  len(c.msgs),
+			Preview:   preview(c.msgs),
+			UpdatedAt: c.updatedAt,
+		})
+	}
+	sortConversations(convs)
+	return convs, nil
+}
@@ -0,0 +1,94 @@
+package history
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/eachlabs/klaw/internal/provider"
+)
+
+func testStores(t *testing.T) map[string]Store {
+	dir := t.TempDir()
+	sqlite, err := NewSQLiteStore(filepath.Join(dir, "history.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = sqlite.Close() })
+
+	return map[string]Store{
+		"memory": NewMemoryStore(),
+		"file":   NewFileStore(filepath.Join(dir, "conversations")),
+		"sqlite": sqlite,
+	}
+}
+
+func TestStores(t *testing.T) {
+	for name, store := range testStores(t) {
+		t.Run(name, func(t *testing.T) {
+			msgs, err := store.Get("C1:1700.1")
+			if err != nil || msgs != nil {
+				t.Fatalf("expected unknown conversation, got %v, %v", msgs, err)
+			}
+
+			first := []provider.Message{
+				{Role: "user", Content: "[Context: channel=C1]\n\nhello"},
+				{Role: "assistant", ToolCalls: []provider.ToolCall{{ID: "t1", Name: "bash"}}},
+				{Role: "user", ToolResult: &provider.ToolResult{ToolUseID: "t1", Content: "ok"}},
+			}
+			if err := store.Set("C1:1700.1", first); err != nil {
+				t.Fatal(err)
+			}
+			if err := store.Set("C2", []provider.Message{{Role: "user", Content: "other"}}); err != nil {
+				t.Fatal(err)
+			}
+
+			msgs, err = store.Get("C1:1700.1")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(msgs) != 3 || msgs[2].ToolResult == nil || msgs[2].ToolResult.ToolUseID != "t1" {
+				t.Fatalf("history not round-tripped: %+v", msgs)
+			}
+
+			convs, err := store.List()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(convs) != 2 || convs[0].ID != "C2" {
+				t.Fatalf("expected 2 conversations, newest first, got %+v", convs)
+			}
+			if convs[1].Messages != 3 || convs[1].Preview != "hello" {
+				t.Errorf("unexpected summary: %+v", convs[1])
+			}
+
+			if err := store.Delete("C1:1700.1"); err != nil {
+				t.Fatal(err)
+			}
+			if err := store.Delete("missing"); err != nil {
+				t.Errorf("deleting unknown conversation: %v", err)
+			}
+			if msgs, _ := store.Get("C1:1700.1"); msgs != nil {
+				t.Error("conversation should be deleted")
+			}
+		})
+	}
+}
+
+func TestFileStoreSharedBetweenInstances(t *testing.T) {
+	dir := t.TempDir()
+	a, b := NewFileStore(dir), NewFileStore(dir)
+
+	if err := a.Set("C1", []provider.Message{{Role: "user", Content: "hi"}}); err != nil {
+		t.Fatal(err)
+	}
+	msgs, err := b.Get("C1")
+	if err != nil || len(msgs) != 1 {
+		t.Fatalf("expected history written by another store, got %v, %v", msgs, err)
+	}
+}
+
+func TestOpenUnknownBackend(t *testing.T) {
+	if _, err := Open("redis", ""); err == nil {
+		t.Error("expected error for unknown backend")
+	}
+}
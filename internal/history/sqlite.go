@@ -0,0 +1,104 @@
+package history
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	_ "modernc.org/sqlite"
+
+	"github.com/eachlabs/klaw/internal/provider"
+)
+
+// SQLiteStore keeps histories in a SQLite database, which can be shared by
+// processes on the same host.
+type SQLiteStore struct {
+	db *sql.DB
+}
+
+// NewSQLiteStore opens (and if needed creates) the database at path.
+func NewSQLiteStore(path string) (*SQLiteStore, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, fmt.Errorf("failed to create history dir: %w", err)
+	}
+	db, err := sql.Open("sqlite", path+"?_pragma=journal_mode(WAL)&_pragma=busy_timeout(5000)")
+	if err != nil {
+		return nil, err
+	}
+	if _, err := db.Exec(`CREATE TABLE IF NOT EXISTS conversations (
+		id         TEXT PRIMARY KEY,
+		messages   TEXT NOT NULL,
+		count      INTEGER NOT NULL,
+		preview    TEXT NOT NULL,
+		updated_at INTEGER NOT NULL
+	)`); err != nil {
+		_ = db.Close()
+		return nil, fmt.Errorf("failed to create history table: %w", err)
+	}
+	return &SQLiteStore{db: db}, nil
+}
+
+// Close closes the database.
+func (s *SQLiteStore) Close() error {
+	return s.db.Close()
+}
+
+func (s *SQLiteStore) Get(id string) ([]provider.Message, error) {
+	var data string
+	err := s.db.QueryRow(`SELECT messages FROM conversations WHERE id = ?`, id).Scan(&data)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var msgs []provider.Message
+	if err := json.Unmarshal([]byte(data), &msgs); err != nil {
+		return nil, fmt.Errorf("failed to parse conversation %s: %w", id, err)
+	}
+	return msgs, nil
+}
+
+func (s *SQLiteStore) Set(id string, msgs []provider.Message) error {
+	data, err := json.Marshal(msgs)
+	if err != nil {
+		return err
+	}
+	_, err = s.db.Exec(`INSERT INTO conversations (id, messages, count, preview, updated_at)
+		VALUES (?, ?, ?, ?, ?)
+		ON CONFLICT(id) DO UPDATE SET
+			messages = excluded.messages,
+			count = excluded.count,
+			preview = excluded.preview,
+			updated_at = excluded.updated_at`,
+		id, string(data), len(msgs), preview(msgs), time.Now().UnixNano())
+	return err
+}
+
+func (s *SQLiteStore) Delete(id string) error {
+	_, err := s.db.Exec(`DELETE FROM conversations WHERE id = ?`, id)
+	return err
+}
+
+func (s *SQLiteStore) List() ([]Conversation, error) {
+	rows, err := s.db.Query(`SELECT id, count, preview, updated_at FROM conversations ORDER BY updated_at DESC`)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = rows.Close() }()
+
+	var convs []Conversation
+	for rows.Next() {
+		var c Conversation
+		var updated int64
+		if err := rows.Scan(&c.ID, &c.Messages, &c.Preview, &updated); err != nil {
+			return nil, err
+		}
+		c.UpdatedAt = time.Unix(0, updated)
+		convs = append(convs, c)
+	}
+	return convs, rows.Err()
+}
@@ -0,0 +1,137 @@
+package lease
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// staleLock is the age after which a lock file left by a crashed process
+// is removed.
+const staleLock = 10 * time.Second
+
+// FileStore keeps each lease in a file of a directory, which replicas on
+// several hosts can share over a network file system.
+type FileStore struct {
+	dir string
+	now func() time.Time
+}
+
+// fileLease is the content of a lease file.
+type fileLease struct {
+	Key     string    `json:"key"`
+	Owner   string    `json:"owner"`
+	Expires time.Time `json:"expires"`
+}
+
+// NewFileStore returns a store keeping leases in dir, creating it.
+func NewFileStore(dir string) (*FileStore, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create lease directory: %w", err)
+	}
+	return &FileStore{dir: dir, now: time.Now}, nil
+}
+
+func (s *FileStore) path(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return filepath.Join(s.dir, hex.EncodeToString(sum[:16])+".json")
+}
+
+func (s *FileStore) Acquire(ctx context.Context, key, owner string, ttl time.Duration) (bool, error) {
+	path := s.path(key)
+	unlock, err := s.lock(ctx, path)
+	if err != nil {
+		return false, err
+	}
+	defer unlock()
+
+	now := s.now()
+	if l, err := readLease(path); err != nil {
+		return false, err
+	} else if l != nil && l.Owner != owner && now.Before(l.Expires) {
+		return false, nil
+	}
+
+	data, err := json.Marshal(fileLease{Key: key, Owner: owner, Expires: now.Add(ttl)})
+	if err != nil {
+		return false, err
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return false, err
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+func (s *FileStore) Release(ctx context.Context, key, owner string) error {
+	path := s.path(key)
+	unlock, err := s.lock(ctx, path)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	l, err := readLease(path)
+	if err != nil || l == nil || l.Owner != owner {
+		return err
+	}
+	if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	return nil
+}
+
+func (s *FileStore) Close() error {
+	return nil
+}
+
+// lock takes the lock file of a lease file, waiting while another process
+// holds it, and returns the function releasing it.
+func (s *FileStore) lock(ctx context.Context, path string) (func(), error) {
+	lockPath := path + ".lock"
+	for {
+		f, err := os.OpenFile(lockPath, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0644)
+		if err == nil {
+			_ = f.Close()
+			return func() { _ = os.Remove(lockPath) }, nil
+		}
+		if !errors.Is(err, os.ErrExist) {
+			return nil, err
+		}
+		if info, err := os.Stat(lockPath); err == nil && time.Since(info.ModTime()) > staleLock {
+			_ = os.Remove(lockPath)
+			continue
+		}
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(10 * time.Millisecond):
+		}
+	}
+}
+
+// readLease reads a lease file; a missing file is no lease.
+func readLease(path string) (*fileLease, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var l fileLease
+	if err := json.Unmarshal(data, &l); err != nil {
+		// A torn write; the lease is lost and can be taken
+		return nil, nil
+	}
+	return &l, nil
+}
@@ -0,0 +1,90 @@
+// Package lease grants time-limited, exclusive leases on keys, so that of
+// several klaw processes sharing a channel only one handles a conversation
+// at a time, and another takes over once the holder stops renewing.
+package lease
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"sync"
+	"time"
+)
+
+// Store grants leases on keys.
+type Store interface {
+	// Acquire takes the lease on key for owner until ttl from now, or
+	// extends it if owner already holds it. It reports whether owner holds
+	// the lease; false means another owner's lease has not expired yet.
+	Acquire(ctx context.Context, key, owner string, ttl time.Duration) (bool, error)
+
+	// Release ends owner's lease on key. Leases of other owners are left
+	// alone.
+	Release(ctx context.Context, key, owner string) error
+
+	// Close releases the resources of the store, not its leases.
+	Close() error
+}
+
+// Open opens the lease store at rawURL: file:///path/to/dir or
+// redis://[:password@]host[:port][/db].
+func Open(rawURL string) (Store, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid lease url: %w", err)
+	}
+	switch u.Scheme {
+	case "file":
+		if u.Path == "" {
+			return nil, fmt.Errorf("invalid lease url: %q has no directory", rawURL)
+		}
+		return NewFileStore(u.Path)
+	case "redis":
+		return NewRedisStore(u), nil
+	default:
+		return nil, fmt.Errorf("unknown lease store: %q (use file:// or redis://)", rawURL)
+	}
+}
+
+// memoryLease is a lease held in a MemoryStore.
+type memoryLease struct {
+	owner   string
+	expires time.Time
+}
+
+// MemoryStore keeps leases in memory, for processes of one binary such as
+// tests.
+type MemoryStore struct {
+	mu     sync.Mutex
+	leases map[string]memoryLease
+	now    func() time.Time
+}
+
+// NewMemoryStore returns an empty memory store.
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{leases: make(map[string]memoryLease), now: time.Now}
+}
+
+func (s *MemoryStore) Acquire(ctx context.Context, key, owner string, ttl time.Duration) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := s.now()
+	if l, ok := s.leases[key]; ok && l.owner != owner && now.Before(l.expires) {
+		return false, nil
+	}
+	s.leases[key] = memoryLease{owner: owner, expires: now.Add(ttl)}
+	return true, nil
+}
+
+func (s *MemoryStore) Release(ctx context.Context, key, owner string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if l, ok := s.leases[key]; ok && l.owner == owner {
+		delete(s.leases, key)
+	}
+	return nil
+}
+
+func (s *MemoryStore) Close() error {
+	return nil
+}
@@ -0,0 +1,96 @@
+package lease
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+// testStore is a store with a clock the test moves.
+type testStore struct {
+	store   Store
+	advance func(time.Duration)
+}
+
+func TestStores(t *testing.T) {
+	file, err := NewFileStore(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	stores := map[string]testStore{}
+
+	mem := NewMemoryStore()
+	memNow := time.Now()
+	mem.now = func() time.Time { return memNow }
+	stores["memory"] = testStore{mem, func(d time.Duration) { memNow = memNow.Add(d) }}
+
+	fileNow := time.Now()
+	file.now = func() time.Time { return fileNow }
+	stores["file"] = testStore{file, func(d time.Duration) { fileNow = fileNow.Add(d) }}
+
+	ctx := context.Background()
+	for name, s := range stores {
+		t.Run(name, func(t *testing.T) {
+			acquire := func(key, owner string) bool {
+				t.Helper()
+				ok, err := s.store.Acquire(ctx, key, owner, time.Minute)
+				if err != nil {
+					t.Fatal(err)
+				}
+				return ok
+			}
+
+			if !acquire("C1:1.0", "a") {
+				t.Fatal("a should take the free lease")
+			}
+			if acquire("C1:1.0", "b") {
+				t.Fatal("b should not take a's lease")
+			}
+			if !acquire("C2:1.0", "b") {
+				t.Fatal("b should take the lease of another conversation")
+			}
+
+			// Renewing keeps the lease past its first expiry
+			s.advance(50 * time.Second)
+			if !acquire("C1:1.0", "a") {
+				t.Fatal("a should renew its lease")
+			}
+			s.advance(50 * time.Second)
+			if acquire("C1:1.0", "b") {
+				t.Fatal("b should not take the renewed lease")
+			}
+
+			// Failover once the holder stops renewing
+			s.advance(2 * time.Minute)
+			if !acquire("C1:1.0", "b") {
+				t.Fatal("b should take the expired lease")
+			}
+
+			// Only the holder releases
+			if err := s.store.Release(ctx, "C1:1.0", "a"); err != nil {
+				t.Fatal(err)
+			}
+			if acquire("C1:1.0", "a") {
+				t.Fatal("a's release should not end b's lease")
+			}
+			if err := s.store.Release(ctx, "C1:1.0", "b"); err != nil {
+				t.Fatal(err)
+			}
+			if !acquire("C1:1.0", "a") {
+				t.Fatal("a should take the released lease")
+			}
+		})
+	}
+}
+
+func TestOpen(t *testing.T) {
+	if _, err := Open("file://" + t.TempDir()); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Open("redis://localhost:6379/1"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Open("etcd://localhost:2379"); err == nil {
+		t.Fatal("expected an error for an unknown store")
+	}
+}
@@ -0,0 +1,102 @@
+package lease
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/eachlabs/klaw/internal/redis"
+)
+
+// redisKeyPrefix namespaces the lease keys in Redis.
+const redisKeyPrefix = "klaw:lease:"
+
+// acquireScript sets the lease when it is free or held by the same owner,
+// in one step.
+const acquireScript = `local cur = redis.call('GET', KEYS[1])
+if cur == false or cur == ARGV[1] then
+  redis.call('SET', KEYS[1], ARGV[1], 'PX', ARGV[2])
+  return 1
+end
+return 0`
+
+// releaseScript deletes the lease only when the owner holds it.
+const releaseScript = `if redis.call('GET', KEYS[1]) == ARGV[1] then
+  return redis.call('DEL', KEYS[1])
+end
+return 0`
+
+// RedisStore keeps leases as Redis keys expiring with them.
+type RedisStore struct {
+	u *url.URL
+
+	mu   sync.Mutex // guards conn
+	conn *redis.Conn
+}
+
+// NewRedisStore returns a store on the Redis server of u. It connects on
+// first use, and again after the connection fails.
+func NewRedisStore(u *url.URL) *RedisStore {
+	return &RedisStore{u: u}
+}
+
+func (s *RedisStore) Acquire(ctx context.Context, key, owner string, ttl time.Duration) (bool, error) {
+	ms := ttl.Milliseconds()
+	if ms < 1 {
+		ms = 1
+	}
+	reply, err := s.do(ctx, "EVAL", acquireScript, "1", redisKeyPrefix+key, owner, strconv.FormatInt(ms, 10))
+	if err != nil {
+		return false, err
+	}
+	n, _ := reply.(int64)
+	return n == 1, nil
+}
+
+func (s *RedisStore) Release(ctx context.Context, key, owner string) error {
+	_, err := s.do(ctx, "EVAL", releaseScript, "1", redisKeyPrefix+key, owner)
+	return err
+}
+
+func (s *RedisStore) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.conn == nil {
+		return nil
+	}
+	err := s.conn.Close()
+	s.conn = nil
+	return err
+}
+
+// do runs a command, dropping the connection when it fails for a reason
+// other than an error reply.
+func (s *RedisStore) do(ctx context.Context, args ...string) (any, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.conn == nil {
+		conn, err := redis.Dial(s.u)
+		if err != nil {
+			return nil, err
+		}
+		s.conn = conn
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		_ = s.conn.SetDeadline(deadline)
+		defer func() {
+			if s.conn != nil {
+				_ = s.conn.SetDeadline(time.Time{})
+			}
+		}()
+	}
+	reply, err := s.conn.Do(args...)
+	var rerr redis.Error
+	if err != nil && !errors.As(err, &rerr) {
+		_ = s.conn.Close()
+		s.conn = nil
+	}
+	return reply, err
+}
@@ -0,0 +1,161 @@
+// Package locale holds the text klaw shows users and the instructions it
+// gives the model as templates per language, so a team gets its replies,
+// help and errors in one language. The built-in languages can be
+// overridden, and others added, with <dir>/<lang>.toml files.
+package locale
+
+import (
+	"bytes"
+	"embed"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/template"
+
+	"github.com/BurntSushi/toml"
+)
+
+//go:embed locales/*.toml
+var builtinLocales embed.FS
+
+// Default is the language used when no language is set, and for texts a
+// language has no template for.
+const Default = "en"
+
+// Catalog is the templates of the texts, by language and key. Keys are
+// dotted, following the tables of the locale files, e.g. "agent.stopped".
+// A nil Catalog has the built-in templates only.
+type Catalog struct {
+	texts map[string]map[string]*template.Template
+}
+
+var builtin = mustBuiltin()
+
+func mustBuiltin() *Catalog {
+	c := &Catalog{texts: make(map[string]map[string]*template.Template)}
+	entries, err := builtinLocales.ReadDir("locales")
+	if err != nil {
+		panic(err)
+	}
+	for _, e := range entries {
+		data, err := builtinLocales.ReadFile("locales/" + e.Name())
+		if err != nil {
+			panic(err)
+		}
+		if err := c.add(strings.TrimSuffix(e.Name(), ".toml"), data); err != nil {
+			panic(fmt.Sprintf("locale %s: %v", e.Name(), err))
+		}
+	}
+	return c
+}
+
+// New returns the built-in templates, overridden by the <lang>.toml files
+// of dir. Keys a file leaves out keep their built-in templates. A missing
+// dir is not an error.
+func New(dir string) (*Catalog, error) {
+	c := &Catalog{texts: make(map[string]map[string]*template.Template)}
+	for lang, texts := range builtin.texts {
+		c.texts[lang] = make(map[string]*template.Template, len(texts))
+		for k, t := range texts {
+			c.texts[lang][k] = t
+		}
+	}
+
+	files, _ := filepath.Glob(filepath.Join(dir, "*.toml"))
+	for _, p := range files {
+		data, err := os.ReadFile(p)
+		if err != nil {
+			return nil, err
+		}
+		if err := c.add(Normalize(strings.TrimSuffix(filepath.Base(p), ".toml")), data); err != nil {
+			return nil, fmt.Errorf("%s: %w", p, err)
+		}
+	}
+	return c, nil
+}
+
+// add parses the templates of a locale file into lang.
+func (c *Catalog) add(lang string, data []byte) error {
+	var tables map[string]any
+	if err := toml.Unmarshal(data, &tables); err != nil {
+		return err
+	}
+	if c.texts[lang] == nil {
+		c.texts[lang] = make(map[string]*template.Template)
+	}
+	return c.addTable(lang, "", tables)
+}
+
+func (c *Catalog) addTable(lang, prefix string, table map[string]any) error {
+	for k, v := range table {
+		key := prefix + k
+		switch v := v.(type) {
+		case map[string]any:
+			if err := c.addTable(lang, key+".", v); err != nil {
+				return err
+			}
+		case string:
+			t, err := template.New(key).Option("missingkey=zero").Parse(v)
+			if err != nil {
+				return err
+			}
+			c.texts[lang][key] = t
+		default:
+			return fmt.Errorf("%s: expected a string", key)
+		}
+	}
+	return nil
+}
+
+// Normalize returns the base language of a language tag, e.g. "tr" for
+// "tr-TR" or "tr_TR".
+func Normalize(lang string) string {
+	lang = strings.ToLower(strings.TrimSpace(lang))
+	if i := strings.IndexAny(lang, "-_"); i >= 0 {
+		lang = lang[:i]
+	}
+	return lang
+}
+
+// Has reports whether the catalog has templates for the language of lang.
+func (c *Catalog) Has(lang string) bool {
+	if c == nil {
+		c = builtin
+	}
+	_, ok := c.texts[Normalize(lang)]
+	return ok
+}
+
+// Languages returns the languages of the catalog, sorted.
+func (c *Catalog) Languages() []string {
+	if c == nil {
+		c = builtin
+	}
+	langs := make([]string, 0, len(c.texts))
+	for lang := range c.texts {
+		langs = append(langs, lang)
+	}
+	sort.Strings(langs)
+	return langs
+}
+
+// Text renders the template of key in lang with data. A key lang has no
+// template for is rendered in Default; an unknown key is returned as is.
+func (c *Catalog) Text(lang, key string, data any) string {
+	if c == nil {
+		c = builtin
+	}
+	t, ok := c.texts[Normalize(lang)][key]
+	if !ok {
+		if t, ok = c.texts[Default][key]; !ok {
+			return key
+		}
+	}
+	var buf bytes.Buffer
+	if err := t.Execute(&buf, data); err != nil {
+		return key
+	}
+	return buf.String()
+}
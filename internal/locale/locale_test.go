@@ -0,0 +1,65 @@
+package locale
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestText(t *testing.T) {
+	var c *Catalog
+	if got := c.Text("tr-TR", "agent.stopped", nil); got != "⏹ Durduruldu." {
+		t.Errorf("expected the Turkish text, got %q", got)
+	}
+	if got := c.Text("de", "agent.routing_failed", map[string]any{"Error": "no agent"}); got != "I couldn't route your message: no agent" {
+		t.Errorf("expected the English text for an unknown language, got %q", got)
+	}
+	if got := c.Text("en", "no.such.key", nil); got != "no.such.key" {
+		t.Errorf("expected the key for an unknown key, got %q", got)
+	}
+	for _, lang := range c.Languages() {
+		for _, key := range []string{"prompt.slack_guidelines", "prompt.slack_instructions", "slack.help.title"} {
+			if strings.HasPrefix(c.Text(lang, key, nil), key) {
+				t.Errorf("%s: missing %s", lang, key)
+			}
+		}
+	}
+}
+
+func TestNew_Overrides(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "de_DE.toml"), []byte("[agent]\nstopped = \"⏹ Angehalten.\"\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "en.toml"), []byte("[slack]\nerror = \"Oops: {{.Error}}\"\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	c, err := New(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !c.Has("de") || c.Text("de", "agent.stopped", nil) != "⏹ Angehalten." {
+		t.Errorf("expected the German override, got %q", c.Text("de", "agent.stopped", nil))
+	}
+	if got := c.Text("de", "agent.nothing_to_stop", nil); got != "Nothing is running to stop." {
+		t.Errorf("expected keys left out to fall back to English, got %q", got)
+	}
+	if got := c.Text("en", "slack.error", map[string]any{"Error": "boom"}); got != "Oops: boom" {
+		t.Errorf("expected the English override, got %q", got)
+	}
+	if got := c.Text("en", "agent.stopped", nil); got != "⏹ Stopped." {
+		t.Errorf("expected other keys to keep their built-in text, got %q", got)
+	}
+	if got := (*Catalog)(nil).Text("en", "slack.error", map[string]any{"Error": "boom"}); got != ":x: *Error*\nboom" {
+		t.Errorf("overrides leaked into the built-in templates: %q", got)
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, "fr.toml"), []byte("[agent]\nstopped = \"{{.Oops\"\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := New(dir); err == nil {
+		t.Error("expected an error for a bad template")
+	}
+}
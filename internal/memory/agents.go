@@ -0,0 +1,110 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// AgentDir returns the memory directory of an agent binding
+// (agents/<name> in the workspace). It holds the agent's own MEMORY.md and
+// USER.md, which no other agent sees.
+func AgentDir(workspaceDir, agent string) string {
+	return filepath.Join(workspaceDir, "agents", agent)
+}
+
+// NamespaceDir returns the shared memory directory of a namespace
+// (namespaces/<cluster>/<namespace> in the workspace). Its MEMORY.md and
+// USER.md are seen by the main agent and by agents that opt in.
+func NamespaceDir(workspaceDir, cluster, namespace string) string {
+	return filepath.Join(workspaceDir, "namespaces", cluster, namespace)
+}
+
+// IdentityPrompt builds a system prompt from the workspace identity files
+// (SOUL.md, AGENTS.md, TOOLS.md) only, leaving out the workspace's user
+// context and memory.
+func IdentityPrompt(ws *Workspace) string {
+	return BuildSystemPrompt(&Workspace{Soul: ws.Soul, Agents: ws.Agents, Tools: ws.Tools})
+}
+
+// AgentMemoryPrompt returns the memory section of an agent binding's
+// prompt: the agent's own memory and, when shared is set, the namespace's
+// shared memory. It is empty when there is nothing to remember.
+func AgentMemoryPrompt(ctx context.Context, workspaceDir, cluster, namespace, agent string, shared bool) string {
+	var parts []string
+	if checkAgentName(agent) == nil {
+		parts = append(parts, memorySections(ctx, AgentDir(workspaceDir, agent), "")...)
+	}
+	if shared {
+		parts = append(parts, memorySections(ctx, NamespaceDir(workspaceDir, cluster, namespace), "Shared ")...)
+	}
+	if len(parts) == 0 {
+		return ""
+	}
+	return "\n\n" + strings.Join(parts, "\n\n")
+}
+
+// NamespaceMemoryPrompt returns the shared memory section of a namespace.
+func NamespaceMemoryPrompt(ctx context.Context, workspaceDir, cluster, namespace string) string {
+	parts := memorySections(ctx, NamespaceDir(workspaceDir, cluster, namespace), "Shared ")
+	if len(parts) == 0 {
+		return ""
+	}
+	return "\n\n" + strings.Join(parts, "\n\n")
+}
+
+// MainMemoryPrompt returns the memory section of the main agent's prompt:
+// the workspace's user context and memory, then the namespace's shared
+// memory.
+func MainMemoryPrompt(ctx context.Context, workspaceDir, cluster, namespace string) string {
+	parts := memorySections(ctx, workspaceDir, "")
+	if cluster != "" && namespace != "" {
+		parts = append(parts, memorySections(ctx, NamespaceDir(workspaceDir, cluster, namespace), "Shared ")...)
+	}
+	if len(parts) == 0 {
+		return ""
+	}
+	return "\n\n" + strings.Join(parts, "\n\n")
+}
+
+// AppendNote adds a bullet to the MEMORY.md of dir, which is part of the
+// prompt of every agent that sees dir.
+func AppendNote(dir, note string) error {
+	note = strings.TrimSpace(note)
+	if note == "" {
+		return fmt.Errorf("note is empty")
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("failed to create memory dir: %w", err)
+	}
+	path := filepath.Join(dir, "MEMORY.md")
+	prefix := ""
+	if data, err := os.ReadFile(path); err == nil && len(data) > 0 && !strings.HasSuffix(string(data), "\n") {
+		prefix = "\n"
+	}
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = f.Close() }()
+	_, err = fmt.Fprintf(f, "%s- %s\n", prefix, strings.ReplaceAll(note, "\n", " "))
+	return err
+}
+
+// memorySections renders the USER.md and MEMORY.md of dir.
+func memorySections(ctx context.Context, dir, prefix string) []string {
+	ws, err := NewFileMemory(dir).LoadWorkspace(ctx)
+	if err != nil {
+		return nil
+	}
+	var parts []string
+	if ws.User != "" {
+		parts = append(parts, "---\n\n# "+prefix+"User Context\n\n"+ws.User)
+	}
+	if ws.Memory != "" {
+		parts = append(parts, "---\n\n# "+prefix+"Memory\n\n"+ws.Memory)
+	}
+	return parts
+}
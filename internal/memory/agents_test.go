@@ -0,0 +1,62 @@
+package memory
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestAgentMemoryPrompt(t *testing.T) {
+	ws := t.TempDir()
+	for path, content := range map[string]string{
+		filepath.Join(AgentDir(ws, "support"), "MEMORY.md"):             "Refunds need a ticket number.",
+		filepath.Join(AgentDir(ws, "coder"), "MEMORY.md"):               "The repo uses Go 1.24.",
+		filepath.Join(NamespaceDir(ws, "acme", "prod"), "MEMORY.md"):    "The company is called Acme.",
+		filepath.Join(NamespaceDir(ws, "acme", "prod"), "USER.md"):      "Jane runs support.",
+		filepath.Join(NamespaceDir(ws, "acme", "staging"), "MEMORY.md"): "Staging resets nightly.",
+	} {
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	ctx := context.Background()
+
+	isolated := AgentMemoryPrompt(ctx, ws, "acme", "prod", "support", false)
+	if !strings.Contains(isolated, "Refunds need a ticket number.") {
+		t.Errorf("agent memory missing: %q", isolated)
+	}
+	for _, leaked := range []string{"Go 1.24", "Acme", "Jane"} {
+		if strings.Contains(isolated, leaked) {
+			t.Errorf("isolated prompt contains %q: %q", leaked, isolated)
+		}
+	}
+
+	shared := AgentMemoryPrompt(ctx, ws, "acme", "prod", "support", true)
+	for _, want := range []string{"Refunds need a ticket number.", "# Shared Memory", "Acme", "# Shared User Context", "Jane"} {
+		if !strings.Contains(shared, want) {
+			t.Errorf("shared prompt misses %q: %q", want, shared)
+		}
+	}
+	if strings.Contains(shared, "Staging") || strings.Contains(shared, "Go 1.24") {
+		t.Errorf("shared prompt leaks another namespace or agent: %q", shared)
+	}
+
+	if got := AgentMemoryPrompt(ctx, ws, "acme", "prod", "writer", false); got != "" {
+		t.Errorf("agent without memory should add nothing, got %q", got)
+	}
+	if got := AgentMemoryPrompt(ctx, ws, "acme", "prod", "../coder", false); got != "" {
+		t.Errorf("unsafe agent name should add nothing, got %q", got)
+	}
+}
+
+func TestIdentityPrompt(t *testing.T) {
+	got := IdentityPrompt(&Workspace{Soul: "I am klaw.", Memory: "secret notes", User: "Jane"})
+	if !strings.Contains(got, "I am klaw.") || strings.Contains(got, "secret notes") || strings.Contains(got, "Jane") {
+		t.Errorf("unexpected identity prompt: %q", got)
+	}
+}
@@ -0,0 +1,63 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/openai/openai-go"
+	"github.com/openai/openai-go/option"
+)
+
+// DefaultEmbeddingModel is used when no embedding model is configured.
+const DefaultEmbeddingModel = "text-embedding-3-small"
+
+// OpenAIEmbedder embeds texts with an OpenAI-compatible /embeddings
+// endpoint (OpenAI, Ollama, vLLM, ...).
+type OpenAIEmbedder struct {
+	client *openai.Client
+	model  string
+}
+
+// NewOpenAIEmbedder creates an embedder. An empty baseURL uses OpenAI, an
+// empty model DefaultEmbeddingModel.
+func NewOpenAIEmbedder(apiKey, baseURL, model string) *OpenAIEmbedder {
+	if model == "" {
+		model = DefaultEmbeddingModel
+	}
+	if apiKey == "" {
+		// Local servers (Ollama, LM Studio) don't need an API key
+		apiKey = "not-needed"
+	}
+	opts := []option.RequestOption{option.WithAPIKey(apiKey)}
+	if baseURL != "" {
+		opts = append(opts, option.WithBaseURL(baseURL))
+	}
+	client := openai.NewClient(opts...)
+	return &OpenAIEmbedder{client: &client, model: model}
+}
+
+func (e *OpenAIEmbedder) Embed(ctx context.Context, texts []string) ([][]float32, error) {
+	resp, err := e.client.Embeddings.New(ctx, openai.EmbeddingNewParams{
+		Input: openai.EmbeddingNewParamsInputUnion{OfArrayOfStrings: texts},
+		Model: e.model,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(resp.Data) != len(texts) {
+		return nil, fmt.Errorf("got %d embeddings for %d texts", len(resp.Data), len(texts))
+	}
+
+	vectors := make([][]float32, len(texts))
+	for _, d := range resp.Data {
+		if d.Index < 0 || int(d.Index) >= len(texts) {
+			return nil, fmt.Errorf("embedding index out of range: %d", d.Index)
+		}
+		v := make([]float32, len(d.Embedding))
+		for i, f := range d.Embedding {
+			v[i] = float32(f)
+		}
+		vectors[d.Index] = v
+	}
+	return vectors, nil
+}
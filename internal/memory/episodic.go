@@ -0,0 +1,387 @@
+package memory
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// KindSummary marks daily summaries in semantic memory.
+const KindSummary = "summary"
+
+const dateFormat = "2006-01-02"
+
+// Episode is one finished exchange of an agent.
+type Episode struct {
+	Agent          string    `json:"agent"`
+	ConversationID string    `json:"conversation_id,omitempty"`
+	User           string    `json:"user"`
+	Reply          string    `json:"reply"`
+	Time           time.Time `json:"time"`
+}
+
+// Journal records the episodes of all agents as JSON lines, one file per
+// day (memory/journal/<date>.jsonl in the workspace).
+type Journal struct {
+	dir string
+	mu  sync.Mutex
+}
+
+// NewJournal creates a journal in workspaceDir.
+func NewJournal(workspaceDir string) *Journal {
+	return &Journal{dir: filepath.Join(workspaceDir, "memory", "journal")}
+}
+
+// Append records an episode in the file of its day.
+func (j *Journal) Append(ep Episode) error {
+	if ep.Time.IsZero() {
+		ep.Time = time.Now()
+	}
+	data, err := json.Marshal(ep)
+	if err != nil {
+		return err
+	}
+
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	if err := os.MkdirAll(j.dir, 0755); err != nil {
+		return fmt.Errorf("failed to create journal dir: %w", err)
+	}
+	f, err := os.OpenFile(j.path(ep.Time), os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to open journal: %w", err)
+	}
+	defer func() { _ = f.Close() }()
+	_, err = f.Write(append(data, '\n'))
+	return err
+}
+
+// Day returns the episodes recorded on date, oldest first.
+func (j *Journal) Day(date time.Time) ([]Episode, error) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	f, err := os.Open(j.path(date))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	defer func() { _ = f.Close() }()
+
+	var episodes []Episode
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	for scanner.Scan() {
+		var ep Episode
+		if err := json.Unmarshal(scanner.Bytes(), &ep); err != nil {
+			continue // skip a line torn by a crash
+		}
+		episodes = append(episodes, ep)
+	}
+	return episodes, scanner.Err()
+}
+
+// Days returns the days with recorded episodes, oldest first.
+func (j *Journal) Days() ([]time.Time, error) {
+	entries, err := os.ReadDir(j.dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	var days []time.Time
+	for _, e := range entries {
+		date, err := time.ParseInLocation(dateFormat, strings.TrimSuffix(e.Name(), ".jsonl"), time.Local)
+		if err != nil || e.IsDir() {
+			continue
+		}
+		days = append(days, date)
+	}
+	sort.Slice(days, func(a, b int) bool { return days[a].Before(days[b]) })
+	return days, nil
+}
+
+// Prune removes the episodes of agent recorded before before and returns
+// how many were removed.
+func (j *Journal) Prune(agent string, before time.Time) (int, error) {
+	days, err := j.Days()
+	if err != nil {
+		return 0, err
+	}
+	removed := 0
+	for _, day := range days {
+		if !day.Before(before) {
+			break
+		}
+		episodes, err := j.Day(day)
+		if err != nil {
+			return removed, err
+		}
+		var kept []byte
+		n := 0
+		for _, ep := range episodes {
+			if ep.Agent == agent && ep.Time.Before(before) {
+				n++
+				continue
+			}
+			data, err := json.Marshal(ep)
+			if err != nil {
+				return removed, err
+			}
+			kept = append(append(kept, data...), '\n')
+		}
+		if n == 0 {
+			continue
+		}
+
+		j.mu.Lock()
+		if len(kept) == 0 {
+			err = os.Remove(j.path(day))
+		} else {
+			err = os.WriteFile(j.path(day), kept, 0644)
+		}
+		j.mu.Unlock()
+		if err != nil {
+			return removed, err
+		}
+		removed += n
+	}
+	return removed, nil
+}
+
+func (j *Journal) path(date time.Time) string {
+	return filepath.Join(j.dir, date.Format(dateFormat)+".jsonl")
+}
+
+// SummarizeFunc asks an LLM to complete prompt.
+type SummarizeFunc func(ctx context.Context, prompt string) (string, error)
+
+// Summary is an agent's summary of one day.
+type Summary struct {
+	Agent   string
+	Date    time.Time
+	Path    string
+	Content string
+}
+
+// EpisodicMemory turns each finished day of the journal into one summary
+// per agent (memory/summaries/<agent>/<date>.md in the workspace), so past
+// discussions can be looked up without replaying raw logs.
+type EpisodicMemory struct {
+	journal   *Journal
+	dir       string
+	summarize SummarizeFunc
+	index     *SemanticMemory
+}
+
+// NewEpisodicMemory creates an episodic memory in workspaceDir. summarize
+// may be nil when summaries are only read.
+func NewEpisodicMemory(workspaceDir string, journal *Journal, summarize SummarizeFunc) *EpisodicMemory {
+	return &EpisodicMemory{
+		journal:   journal,
+		dir:       filepath.Join(workspaceDir, "memory", "summaries"),
+		summarize: summarize,
+	}
+}
+
+// SetIndex also keeps new summaries in semantic memory, so they are
+// recalled by similarity.
+func (e *EpisodicMemory) SetIndex(index *SemanticMemory) {
+	e.index = index
+}
+
+// Run summarizes every finished day now and then every interval until ctx
+// is done. Errors are passed to onError and retried on the next run.
+func (e *EpisodicMemory) Run(ctx context.Context, interval time.Duration, onError func(error)) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		if err := e.CatchUp(ctx); err != nil && ctx.Err() == nil && onError != nil {
+			onError(err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// CatchUp summarizes the journal days before today that have not been
+// summarized yet.
+func (e *EpisodicMemory) CatchUp(ctx context.Context) error {
+	days, err := e.journal.Days()
+	if err != nil {
+		return err
+	}
+	today := time.Now().Format(dateFormat)
+	for _, day := range days {
+		if day.Format(dateFormat) >= today {
+			break
+		}
+		if _, err := e.SummarizeDay(ctx, day); err != nil {
+			return fmt.Errorf("summarize %s: %w", day.Format(dateFormat), err)
+		}
+	}
+	return nil
+}
+
+// SummarizeDay writes the summaries of date for agents that have none yet
+// and returns how many were written.
+func (e *EpisodicMemory) SummarizeDay(ctx context.Context, date time.Time) (int, error) {
+	if e.summarize == nil {
+		return 0, fmt.Errorf("no summarizer configured")
+	}
+	episodes, err := e.journal.Day(date)
+	if err != nil {
+		return 0, err
+	}
+
+	byAgent := make(map[string][]Episode)
+	var agents []string
+	for _, ep := range episodes {
+		if checkAgentName(ep.Agent) != nil {
+			continue
+		}
+		if _, ok := byAgent[ep.Agent]; !ok {
+			agents = append(agents, ep.Agent)
+		}
+		byAgent[ep.Agent] = append(byAgent[ep.Agent], ep)
+	}
+
+	written := 0
+	for _, agent := range agents {
+		path := e.path(agent, date)
+		if _, err := os.Stat(path); err == nil {
+			continue
+		}
+		summary, err := e.summarize(ctx, summaryPrompt(agent, date, byAgent[agent]))
+		if err != nil {
+			return written, err
+		}
+		summary = strings.TrimSpace(summary)
+		if summary == "" {
+			continue
+		}
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			return written, fmt.Errorf("failed to create summaries dir: %w", err)
+		}
+		content := fmt.Sprintf("# %s, %s\n\n%s\n", agent, date.Format("Monday, January 2, 2006"), summary)
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			return written, err
+		}
+		written++
+
+		if e.index != nil {
+			text := fmt.Sprintf("Conversations on %s:\n%s", date.Format(dateFormat), summary)
+			_ = e.index.Remember(ctx, agent, KindSummary, "summary-"+date.Format(dateFormat), text)
+		}
+	}
+	return written, nil
+}
+
+// Summaries returns the summaries of agent from since on, newest first.
+func (e *EpisodicMemory) Summaries(agent string, since time.Time) ([]Summary, error) {
+	if err := checkAgentName(agent); err != nil {
+		return nil, err
+	}
+	dir := filepath.Join(e.dir, agent)
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	first := since.Format(dateFormat)
+	var summaries []Summary
+	for _, entry := range entries {
+		name := strings.TrimSuffix(entry.Name(), ".md")
+		date, err := time.ParseInLocation(dateFormat, name, time.Local)
+		if err != nil || entry.IsDir() || name < first {
+			continue
+		}
+		path := filepath.Join(dir, entry.Name())
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		summaries = append(summaries, Summary{Agent: agent, Date: date, Path: path, Content: string(content)})
+	}
+	sort.Slice(summaries, func(i, j int) bool { return summaries[i].Date.After(summaries[j].Date) })
+	return summaries, nil
+}
+
+// Prune removes the summaries of agent for days before before, and its
+// journal episodes up to before so those days are not summarized again. It
+// returns how many summaries were removed.
+func (e *EpisodicMemory) Prune(agent string, before time.Time) (int, error) {
+	summaries, err := e.Summaries(agent, time.Time{})
+	if err != nil {
+		return 0, err
+	}
+	removed := 0
+	last := before.Format(dateFormat)
+	for _, s := range summaries {
+		if s.Date.Format(dateFormat) >= last {
+			continue
+		}
+		if err := os.Remove(s.Path); err != nil {
+			return removed, err
+		}
+		if e.index != nil {
+			_ = e.index.Forget(context.Background(), agent, "summary-"+s.Date.Format(dateFormat))
+		}
+		removed++
+	}
+	if _, err := e.journal.Prune(agent, before); err != nil {
+		return removed, err
+	}
+	return removed, nil
+}
+
+func (e *EpisodicMemory) path(agent string, date time.Time) string {
+	return filepath.Join(e.dir, agent, date.Format(dateFormat)+".md")
+}
+
+// maxTranscript caps the transcript sent for one summary.
+const maxTranscript = 60000
+
+func summaryPrompt(agent string, date time.Time, episodes []Episode) string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, `Summarize the conversations the agent %q had on %s for its long-term memory.
+Write concise bullet points grouped by topic: what was discussed, decisions made, people and
+projects involved, and open questions or follow-ups. Keep names, numbers and dates. Write only
+the summary, under 300 words.
+
+Conversations:
+`, agent, date.Format(dateFormat))
+
+	for _, ep := range episodes {
+		if sb.Len() > maxTranscript {
+			sb.WriteString("\n[later conversations omitted]\n")
+			break
+		}
+		fmt.Fprintf(&sb, "\n[%s] User: %s\nAssistant: %s\n", ep.Time.Format("15:04"), clip(ep.User, 2000), clip(ep.Reply, 2000))
+	}
+	return sb.String()
+}
+
+func clip(s string, max int) string {
+	s = strings.TrimSpace(s)
+	if len(s) > max {
+		return s[:max] + "..."
+	}
+	return s
+}
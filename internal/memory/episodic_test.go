@@ -0,0 +1,142 @@
+package memory
+
+import (
+	"context"
+	"os"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestEpisodicMemory(t *testing.T) {
+	dir := t.TempDir()
+	journal := NewJournal(dir)
+	today := time.Now()
+	yesterday := today.AddDate(0, 0, -1)
+	lastWeek := today.AddDate(0, 0, -8)
+
+	for _, ep := range []Episode{
+		{Agent: "support", User: "Can we change the pricing page?", Reply: "Yes, I drafted new copy.", Time: lastWeek},
+		{Agent: "support", User: "Ship the pricing page", Reply: "Shipped.", Time: yesterday},
+		{Agent: "coder", User: "Fix the login bug", Reply: "Fixed in #42.", Time: yesterday},
+		{Agent: "support", User: "Anything new?", Reply: "Not yet.", Time: today},
+	} {
+		if err := journal.Append(ep); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	days, err := journal.Days()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(days) != 3 || days[0].Format(dateFormat) != lastWeek.Format(dateFormat) {
+		t.Fatalf("unexpected journal days: %v", days)
+	}
+
+	var prompts []string
+	episodic := NewEpisodicMemory(dir, journal, func(_ context.Context, prompt string) (string, error) {
+		prompts = append(prompts, prompt)
+		return "- summary " + string(rune('A'+len(prompts)-1)), nil
+	})
+	if err := episodic.CatchUp(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	// last week (support), yesterday (support, coder); today is not finished
+	if len(prompts) != 3 {
+		t.Fatalf("expected 3 summaries, got %d", len(prompts))
+	}
+	if !strings.Contains(prompts[1], "Ship the pricing page") || strings.Contains(prompts[1], "login bug") {
+		t.Errorf("summary prompt should hold the agent's conversations only:\n%s", prompts[1])
+	}
+
+	// Summarized days are not summarized again
+	if err := episodic.CatchUp(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if len(prompts) != 3 {
+		t.Errorf("days were summarized again, got %d prompts", len(prompts))
+	}
+
+	recent, err := episodic.Summaries("support", today.AddDate(0, 0, -7))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(recent) != 1 || !strings.Contains(recent[0].Content, "summary B") {
+		t.Fatalf("expected yesterday's summary only, got %+v", recent)
+	}
+	all, _ := episodic.Summaries("support", time.Time{})
+	if len(all) != 2 || !all[0].Date.After(all[1].Date) {
+		t.Errorf("expected both summaries newest first, got %+v", all)
+	}
+	if _, err := os.Stat(episodic.path("coder", yesterday)); err != nil {
+		t.Errorf("coder summary missing: %v", err)
+	}
+}
+
+func TestJournal_SkipsTornLines(t *testing.T) {
+	journal := NewJournal(t.TempDir())
+	now := time.Now()
+	if err := journal.Append(Episode{Agent: "klaw", User: "hi", Reply: "hello", Time: now}); err != nil {
+		t.Fatal(err)
+	}
+	f, err := os.OpenFile(journal.path(now), os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, _ = f.WriteString(`{"agent":"klaw","us`)
+	_ = f.Close()
+
+	episodes, err := journal.Day(now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(episodes) != 1 || episodes[0].Reply != "hello" {
+		t.Errorf("unexpected episodes: %+v", episodes)
+	}
+}
+
+func TestEpisodicMemory_Prune(t *testing.T) {
+	dir := t.TempDir()
+	journal := NewJournal(dir)
+	today := time.Now()
+	old := today.AddDate(0, 0, -40)
+
+	for _, ep := range []Episode{
+		{Agent: "support", User: "old question", Reply: "old answer", Time: old},
+		{Agent: "coder", User: "old bug", Reply: "fixed", Time: old},
+		{Agent: "support", User: "new question", Reply: "new answer", Time: today},
+	} {
+		if err := journal.Append(ep); err != nil {
+			t.Fatal(err)
+		}
+	}
+	episodic := NewEpisodicMemory(dir, journal, func(context.Context, string) (string, error) {
+		return "- summary", nil
+	})
+	if err := episodic.CatchUp(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	removed, err := episodic.Prune("support", today.AddDate(0, 0, -30))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if removed != 1 {
+		t.Errorf("expected 1 summary removed, got %d", removed)
+	}
+	if got, _ := episodic.Summaries("support", time.Time{}); len(got) != 0 {
+		t.Errorf("support summaries left: %+v", got)
+	}
+	if got, _ := episodic.Summaries("coder", time.Time{}); len(got) != 1 {
+		t.Errorf("other agents' summaries should be kept, got %+v", got)
+	}
+
+	episodes, _ := journal.Day(old)
+	if len(episodes) != 1 || episodes[0].Agent != "coder" {
+		t.Errorf("expected only coder's old episode, got %+v", episodes)
+	}
+	if episodes, _ := journal.Day(today); len(episodes) != 1 {
+		t.Errorf("recent episodes should be kept, got %+v", episodes)
+	}
+}
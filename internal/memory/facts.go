@@ -0,0 +1,351 @@
+package memory
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Fact is a durable piece of knowledge saved by an agent.
+type Fact struct {
+	ID        string    `json:"id"`
+	Content   string    `json:"content"`
+	Tags      []string  `json:"tags,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+	LastUsed  time.Time `json:"last_used,omitempty"`
+}
+
+// lastUsed returns when the fact was last saved or recalled.
+func (f Fact) lastUsed() time.Time {
+	if f.LastUsed.After(f.CreatedAt) {
+		return f.LastUsed
+	}
+	return f.CreatedAt
+}
+
+// FactStore persists facts per agent as JSON files in the workspace
+// (memory/facts/<agent>.json).
+type FactStore struct {
+	dir    string
+	mu     sync.Mutex
+	index  *SemanticMemory
+	limits Limits
+}
+
+// NewFactStore creates a fact store in workspaceDir.
+func NewFactStore(workspaceDir string) *FactStore {
+	return &FactStore{dir: filepath.Join(workspaceDir, "memory", "facts")}
+}
+
+// SetIndex also keeps facts in semantic memory, so they are recalled by
+// similarity. Indexing is best effort: Search still finds facts missing from
+// the index.
+func (s *FactStore) SetIndex(index *SemanticMemory) {
+	s.index = index
+}
+
+// SetLimits caps the facts kept per agent. Facts beyond the limits are
+// evicted when new ones are saved and by Evict.
+func (s *FactStore) SetLimits(limits Limits) {
+	s.limits = limits
+}
+
+// indexFact adds or refreshes fact in the semantic index.
+func (s *FactStore) indexFact(agent string, fact Fact) {
+	if s.index == nil {
+		return
+	}
+	content := fact.Content
+	if len(fact.Tags) > 0 {
+		content += " (" + strings.Join(fact.Tags, ", ") + ")"
+	}
+	_ = s.index.Remember(context.Background(), agent, KindFact, fact.ID, content)
+}
+
+// checkAgentName rejects agent names that are unsafe in file paths.
+func checkAgentName(agent string) error {
+	if agent == "" || strings.ContainsAny(agent, `/\`) || agent == "." || agent == ".." {
+		return fmt.Errorf("invalid agent name: %q", agent)
+	}
+	return nil
+}
+
+func (s *FactStore) file(agent string) (string, error) {
+	if err := checkAgentName(agent); err != nil {
+		return "", err
+	}
+	return filepath.Join(s.dir, agent+".json"), nil
+}
+
+func (s *FactStore) load(agent string) ([]Fact, error) {
+	path, err := s.file(agent)
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	var facts []Fact
+	if err := json.Unmarshal(data, &facts); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	return facts, nil
+}
+
+func (s *FactStore) save(agent string, facts []Fact) error {
+	path, err := s.file(agent)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(s.dir, 0755); err != nil {
+		return fmt.Errorf("failed to create facts dir: %w", err)
+	}
+	data, err := json.MarshalIndent(facts, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+// Add saves a fact for agent. Saving the same content again refreshes the
+// existing fact instead of duplicating it.
+func (s *FactStore) Add(agent, content string, tags []string) (*Fact, error) {
+	content = strings.TrimSpace(content)
+	if content == "" {
+		return nil, fmt.Errorf("fact content is empty")
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	facts, err := s.load(agent)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range facts {
+		if strings.EqualFold(facts[i].Content, content) {
+			facts[i].Tags = tags
+			facts[i].CreatedAt = time.Now()
+			fact := facts[i]
+			if err := s.save(agent, facts); err != nil {
+				return nil, err
+			}
+			s.indexFact(agent, fact)
+			return &fact, nil
+		}
+	}
+
+	fact := Fact{
+		ID:        uuid.New().String()[:8],
+		Content:   content,
+		Tags:      tags,
+		CreatedAt: time.Now(),
+	}
+	facts, evicted := s.limits.evict(append(facts, fact), time.Now())
+	if err := s.save(agent, facts); err != nil {
+		return nil, err
+	}
+	s.forget(agent, evicted)
+	s.indexFact(agent, fact)
+	return &fact, nil
+}
+
+// Evict removes the facts of agent beyond the limits and returns how many
+// were removed.
+func (s *FactStore) Evict(agent string) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	facts, err := s.load(agent)
+	if err != nil {
+		return 0, err
+	}
+	kept, evicted := s.limits.evict(facts, time.Now())
+	if len(evicted) == 0 {
+		return 0, nil
+	}
+	if err := s.save(agent, kept); err != nil {
+		return 0, err
+	}
+	s.forget(agent, evicted)
+	return len(evicted), nil
+}
+
+// Touch marks the facts with ids as recalled, which keeps them from being
+// evicted as unused.
+func (s *FactStore) Touch(agent string, ids ...string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	facts, err := s.load(agent)
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+	touched := false
+	for i := range facts {
+		for _, id := range ids {
+			if facts[i].ID == id {
+				facts[i].LastUsed = now
+				touched = true
+			}
+		}
+	}
+	if !touched {
+		return nil
+	}
+	return s.save(agent, facts)
+}
+
+// Agents returns the agents with saved facts.
+func (s *FactStore) Agents() ([]string, error) {
+	entries, err := os.ReadDir(s.dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	var agents []string
+	for _, e := range entries {
+		if !e.IsDir() && strings.HasSuffix(e.Name(), ".json") {
+			agents = append(agents, strings.TrimSuffix(e.Name(), ".json"))
+		}
+	}
+	return agents, nil
+}
+
+// forget removes facts from the semantic index.
+func (s *FactStore) forget(agent string, facts []Fact) {
+	if s.index == nil {
+		return
+	}
+	for _, f := range facts {
+		_ = s.index.Forget(context.Background(), agent, f.ID)
+	}
+}
+
+// List returns all facts for agent, newest first.
+func (s *FactStore) List(agent string) ([]Fact, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	facts, err := s.load(agent)
+	if err != nil {
+		return nil, err
+	}
+	sort.SliceStable(facts, func(i, j int) bool {
+		return facts[i].CreatedAt.After(facts[j].CreatedAt)
+	})
+	return facts, nil
+}
+
+// Search returns up to limit facts for agent matching the words of query,
+// best matches first. An empty query returns the most recent facts.
+func (s *FactStore) Search(agent, query string, limit int) ([]Fact, error) {
+	facts, err := s.List(agent)
+	if err != nil {
+		return nil, err
+	}
+
+	words := strings.Fields(strings.ToLower(query))
+	if len(words) > 0 {
+		type scored struct {
+			fact  Fact
+			score int
+		}
+		var matches []scored
+		for _, f := range facts {
+			text := strings.ToLower(f.Content + " " + strings.Join(f.Tags, " "))
+			score := 0
+			for _, w := range words {
+				if strings.Contains(text, w) {
+					score++
+				}
+			}
+			if score > 0 {
+				matches = append(matches, scored{f, score})
+			}
+		}
+		sort.SliceStable(matches, func(i, j int) bool {
+			return matches[i].score > matches[j].score
+		})
+		facts = facts[:0]
+		for _, m := range matches {
+			facts = append(facts, m.fact)
+		}
+	}
+
+	if limit > 0 && len(facts) > limit {
+		facts = facts[:limit]
+	}
+	return facts, nil
+}
+
+// Prune removes the facts of agent saved before before and returns how many
+// were removed.
+func (s *FactStore) Prune(agent string, before time.Time) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	facts, err := s.load(agent)
+	if err != nil {
+		return 0, err
+	}
+	var kept, removed []Fact
+	for _, f := range facts {
+		if f.CreatedAt.Before(before) {
+			removed = append(removed, f)
+		} else {
+			kept = append(kept, f)
+		}
+	}
+	if len(removed) == 0 {
+		return 0, nil
+	}
+	if err := s.save(agent, kept); err != nil {
+		return 0, err
+	}
+	s.forget(agent, removed)
+	return len(removed), nil
+}
+
+// Delete removes a fact by ID.
+func (s *FactStore) Delete(agent, id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	facts, err := s.load(agent)
+	if err != nil {
+		return err
+	}
+	for i, f := range facts {
+		if f.ID == id {
+			if err := s.save(agent, append(facts[:i], facts[i+1:]...)); err != nil {
+				return err
+			}
+			if s.index != nil {
+				_ = s.index.Forget(context.Background(), agent, id)
+			}
+			return nil
+		}
+	}
+	return fmt.Errorf("fact not found: %s", id)
+}
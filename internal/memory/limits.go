@@ -0,0 +1,214 @@
+package memory
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/eachlabs/klaw/internal/config"
+)
+
+// Default limits, used when a limit is not configured.
+const (
+	DefaultMaxFacts    = 500
+	DefaultMaxFileSize = 16 * 1024
+)
+
+// Limits caps how much is remembered, so memory does not bloat every prompt.
+// Zero values use the defaults and negative values disable a limit.
+type Limits struct {
+	MaxFacts    int           // facts per agent; the least recently used are evicted
+	FactTTL     time.Duration // facts neither saved nor recalled for this long are evicted (0: never)
+	MaxFileSize int           // bytes of a MEMORY.md before it is compacted
+}
+
+// LimitsFromConfig returns the limits of the [memory] section.
+func LimitsFromConfig(cfg *config.Config) Limits {
+	return Limits{
+		MaxFacts:    cfg.Memory.MaxFacts,
+		FactTTL:     time.Duration(cfg.Memory.FactTTLDays) * 24 * time.Hour,
+		MaxFileSize: cfg.Memory.MaxFileSize,
+	}
+}
+
+func (l Limits) maxFacts() int {
+	if l.MaxFacts == 0 {
+		return DefaultMaxFacts
+	}
+	return l.MaxFacts
+}
+
+func (l Limits) maxFileSize() int {
+	if l.MaxFileSize == 0 {
+		return DefaultMaxFileSize
+	}
+	return l.MaxFileSize
+}
+
+// evict splits facts into those kept under l and those evicted: first the
+// facts unused for longer than FactTTL, then the least recently used beyond
+// MaxFacts.
+func (l Limits) evict(facts []Fact, now time.Time) (kept, evicted []Fact) {
+	for _, f := range facts {
+		if l.FactTTL > 0 && now.Sub(f.lastUsed()) > l.FactTTL {
+			evicted = append(evicted, f)
+		} else {
+			kept = append(kept, f)
+		}
+	}
+	if max := l.maxFacts(); max > 0 && len(kept) > max {
+		sort.SliceStable(kept, func(i, j int) bool { return kept[i].lastUsed().After(kept[j].lastUsed()) })
+		evicted = append(evicted, kept[max:]...)
+		kept = kept[:max]
+	}
+	return kept, evicted
+}
+
+// Compactor keeps memory within its limits: it evicts facts and condenses
+// MEMORY.md files that grew past MaxFileSize with an LLM.
+type Compactor struct {
+	workspaceDir string
+	facts        *FactStore
+	limits       Limits
+	summarize    SummarizeFunc
+}
+
+// NewCompactor creates a compactor for the memory in workspaceDir.
+func NewCompactor(workspaceDir string, facts *FactStore, limits Limits, summarize SummarizeFunc) *Compactor {
+	return &Compactor{
+		workspaceDir: workspaceDir,
+		facts:        facts,
+		limits:       limits,
+		summarize:    summarize,
+	}
+}
+
+// Run compacts memory now and then every interval until ctx is done. Errors
+// are passed to onError and retried on the next run.
+func (c *Compactor) Run(ctx context.Context, interval time.Duration, onError func(error)) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		if err := c.CompactAll(ctx); err != nil && ctx.Err() == nil && onError != nil {
+			onError(err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// CompactAll evicts the facts of every agent and compacts every MEMORY.md
+// of the workspace: its own, the agents' and the namespaces'.
+func (c *Compactor) CompactAll(ctx context.Context) error {
+	var errs []error
+	if c.facts != nil {
+		agents, err := c.facts.Agents()
+		if err != nil {
+			errs = append(errs, err)
+		}
+		for _, agent := range agents {
+			if _, err := c.facts.Evict(agent); err != nil {
+				errs = append(errs, fmt.Errorf("facts of %s: %w", agent, err))
+			}
+		}
+	}
+	for _, path := range c.memoryFiles() {
+		if _, err := c.CompactFile(ctx, path); err != nil {
+			errs = append(errs, fmt.Errorf("compact %s: %w", path, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// memoryFiles returns the MEMORY.md files of the workspace.
+func (c *Compactor) memoryFiles() []string {
+	var files []string
+	for _, pattern := range []string{
+		filepath.Join(c.workspaceDir, "MEMORY.md"),
+		filepath.Join(AgentDir(c.workspaceDir, "*"), "MEMORY.md"),
+		filepath.Join(NamespaceDir(c.workspaceDir, "*", "*"), "MEMORY.md"),
+	} {
+		matches, _ := filepath.Glob(pattern)
+		files = append(files, matches...)
+	}
+	return files
+}
+
+// CompactFile condenses the MEMORY.md at path when it is larger than
+// MaxFileSize and reports whether it did. The previous content is archived
+// in memory/archive next to the file.
+func (c *Compactor) CompactFile(ctx context.Context, path string) (bool, error) {
+	max := c.limits.maxFileSize()
+	if max <= 0 {
+		return false, nil
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return false, nil
+		}
+		return false, err
+	}
+	if len(data) <= max {
+		return false, nil
+	}
+	if c.summarize == nil {
+		return false, fmt.Errorf("no summarizer configured")
+	}
+
+	compacted, err := c.summarize(ctx, compactPrompt(string(data), max/2))
+	if err != nil {
+		return false, err
+	}
+	compacted = strings.TrimSpace(compacted)
+	if compacted == "" {
+		return false, fmt.Errorf("summarizer returned nothing")
+	}
+	if len(compacted) > max {
+		compacted = clipLines(compacted, max)
+	}
+
+	archive := filepath.Join(filepath.Dir(path), "memory", "archive", "MEMORY-"+time.Now().Format("2006-01-02-150405")+".md")
+	if err := os.MkdirAll(filepath.Dir(archive), 0755); err != nil {
+		return false, fmt.Errorf("failed to create archive dir: %w", err)
+	}
+	if err := os.WriteFile(archive, data, 0644); err != nil {
+		return false, err
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, []byte(compacted+"\n"), 0644); err != nil {
+		return false, err
+	}
+	return true, os.Rename(tmp, path)
+}
+
+func compactPrompt(content string, size int) string {
+	return fmt.Sprintf(`The following memory file of an AI agent has grown too large. Rewrite it to under
+%d characters for the agent's long-term memory: merge duplicates, drop what is outdated or
+superseded, and keep names, numbers, dates, preferences and standing instructions. Keep its
+markdown structure. Write only the new file.
+
+Memory file:
+
+%s`, size, content)
+}
+
+// clipLines cuts s to at most max bytes at a line boundary.
+func clipLines(s string, max int) string {
+	if len(s) <= max {
+		return s
+	}
+	cut := s[:max]
+	if i := strings.LastIndexByte(cut, '\n'); i > 0 {
+		cut = cut[:i]
+	}
+	return cut
+}
@@ -0,0 +1,109 @@
+package memory
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestFactStore_EvictsLeastRecentlyUsed(t *testing.T) {
+	facts := NewFactStore(t.TempDir())
+	facts.SetLimits(Limits{MaxFacts: 2})
+
+	first, err := facts.Add("klaw", "first fact", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(5 * time.Millisecond)
+	if _, err := facts.Add("klaw", "second fact", nil); err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(5 * time.Millisecond)
+	// Recalling the first fact makes the second the least recently used
+	if err := facts.Touch("klaw", first.ID); err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(5 * time.Millisecond)
+	if _, err := facts.Add("klaw", "third fact", nil); err != nil {
+		t.Fatal(err)
+	}
+
+	list, _ := facts.List("klaw")
+	var got []string
+	for _, f := range list {
+		got = append(got, f.Content)
+	}
+	if strings.Join(got, ",") != "third fact,first fact" {
+		t.Errorf("unexpected facts after eviction: %v", got)
+	}
+}
+
+func TestFactStore_EvictsUnusedFacts(t *testing.T) {
+	facts := NewFactStore(t.TempDir())
+	if _, err := facts.Add("klaw", "stale fact", nil); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := facts.Add("klaw", "fresh fact", nil); err != nil {
+		t.Fatal(err)
+	}
+
+	// Age the stale fact
+	list, _ := facts.load("klaw")
+	for i := range list {
+		if list[i].Content == "stale fact" {
+			list[i].CreatedAt = time.Now().AddDate(0, 0, -40)
+		}
+	}
+	if err := facts.save("klaw", list); err != nil {
+		t.Fatal(err)
+	}
+
+	facts.SetLimits(Limits{FactTTL: 30 * 24 * time.Hour})
+	removed, err := facts.Evict("klaw")
+	if err != nil {
+		t.Fatal(err)
+	}
+	list, _ = facts.List("klaw")
+	if removed != 1 || len(list) != 1 || list[0].Content != "fresh fact" {
+		t.Errorf("expected the stale fact evicted, removed %d, left %+v", removed, list)
+	}
+}
+
+func TestCompactor_CompactsLargeMemoryFiles(t *testing.T) {
+	ws := t.TempDir()
+	small := filepath.Join(AgentDir(ws, "support"), "MEMORY.md")
+	if err := AppendNote(filepath.Dir(small), "short note"); err != nil {
+		t.Fatal(err)
+	}
+	large := filepath.Join(ws, "MEMORY.md")
+	if err := os.WriteFile(large, []byte(strings.Repeat("- the same note again\n", 20)), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	var prompts []string
+	c := NewCompactor(ws, nil, Limits{MaxFileSize: 100}, func(_ context.Context, prompt string) (string, error) {
+		prompts = append(prompts, prompt)
+		return "- the same note", nil
+	})
+	if err := c.CompactAll(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(prompts) != 1 || !strings.Contains(prompts[0], "the same note again") {
+		t.Fatalf("expected one compaction of the large file, got %d", len(prompts))
+	}
+	data, _ := os.ReadFile(large)
+	if string(data) != "- the same note\n" {
+		t.Errorf("unexpected compacted file: %q", data)
+	}
+	archived, _ := filepath.Glob(filepath.Join(ws, "memory", "archive", "MEMORY-*.md"))
+	if len(archived) != 1 {
+		t.Errorf("expected the previous file archived, got %v", archived)
+	}
+	if data, _ := os.ReadFile(small); string(data) != "- short note\n" {
+		t.Errorf("small file should be untouched, got %q", data)
+	}
+}
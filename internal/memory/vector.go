@@ -0,0 +1,186 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/eachlabs/klaw/internal/config"
+)
+
+// DefaultAgent owns the memories of runs that are not bound to a named agent.
+const DefaultAgent = "klaw"
+
+// Kinds of semantic memory entries.
+const (
+	KindFact         = "fact"
+	KindConversation = "conversation"
+)
+
+// VectorEntry is a piece of text kept with its embedding.
+type VectorEntry struct {
+	ID        string
+	Agent     string
+	Kind      string
+	Content   string
+	Vector    []float32
+	CreatedAt time.Time
+	Score     float64 // similarity to the query, set by Search
+}
+
+// Embedder turns texts into embedding vectors.
+type Embedder interface {
+	Embed(ctx context.Context, texts []string) ([][]float32, error)
+}
+
+// VectorStore keeps embedded entries per agent and finds the nearest ones.
+type VectorStore interface {
+	// Upsert adds entries, replacing those with the same agent and ID.
+	Upsert(ctx context.Context, entries []VectorEntry) error
+
+	// Search returns up to k entries of agent closest to vector, best first.
+	Search(ctx context.Context, agent string, vector []float32, k int) ([]VectorEntry, error)
+
+	// Delete removes an entry.
+	Delete(ctx context.Context, agent, id string) error
+
+	// Prune removes the entries of agent created before before.
+	Prune(ctx context.Context, agent string, before time.Time) error
+
+	Close() error
+}
+
+// SemanticMemory recalls facts and past conversations by similarity.
+type SemanticMemory struct {
+	embedder Embedder
+	store    VectorStore
+}
+
+// NewSemanticMemory creates a semantic memory.
+func NewSemanticMemory(embedder Embedder, store VectorStore) *SemanticMemory {
+	return &SemanticMemory{embedder: embedder, store: store}
+}
+
+// OpenFromConfig opens the semantic memory selected by the [memory] config
+// section. It returns nil when no backend is configured.
+func OpenFromConfig(cfg *config.Config) (*SemanticMemory, error) {
+	mc := cfg.Memory
+	var store VectorStore
+	switch mc.Backend {
+	case "", "none":
+		return nil, nil
+	case "sqlite":
+		path := mc.Path
+		if path == "" {
+			path = filepath.Join(cfg.WorkspaceDir(), "memory", "vectors.db")
+		}
+		s, err := NewSQLiteVectorStore(path)
+		if err != nil {
+			return nil, err
+		}
+		store = s
+	case "qdrant":
+		if mc.URL == "" {
+			return nil, fmt.Errorf("memory backend qdrant requires url (e.g. [memory] url = \"http://localhost:6333\")")
+		}
+		store = NewQdrantStore(mc.URL, mc.APIKey, mc.Collection)
+	default:
+		return nil, fmt.Errorf("unknown memory backend: %s (use sqlite or qdrant)", mc.Backend)
+	}
+
+	providerName := mc.EmbeddingProvider
+	if providerName == "" {
+		providerName = "openai"
+	}
+	prov := cfg.Provider[providerName]
+	if prov.APIKey == "" && prov.BaseURL == "" {
+		_ = store.Close()
+		return nil, fmt.Errorf("semantic memory needs an embedding provider: set [provider.%s] api_key or base_url", providerName)
+	}
+	return NewSemanticMemory(NewOpenAIEmbedder(prov.APIKey, prov.BaseURL, mc.EmbeddingModel), store), nil
+}
+
+// Close closes the vector store.
+func (m *SemanticMemory) Close() error {
+	return m.store.Close()
+}
+
+// Remember embeds content and keeps it for agent. An empty id gets a new
+// one; remembering an existing id replaces its entry.
+func (m *SemanticMemory) Remember(ctx context.Context, agent, kind, id, content string) error {
+	content = strings.TrimSpace(content)
+	if content == "" {
+		return nil
+	}
+	if id == "" {
+		id = uuid.New().String()
+	}
+	vectors, err := m.embedder.Embed(ctx, []string{content})
+	if err != nil {
+		return fmt.Errorf("failed to embed memory: %w", err)
+	}
+	return m.store.Upsert(ctx, []VectorEntry{{
+		ID:        id,
+		Agent:     agent,
+		Kind:      kind,
+		Content:   content,
+		Vector:    vectors[0],
+		CreatedAt: time.Now(),
+	}})
+}
+
+// Recall returns up to k memories of agent most similar to query, best
+// first, leaving out those scoring below minScore.
+func (m *SemanticMemory) Recall(ctx context.Context, agent, query string, k int, minScore float64) ([]VectorEntry, error) {
+	if strings.TrimSpace(query) == "" || k <= 0 {
+		return nil, nil
+	}
+	vectors, err := m.embedder.Embed(ctx, []string{query})
+	if err != nil {
+		return nil, fmt.Errorf("failed to embed query: %w", err)
+	}
+	entries, err := m.store.Search(ctx, agent, vectors[0], k)
+	if err != nil {
+		return nil, err
+	}
+	var matches []VectorEntry
+	for _, e := range entries {
+		if e.Score >= minScore {
+			matches = append(matches, e)
+		}
+	}
+	return matches, nil
+}
+
+// Forget removes a memory.
+func (m *SemanticMemory) Forget(ctx context.Context, agent, id string) error {
+	return m.store.Delete(ctx, agent, id)
+}
+
+// Prune removes the memories of agent created before before.
+func (m *SemanticMemory) Prune(ctx context.Context, agent string, before time.Time) error {
+	return m.store.Prune(ctx, agent, before)
+}
+
+// cosine returns the cosine similarity of two vectors, 0 if they differ in
+// length or either is zero.
+func cosine(a, b []float32) float64 {
+	if len(a) != len(b) {
+		return 0
+	}
+	var dot, na, nb float64
+	for i := range a {
+		dot += float64(a[i]) * float64(b[i])
+		na += float64(a[i]) * float64(a[i])
+		nb += float64(b[i]) * float64(b[i])
+	}
+	if na == 0 || nb == 0 {
+		return 0
+	}
+	return dot / (math.Sqrt(na) * math.Sqrt(nb))
+}
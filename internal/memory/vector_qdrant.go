@@ -0,0 +1,239 @@
+package memory
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// DefaultQdrantCollection is used when no collection is configured.
+const DefaultQdrantCollection = "klaw_memory"
+
+// qdrantNamespace derives point IDs, which Qdrant requires to be UUIDs,
+// from agent and entry IDs.
+var qdrantNamespace = uuid.MustParse("6f1c3b0e-5d2a-4c8e-9b7a-2e4f6a8c0d1e")
+
+// QdrantStore keeps embeddings in a Qdrant collection, created on first
+// use. Entries of all agents share the collection and are filtered by agent.
+type QdrantStore struct {
+	baseURL    string
+	apiKey     string
+	collection string
+	client     *http.Client
+
+	mu    sync.Mutex
+	ready bool // collection exists
+}
+
+// NewQdrantStore creates a store for the Qdrant server at baseURL.
+func NewQdrantStore(baseURL, apiKey, collection string) *QdrantStore {
+	if collection == "" {
+		collection = DefaultQdrantCollection
+	}
+	return &QdrantStore{
+		baseURL:    strings.TrimRight(baseURL, "/"),
+		apiKey:     apiKey,
+		collection: collection,
+		client:     &http.Client{Timeout: 30 * time.Second},
+	}
+}
+
+func (s *QdrantStore) Close() error {
+	return nil
+}
+
+type qdrantPayload struct {
+	Agent     string `json:"agent"`
+	ID        string `json:"id"`
+	Kind      string `json:"kind"`
+	Content   string `json:"content"`
+	CreatedAt int64  `json:"created_at"`
+}
+
+type qdrantPoint struct {
+	ID      string        `json:"id"`
+	Vector  []float32     `json:"vector,omitempty"`
+	Payload qdrantPayload `json:"payload"`
+	Score   float64       `json:"score,omitempty"`
+}
+
+func (s *QdrantStore) Upsert(ctx context.Context, entries []VectorEntry) error {
+	if len(entries) == 0 {
+		return nil
+	}
+	if err := s.ensureCollection(ctx, len(entries[0].Vector)); err != nil {
+		return err
+	}
+	points := make([]qdrantPoint, len(entries))
+	for i, e := range entries {
+		points[i] = qdrantPoint{
+			ID:     pointID(e.Agent, e.ID),
+			Vector: e.Vector,
+			Payload: qdrantPayload{
+				Agent:     e.Agent,
+				ID:        e.ID,
+				Kind:      e.Kind,
+				Content:   e.Content,
+				CreatedAt: e.CreatedAt.Unix(),
+			},
+		}
+	}
+	_, err := s.do(ctx, http.MethodPut, "/points?wait=true", map[string]any{"points": points})
+	return err
+}
+
+func (s *QdrantStore) Search(ctx context.Context, agent string, vector []float32, k int) ([]VectorEntry, error) {
+	body, err := s.do(ctx, http.MethodPost, "/points/search", map[string]any{
+		"vector":       vector,
+		"limit":        k,
+		"with_payload": true,
+		"filter":       agentFilter(agent),
+	})
+	if err != nil {
+		if isQdrantNotFound(err) {
+			return nil, nil // nothing remembered yet
+		}
+		return nil, err
+	}
+
+	var resp struct {
+		Result []qdrantPoint `json:"result"`
+	}
+	if err := json.Unmarshal(body, &resp); err != nil {
+		return nil, fmt.Errorf("invalid qdrant response: %w", err)
+	}
+	entries := make([]VectorEntry, len(resp.Result))
+	for i, p := range resp.Result {
+		entries[i] = VectorEntry{
+			ID:        p.Payload.ID,
+			Agent:     p.Payload.Agent,
+			Kind:      p.Payload.Kind,
+			Content:   p.Payload.Content,
+			CreatedAt: time.Unix(p.Payload.CreatedAt, 0),
+			Score:     p.Score,
+		}
+	}
+	return entries, nil
+}
+
+func (s *QdrantStore) Delete(ctx context.Context, agent, id string) error {
+	_, err := s.do(ctx, http.MethodPost, "/points/delete?wait=true", map[string]any{
+		"points": []string{pointID(agent, id)},
+	})
+	if isQdrantNotFound(err) {
+		return nil
+	}
+	return err
+}
+
+func (s *QdrantStore) Prune(ctx context.Context, agent string, before time.Time) error {
+	filter := map[string]any{
+		"must": []map[string]any{
+			{"key": "agent", "match": map[string]any{"value": agent}},
+			{"key": "created_at", "range": map[string]any{"lt": before.Unix()}},
+		},
+	}
+	_, err := s.do(ctx, http.MethodPost, "/points/delete?wait=true", map[string]any{"filter": filter})
+	if isQdrantNotFound(err) {
+		return nil
+	}
+	return err
+}
+
+// ensureCollection creates the collection unless it exists.
+func (s *QdrantStore) ensureCollection(ctx context.Context, size int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.ready {
+		return nil
+	}
+	if _, err := s.do(ctx, http.MethodGet, "", nil); err == nil {
+		s.ready = true
+		return nil
+	} else if !isQdrantNotFound(err) {
+		return err
+	}
+	if _, err := s.do(ctx, http.MethodPut, "", map[string]any{
+		"vectors": map[string]any{"size": size, "distance": "Cosine"},
+	}); err != nil {
+		return fmt.Errorf("failed to create qdrant collection %s: %w", s.collection, err)
+	}
+	if _, err := s.do(ctx, http.MethodPut, "/index", map[string]any{
+		"field_name":   "agent",
+		"field_schema": "keyword",
+	}); err != nil {
+		return fmt.Errorf("failed to index qdrant collection %s: %w", s.collection, err)
+	}
+	s.ready = true
+	return nil
+}
+
+type qdrantError struct {
+	status int
+	body   string
+}
+
+func (e *qdrantError) Error() string {
+	return fmt.Sprintf("qdrant: HTTP %d: %s", e.status, e.body)
+}
+
+func isQdrantNotFound(err error) bool {
+	qe, ok := err.(*qdrantError)
+	return ok && qe.status == http.StatusNotFound
+}
+
+// do sends a request to the collection endpoint path and returns the body.
+func (s *QdrantStore) do(ctx context.Context, method, path string, payload any) ([]byte, error) {
+	var reqBody io.Reader
+	if payload != nil {
+		data, err := json.Marshal(payload)
+		if err != nil {
+			return nil, err
+		}
+		reqBody = bytes.NewReader(data)
+	}
+	endpoint := s.baseURL + "/collections/" + url.PathEscape(s.collection) + path
+	req, err := http.NewRequestWithContext(ctx, method, endpoint, reqBody)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if s.apiKey != "" {
+		req.Header.Set("api-key", s.apiKey)
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode >= 300 {
+		return nil, &qdrantError{status: resp.StatusCode, body: strings.TrimSpace(string(body))}
+	}
+	return body, nil
+}
+
+func pointID(agent, id string) string {
+	return uuid.NewSHA1(qdrantNamespace, []byte(agent+"/"+id)).String()
+}
+
+func agentFilter(agent string) map[string]any {
+	return map[string]any{
+		"must": []map[string]any{
+			{"key": "agent", "match": map[string]any{"value": agent}},
+		},
+	}
+}
@@ -0,0 +1,131 @@
+package memory
+
+import (
+	"context"
+	"database/sql"
+	"encoding/binary"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	_ "modernc.org/sqlite"
+)
+
+// SQLiteVectorStore keeps embeddings in a local SQLite database. Search
+// scans the agent's entries, which is fast for the thousands of memories an
+// agent gathers; use Qdrant for much larger stores.
+type SQLiteVectorStore struct {
+	db *sql.DB
+}
+
+// NewSQLiteVectorStore opens (and if needed creates) the database at path.
+func NewSQLiteVectorStore(path string) (*SQLiteVectorStore, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, fmt.Errorf("failed to create memory dir: %w", err)
+	}
+	db, err := sql.Open("sqlite", path+"?_pragma=journal_mode(WAL)&_pragma=busy_timeout(5000)")
+	if err != nil {
+		return nil, err
+	}
+	if _, err := db.Exec(`CREATE TABLE IF NOT EXISTS memories (
+		agent      TEXT NOT NULL,
+		id         TEXT NOT NULL,
+		kind       TEXT NOT NULL,
+		content    TEXT NOT NULL,
+		vector     BLOB NOT NULL,
+		created_at INTEGER NOT NULL,
+		PRIMARY KEY (agent, id)
+	)`); err != nil {
+		_ = db.Close()
+		return nil, fmt.Errorf("failed to create memories table: %w", err)
+	}
+	return &SQLiteVectorStore{db: db}, nil
+}
+
+func (s *SQLiteVectorStore) Close() error {
+	return s.db.Close()
+}
+
+func (s *SQLiteVectorStore) Upsert(ctx context.Context, entries []VectorEntry) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback() }()
+	for _, e := range entries {
+		if _, err := tx.ExecContext(ctx, `INSERT INTO memories (agent, id, kind, content, vector, created_at)
+			VALUES (?, ?, ?, ?, ?, ?)
+			ON CONFLICT(agent, id) DO UPDATE SET
+				kind = excluded.kind,
+				content = excluded.content,
+				vector = excluded.vector,
+				created_at = excluded.created_at`,
+			e.Agent, e.ID, e.Kind, e.Content, encodeVector(e.Vector), e.CreatedAt.UnixNano()); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+func (s *SQLiteVectorStore) Search(ctx context.Context, agent string, vector []float32, k int) ([]VectorEntry, error) {
+	rows, err := s.db.QueryContext(ctx, `SELECT id, kind, content, vector, created_at FROM memories WHERE agent = ?`, agent)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = rows.Close() }()
+
+	var entries []VectorEntry
+	for rows.Next() {
+		var (
+			e         VectorEntry
+			blob      []byte
+			createdAt int64
+		)
+		if err := rows.Scan(&e.ID, &e.Kind, &e.Content, &blob, &createdAt); err != nil {
+			return nil, err
+		}
+		e.Agent = agent
+		e.Vector = decodeVector(blob)
+		e.CreatedAt = time.Unix(0, createdAt)
+		e.Score = cosine(vector, e.Vector)
+		entries = append(entries, e)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	sort.SliceStable(entries, func(i, j int) bool { return entries[i].Score > entries[j].Score })
+	if len(entries) > k {
+		entries = entries[:k]
+	}
+	return entries, nil
+}
+
+func (s *SQLiteVectorStore) Delete(ctx context.Context, agent, id string) error {
+	_, err := s.db.ExecContext(ctx, `DELETE FROM memories WHERE agent = ? AND id = ?`, agent, id)
+	return err
+}
+
+func (s *SQLiteVectorStore) Prune(ctx context.Context, agent string, before time.Time) error {
+	_, err := s.db.ExecContext(ctx, `DELETE FROM memories WHERE agent = ? AND created_at < ?`, agent, before.UnixNano())
+	return err
+}
+
+func encodeVector(v []float32) []byte {
+	buf := make([]byte, 4*len(v))
+	for i, f := range v {
+		binary.LittleEndian.PutUint32(buf[4*i:], math.Float32bits(f))
+	}
+	return buf
+}
+
+func decodeVector(buf []byte) []float32 {
+	v := make([]float32, len(buf)/4)
+	for i := range v {
+		v[i] = math.Float32frombits(binary.LittleEndian.Uint32(buf[4*i:]))
+	}
+	return v
+}
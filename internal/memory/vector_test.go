@@ -0,0 +1,125 @@
+package memory
+
+import (
+	"context"
+	"hash/fnv"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// wordEmbedder embeds texts as bags of words, so texts sharing words are
+// similar.
+type wordEmbedder struct{}
+
+func (wordEmbedder) Embed(_ context.Context, texts []string) ([][]float32, error) {
+	vectors := make([][]float32, len(texts))
+	for i, text := range texts {
+		v := make([]float32, 64)
+		for _, w := range strings.Fields(strings.ToLower(text)) {
+			h := fnv.New32a()
+			_, _ = h.Write([]byte(strings.Trim(w, ".,?!")))
+			v[h.Sum32()%64]++
+		}
+		vectors[i] = v
+	}
+	return vectors, nil
+}
+
+func TestSemanticMemory_SQLite(t *testing.T) {
+	store, err := NewSQLiteVectorStore(filepath.Join(t.TempDir(), "vectors.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	m := NewSemanticMemory(wordEmbedder{}, store)
+	defer func() { _ = m.Close() }()
+	ctx := context.Background()
+
+	for id, content := range map[string]string{
+		"pricing": "The pricing page lists three plans",
+		"deploy":  "Deploys to production happen on Tuesdays",
+	} {
+		if err := m.Remember(ctx, "support", KindFact, id, content); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := m.Remember(ctx, "other", KindFact, "x", "Deploys happen on Fridays"); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := m.Recall(ctx, "support", "when do deploys to production happen", 5, 0.1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) == 0 || got[0].ID != "deploy" {
+		t.Fatalf("expected the deploy fact first, got %+v", got)
+	}
+	for _, e := range got {
+		if e.Agent != "support" {
+			t.Errorf("recalled a memory of another agent: %+v", e)
+		}
+	}
+
+	// Remembering an ID again replaces the entry
+	if err := m.Remember(ctx, "support", KindFact, "deploy", "Deploys to production happen on Mondays"); err != nil {
+		t.Fatal(err)
+	}
+	got, _ = m.Recall(ctx, "support", "deploys to production", 5, 0.1)
+	if len(got) != 1 || !strings.Contains(got[0].Content, "Mondays") {
+		t.Errorf("expected the replaced fact only, got %+v", got)
+	}
+
+	if err := m.Forget(ctx, "support", "deploy"); err != nil {
+		t.Fatal(err)
+	}
+	got, _ = m.Recall(ctx, "support", "deploys to production", 5, 0.1)
+	if len(got) != 0 {
+		t.Errorf("forgotten memory was recalled: %+v", got)
+	}
+}
+
+func TestFactStore_Index(t *testing.T) {
+	dir := t.TempDir()
+	store, err := NewSQLiteVectorStore(filepath.Join(dir, "vectors.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	index := NewSemanticMemory(wordEmbedder{}, store)
+	defer func() { _ = index.Close() }()
+
+	facts := NewFactStore(dir)
+	facts.SetIndex(index)
+	fact, err := facts.Add("klaw", "John prefers blue", []string{"john"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got, _ := index.Recall(context.Background(), "klaw", "what does john prefer", 5, 0.1)
+	if len(got) != 1 || got[0].ID != fact.ID || got[0].Kind != KindFact {
+		t.Fatalf("expected the fact to be indexed, got %+v", got)
+	}
+
+	if err := facts.Delete("klaw", fact.ID); err != nil {
+		t.Fatal(err)
+	}
+	got, _ = index.Recall(context.Background(), "klaw", "what does john prefer", 5, 0.1)
+	if len(got) != 0 {
+		t.Errorf("deleted fact is still indexed: %+v", got)
+	}
+}
+
+func TestVectorEncoding(t *testing.T) {
+	v := []float32{0, 1.5, -2.25, 3e-7}
+	got := decodeVector(encodeVector(v))
+	for i := range v {
+		if got[i] != v[i] {
+			t.Fatalf("decodeVector(encodeVector(%v)) = %v", v, got)
+		}
+	}
+	if s := cosine(v, v); s < 0.9999 {
+		t.Errorf("cosine of a vector with itself = %f", s)
+	}
+	if s := cosine(v, []float32{1}); s != 0 {
+		t.Errorf("cosine of vectors of different length = %f", s)
+	}
+}
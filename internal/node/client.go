@@ -12,8 +12,15 @@ import (
 	"runtime"
 	"sync"
 	"time"
+
+	"github.com/eachlabs/klaw/internal/controller"
+	"github.com/eachlabs/klaw/internal/priority"
 )
 
+// DefaultMaxTasks is the number of tasks a node runs at once unless
+// configured; queued tasks wait in order of priority.
+const DefaultMaxTasks = 4
+
 // Client connects to the klaw controller
 type Client struct {
 	config     ClientConfig
@@ -25,6 +32,7 @@ type Client struct {
 
 	// Agent execution
 	agentRunner AgentRunner
+	slots       *priority.Limiter
 
 	ctx    context.Context
 	cancel context.CancelFunc
@@ -32,8 +40,9 @@ type Client struct {
 	mu     sync.Mutex
 }
 
-// AgentRunner is called when the controller dispatches a task
-type AgentRunner func(ctx context.Context, agentName, prompt string) (string, error)
+// AgentRunner is called when the controller dispatches a task. It passes
+// the tool calls and results of the task to report as they happen.
+type AgentRunner func(ctx context.Context, agentName, prompt string, report func(controller.TaskEvent)) (string, error)
 
 // NodeClient is the interface for node clients (TCP and gRPC)
 type NodeClient interface {
@@ -52,6 +61,7 @@ type ClientConfig struct {
 	Token          string
 	Labels         map[string]string
 	DataDir        string
+	MaxTasks       int // tasks run at once; 0 means DefaultMaxTasks, less means no limit
 }
 
 // Message mirrors the controller Message type
@@ -75,10 +85,14 @@ type Message struct {
 	Skills      []string `json:"skills,omitempty"`
 
 	// Task
-	TaskID string `json:"task_id,omitempty"`
-	Agent  string `json:"agent,omitempty"`
-	Prompt string `json:"prompt,omitempty"`
-	Result string `json:"result,omitempty"`
+	TaskID   string `json:"task_id,omitempty"`
+	Agent    string `json:"agent,omitempty"`
+	Prompt   string `json:"prompt,omitempty"`
+	Result   string `json:"result,omitempty"`
+	Status   string `json:"status,omitempty"`
+	Priority int    `json:"priority,omitempty"`
+	Tool     string `json:"tool,omitempty"`
+	Input    string `json:"input,omitempty"`
 
 	// Error
 	Error string `json:"error,omitempty"`
@@ -95,6 +109,7 @@ func NewClient(cfg ClientConfig) *Client {
 
 	return &Client{
 		config: cfg,
+		slots:  newTaskSlots(cfg),
 		ctx:    ctx,
 		cancel: cancel,
 	}
@@ -274,7 +289,11 @@ func (c *Client) handleMessage(msg *Message) {
 
 	case "task":
 		// Execute task
-		go c.executeTask(msg)
+		queueTask(c.ctx, c.slots, priority.Level(msg.Priority), func(status string) {
+			c.mu.Lock()
+			_ = c.encoder.Encode(&Message{Type: "task_progress", TaskID: msg.TaskID, Status: status})
+			c.mu.Unlock()
+		}, func() { c.executeTask(msg) })
 
 	case "error":
 		fmt.Printf("❌ Error from controller: %s\n", msg.Error)
@@ -289,7 +308,16 @@ func (c *Client) executeTask(msg *Message) {
 	var taskErr string
 
 	if c.agentRunner != nil {
-		output, err := c.agentRunner(c.ctx, msg.Agent, msg.Prompt)
+		output, err := c.agentRunner(c.ctx, msg.Agent, msg.Prompt, func(ev controller.TaskEvent) {
+			ev = ev.Truncated()
+			event := &Message{Type: ev.Type, TaskID: msg.TaskID, Tool: ev.Tool, Input: ev.Input, Result: ev.Output}
+			if ev.IsError {
+				event.Result, event.Error = "", ev.Output
+			}
+			c.mu.Lock()
+			_ = c.encoder.Encode(event)
+			c.mu.Unlock()
+		})
 		if err != nil {
 			taskErr = err.Error()
 		} else {
@@ -316,6 +344,38 @@ func (c *Client) executeTask(msg *Message) {
 	}
 }
 
+// Task progress statuses reported to the controller
+const (
+	taskQueued  = "queued"  // waiting for a free worker
+	taskRunning = "running" // picked up by a worker
+)
+
+// newTaskSlots creates the limiter of the tasks a node runs at once.
+func newTaskSlots(cfg ClientConfig) *priority.Limiter {
+	limit := cfg.MaxTasks
+	if limit == 0 {
+		limit = DefaultMaxTasks
+	}
+	return priority.NewLimiter(limit, priority.DefaultAging)
+}
+
+// queueTask runs a task once one of the node's workers is free, higher
+// priorities first, reporting when it is queued and when it starts. Tasks
+// still queued when ctx is done are dropped.
+func queueTask(ctx context.Context, slots *priority.Limiter, level priority.Level, report func(status string), run func()) {
+	go func() {
+		if !slots.TryAcquire() {
+			report(taskQueued)
+			if err := slots.Acquire(ctx, level); err != nil {
+				return
+			}
+		}
+		defer slots.Release()
+		report(taskRunning)
+		run()
+	}()
+}
+
 // saveNodeID saves the node ID to disk
 func (c *Client) saveNodeID() {
 	if c.config.DataDir == "" {
@@ -7,7 +7,9 @@ import (
 	"sync"
 	"time"
 
+	"github.com/eachlabs/klaw/internal/controller"
 	"github.com/eachlabs/klaw/internal/controller/pb"
+	"github.com/eachlabs/klaw/internal/priority"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials/insecure"
 )
@@ -20,6 +22,7 @@ type GRPCClient struct {
 	nodeID      string
 	registered  bool
 	agentRunner AgentRunner
+	slots       *priority.Limiter
 
 	taskStream pb.ControllerService_TaskStreamClient
 
@@ -35,6 +38,7 @@ func NewGRPCClient(cfg ClientConfig) *GRPCClient {
 
 	return &GRPCClient{
 		config: cfg,
+		slots:  newTaskSlots(cfg),
 		ctx:    ctx,
 		cancel: cancel,
 	}
@@ -204,12 +208,23 @@ func (c *GRPCClient) taskLoop() {
 			}
 
 			if msg.Type == "task" {
-				go c.executeTask(msg)
+				// The priority travels in the metadata; an invalid one runs as normal
+				level, _ := priority.Parse(msg.Metadata[controller.MetaPriority])
+				queueTask(c.ctx, c.slots, level, func(status string) { c.sendProgress(msg.TaskId, status) }, func() { c.executeTask(msg) })
 			}
 		}
 	}
 }
 
+// sendProgress reports the status of a task to the controller.
+func (c *GRPCClient) sendProgress(taskID, status string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.taskStream != nil {
+		_ = c.taskStream.Send(&pb.TaskMessage{Type: "progress", TaskId: taskID, Status: status})
+	}
+}
+
 func (c *GRPCClient) executeTask(msg *pb.TaskMessage) {
 	fmt.Printf("📥 Task received: %s for agent %s\n", msg.TaskId, msg.AgentName)
 
@@ -217,7 +232,13 @@ func (c *GRPCClient) executeTask(msg *pb.TaskMessage) {
 	var taskErr string
 
 	if c.agentRunner != nil {
-		output, err := c.agentRunner(c.ctx, msg.AgentName, msg.Prompt)
+		output, err := c.agentRunner(c.ctx, msg.AgentName, msg.Prompt, func(ev controller.TaskEvent) {
+			c.mu.Lock()
+			defer c.mu.Unlock()
+			if c.taskStream != nil {
+				_ = c.taskStream.Send(controller.ToolEventMessage(msg.TaskId, ev))
+			}
+		})
 		if err != nil {
 			taskErr = err.Error()
 		} else {
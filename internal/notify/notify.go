@@ -0,0 +1,239 @@
+// Package notify delivers job, task, node and budget events to outbound
+// webhooks: Slack incoming webhooks and generic HTTP endpoints.
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"text/template"
+	"time"
+
+	"github.com/eachlabs/klaw/internal/config"
+)
+
+// Event types.
+const (
+	EventJobSucceeded   = "job_succeeded"
+	EventJobFailed      = "job_failed"
+	EventTaskCompleted  = "task_completed"
+	EventTaskFailed     = "task_failed"
+	EventNodeDown       = "node_down"
+	EventBudgetExceeded = "budget_exceeded"
+)
+
+// Event is something webhooks are notified of. Templates see its fields.
+type Event struct {
+	Type      string    `json:"type"`
+	Time      time.Time `json:"time"`
+	Message   string    `json:"message"` // one-line description
+	Cluster   string    `json:"cluster,omitempty"`
+	Namespace string    `json:"namespace,omitempty"`
+	Agent     string    `json:"agent,omitempty"`
+	Job       string    `json:"job,omitempty"`
+	Task      string    `json:"task,omitempty"` // task ID
+	Node      string    `json:"node,omitempty"`
+	Result    string    `json:"result,omitempty"`
+	Error     string    `json:"error,omitempty"`
+}
+
+// Webhook is an endpoint notified of events.
+type Webhook struct {
+	Name     string
+	URL      string
+	Format   string             // "http" or "slack"
+	Events   map[string]bool    // nil: all events
+	Template *template.Template // nil: the default payload
+	Headers  map[string]string
+}
+
+// Wants reports whether the webhook is notified of events of type t.
+func (w *Webhook) Wants(t string) bool {
+	return w.Events == nil || w.Events[t]
+}
+
+// templateFuncs are the functions of webhook templates.
+var templateFuncs = template.FuncMap{
+	// json quotes a value for JSON bodies, e.g. {"text": {{json .Message}}}
+	"json": func(v any) string {
+		data, _ := marshal(v)
+		return string(data)
+	},
+}
+
+// marshal encodes v as JSON, leaving <, > and & as they are for Slack.
+func marshal(v any) ([]byte, error) {
+	var b bytes.Buffer
+	enc := json.NewEncoder(&b)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(v); err != nil {
+		return nil, err
+	}
+	return bytes.TrimSuffix(b.Bytes(), []byte("\n")), nil
+}
+
+// NewWebhook returns the webhook of a [[webhooks]] entry.
+func NewWebhook(cfg config.WebhookConfig) (*Webhook, error) {
+	name := cfg.Name
+	if name == "" {
+		name = cfg.URL
+	}
+	w := &Webhook{Name: name, URL: cfg.URL, Format: cfg.Format, Headers: cfg.Headers}
+	if w.Format == "" {
+		w.Format = "http"
+	}
+	if w.Format != "http" && w.Format != "slack" {
+		return nil, fmt.Errorf("webhook %s: unknown format %q (use http, slack)", name, cfg.Format)
+	}
+	if cfg.URL == "" {
+		return nil, fmt.Errorf("webhook %s: url is required", name)
+	}
+	if len(cfg.Events) > 0 {
+		w.Events = make(map[string]bool, len(cfg.Events))
+		for _, e := range cfg.Events {
+			w.Events[e] = true
+		}
+	}
+	if cfg.Template != "" {
+		tmpl, err := template.New(name).Funcs(templateFuncs).Parse(cfg.Template)
+		if err != nil {
+			return nil, fmt.Errorf("webhook %s: %w", name, err)
+		}
+		w.Template = tmpl
+	}
+	return w, nil
+}
+
+// Payload returns the request body of ev: for http webhooks the template's
+// output or else the event as JSON, for Slack webhooks a message whose text
+// is the template's output or else the event's message.
+func (w *Webhook) Payload(ev Event) ([]byte, error) {
+	var text string
+	if w.Template != nil {
+		var b bytes.Buffer
+		if err := w.Template.Execute(&b, ev); err != nil {
+			return nil, err
+		}
+		text = b.String()
+	}
+	switch {
+	case w.Format == "slack" && text == "":
+		return marshal(map[string]string{"text": slackText(ev)})
+	case w.Format == "slack":
+		return marshal(map[string]string{"text": text})
+	case w.Template != nil:
+		return []byte(text), nil
+	default:
+		return marshal(ev)
+	}
+}
+
+// slackText is the default Slack message of an event.
+func slackText(ev Event) string {
+	icon := ":white_check_mark:"
+	switch ev.Type {
+	case EventJobFailed, EventTaskFailed, EventNodeDown:
+		icon = ":x:"
+	case EventBudgetExceeded:
+		icon = ":warning:"
+	}
+	text := icon + " " + ev.Message
+	if ev.Error != "" {
+		text += "\n> " + strings.ReplaceAll(ev.Error, "\n", "\n> ")
+	}
+	return text
+}
+
+// Notifier sends events to webhooks in the background. A nil Notifier
+// sends nothing.
+type Notifier struct {
+	hooks  []*Webhook
+	client *http.Client
+	wg     sync.WaitGroup
+}
+
+// New returns a notifier of the webhooks, or nil if there are none.
+func New(hooks []*Webhook) *Notifier {
+	if len(hooks) == 0 {
+		return nil
+	}
+	return &Notifier{hooks: hooks, client: &http.Client{Timeout: 10 * time.Second}}
+}
+
+// FromConfig returns the notifier of the [[webhooks]] of cfg, or nil if
+// there are none.
+func FromConfig(cfg *config.Config) (*Notifier, error) {
+	hooks := make([]*Webhook, 0, len(cfg.Webhooks))
+	for _, wc := range cfg.Webhooks {
+		w, err := NewWebhook(wc)
+		if err != nil {
+			return nil, err
+		}
+		hooks = append(hooks, w)
+	}
+	return New(hooks), nil
+}
+
+// Notify sends ev to the webhooks that want it, without waiting for them.
+// Failed deliveries are logged.
+func (n *Notifier) Notify(ev Event) {
+	if n == nil {
+		return
+	}
+	if ev.Time.IsZero() {
+		ev.Time = time.Now()
+	}
+	for _, w := range n.hooks {
+		if !w.Wants(ev.Type) {
+			continue
+		}
+		n.wg.Add(1)
+		go func(w *Webhook) {
+			defer n.wg.Done()
+			if err := n.deliver(w, ev); err != nil {
+				fmt.Printf("Warning: webhook %s: %s: %v\n", w.Name, ev.Type, err)
+			}
+		}(w)
+	}
+}
+
+// Wait waits until the deliveries in flight are done.
+func (n *Notifier) Wait() {
+	if n == nil {
+		return
+	}
+	n.wg.Wait()
+}
+
+func (n *Notifier) deliver(w *Webhook, ev Event) error {
+	body, err := w.Payload(ev)
+	if err != nil {
+		return err
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, w.URL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Klaw-Event", ev.Type)
+	for k, v := range w.Headers {
+		req.Header.Set(k, v)
+	}
+	resp, err := n.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		data, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("%s: %s", resp.Status, strings.TrimSpace(string(data)))
+	}
+	return nil
+}
@@ -0,0 +1,115 @@
+package notify
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/eachlabs/klaw/internal/config"
+)
+
+func TestPayload(t *testing.T) {
+	ev := Event{Type: EventJobFailed, Message: "Job nightly of agent ops failed", Job: "nightly", Error: "exit 1"}
+
+	tests := []struct {
+		name string
+		cfg  config.WebhookConfig
+		want string
+	}{
+		{
+			"http default",
+			config.WebhookConfig{URL: "http://x"},
+			`{"type":"job_failed","time":"0001-01-01T00:00:00Z","message":"Job nightly of agent ops failed","job":"nightly","error":"exit 1"}`,
+		},
+		{
+			"http template",
+			config.WebhookConfig{URL: "http://x", Template: `{"job": {{json .Job}}, "ok": false}`},
+			`{"job": "nightly", "ok": false}`,
+		},
+		{
+			"slack default",
+			config.WebhookConfig{URL: "http://x", Format: "slack"},
+			`{"text":":x: Job nightly of agent ops failed\n> exit 1"}`,
+		},
+		{
+			"slack template",
+			config.WebhookConfig{URL: "http://x", Format: "slack", Template: `{{.Job}} broke: {{.Error}}`},
+			`{"text":"nightly broke: exit 1"}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w, err := NewWebhook(tt.cfg)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got, err := w.Payload(ev)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(got) != tt.want {
+				t.Errorf("got  %s\nwant %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewWebhook_Errors(t *testing.T) {
+	for _, cfg := range []config.WebhookConfig{
+		{},
+		{URL: "http://x", Format: "teams"},
+		{URL: "http://x", Template: "{{.Job"},
+	} {
+		if _, err := NewWebhook(cfg); err == nil {
+			t.Errorf("expected an error for %+v", cfg)
+		}
+	}
+}
+
+func TestNotify(t *testing.T) {
+	var mu sync.Mutex
+	got := map[string][]string{}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		var ev Event
+		_ = json.Unmarshal(body, &ev)
+		mu.Lock()
+		got[r.URL.Path] = append(got[r.URL.Path], r.Header.Get("X-Klaw-Event")+":"+ev.Node+":"+r.Header.Get("Authorization"))
+		mu.Unlock()
+	}))
+	defer srv.Close()
+
+	n, err := FromConfig(&config.Config{Webhooks: []config.WebhookConfig{
+		{Name: "all", URL: srv.URL + "/all", Headers: map[string]string{"Authorization": "Bearer t"}},
+		{Name: "nodes", URL: srv.URL + "/nodes", Events: []string{EventNodeDown}},
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	n.Notify(Event{Type: EventNodeDown, Node: "n1"})
+	n.Notify(Event{Type: EventTaskCompleted})
+	n.Wait()
+
+	if len(got["/all"]) != 2 {
+		t.Errorf("expected both events at /all, got %v", got["/all"])
+	}
+	if len(got["/nodes"]) != 1 || got["/nodes"][0] != "node_down:n1:" {
+		t.Errorf("expected only the node event at /nodes, got %v", got["/nodes"])
+	}
+	for _, h := range got["/all"] {
+		if h[len(h)-8:] != "Bearer t" {
+			t.Errorf("expected the configured header, got %q", h)
+		}
+	}
+
+	// Without webhooks there is nothing to notify
+	none, err := FromConfig(&config.Config{})
+	if err != nil || none != nil {
+		t.Fatalf("expected a nil notifier, got %v, %v", none, err)
+	}
+	none.Notify(Event{Type: EventNodeDown})
+	none.Wait()
+}
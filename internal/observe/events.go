@@ -0,0 +1,242 @@
+package observe
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// Kinds of agent activity events
+const (
+	EventMessage    = "message"     // user message in
+	EventResponse   = "response"    // final reply out
+	EventToolCall   = "tool_call"   // tool called, with its input
+	EventToolResult = "tool_result" // tool returned
+	EventError      = "error"       // the turn failed
+)
+
+// Event is one step of an agent's activity.
+type Event struct {
+	Time         time.Time `json:"time"`
+	Agent        string    `json:"agent,omitempty"`
+	Conversation string    `json:"conversation,omitempty"`
+	Kind         string    `json:"kind"`
+	Content      string    `json:"content,omitempty"`
+	Tool         string    `json:"tool,omitempty"`
+	IsError      bool      `json:"is_error,omitempty"`
+}
+
+// EventFilter selects the events a client of an event socket receives.
+type EventFilter struct {
+	Agent  string `json:"agent,omitempty"` // empty = all agents
+	Tail   int    `json:"tail"`            // recent events to send first
+	Follow bool   `json:"follow"`          // keep streaming new events
+}
+
+func (f EventFilter) matches(ev Event) bool {
+	return f.Agent == "" || f.Agent == ev.Agent
+}
+
+// eventBuffer is the number of recent events an EventHub keeps.
+const eventBuffer = 500
+
+// EventHub fans agent events out to subscribers and keeps the most recent
+// ones for clients that connect later.
+type EventHub struct {
+	mu     sync.Mutex
+	recent []Event
+	subs   map[chan Event]struct{}
+}
+
+// NewEventHub creates an empty event hub.
+func NewEventHub() *EventHub {
+	return &EventHub{subs: make(map[chan Event]struct{})}
+}
+
+// Publish records an event and sends it to the subscribers. Subscribers
+// that fall behind miss events rather than block the agent.
+func (h *EventHub) Publish(ev Event) {
+	if ev.Time.IsZero() {
+		ev.Time = time.Now()
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.recent = append(h.recent, ev)
+	if len(h.recent) > eventBuffer {
+		h.recent = h.recent[len(h.recent)-eventBuffer:]
+	}
+	for ch := range h.subs {
+		select {
+		case ch <- ev:
+		default:
+		}
+	}
+}
+
+// Recent returns up to n of the latest events matching the filter, oldest
+// first.
+func (h *EventHub) Recent(f EventFilter, n int) []Event {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return h.recentLocked(f, n)
+}
+
+func (h *EventHub) recentLocked(f EventFilter, n int) []Event {
+	var events []Event
+	for i := len(h.recent) - 1; i >= 0 && len(events) < n; i-- {
+		if f.matches(h.recent[i]) {
+			events = append(events, h.recent[i])
+		}
+	}
+	for i, j := 0, len(events)-1; i < j; i, j = i+1, j-1 {
+		events[i], events[j] = events[j], events[i]
+	}
+	return events
+}
+
+// Subscribe returns the recent events matching the filter and a channel of
+// the new ones. cancel ends the subscription.
+func (h *EventHub) Subscribe(f EventFilter) (recent []Event, events <-chan Event, cancel func()) {
+	ch := make(chan Event, 64)
+	h.mu.Lock()
+	recent = h.recentLocked(f, f.Tail)
+	h.subs[ch] = struct{}{}
+	h.mu.Unlock()
+
+	var once sync.Once
+	return recent, ch, func() {
+		once.Do(func() {
+			h.mu.Lock()
+			delete(h.subs, ch)
+			h.mu.Unlock()
+		})
+	}
+}
+
+// Serve streams events on a unix socket at path until ctx is done. A
+// client writes an EventFilter as one JSON line and reads events as JSON
+// lines.
+func (h *EventHub) Serve(ctx context.Context, path string) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return err
+	}
+	// A socket left behind by a process that did not shut down cleanly
+	_ = os.Remove(path)
+	ln, err := net.Listen("unix", path)
+	if err != nil {
+		return err
+	}
+	go func() {
+		<-ctx.Done()
+		_ = ln.Close()
+		_ = os.Remove(path)
+	}()
+
+	for {
+		conn, err := ln.Accept()
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			return err
+		}
+		go h.serveConn(ctx, conn)
+	}
+}
+
+func (h *EventHub) serveConn(ctx context.Context, conn net.Conn) {
+	defer conn.Close()
+
+	line, err := bufio.NewReader(conn).ReadBytes('\n')
+	if err != nil {
+		return
+	}
+	var f EventFilter
+	if err := json.Unmarshal(line, &f); err != nil {
+		return
+	}
+
+	recent, events, cancel := h.Subscribe(f)
+	defer cancel()
+
+	enc := json.NewEncoder(conn)
+	for _, ev := range recent {
+		if err := enc.Encode(ev); err != nil {
+			return
+		}
+	}
+	if !f.Follow {
+		return
+	}
+
+	// Notice when the client goes away
+	closed := make(chan struct{})
+	go func() {
+		_, _ = conn.Read(make([]byte, 1))
+		close(closed)
+	}()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-closed:
+			return
+		case ev := <-events:
+			if !f.matches(ev) {
+				continue
+			}
+			if err := enc.Encode(ev); err != nil {
+				return
+			}
+		}
+	}
+}
+
+// StreamEvents connects to the event socket at path and calls fn for each
+// event the filter selects. It returns when the server is done sending, or
+// when ctx is done.
+func StreamEvents(ctx context.Context, path string, f EventFilter, fn func(Event)) error {
+	var d net.Dialer
+	conn, err := d.DialContext(ctx, "unix", path)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		select {
+		case <-ctx.Done():
+			_ = conn.Close()
+		case <-done:
+		}
+	}()
+
+	data, err := json.Marshal(f)
+	if err != nil {
+		return err
+	}
+	if _, err := conn.Write(append(data, '\n')); err != nil {
+		return err
+	}
+
+	scanner := bufio.NewScanner(conn)
+	scanner.Buffer(make([]byte, 64*1024), 4*1024*1024)
+	for scanner.Scan() {
+		var ev Event
+		if err := json.Unmarshal(scanner.Bytes(), &ev); err != nil {
+			continue
+		}
+		fn(ev)
+	}
+	if ctx.Err() != nil {
+		return nil
+	}
+	return scanner.Err()
+}
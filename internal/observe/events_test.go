@@ -0,0 +1,86 @@
+package observe
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestEventHub_Recent(t *testing.T) {
+	h := NewEventHub()
+	for i := 0; i < eventBuffer+10; i++ {
+		agent := "coder"
+		if i%2 == 1 {
+			agent = "writer"
+		}
+		h.Publish(Event{Agent: agent, Kind: EventMessage, Content: fmt.Sprint(i)})
+	}
+
+	all := h.Recent(EventFilter{}, 3)
+	if len(all) != 3 || all[0].Content != fmt.Sprint(eventBuffer+7) || all[2].Content != fmt.Sprint(eventBuffer+9) {
+		t.Errorf("unexpected recent events: %+v", all)
+	}
+	coder := h.Recent(EventFilter{Agent: "coder"}, 2)
+	if len(coder) != 2 || coder[1].Content != fmt.Sprint(eventBuffer+8) {
+		t.Errorf("unexpected recent coder events: %+v", coder)
+	}
+	if got := len(h.Recent(EventFilter{}, 2*eventBuffer)); got != eventBuffer {
+		t.Errorf("hub kept %d events, want %d", got, eventBuffer)
+	}
+}
+
+func TestEventHub_ServeFollow(t *testing.T) {
+	h := NewEventHub()
+	h.Publish(Event{Agent: "coder", Kind: EventMessage, Content: "earlier"})
+	h.Publish(Event{Agent: "writer", Kind: EventMessage, Content: "other agent"})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	path := filepath.Join(t.TempDir(), "events.sock")
+	go func() { _ = h.Serve(ctx, path) }()
+
+	received := make(chan Event, 10)
+	streamCtx, stop := context.WithCancel(ctx)
+	defer stop()
+	go func() {
+		for {
+			err := StreamEvents(streamCtx, path, EventFilter{Agent: "coder", Tail: 10, Follow: true}, func(ev Event) { received <- ev })
+			if err == nil || streamCtx.Err() != nil {
+				return
+			}
+			time.Sleep(10 * time.Millisecond) // socket not listening yet
+		}
+	}()
+
+	expect := func(content string) {
+		t.Helper()
+		select {
+		case ev := <-received:
+			if ev.Content != content {
+				t.Fatalf("got event %q, want %q", ev.Content, content)
+			}
+		case <-time.After(2 * time.Second):
+			t.Fatalf("timed out waiting for %q", content)
+		}
+	}
+	expect("earlier")
+
+	// Publish until the follower is subscribed and sees the new event
+	deadline := time.After(2 * time.Second)
+	for {
+		h.Publish(Event{Agent: "writer", Kind: EventMessage, Content: "skipped"})
+		h.Publish(Event{Agent: "coder", Kind: EventResponse, Content: "live"})
+		select {
+		case ev := <-received:
+			if ev.Content != "live" || ev.Kind != EventResponse {
+				t.Fatalf("unexpected event %+v", ev)
+			}
+			return
+		case <-time.After(20 * time.Millisecond):
+		case <-deadline:
+			t.Fatal("timed out waiting for live event")
+		}
+	}
+}
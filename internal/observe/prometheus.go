@@ -0,0 +1,116 @@
+package observe
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// Prometheus collects counters and latencies of agent activity for a
+// /metrics endpoint. A nil *Prometheus records nothing.
+type Prometheus struct {
+	registry       *prometheus.Registry
+	messages       *prometheus.CounterVec
+	toolCalls      *prometheus.CounterVec
+	providerErrors *prometheus.CounterVec
+	jobRuns        *prometheus.CounterVec
+	turnLatency    *prometheus.HistogramVec
+	toolDuration   *prometheus.HistogramVec
+}
+
+// NewPrometheus creates the metrics, along with the Go runtime and process
+// metrics, in a registry of their own.
+func NewPrometheus() *Prometheus {
+	p := &Prometheus{
+		registry: prometheus.NewRegistry(),
+		messages: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "klaw_messages_total",
+			Help: "Messages handled, by agent and outcome (ok, error, stopped).",
+		}, []string{"agent", "status"}),
+		toolCalls: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "klaw_tool_calls_total",
+			Help: "Tool calls, by tool and outcome (ok, error).",
+		}, []string{"tool", "status"}),
+		providerErrors: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "klaw_provider_errors_total",
+			Help: "Failed provider requests, by provider.",
+		}, []string{"provider"}),
+		jobRuns: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "klaw_job_runs_total",
+			Help: "Scheduled job runs, by job and outcome (ok, error).",
+		}, []string{"job", "status"}),
+		turnLatency: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "klaw_turn_duration_seconds",
+			Help:    "Time from a message to the end of its reply, by agent.",
+			Buckets: []float64{0.5, 1, 2.5, 5, 10, 20, 30, 60, 120, 300, 600},
+		}, []string{"agent"}),
+		toolDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "klaw_tool_duration_seconds",
+			Help:    "Duration of tool calls, by tool.",
+			Buckets: []float64{0.01, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30, 60, 120},
+		}, []string{"tool"}),
+	}
+	p.registry.MustRegister(
+		p.messages, p.toolCalls, p.providerErrors, p.jobRuns, p.turnLatency, p.toolDuration,
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+	)
+	return p
+}
+
+// Handler serves the metrics in the Prometheus text format.
+func (p *Prometheus) Handler() http.Handler {
+	return promhttp.HandlerFor(p.registry, promhttp.HandlerOpts{})
+}
+
+// Serve serves the metrics at /metrics on addr until the server fails.
+func (p *Prometheus) Serve(addr string) error {
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", p.Handler())
+	srv := &http.Server{Addr: addr, Handler: mux, ReadHeaderTimeout: 10 * time.Second}
+	return srv.ListenAndServe()
+}
+
+// RecordTurn records a handled message and how long its turn took.
+func (p *Prometheus) RecordTurn(agent, status string, d time.Duration) {
+	if p == nil {
+		return
+	}
+	p.messages.WithLabelValues(agent, status).Inc()
+	p.turnLatency.WithLabelValues(agent).Observe(d.Seconds())
+}
+
+// RecordTool records a tool call and its duration.
+func (p *Prometheus) RecordTool(tool string, isError bool, d time.Duration) {
+	if p == nil {
+		return
+	}
+	p.toolCalls.WithLabelValues(tool, outcome(isError)).Inc()
+	p.toolDuration.WithLabelValues(tool).Observe(d.Seconds())
+}
+
+// RecordProviderError records a failed provider request.
+func (p *Prometheus) RecordProviderError(provider string) {
+	if p == nil {
+		return
+	}
+	p.providerErrors.WithLabelValues(provider).Inc()
+}
+
+// RecordJobRun records a run of a scheduled job.
+func (p *Prometheus) RecordJobRun(job string, failed bool) {
+	if p == nil {
+		return
+	}
+	p.jobRuns.WithLabelValues(job, outcome(failed)).Inc()
+}
+
+func outcome(failed bool) string {
+	if failed {
+		return "error"
+	}
+	return "ok"
+}
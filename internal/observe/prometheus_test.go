@@ -0,0 +1,51 @@
+package observe
+
+import (
+	"io"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestPrometheus_Handler(t *testing.T) {
+	p := NewPrometheus()
+	p.RecordTurn("coder", "ok", 2*time.Second)
+	p.RecordTurn("coder", "error", time.Second)
+	p.RecordTool("bash", false, 100*time.Millisecond)
+	p.RecordTool("bash", true, 100*time.Millisecond)
+	p.RecordProviderError("anthropic")
+	p.RecordJobRun("digest", false)
+
+	srv := httptest.NewServer(p.Handler())
+	defer srv.Close()
+	resp, err := srv.Client().Get(srv.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(resp.Body)
+
+	for _, want := range []string{
+		`klaw_messages_total{agent="coder",status="ok"} 1`,
+		`klaw_messages_total{agent="coder",status="error"} 1`,
+		`klaw_tool_calls_total{status="error",tool="bash"} 1`,
+		`klaw_provider_errors_total{provider="anthropic"} 1`,
+		`klaw_job_runs_total{job="digest",status="ok"} 1`,
+		`klaw_turn_duration_seconds_count{agent="coder"} 2`,
+		`klaw_tool_duration_seconds_sum{tool="bash"} 0.2`,
+		`go_goroutines`,
+	} {
+		if !strings.Contains(string(body), want) {
+			t.Errorf("metrics do not contain %s", want)
+		}
+	}
+}
+
+func TestPrometheus_Nil(t *testing.T) {
+	var p *Prometheus
+	p.RecordTurn("coder", "ok", time.Second)
+	p.RecordTool("bash", false, time.Second)
+	p.RecordProviderError("anthropic")
+	p.RecordJobRun("digest", true)
+}
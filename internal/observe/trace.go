@@ -0,0 +1,100 @@
+package observe
+
+import (
+	"context"
+	"strings"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// tracerName is the instrumentation scope of klaw's spans.
+const tracerName = "github.com/eachlabs/klaw"
+
+// Span attributes of agent turns
+const (
+	AttrAgent        = attribute.Key("klaw.agent")
+	AttrConversation = attribute.Key("klaw.conversation.id")
+	AttrChannel      = attribute.Key("klaw.channel")    // channel kind, e.g. slack
+	AttrChannelID    = attribute.Key("klaw.channel.id") // e.g. a Slack channel
+	AttrThread       = attribute.Key("klaw.thread")
+	AttrProvider     = attribute.Key("klaw.provider")
+	AttrModel        = attribute.Key("klaw.model")
+	AttrInputTokens  = attribute.Key("klaw.usage.input_tokens")
+	AttrOutputTokens = attribute.Key("klaw.usage.output_tokens")
+	AttrTool         = attribute.Key("klaw.tool")
+	AttrToolCalls    = attribute.Key("klaw.tool_calls")
+	AttrIsError      = attribute.Key("klaw.is_error")
+)
+
+// Tracer returns the tracer of klaw's spans. Spans are dropped until
+// SetupTracing installs an exporter.
+func Tracer() trace.Tracer {
+	return otel.Tracer(tracerName)
+}
+
+// EndSpan records err, if any, as the span's status and ends it.
+func EndSpan(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+// TracingOptions selects where spans are exported to.
+type TracingOptions struct {
+	Endpoint    string            // OTLP/HTTP collector, host:port or URL; default: OTEL_EXPORTER_OTLP_* or localhost:4318
+	Insecure    bool              // plain HTTP for a host:port endpoint
+	Headers     map[string]string // sent with each export, e.g. an API key
+	ServiceName string            // default: klaw
+	SampleRatio float64           // fraction of traces kept; 0 or 1 = all
+}
+
+// SetupTracing exports spans to an OTLP collector until shutdown is called,
+// which flushes the spans not yet sent.
+func SetupTracing(ctx context.Context, opts TracingOptions) (shutdown func(context.Context) error, err error) {
+	var exporterOpts []otlptracehttp.Option
+	switch {
+	case strings.Contains(opts.Endpoint, "://"):
+		exporterOpts = append(exporterOpts, otlptracehttp.WithEndpointURL(opts.Endpoint))
+	case opts.Endpoint != "":
+		exporterOpts = append(exporterOpts, otlptracehttp.WithEndpoint(opts.Endpoint))
+		if opts.Insecure {
+			exporterOpts = append(exporterOpts, otlptracehttp.WithInsecure())
+		}
+	}
+	if len(opts.Headers) > 0 {
+		exporterOpts = append(exporterOpts, otlptracehttp.WithHeaders(opts.Headers))
+	}
+	exporter, err := otlptracehttp.New(ctx, exporterOpts...)
+	if err != nil {
+		return nil, err
+	}
+
+	serviceName := opts.ServiceName
+	if serviceName == "" {
+		serviceName = "klaw"
+	}
+	res, err := resource.Merge(resource.Default(), resource.NewSchemaless(attribute.String("service.name", serviceName)))
+	if err != nil {
+		return nil, err
+	}
+
+	sampler := sdktrace.AlwaysSample()
+	if opts.SampleRatio > 0 && opts.SampleRatio < 1 {
+		sampler = sdktrace.TraceIDRatioBased(opts.SampleRatio)
+	}
+	tp := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(res),
+		sdktrace.WithSampler(sdktrace.ParentBased(sampler)),
+	)
+	otel.SetTracerProvider(tp)
+	return tp.Shutdown, nil
+}
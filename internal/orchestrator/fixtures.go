@@ -0,0 +1,128 @@
+package orchestrator
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// MainAgent is the name fixtures use for the main agent, which handles
+// the messages no agent binding is picked for.
+const MainAgent = "klaw"
+
+// Fixture is an incoming message and the agent it must be routed to.
+type Fixture struct {
+	Message string `yaml:"message" json:"message"`
+	Agent   string `yaml:"agent" json:"agent"` // MainAgent for the main agent
+}
+
+// LoadFixtures reads routing fixtures from a YAML file: a list of
+// message/agent pairs.
+func LoadFixtures(path string) ([]Fixture, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	fixtures, err := ParseFixtures(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return fixtures, nil
+}
+
+// ParseFixtures parses and validates routing fixtures.
+func ParseFixtures(data []byte) ([]Fixture, error) {
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	dec.KnownFields(true)
+	var fixtures []Fixture
+	if err := dec.Decode(&fixtures); err != nil {
+		return nil, err
+	}
+	if len(fixtures) == 0 {
+		return nil, fmt.Errorf("no fixtures")
+	}
+	for i, f := range fixtures {
+		if strings.TrimSpace(f.Message) == "" || f.Agent == "" {
+			return nil, fmt.Errorf("fixture %d: message and agent are required", i+1)
+		}
+	}
+	return fixtures, nil
+}
+
+// FixtureResult is the routing of a fixture.
+type FixtureResult struct {
+	Fixture
+	Got    string `json:"got"` // agent the message was routed to
+	Via    string `json:"via"`
+	Reason string `json:"reason"`
+	Passed bool   `json:"passed"`
+}
+
+// AgentAccuracy is how well the messages of one agent were routed.
+type AgentAccuracy struct {
+	Agent     string `json:"agent"`
+	Expected  int    `json:"expected"`  // fixtures expecting the agent
+	Correct   int    `json:"correct"`   // of those, routed to it
+	Misrouted int    `json:"misrouted"` // fixtures expecting another agent routed to it
+}
+
+// FixtureReport is the outcome of routing a set of fixtures.
+type FixtureReport struct {
+	Total    int             `json:"total"`
+	Passed   int             `json:"passed"`
+	Accuracy float64         `json:"accuracy"` // share of fixtures routed as expected, 0 to 1
+	Agents   []AgentAccuracy `json:"agents"`   // by agent name
+	Results  []FixtureResult `json:"results"`
+}
+
+// RouteFunc routes a message, returning the agent picked (MainAgent for
+// the main agent) and how.
+type RouteFunc func(ctx context.Context, message string) (agent string, d *Decision, err error)
+
+// EvaluateFixtures routes each fixture and compares the agent with the
+// expected one. A routing error fails the fixture.
+func EvaluateFixtures(ctx context.Context, fixtures []Fixture, route RouteFunc) *FixtureReport {
+	report := &FixtureReport{Total: len(fixtures)}
+	byAgent := make(map[string]*AgentAccuracy)
+	stats := func(name string) *AgentAccuracy {
+		if byAgent[name] == nil {
+			byAgent[name] = &AgentAccuracy{Agent: name}
+		}
+		return byAgent[name]
+	}
+
+	for _, f := range fixtures {
+		res := FixtureResult{Fixture: f}
+		got, d, err := route(ctx, f.Message)
+		if err != nil {
+			res.Reason = err.Error()
+		} else {
+			res.Got, res.Passed = got, got == f.Agent
+			if d != nil {
+				res.Via, res.Reason = d.Via, d.Reason
+			}
+		}
+		stats(f.Agent).Expected++
+		if res.Passed {
+			report.Passed++
+			stats(f.Agent).Correct++
+		} else if res.Got != "" {
+			stats(res.Got).Misrouted++
+		}
+		report.Results = append(report.Results, res)
+	}
+
+	for _, a := range byAgent {
+		report.Agents = append(report.Agents, *a)
+	}
+	sort.Slice(report.Agents, func(i, j int) bool { return report.Agents[i].Agent < report.Agents[j].Agent })
+	if report.Total > 0 {
+		report.Accuracy = float64(report.Passed) / float64(report.Total)
+	}
+	return report
+}
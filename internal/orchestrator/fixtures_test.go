@@ -0,0 +1,83 @@
+package orchestrator
+
+import (
+	"context"
+	"testing"
+)
+
+func TestEvaluateFixtures(t *testing.T) {
+	fixtures, err := ParseFixtures([]byte(`
+- message: please fix the login bug
+  agent: coder
+- message: draft the release notes
+  agent: writer
+- message: write a fix for the crash
+  agent: coder
+- message: what's for lunch?
+  agent: klaw
+- message: "@nobody hello"
+  agent: writer
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	o := New(Config{
+		Mode:        "rules",
+		AllowManual: true,
+		Rules: []RoutingRule{
+			{Match: "write|draft", Agent: "writer"},
+			{Match: "fix|bug", Agent: "coder"},
+		},
+	})
+	o.RegisterAgent(&AgentConfig{Name: "coder"})
+	o.RegisterAgent(&AgentConfig{Name: "writer"})
+
+	report := EvaluateFixtures(context.Background(), fixtures, func(ctx context.Context, message string) (string, *Decision, error) {
+		d, err := o.Explain(ctx, o.ParseMessage(message))
+		if err != nil {
+			if o.ParseMessage(message).TargetAgent != "" {
+				return "", nil, err
+			}
+			return MainAgent, &Decision{Via: ViaDefault, Reason: err.Error()}, nil
+		}
+		return d.Agents[0], d, nil
+	})
+
+	if report.Total != 5 || report.Passed != 3 || report.Accuracy != 0.6 {
+		t.Fatalf("expected 3 of 5 routed right, got %d of %d (%v)", report.Passed, report.Total, report.Accuracy)
+	}
+	if r := report.Results[2]; r.Passed || r.Got != "writer" || r.Via != ViaKeyword {
+		t.Errorf("expected the fix to be misrouted to writer by a rule, got %+v", r)
+	}
+	if r := report.Results[4]; r.Passed || r.Got != "" || r.Reason == "" {
+		t.Errorf("expected the unknown @agent to fail with its error, got %+v", r)
+	}
+
+	want := []AgentAccuracy{
+		{Agent: "coder", Expected: 2, Correct: 1},
+		{Agent: "klaw", Expected: 1, Correct: 1},
+		{Agent: "writer", Expected: 2, Correct: 1, Misrouted: 1},
+	}
+	if len(report.Agents) != len(want) {
+		t.Fatalf("expected %v, got %v", want, report.Agents)
+	}
+	for i := range want {
+		if report.Agents[i] != want[i] {
+			t.Errorf("agent %d: expected %+v, got %+v", i, want[i], report.Agents[i])
+		}
+	}
+}
+
+func TestParseFixtures_Errors(t *testing.T) {
+	for _, src := range []string{
+		`[]`,
+		`[{message: hi}]`,
+		`[{agent: coder}]`,
+		`[{message: hi, agent: coder, expected: writer}]`,
+	} {
+		if _, err := ParseFixtures([]byte(src)); err == nil {
+			t.Errorf("expected an error for %s", src)
+		}
+	}
+}
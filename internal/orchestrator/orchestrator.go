@@ -3,8 +3,10 @@ package orchestrator
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"regexp"
+	"sort"
 	"strings"
 	"sync"
 
@@ -87,6 +89,13 @@ func (o *Orchestrator) AddRule(rule RoutingRule) {
 	o.config.Rules = append(o.config.Rules, rule)
 }
 
+// AllowsManual reports whether messages may address an agent with @agent.
+func (o *Orchestrator) AllowsManual() bool {
+	o.mu.RLock()
+	defer o.mu.RUnlock()
+	return o.config.AllowManual
+}
+
 // SetChannel sets the communication channel.
 func (o *Orchestrator) SetChannel(ch channel.Channel) {
 	o.channel = ch
@@ -108,7 +117,7 @@ func (o *Orchestrator) ParseMessage(msg string) *ParsedMessage {
 	}
 
 	// Check for @agent or @all syntax
-	re := regexp.MustCompile(`^@(\w+)\s+(.*)$`)
+	re := regexp.MustCompile(`(?s)^@([\w-]+)[:,]?\s+(.*)$`)
 	matches := re.FindStringSubmatch(strings.TrimSpace(msg))
 
 	if len(matches) == 3 {
@@ -125,64 +134,161 @@ func (o *Orchestrator) ParseMessage(msg string) *ParsedMessage {
 	return parsed
 }
 
+// ErrAgentNotFound is returned when a message addresses an unknown agent.
+var ErrAgentNotFound = errors.New("agent not found")
+
+// Routing methods recorded in Decision.Via.
+const (
+	ViaManual  = "manual"  // @agent syntax
+	ViaAll     = "all"     // @all syntax
+	ViaKeyword = "keyword" // routing rule or agent trigger
+	ViaAI      = "ai"      // LLM classifier
+	ViaDefault = "default" // fallback agent
+)
+
+// Decision is the outcome of routing a message.
+type Decision struct {
+	Agents []string
+	Via    string
+	Reason string // human-readable explanation
+}
+
 // Route determines which agent(s) should handle the message.
 func (o *Orchestrator) Route(ctx context.Context, parsed *ParsedMessage) ([]string, error) {
+	d, err := o.Explain(ctx, parsed)
+	if err != nil {
+		return nil, err
+	}
+	return d.Agents, nil
+}
+
+// Explain routes the message and reports how the agent was chosen.
+func (o *Orchestrator) Explain(ctx context.Context, parsed *ParsedMessage) (*Decision, error) {
 	o.mu.RLock()
 	defer o.mu.RUnlock()
 
 	// Manual routing with @agent
 	if o.config.AllowManual && parsed.TargetAgent != "" {
 		if _, exists := o.agents[parsed.TargetAgent]; exists {
-			return []string{parsed.TargetAgent}, nil
+			return &Decision{
+				Agents: []string{parsed.TargetAgent},
+				Via:    ViaManual,
+				Reason: "addressed as @" + parsed.TargetAgent,
+			}, nil
 		}
-		return nil, fmt.Errorf("agent not found: %s", parsed.TargetAgent)
+		return nil, fmt.Errorf("%w: %s (available: %s)", ErrAgentNotFound, parsed.TargetAgent, strings.Join(o.agentNames(), ", "))
 	}
 
 	// @all - return all agents
 	if parsed.TargetAll {
-		agents := make([]string, 0, len(o.agents))
-		for name := range o.agents {
-			agents = append(agents, name)
-		}
-		return agents, nil
+		return &Decision{Agents: o.agentNames(), Via: ViaAll, Reason: "addressed as @all"}, nil
 	}
 
 	// Disabled mode - use default
 	if o.config.Mode == "disabled" {
 		if o.config.DefaultAgent != "" {
-			return []string{o.config.DefaultAgent}, nil
+			return o.fallback("routing is disabled"), nil
 		}
 		// Return first available agent
-		for name := range o.agents {
-			return []string{name}, nil
+		if names := o.agentNames(); len(names) > 0 {
+			return &Decision{Agents: names[:1], Via: ViaDefault, Reason: "routing is disabled and no default agent is set"}, nil
 		}
 		return nil, fmt.Errorf("no agents available")
 	}
 
-	// Rules-based routing
+	// Rules-based routing: routing rules first, then agent triggers
 	if o.config.Mode == "rules" || o.config.Mode == "hybrid" {
-		for _, rule := range o.config.Rules {
-			matched, _ := regexp.MatchString("(?i)"+rule.Match, parsed.Content)
-			if matched {
-				return []string{rule.Agent}, nil
+		for i, rule := range o.config.Rules {
+			re, err := regexp.Compile("(?i)" + rule.Match)
+			if err != nil {
+				continue
+			}
+			if m := re.FindString(parsed.Content); m != "" {
+				return &Decision{
+					Agents: []string{rule.Agent},
+					Via:    ViaKeyword,
+					Reason: fmt.Sprintf("rule #%d /%s/ matched %q", i+1, rule.Match, m),
+				}, nil
 			}
 		}
+		if name, trigger := o.matchTrigger(parsed.Content); name != "" {
+			return &Decision{
+				Agents: []string{name},
+				Via:    ViaKeyword,
+				Reason: fmt.Sprintf("trigger %q of agent %s", trigger, name),
+			}, nil
+		}
 	}
 
 	// AI-based routing
 	if o.config.Mode == "ai" || o.config.Mode == "hybrid" {
 		agent, err := o.routeWithAI(ctx, parsed.Content)
 		if err == nil && agent != "" {
-			return []string{agent}, nil
+			return &Decision{Agents: []string{agent}, Via: ViaAI, Reason: "chosen by the AI classifier"}, nil
+		}
+		if o.config.DefaultAgent != "" {
+			return o.fallback(fmt.Sprintf("AI classifier gave no answer (%v)", err)), nil
 		}
 	}
 
 	// Fallback to default
 	if o.config.DefaultAgent != "" {
-		return []string{o.config.DefaultAgent}, nil
+		return o.fallback("no rule or trigger matched"), nil
+	}
+
+	return nil, fmt.Errorf("could not route message: nothing matched and no default agent is set")
+}
+
+func (o *Orchestrator) fallback(reason string) *Decision {
+	return &Decision{
+		Agents: []string{o.config.DefaultAgent},
+		Via:    ViaDefault,
+		Reason: reason + "; using default agent " + o.config.DefaultAgent,
 	}
+}
 
-	return nil, fmt.Errorf("could not route message")
+// matchTrigger returns the first agent, by name, with a trigger keyword in
+// content.
+func (o *Orchestrator) matchTrigger(content string) (agent, trigger string) {
+	lower := strings.ToLower(content)
+	for _, name := range o.agentNames() {
+		for _, t := range o.agents[name].Triggers {
+			t = strings.TrimSpace(t)
+			if t != "" && containsWord(lower, strings.ToLower(t)) {
+				return name, t
+			}
+		}
+	}
+	return "", ""
+}
+
+// containsWord reports whether word occurs in s on word boundaries.
+func containsWord(s, word string) bool {
+	for i := 0; ; {
+		j := strings.Index(s[i:], word)
+		if j < 0 {
+			return false
+		}
+		start, end := i+j, i+j+len(word)
+		if (start == 0 || !isWordByte(s[start-1])) && (end == len(s) || !isWordByte(s[end])) {
+			return true
+		}
+		i = start + 1
+	}
+}
+
+func isWordByte(b byte) bool {
+	return b == '_' || b >= '0' && b <= '9' || b >= 'a' && b <= 'z' || b >= 0x80
+}
+
+// agentNames returns the registered agent names in sorted order.
+func (o *Orchestrator) agentNames() []string {
+	names := make([]string, 0, len(o.agents))
+	for name := range o.agents {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
 }
 
 // routeWithAI uses AI to determine the best agent.
@@ -193,8 +299,8 @@ func (o *Orchestrator) routeWithAI(ctx context.Context, content string) (string,
 
 	// Build agent descriptions
 	var agentList strings.Builder
-	for name, cfg := range o.agents {
-		_, _ = fmt.Fprintf(&agentList, "- %s: %s\n", name, cfg.Description)
+	for _, name := range o.agentNames() {
+		_, _ = fmt.Fprintf(&agentList, "- %s: %s\n", name, o.agents[name].Description)
 	}
 
 	prompt := fmt.Sprintf(`You are a routing assistant. Based on the user's message, determine which agent should handle it.
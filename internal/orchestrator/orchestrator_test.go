@@ -2,6 +2,8 @@ package orchestrator
 
 import (
 	"context"
+	"errors"
+	"strings"
 	"testing"
 
 	"github.com/eachlabs/klaw/internal/channel"
@@ -38,6 +40,13 @@ func TestParseMessage(t *testing.T) {
 			wantAgent:   "",
 			wantAll:     true,
 		},
+		{
+			name:        "hyphenated agent with punctuation",
+			input:       "@web-dev: fix\nthe header",
+			wantContent: "fix\nthe header",
+			wantAgent:   "web-dev",
+			wantAll:     false,
+		},
 		{
 			name:        "uppercase agent",
 			input:       "@CODER fix this",
@@ -88,8 +97,11 @@ func TestRoute_Manual(t *testing.T) {
 	// Unknown agent
 	parsed = o.ParseMessage("@unknown hello")
 	_, err = o.Route(ctx, parsed)
-	if err == nil {
-		t.Error("expected error for unknown agent")
+	if !errors.Is(err, ErrAgentNotFound) {
+		t.Errorf("expected agent not found, got %v", err)
+	}
+	if err != nil && !strings.Contains(err.Error(), "available: coder, writer") {
+		t.Errorf("error should list available agents: %v", err)
 	}
 }
 
@@ -220,6 +232,46 @@ func TestRoute_NoRouteMatch(t *testing.T) {
 	}
 }
 
+func TestExplain(t *testing.T) {
+	o := New(Config{
+		Mode:         "hybrid",
+		DefaultAgent: "support",
+		AllowManual:  true,
+		Rules: []RoutingRule{
+			{Match: "deploy|rollback", Agent: "devops"},
+		},
+	})
+	o.RegisterAgent(&AgentConfig{Name: "devops"})
+	o.RegisterAgent(&AgentConfig{Name: "coder", Triggers: []string{"bug", "pull request"}})
+	o.RegisterAgent(&AgentConfig{Name: "support"})
+
+	tests := []struct {
+		msg       string
+		wantAgent string
+		wantVia   string
+	}{
+		{"please deploy api", "devops", ViaKeyword},
+		{"there is a Bug in login", "coder", ViaKeyword},
+		{"review my pull request", "coder", ViaKeyword},
+		{"debugging is fun", "support", ViaDefault}, // triggers match whole words
+		{"@devops hello", "devops", ViaManual},
+	}
+	for _, tt := range tests {
+		t.Run(tt.msg, func(t *testing.T) {
+			d, err := o.Explain(context.Background(), o.ParseMessage(tt.msg))
+			if err != nil {
+				t.Fatalf("Explain error: %v", err)
+			}
+			if len(d.Agents) != 1 || d.Agents[0] != tt.wantAgent || d.Via != tt.wantVia {
+				t.Errorf("got %v via %s, want [%s] via %s", d.Agents, d.Via, tt.wantAgent, tt.wantVia)
+			}
+			if d.Reason == "" {
+				t.Error("expected a reason")
+			}
+		})
+	}
+}
+
 func TestRegisterAndUnregisterAgent(t *testing.T) {
 	o := New(Config{
 		Mode:         "rules",
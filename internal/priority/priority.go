@@ -0,0 +1,174 @@
+// Package priority orders queued work, e.g. dispatched tasks and cron jobs,
+// by priority, aging work that waits so low-priority work still runs.
+package priority
+
+import (
+	"container/list"
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Level is the priority of a piece of work; higher runs first.
+type Level int
+
+// The named levels. Other values order around them.
+const (
+	Low    Level = -1
+	Normal Level = 0
+	High   Level = 1
+)
+
+// DefaultAging is how long work waits before it is treated one level
+// higher.
+const DefaultAging = time.Minute
+
+// Parse reads a level: low, normal (or empty) or high, or a number.
+func Parse(s string) (Level, error) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "", "normal":
+		return Normal, nil
+	case "low":
+		return Low, nil
+	case "high":
+		return High, nil
+	}
+	n, err := strconv.Atoi(strings.TrimSpace(s))
+	if err != nil {
+		return Normal, fmt.Errorf("invalid priority %q (use low, normal, high or a number)", s)
+	}
+	return Level(n), nil
+}
+
+func (l Level) String() string {
+	switch l {
+	case Low:
+		return "low"
+	case Normal:
+		return "normal"
+	case High:
+		return "high"
+	}
+	return strconv.Itoa(int(l))
+}
+
+// Limiter bounds how much work runs at once. Waiting work is let in
+// highest priority first, and in order of arrival within a level; work
+// rises a level for every aging period it waits, so it is not starved.
+type Limiter struct {
+	aging time.Duration
+	now   func() time.Time // replaced in tests
+
+	mu      sync.Mutex
+	limit   int
+	running int
+	waiting *list.List // of *waiter, in order of arrival
+}
+
+type waiter struct {
+	level Level
+	since time.Time
+	ready chan struct{}
+}
+
+// NewLimiter creates a limiter letting limit pieces of work run at once; 0
+// or less means no limit. An aging of 0 means DefaultAging.
+func NewLimiter(limit int, aging time.Duration) *Limiter {
+	if aging <= 0 {
+		aging = DefaultAging
+	}
+	return &Limiter{aging: aging, now: time.Now, limit: limit, waiting: list.New()}
+}
+
+// TryAcquire lets work in if it can run without waiting, and reports
+// whether it did. Work let in must be followed by a Release.
+func (l *Limiter) TryAcquire() bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.tryAcquire()
+}
+
+// tryAcquire is TryAcquire with l.mu held.
+func (l *Limiter) tryAcquire() bool {
+	if l.limit <= 0 || (l.running < l.limit && l.waiting.Len() == 0) {
+		l.running++
+		return true
+	}
+	return false
+}
+
+// Acquire waits until work of the level may run. Each successful Acquire
+// must be followed by a Release.
+func (l *Limiter) Acquire(ctx context.Context, level Level) error {
+	l.mu.Lock()
+	if l.tryAcquire() {
+		l.mu.Unlock()
+		return nil
+	}
+	w := &waiter{level: level, since: l.now(), ready: make(chan struct{})}
+	elem := l.waiting.PushBack(w)
+	l.mu.Unlock()
+
+	select {
+	case <-w.ready:
+		return nil
+	case <-ctx.Done():
+		l.mu.Lock()
+		defer l.mu.Unlock()
+		select {
+		case <-w.ready:
+			// Let in just now: hand the slot on
+			l.running--
+			l.grant()
+		default:
+			l.waiting.Remove(elem)
+		}
+		return ctx.Err()
+	}
+}
+
+// Release ends work let in by Acquire, letting in the next waiting work.
+func (l *Limiter) Release() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.running--
+	l.grant()
+}
+
+// SetLimit changes the limit, letting in waiting work if it was raised.
+func (l *Limiter) SetLimit(limit int) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.limit = limit
+	l.grant()
+}
+
+// Len returns the amount of work running and waiting.
+func (l *Limiter) Len() (running, waiting int) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.running, l.waiting.Len()
+}
+
+// grant lets in waiting work while there is room, the highest effective
+// level first. l.mu must be held.
+func (l *Limiter) grant() {
+	now := l.now()
+	for l.waiting.Len() > 0 && (l.limit <= 0 || l.running < l.limit) {
+		var best *list.Element
+		var bestLevel Level
+		for e := l.waiting.Front(); e != nil; e = e.Next() {
+			w := e.Value.(*waiter)
+			level := w.level + Level(now.Sub(w.since)/l.aging)
+			if best == nil || level > bestLevel {
+				best, bestLevel = e, level
+			}
+		}
+		l.waiting.Remove(best)
+		l.running++
+		close(best.Value.(*waiter).ready)
+	}
+}
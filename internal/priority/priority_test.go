@@ -0,0 +1,173 @@
+package priority
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		in   string
+		want Level
+	}{
+		{"", Normal},
+		{"normal", Normal},
+		{"LOW", Low},
+		{"high", High},
+		{"5", 5},
+		{"-3", -3},
+	}
+	for _, tt := range tests {
+		got, err := Parse(tt.in)
+		if err != nil || got != tt.want {
+			t.Errorf("Parse(%q) = %v, %v; want %v", tt.in, got, err, tt.want)
+		}
+	}
+	if _, err := Parse("urgent"); err == nil {
+		t.Error("expected an error for an unknown priority")
+	}
+	if High.String() != "high" || Level(3).String() != "3" {
+		t.Errorf("unexpected names %s, %s", High, Level(3))
+	}
+}
+
+// waitQueued waits until n pieces of work wait in l.
+func waitQueued(t *testing.T, l *Limiter, n int) {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		if _, waiting := l.Len(); waiting == n {
+			return
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("expected %d waiting", n)
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestLimiter_OrdersByPriority(t *testing.T) {
+	l := NewLimiter(1, time.Hour)
+	ctx := context.Background()
+	if err := l.Acquire(ctx, Normal); err != nil {
+		t.Fatal(err)
+	}
+
+	order := make(chan string, 3)
+	start := func(name string, level Level) {
+		go func() {
+			if err := l.Acquire(ctx, level); err != nil {
+				t.Error(err)
+				return
+			}
+			order <- name
+			l.Release()
+		}()
+	}
+	start("low", Low)
+	waitQueued(t, l, 1)
+	start("normal", Normal)
+	waitQueued(t, l, 2)
+	start("high", High)
+	waitQueued(t, l, 3)
+
+	l.Release()
+	for _, want := range []string{"high", "normal", "low"} {
+		if got := <-order; got != want {
+			t.Errorf("ran %s, want %s", got, want)
+		}
+	}
+}
+
+func TestLimiter_Aging(t *testing.T) {
+	l := NewLimiter(1, time.Minute)
+	now := time.Now()
+	l.now = func() time.Time { return now }
+	ctx := context.Background()
+	if err := l.Acquire(ctx, Normal); err != nil {
+		t.Fatal(err)
+	}
+
+	order := make(chan string, 2)
+	start := func(name string, level Level) {
+		go func() {
+			if err := l.Acquire(ctx, level); err != nil {
+				t.Error(err)
+				return
+			}
+			order <- name
+			l.Release()
+		}()
+	}
+	start("low", Low)
+	waitQueued(t, l, 1)
+
+	// The low work waited three minutes, rising to level 2
+	l.mu.Lock()
+	now = now.Add(3 * time.Minute)
+	l.mu.Unlock()
+	start("high", High)
+	waitQueued(t, l, 2)
+
+	l.Release()
+	if got := <-order; got != "low" {
+		t.Errorf("ran %s first, want the aged low work", got)
+	}
+	<-order
+}
+
+func TestLimiter_AcquireCancelled(t *testing.T) {
+	l := NewLimiter(1, 0)
+	if err := l.Acquire(context.Background(), Normal); err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() { done <- l.Acquire(ctx, High) }()
+	waitQueued(t, l, 1)
+	cancel()
+	if err := <-done; err == nil {
+		t.Fatal("expected the cancelled Acquire to fail")
+	}
+	if running, waiting := l.Len(); running != 1 || waiting != 0 {
+		t.Errorf("running = %d, waiting = %d; want 1 and 0", running, waiting)
+	}
+
+	l.Release()
+	if err := l.Acquire(context.Background(), Low); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestLimiter_TryAcquire(t *testing.T) {
+	l := NewLimiter(1, 0)
+	if !l.TryAcquire() {
+		t.Fatal("expected room for the first work")
+	}
+	if l.TryAcquire() {
+		t.Fatal("expected no room while the work runs")
+	}
+	l.Release()
+	if !l.TryAcquire() {
+		t.Fatal("expected room after Release")
+	}
+}
+
+func TestLimiter_SetLimit(t *testing.T) {
+	l := NewLimiter(1, 0)
+	ctx := context.Background()
+	_ = l.Acquire(ctx, Normal)
+	done := make(chan struct{})
+	go func() {
+		_ = l.Acquire(ctx, Normal)
+		close(done)
+	}()
+	waitQueued(t, l, 1)
+	l.SetLimit(2)
+	select {
+	case <-done:
+	case <-time.After(2 * time.Second):
+		t.Fatal("expected raising the limit to let the waiting work in")
+	}
+}
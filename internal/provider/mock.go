@@ -0,0 +1,65 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"sync"
+)
+
+// Mock is a provider that answers with canned responses, in order, and
+// keeps the requests it received. It is used to replay recorded turns.
+type Mock struct {
+	Responses []*ChatResponse
+
+	mu       sync.Mutex
+	requests []*ChatRequest
+}
+
+// NewMock creates a mock provider answering with responses.
+func NewMock(responses ...*ChatResponse) *Mock {
+	return &Mock{Responses: responses}
+}
+
+func (m *Mock) Name() string     { return "mock" }
+func (m *Mock) Models() []string { return []string{"mock"} }
+
+// Requests returns the requests received so far.
+func (m *Mock) Requests() []*ChatRequest {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]*ChatRequest(nil), m.requests...)
+}
+
+// Chat returns the next response, or an error when none are left.
+func (m *Mock) Chat(ctx context.Context, req *ChatRequest) (*ChatResponse, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	n := len(m.requests)
+	m.requests = append(m.requests, req)
+	if n >= len(m.Responses) {
+		return nil, fmt.Errorf("mock: no response for request %d (%d recorded)", n+1, len(m.Responses))
+	}
+	return m.Responses[n], nil
+}
+
+// Stream streams the next response as text and tool_use events followed
+// by a stop event.
+func (m *Mock) Stream(ctx context.Context, req *ChatRequest) (<-chan StreamEvent, error) {
+	resp, err := m.Chat(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	events := make(chan StreamEvent, len(resp.Content)+1)
+	for _, block := range resp.Content {
+		switch block.Type {
+		case "text":
+			events <- StreamEvent{Type: "text", Text: block.Text}
+		case "tool_use":
+			events <- StreamEvent{Type: "tool_use", ToolUse: block.ToolUse}
+		}
+	}
+	usage := resp.Usage
+	events <- StreamEvent{Type: "stop", Usage: &usage}
+	close(events)
+	return events, nil
+}
@@ -0,0 +1,139 @@
+// Package redis is a minimal Redis client speaking RESP, the Redis
+// protocol, for the bus and conversation leases.
+package redis
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Conn is a connection to a Redis server. It is not safe for concurrent
+// use.
+type Conn struct {
+	c net.Conn
+	r *bufio.Reader
+}
+
+// Dial connects to the Redis server of u, redis://[user:password@]host[:port][/db],
+// authenticating and selecting the database it names.
+func Dial(u *url.URL) (*Conn, error) {
+	addr := u.Host
+	if u.Port() == "" {
+		host := u.Hostname()
+		if host == "" {
+			host = "localhost"
+		}
+		addr = net.JoinHostPort(host, "6379")
+	}
+	c, err := net.DialTimeout("tcp", addr, 10*time.Second)
+	if err != nil {
+		return nil, fmt.Errorf("redis: %w", err)
+	}
+	conn := &Conn{c: c, r: bufio.NewReader(c)}
+	if u.User != nil {
+		args := []string{"AUTH"}
+		if pass, ok := u.User.Password(); ok {
+			if name := u.User.Username(); name != "" {
+				args = append(args, name)
+			}
+			args = append(args, pass)
+		} else {
+			args = append(args, u.User.Username())
+		}
+		if _, err := conn.Do(args...); err != nil {
+			_ = conn.Close()
+			return nil, fmt.Errorf("redis: %w", err)
+		}
+	}
+	if db := strings.Trim(u.Path, "/"); db != "" && db != "0" {
+		if _, err := conn.Do("SELECT", db); err != nil {
+			_ = conn.Close()
+			return nil, fmt.Errorf("redis: %w", err)
+		}
+	}
+	return conn, nil
+}
+
+// SetDeadline sets the deadline of the commands that follow; the zero time
+// removes it.
+func (c *Conn) SetDeadline(t time.Time) error {
+	return c.c.SetDeadline(t)
+}
+
+// Close closes the connection.
+func (c *Conn) Close() error {
+	return c.c.Close()
+}
+
+// Do sends a command and reads its reply: a string, int64, []any or nil.
+// An error reply is returned as an Error.
+func (c *Conn) Do(args ...string) (any, error) {
+	var b strings.Builder
+	b.WriteString("*" + strconv.Itoa(len(args)) + "\r\n")
+	for _, a := range args {
+		b.WriteString("$" + strconv.Itoa(len(a)) + "\r\n" + a + "\r\n")
+	}
+	if _, err := io.WriteString(c.c, b.String()); err != nil {
+		return nil, err
+	}
+	return readReply(c.r)
+}
+
+// Error is an error reply.
+type Error string
+
+func (e Error) Error() string { return string(e) }
+
+// readReply reads one reply.
+func readReply(r *bufio.Reader) (any, error) {
+	line, err := r.ReadString('\n')
+	if err != nil {
+		return nil, err
+	}
+	line = strings.TrimRight(line, "\r\n")
+	if line == "" {
+		return nil, fmt.Errorf("redis: empty reply")
+	}
+	switch line[0] {
+	case '+':
+		return line[1:], nil
+	case '-':
+		return nil, Error(line[1:])
+	case ':':
+		return strconv.ParseInt(line[1:], 10, 64)
+	case '$':
+		n, err := strconv.Atoi(line[1:])
+		if err != nil || n < 0 {
+			return nil, err
+		}
+		data := make([]byte, n+2)
+		if _, err := io.ReadFull(r, data); err != nil {
+			return nil, err
+		}
+		return string(data[:n]), nil
+	case '*':
+		n, err := strconv.Atoi(line[1:])
+		if err != nil || n < 0 {
+			return nil, err
+		}
+		items := make([]any, n)
+		for i := range items {
+			item, err := readReply(r)
+			var rerr Error
+			if err != nil && !errors.As(err, &rerr) {
+				return nil, err
+			}
+			items[i] = item
+		}
+		return items, nil
+	default:
+		return nil, fmt.Errorf("redis: unexpected reply %q", line)
+	}
+}
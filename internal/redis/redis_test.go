@@ -0,0 +1,34 @@
+package redis
+
+import (
+	"bufio"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestReadReply(t *testing.T) {
+	for raw, want := range map[string]any{
+		"+OK\r\n":                       "OK",
+		":42\r\n":                       int64(42),
+		"$5\r\nhello\r\n":               "hello",
+		"$0\r\n\r\n":                    "",
+		"$-1\r\n":                       nil,
+		"*-1\r\n":                       nil,
+		"*2\r\n$1\r\na\r\n*1\r\n:1\r\n": []any{"a", []any{int64(1)}},
+		"*2\r\n-ERR one\r\n+OK\r\n":     []any{nil, "OK"},
+	} {
+		got, err := readReply(bufio.NewReader(strings.NewReader(raw)))
+		if err != nil {
+			t.Fatalf("%q: %v", raw, err)
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("%q: got %#v, want %#v", raw, got, want)
+		}
+	}
+
+	_, err := readReply(bufio.NewReader(strings.NewReader("-BUSYGROUP exists\r\n")))
+	if _, ok := err.(Error); !ok || !strings.HasPrefix(err.Error(), "BUSYGROUP") {
+		t.Fatalf("expected BUSYGROUP error, got %v", err)
+	}
+}
@@ -0,0 +1,301 @@
+package runtime
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// DefaultSandboxImage is the image sandbox containers run when none is
+// configured.
+const DefaultSandboxImage = "docker.io/library/debian:stable-slim"
+
+// sandboxLabel marks the containers of a ContainerSandbox with the
+// instance that started them, so those left behind by a crashed instance
+// are removed on the next Prepare on the same host.
+const sandboxLabel = "klaw.sandbox"
+
+// SandboxConfig configures the containers of a ContainerSandbox.
+type SandboxConfig struct {
+	Engine      string        // podman (default) or docker
+	Image       string        // default: DefaultSandboxImage
+	Memory      string        // memory limit, e.g. "1g"; default: 1g
+	CPUs        string        // CPU limit, e.g. "1.5"; default: 1
+	PidsLimit   int           // process limit; default: 256
+	Network     string        // network mode, e.g. "none"; default: the engine's
+	IdleTimeout time.Duration // containers unused this long are removed; default: 10 minutes
+	Instance    string        // the process owning the containers, as <host>-<pid>; default: this process
+}
+
+// ContainerSandbox runs commands in ephemeral containers with a host
+// directory mounted at /workspace. The commands of a key (a conversation)
+// share a container, started on the first command and removed by Release,
+// after IdleTimeout, or by Close.
+type ContainerSandbox struct {
+	cfg SandboxConfig
+
+	// run runs the engine CLI with env added to its environment; replaced
+	// in tests
+	run func(ctx context.Context, env []string, args ...string) ([]byte, error)
+
+	mu         sync.Mutex
+	containers map[string]*sandboxContainer // by key and directory
+}
+
+type sandboxContainer struct {
+	name     string
+	key      string
+	lastUsed time.Time
+}
+
+// NewContainerSandbox creates a sandbox; see Prepare.
+func NewContainerSandbox(cfg SandboxConfig) *ContainerSandbox {
+	if cfg.Engine == "" {
+		cfg.Engine = "podman"
+	}
+	if cfg.Image == "" {
+		cfg.Image = DefaultSandboxImage
+	}
+	if cfg.Memory == "" {
+		cfg.Memory = "1g"
+	}
+	if cfg.CPUs == "" {
+		cfg.CPUs = "1"
+	}
+	if cfg.PidsLimit == 0 {
+		cfg.PidsLimit = 256
+	}
+	if cfg.IdleTimeout == 0 {
+		cfg.IdleTimeout = 10 * time.Minute
+	}
+	if cfg.Instance == "" {
+		host, _ := os.Hostname()
+		cfg.Instance = fmt.Sprintf("%s-%d", host, os.Getpid())
+	}
+	s := &ContainerSandbox{cfg: cfg, containers: make(map[string]*sandboxContainer)}
+	s.run = func(ctx context.Context, env []string, args ...string) ([]byte, error) {
+		cmd := exec.CommandContext(ctx, s.cfg.Engine, args...)
+		if len(env) > 0 {
+			cmd.Env = append(os.Environ(), env...)
+		}
+		return cmd.CombinedOutput()
+	}
+	return s
+}
+
+// Image returns the image of the sandbox containers.
+func (s *ContainerSandbox) Image() string {
+	return s.cfg.Image
+}
+
+// Prepare checks that the container engine works, pulls the image when it
+// is missing, and removes the sandbox containers left behind by instances
+// on this host that are no longer running. Containers of running
+// instances, e.g. other klaw processes on the host, are kept.
+func (s *ContainerSandbox) Prepare(ctx context.Context) error {
+	if out, err := s.run(ctx, nil, "version"); err != nil {
+		return fmt.Errorf("%s is not available: %w: %s", s.cfg.Engine, err, strings.TrimSpace(string(out)))
+	}
+	if _, err := s.run(ctx, nil, "image", "inspect", s.cfg.Image); err != nil {
+		if out, err := s.run(ctx, nil, "pull", s.cfg.Image); err != nil {
+			return fmt.Errorf("pull %s: %w: %s", s.cfg.Image, err, strings.TrimSpace(string(out)))
+		}
+	}
+	if out, err := s.run(ctx, nil, "ps", "-aq", "--filter", "label="+sandboxLabel); err == nil {
+		for _, id := range strings.Fields(string(out)) {
+			owner, err := s.run(ctx, nil, "inspect", "--format", `{{index .Config.Labels "`+sandboxLabel+`"}}`, id)
+			if err == nil && s.staleOwner(strings.TrimSpace(string(owner))) {
+				_, _ = s.run(ctx, nil, "rm", "-f", id)
+			}
+		}
+	}
+	return nil
+}
+
+// processRunning reports whether a process of this host is running;
+// replaced in tests.
+var processRunning = func(pid int) bool {
+	p, err := os.FindProcess(pid)
+	return err == nil && p.Signal(syscall.Signal(0)) == nil
+}
+
+// staleOwner reports whether the instance that started a container is
+// gone: this instance, which has none yet when it prepares, or another
+// one of this host whose process has exited.
+func (s *ContainerSandbox) staleOwner(owner string) bool {
+	if owner == s.cfg.Instance {
+		return true
+	}
+	i := strings.LastIndex(owner, "-")
+	j := strings.LastIndex(s.cfg.Instance, "-")
+	if i < 0 || j < 0 || owner[:i] != s.cfg.Instance[:j] {
+		return false
+	}
+	pid, err := strconv.Atoi(owner[i+1:])
+	return err == nil && !processRunning(pid)
+}
+
+// runArgs are the arguments of "<engine> run" for a container with dir
+// mounted, before the command.
+func (s *ContainerSandbox) runArgs(dir string) []string {
+	args := []string{
+		"--label", sandboxLabel + "=" + s.cfg.Instance,
+		"-v", dir + ":/workspace:z",
+		"-w", "/workspace",
+		"--memory", s.cfg.Memory,
+		"--cpus", s.cfg.CPUs,
+		"--pids-limit", strconv.Itoa(s.cfg.PidsLimit),
+	}
+	if s.cfg.Network != "" {
+		args = append(args, "--network", s.cfg.Network)
+	}
+	return args
+}
+
+// Exec runs command with sh in the container of key, starting it if
+// needed. Without a key the command gets a container of its own. A
+// command still running after timeout is killed.
+func (s *ContainerSandbox) Exec(ctx context.Context, key, dir, command string, env []string, timeout time.Duration) (string, error) {
+	secs := strconv.Itoa(max(1, int(timeout/time.Second)))
+	ctx, cancel := context.WithTimeout(ctx, timeout+30*time.Second)
+	defer cancel()
+
+	// Only the names go on the command line, where any local user could
+	// read the values, e.g. skill credentials; the engine takes the values
+	// from its environment
+	var envArgs []string
+	for _, e := range env {
+		name, _, _ := strings.Cut(e, "=")
+		envArgs = append(envArgs, "-e", name)
+	}
+
+	var args []string
+	if key == "" {
+		args = append([]string{"run", "--rm"}, s.runArgs(dir)...)
+		args = append(append(args, envArgs...), s.cfg.Image)
+	} else {
+		name, err := s.container(ctx, key, dir)
+		if err != nil {
+			return "", err
+		}
+		args = append(append([]string{"exec"}, envArgs...), name)
+	}
+	args = append(args, "timeout", "-s", "KILL", secs, "sh", "-c", command)
+
+	out, err := s.run(ctx, env, args...)
+	var exitErr *exec.ExitError
+	switch {
+	case err == nil:
+		return string(out), nil
+	case errors.As(err, &exitErr) && exitErr.ExitCode() == 137:
+		return string(out), fmt.Errorf("command timed out after %ss", secs)
+	case errors.As(err, &exitErr):
+		return string(out), fmt.Errorf("exit status %d", exitErr.ExitCode())
+	default:
+		return string(out), fmt.Errorf("%s: %w", s.cfg.Engine, err)
+	}
+}
+
+// container returns the running container of key and dir, starting one if
+// there is none.
+func (s *ContainerSandbox) container(ctx context.Context, key, dir string) (string, error) {
+	sum := sha256.Sum256([]byte(key + "\x00" + dir))
+	id := hex.EncodeToString(sum[:])
+
+	if name, ok := s.lookup(id); ok {
+		return name, nil
+	}
+
+	// Start the container without the lock, which would stall the
+	// commands of every other conversation meanwhile
+	name := "klaw-sandbox-" + uuid.New().String()[:8]
+	args := append([]string{"run", "-d", "--rm", "--name", name}, s.runArgs(dir)...)
+	args = append(args, s.cfg.Image, "sleep", "infinity")
+	if out, err := s.run(ctx, nil, args...); err != nil {
+		return "", fmt.Errorf("start container: %w: %s", err, strings.TrimSpace(string(out)))
+	}
+
+	s.mu.Lock()
+	if c, ok := s.containers[id]; ok {
+		// Another command of the conversation started one first
+		c.lastUsed = time.Now()
+		s.mu.Unlock()
+		_, _ = s.run(context.Background(), nil, "rm", "-f", name)
+		return c.name, nil
+	}
+	s.containers[id] = &sandboxContainer{name: name, key: key, lastUsed: time.Now()}
+	s.mu.Unlock()
+	return name, nil
+}
+
+// lookup returns the container by id, marking it used.
+func (s *ContainerSandbox) lookup(id string) (string, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	c, ok := s.containers[id]
+	if !ok {
+		return "", false
+	}
+	c.lastUsed = time.Now()
+	return c.name, true
+}
+
+// Release removes the containers of key.
+func (s *ContainerSandbox) Release(key string) {
+	s.remove(func(c *sandboxContainer) bool { return c.key == key })
+}
+
+// ReleaseIdle removes the containers unused for longer than the idle
+// timeout, e.g. those of turns that were stopped.
+func (s *ContainerSandbox) ReleaseIdle() {
+	cutoff := time.Now().Add(-s.cfg.IdleTimeout)
+	s.remove(func(c *sandboxContainer) bool { return c.lastUsed.Before(cutoff) })
+}
+
+// Close removes all containers of the sandbox.
+func (s *ContainerSandbox) Close() {
+	s.remove(func(*sandboxContainer) bool { return true })
+}
+
+func (s *ContainerSandbox) remove(match func(*sandboxContainer) bool) {
+	var names []string
+	s.mu.Lock()
+	for id, c := range s.containers {
+		if match(c) {
+			names = append(names, c.name)
+			delete(s.containers, id)
+		}
+	}
+	s.mu.Unlock()
+
+	for _, name := range names {
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		_, _ = s.run(ctx, nil, "rm", "-f", name)
+		cancel()
+	}
+}
+
+// Run removes idle containers every minute until ctx is done.
+func (s *ContainerSandbox) Run(ctx context.Context) {
+	ticker := time.NewTicker(time.Minute)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			s.ReleaseIdle()
+		}
+	}
+}
@@ -0,0 +1,125 @@
+package runtime
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+// fakeEngine records the engine commands run and answers them.
+type fakeEngine struct {
+	calls  []string
+	envs   [][]string
+	answer func(args []string) ([]byte, error)
+}
+
+func (f *fakeEngine) run(ctx context.Context, env []string, args ...string) ([]byte, error) {
+	f.calls = append(f.calls, strings.Join(args, " "))
+	f.envs = append(f.envs, env)
+	if f.answer != nil {
+		return f.answer(args)
+	}
+	return nil, nil
+}
+
+func (f *fakeEngine) count(prefix string) int {
+	n := 0
+	for _, c := range f.calls {
+		if strings.HasPrefix(c, prefix) {
+			n++
+		}
+	}
+	return n
+}
+
+func TestContainerSandbox_Exec(t *testing.T) {
+	engine := &fakeEngine{}
+	s := NewContainerSandbox(SandboxConfig{Memory: "512m", Network: "none"})
+	s.run = engine.run
+	ctx := context.Background()
+
+	for _, cmd := range []string{"ls", "pwd"} {
+		if _, err := s.Exec(ctx, "C1:1.1", "/srv/agents/coder", cmd, []string{"API_KEY=s3cret"}, 5*time.Second); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if n := engine.count("run -d"); n != 1 {
+		t.Fatalf("expected one container for the conversation, got %d: %v", n, engine.calls)
+	}
+	start := engine.calls[0]
+	for _, want := range []string{"--label klaw.sandbox=" + s.cfg.Instance, "-v /srv/agents/coder:/workspace:z", "--memory 512m", "--cpus 1", "--pids-limit 256", "--network none", DefaultSandboxImage + " sleep infinity"} {
+		if !strings.Contains(start, want) {
+			t.Errorf("expected %q in %s", want, start)
+		}
+	}
+	if exec := engine.calls[2]; !strings.HasPrefix(exec, "exec -e API_KEY klaw-sandbox-") || !strings.HasSuffix(exec, "timeout -s KILL 5 sh -c pwd") {
+		t.Errorf("unexpected exec: %s", exec)
+	}
+	if env := engine.envs[2]; len(env) != 1 || env[0] != "API_KEY=s3cret" {
+		t.Errorf("expected the value in the engine's environment, got %v", env)
+	}
+	for _, c := range engine.calls {
+		if strings.Contains(c, "s3cret") {
+			t.Errorf("secret on the command line: %s", c)
+		}
+	}
+
+	// Another directory of the conversation, and commands without one, get their own
+	_, _ = s.Exec(ctx, "C1:1.1", "/srv/agents/writer", "ls", nil, time.Second)
+	_, _ = s.Exec(ctx, "", "/srv/agents/coder", "ls", nil, time.Second)
+	if n := engine.count("run -d"); n != 2 {
+		t.Errorf("expected a second container for the other directory, got %d", n)
+	}
+	if n := engine.count("run --rm"); n != 1 {
+		t.Errorf("expected a one-off container without a key, got %d", n)
+	}
+
+	s.Release("C1:1.1")
+	if n := engine.count("rm -f klaw-sandbox-"); n != 2 {
+		t.Errorf("expected both containers removed, got %v", engine.calls)
+	}
+}
+
+func TestContainerSandbox_Prepare(t *testing.T) {
+	owners := map[string]string{
+		"abc": "host1-100", // this instance, from before a restart
+		"def": "host1-200", // exited
+		"ghi": "host1-300", // running
+		"jkl": "host2-200", // another host
+	}
+	engine := &fakeEngine{answer: func(args []string) ([]byte, error) {
+		switch args[0] {
+		case "image":
+			return nil, errors.New("no such image")
+		case "ps":
+			return []byte("abc\ndef\nghi\njkl\n"), nil
+		case "inspect":
+			return []byte(owners[args[len(args)-1]] + "\n"), nil
+		}
+		return nil, nil
+	}}
+	running := processRunning
+	processRunning = func(pid int) bool { return pid == 300 }
+	defer func() { processRunning = running }()
+
+	s := NewContainerSandbox(SandboxConfig{Engine: "docker", Image: "alpine:3", Instance: "host1-100"})
+	s.run = engine.run
+
+	if err := s.Prepare(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	var removed []string
+	for _, c := range engine.calls {
+		if id, ok := strings.CutPrefix(c, "rm -f "); ok {
+			removed = append(removed, id)
+		}
+	}
+	if strings.Join(removed, ",") != "abc,def" {
+		t.Errorf("expected the containers of gone instances of this host removed, got %v", engine.calls)
+	}
+	if !strings.HasPrefix(strings.Join(engine.calls, "|"), "version|image inspect alpine:3|pull alpine:3|ps -aq --filter label=klaw.sandbox|") {
+		t.Errorf("unexpected calls: %v", engine.calls)
+	}
+}
@@ -13,6 +13,7 @@ import (
 	"sync"
 	"time"
 
+	"github.com/eachlabs/klaw/internal/priority"
 	"github.com/google/uuid"
 )
 
@@ -32,9 +33,13 @@ type Job struct {
 	LastRun     *time.Time        `json:"last_run,omitempty"`
 	NextRun     *time.Time        `json:"next_run,omitempty"`
 	RunCount    int               `json:"run_count"`
+	FailCount   int               `json:"fail_count,omitempty"` // runs that returned an error
 	LastResult  string            `json:"last_result,omitempty"`
 	LastError   string            `json:"last_error,omitempty"`
 	Config      map[string]string `json:"config,omitempty"`
+	PausedBy    string            `json:"paused_by,omitempty"` // what disabled the job, e.g. "budget"; empty = the user
+	Priority    priority.Level    `json:"priority,omitempty"`  // orders the run against queued messages
+	Cursor      string            `json:"cursor,omitempty"`    // position of the last input processed, e.g. a Slack message timestamp
 }
 
 // JobRun represents a single execution of a job
@@ -56,7 +61,11 @@ type Scheduler struct {
 	ctx       context.Context
 	cancel    context.CancelFunc
 	running   bool
+	draining  bool            // set by Drain; no job starts after it
+	runs      sync.WaitGroup  // jobs running
+	active    map[string]bool // IDs of the jobs running
 	jobRunner JobRunner
+	tickGate  func(ctx context.Context) bool
 }
 
 // JobRunner is called when a job needs to run
@@ -67,6 +76,7 @@ func NewScheduler(dataDir string) *Scheduler {
 	return &Scheduler{
 		dataDir: dataDir,
 		jobs:    make(map[string]*Job),
+		active:  make(map[string]bool),
 	}
 }
 
@@ -75,6 +85,14 @@ func (s *Scheduler) SetJobRunner(runner JobRunner) {
 	s.jobRunner = runner
 }
 
+// SetTickGate makes the scheduler run due jobs only on ticks gate allows,
+// e.g. while it holds a leader lease. The jobs are reloaded from disk on
+// each allowed tick, so schedulers sharing them see each other's runs and
+// jobs created since.
+func (s *Scheduler) SetTickGate(gate func(ctx context.Context) bool) {
+	s.tickGate = gate
+}
+
 // Load loads jobs from disk
 func (s *Scheduler) Load() error {
 	s.mu.Lock()
@@ -125,6 +143,25 @@ func (s *Scheduler) Save() error {
 
 // CreateJob creates a new scheduled job
 func (s *Scheduler) CreateJob(name, schedule, agent, task, cluster, namespace string) (*Job, error) {
+	job, err := NewJob(name, schedule, agent, task, cluster, namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	s.jobs[job.ID] = job
+	s.mu.Unlock()
+
+	if err := s.Save(); err != nil {
+		return nil, err
+	}
+
+	return job, nil
+}
+
+// NewJob builds an enabled job with its next run, without adding it to a
+// scheduler.
+func NewJob(name, schedule, agent, task, cluster, namespace string) (*Job, error) {
 	// Parse natural language schedule to cron
 	cron, err := ParseSchedule(schedule)
 	if err != nil {
@@ -147,15 +184,6 @@ func (s *Scheduler) CreateJob(name, schedule, agent, task, cluster, namespace st
 	// Calculate next run
 	nextRun := NextRunTime(cron)
 	job.NextRun = &nextRun
-
-	s.mu.Lock()
-	s.jobs[job.ID] = job
-	s.mu.Unlock()
-
-	if err := s.Save(); err != nil {
-		return nil, err
-	}
-
 	return job, nil
 }
 
@@ -185,12 +213,68 @@ func (s *Scheduler) ListJobs(cluster, namespace string) []*Job {
 	return jobs
 }
 
+// FindJob returns the job in a namespace with the given ID or name
+func (s *Scheduler) FindJob(cluster, namespace, ref string) (*Job, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	for _, job := range s.jobs {
+		if job.Cluster != cluster || job.Namespace != namespace {
+			continue
+		}
+		if job.ID == ref || job.Name == ref {
+			return job, nil
+		}
+	}
+	return nil, fmt.Errorf("job not found: %s", ref)
+}
+
+// UpdateJob applies update to a job, recalculates its next run and saves it.
+// Callers changing the schedule must set both Schedule and Cron.
+func (s *Scheduler) UpdateJob(id string, update func(job *Job)) (*Job, error) {
+	s.mu.Lock()
+	job, ok := s.jobs[id]
+	if !ok {
+		s.mu.Unlock()
+		return nil, fmt.Errorf("job not found: %s", id)
+	}
+	update(job)
+	if job.Enabled {
+		nextRun := NextRunTime(job.Cron)
+		job.NextRun = &nextRun
+	} else {
+		job.NextRun = nil
+	}
+	s.mu.Unlock()
+
+	if err := s.Save(); err != nil {
+		return nil, err
+	}
+	return job, nil
+}
+
+// SetCursor records the position of the last input a job processed, for
+// its next run to go on from.
+func (s *Scheduler) SetCursor(id, cursor string) error {
+	s.mu.Lock()
+	job, ok := s.jobs[id]
+	if ok {
+		job.Cursor = cursor
+	}
+	s.mu.Unlock()
+	if !ok {
+		return fmt.Errorf("job not found: %s", id)
+	}
+	return s.Save()
+}
+
 // DeleteJob deletes a job
 func (s *Scheduler) DeleteJob(id string) error {
 	s.mu.Lock()
 	delete(s.jobs, id)
 	s.mu.Unlock()
 
+	_ = os.Remove(s.seenPath(id))
 	return s.Save()
 }
 
@@ -199,6 +283,7 @@ func (s *Scheduler) EnableJob(id string) error {
 	s.mu.Lock()
 	if job, ok := s.jobs[id]; ok {
 		job.Enabled = true
+		job.PausedBy = ""
 		nextRun := NextRunTime(job.Cron)
 		job.NextRun = &nextRun
 	}
@@ -212,6 +297,7 @@ func (s *Scheduler) DisableJob(id string) error {
 	s.mu.Lock()
 	if job, ok := s.jobs[id]; ok {
 		job.Enabled = false
+		job.PausedBy = ""
 		job.NextRun = nil
 	}
 	s.mu.Unlock()
@@ -245,6 +331,30 @@ func (s *Scheduler) Stop() {
 	}
 }
 
+// Drain stops the scheduler from starting jobs and waits for the running
+// ones to finish. If ctx is done first, Drain returns its error and the
+// remaining jobs are cancelled. The scheduler is stopped either way.
+func (s *Scheduler) Drain(ctx context.Context) error {
+	s.mu.Lock()
+	s.draining = true
+	s.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		s.runs.Wait()
+		close(done)
+	}()
+
+	var err error
+	select {
+	case <-done:
+	case <-ctx.Done():
+		err = ctx.Err()
+	}
+	s.Stop()
+	return err
+}
+
 // run is the main scheduler loop
 func (s *Scheduler) run() {
 	ticker := time.NewTicker(time.Minute)
@@ -260,9 +370,46 @@ func (s *Scheduler) run() {
 	}
 }
 
+// reload replaces the jobs with the ones on disk, keeping the running
+// jobs, whose results are yet to be saved.
+func (s *Scheduler) reload() error {
+	data, err := os.ReadFile(filepath.Join(s.dataDir, "jobs.json"))
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	var jobs []*Job
+	if len(data) > 0 {
+		if err := json.Unmarshal(data, &jobs); err != nil {
+			return err
+		}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	reloaded := make(map[string]*Job, len(jobs))
+	for _, job := range jobs {
+		if current, ok := s.jobs[job.ID]; ok && s.active[job.ID] {
+			job = current
+		}
+		reloaded[job.ID] = job
+	}
+	s.jobs = reloaded
+	return nil
+}
+
 // checkJobs checks if any jobs need to run
 func (s *Scheduler) checkJobs() {
-	s.mu.RLock()
+	if s.tickGate != nil {
+		if !s.tickGate(s.ctx) || s.reload() != nil {
+			return
+		}
+	}
+
+	s.mu.Lock()
+	if s.draining {
+		s.mu.Unlock()
+		return
+	}
 	jobsToRun := make([]*Job, 0)
 	now := time.Now()
 
@@ -270,15 +417,20 @@ func (s *Scheduler) checkJobs() {
 		if !job.Enabled || job.NextRun == nil {
 			continue
 		}
+		if s.active[job.ID] {
+			continue
+		}
 		if now.After(*job.NextRun) || now.Equal(*job.NextRun) {
 			jobsToRun = append(jobsToRun, job)
+			s.active[job.ID] = true
 		}
 	}
-	s.mu.RUnlock()
+	s.runs.Add(len(jobsToRun))
+	s.mu.Unlock()
 
 	// Run jobs
 	for _, job := range jobsToRun {
-		go s.runJob(job)
+		go s.runTracked(job)
 	}
 }
 
@@ -311,14 +463,19 @@ func (s *Scheduler) runJob(job *Job) {
 	}
 	s.mu.Unlock()
 
-	// Run the job
-	result, err := s.jobRunner(s.ctx, job)
+	// Run the job; RunJobNow may be called before Start
+	ctx := s.ctx
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	result, err := s.jobRunner(ctx, job)
 
 	// Update result
 	s.mu.Lock()
 	if err != nil {
 		job.LastError = err.Error()
 		job.LastResult = ""
+		job.FailCount++
 	} else {
 		job.LastResult = result
 		job.LastError = ""
@@ -328,17 +485,36 @@ func (s *Scheduler) runJob(job *Job) {
 	_ = s.Save()
 }
 
+// runTracked runs a job the caller has added to s.runs and marked active.
+func (s *Scheduler) runTracked(job *Job) {
+	defer s.runs.Done()
+	defer func() {
+		s.mu.Lock()
+		delete(s.active, job.ID)
+		s.mu.Unlock()
+	}()
+	s.runJob(job)
+}
+
 // RunJobNow runs a job immediately
 func (s *Scheduler) RunJobNow(id string) error {
-	s.mu.RLock()
+	s.mu.Lock()
 	job, ok := s.jobs[id]
-	s.mu.RUnlock()
+	draining := s.draining
+	if ok && !draining {
+		s.runs.Add(1)
+		s.active[id] = true
+	}
+	s.mu.Unlock()
 
 	if !ok {
 		return fmt.Errorf("job not found: %s", id)
 	}
+	if draining {
+		return fmt.Errorf("scheduler is shutting down")
+	}
 
-	go s.runJob(job)
+	go s.runTracked(job)
 	return nil
 }
 
@@ -0,0 +1,286 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"os"
+	"testing"
+	"time"
+)
+
+func TestFindAndUpdateJob(t *testing.T) {
+	s := NewScheduler(t.TempDir())
+	job, err := s.CreateJob("channel-monitor", "every 5 minutes", "watcher", "check", "prod", "default")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if found, err := s.FindJob("prod", "default", "channel-monitor"); err != nil || found.ID != job.ID {
+		t.Fatalf("FindJob by name = %v, %v", found, err)
+	}
+	if _, err := s.FindJob("prod", "default", job.ID); err != nil {
+		t.Fatalf("FindJob by id: %v", err)
+	}
+	if _, err := s.FindJob("prod", "other", job.ID); err == nil {
+		t.Error("expected job to be scoped to its namespace")
+	}
+
+	updated, err := s.UpdateJob(job.ID, func(j *Job) { j.Enabled = false })
+	if err != nil {
+		t.Fatal(err)
+	}
+	if updated.Enabled || updated.NextRun != nil {
+		t.Errorf("disabled job should have no next run: %+v", updated)
+	}
+
+	updated, err = s.UpdateJob(job.ID, func(j *Job) { j.Enabled = true })
+	if err != nil {
+		t.Fatal(err)
+	}
+	if updated.NextRun == nil {
+		t.Error("re-enabled job should have a next run")
+	}
+
+	reloaded := NewScheduler(s.dataDir)
+	if err := reloaded.Load(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := reloaded.FindJob("prod", "default", "channel-monitor"); err != nil {
+		t.Errorf("update not persisted: %v", err)
+	}
+}
+
+func TestSetCursorPersists(t *testing.T) {
+	s := NewScheduler(t.TempDir())
+	job, err := s.CreateJob("channel-monitor", "every 5 minutes", "watcher", "check", "prod", "default")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := s.SetCursor(job.ID, "1700000000.000100"); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.SetCursor("missing", "1"); err == nil {
+		t.Error("expected an error for an unknown job")
+	}
+
+	reloaded := NewScheduler(s.dataDir)
+	if err := reloaded.Load(); err != nil {
+		t.Fatal(err)
+	}
+	if got, _ := reloaded.GetJob(job.ID); got.Cursor != "1700000000.000100" {
+		t.Errorf("cursor = %q after reload", got.Cursor)
+	}
+}
+
+func TestRunJobCountsFailures(t *testing.T) {
+	s := NewScheduler(t.TempDir())
+	job, err := s.CreateJob("report", "every day at 9am", "writer", "report", "prod", "default")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	fail := true
+	s.SetJobRunner(func(ctx context.Context, job *Job) (string, error) {
+		if fail {
+			return "", errors.New("provider down")
+		}
+		return "done", nil
+	})
+	s.runJob(job)
+	fail = false
+	s.runJob(job)
+
+	if job.RunCount != 2 || job.FailCount != 1 {
+		t.Errorf("runs = %d, failures = %d; want 2 and 1", job.RunCount, job.FailCount)
+	}
+	if job.LastError != "" || job.LastResult != "done" {
+		t.Errorf("last run not recorded: %+v", job)
+	}
+}
+
+func TestEnableDisableClearPausedBy(t *testing.T) {
+	s := NewScheduler(t.TempDir())
+	job, err := s.CreateJob("digest", "every day at 9am", "writer", "digest", "prod", "default")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := s.UpdateJob(job.ID, func(j *Job) { j.Enabled = false; j.PausedBy = "budget" }); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.DisableJob(job.ID); err != nil {
+		t.Fatal(err)
+	}
+	if got, _ := s.GetJob(job.ID); got.PausedBy != "" {
+		t.Errorf("disabling by hand should clear PausedBy, got %q", got.PausedBy)
+	}
+
+	s.UpdateJob(job.ID, func(j *Job) { j.Enabled = false; j.PausedBy = "budget" })
+	if err := s.EnableJob(job.ID); err != nil {
+		t.Fatal(err)
+	}
+	if got, _ := s.GetJob(job.ID); !got.Enabled || got.PausedBy != "" {
+		t.Errorf("enabled job = %+v", got)
+	}
+}
+
+func TestDrainWaitsForRunningJobs(t *testing.T) {
+	s := NewScheduler(t.TempDir())
+	job, err := s.CreateJob("report", "every day at 9am", "writer", "report", "prod", "default")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	started, release := make(chan struct{}), make(chan struct{})
+	s.SetJobRunner(func(ctx context.Context, job *Job) (string, error) {
+		close(started)
+		select {
+		case <-release:
+			return "done", nil
+		case <-ctx.Done():
+			return "", ctx.Err()
+		}
+	})
+	_ = s.Start(context.Background())
+	if err := s.RunJobNow(job.ID); err != nil {
+		t.Fatal(err)
+	}
+	<-started
+
+	drained := make(chan error, 1)
+	go func() { drained <- s.Drain(context.Background()) }()
+	select {
+	case <-drained:
+		t.Fatal("Drain returned while the job was running")
+	case <-time.After(50 * time.Millisecond):
+	}
+	if err := s.RunJobNow(job.ID); err == nil {
+		t.Error("job started while draining")
+	}
+
+	close(release)
+	if err := <-drained; err != nil {
+		t.Fatal(err)
+	}
+	if job.LastResult != "done" {
+		t.Errorf("job did not finish: %+v", job)
+	}
+}
+
+func TestDrainCancelsJobsAfterDeadline(t *testing.T) {
+	s := NewScheduler(t.TempDir())
+	job, err := s.CreateJob("report", "every day at 9am", "writer", "report", "prod", "default")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cancelled := make(chan struct{})
+	s.SetJobRunner(func(ctx context.Context, job *Job) (string, error) {
+		<-ctx.Done()
+		close(cancelled)
+		return "", ctx.Err()
+	})
+	_ = s.Start(context.Background())
+	if err := s.RunJobNow(job.ID); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if err := s.Drain(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Drain = %v, want deadline exceeded", err)
+	}
+	select {
+	case <-cancelled:
+	case <-time.After(time.Second):
+		t.Fatal("job not cancelled after the deadline")
+	}
+}
+
+func TestTickGateReloadsSharedJobs(t *testing.T) {
+	dir := t.TempDir()
+	s := NewScheduler(dir)
+	ran := make(chan string, 1)
+	s.SetJobRunner(func(ctx context.Context, job *Job) (string, error) {
+		ran <- job.Name
+		return "done", nil
+	})
+	leader := false
+	s.SetTickGate(func(ctx context.Context) bool { return leader })
+
+	// Another process creates a due job in the shared directory
+	other := NewScheduler(dir)
+	job, err := other.CreateJob("report", "every day at 9am", "writer", "report", "prod", "default")
+	if err != nil {
+		t.Fatal(err)
+	}
+	past := time.Now().Add(-time.Minute)
+	job.NextRun = &past
+	if err := other.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	s.checkJobs()
+	select {
+	case name := <-ran:
+		t.Fatalf("ran %s without owning the tick", name)
+	case <-time.After(20 * time.Millisecond):
+	}
+
+	leader = true
+	s.checkJobs()
+	select {
+	case name := <-ran:
+		if name != "report" {
+			t.Errorf("ran %s, want report", name)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("expected the shared job to run once the tick is owned")
+	}
+}
+
+func TestSeenStore(t *testing.T) {
+	s := NewScheduler(t.TempDir())
+	job, err := s.CreateJob("channel-monitor", "every 5 minutes", "watcher", "check", "prod", "default")
+	if err != nil {
+		t.Fatal(err)
+	}
+	seen, err := s.Seen(job.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if seen.Has("1700000000.000100") {
+		t.Fatal("new store should be empty")
+	}
+	if err := seen.Add("1700000000.000100", "Paging on-call"); err != nil {
+		t.Fatal(err)
+	}
+
+	// A restarted job remembers the message
+	reopened, err := s.Seen(job.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reopened.Has("1700000000.000100") || reopened.Has("1700000000.000200") {
+		t.Error("reopened store lost or invented messages")
+	}
+
+	// A torn file doesn't stop the job
+	if err := os.WriteFile(s.seenPath(job.ID), []byte(`{"1700000000.000100": {"at"`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	torn, err := s.Seen(job.ID)
+	if err != nil {
+		t.Fatalf("expected a torn store to open empty, got %v", err)
+	}
+	if err := torn.Add("1700000000.000300", ""); err != nil || !torn.Has("1700000000.000300") {
+		t.Errorf("torn store: %v", err)
+	}
+
+	if err := s.DeleteJob(job.ID); err != nil {
+		t.Fatal(err)
+	}
+	if gone, _ := s.Seen(job.ID); gone.Has("1700000000.000100") {
+		t.Error("deleting the job should drop its store")
+	}
+}
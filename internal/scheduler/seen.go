@@ -0,0 +1,98 @@
+package scheduler
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+)
+
+// maxSeen bounds the inputs a SeenStore remembers; the oldest are dropped.
+const maxSeen = 5000
+
+// SeenStore remembers the inputs a job processed, e.g. Slack messages by
+// timestamp, with a hash of its result for each, so that restarts and
+// config changes don't make the job process them again.
+type SeenStore struct {
+	path string
+
+	mu      sync.Mutex
+	entries map[string]seenEntry
+}
+
+type seenEntry struct {
+	Hash string    `json:"hash,omitempty"` // of the result; empty when there was none
+	At   time.Time `json:"at"`
+}
+
+// Seen opens the store of the inputs a job processed. A store that can't
+// be parsed is treated as empty: the job may process a few inputs again,
+// but it keeps running.
+func (s *Scheduler) Seen(jobID string) (*SeenStore, error) {
+	st := &SeenStore{path: s.seenPath(jobID), entries: make(map[string]seenEntry)}
+	data, err := os.ReadFile(st.path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return st, nil
+		}
+		return nil, err
+	}
+	if err := json.Unmarshal(data, &st.entries); err != nil || st.entries == nil {
+		st.entries = make(map[string]seenEntry)
+	}
+	return st, nil
+}
+
+func (s *Scheduler) seenPath(jobID string) string {
+	return filepath.Join(s.dataDir, "seen", jobID+".json")
+}
+
+// Has reports whether the input was processed.
+func (st *SeenStore) Has(key string) bool {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	_, ok := st.entries[key]
+	return ok
+}
+
+// Add records an input as processed with its result, and saves the store.
+func (st *SeenStore) Add(key, result string) error {
+	entry := seenEntry{At: time.Now()}
+	if result != "" {
+		sum := sha256.Sum256([]byte(result))
+		entry.Hash = hex.EncodeToString(sum[:])
+	}
+
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	st.entries[key] = entry
+	if len(st.entries) > maxSeen {
+		keys := make([]string, 0, len(st.entries))
+		for k := range st.entries {
+			keys = append(keys, k)
+		}
+		sort.Slice(keys, func(i, j int) bool { return st.entries[keys[i]].At.Before(st.entries[keys[j]].At) })
+		for _, k := range keys[:len(keys)-maxSeen] {
+			delete(st.entries, k)
+		}
+	}
+
+	data, err := json.Marshal(st.entries)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(st.path), 0755); err != nil {
+		return err
+	}
+	// Write a new file and rename it over the old one, so that a crash
+	// mid-write doesn't leave a torn store
+	tmp := st.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, st.path)
+}
@@ -0,0 +1,277 @@
+package server
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/eachlabs/klaw/internal/cluster"
+	"github.com/eachlabs/klaw/internal/scheduler"
+	chatsession "github.com/eachlabs/klaw/internal/session"
+)
+
+// DispatchFunc runs an agent binding on a message and returns its response.
+type DispatchFunc func(ctx context.Context, agentName, message string) (string, error)
+
+// APIConfig configures the REST API served under /api/v1. Every request
+// must carry one of Keys as a bearer token; with no keys the API refuses
+// all requests.
+type APIConfig struct {
+	Keys      []string
+	Store     *cluster.Store
+	Scheduler *scheduler.Scheduler
+	Cluster   string
+	Namespace string
+	Dispatch  DispatchFunc
+}
+
+// apiLogChannel is the log channel of messages dispatched through the API.
+const apiLogChannel = "api"
+
+// EnableAPI serves the REST API for agents, jobs, sessions and logs of the
+// configured namespace next to the OpenAI gateway.
+func (s *Server) EnableAPI(cfg APIConfig) {
+	s.api = &cfg
+}
+
+func (s *Server) registerAPI(mux *http.ServeMux) {
+	api := http.NewServeMux()
+	api.HandleFunc("POST /api/v1/dispatch", s.handleDispatch)
+	api.HandleFunc("GET /api/v1/agents", s.handleListAgents)
+	api.HandleFunc("GET /api/v1/agents/{name}", s.handleGetAgent)
+	api.HandleFunc("GET /api/v1/jobs", s.handleListJobs)
+	api.HandleFunc("GET /api/v1/jobs/{id}", s.handleGetJob)
+	api.HandleFunc("POST /api/v1/jobs/{id}/run", s.handleRunJob)
+	api.HandleFunc("GET /api/v1/sessions", s.handleListSessions)
+	api.HandleFunc("GET /api/v1/sessions/{id}", s.handleGetSession)
+	api.HandleFunc("GET /api/v1/logs", s.handleLogs)
+	mux.Handle("/api/", s.apiAuth(api))
+}
+
+// apiAuth checks the bearer token of API requests. Unlike the gateway's
+// auth_required, it cannot be turned off.
+func (s *Server) apiAuth(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if len(s.api.Keys) == 0 {
+			writeAPIError(w, http.StatusUnauthorized, "authentication_error", "no_api_keys", "No API keys configured; set [server] api_keys or KLAW_API_KEY")
+			return
+		}
+		token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if !ok || token == "" {
+			writeAPIError(w, http.StatusUnauthorized, "authentication_error", "invalid_api_key", "Missing bearer token")
+			return
+		}
+		for _, key := range s.api.Keys {
+			if subtle.ConstantTimeCompare([]byte(key), []byte(token)) == 1 {
+				next.ServeHTTP(w, r)
+				return
+			}
+		}
+		writeAPIError(w, http.StatusUnauthorized, "authentication_error", "invalid_api_key", "Invalid API key")
+	})
+}
+
+type dispatchRequest struct {
+	Agent   string `json:"agent"`
+	Message string `json:"message"`
+}
+
+type dispatchResponse struct {
+	Agent    string `json:"agent"`
+	Response string `json:"response"`
+}
+
+func (s *Server) handleDispatch(w http.ResponseWriter, r *http.Request) {
+	var req dispatchRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeAPIError(w, http.StatusBadRequest, "invalid_request_error", "invalid_json", "Invalid request body: "+err.Error())
+		return
+	}
+	if req.Agent == "" || strings.TrimSpace(req.Message) == "" {
+		writeAPIError(w, http.StatusBadRequest, "invalid_request_error", "missing_field", "agent and message are required")
+		return
+	}
+	if !s.api.Store.AgentBindingExists(s.api.Cluster, s.api.Namespace, req.Agent) {
+		writeAPIError(w, http.StatusNotFound, "invalid_request_error", "agent_not_found", "Agent not found: "+req.Agent)
+		return
+	}
+
+	select {
+	case s.sem <- struct{}{}:
+		defer func() { <-s.sem }()
+	case <-r.Context().Done():
+		return
+	}
+
+	response, err := s.api.Dispatch(r.Context(), req.Agent, req.Message)
+	if err != nil {
+		writeAPIError(w, http.StatusInternalServerError, "server_error", "dispatch_failed", err.Error())
+		return
+	}
+	_ = s.api.Store.AppendMessageLog(s.api.Cluster, s.api.Namespace, apiLogChannel, &cluster.MessageLog{
+		ID:        strconv.FormatInt(time.Now().UnixNano(), 36),
+		Channel:   apiLogChannel,
+		User:      "api",
+		Agent:     req.Agent,
+		Content:   req.Message,
+		Response:  response,
+		RoutedVia: "manual",
+	})
+	writeJSON(w, http.StatusOK, dispatchResponse{Agent: req.Agent, Response: response})
+}
+
+// publicAgent hides the agent's skill settings, which hold credentials.
+func publicAgent(ab *cluster.AgentBinding) *cluster.AgentBinding {
+	public := *ab
+	public.SkillConfig = nil
+	return &public
+}
+
+func (s *Server) handleListAgents(w http.ResponseWriter, r *http.Request) {
+	agents, err := s.api.Store.ListAgentBindings(s.api.Cluster, s.api.Namespace)
+	if err != nil {
+		writeAPIError(w, http.StatusInternalServerError, "server_error", "store_error", err.Error())
+		return
+	}
+	public := make([]*cluster.AgentBinding, 0, len(agents))
+	for _, ab := range agents {
+		public = append(public, publicAgent(ab))
+	}
+	writeJSON(w, http.StatusOK, map[string]any{"agents": public})
+}
+
+func (s *Server) handleGetAgent(w http.ResponseWriter, r *http.Request) {
+	ab, err := s.api.Store.GetAgentBinding(s.api.Cluster, s.api.Namespace, r.PathValue("name"))
+	if err != nil {
+		writeAPIError(w, http.StatusNotFound, "invalid_request_error", "agent_not_found", err.Error())
+		return
+	}
+	writeJSON(w, http.StatusOK, publicAgent(ab))
+}
+
+func (s *Server) handleListJobs(w http.ResponseWriter, r *http.Request) {
+	jobs := s.api.Scheduler.ListJobs(s.api.Cluster, s.api.Namespace)
+	if jobs == nil {
+		jobs = []*scheduler.Job{}
+	}
+	sort.Slice(jobs, func(i, j int) bool { return jobs[i].Name < jobs[j].Name })
+	writeJSON(w, http.StatusOK, map[string]any{"jobs": jobs})
+}
+
+func (s *Server) handleGetJob(w http.ResponseWriter, r *http.Request) {
+	job, err := s.api.Scheduler.FindJob(s.api.Cluster, s.api.Namespace, r.PathValue("id"))
+	if err != nil {
+		writeAPIError(w, http.StatusNotFound, "invalid_request_error", "job_not_found", err.Error())
+		return
+	}
+	writeJSON(w, http.StatusOK, job)
+}
+
+// handleRunJob starts a job in the background; its result shows up in the
+// job's last_result or last_error.
+func (s *Server) handleRunJob(w http.ResponseWriter, r *http.Request) {
+	job, err := s.api.Scheduler.FindJob(s.api.Cluster, s.api.Namespace, r.PathValue("id"))
+	if err != nil {
+		writeAPIError(w, http.StatusNotFound, "invalid_request_error", "job_not_found", err.Error())
+		return
+	}
+	if err := s.api.Scheduler.RunJobNow(job.ID); err != nil {
+		writeAPIError(w, http.StatusInternalServerError, "server_error", "run_failed", err.Error())
+		return
+	}
+	writeJSON(w, http.StatusAccepted, map[string]string{"id": job.ID, "status": "running"})
+}
+
+// sessionSummary is a chat session without its messages.
+type sessionSummary struct {
+	ID        string    `json:"id"`
+	Name      string    `json:"name,omitempty"`
+	Agent     string    `json:"agent,omitempty"`
+	Model     string    `json:"model"`
+	Messages  int       `json:"messages"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+func (s *Server) handleListSessions(w http.ResponseWriter, r *http.Request) {
+	sessions, err := chatsession.NewManager().List()
+	if err != nil {
+		writeAPIError(w, http.StatusInternalServerError, "server_error", "session_error", err.Error())
+		return
+	}
+	summaries := make([]sessionSummary, 0, len(sessions))
+	for _, sess := range sessions {
+		summaries = append(summaries, sessionSummary{
+			ID:        sess.ID,
+			Name:      sess.Name,
+			Agent:     sess.Agent,
+			Model:     sess.Model,
+			Messages:  len(sess.Messages),
+			CreatedAt: sess.CreatedAt,
+			UpdatedAt: sess.UpdatedAt,
+		})
+	}
+	writeJSON(w, http.StatusOK, map[string]any{"sessions": summaries})
+}
+
+func (s *Server) handleGetSession(w http.ResponseWriter, r *http.Request) {
+	sess, err := chatsession.NewManager().Load(r.PathValue("id"))
+	if err != nil {
+		writeAPIError(w, http.StatusNotFound, "invalid_request_error", "session_not_found", err.Error())
+		return
+	}
+	writeJSON(w, http.StatusOK, sess)
+}
+
+// handleLogs returns the most recent messages of a channel, or of all the
+// namespace's channels and the API when no channel is given, oldest first.
+func (s *Server) handleLogs(w http.ResponseWriter, r *http.Request) {
+	limit := 100
+	if raw := r.URL.Query().Get("limit"); raw != "" {
+		n, err := strconv.Atoi(raw)
+		if err != nil || n <= 0 {
+			writeAPIError(w, http.StatusBadRequest, "invalid_request_error", "invalid_limit", "limit must be a positive number")
+			return
+		}
+		limit = n
+	}
+
+	channels := []string{r.URL.Query().Get("channel")}
+	if channels[0] == "" {
+		bindings, err := s.api.Store.ListChannelBindings(s.api.Cluster, s.api.Namespace)
+		if err != nil {
+			writeAPIError(w, http.StatusInternalServerError, "server_error", "store_error", err.Error())
+			return
+		}
+		channels = []string{apiLogChannel}
+		for _, cb := range bindings {
+			channels = append(channels, cb.Name)
+		}
+	}
+
+	logs := []*cluster.MessageLog{}
+	for _, ch := range channels {
+		chLogs, err := s.api.Store.GetMessageLogs(s.api.Cluster, s.api.Namespace, ch, limit)
+		if err != nil {
+			writeAPIError(w, http.StatusInternalServerError, "server_error", "store_error", err.Error())
+			return
+		}
+		logs = append(logs, chLogs...)
+	}
+	sort.SliceStable(logs, func(i, j int) bool { return logs[i].Timestamp.Before(logs[j].Timestamp) })
+	if len(logs) > limit {
+		logs = logs[len(logs)-limit:]
+	}
+	writeJSON(w, http.StatusOK, map[string]any{"logs": logs})
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
@@ -0,0 +1,54 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestAPIAuth(t *testing.T) {
+	ok := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+
+	tests := []struct {
+		name   string
+		keys   []string
+		header string
+		status int
+		code   string
+	}{
+		{"no keys configured", nil, "Bearer secret", http.StatusUnauthorized, "no_api_keys"},
+		{"missing key", []string{"secret"}, "", http.StatusUnauthorized, "invalid_api_key"},
+		{"empty bearer", []string{"secret"}, "Bearer ", http.StatusUnauthorized, "invalid_api_key"},
+		{"wrong key", []string{"secret"}, "Bearer secreT", http.StatusUnauthorized, "invalid_api_key"},
+		{"key prefix", []string{"secret"}, "Bearer sec", http.StatusUnauthorized, "invalid_api_key"},
+		{"right key", []string{"other", "secret"}, "Bearer secret", http.StatusOK, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &Server{api: &APIConfig{Keys: tt.keys}}
+			req := httptest.NewRequest(http.MethodGet, "/api/v1/agents", nil)
+			if tt.header != "" {
+				req.Header.Set("Authorization", tt.header)
+			}
+			rec := httptest.NewRecorder()
+			s.apiAuth(ok).ServeHTTP(rec, req)
+
+			if rec.Code != tt.status {
+				t.Fatalf("status = %d, want %d", rec.Code, tt.status)
+			}
+			if tt.code == "" {
+				return
+			}
+			var body apiError
+			if err := json.NewDecoder(rec.Body).Decode(&body); err != nil {
+				t.Fatalf("decode error body: %v", err)
+			}
+			if body.Error.Code != tt.code {
+				t.Errorf("code = %q, want %q", body.Error.Code, tt.code)
+			}
+		})
+	}
+}
@@ -175,6 +175,10 @@ func (s *Server) handleChatCompletions(w http.ResponseWriter, r *http.Request) {
 	tools := s.tools
 	if sess != nil {
 		tools = tool.DefaultRegistry(sess.filesDir())
+		// Keep the server's skill tool, which knows the skill scopes
+		if t, ok := s.tools.Get("skill"); ok {
+			tools.Register(t)
+		}
 	}
 
 	if req.Stream {
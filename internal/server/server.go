@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"net"
 	"net/http"
+	"strings"
 	"time"
 
 	"github.com/eachlabs/klaw/internal/memory"
@@ -48,6 +49,7 @@ type Server struct {
 	skillLoader  *skill.SkillLoader
 	sem          chan struct{}
 	sessions     *sessionPool
+	api          *APIConfig // REST API; nil when not enabled
 }
 
 // New creates a new gateway server.
@@ -103,6 +105,9 @@ func (s *Server) Start(ctx context.Context) error {
 		w.WriteHeader(http.StatusOK)
 		_, _ = w.Write([]byte(`{"status":"ok"}`))
 	})
+	if s.api != nil {
+		s.registerAPI(mux)
+	}
 
 	handler := s.corsMiddleware(s.authMiddleware(mux))
 
@@ -156,7 +161,8 @@ func (s *Server) corsMiddleware(next http.Handler) http.Handler {
 
 func (s *Server) authMiddleware(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		if !s.cfg.AuthRequired {
+		// The REST API checks its own keys
+		if !s.cfg.AuthRequired || (s.api != nil && strings.HasPrefix(r.URL.Path, "/api/")) {
 			next.ServeHTTP(w, r)
 			return
 		}
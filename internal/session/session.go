@@ -1,4 +1,5 @@
-// Package session provides conversation persistence for klaw chat.
+// Package session provides conversation persistence for klaw chat and
+// klaw dispatch --session.
 package session
 
 import (
@@ -120,6 +121,27 @@ func (m *Manager) Load(id string) (*Session, error) {
 	return m.session, nil
 }
 
+// Find loads the session with the given ID or, failing that, name. Of
+// several sessions with the name, the most recently updated one is loaded.
+func (m *Manager) Find(idOrName string) (*Session, error) {
+	if sess, err := m.Load(idOrName); err == nil {
+		return sess, nil
+	}
+	sessions, err := m.List()
+	if err != nil {
+		return nil, err
+	}
+	for _, sess := range sessions {
+		if sess.Name == idOrName {
+			m.mu.Lock()
+			m.session = sess
+			m.mu.Unlock()
+			return sess, nil
+		}
+	}
+	return nil, fmt.Errorf("session not found: %s", idOrName)
+}
+
 // Session returns the current session.
 func (m *Manager) Session() *Session {
 	m.mu.Lock()
@@ -201,6 +201,27 @@ func TestManager_Load_NotFound(t *testing.T) {
 	}
 }
 
+func TestManager_Find(t *testing.T) {
+	m := newTestManager(t)
+	sess := m.New("", "", "coder", "", "")
+	m.SetName("release-notes")
+	_ = m.ForceSave()
+
+	for _, key := range []string{sess.ID, "release-notes"} {
+		found, err := (&Manager{dir: m.dir}).Find(key)
+		if err != nil {
+			t.Fatalf("Find(%q): %v", key, err)
+		}
+		if found.ID != sess.ID || found.Agent != "coder" {
+			t.Errorf("Find(%q) = %+v", key, found)
+		}
+	}
+
+	if _, err := m.Find("other"); err == nil || !strings.Contains(err.Error(), "not found") {
+		t.Errorf("expected not found, got %v", err)
+	}
+}
+
 func TestManager_List(t *testing.T) {
 	m := newTestManager(t)
 
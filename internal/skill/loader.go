@@ -8,6 +8,7 @@ import (
 	"net/http"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
 	"time"
 )
@@ -15,26 +16,37 @@ import (
 // SkillLoader loads skills from the skills directory.
 // Each skill is a folder containing a SKILL.md file.
 type SkillLoader struct {
-	skillsDir string
+	skillsDir string   // where skills are installed
+	dirs      []string // where skills are looked up, in order
 }
 
 // NewSkillLoader creates a new skill loader.
 func NewSkillLoader(skillsDir string) *SkillLoader {
 	return &SkillLoader{
 		skillsDir: skillsDir,
+		dirs:      []string{skillsDir},
+	}
+}
+
+// NewScopedSkillLoader creates a skill loader looking skills up in several
+// directories, the first one having a skill winning, e.g. the ScopeDirs of
+// a namespace. Skills missing from all of them are installed in the last.
+func NewScopedSkillLoader(dirs ...string) *SkillLoader {
+	return &SkillLoader{
+		skillsDir: dirs[len(dirs)-1],
+		dirs:      dirs,
 	}
 }
 
 // LoadSkill loads a skill by name. Returns the SKILL.md content.
 // If skill doesn't exist locally, tries to install it.
 func (l *SkillLoader) LoadSkill(name string) (string, error) {
-	skillPath := filepath.Join(l.skillsDir, name, "SKILL.md")
-
 	// Check if skill exists locally
-	content, err := os.ReadFile(skillPath)
+	content, err := os.ReadFile(l.SkillPath(name))
 	if err == nil {
 		return string(content), nil
 	}
+	skillPath := filepath.Join(l.skillsDir, name, "SKILL.md")
 
 	// Skill not found locally - try to install
 	if err := l.InstallSkill(name); err != nil {
@@ -94,7 +106,31 @@ func (l *SkillLoader) InstallSkill(name string) error {
 
 // ListSkills returns all installed skills.
 func (l *SkillLoader) ListSkills() ([]string, error) {
-	entries, err := os.ReadDir(l.skillsDir)
+	if len(l.dirs) == 1 {
+		return ListDir(l.skillsDir)
+	}
+
+	seen := make(map[string]bool)
+	skills := []string{}
+	for _, dir := range l.dirs {
+		names, err := ListDir(dir)
+		if err != nil {
+			return nil, err
+		}
+		for _, name := range names {
+			if !seen[name] {
+				seen[name] = true
+				skills = append(skills, name)
+			}
+		}
+	}
+	sort.Strings(skills)
+	return skills, nil
+}
+
+// ListDir returns the skills installed in a skills directory.
+func ListDir(dir string) ([]string, error) {
+	entries, err := os.ReadDir(dir)
 	if err != nil {
 		if os.IsNotExist(err) {
 			return []string{}, nil
@@ -109,7 +145,7 @@ func (l *SkillLoader) ListSkills() ([]string, error) {
 		}
 
 		// Check if SKILL.md exists
-		skillPath := filepath.Join(l.skillsDir, entry.Name(), "SKILL.md")
+		skillPath := filepath.Join(dir, entry.Name(), "SKILL.md")
 		if _, err := os.Stat(skillPath); err == nil {
 			skills = append(skills, entry.Name())
 		}
@@ -118,6 +154,30 @@ func (l *SkillLoader) ListSkills() ([]string, error) {
 	return skills, nil
 }
 
+// SkillPath returns the path of an installed skill's SKILL.md, in the
+// first directory having it; the path in the install directory otherwise.
+func (l *SkillLoader) SkillPath(name string) string {
+	for _, dir := range l.dirs {
+		path := filepath.Join(dir, name, "SKILL.md")
+		if _, err := os.Stat(path); err == nil {
+			return path
+		}
+	}
+	return filepath.Join(l.skillsDir, name, "SKILL.md")
+}
+
+// UninstallSkill removes an installed skill and all its files.
+func (l *SkillLoader) UninstallSkill(name string) error {
+	if name == "" || name == "." || strings.ContainsAny(name, `/\`) || strings.Contains(name, "..") {
+		return fmt.Errorf("invalid skill name: %q", name)
+	}
+	skillDir := filepath.Join(l.skillsDir, name)
+	if _, err := os.Stat(skillDir); os.IsNotExist(err) {
+		return fmt.Errorf("skill '%s' not found", name)
+	}
+	return os.RemoveAll(skillDir)
+}
+
 // GetSkillPrompt returns the system prompt addition for a skill.
 func (l *SkillLoader) GetSkillPrompt(name string) (string, error) {
 	content, err := l.LoadSkill(name)
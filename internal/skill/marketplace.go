@@ -2,10 +2,15 @@
 package skill
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
 	"sort"
 	"strings"
 	"time"
@@ -13,7 +18,8 @@ import (
 
 // MarketplaceConfig holds marketplace configuration
 type MarketplaceConfig struct {
-	BaseURL string // Default: https://skills.sh
+	BaseURL  string // Default: https://skills.sh
+	CacheDir string // ETag response cache; empty disables caching
 }
 
 // Marketplace provides access to the skills.sh registry
@@ -54,6 +60,7 @@ type SearchResult struct {
 	Page       int                `json:"page"`
 	PerPage    int                `json:"per_page"`
 	Categories []string           `json:"categories"`
+	Offline    bool               `json:"-"` // served from the built-in catalog
 }
 
 // NewMarketplace creates a new marketplace client
@@ -69,9 +76,142 @@ func NewMarketplace(config MarketplaceConfig) *Marketplace {
 
 // GetCategories returns all skill categories
 func (m *Marketplace) GetCategories() ([]MarketplaceCategory, error) {
-	// For now, return hardcoded categories until skills.sh API is available
-	// This will be replaced with actual API call
-	categories := []MarketplaceCategory{
+	var categories []MarketplaceCategory
+	if err := m.getJSON("/api/categories", nil, &categories); err != nil {
+		return builtinCategories(), nil
+	}
+	return categories, nil
+}
+
+// GetFeatured returns featured skills
+func (m *Marketplace) GetFeatured() ([]MarketplaceSkill, error) {
+	var skills []MarketplaceSkill
+	if err := m.getJSON("/api/skills/featured", nil, &skills); err != nil {
+		return builtinFeatured(), nil
+	}
+	return skills, nil
+}
+
+// Search searches for skills. It queries the live API and falls back to
+// the built-in catalog when the registry is unreachable.
+func (m *Marketplace) Search(query string, category string) (*SearchResult, error) {
+	params := url.Values{}
+	if query != "" {
+		params.Set("q", query)
+	}
+	if category != "" {
+		params.Set("category", category)
+	}
+
+	var result SearchResult
+	if err := m.getJSON("/api/skills", params, &result); err != nil {
+		return searchCatalog(builtinCatalog(), query, category), nil
+	}
+	if result.Total == 0 {
+		result.Total = len(result.Skills)
+	}
+	return &result, nil
+}
+
+// cachedResponse is an API response stored with its ETag.
+type cachedResponse struct {
+	ETag      string          `json:"etag"`
+	Body      json.RawMessage `json:"body"`
+	FetchedAt time.Time       `json:"fetched_at"`
+}
+
+// getJSON fetches an API path into out. Responses are cached by ETag;
+// when the request fails, a previously cached body is used instead.
+func (m *Marketplace) getJSON(path string, params url.Values, out any) error {
+	reqURL := strings.TrimRight(m.config.BaseURL, "/") + path
+	if len(params) > 0 {
+		reqURL += "?" + params.Encode()
+	}
+
+	cached := m.readCache(reqURL)
+
+	req, err := http.NewRequest(http.MethodGet, reqURL, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+	if cached != nil && cached.ETag != "" {
+		req.Header.Set("If-None-Match", cached.ETag)
+	}
+
+	resp, err := m.client.Do(req)
+	if err != nil {
+		if cached != nil {
+			return json.Unmarshal(cached.Body, out)
+		}
+		return err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	switch {
+	case resp.StatusCode == http.StatusNotModified && cached != nil:
+		return json.Unmarshal(cached.Body, out)
+	case resp.StatusCode != http.StatusOK:
+		if cached != nil {
+			return json.Unmarshal(cached.Body, out)
+		}
+		return fmt.Errorf("marketplace returned %s", resp.Status)
+	}
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 5*1024*1024))
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(body, out); err != nil {
+		return fmt.Errorf("invalid marketplace response: %w", err)
+	}
+
+	m.writeCache(reqURL, &cachedResponse{
+		ETag:      resp.Header.Get("ETag"),
+		Body:      body,
+		FetchedAt: time.Now(),
+	})
+	return nil
+}
+
+func (m *Marketplace) cacheFile(reqURL string) string {
+	sum := sha256.Sum256([]byte(reqURL))
+	return filepath.Join(m.config.CacheDir, hex.EncodeToString(sum[:8])+".json")
+}
+
+func (m *Marketplace) readCache(reqURL string) *cachedResponse {
+	if m.config.CacheDir == "" {
+		return nil
+	}
+	data, err := os.ReadFile(m.cacheFile(reqURL))
+	if err != nil {
+		return nil
+	}
+	var cached cachedResponse
+	if err := json.Unmarshal(data, &cached); err != nil {
+		return nil
+	}
+	return &cached
+}
+
+func (m *Marketplace) writeCache(reqURL string, cached *cachedResponse) {
+	if m.config.CacheDir == "" {
+		return
+	}
+	if err := os.MkdirAll(m.config.CacheDir, 0755); err != nil {
+		return
+	}
+	data, err := json.Marshal(cached)
+	if err != nil {
+		return
+	}
+	_ = os.WriteFile(m.cacheFile(reqURL), data, 0644)
+}
+
+// --- Built-in catalog (offline fallback) ---
+
+func builtinCategories() []MarketplaceCategory {
+	return []MarketplaceCategory{
 		{Name: "Web Browsing", Slug: "browser", Description: "Browse websites, take screenshots, interact with pages", Icon: "🌐", Count: 5},
 		{Name: "Web Search", Slug: "search", Description: "Search the web and retrieve information", Icon: "🔍", Count: 8},
 		{Name: "Code Execution", Slug: "code", Description: "Execute code in various languages", Icon: "💻", Count: 12},
@@ -85,13 +225,10 @@ func (m *Marketplace) GetCategories() ([]MarketplaceCategory, error) {
 		{Name: "Files & Storage", Slug: "storage", Description: "File management and cloud storage", Icon: "📁", Count: 6},
 		{Name: "Scraping", Slug: "scraping", Description: "Web scraping and data extraction", Icon: "🕷️", Count: 4},
 	}
-	return categories, nil
 }
 
-// GetFeatured returns featured skills
-func (m *Marketplace) GetFeatured() ([]MarketplaceSkill, error) {
-	// Featured skills - will be replaced with API call
-	skills := []MarketplaceSkill{
+func builtinFeatured() []MarketplaceSkill {
+	return []MarketplaceSkill{
 		{
 			Name:        "agent-browser",
 			Org:         "vercel-labs",
@@ -177,16 +314,10 @@ func (m *Marketplace) GetFeatured() ([]MarketplaceSkill, error) {
 			Featured:    true,
 		},
 	}
-	return skills, nil
 }
 
-// Search searches for skills
-func (m *Marketplace) Search(query string, category string) (*SearchResult, error) {
-	// Get all skills (featured + more)
-	featured, _ := m.GetFeatured()
-
-	// Additional skills for search
-	allSkills := append(featured, []MarketplaceSkill{
+func builtinCatalog() []MarketplaceSkill {
+	return append(builtinFeatured(), []MarketplaceSkill{
 		{Name: "puppeteer", Org: "mcp-servers", Version: "1.0.0", Description: "Browser automation with Puppeteer", Tags: []string{"browser"}, Categories: []string{"browser"}, URL: "https://skills.sh/mcp-servers/puppeteer"},
 		{Name: "playwright", Org: "mcp-servers", Version: "1.0.0", Description: "Browser automation with Playwright", Tags: []string{"browser"}, Categories: []string{"browser"}, URL: "https://skills.sh/mcp-servers/playwright"},
 		{Name: "tavily", Org: "tavily", Version: "1.0.0", Description: "AI-powered web search", Tags: []string{"search"}, Categories: []string{"search"}, URL: "https://skills.sh/tavily/tavily"},
@@ -205,7 +336,10 @@ func (m *Marketplace) Search(query string, category string) (*SearchResult, erro
 		{Name: "discord", Org: "mcp-servers", Version: "1.0.0", Description: "Discord bot integration", Tags: []string{"discord"}, Categories: []string{"communication"}, URL: "https://skills.sh/mcp-servers/discord"},
 		{Name: "twitter", Org: "mcp-servers", Version: "1.0.0", Description: "Twitter/X API integration", Tags: []string{"twitter", "social"}, Categories: []string{"communication"}, URL: "https://skills.sh/mcp-servers/twitter"},
 	}...)
+}
 
+// searchCatalog filters a local skill list by query and category.
+func searchCatalog(allSkills []MarketplaceSkill, query, category string) *SearchResult {
 	// Filter by query
 	var filtered []MarketplaceSkill
 	queryLower := strings.ToLower(query)
@@ -264,51 +398,24 @@ func (m *Marketplace) Search(query string, category string) (*SearchResult, erro
 		Total:   len(filtered),
 		Page:    1,
 		PerPage: 20,
-	}, nil
+		Offline: true,
+	}
 }
 
 // GetSkill gets details for a specific skill
 func (m *Marketplace) GetSkill(org, name string) (*MarketplaceSkill, error) {
-	url := fmt.Sprintf("%s/api/skills/%s/%s", m.config.BaseURL, org, name)
-
-	resp, err := m.client.Get(url)
-	if err != nil {
-		// Fallback to search
-		result, err := m.Search(name, "")
-		if err != nil {
-			return nil, err
-		}
-		for _, skill := range result.Skills {
-			if skill.Name == name && skill.Org == org {
-				return &skill, nil
-			}
-		}
-		return nil, fmt.Errorf("skill not found: %s/%s", org, name)
+	var skill MarketplaceSkill
+	if err := m.getJSON(fmt.Sprintf("/api/skills/%s/%s", org, name), nil, &skill); err == nil {
+		return &skill, nil
 	}
-	defer func() { _ = resp.Body.Close() }()
 
-	if resp.StatusCode != http.StatusOK {
-		// Fallback
-		result, _ := m.Search(name, "")
-		for _, skill := range result.Skills {
-			if skill.Name == name {
-				return &skill, nil
-			}
+	// Fallback to the built-in catalog
+	for _, skill := range builtinCatalog() {
+		if skill.Name == name && skill.Org == org {
+			return &skill, nil
 		}
-		return nil, fmt.Errorf("skill not found")
-	}
-
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, err
 	}
-
-	var skill MarketplaceSkill
-	if err := json.Unmarshal(body, &skill); err != nil {
-		return nil, err
-	}
-
-	return &skill, nil
+	return nil, fmt.Errorf("skill not found: %s/%s", org, name)
 }
 
 // FormatSkillCard formats a skill for display
@@ -2,6 +2,7 @@
 package skill
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -11,6 +12,8 @@ import (
 	"path/filepath"
 	"strings"
 	"time"
+
+	"github.com/eachlabs/klaw/internal/tool"
 )
 
 // RemoteManifest represents a skill manifest from skills.sh
@@ -264,7 +267,7 @@ func runInstall(cfg *InstallConfig, workDir string) error {
 	// Shell commands
 	for _, cmdStr := range cfg.Commands {
 		fmt.Printf("  %s\n", cmdStr)
-		cmd := exec.Command("sh", "-c", cmdStr)
+		cmd := tool.ShellCommand(context.Background(), cmdStr)
 		cmd.Dir = workDir
 		cmd.Stdout = os.Stdout
 		cmd.Stderr = os.Stderr
@@ -0,0 +1,51 @@
+package skill
+
+import (
+	"fmt"
+	"path/filepath"
+)
+
+// Scopes skills are installed in. A namespace's agents see the skills of
+// the namespace, then those of its cluster, then the global ones, so a
+// team can try a skill out without it reaching the agents of other teams.
+const (
+	ScopeNamespace = "namespace"
+	ScopeCluster   = "cluster"
+	ScopeGlobal    = "global"
+)
+
+// Scopes lists the scopes in resolution order.
+var Scopes = []string{ScopeNamespace, ScopeCluster, ScopeGlobal}
+
+// ScopeDir returns the directory of the skills of a scope under the klaw
+// config directory: namespaces/<cluster>/<namespace>/skills,
+// clusters/<cluster>/skills or skills.
+func ScopeDir(configDir, scope, cluster, namespace string) (string, error) {
+	switch scope {
+	case ScopeNamespace:
+		if cluster == "" || namespace == "" {
+			return "", fmt.Errorf("namespace scope requires a current namespace")
+		}
+		return filepath.Join(configDir, "namespaces", cluster, namespace, "skills"), nil
+	case ScopeCluster:
+		if cluster == "" {
+			return "", fmt.Errorf("cluster scope requires a current cluster")
+		}
+		return filepath.Join(configDir, "clusters", cluster, "skills"), nil
+	case "", ScopeGlobal:
+		return filepath.Join(configDir, "skills"), nil
+	}
+	return "", fmt.Errorf("invalid skill scope %q (use namespace, cluster or global)", scope)
+}
+
+// ScopeDirs returns the skill directories of a namespace in resolution
+// order. Without a cluster or namespace only the broader scopes are left.
+func ScopeDirs(configDir, cluster, namespace string) []string {
+	var dirs []string
+	for _, scope := range Scopes {
+		if dir, err := ScopeDir(configDir, scope, cluster, namespace); err == nil {
+			dirs = append(dirs, dir)
+		}
+	}
+	return dirs
+}
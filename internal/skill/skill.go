@@ -2,7 +2,6 @@
 package skill
 
 import (
-	"context"
 	"encoding/json"
 	"fmt"
 	"os"
@@ -53,7 +52,7 @@ func (r *Registry) loadBuiltins() {
 			Name:        "web-search",
 			Version:     "1.0.0",
 			Description: "Search the web using multiple search engines",
-			Tools:       []string{"web_search"},
+			Tools:       []string{"web_search", "web_fetch"},
 			SystemPrompt: `You have web search capabilities. When users ask questions that require current information or facts you don't know, use the web_search tool to find answers. Always cite your sources.`,
 			Source:      "builtin",
 		},
@@ -85,7 +84,7 @@ Always be careful with code execution and validate inputs.`,
 			Name:        "git",
 			Version:     "1.0.0",
 			Description: "Git operations - clone, commit, push, pull, branch management",
-			Tools:       []string{"git_clone", "git_status", "git_commit", "git_push", "git_pull", "git_branch"},
+			Tools:       []string{"git_clone", "git_status", "git_diff", "git_commit", "git_push", "git_pull", "git_branch"},
 			SystemPrompt: `You have Git capabilities. You can:
 - Clone repositories
 - Check status and diff
@@ -95,6 +94,32 @@ Always be careful with code execution and validate inputs.`,
 Always confirm before destructive operations like force push.`,
 			Source: "builtin",
 		},
+		{
+			Name:        "github",
+			Version:     "1.0.0",
+			Description: "GitHub issues, pull requests, reviews and search",
+			Tools:       []string{"gh_issue_create", "gh_issue_comment", "gh_pr_create", "gh_pr_review", "gh_repo_search"},
+			SystemPrompt: `You can work with GitHub. Use this for:
+- Opening issues and responding to them with comments
+- Opening pull requests from branches you pushed with git_push
+- Reviewing pull requests (approve, request changes, comment)
+- Searching repositories, code and issues
+Link to the created issue or pull request in your reply.`,
+			Source: "builtin",
+		},
+		{
+			Name:        "jira",
+			Version:     "1.0.0",
+			Description: "Jira issue search, creation, comments and triage",
+			Tools:       []string{"jira_search", "jira_create", "jira_comment", "jira_transition"},
+			SystemPrompt: `You can work with Jira. Use this for:
+- Searching issues with JQL before opening duplicates
+- Creating issues with a clear summary, description and labels
+- Commenting on issues
+- Triaging: adding labels, assigning and moving issues between statuses
+Mention the issue key in your reply.`,
+			Source: "builtin",
+		},
 		{
 			Name:        "docker",
 			Version:     "1.0.0",
@@ -107,6 +132,18 @@ Always confirm before destructive operations like force push.`,
 - Managing container lifecycle`,
 			Source: "builtin",
 		},
+		{
+			Name:        "kubernetes",
+			Version:     "1.0.0",
+			Description: "Investigate Kubernetes clusters with kubectl",
+			Tools:       []string{"kubectl"},
+			SystemPrompt: `You can inspect a Kubernetes cluster with the kubectl tool. Use this for:
+- Listing and describing pods, deployments, services and other resources
+- Reading pod logs and recent events
+- Applying manifests (always show the diff and get confirmation first)
+Start broad (get pods, events) and narrow down before drawing conclusions.`,
+			Source: "builtin",
+		},
 		{
 			Name:        "api",
 			Version:     "1.0.0",
@@ -128,9 +165,23 @@ Always handle API keys and secrets securely.`,
 - Running SELECT queries to fetch data
 - Executing INSERT, UPDATE, DELETE operations
 - Analyzing data with SQL
+Use sql_query for reads and sql_execute for changes (disabled unless the agent's
+database config sets read_only=false).
 Always be careful with data modifications and confirm before DELETE/UPDATE.`,
 			Source: "builtin",
 		},
+		{
+			Name:        "eachlabs-image-generation",
+			Version:     "1.0.0",
+			Description: "Generate images using EachLabs AI models",
+			Tools:       []string{"image_generate"},
+			SystemPrompt: `You can generate images with the image_generate tool. Use this for:
+- Illustrations, mockups, social media visuals and concept art
+- Variations of an idea (num_images up to 4)
+Write detailed prompts covering subject, style, lighting and composition.
+Generated images are saved to the workspace and shared in the conversation.`,
+			Source: "builtin",
+		},
 		{
 			Name:        "slack",
 			Version:     "1.0.0",
@@ -266,6 +317,7 @@ func (r *Registry) Uninstall(name string) error {
 func (r *Registry) GetToolsForSkills(skillNames []string, workDir string) (*tool.Registry, string) {
 	tools := tool.NewRegistry()
 	var prompts []string
+	var browser *tool.BrowserSession // shared by all browser_* tools
 
 	for _, name := range skillNames {
 		skill, ok := r.skills[name]
@@ -294,13 +346,55 @@ func (r *Registry) GetToolsForSkills(skillNames []string, workDir string) (*tool
 			case "grep":
 				tools.Register(tool.NewGrep(workDir))
 
-			// Web search - stub for now
 			case "web_search":
-				tools.Register(&webSearchTool{})
-
-			// HTTP tools - stub for now
-			case "http_get", "http_post", "http_request":
-				tools.Register(&httpTool{name: toolName})
+				tools.Register(tool.NewWebSearch())
+			case "web_fetch":
+				tools.Register(tool.NewWebFetch())
+
+			case "http_get":
+				tools.Register(tool.NewHTTPGet())
+			case "http_post":
+				tools.Register(tool.NewHTTPPost())
+			case "http_request":
+				tools.Register(tool.NewHTTPRequest())
+
+			case "git_clone", "git_status", "git_diff", "git_commit", "git_push", "git_pull", "git_branch":
+				tools.Register(tool.NewGit(toolName, workDir))
+
+			case "gh_issue_create", "gh_issue_comment", "gh_pr_create", "gh_pr_review", "gh_repo_search":
+				tools.Register(tool.NewGitHub(toolName))
+
+			case "jira_search", "jira_create", "jira_comment", "jira_transition":
+				tools.Register(tool.NewJira(toolName))
+
+			case "kubectl":
+				tools.Register(tool.NewKubectl(workDir))
+
+			case "email_send":
+				tools.Register(tool.NewEmailSend(workDir))
+
+			case "image_generate":
+				tools.Register(tool.NewImageGenerate())
+
+			case "sql_query":
+				tools.Register(tool.NewSQLQuery())
+			case "sql_execute":
+				tools.Register(tool.NewSQLExecute())
+
+			case "browser_open", "browser_click", "browser_type", "browser_screenshot":
+				if browser == nil {
+					browser = tool.NewBrowserSession()
+				}
+				switch toolName {
+				case "browser_open":
+					tools.Register(tool.NewBrowserOpen(browser))
+				case "browser_click":
+					tools.Register(tool.NewBrowserClick(browser))
+				case "browser_type":
+					tools.Register(tool.NewBrowserType(browser))
+				case "browser_screenshot":
+					tools.Register(tool.NewBrowserScreenshot(browser))
+				}
 
 			// Other tools would be registered here
 			// For now, they're stubs that will be implemented later
@@ -315,65 +409,3 @@ func (r *Registry) GetToolsForSkills(skillNames []string, workDir string) (*tool
 
 	return tools, systemPrompt
 }
-
-// Stub tool implementations
-
-type webSearchTool struct{}
-
-func (t *webSearchTool) Name() string        { return "web_search" }
-func (t *webSearchTool) Description() string { return "Search the web for information" }
-func (t *webSearchTool) Schema() json.RawMessage {
-	return json.RawMessage(`{
-		"type": "object",
-		"properties": {
-			"query": {
-				"type": "string",
-				"description": "The search query"
-			}
-		},
-		"required": ["query"]
-	}`)
-}
-func (t *webSearchTool) Execute(ctx context.Context, params json.RawMessage) (*tool.Result, error) {
-	var p struct {
-		Query string `json:"query"`
-	}
-	_ = json.Unmarshal(params, &p)
-	// TODO: Implement actual web search
-	return &tool.Result{
-		Content: fmt.Sprintf("Web search for '%s' - feature coming soon", p.Query),
-	}, nil
-}
-
-type httpTool struct {
-	name string
-}
-
-func (t *httpTool) Name() string        { return t.name }
-func (t *httpTool) Description() string { return "Make HTTP requests" }
-func (t *httpTool) Schema() json.RawMessage {
-	return json.RawMessage(`{
-		"type": "object",
-		"properties": {
-			"url": {
-				"type": "string",
-				"description": "The URL to request"
-			},
-			"method": {
-				"type": "string",
-				"description": "HTTP method (GET, POST, etc.)",
-				"default": "GET"
-			}
-		},
-		"required": ["url"]
-	}`)
-}
-func (t *httpTool) Execute(ctx context.Context, params json.RawMessage) (*tool.Result, error) {
-	var p struct {
-		URL string `json:"url"`
-	}
-	_ = json.Unmarshal(params, &p)
-	return &tool.Result{
-		Content: fmt.Sprintf("HTTP request to '%s' - feature coming soon", p.URL),
-	}, nil
-}
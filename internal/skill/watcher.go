@@ -0,0 +1,111 @@
+package skill
+
+import (
+	"context"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+)
+
+// Watcher polls the skills directories and reports SKILL.md changes.
+type Watcher struct {
+	dirs     []string
+	interval time.Duration
+	onChange func(changed []string)
+	state    map[string]skillFile
+}
+
+// skillFile is a SKILL.md seen by a scan.
+type skillFile struct {
+	name string
+	mod  time.Time
+}
+
+// NewWatcher creates a watcher that calls onChange with the names of
+// added, modified, or removed skills.
+func NewWatcher(skillsDir string, interval time.Duration, onChange func(changed []string)) *Watcher {
+	return NewScopedWatcher([]string{skillsDir}, interval, onChange)
+}
+
+// NewScopedWatcher creates a watcher over several skills directories, e.g.
+// the ScopeDirs of a namespace.
+func NewScopedWatcher(dirs []string, interval time.Duration, onChange func(changed []string)) *Watcher {
+	if interval <= 0 {
+		interval = 2 * time.Second
+	}
+	return &Watcher{
+		dirs:     dirs,
+		interval: interval,
+		onChange: onChange,
+	}
+}
+
+// Run watches until ctx is cancelled.
+func (w *Watcher) Run(ctx context.Context) {
+	w.state = w.scan()
+
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if changed := w.poll(); len(changed) > 0 {
+				w.onChange(changed)
+			}
+		}
+	}
+}
+
+// poll rescans the directory and returns the skills that changed.
+func (w *Watcher) poll() []string {
+	current := w.scan()
+
+	names := make(map[string]bool)
+	for path, f := range current {
+		if prev, ok := w.state[path]; !ok || !prev.mod.Equal(f.mod) {
+			names[f.name] = true
+		}
+	}
+	for path, f := range w.state {
+		if _, ok := current[path]; !ok {
+			names[f.name] = true
+		}
+	}
+	w.state = current
+
+	var changed []string
+	for name := range names {
+		changed = append(changed, name)
+	}
+	sort.Strings(changed)
+	return changed
+}
+
+// scan maps SKILL.md paths to their skill name (path relative to its
+// skills directory) and mtime.
+func (w *Watcher) scan() map[string]skillFile {
+	result := make(map[string]skillFile)
+	for _, dir := range w.dirs {
+		_ = filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+			if err != nil || d.IsDir() || d.Name() != "SKILL.md" {
+				return nil
+			}
+			info, err := os.Stat(path)
+			if err != nil {
+				return nil
+			}
+			rel, err := filepath.Rel(dir, filepath.Dir(path))
+			if err != nil {
+				return nil
+			}
+			result[path] = skillFile{name: filepath.ToSlash(rel), mod: info.ModTime()}
+			return nil
+		})
+	}
+	return result
+}
@@ -124,20 +124,7 @@ func (t *AgentTool) Execute(ctx context.Context, params json.RawMessage) (*Resul
 	isUpdate := existingAgent != nil
 
 	// Build system prompt
-	var systemPrompt strings.Builder
-	_, _ = fmt.Fprintf(&systemPrompt, "You are %s, an AI agent.\n\n", p.Name)
-	_, _ = fmt.Fprintf(&systemPrompt, "## Your Role\n%s\n\n", p.Description)
-	systemPrompt.WriteString("## Guidelines\n")
-	systemPrompt.WriteString("- Be concise and direct in your responses\n")
-	systemPrompt.WriteString("- Take action when needed, don't just describe what you could do\n")
-	systemPrompt.WriteString("- If you need more information, ask specific questions\n")
-	if len(p.Skills) > 0 {
-		_, _ = fmt.Fprintf(&systemPrompt, "- You have access to these skills: %s\n", strings.Join(p.Skills, ", "))
-	}
-	systemPrompt.WriteString("\n## Response Format\n")
-	systemPrompt.WriteString("- Keep responses short (1-3 sentences when possible)\n")
-	systemPrompt.WriteString("- Use bullet points for lists\n")
-	systemPrompt.WriteString("- Include specific data/numbers when available\n")
+	systemPrompt := defaultAgentPrompt(p.Name, p.Description, p.Skills)
 
 	// Create or update the agent binding
 	binding := &cluster.AgentBinding{
@@ -145,7 +132,7 @@ func (t *AgentTool) Execute(ctx context.Context, params json.RawMessage) (*Resul
 		Cluster:      clusterName,
 		Namespace:    namespace,
 		Description:  p.Description,
-		SystemPrompt: systemPrompt.String(),
+		SystemPrompt: systemPrompt,
 		Model:        p.Model,
 		Tools:        tools,
 		Skills:       p.Skills,
@@ -185,3 +172,22 @@ func (t *AgentTool) Execute(ctx context.Context, params json.RawMessage) (*Resul
 
 	return &Result{Content: sb.String()}, nil
 }
+
+// defaultAgentPrompt builds the system prompt given to agents created by the AI.
+func defaultAgentPrompt(name, description string, skills []string) string {
+	var systemPrompt strings.Builder
+	_, _ = fmt.Fprintf(&systemPrompt, "You are %s, an AI agent.\n\n", name)
+	_, _ = fmt.Fprintf(&systemPrompt, "## Your Role\n%s\n\n", description)
+	systemPrompt.WriteString("## Guidelines\n")
+	systemPrompt.WriteString("- Be concise and direct in your responses\n")
+	systemPrompt.WriteString("- Take action when needed, don't just describe what you could do\n")
+	systemPrompt.WriteString("- If you need more information, ask specific questions\n")
+	if len(skills) > 0 {
+		_, _ = fmt.Fprintf(&systemPrompt, "- You have access to these skills: %s\n", strings.Join(skills, ", "))
+	}
+	systemPrompt.WriteString("\n## Response Format\n")
+	systemPrompt.WriteString("- Keep responses short (1-3 sentences when possible)\n")
+	systemPrompt.WriteString("- Use bullet points for lists\n")
+	systemPrompt.WriteString("- Include specific data/numbers when available\n")
+	return systemPrompt.String()
+}
@@ -0,0 +1,214 @@
+package tool
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/eachlabs/klaw/internal/cluster"
+	"github.com/eachlabs/klaw/internal/config"
+)
+
+// BootstrapFunc generates a system prompt for an agent.
+type BootstrapFunc func(ctx context.Context, ab *cluster.AgentBinding) (string, error)
+
+// AgentUpdateTool allows the AI to modify existing agents
+type AgentUpdateTool struct {
+	store     *cluster.Store
+	ctxMgr    *cluster.ContextManager
+	bootstrap BootstrapFunc
+}
+
+// NewAgentUpdateTool creates a new agent update tool. bootstrap regenerates
+// system prompts on request; when nil the default prompt is used.
+func NewAgentUpdateTool(bootstrap BootstrapFunc) *AgentUpdateTool {
+	return &AgentUpdateTool{
+		store:     cluster.NewStore(config.StateDir()),
+		ctxMgr:    cluster.NewContextManager(config.ConfigDir()),
+		bootstrap: bootstrap,
+	}
+}
+
+func (t *AgentUpdateTool) Name() string {
+	return "agent_update"
+}
+
+func (t *AgentUpdateTool) Description() string {
+	return `Update an existing agent. Only the fields given are changed; everything else is kept.
+
+Use this when the user wants to change an agent instead of creating a new one, e.g.
+"add web-search to the researcher agent" -> name: "researcher", add_skills: ["web-search"]
+
+Set regenerate_prompt=true to rebuild the agent's system prompt from its new
+description and skills (do this when its role changes significantly).`
+}
+
+func (t *AgentUpdateTool) Schema() json.RawMessage {
+	return json.RawMessage(`{
+		"type": "object",
+		"properties": {
+			"name": {
+				"type": "string",
+				"description": "Name of the agent to update (see agent_list)"
+			},
+			"description": {
+				"type": "string",
+				"description": "New description"
+			},
+			"model": {
+				"type": "string",
+				"description": "New model"
+			},
+			"skills": {
+				"type": "array",
+				"items": {"type": "string"},
+				"description": "Replace all skills with this list"
+			},
+			"add_skills": {
+				"type": "array",
+				"items": {"type": "string"},
+				"description": "Skills to add"
+			},
+			"remove_skills": {
+				"type": "array",
+				"items": {"type": "string"},
+				"description": "Skills to remove"
+			},
+			"triggers": {
+				"type": "array",
+				"items": {"type": "string"},
+				"description": "Replace all triggers with this list"
+			},
+			"add_triggers": {
+				"type": "array",
+				"items": {"type": "string"},
+				"description": "Triggers to add"
+			},
+			"remove_triggers": {
+				"type": "array",
+				"items": {"type": "string"},
+				"description": "Triggers to remove"
+			},
+			"regenerate_prompt": {
+				"type": "boolean",
+				"description": "Regenerate the system prompt from the updated agent (default: false)"
+			}
+		},
+		"required": ["name"]
+	}`)
+}
+
+type agentUpdateParams struct {
+	Name             string   `json:"name"`
+	Description      string   `json:"description"`
+	Model            string   `json:"model"`
+	Skills           []string `json:"skills"`
+	AddSkills        []string `json:"add_skills"`
+	RemoveSkills     []string `json:"remove_skills"`
+	Triggers         []string `json:"triggers"`
+	AddTriggers      []string `json:"add_triggers"`
+	RemoveTriggers   []string `json:"remove_triggers"`
+	RegeneratePrompt bool     `json:"regenerate_prompt"`
+}
+
+func (t *AgentUpdateTool) Execute(ctx context.Context, params json.RawMessage) (*Result, error) {
+	var p agentUpdateParams
+	if err := json.Unmarshal(params, &p); err != nil {
+		return &Result{Content: fmt.Sprintf("Invalid parameters: %v", err), IsError: true}, nil
+	}
+	if p.Name == "" {
+		return &Result{Content: "Agent name is required", IsError: true}, nil
+	}
+
+	clusterName, namespace, err := t.ctxMgr.RequireCurrent()
+	if err != nil {
+		clusterName = "default"
+		namespace = "default"
+	}
+
+	ab, err := t.store.GetAgentBinding(clusterName, namespace, p.Name)
+	if err != nil {
+		return &Result{
+			Content: fmt.Sprintf("Agent not found: %s. Use agent_list to see agents or agent_spawn to create one.", p.Name),
+			IsError: true,
+		}, nil
+	}
+
+	var changes []string
+	if p.Description != "" && p.Description != ab.Description {
+		ab.Description = p.Description
+		changes = append(changes, "description")
+	}
+	if p.Model != "" && p.Model != ab.Model {
+		ab.Model = p.Model
+		changes = append(changes, "model: "+p.Model)
+	}
+	if skills := editList(ab.Skills, p.Skills, p.AddSkills, p.RemoveSkills); !slices.Equal(skills, ab.Skills) {
+		ab.Skills = skills
+		changes = append(changes, "skills: "+joinOrNone(skills))
+	}
+	if triggers := editList(ab.Triggers, p.Triggers, p.AddTriggers, p.RemoveTriggers); !slices.Equal(triggers, ab.Triggers) {
+		ab.Triggers = triggers
+		changes = append(changes, "triggers: "+joinOrNone(triggers))
+	}
+
+	if p.RegeneratePrompt {
+		prompt := defaultAgentPrompt(ab.Name, ab.Description, ab.Skills)
+		if t.bootstrap != nil {
+			generated, err := t.bootstrap(ctx, ab)
+			if err != nil {
+				return &Result{Content: fmt.Sprintf("Failed to regenerate system prompt: %v", err), IsError: true}, nil
+			}
+			prompt = generated
+		}
+		ab.SystemPrompt = prompt
+		changes = append(changes, "system prompt regenerated")
+	}
+
+	if len(changes) == 0 {
+		return &Result{Content: fmt.Sprintf("Nothing to change for agent '%s'.", ab.Name)}, nil
+	}
+
+	if err := t.store.UpdateAgentBinding(ab); err != nil {
+		return &Result{Content: fmt.Sprintf("Failed to update agent: %v", err), IsError: true}, nil
+	}
+
+	var sb strings.Builder
+	_, _ = fmt.Fprintf(&sb, "Agent '%s' updated:\n", ab.Name)
+	for _, c := range changes {
+		_, _ = fmt.Fprintf(&sb, "- %s\n", c)
+	}
+	return &Result{Content: sb.String()}, nil
+}
+
+// editList replaces list with set when given, then applies additions and
+// removals, keeping order and dropping duplicates.
+func editList(list, set, add, remove []string) []string {
+	if set != nil {
+		list = set
+	}
+	removed := make(map[string]bool, len(remove))
+	for _, v := range remove {
+		removed[v] = true
+	}
+	seen := make(map[string]bool)
+	var result []string
+	for _, v := range append(append([]string{}, list...), add...) {
+		v = strings.TrimSpace(v)
+		if v == "" || removed[v] || seen[v] {
+			continue
+		}
+		seen[v] = true
+		result = append(result, v)
+	}
+	return result
+}
+
+func joinOrNone(list []string) string {
+	if len(list) == 0 {
+		return "(none)"
+	}
+	return strings.Join(list, ", ")
+}
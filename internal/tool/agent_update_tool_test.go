@@ -0,0 +1,63 @@
+package tool
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/eachlabs/klaw/internal/cluster"
+)
+
+func TestEditList(t *testing.T) {
+	got := editList([]string{"git", "browser"}, nil, []string{"web-search", "git"}, []string{"browser"})
+	if want := []string{"git", "web-search"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("editList = %v, want %v", got, want)
+	}
+	got = editList([]string{"git"}, []string{"docker"}, nil, nil)
+	if want := []string{"docker"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("editList with set = %v, want %v", got, want)
+	}
+}
+
+func TestAgentUpdateTool(t *testing.T) {
+	store := cluster.NewStore(t.TempDir())
+	if err := store.CreateCluster(&cluster.Cluster{Name: "default"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.CreateAgentBinding(&cluster.AgentBinding{
+		Name: "researcher", Cluster: "default", Namespace: "default",
+		Description: "Researches topics", SystemPrompt: "old", Skills: []string{"browser"},
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	tl := &AgentUpdateTool{
+		store:  store,
+		ctxMgr: cluster.NewContextManager(t.TempDir()),
+		bootstrap: func(ctx context.Context, ab *cluster.AgentBinding) (string, error) {
+			return "prompt for " + strings.Join(ab.Skills, ","), nil
+		},
+	}
+	res, err := tl.Execute(context.Background(), json.RawMessage(
+		`{"name":"researcher","add_skills":["web-search"],"regenerate_prompt":true}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.IsError {
+		t.Fatalf("unexpected error: %s", res.Content)
+	}
+
+	ab, err := store.GetAgentBinding("default", "default", "researcher")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ab.Description != "Researches topics" || ab.SystemPrompt != "prompt for browser,web-search" {
+		t.Errorf("unexpected agent after update: %+v", ab)
+	}
+
+	if res, _ := tl.Execute(context.Background(), json.RawMessage(`{"name":"missing"}`)); !res.IsError {
+		t.Error("expected error for unknown agent")
+	}
+}
@@ -0,0 +1,358 @@
+package tool
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	backgroundDefaultTimeout = 30 * time.Minute
+	backgroundMaxTimeout     = 4 * time.Hour
+	backgroundMaxOutput      = 1 << 20 // bytes of output kept per task
+	backgroundMaxFinished    = 50      // finished tasks kept for status queries
+)
+
+// BackgroundTasks tracks shell commands and agent runs started with
+// background_run.
+type BackgroundTasks struct {
+	workDir string
+	run     AgentRunFunc // nil disables agent tasks
+
+	mu    sync.Mutex
+	tasks map[string]*backgroundTask
+}
+
+type backgroundTask struct {
+	id          string
+	description string
+	startedAt   time.Time
+	cancel      context.CancelFunc
+
+	mu      sync.Mutex
+	status  string // running, completed, failed, cancelled, timed out
+	endedAt time.Time
+	output  []byte
+	dropped int
+	errMsg  string
+}
+
+// Write appends to the task output, keeping only the most recent bytes.
+func (t *backgroundTask) Write(p []byte) (int, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.output = append(t.output, p...)
+	if over := len(t.output) - backgroundMaxOutput; over > 0 {
+		t.output = t.output[over:]
+		t.dropped += over
+	}
+	return len(p), nil
+}
+
+func (t *backgroundTask) finish(ctx context.Context, err error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.endedAt = time.Now()
+	switch {
+	case err == nil:
+		t.status = "completed"
+	case ctx.Err() == context.Canceled:
+		t.status = "cancelled"
+	case ctx.Err() == context.DeadlineExceeded:
+		t.status = "timed out"
+	default:
+		t.status = "failed"
+		t.errMsg = err.Error()
+	}
+}
+
+// NewBackgroundTasks creates a task manager. Shell commands run in workDir;
+// run executes agent tasks and may be nil.
+func NewBackgroundTasks(workDir string, run AgentRunFunc) *BackgroundTasks {
+	return &BackgroundTasks{workDir: workDir, run: run, tasks: make(map[string]*backgroundTask)}
+}
+
+// Tools returns background_run, background_status and background_cancel.
+func (b *BackgroundTasks) Tools() []Tool {
+	return []Tool{
+		&Background{name: "background_run", tasks: b},
+		&Background{name: "background_status", tasks: b},
+		&Background{name: "background_cancel", tasks: b},
+	}
+}
+
+// start registers a task and runs fn in a goroutine. The task outlives the
+// tool call but keeps ctx values such as the skill config.
+func (b *BackgroundTasks) start(ctx context.Context, description string, timeout time.Duration, fn func(ctx context.Context, task *backgroundTask) error) *backgroundTask {
+	runCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), timeout)
+	task := &backgroundTask{
+		id:          uuid.New().String()[:8],
+		description: description,
+		startedAt:   time.Now(),
+		cancel:      cancel,
+		status:      "running",
+	}
+
+	b.mu.Lock()
+	b.pruneLocked()
+	b.tasks[task.id] = task
+	b.mu.Unlock()
+
+	go func() {
+		defer cancel()
+		task.finish(runCtx, fn(runCtx, task))
+	}()
+	return task
+}
+
+// pruneLocked drops the oldest finished tasks beyond backgroundMaxFinished.
+func (b *BackgroundTasks) pruneLocked() {
+	var finished []*backgroundTask
+	for _, t := range b.tasks {
+		t.mu.Lock()
+		if t.status != "running" {
+			finished = append(finished, t)
+		}
+		t.mu.Unlock()
+	}
+	if len(finished) <= backgroundMaxFinished {
+		return
+	}
+	sort.Slice(finished, func(i, j int) bool { return finished[i].startedAt.Before(finished[j].startedAt) })
+	for _, t := range finished[:len(finished)-backgroundMaxFinished] {
+		delete(b.tasks, t.id)
+	}
+}
+
+func (b *BackgroundTasks) get(id string) (*backgroundTask, bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	t, ok := b.tasks[id]
+	return t, ok
+}
+
+// Background is one of the background_* tools.
+type Background struct {
+	name  string
+	tasks *BackgroundTasks
+}
+
+func (t *Background) Name() string {
+	return t.name
+}
+
+func (t *Background) Description() string {
+	switch t.name {
+	case "background_run":
+		return `Start a long-running shell command (builds, test suites, data jobs) or a named agent's task
+in the background and return a task ID immediately. Use this instead of bash for anything that
+may take more than a couple of minutes, then poll with background_status.`
+	case "background_status":
+		return `Show the status and latest output of a background task, or list all tasks when no ID is given.`
+	default:
+		return `Cancel a running background task.`
+	}
+}
+
+func (t *Background) Schema() json.RawMessage {
+	switch t.name {
+	case "background_run":
+		return json.RawMessage(`{
+			"type": "object",
+			"properties": {
+				"command": {
+					"type": "string",
+					"description": "Shell command to run (runs with bash in the working directory)"
+				},
+				"agent": {
+					"type": "string",
+					"description": "Instead of a command: name of the agent to run the task"
+				},
+				"task": {
+					"type": "string",
+					"description": "Task for the agent (with agent)"
+				},
+				"timeout": {
+					"type": "integer",
+					"description": "Timeout in minutes (default: 30, max: 240)"
+				}
+			}
+		}`)
+	case "background_status":
+		return json.RawMessage(`{
+			"type": "object",
+			"properties": {
+				"id": {
+					"type": "string",
+					"description": "Task ID (omit to list all tasks)"
+				},
+				"tail": {
+					"type": "integer",
+					"description": "Number of output lines to return from the end (default: 50, 0 for all)"
+				}
+			}
+		}`)
+	default:
+		return json.RawMessage(`{
+			"type": "object",
+			"properties": {
+				"id": {
+					"type": "string",
+					"description": "Task ID"
+				}
+			},
+			"required": ["id"]
+		}`)
+	}
+}
+
+type backgroundParams struct {
+	Command string `json:"command"`
+	Agent   string `json:"agent"`
+	Task    string `json:"task"`
+	Timeout int    `json:"timeout"`
+	ID      string `json:"id"`
+	Tail    *int   `json:"tail"`
+}
+
+func (t *Background) Execute(ctx context.Context, params json.RawMessage) (*Result, error) {
+	var p backgroundParams
+	if err := json.Unmarshal(params, &p); err != nil {
+		return &Result{Content: fmt.Sprintf("invalid params: %v", err), IsError: true}, nil
+	}
+
+	switch t.name {
+	case "background_run":
+		return t.runTask(ctx, p), nil
+	case "background_status":
+		if p.ID == "" {
+			return t.list(), nil
+		}
+		return t.status(p), nil
+	default:
+		task, ok := t.tasks.get(p.ID)
+		if !ok {
+			return &Result{Content: fmt.Sprintf("task not found: %s", p.ID), IsError: true}, nil
+		}
+		task.cancel()
+		return &Result{Content: fmt.Sprintf("cancelling task %s", p.ID)}, nil
+	}
+}
+
+func (t *Background) runTask(ctx context.Context, p backgroundParams) *Result {
+	timeout := backgroundDefaultTimeout
+	if p.Timeout > 0 {
+		timeout = min(time.Duration(p.Timeout)*time.Minute, backgroundMaxTimeout)
+	}
+
+	var task *backgroundTask
+	switch {
+	case p.Command != "" && p.Agent != "":
+		return &Result{Content: "give either command or agent, not both", IsError: true}
+	case p.Command != "":
+		workDir := t.tasks.workDir
+		task = t.tasks.start(ctx, p.Command, timeout, func(ctx context.Context, task *backgroundTask) error {
+			cmd := ShellCommand(ctx, p.Command)
+			cmd.Dir = workDir
+			cmd.Env = append(os.Environ(), skillConfigEnv(ctx)...)
+			cmd.Stdout = task
+			cmd.Stderr = task
+			cmd.WaitDelay = 5 * time.Second // don't wait on children holding the output pipe
+			return cmd.Run()
+		})
+	case p.Agent != "":
+		if p.Task == "" {
+			return &Result{Content: "task is required with agent", IsError: true}
+		}
+		if t.tasks.run == nil {
+			return &Result{Content: "background agent runs are not available here", IsError: true}
+		}
+		run := t.tasks.run
+		task = t.tasks.start(ctx, "agent "+p.Agent+": "+truncateString(p.Task, 80), timeout, func(ctx context.Context, task *backgroundTask) error {
+			result, err := run(ctx, p.Agent, p.Task)
+			_, _ = task.Write([]byte(result))
+			return err
+		})
+	default:
+		return &Result{Content: "command or agent is required", IsError: true}
+	}
+
+	return &Result{Content: fmt.Sprintf("Started background task %s (timeout %s). Check it with background_status id=%s.", task.id, timeout, task.id)}
+}
+
+func (t *Background) list() *Result {
+	t.tasks.mu.Lock()
+	tasks := make([]*backgroundTask, 0, len(t.tasks.tasks))
+	for _, task := range t.tasks.tasks {
+		tasks = append(tasks, task)
+	}
+	t.tasks.mu.Unlock()
+
+	if len(tasks) == 0 {
+		return &Result{Content: "No background tasks."}
+	}
+	sort.Slice(tasks, func(i, j int) bool { return tasks[i].startedAt.After(tasks[j].startedAt) })
+
+	var sb strings.Builder
+	for _, task := range tasks {
+		task.mu.Lock()
+		_, _ = fmt.Fprintf(&sb, "%s  %-9s  %s  %s\n", task.id, task.status, task.duration().Round(time.Second), truncateString(task.description, 80))
+		task.mu.Unlock()
+	}
+	return &Result{Content: sb.String()}
+}
+
+func (t *Background) status(p backgroundParams) *Result {
+	task, ok := t.tasks.get(p.ID)
+	if !ok {
+		return &Result{Content: fmt.Sprintf("task not found: %s", p.ID), IsError: true}
+	}
+
+	tail := 50
+	if p.Tail != nil {
+		tail = *p.Tail
+	}
+
+	task.mu.Lock()
+	defer task.mu.Unlock()
+
+	var sb strings.Builder
+	_, _ = fmt.Fprintf(&sb, "Task %s: %s (%s)\n", task.id, task.status, task.duration().Round(time.Second))
+	_, _ = fmt.Fprintf(&sb, "%s\n", task.description)
+	if task.errMsg != "" {
+		_, _ = fmt.Fprintf(&sb, "Error: %s\n", task.errMsg)
+	}
+
+	output := strings.TrimRight(string(task.output), "\n")
+	if tail > 0 {
+		if lines := strings.Split(output, "\n"); len(lines) > tail {
+			output = fmt.Sprintf("... (%d earlier lines)\n", len(lines)-tail) + strings.Join(lines[len(lines)-tail:], "\n")
+		}
+	} else if task.dropped > 0 {
+		output = fmt.Sprintf("... (%d earlier bytes dropped)\n", task.dropped) + output
+	}
+	if len(output) > 30000 {
+		output = "... (output truncated)\n" + output[len(output)-30000:]
+	}
+	if output == "" {
+		output = "(no output yet)"
+	}
+	_, _ = fmt.Fprintf(&sb, "\n%s", output)
+
+	return &Result{Content: sb.String(), IsError: task.status == "failed"}
+}
+
+// duration is how long the task ran, or has been running. Caller holds mu.
+func (t *backgroundTask) duration() time.Duration {
+	if t.endedAt.IsZero() {
+		return time.Since(t.startedAt)
+	}
+	return t.endedAt.Sub(t.startedAt)
+}
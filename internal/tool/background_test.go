@@ -0,0 +1,66 @@
+package tool
+
+import (
+	"context"
+	"encoding/json"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestBackgroundTasks(t *testing.T) {
+	bg := NewBackgroundTasks(t.TempDir(), func(ctx context.Context, agentName, task string) (string, error) {
+		return agentName + " did " + task, nil
+	})
+	tools := NewRegistry()
+	for _, tl := range bg.Tools() {
+		tools.Register(tl)
+	}
+	call := func(name, params string) *Result {
+		t.Helper()
+		tl, _ := tools.Get(name)
+		res, err := tl.Execute(context.Background(), json.RawMessage(params))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return res
+	}
+	start := func(params string) string {
+		t.Helper()
+		res := call("background_run", params)
+		id := regexp.MustCompile(`task (\w+)`).FindStringSubmatch(res.Content)
+		if res.IsError || id == nil {
+			t.Fatalf("background_run failed: %s", res.Content)
+		}
+		return id[1]
+	}
+	waitFor := func(id, status string) *Result {
+		t.Helper()
+		for deadline := time.Now().Add(5 * time.Second); time.Now().Before(deadline); time.Sleep(20 * time.Millisecond) {
+			if res := call("background_status", `{"id":"`+id+`"}`); strings.Contains(res.Content, ": "+status+" ") {
+				return res
+			}
+		}
+		t.Fatalf("task %s did not reach %q", id, status)
+		return nil
+	}
+
+	cmdID := start(`{"command":"echo hello; echo done"}`)
+	if res := waitFor(cmdID, "completed"); !strings.Contains(res.Content, "hello\ndone") {
+		t.Errorf("unexpected output: %s", res.Content)
+	}
+
+	agentID := start(`{"agent":"builder","task":"the build"}`)
+	if res := waitFor(agentID, "completed"); !strings.Contains(res.Content, "builder did the build") {
+		t.Errorf("unexpected agent output: %s", res.Content)
+	}
+
+	sleepID := start(`{"command":"sleep 30"}`)
+	call("background_cancel", `{"id":"`+sleepID+`"}`)
+	waitFor(sleepID, "cancelled")
+
+	if res := call("background_status", `{}`); strings.Count(res.Content, "\n") != 3 {
+		t.Errorf("expected 3 tasks listed, got:\n%s", res.Content)
+	}
+}
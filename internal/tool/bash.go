@@ -6,7 +6,6 @@ import (
 	"encoding/json"
 	"fmt"
 	"os"
-	"os/exec"
 	"strings"
 	"time"
 )
@@ -26,9 +25,18 @@ func (b *Bash) Name() string {
 }
 
 func (b *Bash) Description() string {
-	return `Execute a bash command. Use for running shell commands, git operations, package management, etc.
+	desc := `Execute a bash command. Use for running shell commands, git operations, package management, etc.
 The command runs in the current working directory.
 Returns stdout/stderr combined. Exit code 0 = success.`
+	if sh := Shell(); !isPosixShell(sh) {
+		desc += "\nCommands run with " + sh + ", not bash: use its syntax."
+	}
+	return desc
+}
+
+// isPosixShell reports whether shell takes bash syntax.
+func isPosixShell(shell string) bool {
+	return ShellArgs(shell, "")[0] == "-c"
 }
 
 func (b *Bash) Schema() json.RawMessage {
@@ -71,9 +79,9 @@ func (b *Bash) Execute(ctx context.Context, params json.RawMessage) (*Result, er
 	ctx, cancel := context.WithTimeout(ctx, time.Duration(timeout)*time.Second)
 	defer cancel()
 
-	cmd := exec.CommandContext(ctx, "bash", "-c", p.Command)
+	cmd := ShellCommand(ctx, p.Command)
 	cmd.Dir = b.workDir
-	cmd.Env = os.Environ()
+	cmd.Env = append(os.Environ(), skillConfigEnv(ctx)...)
 
 	var stdout, stderr bytes.Buffer
 	cmd.Stdout = &stdout
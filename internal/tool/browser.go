@@ -0,0 +1,471 @@
+package tool
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/chromedp/cdproto/cdp"
+	"github.com/chromedp/cdproto/fetch"
+	"github.com/chromedp/cdproto/network"
+	"github.com/chromedp/chromedp"
+)
+
+// BrowserSession is a lazily started headless Chrome shared by the browser
+// tools, so that they act on the same page.
+type BrowserSession struct {
+	mu          sync.Mutex
+	ctx         context.Context
+	cancel      context.CancelFunc
+	allocCancel context.CancelFunc
+	timeout     time.Duration
+
+	// Allowed domains of the agent policy of the running action, which
+	// every page and frame the browser navigates to is checked against
+	policyMu sync.Mutex
+	domains  []string
+}
+
+// browserSessions are the sessions CloseBrowsers shuts down.
+var browserSessions = struct {
+	mu       sync.Mutex
+	sessions []*BrowserSession
+}{}
+
+// NewBrowserSession creates a browser session. Chrome is launched on first use;
+// set KLAW_CHROME_PATH to use a specific browser binary.
+func NewBrowserSession() *BrowserSession {
+	b := &BrowserSession{timeout: 30 * time.Second}
+	browserSessions.mu.Lock()
+	browserSessions.sessions = append(browserSessions.sessions, b)
+	browserSessions.mu.Unlock()
+	return b
+}
+
+// CloseBrowsers shuts down the browsers of all sessions, e.g. on exit.
+func CloseBrowsers() {
+	browserSessions.mu.Lock()
+	sessions := browserSessions.sessions
+	browserSessions.sessions = nil
+	browserSessions.mu.Unlock()
+	for _, b := range sessions {
+		b.Close()
+	}
+}
+
+// browserContext returns the context of the browser's tab, starting Chrome
+// if needed. Actions run on contexts derived from it share the tab; only
+// cancelling it closes the browser.
+func (b *BrowserSession) browserContext() (context.Context, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.ctx != nil && b.ctx.Err() == nil {
+		return b.ctx, nil
+	}
+
+	opts := append(chromedp.DefaultExecAllocatorOptions[:],
+		chromedp.WindowSize(1280, 900),
+	)
+	if path := os.Getenv("KLAW_CHROME_PATH"); path != "" {
+		opts = append(opts, chromedp.ExecPath(path))
+	}
+
+	allocCtx, allocCancel := chromedp.NewExecAllocator(context.Background(), opts...)
+	ctx, cancel := chromedp.NewContext(allocCtx)
+	chromedp.ListenTarget(ctx, func(ev any) {
+		if paused, ok := ev.(*fetch.EventRequestPaused); ok {
+			go b.checkNavigation(ctx, paused)
+		}
+	})
+	// Start the browser on the long-lived context: the first Run on a
+	// context owns the browser, which closes when that context is done.
+	// Document requests are paused for checkNavigation, so redirects and
+	// links are held to the agent policy too
+	started := make(chan error, 1)
+	go func() {
+		started <- chromedp.Run(ctx, fetch.Enable().WithPatterns([]*fetch.RequestPattern{
+			{ResourceType: network.ResourceTypeDocument},
+		}))
+	}()
+	select {
+	case err := <-started:
+		if err != nil {
+			cancel()
+			allocCancel()
+			return nil, fmt.Errorf("failed to start browser: %w", err)
+		}
+	case <-time.After(b.timeout):
+		cancel()
+		allocCancel()
+		return nil, fmt.Errorf("browser did not start within %s", b.timeout)
+	}
+	b.ctx, b.cancel, b.allocCancel = ctx, cancel, allocCancel
+	return ctx, nil
+}
+
+// checkNavigation lets a paused document request through if the agent
+// policy allows its host, and fails it otherwise.
+func (b *BrowserSession) checkNavigation(ctx context.Context, ev *fetch.EventRequestPaused) {
+	b.policyMu.Lock()
+	domains := b.domains
+	b.policyMu.Unlock()
+
+	exec := cdp.WithExecutor(ctx, chromedp.FromContext(ctx).Target)
+	if len(domains) > 0 {
+		u, err := url.Parse(ev.Request.URL)
+		if err != nil || domainReason(domains, u.Hostname()) != "" {
+			_ = fetch.FailRequest(ev.RequestID, network.ErrorReasonBlockedByClient).Do(exec)
+			return
+		}
+	}
+	_ = fetch.ContinueRequest(ev.RequestID).Do(exec)
+}
+
+// run executes chromedp actions, bounded by both ctx and the session timeout.
+func (b *BrowserSession) run(ctx context.Context, actions ...chromedp.Action) error {
+	bctx, err := b.browserContext()
+	if err != nil {
+		return err
+	}
+	b.policyMu.Lock()
+	b.domains = allowedDomains(ctx)
+	b.policyMu.Unlock()
+
+	tctx, cancel := context.WithTimeout(bctx, b.timeout)
+	defer cancel()
+	stop := context.AfterFunc(ctx, cancel)
+	defer stop()
+
+	return chromedp.Run(tctx, actions...)
+}
+
+// Close shuts down the browser.
+func (b *BrowserSession) Close() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.cancel != nil {
+		b.cancel()
+		b.allocCancel()
+		b.cancel, b.allocCancel, b.ctx = nil, nil, nil
+	}
+}
+
+// pageSummary returns the current URL, title and readable text of the page.
+func (b *BrowserSession) pageSummary(ctx context.Context) (string, error) {
+	var location, title, page string
+	if err := b.run(ctx,
+		chromedp.Location(&location),
+		chromedp.Title(&title),
+		chromedp.OuterHTML("html", &page, chromedp.ByQuery),
+	); err != nil {
+		return "", err
+	}
+
+	_, text := extractReadable(page)
+	if len(text) > 20000 {
+		text = text[:20000] + "\n\n[Content truncated - too long]"
+	}
+	return fmt.Sprintf("URL: %s\nTitle: %s\n\n%s", location, title, text), nil
+}
+
+// --- browser_open ---
+
+// BrowserOpen navigates the browser to a URL.
+type BrowserOpen struct {
+	session *BrowserSession
+}
+
+// NewBrowserOpen creates the browser_open tool.
+func NewBrowserOpen(session *BrowserSession) *BrowserOpen {
+	return &BrowserOpen{session: session}
+}
+
+func (t *BrowserOpen) Name() string {
+	return "browser_open"
+}
+
+func (t *BrowserOpen) Description() string {
+	return `Open a URL in a headless browser (JavaScript is executed).
+Returns the page title and readable text. Use browser_click, browser_type and
+browser_screenshot to interact with the page afterwards.`
+}
+
+func (t *BrowserOpen) Schema() json.RawMessage {
+	return json.RawMessage(`{
+		"type": "object",
+		"properties": {
+			"url": {
+				"type": "string",
+				"description": "The URL to open"
+			}
+		},
+		"required": ["url"]
+	}`)
+}
+
+type browserOpenParams struct {
+	URL string `json:"url"`
+}
+
+func (t *BrowserOpen) Execute(ctx context.Context, params json.RawMessage) (*Result, error) {
+	var p browserOpenParams
+	if err := json.Unmarshal(params, &p); err != nil {
+		return &Result{Content: fmt.Sprintf("Invalid parameters: %v", err), IsError: true}, nil
+	}
+	if p.URL == "" {
+		return &Result{Content: "URL is required", IsError: true}, nil
+	}
+
+	if err := t.session.run(ctx, chromedp.Navigate(p.URL), chromedp.WaitReady("body", chromedp.ByQuery)); err != nil {
+		return &Result{Content: fmt.Sprintf("Failed to open %s: %v", p.URL, err), IsError: true}, nil
+	}
+
+	summary, err := t.session.pageSummary(ctx)
+	if err != nil {
+		return &Result{Content: fmt.Sprintf("Opened %s but failed to read page: %v", p.URL, err), IsError: true}, nil
+	}
+	return &Result{Content: summary}, nil
+}
+
+// --- browser_click ---
+
+// BrowserClick clicks an element on the current page.
+type BrowserClick struct {
+	session *BrowserSession
+}
+
+// NewBrowserClick creates the browser_click tool.
+func NewBrowserClick(session *BrowserSession) *BrowserClick {
+	return &BrowserClick{session: session}
+}
+
+func (t *BrowserClick) Name() string {
+	return "browser_click"
+}
+
+func (t *BrowserClick) Description() string {
+	return `Click an element on the current browser page, selected by CSS selector.
+Returns the page title and text after the click.`
+}
+
+func (t *BrowserClick) Schema() json.RawMessage {
+	return json.RawMessage(`{
+		"type": "object",
+		"properties": {
+			"selector": {
+				"type": "string",
+				"description": "CSS selector of the element to click"
+			}
+		},
+		"required": ["selector"]
+	}`)
+}
+
+type browserClickParams struct {
+	Selector string `json:"selector"`
+}
+
+func (t *BrowserClick) Execute(ctx context.Context, params json.RawMessage) (*Result, error) {
+	var p browserClickParams
+	if err := json.Unmarshal(params, &p); err != nil {
+		return &Result{Content: fmt.Sprintf("Invalid parameters: %v", err), IsError: true}, nil
+	}
+	if p.Selector == "" {
+		return &Result{Content: "selector is required", IsError: true}, nil
+	}
+
+	if err := t.session.run(ctx,
+		chromedp.WaitVisible(p.Selector, chromedp.ByQuery),
+		chromedp.Click(p.Selector, chromedp.ByQuery),
+		chromedp.Sleep(500*time.Millisecond),
+	); err != nil {
+		return &Result{Content: fmt.Sprintf("Failed to click %s: %v", p.Selector, err), IsError: true}, nil
+	}
+
+	summary, err := t.session.pageSummary(ctx)
+	if err != nil {
+		return &Result{Content: fmt.Sprintf("Clicked %s", p.Selector)}, nil
+	}
+	return &Result{Content: fmt.Sprintf("Clicked %s\n\n%s", p.Selector, summary)}, nil
+}
+
+// --- browser_type ---
+
+// BrowserType types text into an input on the current page.
+type BrowserType struct {
+	session *BrowserSession
+}
+
+// NewBrowserType creates the browser_type tool.
+func NewBrowserType(session *BrowserSession) *BrowserType {
+	return &BrowserType{session: session}
+}
+
+func (t *BrowserType) Name() string {
+	return "browser_type"
+}
+
+func (t *BrowserType) Description() string {
+	return `Type text into an input field on the current browser page, selected by CSS selector.
+Set submit=true to submit the surrounding form afterwards.`
+}
+
+func (t *BrowserType) Schema() json.RawMessage {
+	return json.RawMessage(`{
+		"type": "object",
+		"properties": {
+			"selector": {
+				"type": "string",
+				"description": "CSS selector of the input field"
+			},
+			"text": {
+				"type": "string",
+				"description": "Text to type"
+			},
+			"clear": {
+				"type": "boolean",
+				"description": "Clear the field before typing (default: true)"
+			},
+			"submit": {
+				"type": "boolean",
+				"description": "Submit the form after typing (default: false)"
+			}
+		},
+		"required": ["selector", "text"]
+	}`)
+}
+
+type browserTypeParams struct {
+	Selector string `json:"selector"`
+	Text     string `json:"text"`
+	Clear    *bool  `json:"clear"`
+	Submit   bool   `json:"submit"`
+}
+
+func (t *BrowserType) Execute(ctx context.Context, params json.RawMessage) (*Result, error) {
+	var p browserTypeParams
+	if err := json.Unmarshal(params, &p); err != nil {
+		return &Result{Content: fmt.Sprintf("Invalid parameters: %v", err), IsError: true}, nil
+	}
+	if p.Selector == "" {
+		return &Result{Content: "selector is required", IsError: true}, nil
+	}
+
+	actions := []chromedp.Action{chromedp.WaitVisible(p.Selector, chromedp.ByQuery)}
+	if p.Clear == nil || *p.Clear {
+		actions = append(actions, chromedp.Clear(p.Selector, chromedp.ByQuery))
+	}
+	actions = append(actions, chromedp.SendKeys(p.Selector, p.Text, chromedp.ByQuery))
+	if p.Submit {
+		actions = append(actions,
+			chromedp.Submit(p.Selector, chromedp.ByQuery),
+			chromedp.Sleep(time.Second),
+		)
+	}
+
+	if err := t.session.run(ctx, actions...); err != nil {
+		return &Result{Content: fmt.Sprintf("Failed to type into %s: %v", p.Selector, err), IsError: true}, nil
+	}
+
+	if !p.Submit {
+		return &Result{Content: fmt.Sprintf("Typed %d characters into %s", len(p.Text), p.Selector)}, nil
+	}
+	summary, err := t.session.pageSummary(ctx)
+	if err != nil {
+		return &Result{Content: fmt.Sprintf("Typed into %s and submitted", p.Selector)}, nil
+	}
+	return &Result{Content: fmt.Sprintf("Typed into %s and submitted\n\n%s", p.Selector, summary)}, nil
+}
+
+// --- browser_screenshot ---
+
+// BrowserScreenshot captures the current page as a PNG.
+type BrowserScreenshot struct {
+	session *BrowserSession
+}
+
+// NewBrowserScreenshot creates the browser_screenshot tool.
+func NewBrowserScreenshot(session *BrowserSession) *BrowserScreenshot {
+	return &BrowserScreenshot{session: session}
+}
+
+func (t *BrowserScreenshot) Name() string {
+	return "browser_screenshot"
+}
+
+func (t *BrowserScreenshot) Description() string {
+	return `Take a screenshot of the current browser page (or one element).
+The image is attached to the conversation for channels that support files.`
+}
+
+func (t *BrowserScreenshot) Schema() json.RawMessage {
+	return json.RawMessage(`{
+		"type": "object",
+		"properties": {
+			"selector": {
+				"type": "string",
+				"description": "CSS selector of an element to capture (default: viewport)"
+			},
+			"full_page": {
+				"type": "boolean",
+				"description": "Capture the full scrollable page (default: false)"
+			}
+		}
+	}`)
+}
+
+type browserScreenshotParams struct {
+	Selector string `json:"selector"`
+	FullPage bool   `json:"full_page"`
+}
+
+func (t *BrowserScreenshot) Execute(ctx context.Context, params json.RawMessage) (*Result, error) {
+	var p browserScreenshotParams
+	if len(params) > 0 {
+		if err := json.Unmarshal(params, &p); err != nil {
+			return &Result{Content: fmt.Sprintf("Invalid parameters: %v", err), IsError: true}, nil
+		}
+	}
+
+	var buf []byte
+	var action chromedp.Action
+	switch {
+	case p.Selector != "":
+		action = chromedp.Screenshot(p.Selector, &buf, chromedp.ByQuery)
+	case p.FullPage:
+		action = chromedp.FullScreenshot(&buf, 90)
+	default:
+		action = chromedp.CaptureScreenshot(&buf)
+	}
+
+	var location string
+	if err := t.session.run(ctx, chromedp.Location(&location), action); err != nil {
+		return &Result{Content: fmt.Sprintf("Failed to take screenshot: %v", err), IsError: true}, nil
+	}
+
+	name := fmt.Sprintf("screenshot-%s.png", time.Now().Format("20060102-150405"))
+	mimeType := "image/png"
+	if p.FullPage && p.Selector == "" {
+		name = name[:len(name)-4] + ".jpg"
+		mimeType = "image/jpeg"
+	}
+
+	dir := filepath.Join(os.TempDir(), "klaw-screenshots")
+	path := filepath.Join(dir, name)
+	if err := os.MkdirAll(dir, 0755); err == nil {
+		_ = os.WriteFile(path, buf, 0644)
+	}
+
+	return &Result{
+		Content: fmt.Sprintf("Screenshot of %s (%d bytes) saved to %s", location, len(buf), path),
+		Attachments: []Attachment{
+			{Name: name, MimeType: mimeType, Data: buf},
+		},
+	}, nil
+}
@@ -0,0 +1,54 @@
+package tool
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"os/exec"
+	"strings"
+	"testing"
+)
+
+func TestBrowserOpen_RedirectPolicy(t *testing.T) {
+	if os.Getenv("KLAW_CHROME_PATH") == "" {
+		found := false
+		for _, name := range []string{"google-chrome", "chromium", "chromium-browser", "headless-shell"} {
+			if _, err := exec.LookPath(name); err == nil {
+				found = true
+				break
+			}
+		}
+		if !found {
+			t.Skip("chrome not installed")
+		}
+	}
+
+	denied := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Error("browser reached a host outside the agent policy")
+	}))
+	defer denied.Close()
+	allowed := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, strings.Replace(denied.URL, "127.0.0.1", "localhost", 1), http.StatusFound)
+	}))
+	defer allowed.Close()
+
+	session := NewBrowserSession()
+	defer session.Close()
+	r := NewRegistry()
+	r.Register(NewBrowserOpen(session))
+	restricted, err := r.WithPolicy(Policy{AllowedDomains: []string{"127.0.0.1"}}, t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tl, _ := restricted.Get("browser_open")
+	res, err := tl.Execute(context.Background(), json.RawMessage(`{"url":"`+allowed.URL+`"}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !res.IsError {
+		t.Errorf("expected navigation outside the allowed domains to fail, got:\n%s", res.Content)
+	}
+}
@@ -47,17 +47,17 @@ func (t *CronCreateTool) Description() string {
 	return `Create a scheduled cron job. Use this when the user wants something to run automatically at intervals.
 
 IMPORTANT: Use this tool for ANY time-based recurring task like:
-- "every 5 minutes", "every hour", "daily at 9am", "every monday"
-- "her saat", "her 5 dakikada", "günlük"
+- "every 5 minutes", "every hour", "daily at 9am", "every monday", in any language
 
 CRITICAL - Task field must be VERY DETAILED and WELL-DEFINED:
 - The task runs WITHOUT any conversation context
 - Write EXACT step-by-step instructions for what to do
 - Include ALL criteria, formats, and expected outputs
 - Be specific about what to look for and how to respond
+- Write it in the language the user talks to you in, so the results are posted in that language
 
-BAD task: "Kanalı kontrol et"
-GOOD task: "1. Kanaldaki mesajlardaki URL/domain'leri bul. 2. Her domain için web sitesini ziyaret et. 3. Image/video/audio AI modeli kullanıyorlarsa puan ver (1-10). 4. Format: 'domain.com - 8/10 - Video generation' şeklinde yanıtla. 5. Hiç domain yoksa 'Yeni domain yok' de."
+BAD task: "Check the channel"
+GOOD task: "1. Find the URLs/domains in the channel's messages. 2. Visit the website of each domain. 3. Score (1-10) how much they use image/video/audio AI models. 4. Reply in the format 'domain.com - 8/10 - Video generation'. 5. If there are no domains, say 'No new domains'."
 
 The job will run the specified agent with the given task at the scheduled times.`
 }
@@ -282,7 +282,8 @@ func (t *CronListTool) Name() string {
 }
 
 func (t *CronListTool) Description() string {
-	return `List all scheduled cron jobs. Use this to check existing scheduled tasks before creating new ones.`
+	return `List all scheduled cron jobs. Use this to check existing scheduled tasks before creating new ones,
+or to find the job to change with cron_update or remove with cron_delete.`
 }
 
 func (t *CronListTool) Schema() json.RawMessage {
@@ -312,9 +313,13 @@ func (t *CronListTool) Execute(ctx context.Context, params json.RawMessage) (*Re
 		if !job.Enabled {
 			status = "disabled"
 		}
-		_, _ = fmt.Fprintf(&sb, "- %s (%s)\n", job.Name, status)
+		_, _ = fmt.Fprintf(&sb, "- %s [id: %s] (%s)\n", job.Name, job.ID, status)
 		_, _ = fmt.Fprintf(&sb, "  Schedule: %s\n", scheduler.FormatSchedule(job.Cron))
 		_, _ = fmt.Fprintf(&sb, "  Agent: %s\n", job.Agent)
+		_, _ = fmt.Fprintf(&sb, "  Task: %s\n", truncateString(job.Task, 100))
+		if ch := job.Config["channel"]; ch != "" {
+			_, _ = fmt.Fprintf(&sb, "  Channel: %s\n", ch)
+		}
 		if job.NextRun != nil {
 			_, _ = fmt.Fprintf(&sb, "  Next run: %s\n", job.NextRun.Format("Jan 02 15:04"))
 		}
@@ -322,3 +327,243 @@ func (t *CronListTool) Execute(ctx context.Context, params json.RawMessage) (*Re
 
 	return &Result{Content: sb.String()}, nil
 }
+
+// sharedScheduler returns sched, or loads the scheduler from the state dir.
+func sharedScheduler(sched interface{}) *scheduler.Scheduler {
+	if sched != nil {
+		return sched.(*scheduler.Scheduler)
+	}
+	s := scheduler.NewScheduler(config.StateDir() + "/scheduler")
+	_ = s.Load()
+	return s
+}
+
+// CronDeleteTool allows the AI to remove scheduled jobs
+type CronDeleteTool struct {
+	scheduler *scheduler.Scheduler
+	ctxMgr    *cluster.ContextManager
+}
+
+// NewCronDeleteToolWithScheduler creates a cron delete tool with a shared scheduler
+func NewCronDeleteToolWithScheduler(sched interface{}) *CronDeleteTool {
+	return &CronDeleteTool{
+		scheduler: sharedScheduler(sched),
+		ctxMgr:    cluster.NewContextManager(config.ConfigDir()),
+	}
+}
+
+func (t *CronDeleteTool) Name() string {
+	return "cron_delete"
+}
+
+func (t *CronDeleteTool) Description() string {
+	return `Delete a scheduled cron job by name or ID. Use this when the user wants to stop a recurring task
+(e.g. "stop monitoring this channel"). Use cron_list first if unsure which job is meant.
+To pause a job without deleting it, use cron_update with enabled=false.`
+}
+
+func (t *CronDeleteTool) Schema() json.RawMessage {
+	return json.RawMessage(`{
+		"type": "object",
+		"properties": {
+			"job": {
+				"type": "string",
+				"description": "Job name or ID (see cron_list)"
+			}
+		},
+		"required": ["job"]
+	}`)
+}
+
+type cronDeleteParams struct {
+	Job string `json:"job"`
+}
+
+func (t *CronDeleteTool) Execute(ctx context.Context, params json.RawMessage) (*Result, error) {
+	var p cronDeleteParams
+	if err := json.Unmarshal(params, &p); err != nil {
+		return &Result{Content: fmt.Sprintf("Invalid parameters: %v", err), IsError: true}, nil
+	}
+	if p.Job == "" {
+		return &Result{Content: "Job name or ID is required", IsError: true}, nil
+	}
+
+	clusterName, namespace, err := t.ctxMgr.RequireCurrent()
+	if err != nil {
+		clusterName = "default"
+		namespace = "default"
+	}
+
+	job, err := t.scheduler.FindJob(clusterName, namespace, p.Job)
+	if err != nil {
+		return &Result{Content: fmt.Sprintf("%v. Use cron_list to see existing jobs.", err), IsError: true}, nil
+	}
+	if err := t.scheduler.DeleteJob(job.ID); err != nil {
+		return &Result{Content: fmt.Sprintf("Failed to delete job: %v", err), IsError: true}, nil
+	}
+
+	return &Result{Content: fmt.Sprintf("Scheduled job '%s' deleted.", job.Name)}, nil
+}
+
+// CronUpdateTool allows the AI to modify scheduled jobs
+type CronUpdateTool struct {
+	scheduler *scheduler.Scheduler
+	store     *cluster.Store
+	ctxMgr    *cluster.ContextManager
+}
+
+// NewCronUpdateToolWithScheduler creates a cron update tool with a shared scheduler
+func NewCronUpdateToolWithScheduler(sched interface{}) *CronUpdateTool {
+	return &CronUpdateTool{
+		scheduler: sharedScheduler(sched),
+		store:     cluster.NewStore(config.StateDir()),
+		ctxMgr:    cluster.NewContextManager(config.ConfigDir()),
+	}
+}
+
+func (t *CronUpdateTool) Name() string {
+	return "cron_update"
+}
+
+func (t *CronUpdateTool) Description() string {
+	return `Update a scheduled cron job by name or ID. Only the fields given are changed.
+Use this to change when a job runs, what it does, which agent or channel it uses,
+or to pause (enabled=false) and resume (enabled=true) it.`
+}
+
+func (t *CronUpdateTool) Schema() json.RawMessage {
+	return json.RawMessage(`{
+		"type": "object",
+		"properties": {
+			"job": {
+				"type": "string",
+				"description": "Job name or ID (see cron_list)"
+			},
+			"schedule": {
+				"type": "string",
+				"description": "New schedule in plain English, e.g. 'every 30 minutes'"
+			},
+			"agent": {
+				"type": "string",
+				"description": "New agent to run the task"
+			},
+			"task": {
+				"type": "string",
+				"description": "New task/prompt. Must be as detailed as for cron_create."
+			},
+			"channel": {
+				"type": "string",
+				"description": "Slack channel ID to read messages from. Empty string removes the channel."
+			},
+			"skip_replied": {
+				"type": "boolean",
+				"description": "Skip messages that already have a bot reply"
+			},
+			"enabled": {
+				"type": "boolean",
+				"description": "Pause (false) or resume (true) the job"
+			}
+		},
+		"required": ["job"]
+	}`)
+}
+
+type cronUpdateParams struct {
+	Job         string  `json:"job"`
+	Schedule    string  `json:"schedule,omitempty"`
+	Agent       string  `json:"agent,omitempty"`
+	Task        string  `json:"task,omitempty"`
+	Channel     *string `json:"channel,omitempty"`
+	SkipReplied *bool   `json:"skip_replied,omitempty"`
+	Enabled     *bool   `json:"enabled,omitempty"`
+}
+
+func (t *CronUpdateTool) Execute(ctx context.Context, params json.RawMessage) (*Result, error) {
+	var p cronUpdateParams
+	if err := json.Unmarshal(params, &p); err != nil {
+		return &Result{Content: fmt.Sprintf("Invalid parameters: %v", err), IsError: true}, nil
+	}
+	if p.Job == "" {
+		return &Result{Content: "Job name or ID is required", IsError: true}, nil
+	}
+
+	clusterName, namespace, err := t.ctxMgr.RequireCurrent()
+	if err != nil {
+		clusterName = "default"
+		namespace = "default"
+	}
+
+	job, err := t.scheduler.FindJob(clusterName, namespace, p.Job)
+	if err != nil {
+		return &Result{Content: fmt.Sprintf("%v. Use cron_list to see existing jobs.", err), IsError: true}, nil
+	}
+
+	var cron string
+	if p.Schedule != "" {
+		cron, err = scheduler.ParseSchedule(p.Schedule)
+		if err != nil {
+			return &Result{Content: fmt.Sprintf("Invalid schedule: %v", err), IsError: true}, nil
+		}
+	}
+	if p.Agent != "" && !t.store.AgentBindingExists(clusterName, namespace, p.Agent) {
+		return &Result{
+			Content: fmt.Sprintf("Agent not found: %s. Create it first with agent_spawn.", p.Agent),
+			IsError: true,
+		}, nil
+	}
+
+	var changes []string
+	job, err = t.scheduler.UpdateJob(job.ID, func(job *scheduler.Job) {
+		if cron != "" {
+			job.Schedule = p.Schedule
+			job.Cron = cron
+			changes = append(changes, "schedule: "+scheduler.FormatSchedule(cron))
+		}
+		if p.Agent != "" {
+			job.Agent = p.Agent
+			changes = append(changes, "agent: "+p.Agent)
+		}
+		if p.Task != "" {
+			job.Task = p.Task
+			changes = append(changes, "task: "+truncateString(p.Task, 100))
+		}
+		if job.Config == nil {
+			job.Config = make(map[string]string)
+		}
+		if p.Channel != nil {
+			if *p.Channel == "" {
+				delete(job.Config, "channel")
+				changes = append(changes, "channel: removed")
+			} else {
+				job.Config["channel"] = *p.Channel
+				changes = append(changes, "channel: "+*p.Channel)
+			}
+		}
+		if p.SkipReplied != nil {
+			job.Config["skip_replied"] = fmt.Sprintf("%t", *p.SkipReplied)
+			changes = append(changes, fmt.Sprintf("skip_replied: %t", *p.SkipReplied))
+		}
+		if p.Enabled != nil {
+			job.Enabled = *p.Enabled
+			changes = append(changes, fmt.Sprintf("enabled: %t", *p.Enabled))
+		}
+	})
+	if err != nil {
+		return &Result{Content: fmt.Sprintf("Failed to update job: %v", err), IsError: true}, nil
+	}
+
+	if len(changes) == 0 {
+		return &Result{Content: fmt.Sprintf("Nothing to change for job '%s'.", job.Name)}, nil
+	}
+
+	var sb strings.Builder
+	_, _ = fmt.Fprintf(&sb, "Scheduled job '%s' updated:\n", job.Name)
+	for _, c := range changes {
+		_, _ = fmt.Fprintf(&sb, "- %s\n", c)
+	}
+	if job.NextRun != nil {
+		_, _ = fmt.Fprintf(&sb, "Next run: %s\n", job.NextRun.Format("Jan 02 15:04"))
+	}
+
+	return &Result{Content: sb.String()}, nil
+}
@@ -0,0 +1,114 @@
+package tool
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// AgentRunFunc runs the named agent on a task and returns its final response.
+type AgentRunFunc func(ctx context.Context, agentName, task string) (string, error)
+
+type dispatchDepthKey struct{}
+
+// AgentDispatchTool hands a subtask to another named agent and returns its
+// result. Agents run in-process, or through the controller when one is
+// configured.
+type AgentDispatchTool struct {
+	local    AgentRunFunc
+	remote   AgentRunFunc // nil when not distributed
+	maxDepth int
+}
+
+// NewAgentDispatchTool creates the agent_dispatch tool. remote may be nil.
+func NewAgentDispatchTool(local, remote AgentRunFunc) *AgentDispatchTool {
+	return &AgentDispatchTool{local: local, remote: remote, maxDepth: 3}
+}
+
+func (t *AgentDispatchTool) Name() string { return "agent_dispatch" }
+
+func (t *AgentDispatchTool) Description() string {
+	return `Hand a subtask to another named agent (see agent_list) and wait for its result.
+Use this to act as a supervisor: split work, send each part to the agent best suited for it,
+then combine the results. Give the agent a complete, self-contained task description.`
+}
+
+func (t *AgentDispatchTool) Schema() json.RawMessage {
+	return json.RawMessage(`{
+	"type": "object",
+	"properties": {
+		"agent": {
+			"type": "string",
+			"description": "Name of the agent to run"
+		},
+		"task": {
+			"type": "string",
+			"description": "Self-contained task for the agent"
+		},
+		"via": {
+			"type": "string",
+			"enum": ["auto", "local", "controller"],
+			"description": "Where to run the agent (default: auto = controller if configured, else local)"
+		}
+	},
+	"required": ["agent", "task"]
+}`)
+}
+
+type agentDispatchParams struct {
+	Agent string `json:"agent"`
+	Task  string `json:"task"`
+	Via   string `json:"via"`
+}
+
+func (t *AgentDispatchTool) Execute(ctx context.Context, params json.RawMessage) (*Result, error) {
+	var p agentDispatchParams
+	if err := json.Unmarshal(params, &p); err != nil {
+		return &Result{Content: fmt.Sprintf("invalid params: %v", err), IsError: true}, nil
+	}
+	if p.Agent == "" || p.Task == "" {
+		return &Result{Content: "agent and task are required", IsError: true}, nil
+	}
+
+	depth, _ := ctx.Value(dispatchDepthKey{}).(int)
+	if depth >= t.maxDepth {
+		return &Result{Content: fmt.Sprintf("maximum dispatch depth reached (%d)", t.maxDepth), IsError: true}, nil
+	}
+
+	var run AgentRunFunc
+	switch p.Via {
+	case "", "auto":
+		run = t.remote
+		if run == nil {
+			run = t.local
+		}
+	case "local":
+		run = t.local
+	case "controller":
+		if t.remote == nil {
+			return &Result{Content: "no controller configured", IsError: true}, nil
+		}
+		run = t.remote
+	default:
+		return &Result{Content: fmt.Sprintf("unknown via: %s", p.Via), IsError: true}, nil
+	}
+	if run == nil {
+		return &Result{Content: "agent dispatch is not available here", IsError: true}, nil
+	}
+
+	ctx = context.WithValue(ctx, dispatchDepthKey{}, depth+1)
+	ctx, cancel := context.WithTimeout(ctx, delegateTimeout)
+	defer cancel()
+
+	start := time.Now()
+	result, err := run(ctx, p.Agent, p.Task)
+	if err != nil {
+		return &Result{Content: fmt.Sprintf("agent %s failed: %v", p.Agent, err), IsError: true}, nil
+	}
+
+	if len(result) > delegateMaxOutputLen {
+		result = result[:delegateMaxOutputLen] + "\n... (output truncated)"
+	}
+	return &Result{Content: fmt.Sprintf("[%s finished in %s]\n%s", p.Agent, time.Since(start).Round(time.Second), result)}, nil
+}
@@ -0,0 +1,48 @@
+package tool
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestAgentDispatchTool(t *testing.T) {
+	var ran []string
+	runner := func(where string) AgentRunFunc {
+		return func(ctx context.Context, agentName, task string) (string, error) {
+			ran = append(ran, where+":"+agentName)
+			return "done: " + task, nil
+		}
+	}
+
+	localOnly := NewAgentDispatchTool(runner("local"), nil)
+	res, err := localOnly.Execute(context.Background(), json.RawMessage(`{"agent":"coder","task":"write tests"}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.IsError || !strings.Contains(res.Content, "done: write tests") {
+		t.Errorf("unexpected result: %s", res.Content)
+	}
+	if res, _ := localOnly.Execute(context.Background(), json.RawMessage(`{"agent":"coder","task":"x","via":"controller"}`)); !res.IsError {
+		t.Error("expected error without controller")
+	}
+
+	both := NewAgentDispatchTool(runner("local"), runner("controller"))
+	_, _ = both.Execute(context.Background(), json.RawMessage(`{"agent":"a","task":"x"}`))
+	_, _ = both.Execute(context.Background(), json.RawMessage(`{"agent":"b","task":"x","via":"local"}`))
+	if got := strings.Join(ran, ","); got != "local:coder,controller:a,local:b" {
+		t.Errorf("unexpected runs: %s", got)
+	}
+
+	// Nested dispatches stop at the depth limit
+	var nested *AgentDispatchTool
+	nested = NewAgentDispatchTool(func(ctx context.Context, agentName, task string) (string, error) {
+		res, _ := nested.Execute(ctx, json.RawMessage(`{"agent":"self","task":"again"}`))
+		return res.Content, nil
+	}, nil)
+	res, _ = nested.Execute(context.Background(), json.RawMessage(`{"agent":"self","task":"loop"}`))
+	if !strings.Contains(res.Content, "maximum dispatch depth") {
+		t.Errorf("expected depth limit, got: %s", res.Content)
+	}
+}
@@ -19,6 +19,11 @@ func NewEdit(workDir string) *Edit {
 	return &Edit{workDir: workDir}
 }
 
+// WorkDir returns the directory relative paths are resolved against.
+func (e *Edit) WorkDir() string {
+	return e.workDir
+}
+
 func (e *Edit) Name() string {
 	return "edit"
 }
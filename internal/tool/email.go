@@ -0,0 +1,350 @@
+package tool
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/tls"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"mime"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"text/template"
+	"time"
+)
+
+// EmailSend reads its SMTP server from the "email" skill config, which
+// defaults to the namespace SMTP settings (klaw namespace smtp set):
+//
+//	email.host, email.port, email.username, email.password, email.from
+//	email.allowed_recipients  comma-separated addresses or @domains
+const emailSkill = "email"
+
+// EmailSend sends email over SMTP.
+type EmailSend struct {
+	workDir string
+}
+
+// NewEmailSend creates the email_send tool.
+func NewEmailSend(workDir string) *EmailSend {
+	return &EmailSend{workDir: workDir}
+}
+
+func (t *EmailSend) Name() string {
+	return "email_send"
+}
+
+func (t *EmailSend) Description() string {
+	return `Send an email. The subject and body are Go templates rendered with "data"
+(e.g. "Hello {{.name}}"). Files in the working directory can be attached.
+Recipients may be restricted to an allowlist.`
+}
+
+func (t *EmailSend) Schema() json.RawMessage {
+	return json.RawMessage(`{
+		"type": "object",
+		"properties": {
+			"to": {
+				"type": "array",
+				"items": {"type": "string"},
+				"description": "Recipient addresses"
+			},
+			"cc": {
+				"type": "array",
+				"items": {"type": "string"},
+				"description": "CC addresses"
+			},
+			"subject": {
+				"type": "string",
+				"description": "Subject (template)"
+			},
+			"body": {
+				"type": "string",
+				"description": "Body (template)"
+			},
+			"html": {
+				"type": "boolean",
+				"description": "Send the body as HTML (default: plain text)"
+			},
+			"data": {
+				"type": "object",
+				"description": "Values for the subject/body templates"
+			},
+			"attachments": {
+				"type": "array",
+				"items": {"type": "string"},
+				"description": "Paths of files to attach"
+			}
+		},
+		"required": ["to", "subject", "body"]
+	}`)
+}
+
+type emailParams struct {
+	To          []string       `json:"to"`
+	CC          []string       `json:"cc"`
+	Subject     string         `json:"subject"`
+	Body        string         `json:"body"`
+	HTML        bool           `json:"html"`
+	Data        map[string]any `json:"data"`
+	Attachments []string       `json:"attachments"`
+}
+
+// emailAttachment is a file to include in the message.
+type emailAttachment struct {
+	name string
+	data []byte
+}
+
+func (t *EmailSend) Execute(ctx context.Context, params json.RawMessage) (*Result, error) {
+	var p emailParams
+	if err := json.Unmarshal(params, &p); err != nil {
+		return &Result{Content: fmt.Sprintf("Invalid parameters: %v", err), IsError: true}, nil
+	}
+	if len(p.To) == 0 {
+		return &Result{Content: "at least one recipient is required", IsError: true}, nil
+	}
+
+	cfg := SkillConfigFromContext(ctx, emailSkill)
+	if cfg["host"] == "" || cfg["from"] == "" {
+		return &Result{Content: "no SMTP server configured (klaw namespace smtp set --host ... --from ...)", IsError: true}, nil
+	}
+
+	recipients := append(append([]string{}, p.To...), p.CC...)
+	for _, r := range recipients {
+		addr, err := mail.ParseAddress(r)
+		if err != nil {
+			return &Result{Content: fmt.Sprintf("Invalid address %q: %v", r, err), IsError: true}, nil
+		}
+		if !recipientAllowed(addr.Address, cfg["allowed_recipients"]) {
+			return &Result{Content: fmt.Sprintf("Recipient %s is not in the allowed recipients list", addr.Address), IsError: true}, nil
+		}
+	}
+
+	subject, err := renderEmailTemplate("subject", p.Subject, p.Data)
+	if err != nil {
+		return &Result{Content: err.Error(), IsError: true}, nil
+	}
+	body, err := renderEmailTemplate("body", p.Body, p.Data)
+	if err != nil {
+		return &Result{Content: err.Error(), IsError: true}, nil
+	}
+
+	var attachments []emailAttachment
+	for _, path := range p.Attachments {
+		path, err := t.attachmentPath(path)
+		if err != nil {
+			return &Result{Content: fmt.Sprintf("Failed to attach file: %v", err), IsError: true}, nil
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return &Result{Content: fmt.Sprintf("Failed to read attachment: %v", err), IsError: true}, nil
+		}
+		attachments = append(attachments, emailAttachment{name: filepath.Base(path), data: data})
+	}
+
+	msg := buildEmailMessage(cfg["from"], p.To, p.CC, subject, body, p.HTML, attachments)
+
+	if err := sendSMTP(ctx, cfg, recipients, msg); err != nil {
+		return &Result{Content: fmt.Sprintf("Failed to send email: %v", err), IsError: true}, nil
+	}
+
+	return &Result{Content: fmt.Sprintf("Email %q sent to %s (%d attachments)", subject, strings.Join(recipients, ", "), len(attachments))}, nil
+}
+
+// attachmentPath resolves an attachment against the working directory,
+// following symlinks, and refuses files outside it so the agent cannot mail
+// out keys or config of the host.
+func (t *EmailSend) attachmentPath(path string) (string, error) {
+	root, err := filepath.Abs(t.workDir)
+	if err != nil {
+		return "", err
+	}
+	if root, err = filepath.EvalSymlinks(root); err != nil {
+		return "", err
+	}
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(root, path)
+	}
+	resolved, err := filepath.EvalSymlinks(path)
+	if err != nil {
+		return "", err
+	}
+	if resolved != root && !strings.HasPrefix(resolved, root+string(filepath.Separator)) {
+		return "", fmt.Errorf("%s is outside the working directory %s", path, root)
+	}
+	return resolved, nil
+}
+
+// recipientAllowed checks addr against a comma-separated allowlist of
+// addresses and @domains. An empty allowlist allows everyone.
+func recipientAllowed(addr, allowlist string) bool {
+	if strings.TrimSpace(allowlist) == "" {
+		return true
+	}
+	addr = strings.ToLower(addr)
+	_, domain, _ := strings.Cut(addr, "@")
+	for _, entry := range strings.Split(allowlist, ",") {
+		entry = strings.ToLower(strings.TrimSpace(entry))
+		switch {
+		case entry == "":
+		case strings.HasPrefix(entry, "@"):
+			if domain == entry[1:] {
+				return true
+			}
+		case !strings.Contains(entry, "@"):
+			if domain == entry {
+				return true
+			}
+		case entry == addr:
+			return true
+		}
+	}
+	return false
+}
+
+func renderEmailTemplate(name, text string, data map[string]any) (string, error) {
+	tmpl, err := template.New(name).Option("missingkey=error").Parse(text)
+	if err != nil {
+		return "", fmt.Errorf("invalid %s template: %w", name, err)
+	}
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return "", fmt.Errorf("failed to render %s: %w", name, err)
+	}
+	return buf.String(), nil
+}
+
+// buildEmailMessage assembles a MIME message with optional attachments.
+func buildEmailMessage(from string, to, cc []string, subject, body string, html bool, attachments []emailAttachment) []byte {
+	var buf bytes.Buffer
+	_, _ = fmt.Fprintf(&buf, "From: %s\r\n", from)
+	_, _ = fmt.Fprintf(&buf, "To: %s\r\n", strings.Join(to, ", "))
+	if len(cc) > 0 {
+		_, _ = fmt.Fprintf(&buf, "Cc: %s\r\n", strings.Join(cc, ", "))
+	}
+	_, _ = fmt.Fprintf(&buf, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", subject))
+	_, _ = fmt.Fprintf(&buf, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	buf.WriteString("MIME-Version: 1.0\r\n")
+
+	contentType := "text/plain; charset=utf-8"
+	if html {
+		contentType = "text/html; charset=utf-8"
+	}
+
+	if len(attachments) == 0 {
+		_, _ = fmt.Fprintf(&buf, "Content-Type: %s\r\nContent-Transfer-Encoding: base64\r\n\r\n", contentType)
+		writeBase64Lines(&buf, []byte(body))
+		return buf.Bytes()
+	}
+
+	boundary := randomBoundary()
+	_, _ = fmt.Fprintf(&buf, "Content-Type: multipart/mixed; boundary=%q\r\n\r\n", boundary)
+
+	_, _ = fmt.Fprintf(&buf, "--%s\r\nContent-Type: %s\r\nContent-Transfer-Encoding: base64\r\n\r\n", boundary, contentType)
+	writeBase64Lines(&buf, []byte(body))
+
+	for _, att := range attachments {
+		mimeType := mime.TypeByExtension(filepath.Ext(att.name))
+		if mimeType == "" {
+			mimeType = "application/octet-stream"
+		}
+		_, _ = fmt.Fprintf(&buf, "--%s\r\nContent-Type: %s\r\nContent-Transfer-Encoding: base64\r\nContent-Disposition: attachment; filename=%q\r\n\r\n",
+			boundary, mimeType, att.name)
+		writeBase64Lines(&buf, att.data)
+	}
+	_, _ = fmt.Fprintf(&buf, "--%s--\r\n", boundary)
+	return buf.Bytes()
+}
+
+// writeBase64Lines writes data base64-encoded in 76-character lines.
+func writeBase64Lines(buf *bytes.Buffer, data []byte) {
+	enc := base64.StdEncoding.EncodeToString(data)
+	for len(enc) > 76 {
+		buf.WriteString(enc[:76] + "\r\n")
+		enc = enc[76:]
+	}
+	buf.WriteString(enc + "\r\n")
+}
+
+func randomBoundary() string {
+	b := make([]byte, 12)
+	_, _ = rand.Read(b)
+	return "klaw-" + hex.EncodeToString(b)
+}
+
+// sendSMTP delivers msg. Port 465 uses implicit TLS; other ports upgrade
+// with STARTTLS when the server offers it.
+func sendSMTP(ctx context.Context, cfg map[string]string, recipients []string, msg []byte) error {
+	host := cfg["host"]
+	port := 587
+	if n, err := strconv.Atoi(cfg["port"]); err == nil && n > 0 {
+		port = n
+	}
+	addr := net.JoinHostPort(host, strconv.Itoa(port))
+
+	dialer := &net.Dialer{Timeout: 30 * time.Second}
+	var conn net.Conn
+	var err error
+	if port == 465 {
+		conn, err = tls.DialWithDialer(dialer, "tcp", addr, &tls.Config{ServerName: host})
+	} else {
+		conn, err = dialer.DialContext(ctx, "tcp", addr)
+	}
+	if err != nil {
+		return err
+	}
+	_ = conn.SetDeadline(time.Now().Add(2 * time.Minute))
+
+	client, err := smtp.NewClient(conn, host)
+	if err != nil {
+		_ = conn.Close()
+		return err
+	}
+	defer func() { _ = client.Close() }()
+
+	if ok, _ := client.Extension("STARTTLS"); ok && port != 465 {
+		if err := client.StartTLS(&tls.Config{ServerName: host}); err != nil {
+			return err
+		}
+	}
+	if cfg["username"] != "" {
+		if err := client.Auth(smtp.PlainAuth("", cfg["username"], cfg["password"], host)); err != nil {
+			return err
+		}
+	}
+
+	from, err := mail.ParseAddress(cfg["from"])
+	if err != nil {
+		return fmt.Errorf("invalid from address: %w", err)
+	}
+	if err := client.Mail(from.Address); err != nil {
+		return err
+	}
+	for _, r := range recipients {
+		addr, _ := mail.ParseAddress(r)
+		if err := client.Rcpt(addr.Address); err != nil {
+			return err
+		}
+	}
+
+	w, err := client.Data()
+	if err != nil {
+		return err
+	}
+	if _, err := w.Write(msg); err != nil {
+		return err
+	}
+	if err := w.Close(); err != nil {
+		return err
+	}
+	return client.Quit()
+}
@@ -0,0 +1,99 @@
+package tool
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRecipientAllowed(t *testing.T) {
+	allowlist := "@example.com, partner.io, ops@vendor.net"
+	tests := []struct {
+		addr string
+		want bool
+	}{
+		{"alice@example.com", true},
+		{"bob@partner.io", true},
+		{"ops@vendor.net", true},
+		{"eve@vendor.net", false},
+		{"mallory@evil.com", false},
+		{"alice@sub.example.com", false},
+	}
+	for _, tt := range tests {
+		if got := recipientAllowed(tt.addr, allowlist); got != tt.want {
+			t.Errorf("recipientAllowed(%q) = %v, want %v", tt.addr, got, tt.want)
+		}
+	}
+	if !recipientAllowed("anyone@anywhere.org", "") {
+		t.Error("empty allowlist should allow everyone")
+	}
+}
+
+func TestBuildEmailMessage(t *testing.T) {
+	body, err := renderEmailTemplate("body", "Hello {{.name}}", map[string]any{"name": "Ada"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	msg := string(buildEmailMessage("bot@example.com", []string{"ada@example.com"}, nil, "Report", body, false,
+		[]emailAttachment{{name: "report.csv", data: []byte("a,b\n1,2\n")}}))
+
+	for _, want := range []string{
+		"To: ada@example.com\r\n",
+		"Content-Type: multipart/mixed;",
+		`Content-Disposition: attachment; filename="report.csv"`,
+		"SGVsbG8gQWRh", // base64("Hello Ada")
+	} {
+		if !strings.Contains(msg, want) {
+			t.Errorf("message missing %q:\n%s", want, msg)
+		}
+	}
+
+	if _, err := renderEmailTemplate("body", "Hi {{.missing}}", nil); err == nil {
+		t.Error("expected error for missing template key")
+	}
+}
+
+func TestEmailSend_RejectsRecipientOutsideAllowlist(t *testing.T) {
+	ctx := WithSkillConfig(context.Background(), map[string]map[string]string{
+		"email": {"host": "127.0.0.1", "from": "bot@example.com", "allowed_recipients": "@example.com"},
+	})
+	res, err := NewEmailSend(t.TempDir()).Execute(ctx, json.RawMessage(`{"to":["x@evil.com"],"subject":"s","body":"b"}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !res.IsError || !strings.Contains(res.Content, "not in the allowed recipients") {
+		t.Errorf("expected allowlist rejection, got: %s", res.Content)
+	}
+}
+
+func TestEmailSend_RejectsAttachmentOutsideWorkDir(t *testing.T) {
+	root := t.TempDir()
+	work := filepath.Join(root, "work")
+	secret := filepath.Join(root, "secret.key")
+	if err := os.MkdirAll(work, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(secret, []byte("key"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Symlink(secret, filepath.Join(work, "link.key")); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx := WithSkillConfig(context.Background(), map[string]map[string]string{
+		"email": {"host": "127.0.0.1", "from": "bot@example.com"},
+	})
+	for _, path := range []string{"../secret.key", secret, "link.key"} {
+		params, _ := json.Marshal(map[string]any{"to": []string{"x@example.com"}, "subject": "s", "body": "b", "attachments": []string{path}})
+		res, err := NewEmailSend(work).Execute(ctx, params)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !res.IsError || !strings.Contains(res.Content, "outside the working directory") {
+			t.Errorf("expected attachment %s to be refused, got: %s", path, res.Content)
+		}
+	}
+}
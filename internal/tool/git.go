@@ -0,0 +1,373 @@
+package tool
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// Git runs git operations. The same type backs git_status, git_diff,
+// git_commit, git_push, git_pull, git_branch and git_clone.
+//
+// Pushes to branches listed in the agent's git.protected_branches skill
+// config (comma-separated) are refused, and force pushes need confirm=true.
+type Git struct {
+	name    string
+	workDir string
+}
+
+// NewGit creates the git tool with the given name (e.g. "git_status").
+func NewGit(name, workDir string) *Git {
+	return &Git{name: name, workDir: workDir}
+}
+
+func (g *Git) Name() string {
+	return g.name
+}
+
+func (g *Git) Description() string {
+	switch g.name {
+	case "git_status":
+		return "Show the working tree status of a git repository (branch, staged, unstaged and untracked files)."
+	case "git_diff":
+		return "Show changes in a git repository. Set staged=true for staged changes, or ref to diff against a commit/branch."
+	case "git_commit":
+		return `Create a git commit. Stages the given files (or all changes with all=true) and commits with the message.
+Write clear, meaningful commit messages.`
+	case "git_push":
+		return `Push commits to a remote. Pushing to protected branches is refused.
+Force pushes rewrite history: ask the user first, then call again with confirm=true.`
+	case "git_pull":
+		return "Pull changes from a remote (optionally with rebase)."
+	case "git_branch":
+		return "Manage branches: list, create, switch, or delete."
+	case "git_clone":
+		return "Clone a git repository into the working directory."
+	}
+	return "Run a git operation."
+}
+
+func (g *Git) Schema() json.RawMessage {
+	pathProp := `
+			"path": {
+				"type": "string",
+				"description": "Repository directory (default: working directory)"
+			}`
+
+	var props, required string
+	switch g.name {
+	case "git_diff":
+		props = `
+			"staged": {"type": "boolean", "description": "Show staged changes"},
+			"ref": {"type": "string", "description": "Commit or branch to diff against"},
+			"files": {"type": "array", "items": {"type": "string"}, "description": "Limit to these files"},`
+	case "git_commit":
+		props = `
+			"message": {"type": "string", "description": "Commit message"},
+			"files": {"type": "array", "items": {"type": "string"}, "description": "Files to stage before committing"},
+			"all": {"type": "boolean", "description": "Stage all changes (including untracked files)"},`
+		required = `"message"`
+	case "git_push":
+		props = `
+			"remote": {"type": "string", "description": "Remote name (default: origin)"},
+			"branch": {"type": "string", "description": "Branch or src:dst refspec to push (default: current branch)"},
+			"set_upstream": {"type": "boolean", "description": "Set upstream tracking (-u)"},
+			"force": {"type": "boolean", "description": "Force push (uses --force-with-lease)"},
+			"confirm": {"type": "boolean", "description": "Confirm a force push after the user approved it"},`
+	case "git_pull":
+		props = `
+			"remote": {"type": "string", "description": "Remote name (default: origin)"},
+			"branch": {"type": "string", "description": "Branch to pull (default: upstream of current branch)"},
+			"rebase": {"type": "boolean", "description": "Rebase instead of merge"},`
+	case "git_branch":
+		props = `
+			"action": {"type": "string", "enum": ["list", "create", "switch", "delete"], "description": "Branch action (default: list)"},
+			"name": {"type": "string", "description": "Branch name (for create/switch/delete)"},
+			"start_point": {"type": "string", "description": "Start point for create (default: HEAD)"},`
+	case "git_clone":
+		props = `
+			"url": {"type": "string", "description": "Repository URL"},
+			"depth": {"type": "integer", "description": "Shallow clone depth"},
+			"branch": {"type": "string", "description": "Branch to check out"},`
+		required = `"url"`
+		pathProp = `
+			"path": {
+				"type": "string",
+				"description": "Target directory (default: derived from URL)"
+			}`
+	}
+
+	return json.RawMessage(fmt.Sprintf(`{
+		"type": "object",
+		"properties": {%s%s
+		},
+		"required": [%s]
+	}`, props, pathProp, required))
+}
+
+type gitParams struct {
+	Path        string   `json:"path"`
+	Staged      bool     `json:"staged"`
+	Ref         string   `json:"ref"`
+	Files       []string `json:"files"`
+	Message     string   `json:"message"`
+	All         bool     `json:"all"`
+	Remote      string   `json:"remote"`
+	Branch      string   `json:"branch"`
+	SetUpstream bool     `json:"set_upstream"`
+	Force       bool     `json:"force"`
+	Confirm     bool     `json:"confirm"`
+	Rebase      bool     `json:"rebase"`
+	Action      string   `json:"action"`
+	Name        string   `json:"name"`
+	StartPoint  string   `json:"start_point"`
+	URL         string   `json:"url"`
+	Depth       int      `json:"depth"`
+}
+
+func (g *Git) Execute(ctx context.Context, params json.RawMessage) (*Result, error) {
+	var p gitParams
+	if len(params) > 0 {
+		if err := json.Unmarshal(params, &p); err != nil {
+			return &Result{Content: fmt.Sprintf("Invalid parameters: %v", err), IsError: true}, nil
+		}
+	}
+
+	if err := checkGitArgs(p); err != nil {
+		return &Result{Content: err.Error(), IsError: true}, nil
+	}
+
+	dir := g.workDir
+	if p.Path != "" && g.name != "git_clone" {
+		dir = p.Path
+		if !filepath.IsAbs(dir) {
+			dir = filepath.Join(g.workDir, dir)
+		}
+	}
+
+	switch g.name {
+	case "git_status":
+		return g.run(ctx, dir, "status", "--short", "--branch")
+
+	case "git_diff":
+		args := []string{"diff", "--stat", "--patch"}
+		if p.Staged {
+			args = append(args, "--cached")
+		}
+		if p.Ref != "" {
+			args = append(args, p.Ref)
+		}
+		if len(p.Files) > 0 {
+			args = append(append(args, "--"), p.Files...)
+		}
+		res, err := g.run(ctx, dir, args...)
+		if err == nil && !res.IsError && strings.TrimSpace(res.Content) == "" {
+			res.Content = "No changes"
+		}
+		return res, err
+
+	case "git_commit":
+		if strings.TrimSpace(p.Message) == "" {
+			return &Result{Content: "message is required", IsError: true}, nil
+		}
+		switch {
+		case p.All:
+			if res, err := g.run(ctx, dir, "add", "--all"); err != nil || res.IsError {
+				return res, err
+			}
+		case len(p.Files) > 0:
+			if res, err := g.run(ctx, dir, append([]string{"add", "--"}, p.Files...)...); err != nil || res.IsError {
+				return res, err
+			}
+		}
+		return g.run(ctx, dir, "commit", "-m", p.Message)
+
+	case "git_push":
+		return g.push(ctx, dir, p)
+
+	case "git_pull":
+		args := []string{"pull"}
+		if p.Rebase {
+			args = append(args, "--rebase")
+		}
+		if p.Remote != "" || p.Branch != "" {
+			args = append(args, defaultString(p.Remote, "origin"))
+			if p.Branch != "" {
+				args = append(args, p.Branch)
+			}
+		}
+		return g.run(ctx, dir, args...)
+
+	case "git_branch":
+		switch p.Action {
+		case "", "list":
+			return g.run(ctx, dir, "branch", "--all", "--verbose")
+		case "create":
+			if p.Name == "" {
+				return &Result{Content: "name is required", IsError: true}, nil
+			}
+			args := []string{"switch", "-c", p.Name}
+			if p.StartPoint != "" {
+				args = append(args, p.StartPoint)
+			}
+			return g.run(ctx, dir, args...)
+		case "switch":
+			if p.Name == "" {
+				return &Result{Content: "name is required", IsError: true}, nil
+			}
+			return g.run(ctx, dir, "switch", p.Name)
+		case "delete":
+			if p.Name == "" {
+				return &Result{Content: "name is required", IsError: true}, nil
+			}
+			if isProtectedBranch(ctx, p.Name) {
+				return &Result{Content: fmt.Sprintf("Branch %s is protected and cannot be deleted", p.Name), IsError: true}, nil
+			}
+			return g.run(ctx, dir, "branch", "-d", p.Name)
+		default:
+			return &Result{Content: fmt.Sprintf("Unknown action: %s", p.Action), IsError: true}, nil
+		}
+
+	case "git_clone":
+		if p.URL == "" {
+			return &Result{Content: "url is required", IsError: true}, nil
+		}
+		args := []string{"clone"}
+		if p.Depth > 0 {
+			args = append(args, "--depth", fmt.Sprintf("%d", p.Depth))
+		}
+		if p.Branch != "" {
+			args = append(args, "--branch", p.Branch)
+		}
+		args = append(args, "--", p.URL)
+		if p.Path != "" {
+			args = append(args, p.Path)
+		}
+		return g.run(ctx, g.workDir, args...)
+	}
+
+	return &Result{Content: fmt.Sprintf("Unknown git tool: %s", g.name), IsError: true}, nil
+}
+
+// checkGitArgs refuses parameters that git would parse as options, e.g. a
+// remote of --upload-pack=<cmd> or a ref of --output=<file>.
+func checkGitArgs(p gitParams) error {
+	for _, arg := range []struct{ name, value string }{
+		{"remote", p.Remote},
+		{"branch", p.Branch},
+		{"ref", p.Ref},
+		{"name", p.Name},
+		{"start_point", p.StartPoint},
+	} {
+		if strings.HasPrefix(arg.value, "-") {
+			return fmt.Errorf("%s cannot start with '-'", arg.name)
+		}
+	}
+	return nil
+}
+
+func (g *Git) push(ctx context.Context, dir string, p gitParams) (*Result, error) {
+	refspec := p.Branch
+	if refspec == "" {
+		refspec = "HEAD"
+	}
+	// A leading + force-pushes the refspec, like force does
+	force := p.Force
+	if strings.HasPrefix(refspec, "+") {
+		refspec, force = refspec[1:], true
+	}
+	src, dst, hasDst := strings.Cut(refspec, ":")
+	if !hasDst || dst == "" {
+		dst = src
+	}
+	if dst == "HEAD" {
+		out, err := g.output(ctx, dir, "rev-parse", "--abbrev-ref", "HEAD")
+		if err != nil {
+			return &Result{Content: fmt.Sprintf("Failed to determine current branch: %v", err), IsError: true}, nil
+		}
+		dst = out
+	}
+	branch := strings.TrimPrefix(dst, "refs/heads/")
+
+	if isProtectedBranch(ctx, branch) {
+		return &Result{Content: fmt.Sprintf("Branch %s is protected; push to a feature branch and open a pull request instead", branch), IsError: true}, nil
+	}
+	if force && !p.Confirm {
+		return &Result{
+			Content: fmt.Sprintf("Force pushing %s rewrites remote history. Ask the user to confirm, then call git_push again with confirm=true.", branch),
+			IsError: true,
+		}, nil
+	}
+
+	args := []string{"push"}
+	if p.SetUpstream {
+		args = append(args, "-u")
+	}
+	if force {
+		args = append(args, "--force-with-lease")
+	}
+	args = append(args, defaultString(p.Remote, "origin"), refspec)
+	return g.run(ctx, dir, args...)
+}
+
+// run executes git and returns its combined output as a Result.
+func (g *Git) run(ctx context.Context, dir string, args ...string) (*Result, error) {
+	ctx, cancel := context.WithTimeout(ctx, 2*time.Minute)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, "git", args...)
+	cmd.Dir = dir
+	cmd.Env = append(os.Environ(), "GIT_TERMINAL_PROMPT=0")
+
+	var out bytes.Buffer
+	cmd.Stdout = &out
+	cmd.Stderr = &out
+	err := cmd.Run()
+
+	output := strings.TrimRight(out.String(), "\n")
+	if len(output) > 30000 {
+		output = output[:30000] + "\n... (output truncated)"
+	}
+
+	if ctx.Err() == context.DeadlineExceeded {
+		return &Result{Content: "git timed out\n" + output, IsError: true}, nil
+	}
+	if err != nil {
+		return &Result{Content: fmt.Sprintf("git %s failed: %v\n%s", args[0], err, output), IsError: true}, nil
+	}
+	if output == "" {
+		output = fmt.Sprintf("git %s: done", args[0])
+	}
+	return &Result{Content: output}, nil
+}
+
+// output runs git and returns trimmed stdout.
+func (g *Git) output(ctx context.Context, dir string, args ...string) (string, error) {
+	cmd := exec.CommandContext(ctx, "git", args...)
+	cmd.Dir = dir
+	out, err := cmd.Output()
+	return strings.TrimSpace(string(out)), err
+}
+
+// isProtectedBranch checks the agent's git.protected_branches skill config.
+func isProtectedBranch(ctx context.Context, branch string) bool {
+	for _, b := range strings.Split(SkillConfigValue(ctx, "git", "protected_branches"), ",") {
+		if b = strings.TrimSpace(b); b != "" && b == branch {
+			return true
+		}
+	}
+	return false
+}
+
+func defaultString(s, def string) string {
+	if s == "" {
+		return def
+	}
+	return s
+}
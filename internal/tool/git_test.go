@@ -0,0 +1,96 @@
+package tool
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestGitTools(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+
+	root := t.TempDir()
+	remote := filepath.Join(root, "remote.git")
+	repo := filepath.Join(root, "repo")
+	for _, args := range [][]string{
+		{"init", "--bare", remote},
+		{"init", "-b", "main", repo},
+		{"-C", repo, "config", "user.email", "test@example.com"},
+		{"-C", repo, "config", "user.name", "Test"},
+		{"-C", repo, "remote", "add", "origin", remote},
+	} {
+		if out, err := exec.Command("git", args...).CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+	}
+	if err := os.WriteFile(filepath.Join(repo, "a.txt"), []byte("hello\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx := WithSkillConfig(context.Background(), map[string]map[string]string{
+		"git": {"protected_branches": "main, release"},
+	})
+	call := func(name, params string) *Result {
+		t.Helper()
+		res, err := NewGit(name, repo).Execute(ctx, json.RawMessage(params))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return res
+	}
+
+	if res := call("git_status", `{}`); !strings.Contains(res.Content, "?? a.txt") {
+		t.Errorf("status: %s", res.Content)
+	}
+	if res := call("git_commit", `{"message":"initial","all":true}`); res.IsError {
+		t.Fatalf("commit: %s", res.Content)
+	}
+
+	if res := call("git_push", `{}`); !res.IsError || !strings.Contains(res.Content, "protected") {
+		t.Errorf("expected push to protected main to be refused: %s", res.Content)
+	}
+
+	if res := call("git_branch", `{"action":"create","name":"feature"}`); res.IsError {
+		t.Fatalf("branch: %s", res.Content)
+	}
+	for _, branch := range []string{"feature:main", "HEAD:main", "feature:refs/heads/release", ":main", "+feature:main"} {
+		if res := call("git_push", `{"branch":"`+branch+`"}`); !res.IsError || !strings.Contains(res.Content, "protected") {
+			t.Errorf("expected push of %s to a protected branch to be refused: %s", branch, res.Content)
+		}
+	}
+	if res := call("git_push", `{"branch":"+feature"}`); !res.IsError || !strings.Contains(res.Content, "confirm=true") {
+		t.Errorf("expected +refspec to require confirmation: %s", res.Content)
+	}
+	if res := call("git_push", `{"branch":"--delete"}`); !res.IsError {
+		t.Errorf("expected option-like branch to be refused: %s", res.Content)
+	}
+	outFile := filepath.Join(root, "out.txt")
+	for _, c := range []struct{ tool, params string }{
+		{"git_pull", `{"remote":"--upload-pack=touch ` + outFile + `"}`},
+		{"git_pull", `{"branch":"--upload-pack=touch ` + outFile + `"}`},
+		{"git_diff", `{"ref":"--output=` + outFile + `"}`},
+		{"git_branch", `{"action":"create","name":"-D"}`},
+		{"git_branch", `{"action":"create","name":"x","start_point":"--orphan"}`},
+		{"git_branch", `{"action":"switch","name":"--detach"}`},
+	} {
+		if res := call(c.tool, c.params); !res.IsError || !strings.Contains(res.Content, "cannot start with '-'") {
+			t.Errorf("expected %s %s to be refused: %s", c.tool, c.params, res.Content)
+		}
+	}
+	if _, err := os.Stat(outFile); err == nil {
+		t.Error("option-like argument reached git")
+	}
+
+	if res := call("git_push", `{"force":true}`); !res.IsError || !strings.Contains(res.Content, "confirm=true") {
+		t.Errorf("expected force push to require confirmation: %s", res.Content)
+	}
+	if res := call("git_push", `{"set_upstream":true}`); res.IsError {
+		t.Errorf("push feature: %s", res.Content)
+	}
+}
@@ -0,0 +1,338 @@
+package tool
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+)
+
+// GitHub tools read their token from the agent's github.token skill config,
+// falling back to GITHUB_TOKEN / GH_TOKEN. github.api_url points them at
+// GitHub Enterprise.
+const githubSkill = "github"
+
+// GitHub calls the GitHub REST API. The same type backs gh_issue_create,
+// gh_issue_comment, gh_pr_create, gh_pr_review and gh_repo_search.
+type GitHub struct {
+	name    string
+	baseURL string
+	client  *http.Client
+}
+
+// NewGitHub creates the GitHub tool with the given name (e.g. "gh_pr_create").
+func NewGitHub(name string) *GitHub {
+	return NewGitHubWithURL(name, "https://api.github.com")
+}
+
+// NewGitHubWithURL creates a GitHub tool against a specific API base URL.
+func NewGitHubWithURL(name, baseURL string) *GitHub {
+	return &GitHub{
+		name:    name,
+		baseURL: strings.TrimRight(baseURL, "/"),
+		client:  &http.Client{Timeout: 30 * time.Second},
+	}
+}
+
+func (g *GitHub) Name() string {
+	return g.name
+}
+
+func (g *GitHub) Description() string {
+	switch g.name {
+	case "gh_issue_create":
+		return "Create a GitHub issue in a repository (owner/name)."
+	case "gh_issue_comment":
+		return "Comment on a GitHub issue or pull request."
+	case "gh_pr_create":
+		return "Open a GitHub pull request from a pushed branch (head) into a base branch."
+	case "gh_pr_review":
+		return "Review a GitHub pull request: APPROVE, REQUEST_CHANGES, or COMMENT."
+	case "gh_repo_search":
+		return "Search GitHub repositories, code, or issues/PRs using GitHub search syntax."
+	}
+	return "Call the GitHub API."
+}
+
+func (g *GitHub) Schema() json.RawMessage {
+	repoProp := `
+			"repo": {"type": "string", "description": "Repository as owner/name"},`
+
+	var props, required string
+	switch g.name {
+	case "gh_issue_create":
+		props = repoProp + `
+			"title": {"type": "string", "description": "Issue title"},
+			"body": {"type": "string", "description": "Issue body (markdown)"},
+			"labels": {"type": "array", "items": {"type": "string"}, "description": "Labels to apply"}`
+		required = `"repo", "title"`
+	case "gh_issue_comment":
+		props = repoProp + `
+			"number": {"type": "integer", "description": "Issue or pull request number"},
+			"body": {"type": "string", "description": "Comment body (markdown)"}`
+		required = `"repo", "number", "body"`
+	case "gh_pr_create":
+		props = repoProp + `
+			"title": {"type": "string", "description": "Pull request title"},
+			"head": {"type": "string", "description": "Branch with the changes"},
+			"base": {"type": "string", "description": "Branch to merge into (default: repository default branch)"},
+			"body": {"type": "string", "description": "Pull request description (markdown)"},
+			"draft": {"type": "boolean", "description": "Open as draft"}`
+		required = `"repo", "title", "head"`
+	case "gh_pr_review":
+		props = repoProp + `
+			"number": {"type": "integer", "description": "Pull request number"},
+			"event": {"type": "string", "enum": ["APPROVE", "REQUEST_CHANGES", "COMMENT"], "description": "Review action"},
+			"body": {"type": "string", "description": "Review comment"}`
+		required = `"repo", "number", "event"`
+	case "gh_repo_search":
+		props = `
+			"query": {"type": "string", "description": "GitHub search query (e.g. \"language:go stars:>100 cli\")"},
+			"type": {"type": "string", "enum": ["repositories", "code", "issues"], "description": "What to search (default: repositories)"},
+			"limit": {"type": "integer", "description": "Maximum results (default: 10)"}`
+		required = `"query"`
+	}
+
+	return json.RawMessage(fmt.Sprintf(`{
+		"type": "object",
+		"properties": {%s
+		},
+		"required": [%s]
+	}`, props, required))
+}
+
+type githubParams struct {
+	Repo   string   `json:"repo"`
+	Title  string   `json:"title"`
+	Body   string   `json:"body"`
+	Labels []string `json:"labels"`
+	Number int      `json:"number"`
+	Head   string   `json:"head"`
+	Base   string   `json:"base"`
+	Draft  bool     `json:"draft"`
+	Event  string   `json:"event"`
+	Query  string   `json:"query"`
+	Type   string   `json:"type"`
+	Limit  int      `json:"limit"`
+}
+
+func (g *GitHub) Execute(ctx context.Context, params json.RawMessage) (*Result, error) {
+	var p githubParams
+	if err := json.Unmarshal(params, &p); err != nil {
+		return &Result{Content: fmt.Sprintf("Invalid parameters: %v", err), IsError: true}, nil
+	}
+	if g.name != "gh_repo_search" && strings.Count(p.Repo, "/") != 1 {
+		return &Result{Content: "repo must be in owner/name form", IsError: true}, nil
+	}
+
+	switch g.name {
+	case "gh_issue_create":
+		if p.Title == "" {
+			return &Result{Content: "title is required", IsError: true}, nil
+		}
+		var issue struct {
+			Number  int    `json:"number"`
+			HTMLURL string `json:"html_url"`
+		}
+		payload := map[string]any{"title": p.Title, "body": p.Body}
+		if len(p.Labels) > 0 {
+			payload["labels"] = p.Labels
+		}
+		if err := g.call(ctx, "POST", "/repos/"+p.Repo+"/issues", payload, &issue); err != nil {
+			return &Result{Content: fmt.Sprintf("Failed to create issue: %v", err), IsError: true}, nil
+		}
+		return &Result{Content: fmt.Sprintf("Created issue #%d: %s", issue.Number, issue.HTMLURL)}, nil
+
+	case "gh_issue_comment":
+		if p.Number <= 0 || p.Body == "" {
+			return &Result{Content: "number and body are required", IsError: true}, nil
+		}
+		var comment struct {
+			HTMLURL string `json:"html_url"`
+		}
+		path := fmt.Sprintf("/repos/%s/issues/%d/comments", p.Repo, p.Number)
+		if err := g.call(ctx, "POST", path, map[string]any{"body": p.Body}, &comment); err != nil {
+			return &Result{Content: fmt.Sprintf("Failed to comment: %v", err), IsError: true}, nil
+		}
+		return &Result{Content: fmt.Sprintf("Commented on #%d: %s", p.Number, comment.HTMLURL)}, nil
+
+	case "gh_pr_create":
+		if p.Title == "" || p.Head == "" {
+			return &Result{Content: "title and head are required", IsError: true}, nil
+		}
+		base := p.Base
+		if base == "" {
+			var repo struct {
+				DefaultBranch string `json:"default_branch"`
+			}
+			if err := g.call(ctx, "GET", "/repos/"+p.Repo, nil, &repo); err != nil {
+				return &Result{Content: fmt.Sprintf("Failed to look up default branch: %v", err), IsError: true}, nil
+			}
+			base = repo.DefaultBranch
+		}
+		var pr struct {
+			Number  int    `json:"number"`
+			HTMLURL string `json:"html_url"`
+		}
+		payload := map[string]any{"title": p.Title, "head": p.Head, "base": base, "body": p.Body, "draft": p.Draft}
+		if err := g.call(ctx, "POST", "/repos/"+p.Repo+"/pulls", payload, &pr); err != nil {
+			return &Result{Content: fmt.Sprintf("Failed to create pull request: %v", err), IsError: true}, nil
+		}
+		return &Result{Content: fmt.Sprintf("Opened PR #%d (%s -> %s): %s", pr.Number, p.Head, base, pr.HTMLURL)}, nil
+
+	case "gh_pr_review":
+		event := strings.ToUpper(p.Event)
+		switch event {
+		case "APPROVE", "REQUEST_CHANGES", "COMMENT":
+		default:
+			return &Result{Content: "event must be APPROVE, REQUEST_CHANGES, or COMMENT", IsError: true}, nil
+		}
+		if p.Number <= 0 {
+			return &Result{Content: "number is required", IsError: true}, nil
+		}
+		if event != "APPROVE" && p.Body == "" {
+			return &Result{Content: "body is required for REQUEST_CHANGES and COMMENT reviews", IsError: true}, nil
+		}
+		var review struct {
+			HTMLURL string `json:"html_url"`
+		}
+		path := fmt.Sprintf("/repos/%s/pulls/%d/reviews", p.Repo, p.Number)
+		if err := g.call(ctx, "POST", path, map[string]any{"event": event, "body": p.Body}, &review); err != nil {
+			return &Result{Content: fmt.Sprintf("Failed to review: %v", err), IsError: true}, nil
+		}
+		return &Result{Content: fmt.Sprintf("Submitted %s review on #%d: %s", event, p.Number, review.HTMLURL)}, nil
+
+	case "gh_repo_search":
+		return g.search(ctx, p)
+	}
+
+	return &Result{Content: fmt.Sprintf("Unknown GitHub tool: %s", g.name), IsError: true}, nil
+}
+
+func (g *GitHub) search(ctx context.Context, p githubParams) (*Result, error) {
+	if p.Query == "" {
+		return &Result{Content: "query is required", IsError: true}, nil
+	}
+	kind := p.Type
+	if kind == "" {
+		kind = "repositories"
+	}
+	if kind != "repositories" && kind != "code" && kind != "issues" {
+		return &Result{Content: fmt.Sprintf("Unknown search type: %s", kind), IsError: true}, nil
+	}
+	limit := p.Limit
+	if limit <= 0 || limit > 50 {
+		limit = 10
+	}
+
+	var resp struct {
+		TotalCount int `json:"total_count"`
+		Items      []struct {
+			FullName    string `json:"full_name"`
+			Description string `json:"description"`
+			Stars       int    `json:"stargazers_count"`
+			Title       string `json:"title"`
+			Number      int    `json:"number"`
+			State       string `json:"state"`
+			Path        string `json:"path"`
+			HTMLURL     string `json:"html_url"`
+			Repository  struct {
+				FullName string `json:"full_name"`
+			} `json:"repository"`
+		} `json:"items"`
+	}
+	path := fmt.Sprintf("/search/%s?q=%s&per_page=%d", kind, url.QueryEscape(p.Query), limit)
+	if err := g.call(ctx, "GET", path, nil, &resp); err != nil {
+		return &Result{Content: fmt.Sprintf("Search failed: %v", err), IsError: true}, nil
+	}
+
+	var sb strings.Builder
+	_, _ = fmt.Fprintf(&sb, "%d results for %q (%s)\n\n", resp.TotalCount, p.Query, kind)
+	for i, item := range resp.Items {
+		switch kind {
+		case "repositories":
+			_, _ = fmt.Fprintf(&sb, "%d. %s (★%d)\n   %s\n   %s\n", i+1, item.FullName, item.Stars, item.Description, item.HTMLURL)
+		case "code":
+			_, _ = fmt.Fprintf(&sb, "%d. %s: %s\n   %s\n", i+1, item.Repository.FullName, item.Path, item.HTMLURL)
+		case "issues":
+			_, _ = fmt.Fprintf(&sb, "%d. #%d %s [%s]\n   %s\n", i+1, item.Number, item.Title, item.State, item.HTMLURL)
+		}
+	}
+	return &Result{Content: sb.String()}, nil
+}
+
+// call performs an authenticated GitHub API request and decodes the JSON
+// response into out.
+func (g *GitHub) call(ctx context.Context, method, path string, payload any, out any) error {
+	token := githubToken(ctx)
+	if token == "" {
+		return fmt.Errorf("no GitHub token configured (klaw agent config set <agent> github.token=...)")
+	}
+
+	base := g.baseURL
+	if u := SkillConfigValue(ctx, githubSkill, "api_url"); u != "" {
+		base = strings.TrimRight(u, "/")
+	}
+
+	var body io.Reader
+	if payload != nil {
+		data, err := json.Marshal(payload)
+		if err != nil {
+			return err
+		}
+		body = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, base+path, body)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	req.Header.Set("Accept", "application/vnd.github+json")
+	req.Header.Set("X-GitHub-Api-Version", "2022-11-28")
+	req.Header.Set("User-Agent", "Klaw/1.0")
+	if payload != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := g.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, 4*1024*1024))
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode >= 300 {
+		var apiErr struct {
+			Message string `json:"message"`
+		}
+		_ = json.Unmarshal(data, &apiErr)
+		if apiErr.Message == "" {
+			apiErr.Message = truncateString(string(data), 200)
+		}
+		return fmt.Errorf("HTTP %d: %s", resp.StatusCode, apiErr.Message)
+	}
+	if out == nil {
+		return nil
+	}
+	return json.Unmarshal(data, out)
+}
+
+func githubToken(ctx context.Context) string {
+	if token := SkillConfigValue(ctx, githubSkill, "token"); token != "" {
+		return token
+	}
+	if token := os.Getenv("GITHUB_TOKEN"); token != "" {
+		return token
+	}
+	return os.Getenv("GH_TOKEN")
+}
@@ -0,0 +1,52 @@
+package tool
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestGitHub_PRCreateUsesDefaultBranch(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer tok" {
+			t.Errorf("missing token: %q", r.Header.Get("Authorization"))
+		}
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/repos/acme/app":
+			_, _ = w.Write([]byte(`{"default_branch":"trunk"}`))
+		case r.Method == http.MethodPost && r.URL.Path == "/repos/acme/app/pulls":
+			body, _ := io.ReadAll(r.Body)
+			if !strings.Contains(string(body), `"base":"trunk"`) {
+				t.Errorf("expected base trunk, got %s", body)
+			}
+			w.WriteHeader(http.StatusCreated)
+			_, _ = w.Write([]byte(`{"number":7,"html_url":"https://github.com/acme/app/pull/7"}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"message":"Not Found"}`))
+		}
+	}))
+	defer srv.Close()
+
+	ctx := WithSkillConfig(context.Background(), map[string]map[string]string{
+		"github": {"token": "tok"},
+	})
+	gh := NewGitHubWithURL("gh_pr_create", srv.URL)
+
+	res, err := gh.Execute(ctx, json.RawMessage(`{"repo":"acme/app","title":"Fix","head":"fix-bug"}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.IsError || !strings.Contains(res.Content, "Opened PR #7 (fix-bug -> trunk)") {
+		t.Errorf("unexpected result: %s", res.Content)
+	}
+
+	res, _ = gh.Execute(ctx, json.RawMessage(`{"repo":"acme/missing","title":"Fix","head":"x","base":"main"}`))
+	if !res.IsError || !strings.Contains(res.Content, "HTTP 404: Not Found") {
+		t.Errorf("expected API error, got: %s", res.Content)
+	}
+}
@@ -0,0 +1,316 @@
+package tool
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/eachlabs/klaw/internal/config"
+)
+
+// HTTPConfig controls the HTTP request tools.
+type HTTPConfig struct {
+	AllowedDomains   []string // empty = all domains allowed
+	DeniedDomains    []string
+	Timeout          time.Duration
+	MaxResponseBytes int
+}
+
+// loadHTTPConfig reads the [tools.http] section of the klaw config.
+func loadHTTPConfig() HTTPConfig {
+	cfg, err := config.Load()
+	if err != nil {
+		return HTTPConfig{}
+	}
+	h := cfg.Tools.HTTP
+	return HTTPConfig{
+		AllowedDomains:   h.AllowedDomains,
+		DeniedDomains:    h.DeniedDomains,
+		Timeout:          time.Duration(h.TimeoutSeconds) * time.Second,
+		MaxResponseBytes: h.MaxResponseBytes,
+	}
+}
+
+// HTTPRequest makes HTTP requests. The same type backs http_get,
+// http_post, and the generic http_request tool.
+type HTTPRequest struct {
+	name   string
+	method string // fixed method, or "" to take it from params
+	config HTTPConfig
+	client *http.Client
+}
+
+// NewHTTPGet creates the http_get tool.
+func NewHTTPGet() *HTTPRequest {
+	return NewHTTPRequestTool("http_get", http.MethodGet, loadHTTPConfig())
+}
+
+// NewHTTPPost creates the http_post tool.
+func NewHTTPPost() *HTTPRequest {
+	return NewHTTPRequestTool("http_post", http.MethodPost, loadHTTPConfig())
+}
+
+// NewHTTPRequest creates the generic http_request tool.
+func NewHTTPRequest() *HTTPRequest {
+	return NewHTTPRequestTool("http_request", "", loadHTTPConfig())
+}
+
+// NewHTTPRequestTool creates an HTTP tool with an explicit config.
+func NewHTTPRequestTool(name, method string, cfg HTTPConfig) *HTTPRequest {
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = 30 * time.Second
+	}
+	if cfg.MaxResponseBytes <= 0 {
+		cfg.MaxResponseBytes = 1024 * 1024
+	}
+	t := &HTTPRequest{
+		name:   name,
+		method: method,
+		config: cfg,
+	}
+	t.client = &http.Client{Timeout: cfg.Timeout, CheckRedirect: t.checkRedirect}
+	return t
+}
+
+// maxHTTPRedirects bounds the redirects an HTTP tool follows.
+const maxHTTPRedirects = 5
+
+// checkRedirect applies the domain policy and the agent policy to every
+// hop, so an allowed host can't redirect to a denied one.
+func (t *HTTPRequest) checkRedirect(req *http.Request, via []*http.Request) error {
+	if len(via) > maxHTTPRedirects {
+		return fmt.Errorf("stopped after %d redirects", maxHTTPRedirects)
+	}
+	if req.URL.Scheme != "http" && req.URL.Scheme != "https" {
+		return fmt.Errorf("redirect to unsupported scheme %s", req.URL.Scheme)
+	}
+	if err := t.checkDomain(req.URL.Hostname()); err != nil {
+		return fmt.Errorf("redirect blocked: %w", err)
+	}
+	if reason := policyDomainReason(req.Context(), req.URL.Hostname()); reason != "" {
+		return fmt.Errorf("redirect blocked by agent policy: %s", reason)
+	}
+	return nil
+}
+
+func (t *HTTPRequest) Name() string {
+	return t.name
+}
+
+func (t *HTTPRequest) Description() string {
+	switch t.method {
+	case http.MethodGet:
+		return `Make an HTTP GET request to an API or URL. Returns status, headers, and body.
+JSON responses are pretty-printed.`
+	case http.MethodPost:
+		return `Make an HTTP POST request. Send a JSON object in "json" or a raw string in "body".
+Returns status, headers, and body. JSON responses are pretty-printed.`
+	default:
+		return `Make an HTTP request with any method (GET, POST, PUT, PATCH, DELETE, HEAD).
+Send a JSON object in "json" or a raw string in "body".
+Returns status, headers, and body. JSON responses are pretty-printed.`
+	}
+}
+
+func (t *HTTPRequest) Schema() json.RawMessage {
+	methodProp := ""
+	required := `["url"]`
+	if t.method == "" {
+		methodProp = `
+			"method": {
+				"type": "string",
+				"description": "HTTP method (GET, POST, PUT, PATCH, DELETE, HEAD)"
+			},`
+		required = `["url", "method"]`
+	}
+	bodyProps := ""
+	if t.method != http.MethodGet {
+		bodyProps = `
+			"body": {
+				"type": "string",
+				"description": "Raw request body"
+			},
+			"json": {
+				"type": "object",
+				"description": "JSON request body (sets Content-Type: application/json)"
+			},`
+	}
+	return json.RawMessage(fmt.Sprintf(`{
+		"type": "object",
+		"properties": {
+			"url": {
+				"type": "string",
+				"description": "The URL to request"
+			},%s%s
+			"headers": {
+				"type": "object",
+				"description": "Request headers",
+				"additionalProperties": {"type": "string"}
+			},
+			"timeout": {
+				"type": "integer",
+				"description": "Timeout in seconds (default: 30)"
+			}
+		},
+		"required": %s
+	}`, methodProp, bodyProps, required))
+}
+
+type httpRequestParams struct {
+	URL     string            `json:"url"`
+	Method  string            `json:"method"`
+	Headers map[string]string `json:"headers"`
+	Body    string            `json:"body"`
+	JSON    json.RawMessage   `json:"json"`
+	Timeout int               `json:"timeout"`
+}
+
+func (t *HTTPRequest) Execute(ctx context.Context, params json.RawMessage) (*Result, error) {
+	var p httpRequestParams
+	if err := json.Unmarshal(params, &p); err != nil {
+		return &Result{Content: fmt.Sprintf("Invalid parameters: %v", err), IsError: true}, nil
+	}
+
+	method := t.method
+	if method == "" {
+		method = strings.ToUpper(strings.TrimSpace(p.Method))
+	}
+	switch method {
+	case http.MethodGet, http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete, http.MethodHead:
+	default:
+		return &Result{Content: fmt.Sprintf("Unsupported method: %q", p.Method), IsError: true}, nil
+	}
+
+	u, err := url.Parse(p.URL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return &Result{Content: "URL must be an absolute http:// or https:// URL", IsError: true}, nil
+	}
+	if err := t.checkDomain(u.Hostname()); err != nil {
+		return &Result{Content: err.Error(), IsError: true}, nil
+	}
+
+	var body io.Reader
+	contentType := ""
+	switch {
+	case len(p.JSON) > 0 && string(p.JSON) != "null":
+		body = bytes.NewReader(p.JSON)
+		contentType = "application/json"
+	case p.Body != "":
+		body = strings.NewReader(p.Body)
+	}
+
+	if p.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, time.Duration(p.Timeout)*time.Second)
+		defer cancel()
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, u.String(), body)
+	if err != nil {
+		return &Result{Content: fmt.Sprintf("Failed to create request: %v", err), IsError: true}, nil
+	}
+	req.Header.Set("User-Agent", "Klaw/1.0")
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+	for k, v := range p.Headers {
+		req.Header.Set(k, v)
+	}
+
+	start := time.Now()
+	resp, err := t.client.Do(req)
+	if err != nil {
+		return &Result{Content: fmt.Sprintf("Request failed: %v", err), IsError: true}, nil
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, int64(t.config.MaxResponseBytes)+1))
+	if err != nil {
+		return &Result{Content: fmt.Sprintf("Failed to read response: %v", err), IsError: true}, nil
+	}
+	truncated := len(data) > t.config.MaxResponseBytes
+	if truncated {
+		data = data[:t.config.MaxResponseBytes]
+	}
+
+	var sb strings.Builder
+	_, _ = fmt.Fprintf(&sb, "%s %s\n", method, u.String())
+	_, _ = fmt.Fprintf(&sb, "Status: %s (%dms)\n", resp.Status, time.Since(start).Milliseconds())
+	sb.WriteString(formatResponseHeaders(resp.Header))
+	sb.WriteString("\n")
+	sb.WriteString(formatResponseBody(resp.Header.Get("Content-Type"), data))
+	if truncated {
+		_, _ = fmt.Fprintf(&sb, "\n... (response truncated at %d bytes)", t.config.MaxResponseBytes)
+	}
+
+	output := sb.String()
+	if len(output) > 30000 {
+		output = output[:30000] + "\n... (output truncated)"
+	}
+
+	return &Result{Content: output, IsError: resp.StatusCode >= 400}, nil
+}
+
+// checkDomain enforces the configured allow/deny lists.
+func (t *HTTPRequest) checkDomain(host string) error {
+	for _, d := range t.config.DeniedDomains {
+		if domainMatches(host, d) {
+			return fmt.Errorf("domain %s is denied by policy", host)
+		}
+	}
+	if len(t.config.AllowedDomains) == 0 {
+		return nil
+	}
+	for _, d := range t.config.AllowedDomains {
+		if domainMatches(host, d) {
+			return nil
+		}
+	}
+	return fmt.Errorf("domain %s is not in the allowed domains list", host)
+}
+
+// domainMatches reports whether host equals pattern or is a subdomain of it.
+// A leading "*." in pattern is accepted and means the same thing.
+func domainMatches(host, pattern string) bool {
+	host = strings.ToLower(strings.TrimSuffix(host, "."))
+	pattern = strings.ToLower(strings.TrimPrefix(strings.TrimSpace(pattern), "*."))
+	if pattern == "" {
+		return false
+	}
+	return host == pattern || strings.HasSuffix(host, "."+pattern)
+}
+
+func formatResponseHeaders(h http.Header) string {
+	keys := make([]string, 0, len(h))
+	for k := range h {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var sb strings.Builder
+	for _, k := range keys {
+		_, _ = fmt.Fprintf(&sb, "%s: %s\n", k, strings.Join(h[k], ", "))
+	}
+	return sb.String()
+}
+
+// formatResponseBody pretty-prints JSON bodies and returns others as text.
+func formatResponseBody(contentType string, data []byte) string {
+	if len(data) == 0 {
+		return "(empty body)"
+	}
+	if strings.Contains(contentType, "json") || json.Valid(data) {
+		var buf bytes.Buffer
+		if err := json.Indent(&buf, data, "", "  "); err == nil {
+			return buf.String()
+		}
+	}
+	return string(data)
+}
@@ -0,0 +1,134 @@
+package tool
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestHTTPRequest_PostJSON(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		if r.Method != http.MethodPost || r.Header.Get("Content-Type") != "application/json" {
+			t.Errorf("unexpected request: %s %s", r.Method, r.Header.Get("Content-Type"))
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"echo":` + string(body) + `}`))
+	}))
+	defer srv.Close()
+
+	h := NewHTTPRequestTool("http_post", http.MethodPost, HTTPConfig{})
+	res, err := h.Execute(context.Background(), json.RawMessage(`{"url":"`+srv.URL+`","json":{"a":1}}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.IsError {
+		t.Fatalf("unexpected error: %s", res.Content)
+	}
+	if !strings.Contains(res.Content, "\"echo\": {\n    \"a\": 1\n  }") {
+		t.Errorf("expected pretty-printed JSON, got:\n%s", res.Content)
+	}
+}
+
+func TestHTTPRequest_DomainPolicy(t *testing.T) {
+	h := NewHTTPRequestTool("http_get", http.MethodGet, HTTPConfig{
+		AllowedDomains: []string{"example.com"},
+		DeniedDomains:  []string{"internal.example.com"},
+	})
+
+	tests := []struct {
+		host    string
+		allowed bool
+	}{
+		{"example.com", true},
+		{"api.example.com", true},
+		{"internal.example.com", false},
+		{"evil.com", false},
+		{"notexample.com", false},
+	}
+	for _, tt := range tests {
+		err := h.checkDomain(tt.host)
+		if (err == nil) != tt.allowed {
+			t.Errorf("checkDomain(%q) = %v, want allowed=%v", tt.host, err, tt.allowed)
+		}
+	}
+}
+
+func TestHTTPRequest_SizeCap(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(strings.Repeat("x", 100)))
+	}))
+	defer srv.Close()
+
+	h := NewHTTPRequestTool("http_get", http.MethodGet, HTTPConfig{MaxResponseBytes: 10})
+	res, _ := h.Execute(context.Background(), json.RawMessage(`{"url":"`+srv.URL+`"}`))
+	if !strings.Contains(res.Content, "response truncated at 10 bytes") {
+		t.Errorf("expected truncation note, got:\n%s", res.Content)
+	}
+}
+
+func TestHTTPRequest_RedirectPolicy(t *testing.T) {
+	denied := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Error("request reached the denied host")
+	}))
+	defer denied.Close()
+	allowed := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/loop":
+			http.Redirect(w, r, "/loop", http.StatusFound)
+		default:
+			http.Redirect(w, r, strings.Replace(denied.URL, "127.0.0.1", "localhost", 1), http.StatusFound)
+		}
+	}))
+	defer allowed.Close()
+
+	h := NewHTTPRequestTool("http_get", http.MethodGet, HTTPConfig{
+		AllowedDomains: []string{"127.0.0.1"},
+	})
+	res, _ := h.Execute(context.Background(), json.RawMessage(`{"url":"`+allowed.URL+`"}`))
+	if !res.IsError || !strings.Contains(res.Content, "redirect blocked") {
+		t.Errorf("expected redirect to a disallowed host to be blocked, got:\n%s", res.Content)
+	}
+	res, _ = h.Execute(context.Background(), json.RawMessage(`{"url":"`+allowed.URL+`/loop"}`))
+	if !res.IsError || !strings.Contains(res.Content, "redirects") {
+		t.Errorf("expected redirect loop to stop, got:\n%s", res.Content)
+	}
+}
+
+func TestRegistryWithPolicy_Redirects(t *testing.T) {
+	denied := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Error("request reached a host outside the agent policy")
+	}))
+	defer denied.Close()
+	allowed := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/robots.txt" {
+			http.NotFound(w, r)
+			return
+		}
+		http.Redirect(w, r, strings.Replace(denied.URL, "127.0.0.1", "localhost", 1), http.StatusFound)
+	}))
+	defer allowed.Close()
+
+	r := NewRegistry()
+	r.Register(NewHTTPRequestTool("http_get", http.MethodGet, HTTPConfig{}))
+	r.Register(NewWebFetch())
+	restricted, err := r.WithPolicy(Policy{AllowedDomains: []string{"127.0.0.1"}}, t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, name := range []string{"http_get", "web_fetch"} {
+		tl, _ := restricted.Get(name)
+		res, err := tl.Execute(context.Background(), json.RawMessage(`{"url":"`+allowed.URL+`/page"}`))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !res.IsError || !strings.Contains(res.Content, "redirect blocked by agent policy") {
+			t.Errorf("%s: expected redirect outside the allowed domains to be blocked, got:\n%s", name, res.Content)
+		}
+	}
+}
@@ -0,0 +1,386 @@
+package tool
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/eachlabs/klaw/internal/config"
+)
+
+// ImageGenerate reads optional overrides from the "eachlabs-image-generation"
+// skill config:
+//
+//	eachlabs-image-generation.api_key   defaults to the eachlabs provider key
+//	eachlabs-image-generation.model     default model (flux-1-1-pro)
+const imageSkill = "eachlabs-image-generation"
+
+const (
+	imageDefaultBaseURL = "https://api.eachlabs.ai/v1"
+	imageDefaultModel   = "flux-1-1-pro"
+	imageMaxBytes       = 20 << 20
+)
+
+// ImageConfig controls the image_generate tool.
+type ImageConfig struct {
+	APIKey       string
+	BaseURL      string
+	Model        string
+	OutputDir    string // generated images are saved here
+	PollInterval time.Duration
+	Timeout      time.Duration
+}
+
+// loadImageConfig reads the eachlabs provider settings and the workspace
+// directory from the klaw config.
+func loadImageConfig() ImageConfig {
+	cfg := ImageConfig{APIKey: os.Getenv("EACHLABS_API_KEY")}
+	c, err := config.Load()
+	if err != nil {
+		return cfg
+	}
+	if p, ok := c.Provider["eachlabs"]; ok && p.APIKey != "" && cfg.APIKey == "" {
+		cfg.APIKey = p.APIKey
+	}
+	cfg.OutputDir = filepath.Join(c.WorkspaceDir(), "images")
+	return cfg
+}
+
+// ImageGenerate creates images with EachLabs models.
+type ImageGenerate struct {
+	config ImageConfig
+	client *http.Client
+}
+
+// NewImageGenerate creates the image_generate tool from the klaw config.
+func NewImageGenerate() *ImageGenerate {
+	return NewImageGenerateTool(loadImageConfig())
+}
+
+// NewImageGenerateTool creates the image_generate tool with an explicit config.
+func NewImageGenerateTool(cfg ImageConfig) *ImageGenerate {
+	if cfg.BaseURL == "" {
+		cfg.BaseURL = imageDefaultBaseURL
+	}
+	cfg.BaseURL = strings.TrimRight(cfg.BaseURL, "/")
+	if cfg.Model == "" {
+		cfg.Model = imageDefaultModel
+	}
+	if cfg.OutputDir == "" {
+		cfg.OutputDir = filepath.Join(os.TempDir(), "klaw-images")
+	}
+	if cfg.PollInterval <= 0 {
+		cfg.PollInterval = 2 * time.Second
+	}
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = 5 * time.Minute
+	}
+	return &ImageGenerate{config: cfg, client: &http.Client{Timeout: 60 * time.Second}}
+}
+
+// Configured reports whether an EachLabs API key is available.
+func (t *ImageGenerate) Configured() bool {
+	return t.config.APIKey != ""
+}
+
+func (t *ImageGenerate) Name() string {
+	return "image_generate"
+}
+
+func (t *ImageGenerate) Description() string {
+	return `Generate images from a text prompt using EachLabs AI models.
+Images are saved to the workspace and shared in the conversation when the channel supports files.
+Write detailed prompts: subject, style, lighting, composition.`
+}
+
+func (t *ImageGenerate) Schema() json.RawMessage {
+	return json.RawMessage(`{
+		"type": "object",
+		"properties": {
+			"prompt": {
+				"type": "string",
+				"description": "Description of the image to generate"
+			},
+			"model": {
+				"type": "string",
+				"description": "EachLabs model slug (default: flux-1-1-pro)"
+			},
+			"aspect_ratio": {
+				"type": "string",
+				"description": "Aspect ratio, e.g. 1:1, 16:9, 9:16 (default: 1:1)"
+			},
+			"num_images": {
+				"type": "integer",
+				"description": "Number of images, 1-4 (default: 1)"
+			}
+		},
+		"required": ["prompt"]
+	}`)
+}
+
+type imageGenerateParams struct {
+	Prompt      string `json:"prompt"`
+	Model       string `json:"model"`
+	AspectRatio string `json:"aspect_ratio"`
+	NumImages   int    `json:"num_images"`
+}
+
+func (t *ImageGenerate) Execute(ctx context.Context, params json.RawMessage) (*Result, error) {
+	var p imageGenerateParams
+	if err := json.Unmarshal(params, &p); err != nil {
+		return &Result{Content: fmt.Sprintf("Invalid parameters: %v", err), IsError: true}, nil
+	}
+	if strings.TrimSpace(p.Prompt) == "" {
+		return &Result{Content: "prompt is required", IsError: true}, nil
+	}
+
+	apiKey := t.config.APIKey
+	if v := SkillConfigValue(ctx, imageSkill, "api_key"); v != "" {
+		apiKey = v
+	}
+	if apiKey == "" {
+		return &Result{Content: "EachLabs API key is not configured (set EACHLABS_API_KEY or [provider.eachlabs] api_key)", IsError: true}, nil
+	}
+
+	model := p.Model
+	if model == "" {
+		model = SkillConfigValue(ctx, imageSkill, "model")
+	}
+	if model == "" {
+		model = t.config.Model
+	}
+	aspectRatio := p.AspectRatio
+	if aspectRatio == "" {
+		aspectRatio = "1:1"
+	}
+	numImages := min(max(p.NumImages, 1), 4)
+
+	ctx, cancel := context.WithTimeout(ctx, t.config.Timeout)
+	defer cancel()
+
+	id, err := t.createPrediction(ctx, apiKey, model, map[string]any{
+		"prompt":       p.Prompt,
+		"aspect_ratio": aspectRatio,
+		"num_images":   numImages,
+	})
+	if err != nil {
+		return &Result{Content: fmt.Sprintf("Failed to start image generation: %v", err), IsError: true}, nil
+	}
+
+	urls, err := t.waitForPrediction(ctx, apiKey, id)
+	if err != nil {
+		return &Result{Content: fmt.Sprintf("Image generation failed: %v", err), IsError: true}, nil
+	}
+	if len(urls) == 0 {
+		return &Result{Content: "Image generation returned no images", IsError: true}, nil
+	}
+
+	if err := os.MkdirAll(t.config.OutputDir, 0755); err != nil {
+		return &Result{Content: fmt.Sprintf("Failed to create %s: %v", t.config.OutputDir, err), IsError: true}, nil
+	}
+
+	base := time.Now().Format("20060102-150405") + "-" + imageSlug(p.Prompt)
+	var sb strings.Builder
+	var attachments []Attachment
+	for i, u := range urls {
+		data, mimeType, err := t.download(ctx, u)
+		if err != nil {
+			_, _ = fmt.Fprintf(&sb, "Failed to download %s: %v\n", u, err)
+			continue
+		}
+		name := base + imageExt(mimeType)
+		if len(urls) > 1 {
+			name = fmt.Sprintf("%s-%d%s", base, i+1, imageExt(mimeType))
+		}
+		path := filepath.Join(t.config.OutputDir, name)
+		if err := os.WriteFile(path, data, 0644); err != nil {
+			_, _ = fmt.Fprintf(&sb, "Failed to save %s: %v\n", name, err)
+			continue
+		}
+		attachments = append(attachments, Attachment{Name: name, MimeType: mimeType, Data: data})
+		_, _ = fmt.Fprintf(&sb, "Saved %s (%d bytes)\nURL: %s\n", path, len(data), u)
+	}
+
+	if len(attachments) == 0 {
+		return &Result{Content: sb.String(), IsError: true}, nil
+	}
+	return &Result{
+		Content:     fmt.Sprintf("Generated %d image(s) with %s.\n%s", len(attachments), model, sb.String()),
+		Attachments: attachments,
+	}, nil
+}
+
+// createPrediction starts a prediction and returns its ID.
+func (t *ImageGenerate) createPrediction(ctx context.Context, apiKey, model string, input map[string]any) (string, error) {
+	body, err := json.Marshal(map[string]any{
+		"model":   model,
+		"version": "0.0.1",
+		"input":   input,
+	})
+	if err != nil {
+		return "", err
+	}
+
+	var resp struct {
+		Status       string `json:"status"`
+		Message      string `json:"message"`
+		PredictionID string `json:"predictionID"`
+	}
+	if err := t.call(ctx, http.MethodPost, t.config.BaseURL+"/prediction/", apiKey, body, &resp); err != nil {
+		return "", err
+	}
+	if resp.PredictionID == "" {
+		if resp.Message != "" {
+			return "", fmt.Errorf("%s", resp.Message)
+		}
+		return "", fmt.Errorf("no prediction ID in response (status %q)", resp.Status)
+	}
+	return resp.PredictionID, nil
+}
+
+// waitForPrediction polls a prediction until it finishes and returns the
+// output URLs.
+func (t *ImageGenerate) waitForPrediction(ctx context.Context, apiKey, id string) ([]string, error) {
+	for {
+		var resp struct {
+			Status string          `json:"status"`
+			Output json.RawMessage `json:"output"`
+			Logs   string          `json:"logs"`
+		}
+		if err := t.call(ctx, http.MethodGet, t.config.BaseURL+"/prediction/"+id, apiKey, nil, &resp); err != nil {
+			return nil, err
+		}
+
+		switch resp.Status {
+		case "success", "succeeded":
+			return predictionURLs(resp.Output), nil
+		case "error", "failed", "cancelled":
+			msg := resp.Status
+			if resp.Logs != "" {
+				msg += ": " + truncateString(resp.Logs, 500)
+			}
+			return nil, fmt.Errorf("%s", msg)
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, fmt.Errorf("timed out waiting for prediction %s", id)
+		case <-time.After(t.config.PollInterval):
+		}
+	}
+}
+
+func (t *ImageGenerate) call(ctx context.Context, method, url, apiKey string, body []byte, out any) error {
+	var reader io.Reader
+	if body != nil {
+		reader = bytes.NewReader(body)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, url, reader)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("X-API-Key", apiKey)
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := t.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("HTTP %d: %s", resp.StatusCode, truncateString(strings.TrimSpace(string(data)), 500))
+	}
+	if err := json.Unmarshal(data, out); err != nil {
+		return fmt.Errorf("invalid response: %w", err)
+	}
+	return nil
+}
+
+func (t *ImageGenerate) download(ctx context.Context, url string) ([]byte, string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, "", err
+	}
+	resp, err := t.client.Do(req)
+	if err != nil {
+		return nil, "", err
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode >= 300 {
+		return nil, "", fmt.Errorf("HTTP %d", resp.StatusCode)
+	}
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, imageMaxBytes+1))
+	if err != nil {
+		return nil, "", err
+	}
+	if len(data) > imageMaxBytes {
+		return nil, "", fmt.Errorf("image larger than %d bytes", imageMaxBytes)
+	}
+
+	mimeType, _, _ := mime.ParseMediaType(resp.Header.Get("Content-Type"))
+	if !strings.HasPrefix(mimeType, "image/") {
+		mimeType = http.DetectContentType(data)
+	}
+	return data, mimeType, nil
+}
+
+// predictionURLs extracts image URLs from a prediction output, which is
+// either a single URL or a list of URLs.
+func predictionURLs(output json.RawMessage) []string {
+	var single string
+	if err := json.Unmarshal(output, &single); err == nil {
+		if single == "" {
+			return nil
+		}
+		return []string{single}
+	}
+	var list []string
+	if err := json.Unmarshal(output, &list); err == nil {
+		return list
+	}
+	return nil
+}
+
+var nonSlug = regexp.MustCompile(`[^a-z0-9]+`)
+
+// imageSlug turns the start of a prompt into a file-name friendly slug.
+func imageSlug(prompt string) string {
+	slug := strings.Trim(nonSlug.ReplaceAllString(strings.ToLower(prompt), "-"), "-")
+	if len(slug) > 40 {
+		slug = strings.TrimRight(slug[:40], "-")
+	}
+	if slug == "" {
+		slug = "image"
+	}
+	return slug
+}
+
+func imageExt(mimeType string) string {
+	switch mimeType {
+	case "image/jpeg":
+		return ".jpg"
+	case "image/webp":
+		return ".webp"
+	case "image/gif":
+		return ".gif"
+	default:
+		return ".png"
+	}
+}
@@ -0,0 +1,104 @@
+package tool
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestImageGenerate(t *testing.T) {
+	png := []byte("\x89PNG\r\n\x1a\nfake")
+	polls := 0
+
+	var srv *httptest.Server
+	srv = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-API-Key") != "secret" && r.URL.Path != "/files/out.png" {
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+		switch {
+		case r.Method == http.MethodPost && r.URL.Path == "/prediction/":
+			var body struct {
+				Model string         `json:"model"`
+				Input map[string]any `json:"input"`
+			}
+			_ = json.NewDecoder(r.Body).Decode(&body)
+			if body.Model != "flux-1-1-pro" || body.Input["prompt"] != "A red fox" {
+				t.Errorf("unexpected request: %+v", body)
+			}
+			_, _ = w.Write([]byte(`{"status":"success","predictionID":"p1"}`))
+		case r.URL.Path == "/prediction/p1":
+			polls++
+			if polls < 2 {
+				_, _ = w.Write([]byte(`{"status":"processing"}`))
+				return
+			}
+			_, _ = w.Write([]byte(`{"status":"success","output":"` + srv.URL + `/files/out.png"}`))
+		case r.URL.Path == "/files/out.png":
+			w.Header().Set("Content-Type", "image/png")
+			_, _ = w.Write(png)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+
+	dir := t.TempDir()
+	img := NewImageGenerateTool(ImageConfig{
+		APIKey:       "secret",
+		BaseURL:      srv.URL,
+		OutputDir:    dir,
+		PollInterval: time.Millisecond,
+	})
+
+	res, err := img.Execute(context.Background(), json.RawMessage(`{"prompt":"A red fox"}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.IsError {
+		t.Fatalf("unexpected error: %s", res.Content)
+	}
+	if len(res.Attachments) != 1 || res.Attachments[0].MimeType != "image/png" {
+		t.Fatalf("expected one png attachment, got %+v", res.Attachments)
+	}
+	name := res.Attachments[0].Name
+	if !strings.HasSuffix(name, "-a-red-fox.png") {
+		t.Errorf("unexpected file name %q", name)
+	}
+	data, err := os.ReadFile(filepath.Join(dir, name))
+	if err != nil || string(data) != string(png) {
+		t.Errorf("image not saved to workspace: %v", err)
+	}
+}
+
+func TestImageGenerate_Failures(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPost {
+			_, _ = w.Write([]byte(`{"status":"success","predictionID":"p2"}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"status":"error","logs":"NSFW content detected"}`))
+	}))
+	defer srv.Close()
+
+	img := NewImageGenerateTool(ImageConfig{BaseURL: srv.URL, OutputDir: t.TempDir(), PollInterval: time.Millisecond})
+
+	res, _ := img.Execute(context.Background(), json.RawMessage(`{"prompt":"x"}`))
+	if !res.IsError || !strings.Contains(res.Content, "API key") {
+		t.Errorf("expected missing key error, got: %s", res.Content)
+	}
+
+	ctx := WithSkillConfig(context.Background(), map[string]map[string]string{
+		imageSkill: {"api_key": "from-skill"},
+	})
+	res, _ = img.Execute(ctx, json.RawMessage(`{"prompt":"x"}`))
+	if !res.IsError || !strings.Contains(res.Content, "NSFW content detected") {
+		t.Errorf("expected prediction error, got: %s", res.Content)
+	}
+}
@@ -0,0 +1,364 @@
+package tool
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+)
+
+// Jira tools read the site URL and credentials from the agent's jira.url,
+// jira.email and jira.token skill config, falling back to JIRA_URL,
+// JIRA_EMAIL and JIRA_API_TOKEN. With an email the token is a Jira Cloud
+// API token; without one it is a Jira Data Center personal access token.
+const jiraSkill = "jira"
+
+// Jira calls the Jira REST API. The same type backs jira_search,
+// jira_create, jira_comment and jira_transition.
+type Jira struct {
+	name   string
+	client *http.Client
+}
+
+// NewJira creates the Jira tool with the given name (e.g. "jira_search").
+func NewJira(name string) *Jira {
+	return &Jira{name: name, client: &http.Client{Timeout: 30 * time.Second}}
+}
+
+func (j *Jira) Name() string {
+	return j.name
+}
+
+func (j *Jira) Description() string {
+	switch j.name {
+	case "jira_search":
+		return "Search Jira issues with JQL."
+	case "jira_create":
+		return "Create a Jira issue in a project."
+	case "jira_comment":
+		return "Comment on a Jira issue."
+	case "jira_transition":
+		return "Move a Jira issue to another status, add labels to it, or assign it."
+	}
+	return "Call the Jira API."
+}
+
+func (j *Jira) Schema() json.RawMessage {
+	keyProp := `
+			"key": {"type": "string", "description": "Issue key, e.g. OPS-123"},`
+
+	var props, required string
+	switch j.name {
+	case "jira_search":
+		props = `
+			"jql": {"type": "string", "description": "JQL query, e.g. \"project = OPS AND status = Open ORDER BY created DESC\""},
+			"limit": {"type": "integer", "description": "Maximum results (default: 10)"}`
+		required = `"jql"`
+	case "jira_create":
+		props = `
+			"project": {"type": "string", "description": "Project key, e.g. OPS"},
+			"summary": {"type": "string", "description": "Issue summary"},
+			"description": {"type": "string", "description": "Issue description"},
+			"type": {"type": "string", "description": "Issue type (default: Task)"},
+			"priority": {"type": "string", "description": "Priority name, e.g. High"},
+			"labels": {"type": "array", "items": {"type": "string"}, "description": "Labels to apply"}`
+		required = `"project", "summary"`
+	case "jira_comment":
+		props = strings.TrimSuffix(keyProp, ",") + `,
+			"body": {"type": "string", "description": "Comment text"}`
+		required = `"key", "body"`
+	case "jira_transition":
+		props = keyProp + `
+			"status": {"type": "string", "description": "Status to move the issue to, or the name of the transition"},
+			"labels": {"type": "array", "items": {"type": "string"}, "description": "Labels to add"},
+			"assignee": {"type": "string", "description": "Account ID of the user to assign the issue to"}`
+		required = `"key"`
+	}
+
+	return json.RawMessage(fmt.Sprintf(`{
+		"type": "object",
+		"properties": {%s
+		},
+		"required": [%s]
+	}`, props, required))
+}
+
+type jiraParams struct {
+	JQL         string   `json:"jql"`
+	Limit       int      `json:"limit"`
+	Project     string   `json:"project"`
+	Summary     string   `json:"summary"`
+	Description string   `json:"description"`
+	Type        string   `json:"type"`
+	Priority    string   `json:"priority"`
+	Labels      []string `json:"labels"`
+	Key         string   `json:"key"`
+	Body        string   `json:"body"`
+	Status      string   `json:"status"`
+	Assignee    string   `json:"assignee"`
+}
+
+func (j *Jira) Execute(ctx context.Context, params json.RawMessage) (*Result, error) {
+	var p jiraParams
+	if err := json.Unmarshal(params, &p); err != nil {
+		return &Result{Content: fmt.Sprintf("Invalid parameters: %v", err), IsError: true}, nil
+	}
+
+	switch j.name {
+	case "jira_search":
+		return j.search(ctx, p)
+
+	case "jira_create":
+		if p.Project == "" || p.Summary == "" {
+			return &Result{Content: "project and summary are required", IsError: true}, nil
+		}
+		issueType := p.Type
+		if issueType == "" {
+			issueType = "Task"
+		}
+		fields := map[string]any{
+			"project":     map[string]string{"key": p.Project},
+			"summary":     p.Summary,
+			"description": p.Description,
+			"issuetype":   map[string]string{"name": issueType},
+		}
+		if p.Priority != "" {
+			fields["priority"] = map[string]string{"name": p.Priority}
+		}
+		if len(p.Labels) > 0 {
+			fields["labels"] = p.Labels
+		}
+		var issue struct {
+			Key string `json:"key"`
+		}
+		if err := j.call(ctx, "POST", "/rest/api/2/issue", map[string]any{"fields": fields}, &issue); err != nil {
+			return &Result{Content: fmt.Sprintf("Failed to create issue: %v", err), IsError: true}, nil
+		}
+		return &Result{Content: fmt.Sprintf("Created %s: %s", issue.Key, j.browseURL(ctx, issue.Key))}, nil
+
+	case "jira_comment":
+		if p.Key == "" || p.Body == "" {
+			return &Result{Content: "key and body are required", IsError: true}, nil
+		}
+		path := "/rest/api/2/issue/" + url.PathEscape(p.Key) + "/comment"
+		if err := j.call(ctx, "POST", path, map[string]any{"body": p.Body}, nil); err != nil {
+			return &Result{Content: fmt.Sprintf("Failed to comment: %v", err), IsError: true}, nil
+		}
+		return &Result{Content: fmt.Sprintf("Commented on %s", p.Key)}, nil
+
+	case "jira_transition":
+		return j.transition(ctx, p)
+	}
+
+	return &Result{Content: fmt.Sprintf("Unknown Jira tool: %s", j.name), IsError: true}, nil
+}
+
+func (j *Jira) search(ctx context.Context, p jiraParams) (*Result, error) {
+	if p.JQL == "" {
+		return &Result{Content: "jql is required", IsError: true}, nil
+	}
+	limit := p.Limit
+	if limit <= 0 || limit > 50 {
+		limit = 10
+	}
+
+	var resp struct {
+		Total  int `json:"total"`
+		Issues []struct {
+			Key    string `json:"key"`
+			Fields struct {
+				Summary string `json:"summary"`
+				Status  struct {
+					Name string `json:"name"`
+				} `json:"status"`
+				Priority *struct {
+					Name string `json:"name"`
+				} `json:"priority"`
+				Assignee *struct {
+					DisplayName string `json:"displayName"`
+				} `json:"assignee"`
+				Labels []string `json:"labels"`
+			} `json:"fields"`
+		} `json:"issues"`
+	}
+	query := url.Values{
+		"jql":        {p.JQL},
+		"maxResults": {fmt.Sprint(limit)},
+		"fields":     {"summary,status,priority,assignee,labels"},
+	}
+	if err := j.call(ctx, "GET", "/rest/api/2/search?"+query.Encode(), nil, &resp); err != nil {
+		return &Result{Content: fmt.Sprintf("Search failed: %v", err), IsError: true}, nil
+	}
+
+	var sb strings.Builder
+	_, _ = fmt.Fprintf(&sb, "%d issues for %q\n\n", resp.Total, p.JQL)
+	for i, issue := range resp.Issues {
+		f := issue.Fields
+		_, _ = fmt.Fprintf(&sb, "%d. %s %s [%s]", i+1, issue.Key, f.Summary, f.Status.Name)
+		if f.Priority != nil {
+			_, _ = fmt.Fprintf(&sb, " priority=%s", f.Priority.Name)
+		}
+		if f.Assignee != nil {
+			_, _ = fmt.Fprintf(&sb, " assignee=%s", f.Assignee.DisplayName)
+		}
+		if len(f.Labels) > 0 {
+			_, _ = fmt.Fprintf(&sb, " labels=%s", strings.Join(f.Labels, ","))
+		}
+		sb.WriteString("\n")
+	}
+	return &Result{Content: sb.String()}, nil
+}
+
+// transition adds labels, assigns and moves an issue, in that order, so a
+// status that closes the issue comes last.
+func (j *Jira) transition(ctx context.Context, p jiraParams) (*Result, error) {
+	if p.Key == "" {
+		return &Result{Content: "key is required", IsError: true}, nil
+	}
+	if p.Status == "" && len(p.Labels) == 0 && p.Assignee == "" {
+		return &Result{Content: "status, labels or assignee is required", IsError: true}, nil
+	}
+	issuePath := "/rest/api/2/issue/" + url.PathEscape(p.Key)
+	var done []string
+
+	if len(p.Labels) > 0 {
+		add := make([]map[string]string, 0, len(p.Labels))
+		for _, l := range p.Labels {
+			add = append(add, map[string]string{"add": l})
+		}
+		if err := j.call(ctx, "PUT", issuePath, map[string]any{"update": map[string]any{"labels": add}}, nil); err != nil {
+			return &Result{Content: fmt.Sprintf("Failed to add labels: %v", err), IsError: true}, nil
+		}
+		done = append(done, "labeled "+strings.Join(p.Labels, ", "))
+	}
+
+	if p.Assignee != "" {
+		if err := j.call(ctx, "PUT", issuePath+"/assignee", map[string]any{"accountId": p.Assignee}, nil); err != nil {
+			return &Result{Content: fmt.Sprintf("Failed to assign: %v", err), IsError: true}, nil
+		}
+		done = append(done, "assigned to "+p.Assignee)
+	}
+
+	if p.Status != "" {
+		var resp struct {
+			Transitions []struct {
+				ID   string `json:"id"`
+				Name string `json:"name"`
+				To   struct {
+					Name string `json:"name"`
+				} `json:"to"`
+			} `json:"transitions"`
+		}
+		if err := j.call(ctx, "GET", issuePath+"/transitions", nil, &resp); err != nil {
+			return &Result{Content: fmt.Sprintf("Failed to list transitions: %v", err), IsError: true}, nil
+		}
+		id := ""
+		var names []string
+		for _, t := range resp.Transitions {
+			if strings.EqualFold(t.To.Name, p.Status) || strings.EqualFold(t.Name, p.Status) {
+				id = t.ID
+				break
+			}
+			names = append(names, t.To.Name)
+		}
+		if id == "" {
+			return &Result{Content: fmt.Sprintf("%s cannot move to %q (available: %s)", p.Key, p.Status, strings.Join(names, ", ")), IsError: true}, nil
+		}
+		if err := j.call(ctx, "POST", issuePath+"/transitions", map[string]any{"transition": map[string]string{"id": id}}, nil); err != nil {
+			return &Result{Content: fmt.Sprintf("Failed to transition: %v", err), IsError: true}, nil
+		}
+		done = append(done, "moved to "+p.Status)
+	}
+
+	return &Result{Content: fmt.Sprintf("%s %s", p.Key, strings.Join(done, ", "))}, nil
+}
+
+// browseURL returns the web URL of an issue.
+func (j *Jira) browseURL(ctx context.Context, key string) string {
+	return jiraSetting(ctx, "url", "JIRA_URL") + "/browse/" + key
+}
+
+// call performs an authenticated Jira API request and decodes the JSON
+// response into out.
+func (j *Jira) call(ctx context.Context, method, path string, payload any, out any) error {
+	base := jiraSetting(ctx, "url", "JIRA_URL")
+	token := jiraSetting(ctx, "token", "JIRA_API_TOKEN")
+	if base == "" || token == "" {
+		return fmt.Errorf("no Jira site configured (klaw agent config set <agent> jira.url=https://<site>.atlassian.net jira.email=... jira.token=...)")
+	}
+
+	var body io.Reader
+	if payload != nil {
+		data, err := json.Marshal(payload)
+		if err != nil {
+			return err
+		}
+		body = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, base+path, body)
+	if err != nil {
+		return err
+	}
+	if email := jiraSetting(ctx, "email", "JIRA_EMAIL"); email != "" {
+		req.Header.Set("Authorization", "Basic "+base64.StdEncoding.EncodeToString([]byte(email+":"+token)))
+	} else {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("User-Agent", "Klaw/1.0")
+	if payload != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := j.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, 4*1024*1024))
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode >= 300 {
+		var apiErr struct {
+			ErrorMessages []string          `json:"errorMessages"`
+			Errors        map[string]string `json:"errors"`
+		}
+		_ = json.Unmarshal(data, &apiErr)
+		msgs := apiErr.ErrorMessages
+		for field, msg := range apiErr.Errors {
+			msgs = append(msgs, field+": "+msg)
+		}
+		msg := strings.Join(msgs, "; ")
+		if msg == "" {
+			msg = truncateString(string(data), 200)
+		}
+		return fmt.Errorf("HTTP %d: %s", resp.StatusCode, msg)
+	}
+	if out == nil || len(data) == 0 {
+		return nil
+	}
+	return json.Unmarshal(data, out)
+}
+
+// jiraSetting returns a jira skill config value, or else the environment
+// variable env.
+func jiraSetting(ctx context.Context, key, env string) string {
+	v := SkillConfigValue(ctx, jiraSkill, key)
+	if v == "" {
+		v = os.Getenv(env)
+	}
+	if key == "url" {
+		v = strings.TrimRight(v, "/")
+	}
+	return v
+}
@@ -0,0 +1,97 @@
+package tool
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestJira_Transition(t *testing.T) {
+	var calls []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		user, pass, ok := r.BasicAuth()
+		if !ok || user != "bot@acme.io" || pass != "tok" {
+			t.Errorf("missing credentials: %q", r.Header.Get("Authorization"))
+		}
+		body, _ := io.ReadAll(r.Body)
+		calls = append(calls, r.Method+" "+r.URL.Path+" "+string(body))
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/rest/api/2/issue/OPS-1/transitions":
+			_, _ = w.Write([]byte(`{"transitions":[{"id":"11","name":"Start","to":{"name":"In Progress"}},{"id":"31","name":"Resolve","to":{"name":"Done"}}]}`))
+		case r.URL.Path == "/rest/api/2/issue/OPS-404/transitions":
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"errorMessages":["Issue does not exist or you do not have permission to see it."],"errors":{}}`))
+		default:
+			w.WriteHeader(http.StatusNoContent)
+		}
+	}))
+	defer srv.Close()
+
+	ctx := WithSkillConfig(context.Background(), map[string]map[string]string{
+		"jira": {"url": srv.URL + "/", "email": "bot@acme.io", "token": "tok"},
+	})
+	jira := NewJira("jira_transition")
+
+	res, err := jira.Execute(ctx, json.RawMessage(`{"key":"OPS-1","status":"in progress","labels":["triaged"]}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.IsError || res.Content != "OPS-1 labeled triaged, moved to in progress" {
+		t.Errorf("unexpected result: %s", res.Content)
+	}
+	want := []string{
+		`PUT /rest/api/2/issue/OPS-1 {"update":{"labels":[{"add":"triaged"}]}}`,
+		`GET /rest/api/2/issue/OPS-1/transitions `,
+		`POST /rest/api/2/issue/OPS-1/transitions {"transition":{"id":"11"}}`,
+	}
+	if strings.Join(calls, "\n") != strings.Join(want, "\n") {
+		t.Errorf("unexpected calls:\n%s", strings.Join(calls, "\n"))
+	}
+
+	res, _ = jira.Execute(ctx, json.RawMessage(`{"key":"OPS-1","status":"Closed"}`))
+	if !res.IsError || !strings.Contains(res.Content, "available: In Progress, Done") {
+		t.Errorf("expected the available statuses, got: %s", res.Content)
+	}
+
+	res, _ = jira.Execute(ctx, json.RawMessage(`{"key":"OPS-404","status":"Done"}`))
+	if !res.IsError || !strings.Contains(res.Content, "HTTP 404: Issue does not exist") {
+		t.Errorf("expected API error, got: %s", res.Content)
+	}
+}
+
+func TestJira_Search(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer pat" {
+			t.Errorf("expected a bearer token, got %q", r.Header.Get("Authorization"))
+		}
+		if r.URL.Query().Get("jql") != "project = OPS" || r.URL.Query().Get("maxResults") != "10" {
+			t.Errorf("unexpected query: %s", r.URL.RawQuery)
+		}
+		_, _ = w.Write([]byte(`{"total":1,"issues":[{"key":"OPS-2","fields":{"summary":"Disk full","status":{"name":"Open"},"priority":{"name":"High"},"labels":["infra"]}}]}`))
+	}))
+	defer srv.Close()
+
+	ctx := WithSkillConfig(context.Background(), map[string]map[string]string{
+		"jira": {"url": srv.URL, "token": "pat"},
+	})
+	res, err := NewJira("jira_search").Execute(ctx, json.RawMessage(`{"jql":"project = OPS"}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.IsError || !strings.Contains(res.Content, "1. OPS-2 Disk full [Open] priority=High labels=infra") {
+		t.Errorf("unexpected result: %s", res.Content)
+	}
+}
+
+func TestJira_NotConfigured(t *testing.T) {
+	t.Setenv("JIRA_URL", "")
+	t.Setenv("JIRA_API_TOKEN", "")
+	res, _ := NewJira("jira_comment").Execute(context.Background(), json.RawMessage(`{"key":"OPS-1","body":"hi"}`))
+	if !res.IsError || !strings.Contains(res.Content, "no Jira site configured") {
+		t.Errorf("expected a configuration error, got: %s", res.Content)
+	}
+}
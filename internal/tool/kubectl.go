@@ -0,0 +1,274 @@
+package tool
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// Kubectl reads its cluster scope from the agent's "kubernetes" skill config:
+//
+//	kubernetes.context     kubeconfig context to use (required)
+//	kubernetes.kubeconfig  path to the kubeconfig file (default: kubectl's)
+//	kubernetes.namespace   default namespace
+const kubernetesSkill = "kubernetes"
+
+// Kubectl runs read-only kubectl commands and confirmed applies.
+type Kubectl struct {
+	workDir string
+}
+
+// NewKubectl creates the kubectl tool.
+func NewKubectl(workDir string) *Kubectl {
+	return &Kubectl{workDir: workDir}
+}
+
+func (k *Kubectl) Name() string {
+	return "kubectl"
+}
+
+func (k *Kubectl) Description() string {
+	return `Investigate and change a Kubernetes cluster with kubectl, scoped to the agent's configured context.
+Actions: get, describe, logs, events, apply.
+apply first returns a diff; show it to the user and call again with confirm=true to apply.`
+}
+
+func (k *Kubectl) Schema() json.RawMessage {
+	return json.RawMessage(`{
+		"type": "object",
+		"properties": {
+			"action": {
+				"type": "string",
+				"enum": ["get", "describe", "logs", "events", "apply"],
+				"description": "kubectl action"
+			},
+			"resource": {
+				"type": "string",
+				"description": "Resource type (e.g. pods, deployments, svc) for get/describe"
+			},
+			"name": {
+				"type": "string",
+				"description": "Resource name (pod name for logs)"
+			},
+			"namespace": {
+				"type": "string",
+				"description": "Namespace (default: configured namespace)"
+			},
+			"all_namespaces": {
+				"type": "boolean",
+				"description": "Query all namespaces (get/events)"
+			},
+			"selector": {
+				"type": "string",
+				"description": "Label selector (e.g. app=web)"
+			},
+			"output": {
+				"type": "string",
+				"enum": ["wide", "yaml", "json", "name"],
+				"description": "Output format for get"
+			},
+			"container": {
+				"type": "string",
+				"description": "Container name for logs"
+			},
+			"tail": {
+				"type": "integer",
+				"description": "Number of log lines (default: 200)"
+			},
+			"previous": {
+				"type": "boolean",
+				"description": "Logs of the previous container instance"
+			},
+			"manifest": {
+				"type": "string",
+				"description": "YAML manifest for apply"
+			},
+			"confirm": {
+				"type": "boolean",
+				"description": "Apply the manifest after the user approved the diff"
+			}
+		},
+		"required": ["action"]
+	}`)
+}
+
+type kubectlParams struct {
+	Action        string `json:"action"`
+	Resource      string `json:"resource"`
+	Name          string `json:"name"`
+	Namespace     string `json:"namespace"`
+	AllNamespaces bool   `json:"all_namespaces"`
+	Selector      string `json:"selector"`
+	Output        string `json:"output"`
+	Container     string `json:"container"`
+	Tail          int    `json:"tail"`
+	Previous      bool   `json:"previous"`
+	Manifest      string `json:"manifest"`
+	Confirm       bool   `json:"confirm"`
+}
+
+// Resource, name and selector values must not smuggle in extra flags.
+var reKubectlArg = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._/:=,!() -]*$`)
+
+func (k *Kubectl) Execute(ctx context.Context, params json.RawMessage) (*Result, error) {
+	var p kubectlParams
+	if err := json.Unmarshal(params, &p); err != nil {
+		return &Result{Content: fmt.Sprintf("Invalid parameters: %v", err), IsError: true}, nil
+	}
+
+	args, err := kubectlArgs(ctx, p)
+	if err != nil {
+		return &Result{Content: err.Error(), IsError: true}, nil
+	}
+
+	if p.Action != "apply" {
+		return k.run(ctx, args, "")
+	}
+
+	if strings.TrimSpace(p.Manifest) == "" {
+		return &Result{Content: "manifest is required for apply", IsError: true}, nil
+	}
+	if !p.Confirm {
+		res, err := k.run(ctx, kubectlDiffArgs(args), p.Manifest)
+		if err != nil {
+			return nil, err
+		}
+		// kubectl diff exits 1 when there are differences, >1 on errors
+		diff, hasChanges := strings.CutPrefix(res.Content, "kubectl failed: exit status 1\n")
+		if res.IsError && !hasChanges {
+			return res, nil
+		}
+		if !hasChanges {
+			diff = "(no changes)"
+		}
+		return &Result{
+			Content: "Changes that apply would make:\n\n" + diff +
+				"\n\nShow this diff to the user. If they approve, call kubectl again with action=apply and confirm=true.",
+		}, nil
+	}
+	return k.run(ctx, args, p.Manifest)
+}
+
+// kubectlArgs builds the kubectl argument list for p, including the
+// configured context, kubeconfig and namespace.
+func kubectlArgs(ctx context.Context, p kubectlParams) ([]string, error) {
+	kubeContext := SkillConfigValue(ctx, kubernetesSkill, "context")
+	if kubeContext == "" {
+		return nil, fmt.Errorf("no kubeconfig context configured (klaw agent config set <agent> kubernetes.context=...)")
+	}
+
+	for _, v := range []string{p.Resource, p.Name, p.Namespace, p.Selector, p.Container} {
+		if v != "" && !reKubectlArg.MatchString(v) {
+			return nil, fmt.Errorf("invalid argument: %q", v)
+		}
+	}
+
+	var args []string
+	switch p.Action {
+	case "get", "describe":
+		if p.Resource == "" {
+			return nil, fmt.Errorf("resource is required for %s", p.Action)
+		}
+		args = []string{p.Action, p.Resource}
+		if p.Name != "" {
+			args = append(args, p.Name)
+		}
+		if p.Action == "get" && p.Output != "" {
+			switch p.Output {
+			case "wide", "yaml", "json", "name":
+				args = append(args, "-o", p.Output)
+			default:
+				return nil, fmt.Errorf("unsupported output format: %s", p.Output)
+			}
+		}
+	case "logs":
+		if p.Name == "" {
+			return nil, fmt.Errorf("name (pod) is required for logs")
+		}
+		tail := p.Tail
+		if tail <= 0 {
+			tail = 200
+		}
+		args = []string{"logs", p.Name, fmt.Sprintf("--tail=%d", tail)}
+		if p.Container != "" {
+			args = append(args, "-c", p.Container)
+		}
+		if p.Previous {
+			args = append(args, "--previous")
+		}
+	case "events":
+		args = []string{"events"}
+		if p.Name != "" {
+			args = append(args, "--for", p.Name)
+		}
+	case "apply":
+		args = []string{"apply", "-f", "-"}
+	default:
+		return nil, fmt.Errorf("unsupported action: %q (use get, describe, logs, events, apply)", p.Action)
+	}
+
+	if p.Selector != "" && p.Action != "apply" && p.Action != "events" {
+		args = append(args, "-l", p.Selector)
+	}
+
+	namespace := p.Namespace
+	if namespace == "" {
+		namespace = SkillConfigValue(ctx, kubernetesSkill, "namespace")
+	}
+	if p.AllNamespaces && (p.Action == "get" || p.Action == "events") {
+		args = append(args, "--all-namespaces")
+	} else if namespace != "" {
+		args = append(args, "-n", namespace)
+	}
+
+	args = append(args, "--context", kubeContext)
+	if kubeconfig := SkillConfigValue(ctx, kubernetesSkill, "kubeconfig"); kubeconfig != "" {
+		args = append(args, "--kubeconfig", kubeconfig)
+	}
+	return args, nil
+}
+
+// kubectlDiffArgs turns the arguments of an apply into those of the diff
+// previewing it, with the same manifest, namespace and context.
+func kubectlDiffArgs(applyArgs []string) []string {
+	return append([]string{"diff"}, applyArgs[1:]...)
+}
+
+func (k *Kubectl) run(ctx context.Context, args []string, stdin string) (*Result, error) {
+	ctx, cancel := context.WithTimeout(ctx, 60*time.Second)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, "kubectl", args...)
+	cmd.Dir = k.workDir
+	cmd.Env = os.Environ()
+	if stdin != "" {
+		cmd.Stdin = strings.NewReader(stdin)
+	}
+
+	var out bytes.Buffer
+	cmd.Stdout = &out
+	cmd.Stderr = &out
+	err := cmd.Run()
+
+	output := strings.TrimRight(out.String(), "\n")
+	if len(output) > 30000 {
+		output = output[:30000] + "\n... (output truncated)"
+	}
+
+	if ctx.Err() == context.DeadlineExceeded {
+		return &Result{Content: "kubectl timed out\n" + output, IsError: true}, nil
+	}
+	if err != nil {
+		return &Result{Content: fmt.Sprintf("kubectl failed: %v\n%s", err, output), IsError: true}, nil
+	}
+	if output == "" {
+		output = "kubectl: done"
+	}
+	return &Result{Content: output}, nil
+}
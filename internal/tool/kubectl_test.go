@@ -0,0 +1,65 @@
+package tool
+
+import (
+	"context"
+	"reflect"
+	"testing"
+)
+
+func TestKubectlArgs(t *testing.T) {
+	ctx := WithSkillConfig(context.Background(), map[string]map[string]string{
+		"kubernetes": {"context": "prod", "namespace": "web"},
+	})
+
+	tests := []struct {
+		name string
+		p    kubectlParams
+		want []string
+	}{
+		{
+			name: "get with selector",
+			p:    kubectlParams{Action: "get", Resource: "pods", Selector: "app=api", Output: "wide"},
+			want: []string{"get", "pods", "-o", "wide", "-l", "app=api", "-n", "web", "--context", "prod"},
+		},
+		{
+			name: "logs",
+			p:    kubectlParams{Action: "logs", Name: "api-123", Container: "app", Namespace: "jobs"},
+			want: []string{"logs", "api-123", "--tail=200", "-c", "app", "-n", "jobs", "--context", "prod"},
+		},
+		{
+			name: "all namespaces",
+			p:    kubectlParams{Action: "get", Resource: "nodes", AllNamespaces: true},
+			want: []string{"get", "nodes", "--all-namespaces", "--context", "prod"},
+		},
+	}
+	for _, tt := range tests {
+		got, err := kubectlArgs(ctx, tt.p)
+		if err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s:\n got  %v\n want %v", tt.name, got, tt.want)
+		}
+	}
+
+	apply, err := kubectlArgs(ctx, kubectlParams{Action: "apply", Manifest: "kind: Pod"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"apply", "-f", "-", "-n", "web", "--context", "prod"}; !reflect.DeepEqual(apply, want) {
+		t.Errorf("apply:\n got  %v\n want %v", apply, want)
+	}
+	if got, want := kubectlDiffArgs(apply), []string{"diff", "-f", "-", "-n", "web", "--context", "prod"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("diff:\n got  %v\n want %v", got, want)
+	}
+
+	if _, err := kubectlArgs(ctx, kubectlParams{Action: "get", Resource: "pods", Name: "--kubeconfig=/etc/x"}); err == nil {
+		t.Error("expected flag injection to be rejected")
+	}
+	if _, err := kubectlArgs(context.Background(), kubectlParams{Action: "get", Resource: "pods"}); err == nil {
+		t.Error("expected error without configured context")
+	}
+	if _, err := kubectlArgs(ctx, kubectlParams{Action: "delete", Resource: "pods"}); err == nil {
+		t.Error("expected delete to be unsupported")
+	}
+}
@@ -0,0 +1,261 @@
+package tool
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/eachlabs/klaw/internal/memory"
+)
+
+type agentNameKey struct{}
+
+// WithAgentName records the name of the agent running the tools in ctx.
+func WithAgentName(ctx context.Context, name string) context.Context {
+	if name == "" {
+		return ctx
+	}
+	return context.WithValue(ctx, agentNameKey{}, name)
+}
+
+// AgentNameFromContext returns the running agent's name, or "" if unset.
+func AgentNameFromContext(ctx context.Context) string {
+	name, _ := ctx.Value(agentNameKey{}).(string)
+	return name
+}
+
+func memoryAgent(ctx context.Context) string {
+	if name := AgentNameFromContext(ctx); name != "" {
+		return name
+	}
+	return memory.DefaultAgent
+}
+
+// MemoryTools returns memory_store and memory_recall backed by facts.
+func MemoryTools(facts *memory.FactStore) []Tool {
+	return []Tool{NewMemoryStore(facts), NewMemoryRecall(facts)}
+}
+
+// --- memory_store ---
+
+// MemoryStore saves durable facts for the running agent.
+type MemoryStore struct {
+	facts *memory.FactStore
+}
+
+// NewMemoryStore creates the memory_store tool.
+func NewMemoryStore(facts *memory.FactStore) *MemoryStore {
+	return &MemoryStore{facts: facts}
+}
+
+func (t *MemoryStore) Name() string {
+	return "memory_store"
+}
+
+func (t *MemoryStore) Description() string {
+	return `Save a durable fact to your long-term memory so it is available in future conversations,
+threads and after restarts. Store one self-contained fact per call, e.g. "John prefers blue",
+"Deploys to production happen on Tuesdays". Do not store secrets or one-off details.`
+}
+
+func (t *MemoryStore) Schema() json.RawMessage {
+	return json.RawMessage(`{
+		"type": "object",
+		"properties": {
+			"fact": {
+				"type": "string",
+				"description": "The fact to remember, as a complete sentence"
+			},
+			"tags": {
+				"type": "array",
+				"items": {"type": "string"},
+				"description": "Optional keywords to help recall (e.g. person, project)"
+			}
+		},
+		"required": ["fact"]
+	}`)
+}
+
+type memoryStoreParams struct {
+	Fact string   `json:"fact"`
+	Tags []string `json:"tags"`
+}
+
+func (t *MemoryStore) Execute(ctx context.Context, params json.RawMessage) (*Result, error) {
+	var p memoryStoreParams
+	if err := json.Unmarshal(params, &p); err != nil {
+		return &Result{Content: fmt.Sprintf("Invalid parameters: %v", err), IsError: true}, nil
+	}
+	if strings.TrimSpace(p.Fact) == "" {
+		return &Result{Content: "fact is required", IsError: true}, nil
+	}
+
+	fact, err := t.facts.Add(memoryAgent(ctx), p.Fact, p.Tags)
+	if err != nil {
+		return &Result{Content: fmt.Sprintf("Failed to save fact: %v", err), IsError: true}, nil
+	}
+	return &Result{Content: fmt.Sprintf("Remembered [%s]: %s", fact.ID, fact.Content)}, nil
+}
+
+// --- memory_recall ---
+
+// MemoryRecall searches the running agent's saved facts.
+type MemoryRecall struct {
+	facts *memory.FactStore
+}
+
+// NewMemoryRecall creates the memory_recall tool.
+func NewMemoryRecall(facts *memory.FactStore) *MemoryRecall {
+	return &MemoryRecall{facts: facts}
+}
+
+func (t *MemoryRecall) Name() string {
+	return "memory_recall"
+}
+
+func (t *MemoryRecall) Description() string {
+	return `Search your long-term memory for facts saved with memory_store.
+Use this before answering questions about people, preferences or past decisions.
+Leave query empty to list the most recent facts.`
+}
+
+func (t *MemoryRecall) Schema() json.RawMessage {
+	return json.RawMessage(`{
+		"type": "object",
+		"properties": {
+			"query": {
+				"type": "string",
+				"description": "Keywords to search for"
+			},
+			"limit": {
+				"type": "integer",
+				"description": "Maximum number of facts (default: 10)"
+			}
+		}
+	}`)
+}
+
+type memoryRecallParams struct {
+	Query string `json:"query"`
+	Limit int    `json:"limit"`
+}
+
+func (t *MemoryRecall) Execute(ctx context.Context, params json.RawMessage) (*Result, error) {
+	var p memoryRecallParams
+	if err := json.Unmarshal(params, &p); err != nil {
+		return &Result{Content: fmt.Sprintf("Invalid parameters: %v", err), IsError: true}, nil
+	}
+	limit := p.Limit
+	if limit <= 0 {
+		limit = 10
+	}
+
+	facts, err := t.facts.Search(memoryAgent(ctx), p.Query, limit)
+	if err != nil {
+		return &Result{Content: fmt.Sprintf("Failed to search memory: %v", err), IsError: true}, nil
+	}
+	if len(facts) == 0 {
+		return &Result{Content: "No matching facts in memory."}, nil
+	}
+	ids := make([]string, len(facts))
+	for i, f := range facts {
+		ids[i] = f.ID
+	}
+	_ = t.facts.Touch(memoryAgent(ctx), ids...)
+
+	var sb strings.Builder
+	for _, f := range facts {
+		_, _ = fmt.Fprintf(&sb, "- [%s] %s (%s)", f.ID, f.Content, f.CreatedAt.Format("2006-01-02"))
+		if len(f.Tags) > 0 {
+			_, _ = fmt.Fprintf(&sb, " tags: %s", strings.Join(f.Tags, ", "))
+		}
+		sb.WriteString("\n")
+	}
+	return &Result{Content: sb.String()}, nil
+}
+
+// --- memory_history ---
+
+// MemoryHistory reads the running agent's daily conversation summaries.
+type MemoryHistory struct {
+	episodic *memory.EpisodicMemory
+}
+
+// NewMemoryHistory creates the memory_history tool.
+func NewMemoryHistory(episodic *memory.EpisodicMemory) *MemoryHistory {
+	return &MemoryHistory{episodic: episodic}
+}
+
+func (t *MemoryHistory) Name() string {
+	return "memory_history"
+}
+
+func (t *MemoryHistory) Description() string {
+	return `Read the daily summaries of your past conversations, e.g. to answer
+"what did we discuss about the pricing page last week?". Summaries cover finished days;
+today's conversations are not summarized yet.`
+}
+
+func (t *MemoryHistory) Schema() json.RawMessage {
+	return json.RawMessage(`{
+		"type": "object",
+		"properties": {
+			"days": {
+				"type": "integer",
+				"description": "How many days back to look (default: 7)"
+			},
+			"query": {
+				"type": "string",
+				"description": "Optional keywords; only summaries mentioning one of them are returned"
+			}
+		}
+	}`)
+}
+
+type memoryHistoryParams struct {
+	Days  int    `json:"days"`
+	Query string `json:"query"`
+}
+
+func (t *MemoryHistory) Execute(ctx context.Context, params json.RawMessage) (*Result, error) {
+	var p memoryHistoryParams
+	if err := json.Unmarshal(params, &p); err != nil {
+		return &Result{Content: fmt.Sprintf("Invalid parameters: %v", err), IsError: true}, nil
+	}
+	days := p.Days
+	if days <= 0 {
+		days = 7
+	}
+
+	since := time.Now().AddDate(0, 0, -days)
+	summaries, err := t.episodic.Summaries(memoryAgent(ctx), since)
+	if err != nil {
+		return &Result{Content: fmt.Sprintf("Failed to read summaries: %v", err), IsError: true}, nil
+	}
+
+	words := strings.Fields(strings.ToLower(p.Query))
+	var sb strings.Builder
+	for _, s := range summaries {
+		if len(words) > 0 {
+			text := strings.ToLower(s.Content)
+			found := false
+			for _, w := range words {
+				if strings.Contains(text, w) {
+					found = true
+					break
+				}
+			}
+			if !found {
+				continue
+			}
+		}
+		sb.WriteString(strings.TrimSpace(s.Content))
+		sb.WriteString("\n\n")
+	}
+	if sb.Len() == 0 {
+		return &Result{Content: fmt.Sprintf("No conversation summaries in the last %d days match.", days)}, nil
+	}
+	return &Result{Content: sb.String()}, nil
+}
@@ -0,0 +1,86 @@
+package tool
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/eachlabs/klaw/internal/memory"
+)
+
+func TestMemoryTools(t *testing.T) {
+	facts := memory.NewFactStore(t.TempDir())
+	store, recall := NewMemoryStore(facts), NewMemoryRecall(facts)
+	researcher := WithAgentName(context.Background(), "researcher")
+
+	for _, fact := range []string{"John prefers blue", "Deploys happen on Tuesdays", "john prefers blue"} {
+		params, _ := json.Marshal(memoryStoreParams{Fact: fact})
+		if res, err := store.Execute(researcher, params); err != nil || res.IsError {
+			t.Fatalf("memory_store(%q) = %v, %v", fact, res, err)
+		}
+	}
+
+	res, err := recall.Execute(researcher, json.RawMessage(`{"query":"what does John prefer"}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(res.Content, "prefers blue") || strings.Contains(res.Content, "Tuesdays") {
+		t.Errorf("unexpected recall: %s", res.Content)
+	}
+
+	all, _ := facts.List("researcher")
+	if len(all) != 2 {
+		t.Errorf("expected duplicate fact to be merged, got %d facts", len(all))
+	}
+
+	// Facts are scoped to the agent
+	res, _ = recall.Execute(WithAgentName(context.Background(), "coder"), json.RawMessage(`{}`))
+	if !strings.Contains(res.Content, "No matching facts") {
+		t.Errorf("facts leaked across agents: %s", res.Content)
+	}
+}
+
+func TestMemoryHistory(t *testing.T) {
+	dir := t.TempDir()
+	journal := memory.NewJournal(dir)
+	for i, ep := range []memory.Episode{
+		{Agent: "support", User: "Update the pricing page", Reply: "Done"},
+		{Agent: "support", User: "Book the offsite", Reply: "Booked"},
+	} {
+		ep.Time = time.Now().AddDate(0, 0, -(i + 2))
+		if err := journal.Append(ep); err != nil {
+			t.Fatal(err)
+		}
+	}
+	episodic := memory.NewEpisodicMemory(dir, journal, func(_ context.Context, prompt string) (string, error) {
+		if strings.Contains(prompt, "pricing") {
+			return "- Updated the pricing page", nil
+		}
+		return "- Booked the offsite", nil
+	})
+	if err := episodic.CatchUp(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	history := NewMemoryHistory(episodic)
+	support := WithAgentName(context.Background(), "support")
+	res, err := history.Execute(support, json.RawMessage(`{"query":"pricing"}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(res.Content, "pricing page") || strings.Contains(res.Content, "offsite") {
+		t.Errorf("unexpected history: %s", res.Content)
+	}
+
+	res, _ = history.Execute(support, json.RawMessage(`{"days":1}`))
+	if !strings.Contains(res.Content, "No conversation summaries") {
+		t.Errorf("expected no summaries for yesterday, got %s", res.Content)
+	}
+
+	res, _ = history.Execute(WithAgentName(context.Background(), "coder"), json.RawMessage(`{}`))
+	if !strings.Contains(res.Content, "No conversation summaries") {
+		t.Errorf("summaries leaked across agents: %s", res.Content)
+	}
+}
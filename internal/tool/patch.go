@@ -0,0 +1,420 @@
+package tool
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// ApplyPatch applies unified diffs to files in the working directory.
+type ApplyPatch struct {
+	workDir string
+}
+
+// NewApplyPatch creates a new apply_patch tool.
+func NewApplyPatch(workDir string) *ApplyPatch {
+	return &ApplyPatch{workDir: workDir}
+}
+
+func (a *ApplyPatch) Name() string {
+	return "apply_patch"
+}
+
+func (a *ApplyPatch) Description() string {
+	return `Apply a unified diff (as produced by "git diff" or "diff -u") to one or more files.
+All files are changed together: if any hunk does not match the current file contents,
+nothing is written. Use dry_run to check that a patch applies without changing files.
+Supports new files (--- /dev/null), deleted files (+++ /dev/null) and renames.
+Prefer this over repeated edit calls for multi-file or multi-hunk changes.`
+}
+
+func (a *ApplyPatch) Schema() json.RawMessage {
+	return json.RawMessage(`{
+		"type": "object",
+		"properties": {
+			"patch": {
+				"type": "string",
+				"description": "Unified diff with ---/+++ file headers and @@ hunks"
+			},
+			"dry_run": {
+				"type": "boolean",
+				"description": "Only check that the patch applies (default: false)"
+			}
+		},
+		"required": ["patch"]
+	}`)
+}
+
+type applyPatchParams struct {
+	Patch  string `json:"patch"`
+	DryRun bool   `json:"dry_run"`
+}
+
+// filePatch is the set of hunks for one file. oldPath or newPath is empty
+// for created and deleted files.
+type filePatch struct {
+	oldPath string
+	newPath string
+	hunks   []patchHunk
+}
+
+type patchHunk struct {
+	oldStart int
+	lines    []string // each prefixed with ' ', '-' or '+'
+	oldNoEOL bool
+	newNoEOL bool
+}
+
+var reHunkHeader = regexp.MustCompile(`^@@ -(\d+)(?:,(\d+))? \+(\d+)(?:,(\d+))? @@`)
+
+// parsePatch splits a unified diff into per-file patches.
+func parsePatch(text string) ([]*filePatch, error) {
+	lines := strings.Split(strings.ReplaceAll(text, "\r\n", "\n"), "\n")
+	var patches []*filePatch
+
+	for i := 0; i < len(lines); i++ {
+		if !strings.HasPrefix(lines[i], "--- ") {
+			continue
+		}
+		if i+1 >= len(lines) || !strings.HasPrefix(lines[i+1], "+++ ") {
+			return nil, fmt.Errorf("line %d: expected +++ header after ---", i+1)
+		}
+		fp := &filePatch{
+			oldPath: patchPath(lines[i][4:], "a/"),
+			newPath: patchPath(lines[i+1][4:], "b/"),
+		}
+		if fp.oldPath == "" && fp.newPath == "" {
+			return nil, fmt.Errorf("line %d: both files are /dev/null", i+1)
+		}
+		i += 2
+
+		for i < len(lines) {
+			m := reHunkHeader.FindStringSubmatch(lines[i])
+			if m == nil {
+				break
+			}
+			h := patchHunk{oldStart: atoiDefault(m[1], 0)}
+			// "\ No newline at end of file" refers to the line before it
+			markNoEOL := func() {
+				if len(h.lines) == 0 {
+					return
+				}
+				switch h.lines[len(h.lines)-1][0] {
+				case '-':
+					h.oldNoEOL = true
+				case '+':
+					h.newNoEOL = true
+				default:
+					h.oldNoEOL, h.newNoEOL = true, true
+				}
+			}
+			oldCount, newCount := atoiDefault(m[2], 1), atoiDefault(m[4], 1)
+			i++
+			for oldCount > 0 || newCount > 0 {
+				if i >= len(lines) {
+					return nil, fmt.Errorf("%s: hunk at line %d is truncated", fp.displayPath(), h.oldStart)
+				}
+				line := lines[i]
+				if line == "" {
+					line = " " // blank context line with trailing space stripped
+				}
+				switch line[0] {
+				case ' ':
+					oldCount--
+					newCount--
+				case '-':
+					oldCount--
+				case '+':
+					newCount--
+				case '\\':
+					markNoEOL()
+					i++
+					continue
+				default:
+					return nil, fmt.Errorf("%s: unexpected line in hunk: %q", fp.displayPath(), line)
+				}
+				h.lines = append(h.lines, line)
+				i++
+			}
+			if oldCount < 0 || newCount < 0 {
+				return nil, fmt.Errorf("%s: hunk at line %d does not match its header counts", fp.displayPath(), h.oldStart)
+			}
+			if i < len(lines) && strings.HasPrefix(lines[i], `\`) {
+				markNoEOL()
+				i++
+			}
+			fp.hunks = append(fp.hunks, h)
+		}
+		i--
+
+		if len(fp.hunks) == 0 && fp.oldPath == fp.newPath {
+			return nil, fmt.Errorf("%s: no hunks", fp.displayPath())
+		}
+		patches = append(patches, fp)
+	}
+
+	if len(patches) == 0 {
+		return nil, fmt.Errorf("no file headers (--- / +++) found in patch")
+	}
+	return patches, nil
+}
+
+// patchPath extracts the file path from a ---/+++ header value.
+func patchPath(header, prefix string) string {
+	if i := strings.IndexByte(header, '\t'); i >= 0 {
+		header = header[:i]
+	}
+	header = strings.TrimSpace(header)
+	if header == "/dev/null" {
+		return ""
+	}
+	return strings.TrimPrefix(header, prefix)
+}
+
+func atoiDefault(s string, def int) int {
+	if s == "" {
+		return def
+	}
+	n, err := strconv.Atoi(s)
+	if err != nil {
+		return def
+	}
+	return n
+}
+
+func (fp *filePatch) displayPath() string {
+	if fp.newPath != "" {
+		return fp.newPath
+	}
+	return fp.oldPath
+}
+
+// apply applies the hunks to content. Hunks may have drifted from their
+// stated line numbers, but their context must match exactly.
+func (fp *filePatch) apply(content string) (string, int, int, error) {
+	var lines []string
+	eol := true
+	if content != "" {
+		eol = strings.HasSuffix(content, "\n")
+		lines = strings.Split(strings.TrimSuffix(content, "\n"), "\n")
+	}
+
+	added, removed := 0, 0
+	offset, minPos := 0, 0
+	for n, h := range fp.hunks {
+		var oldBlock, newBlock []string
+		for _, l := range h.lines {
+			switch l[0] {
+			case ' ':
+				oldBlock = append(oldBlock, l[1:])
+				newBlock = append(newBlock, l[1:])
+			case '-':
+				oldBlock = append(oldBlock, l[1:])
+				removed++
+			case '+':
+				newBlock = append(newBlock, l[1:])
+				added++
+			}
+		}
+
+		want := h.oldStart - 1 + offset
+		if len(oldBlock) == 0 {
+			want = h.oldStart + offset // pure insertion after line oldStart
+		}
+		pos := findBlock(lines, oldBlock, want, minPos)
+		if pos < 0 {
+			return "", 0, 0, fmt.Errorf("hunk %d of %s does not match the file near line %d", n+1, fp.displayPath(), h.oldStart)
+		}
+
+		rest := append(append([]string{}, newBlock...), lines[pos+len(oldBlock):]...)
+		lines = append(lines[:pos], rest...)
+		offset += pos - want + len(newBlock) - len(oldBlock)
+		minPos = pos + len(newBlock)
+
+		if h.newNoEOL {
+			eol = false
+		} else if h.oldNoEOL {
+			eol = true
+		}
+	}
+
+	if len(lines) == 0 {
+		return "", added, removed, nil
+	}
+	result := strings.Join(lines, "\n")
+	if eol {
+		result += "\n"
+	}
+	return result, added, removed, nil
+}
+
+// findBlock returns the index of block in lines closest to want, not before
+// minPos, or -1.
+func findBlock(lines, block []string, want, minPos int) int {
+	matches := func(pos int) bool {
+		if pos < minPos || pos+len(block) > len(lines) {
+			return false
+		}
+		for i, l := range block {
+			if lines[pos+i] != l {
+				return false
+			}
+		}
+		return true
+	}
+	for d := 0; d <= len(lines); d++ {
+		if matches(want - d) {
+			return want - d
+		}
+		if d > 0 && matches(want+d) {
+			return want + d
+		}
+	}
+	return -1
+}
+
+// patchResult is the planned change to one file.
+type patchResult struct {
+	fp      *filePatch
+	path    string // file to write (or delete)
+	oldPath string // file to remove after a rename
+	content string
+	orig    []byte
+	existed bool
+	added   int
+	removed int
+}
+
+func (a *ApplyPatch) resolve(path string) string {
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(a.workDir, path)
+	}
+	return path
+}
+
+func (a *ApplyPatch) Execute(ctx context.Context, params json.RawMessage) (*Result, error) {
+	var p applyPatchParams
+	if err := json.Unmarshal(params, &p); err != nil {
+		return &Result{Content: fmt.Sprintf("invalid params: %v", err), IsError: true}, nil
+	}
+	if strings.TrimSpace(p.Patch) == "" {
+		return &Result{Content: "patch is required", IsError: true}, nil
+	}
+
+	patches, err := parsePatch(p.Patch)
+	if err != nil {
+		return &Result{Content: fmt.Sprintf("invalid patch: %v", err), IsError: true}, nil
+	}
+
+	// Compute every result before touching the filesystem
+	var results []*patchResult
+	for _, fp := range patches {
+		r := &patchResult{fp: fp}
+		var current string
+		if fp.oldPath != "" {
+			data, err := os.ReadFile(a.resolve(fp.oldPath))
+			if err != nil {
+				if os.IsNotExist(err) {
+					return &Result{Content: fmt.Sprintf("file not found: %s", fp.oldPath), IsError: true}, nil
+				}
+				return &Result{Content: fmt.Sprintf("failed to read file: %v", err), IsError: true}, nil
+			}
+			current = string(data)
+		}
+
+		if fp.newPath == "" {
+			r.path = a.resolve(fp.oldPath)
+		} else {
+			r.path = a.resolve(fp.newPath)
+			if fp.oldPath != fp.newPath {
+				if _, err := os.Stat(r.path); err == nil {
+					return &Result{Content: fmt.Sprintf("file already exists: %s", fp.newPath), IsError: true}, nil
+				}
+				if fp.oldPath != "" {
+					r.oldPath = a.resolve(fp.oldPath)
+				}
+			}
+		}
+
+		r.content, r.added, r.removed, err = fp.apply(current)
+		if err != nil {
+			return &Result{Content: fmt.Sprintf("patch rejected, no files changed: %v", err), IsError: true}, nil
+		}
+		if fp.newPath == "" && r.content != "" {
+			return &Result{Content: fmt.Sprintf("patch rejected, no files changed: %s is not empty after removing its lines", fp.oldPath), IsError: true}, nil
+		}
+		results = append(results, r)
+	}
+
+	var sb strings.Builder
+	if p.DryRun {
+		sb.WriteString("Patch applies cleanly (dry run, no files changed):\n")
+	} else {
+		if err := a.write(results); err != nil {
+			return &Result{Content: fmt.Sprintf("failed to apply patch: %v", err), IsError: true}, nil
+		}
+		sb.WriteString("Patch applied:\n")
+	}
+	for _, r := range results {
+		switch {
+		case r.fp.oldPath == "":
+			_, _ = fmt.Fprintf(&sb, "  A %s (+%d)\n", r.fp.newPath, r.added)
+		case r.fp.newPath == "":
+			_, _ = fmt.Fprintf(&sb, "  D %s (-%d)\n", r.fp.oldPath, r.removed)
+		case r.fp.oldPath != r.fp.newPath:
+			_, _ = fmt.Fprintf(&sb, "  R %s -> %s (+%d -%d)\n", r.fp.oldPath, r.fp.newPath, r.added, r.removed)
+		default:
+			_, _ = fmt.Fprintf(&sb, "  M %s (+%d -%d)\n", r.fp.newPath, r.added, r.removed)
+		}
+	}
+	return &Result{Content: sb.String()}, nil
+}
+
+// write applies the planned results, restoring already written files if a
+// later one fails.
+func (a *ApplyPatch) write(results []*patchResult) error {
+	var done []*patchResult
+	rollback := func() {
+		for i := len(done) - 1; i >= 0; i-- {
+			r := done[i]
+			if r.existed {
+				_ = os.WriteFile(r.path, r.orig, 0644)
+			} else {
+				_ = os.Remove(r.path)
+			}
+		}
+	}
+
+	for _, r := range results {
+		if data, err := os.ReadFile(r.path); err == nil {
+			r.orig, r.existed = data, true
+		}
+
+		var err error
+		if r.fp.newPath == "" {
+			err = os.Remove(r.path)
+		} else {
+			if err = os.MkdirAll(filepath.Dir(r.path), 0755); err == nil {
+				err = os.WriteFile(r.path, []byte(r.content), 0644)
+			}
+		}
+		if err != nil {
+			rollback()
+			return err
+		}
+		done = append(done, r)
+	}
+
+	// Renames: remove the old files once everything is written
+	for _, r := range results {
+		if r.oldPath != "" {
+			_ = os.Remove(r.oldPath)
+		}
+	}
+	return nil
+}
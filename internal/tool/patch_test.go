@@ -0,0 +1,102 @@
+package tool
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func runApplyPatch(t *testing.T, dir, patch string, dryRun bool) *Result {
+	t.Helper()
+	params, _ := json.Marshal(applyPatchParams{Patch: patch, DryRun: dryRun})
+	res, err := NewApplyPatch(dir).Execute(context.Background(), params)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return res
+}
+
+func TestApplyPatch(t *testing.T) {
+	dir := t.TempDir()
+	main := "package main\n\nfunc main() {\n\tprintln(\"hello\")\n}\n"
+	if err := os.WriteFile(filepath.Join(dir, "main.go"), []byte("// header\n"+main), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "old.txt"), []byte("bye\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	// Line numbers are off by one (the header line); context still matches.
+	patch := `diff --git a/main.go b/main.go
+--- a/main.go
++++ b/main.go
+@@ -3,3 +3,4 @@
+ func main() {
+-	println("hello")
++	println("hello, world")
++	println("done")
+ }
+--- /dev/null
++++ b/docs/new.md
+@@ -0,0 +1,2 @@
++# New
++file
+--- a/old.txt
++++ /dev/null
+@@ -1 +0,0 @@
+-bye
+`
+
+	res := runApplyPatch(t, dir, patch, true)
+	if res.IsError || !strings.Contains(res.Content, "dry run") {
+		t.Fatalf("dry run failed: %s", res.Content)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "docs/new.md")); err == nil {
+		t.Fatal("dry run wrote files")
+	}
+
+	res = runApplyPatch(t, dir, patch, false)
+	if res.IsError {
+		t.Fatalf("apply failed: %s", res.Content)
+	}
+	got, _ := os.ReadFile(filepath.Join(dir, "main.go"))
+	want := "// header\npackage main\n\nfunc main() {\n\tprintln(\"hello, world\")\n\tprintln(\"done\")\n}\n"
+	if string(got) != want {
+		t.Errorf("main.go = %q, want %q", got, want)
+	}
+	if got, _ := os.ReadFile(filepath.Join(dir, "docs/new.md")); string(got) != "# New\nfile\n" {
+		t.Errorf("new.md = %q", got)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "old.txt")); !os.IsNotExist(err) {
+		t.Error("old.txt was not deleted")
+	}
+}
+
+func TestApplyPatch_RejectsMismatchAtomically(t *testing.T) {
+	dir := t.TempDir()
+	_ = os.WriteFile(filepath.Join(dir, "a.txt"), []byte("one\ntwo\n"), 0644)
+	_ = os.WriteFile(filepath.Join(dir, "b.txt"), []byte("three\n"), 0644)
+
+	patch := `--- a/a.txt
++++ b/a.txt
+@@ -1,2 +1,2 @@
+ one
+-two
++2
+--- a/b.txt
++++ b/b.txt
+@@ -1 +1 @@
+-four
++4
+`
+	res := runApplyPatch(t, dir, patch, false)
+	if !res.IsError || !strings.Contains(res.Content, "hunk 1 of b.txt") {
+		t.Fatalf("expected rejection, got: %s", res.Content)
+	}
+	if got, _ := os.ReadFile(filepath.Join(dir, "a.txt")); string(got) != "one\ntwo\n" {
+		t.Errorf("a.txt changed despite rejection: %q", got)
+	}
+}
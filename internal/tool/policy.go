@@ -0,0 +1,222 @@
+package tool
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// Policy restricts what an agent's tools may do. Empty fields impose no
+// restriction.
+type Policy struct {
+	AllowCommands  []string // regexes; bash commands must match at least one
+	DenyCommands   []string // regexes; matching bash commands are refused
+	WritablePaths  []string // path prefixes write/edit/apply_patch may modify
+	AllowedDomains []string // hosts the network tools may reach
+}
+
+// IsZero reports whether the policy imposes no restrictions.
+func (p Policy) IsZero() bool {
+	return len(p.AllowCommands) == 0 && len(p.DenyCommands) == 0 &&
+		len(p.WritablePaths) == 0 && len(p.AllowedDomains) == 0
+}
+
+// Tools whose "url" parameter is checked against AllowedDomains.
+var policyNetworkTools = map[string]bool{
+	"http_get": true, "http_post": true, "http_request": true,
+	"web_fetch": true, "browser_open": true,
+}
+
+// compiledPolicy is a Policy with its regexes and paths prepared.
+type compiledPolicy struct {
+	allow    []*regexp.Regexp
+	deny     []*regexp.Regexp
+	writable []string
+	domains  []string
+	workDir  string
+}
+
+// WithPolicy returns a new registry whose tools enforce p. Relative
+// writable paths are resolved against workDir.
+func (r *Registry) WithPolicy(p Policy, workDir string) (*Registry, error) {
+	if p.IsZero() {
+		return r, nil
+	}
+
+	cp := &compiledPolicy{domains: p.AllowedDomains, workDir: workDir}
+	for _, expr := range p.AllowCommands {
+		re, err := regexp.Compile(expr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid allow command pattern %q: %w", expr, err)
+		}
+		cp.allow = append(cp.allow, re)
+	}
+	for _, expr := range p.DenyCommands {
+		re, err := regexp.Compile(expr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid deny command pattern %q: %w", expr, err)
+		}
+		cp.deny = append(cp.deny, re)
+	}
+	for _, prefix := range p.WritablePaths {
+		cp.writable = append(cp.writable, cp.resolve(prefix))
+	}
+
+	wrapped := NewRegistry()
+	for _, t := range r.tools {
+		wrapped.Register(&policyTool{Tool: t, policy: cp})
+	}
+	return wrapped, nil
+}
+
+func (cp *compiledPolicy) resolve(path string) string {
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(cp.workDir, path)
+	}
+	return filepath.Clean(path)
+}
+
+// checkWritable returns a non-empty reason if path is outside the writable paths.
+func (cp *compiledPolicy) checkWritable(path string) string {
+	path = cp.resolve(path)
+	for _, prefix := range cp.writable {
+		if path == prefix || strings.HasPrefix(path, prefix+string(filepath.Separator)) {
+			return ""
+		}
+	}
+	return fmt.Sprintf("%s is outside the writable paths", path)
+}
+
+// check returns a non-empty reason if the call is not permitted.
+func (cp *compiledPolicy) check(name string, params json.RawMessage) string {
+	switch {
+	case name == "bash" || name == "background_run":
+		var p struct {
+			Command string `json:"command"`
+		}
+		_ = json.Unmarshal(params, &p)
+		if p.Command == "" && name == "background_run" {
+			return "" // agent run, not a command
+		}
+		for _, re := range cp.deny {
+			if re.MatchString(p.Command) {
+				return fmt.Sprintf("command matches denied pattern %q", re.String())
+			}
+		}
+		if len(cp.allow) > 0 {
+			for _, re := range cp.allow {
+				if re.MatchString(p.Command) {
+					return ""
+				}
+			}
+			return "command does not match any allowed pattern"
+		}
+
+	case name == "write" || name == "edit":
+		if len(cp.writable) == 0 {
+			return ""
+		}
+		var p struct {
+			Path string `json:"path"`
+		}
+		_ = json.Unmarshal(params, &p)
+		return cp.checkWritable(p.Path)
+
+	case name == "apply_patch":
+		if len(cp.writable) == 0 {
+			return ""
+		}
+		var p struct {
+			Patch string `json:"patch"`
+		}
+		_ = json.Unmarshal(params, &p)
+		patches, err := parsePatch(p.Patch)
+		if err != nil {
+			return "" // the tool reports the invalid patch
+		}
+		for _, fp := range patches {
+			for _, path := range []string{fp.oldPath, fp.newPath} {
+				if path == "" {
+					continue
+				}
+				if reason := cp.checkWritable(path); reason != "" {
+					return reason
+				}
+			}
+		}
+
+	case policyNetworkTools[name]:
+		if len(cp.domains) == 0 {
+			return ""
+		}
+		var p struct {
+			URL string `json:"url"`
+		}
+		_ = json.Unmarshal(params, &p)
+		u, err := url.Parse(p.URL)
+		if err != nil || u.Hostname() == "" {
+			return "invalid URL"
+		}
+		return domainReason(cp.domains, u.Hostname())
+	}
+	return ""
+}
+
+// domainReason returns a non-empty reason if host is not in domains.
+func domainReason(domains []string, host string) string {
+	for _, d := range domains {
+		if domainMatches(host, d) {
+			return ""
+		}
+	}
+	return fmt.Sprintf("domain %s is not in the allowed domains list", host)
+}
+
+type allowedDomainsKey struct{}
+
+// withAllowedDomains attaches the allowed domains of the agent policy to
+// ctx, for the network tools to check the hosts they are redirected to.
+func withAllowedDomains(ctx context.Context, domains []string) context.Context {
+	if len(domains) == 0 {
+		return ctx
+	}
+	return context.WithValue(ctx, allowedDomainsKey{}, domains)
+}
+
+// allowedDomains returns the allowed domains of the agent policy in ctx;
+// nil when any domain is allowed.
+func allowedDomains(ctx context.Context) []string {
+	domains, _ := ctx.Value(allowedDomainsKey{}).([]string)
+	return domains
+}
+
+// policyDomainReason returns a non-empty reason if the agent policy in ctx
+// does not allow host.
+func policyDomainReason(ctx context.Context, host string) string {
+	domains := allowedDomains(ctx)
+	if len(domains) == 0 {
+		return ""
+	}
+	return domainReason(domains, host)
+}
+
+// policyTool wraps a tool and refuses calls that violate the policy.
+type policyTool struct {
+	Tool
+	policy *compiledPolicy
+}
+
+func (t *policyTool) WorkDir() string {
+	return WorkDir(t.Tool)
+}
+
+func (t *policyTool) Execute(ctx context.Context, params json.RawMessage) (*Result, error) {
+	if reason := t.policy.check(t.Name(), params); reason != "" {
+		return &Result{Content: fmt.Sprintf("Blocked by agent policy: %s", reason), IsError: true}, nil
+	}
+	return t.Tool.Execute(withAllowedDomains(ctx, t.policy.domains), params)
+}
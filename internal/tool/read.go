@@ -19,6 +19,11 @@ func NewRead(workDir string) *Read {
 	return &Read{workDir: workDir}
 }
 
+// WorkDir returns the directory relative paths are resolved against.
+func (r *Read) WorkDir() string {
+	return r.workDir
+}
+
 func (r *Read) Name() string {
 	return "read"
 }
@@ -0,0 +1,142 @@
+package tool
+
+import (
+	"regexp"
+	"strings"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+// Tags that never contain article content.
+var readabilitySkipTags = map[atom.Atom]bool{
+	atom.Script: true, atom.Style: true, atom.Noscript: true, atom.Nav: true,
+	atom.Header: true, atom.Footer: true, atom.Aside: true, atom.Form: true,
+	atom.Svg: true, atom.Iframe: true, atom.Button: true, atom.Select: true,
+}
+
+// Class/id fragments that mark boilerplate blocks.
+var reBoilerplate = regexp.MustCompile(`(?i)(comment|sidebar|footer|menu|banner|cookie|share|social|related|promo|advert|popup|newsletter)`)
+
+// extractReadable returns the page title and the main article text,
+// using a paragraph-density heuristic similar to Readability.
+func extractReadable(page string) (string, string) {
+	doc, err := html.Parse(strings.NewReader(page))
+	if err != nil {
+		return "", cleanText(stripHTML(page))
+	}
+
+	title := ""
+	var body *html.Node
+	scores := make(map[*html.Node]float64)
+
+	var walk func(n *html.Node)
+	walk = func(n *html.Node) {
+		if n.Type == html.ElementNode {
+			if readabilitySkipTags[n.DataAtom] || isBoilerplate(n) {
+				return
+			}
+			switch n.DataAtom {
+			case atom.Title:
+				if title == "" {
+					title = strings.TrimSpace(nodeText(n))
+				}
+			case atom.Body:
+				body = n
+			case atom.P, atom.Pre, atom.Blockquote:
+				text := strings.TrimSpace(nodeText(n))
+				if len(text) >= 25 && n.Parent != nil {
+					score := 1 + float64(strings.Count(text, ",")) + float64(min(len(text)/100, 3))
+					scores[n.Parent] += score
+					if n.Parent.Parent != nil {
+						scores[n.Parent.Parent] += score / 2
+					}
+				}
+			}
+		}
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			walk(c)
+		}
+	}
+	walk(doc)
+
+	var best *html.Node
+	bestScore := 0.0
+	for n, s := range scores {
+		if s > bestScore {
+			best, bestScore = n, s
+		}
+	}
+	if best == nil || bestScore < 3 {
+		best = body
+	}
+	if best == nil {
+		best = doc
+	}
+
+	var sb strings.Builder
+	renderReadable(&sb, best)
+	return title, cleanText(sb.String())
+}
+
+func isBoilerplate(n *html.Node) bool {
+	if n.DataAtom == atom.Body || n.DataAtom == atom.Html || n.DataAtom == atom.Article || n.DataAtom == atom.Main {
+		return false
+	}
+	for _, a := range n.Attr {
+		if (a.Key == "class" || a.Key == "id" || a.Key == "role") && reBoilerplate.MatchString(a.Val) {
+			return true
+		}
+	}
+	return false
+}
+
+// nodeText returns the concatenated text content of n.
+func nodeText(n *html.Node) string {
+	var sb strings.Builder
+	var walk func(*html.Node)
+	walk = func(n *html.Node) {
+		if n.Type == html.TextNode {
+			sb.WriteString(n.Data)
+		}
+		if n.Type == html.ElementNode && readabilitySkipTags[n.DataAtom] {
+			return
+		}
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			walk(c)
+		}
+	}
+	walk(n)
+	return sb.String()
+}
+
+// renderReadable writes n as plain text with light markdown for headings and lists.
+func renderReadable(sb *strings.Builder, n *html.Node) {
+	switch n.Type {
+	case html.TextNode:
+		sb.WriteString(n.Data)
+		return
+	case html.ElementNode:
+		if readabilitySkipTags[n.DataAtom] || isBoilerplate(n) {
+			return
+		}
+		switch n.DataAtom {
+		case atom.H1, atom.H2, atom.H3, atom.H4, atom.H5, atom.H6:
+			level := int(n.Data[1] - '0')
+			sb.WriteString("\n\n" + strings.Repeat("#", level) + " " + strings.TrimSpace(nodeText(n)) + "\n")
+			return
+		case atom.Li:
+			sb.WriteString("\n- ")
+		case atom.Br:
+			sb.WriteString("\n")
+		case atom.P, atom.Div, atom.Section, atom.Article, atom.Tr, atom.Pre, atom.Blockquote, atom.Ul, atom.Ol, atom.Table:
+			sb.WriteString("\n\n")
+		}
+	}
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		renderReadable(sb, c)
+	}
+	if n.Type == html.ElementNode && (n.DataAtom == atom.P || n.DataAtom == atom.Div) {
+		sb.WriteString("\n")
+	}
+}
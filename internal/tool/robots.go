@@ -0,0 +1,175 @@
+package tool
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// robotsUserAgent is the product token matched against robots.txt groups.
+const robotsUserAgent = "klaw"
+
+// robotsAgentToken returns the product token of a User-agent value, e.g.
+// "klaw" for "Klaw/1.0", lowercased.
+func robotsAgentToken(value string) string {
+	token, _, _ := strings.Cut(strings.TrimSpace(value), "/")
+	if fields := strings.Fields(token); len(fields) > 0 {
+		token = fields[0]
+	}
+	return strings.ToLower(token)
+}
+
+// robotsRules holds the Allow/Disallow rules that apply to klaw for one host.
+type robotsRules struct {
+	allow     []string
+	disallow  []string
+	fetchedAt time.Time
+}
+
+// allowed applies longest-match semantics; Allow wins ties.
+func (r *robotsRules) allowed(path string) bool {
+	best, allow := -1, true
+	for _, p := range r.disallow {
+		if p != "" && strings.HasPrefix(path, p) && len(p) > best {
+			best, allow = len(p), false
+		}
+	}
+	for _, p := range r.allow {
+		if strings.HasPrefix(path, p) && len(p) >= best {
+			best, allow = len(p), true
+		}
+	}
+	return allow
+}
+
+// robotsCache fetches and caches robots.txt per host.
+type robotsCache struct {
+	client *http.Client
+	ttl    time.Duration
+	mu     sync.Mutex
+	hosts  map[string]*robotsRules
+}
+
+func newRobotsCache(client *http.Client) *robotsCache {
+	return &robotsCache{
+		client: client,
+		ttl:    time.Hour,
+		hosts:  make(map[string]*robotsRules),
+	}
+}
+
+// Allowed reports whether klaw may fetch u. Missing or unreadable
+// robots.txt files allow everything.
+func (c *robotsCache) Allowed(ctx context.Context, u *url.URL) bool {
+	key := u.Scheme + "://" + u.Host
+
+	c.mu.Lock()
+	rules, ok := c.hosts[key]
+	c.mu.Unlock()
+
+	if !ok || time.Since(rules.fetchedAt) > c.ttl {
+		rules = c.fetch(ctx, key)
+		c.mu.Lock()
+		c.hosts[key] = rules
+		c.mu.Unlock()
+	}
+
+	path := u.EscapedPath()
+	if path == "" {
+		path = "/"
+	}
+	if u.RawQuery != "" {
+		path += "?" + u.RawQuery
+	}
+	return rules.allowed(path)
+}
+
+func (c *robotsCache) fetch(ctx context.Context, origin string) *robotsRules {
+	rules := &robotsRules{fetchedAt: time.Now()}
+
+	req, err := http.NewRequestWithContext(ctx, "GET", origin+"/robots.txt", nil)
+	if err != nil {
+		return rules
+	}
+	req.Header.Set("User-Agent", "Mozilla/5.0 (compatible; Klaw/1.0; +https://github.com/eachlabs/klaw)")
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return rules
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode != http.StatusOK {
+		return rules
+	}
+
+	return parseRobots(io.LimitReader(resp.Body, 512*1024), rules)
+}
+
+// parseRobots collects rules for the klaw group, falling back to "*".
+func parseRobots(r io.Reader, rules *robotsRules) *robotsRules {
+	type group struct{ allow, disallow []string }
+	var specific, wildcard *group
+	var current []*group
+	inAgents := false
+
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if i := strings.Index(line, "#"); i >= 0 {
+			line = line[:i]
+		}
+		field, value, ok := strings.Cut(line, ":")
+		if !ok {
+			continue
+		}
+		field = strings.ToLower(strings.TrimSpace(field))
+		value = strings.TrimSpace(value)
+
+		switch field {
+		case "user-agent":
+			if !inAgents {
+				current = nil
+			}
+			inAgents = true
+			agent := strings.ToLower(value)
+			switch {
+			case agent == "*":
+				if wildcard == nil {
+					wildcard = &group{}
+				}
+				current = append(current, wildcard)
+			case robotsAgentToken(agent) == robotsUserAgent:
+				if specific == nil {
+					specific = &group{}
+				}
+				current = append(current, specific)
+			}
+		case "allow", "disallow":
+			inAgents = false
+			for _, g := range current {
+				if field == "allow" {
+					g.allow = append(g.allow, value)
+				} else {
+					g.disallow = append(g.disallow, value)
+				}
+			}
+		default:
+			inAgents = false
+		}
+	}
+
+	chosen := specific
+	if chosen == nil {
+		chosen = wildcard
+	}
+	if chosen != nil {
+		rules.allow = chosen.allow
+		rules.disallow = chosen.disallow
+	}
+	return rules
+}
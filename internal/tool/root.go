@@ -0,0 +1,145 @@
+package tool
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// rootedTools are the tools WithRoot moves to a directory, by the
+// constructor of an instance working there.
+var rootedTools = map[string]func(dir string) Tool{
+	"bash":        func(dir string) Tool { return NewBash(dir) },
+	"read":        func(dir string) Tool { return NewRead(dir) },
+	"write":       func(dir string) Tool { return NewWrite(dir) },
+	"edit":        func(dir string) Tool { return NewEdit(dir) },
+	"apply_patch": func(dir string) Tool { return NewApplyPatch(dir) },
+	"glob":        func(dir string) Tool { return NewGlob(dir) },
+	"grep":        func(dir string) Tool { return NewGrep(dir) },
+}
+
+// WithRoot returns a new registry whose file tools work in dir, which is
+// created if missing. read, write, edit, apply_patch, glob and grep refuse
+// paths outside dir, following symlinks; bash starts in dir but is not
+// confined to it, unless it runs in a Sandbox. Tools with an InDir method
+// are moved with it. The other tools of r are kept as they are.
+func (r *Registry) WithRoot(dir string) (*Registry, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("working directory: %w", err)
+	}
+	root, err := filepath.Abs(dir)
+	if err != nil {
+		return nil, err
+	}
+	if root, err = filepath.EvalSymlinks(root); err != nil {
+		return nil, err
+	}
+
+	rooted := NewRegistry()
+	for name, t := range r.tools {
+		if mover, ok := t.(interface{ InDir(string) Tool }); ok {
+			rooted.Register(mover.InDir(root))
+			continue
+		}
+		newTool, ok := rootedTools[name]
+		if !ok {
+			rooted.Register(t)
+			continue
+		}
+		t = newTool(root)
+		if name != "bash" {
+			t = &rootedTool{Tool: t, root: root}
+		}
+		rooted.Register(t)
+	}
+	return rooted, nil
+}
+
+// rootedTool wraps a file tool and refuses calls with paths outside root.
+type rootedTool struct {
+	Tool
+	root string
+}
+
+func (t *rootedTool) Execute(ctx context.Context, params json.RawMessage) (*Result, error) {
+	if reason := t.check(params); reason != "" {
+		return &Result{Content: fmt.Sprintf("Blocked: %s", reason), IsError: true}, nil
+	}
+	return t.Tool.Execute(ctx, params)
+}
+
+// WorkDir returns the root, which the tool resolves relative paths against.
+func (t *rootedTool) WorkDir() string {
+	return t.root
+}
+
+// check returns a non-empty reason if the call reaches outside the root.
+func (t *rootedTool) check(params json.RawMessage) string {
+	var p struct {
+		Path    string `json:"path"`
+		Pattern string `json:"pattern"`
+		Patch   string `json:"patch"`
+	}
+	_ = json.Unmarshal(params, &p)
+
+	paths := []string{p.Path}
+	switch t.Name() {
+	case "glob":
+		if filepath.IsAbs(p.Pattern) || hasDotDot(p.Pattern) {
+			return fmt.Sprintf("pattern %s reaches outside the working directory", p.Pattern)
+		}
+	case "apply_patch":
+		patches, err := parsePatch(p.Patch)
+		if err != nil {
+			return "" // the tool reports the invalid patch
+		}
+		for _, fp := range patches {
+			paths = append(paths, fp.oldPath, fp.newPath)
+		}
+	}
+	for _, path := range paths {
+		if path != "" && !t.contains(path) {
+			return fmt.Sprintf("%s is outside the working directory %s", path, t.root)
+		}
+	}
+	return ""
+}
+
+// contains reports whether path, resolved against the root and with its
+// symlinks followed, is within the root.
+func (t *rootedTool) contains(path string) bool {
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(t.root, path)
+	}
+	path = filepath.Clean(path)
+
+	// Follow the symlinks of the longest part that exists
+	existing, rest := path, ""
+	for {
+		if resolved, err := filepath.EvalSymlinks(existing); err == nil {
+			path = filepath.Join(resolved, rest)
+			break
+		}
+		parent := filepath.Dir(existing)
+		if parent == existing {
+			break
+		}
+		rest = filepath.Join(filepath.Base(existing), rest)
+		existing = parent
+	}
+	return path == t.root || strings.HasPrefix(path, t.root+string(filepath.Separator))
+}
+
+// hasDotDot reports whether a slash or separator separated path has a ".."
+// element.
+func hasDotDot(path string) bool {
+	for _, elem := range strings.FieldsFunc(path, func(r rune) bool { return r == '/' || r == filepath.Separator }) {
+		if elem == ".." {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,94 @@
+package tool
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+)
+
+type conversationKey struct{}
+
+// WithConversationID records the conversation the tools run for in ctx.
+func WithConversationID(ctx context.Context, id string) context.Context {
+	if id == "" {
+		return ctx
+	}
+	return context.WithValue(ctx, conversationKey{}, id)
+}
+
+// ConversationIDFromContext returns the conversation the tools run for, or
+// "" if unset (e.g. cron jobs and agent_dispatch runs).
+func ConversationIDFromContext(ctx context.Context) string {
+	id, _ := ctx.Value(conversationKey{}).(string)
+	return id
+}
+
+// Sandbox runs shell commands isolated from the host, e.g. in containers,
+// with dir mounted as their working directory. Commands of the same key
+// share an environment until it is released; those without a key get one
+// of their own.
+type Sandbox interface {
+	Exec(ctx context.Context, key, dir, command string, env []string, timeout time.Duration) (output string, err error)
+}
+
+// SandboxBash is the bash tool running its commands in a Sandbox, one
+// environment per conversation.
+type SandboxBash struct {
+	sandbox Sandbox
+	workDir string
+}
+
+// NewSandboxBash creates a bash tool whose commands run in sb with workDir
+// mounted.
+func NewSandboxBash(sb Sandbox, workDir string) *SandboxBash {
+	return &SandboxBash{sandbox: sb, workDir: workDir}
+}
+
+// InDir returns the tool with dir mounted instead, see Registry.WithRoot.
+func (b *SandboxBash) InDir(dir string) Tool {
+	return NewSandboxBash(b.sandbox, dir)
+}
+
+func (b *SandboxBash) Name() string {
+	return "bash"
+}
+
+func (b *SandboxBash) Description() string {
+	return `Execute a bash command in an isolated container. Use for running shell commands, git operations, package management, etc.
+The command runs in /workspace, the working directory. Only files there are shared with the other tools.
+Returns stdout/stderr combined. Exit code 0 = success.`
+}
+
+func (b *SandboxBash) Schema() json.RawMessage {
+	return NewBash(b.workDir).Schema()
+}
+
+func (b *SandboxBash) Execute(ctx context.Context, params json.RawMessage) (*Result, error) {
+	var p bashParams
+	if err := json.Unmarshal(params, &p); err != nil {
+		return &Result{Content: fmt.Sprintf("invalid params: %v", err), IsError: true}, nil
+	}
+	if p.Command == "" {
+		return &Result{Content: "command is required", IsError: true}, nil
+	}
+
+	timeout := p.Timeout
+	if timeout <= 0 {
+		timeout = 120
+	}
+
+	output, err := b.sandbox.Exec(ctx, ConversationIDFromContext(ctx), b.workDir, p.Command, skillConfigEnv(ctx), time.Duration(timeout)*time.Second)
+	output = strings.TrimSpace(output)
+	if len(output) > 30000 {
+		output = output[:30000] + "\n... (output truncated)"
+	}
+	if err != nil {
+		return &Result{Content: fmt.Sprintf("%v\n%s", err, output), IsError: true}, nil
+	}
+	if output == "" {
+		output = "(no output)"
+	}
+	return &Result{Content: output}, nil
+}
@@ -0,0 +1,56 @@
+package tool
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+type fakeSandbox struct {
+	key, dir, command string
+	env               []string
+	err               error
+}
+
+func (f *fakeSandbox) Exec(ctx context.Context, key, dir, command string, env []string, timeout time.Duration) (string, error) {
+	f.key, f.dir, f.command, f.env = key, dir, command, env
+	return "hi\n", f.err
+}
+
+func TestSandboxBash(t *testing.T) {
+	sb := &fakeSandbox{}
+	r := NewRegistry()
+	r.Register(NewSandboxBash(sb, t.TempDir()))
+
+	root := filepath.Join(t.TempDir(), "coder")
+	rooted, err := r.WithRoot(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	bash, _ := rooted.Get("bash")
+	if _, ok := bash.(*SandboxBash); !ok {
+		t.Fatalf("expected WithRoot to keep the sandboxed bash, got %T", bash)
+	}
+
+	ctx := WithConversationID(context.Background(), "C1:1.1")
+	ctx = WithSkillConfig(ctx, map[string]map[string]string{"jira": {"url": "https://jira.test"}})
+	res, err := bash.Execute(ctx, json.RawMessage(`{"command":"echo hi"}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	resolved, _ := filepath.EvalSymlinks(root)
+	if res.IsError || res.Content != "hi" || sb.key != "C1:1.1" || sb.dir != resolved || sb.command != "echo hi" {
+		t.Errorf("unexpected run: %+v, sandbox %+v", res, sb)
+	}
+	if len(sb.env) != 1 || sb.env[0] != "KLAW_JIRA_URL=https://jira.test" {
+		t.Errorf("expected the skill config in the environment, got %v", sb.env)
+	}
+
+	sb.err = errors.New("exit status 1")
+	if res, _ := bash.Execute(ctx, json.RawMessage(`{"command":"false"}`)); !res.IsError {
+		t.Error("expected a failed command to be an error")
+	}
+}
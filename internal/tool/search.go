@@ -0,0 +1,268 @@
+package tool
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/eachlabs/klaw/internal/config"
+)
+
+// SearchConfig selects the web_search backend.
+type SearchConfig struct {
+	Backend string // duckduckgo (default), brave, tavily, searxng
+	APIKey  string
+	URL     string // SearXNG instance URL
+
+	MaxResults int // results returned when the call sets none (default 5)
+}
+
+// SearchBackend runs a web search and returns ranked results.
+type SearchBackend interface {
+	Name() string
+	Search(ctx context.Context, query string, maxResults int) ([]searchResult, error)
+}
+
+// loadSearchConfig reads the [tools.search] section of the klaw config.
+func loadSearchConfig() SearchConfig {
+	cfg, err := config.Load()
+	if err != nil {
+		return SearchConfig{}
+	}
+	return SearchConfig{
+		Backend: cfg.Tools.Search.Backend,
+		APIKey:  cfg.Tools.Search.APIKey,
+		URL:     cfg.Tools.Search.URL,
+
+		MaxResults: cfg.Tools.Search.MaxResults,
+	}
+}
+
+// newSearchBackend builds the backend named in cfg.
+func newSearchBackend(cfg SearchConfig, client *http.Client) (SearchBackend, error) {
+	switch strings.ToLower(cfg.Backend) {
+	case "", "duckduckgo", "ddg":
+		return &duckDuckGoBackend{client: client}, nil
+	case "brave":
+		if cfg.APIKey == "" {
+			return nil, fmt.Errorf("brave search requires an API key (BRAVE_API_KEY)")
+		}
+		return &braveBackend{client: client, apiKey: cfg.APIKey}, nil
+	case "tavily":
+		if cfg.APIKey == "" {
+			return nil, fmt.Errorf("tavily search requires an API key (TAVILY_API_KEY)")
+		}
+		return &tavilyBackend{client: client, apiKey: cfg.APIKey}, nil
+	case "searxng":
+		if cfg.URL == "" {
+			return nil, fmt.Errorf("searxng search requires an instance URL (SEARXNG_URL)")
+		}
+		return &searxngBackend{client: client, baseURL: strings.TrimRight(cfg.URL, "/")}, nil
+	default:
+		return nil, fmt.Errorf("unknown search backend: %s", cfg.Backend)
+	}
+}
+
+// doSearchRequest executes req and decodes a JSON response into out.
+func doSearchRequest(client *http.Client, req *http.Request, out any) error {
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 2*1024*1024))
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("HTTP %d: %s", resp.StatusCode, truncateString(string(body), 200))
+	}
+	return json.Unmarshal(body, out)
+}
+
+// --- DuckDuckGo (HTML, no API key) ---
+
+type duckDuckGoBackend struct {
+	client *http.Client
+}
+
+func (b *duckDuckGoBackend) Name() string { return "duckduckgo" }
+
+func (b *duckDuckGoBackend) Search(ctx context.Context, query string, maxResults int) ([]searchResult, error) {
+	searchURL := "https://html.duckduckgo.com/html/?q=" + url.QueryEscape(query)
+
+	req, err := http.NewRequestWithContext(ctx, "GET", searchURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("User-Agent", "Mozilla/5.0 (compatible; Klaw/1.0)")
+
+	resp, err := b.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	results := parseSearchResults(string(body), maxResults)
+	for i := range results {
+		results[i].URL = unwrapDuckDuckGoURL(results[i].URL)
+	}
+	return results, nil
+}
+
+// unwrapDuckDuckGoURL extracts the target from DuckDuckGo redirect links
+// (//duckduckgo.com/l/?uddg=<url>).
+func unwrapDuckDuckGoURL(link string) string {
+	u, err := url.Parse(link)
+	if err != nil {
+		return link
+	}
+	if target := u.Query().Get("uddg"); target != "" {
+		return target
+	}
+	return link
+}
+
+// --- Brave Search API ---
+
+type braveBackend struct {
+	client *http.Client
+	apiKey string
+}
+
+func (b *braveBackend) Name() string { return "brave" }
+
+func (b *braveBackend) Search(ctx context.Context, query string, maxResults int) ([]searchResult, error) {
+	params := url.Values{}
+	params.Set("q", query)
+	params.Set("count", fmt.Sprintf("%d", maxResults))
+
+	req, err := http.NewRequestWithContext(ctx, "GET", "https://api.search.brave.com/res/v1/web/search?"+params.Encode(), nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("X-Subscription-Token", b.apiKey)
+
+	var resp struct {
+		Web struct {
+			Results []struct {
+				Title       string `json:"title"`
+				URL         string `json:"url"`
+				Description string `json:"description"`
+			} `json:"results"`
+		} `json:"web"`
+	}
+	if err := doSearchRequest(b.client, req, &resp); err != nil {
+		return nil, err
+	}
+
+	var results []searchResult
+	for _, r := range resp.Web.Results {
+		results = append(results, searchResult{
+			Title:   stripHTML(r.Title),
+			URL:     r.URL,
+			Snippet: stripHTML(r.Description),
+		})
+	}
+	return limitResults(results, maxResults), nil
+}
+
+// --- Tavily API ---
+
+type tavilyBackend struct {
+	client *http.Client
+	apiKey string
+}
+
+func (b *tavilyBackend) Name() string { return "tavily" }
+
+func (b *tavilyBackend) Search(ctx context.Context, query string, maxResults int) ([]searchResult, error) {
+	payload, err := json.Marshal(map[string]any{
+		"api_key":     b.apiKey,
+		"query":       query,
+		"max_results": maxResults,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", "https://api.tavily.com/search", bytes.NewReader(payload))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	var resp struct {
+		Results []struct {
+			Title   string `json:"title"`
+			URL     string `json:"url"`
+			Content string `json:"content"`
+		} `json:"results"`
+	}
+	if err := doSearchRequest(b.client, req, &resp); err != nil {
+		return nil, err
+	}
+
+	var results []searchResult
+	for _, r := range resp.Results {
+		results = append(results, searchResult{Title: r.Title, URL: r.URL, Snippet: r.Content})
+	}
+	return limitResults(results, maxResults), nil
+}
+
+// --- SearXNG (self-hosted, JSON format must be enabled) ---
+
+type searxngBackend struct {
+	client  *http.Client
+	baseURL string
+}
+
+func (b *searxngBackend) Name() string { return "searxng" }
+
+func (b *searxngBackend) Search(ctx context.Context, query string, maxResults int) ([]searchResult, error) {
+	params := url.Values{}
+	params.Set("q", query)
+	params.Set("format", "json")
+
+	req, err := http.NewRequestWithContext(ctx, "GET", b.baseURL+"/search?"+params.Encode(), nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/json")
+
+	var resp struct {
+		Results []struct {
+			Title   string `json:"title"`
+			URL     string `json:"url"`
+			Content string `json:"content"`
+		} `json:"results"`
+	}
+	if err := doSearchRequest(b.client, req, &resp); err != nil {
+		return nil, err
+	}
+
+	var results []searchResult
+	for _, r := range resp.Results {
+		results = append(results, searchResult{Title: r.Title, URL: r.URL, Snippet: r.Content})
+	}
+	return limitResults(results, maxResults), nil
+}
+
+func limitResults(results []searchResult, max int) []searchResult {
+	if len(results) > max {
+		return results[:max]
+	}
+	return results
+}
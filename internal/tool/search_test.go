@@ -0,0 +1,76 @@
+package tool
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestWebSearch_SearXNG(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("format") != "json" {
+			t.Errorf("expected format=json, got %q", r.URL.RawQuery)
+		}
+		_, _ = w.Write([]byte(`{"results":[
+			{"title":"Go","url":"https://go.dev","content":"The Go language"},
+			{"title":"Tour","url":"https://go.dev/tour","content":"A tour of Go"}
+		]}`))
+	}))
+	defer srv.Close()
+
+	ws := NewWebSearchWithConfig(SearchConfig{Backend: "searxng", URL: srv.URL})
+	res, err := ws.Execute(context.Background(), json.RawMessage(`{"query":"golang","max_results":1}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.IsError {
+		t.Fatalf("unexpected error: %s", res.Content)
+	}
+	if !strings.Contains(res.Content, "[1] Go") || !strings.Contains(res.Content, "Sources:\n[1] https://go.dev") {
+		t.Errorf("expected cited result, got:\n%s", res.Content)
+	}
+	if strings.Contains(res.Content, "go.dev/tour") {
+		t.Error("expected max_results to limit output")
+	}
+}
+
+func TestWebSearch_MissingAPIKey(t *testing.T) {
+	ws := NewWebSearchWithConfig(SearchConfig{Backend: "brave"})
+	res, err := ws.Execute(context.Background(), json.RawMessage(`{"query":"x"}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !res.IsError {
+		t.Error("expected error when brave API key is missing")
+	}
+}
+
+func TestUnwrapDuckDuckGoURL(t *testing.T) {
+	got := unwrapDuckDuckGoURL("//duckduckgo.com/l/?uddg=https%3A%2F%2Fexample.com%2Fa&rut=abc")
+	if got != "https://example.com/a" {
+		t.Errorf("got %q", got)
+	}
+}
+
+func TestWebSearch_ConfiguredMaxResults(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"results":[
+			{"title":"Go","url":"https://go.dev","content":"The Go language"},
+			{"title":"Tour","url":"https://go.dev/tour","content":"A tour of Go"},
+			{"title":"Blog","url":"https://go.dev/blog","content":"The Go blog"}
+		]}`))
+	}))
+	defer srv.Close()
+
+	ws := NewWebSearchWithConfig(SearchConfig{Backend: "searxng", URL: srv.URL, MaxResults: 2})
+	res, err := ws.Execute(context.Background(), json.RawMessage(`{"query":"golang"}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(res.Content, "go.dev/tour") || strings.Contains(res.Content, "go.dev/blog") {
+		t.Errorf("expected the configured max_results to limit output, got:\n%s", res.Content)
+	}
+}
@@ -0,0 +1,48 @@
+package tool
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+)
+
+// Shell returns the shell that runs commands: KLAW_SHELL when set, else
+// bash (sh when bash is missing) on Unix, and on Windows PowerShell (cmd
+// when PowerShell is missing).
+func Shell() string {
+	if sh := os.Getenv("KLAW_SHELL"); sh != "" {
+		return sh
+	}
+	candidates := []string{"bash", "sh"}
+	if runtime.GOOS == "windows" {
+		candidates = []string{"pwsh", "powershell", "cmd"}
+	}
+	for _, sh := range candidates {
+		if _, err := exec.LookPath(sh); err == nil {
+			return sh
+		}
+	}
+	return candidates[len(candidates)-1]
+}
+
+// ShellArgs returns the arguments that make shell run command.
+func ShellArgs(shell, command string) []string {
+	name := strings.ToLower(strings.TrimSuffix(filepath.Base(shell), filepath.Ext(shell)))
+	switch name {
+	case "cmd":
+		return []string{"/C", command}
+	case "pwsh", "powershell":
+		return []string{"-NoProfile", "-NonInteractive", "-Command", command}
+	default:
+		return []string{"-c", command}
+	}
+}
+
+// ShellCommand returns the command running command with Shell.
+func ShellCommand(ctx context.Context, command string) *exec.Cmd {
+	shell := Shell()
+	return exec.CommandContext(ctx, shell, ShellArgs(shell, command)...)
+}
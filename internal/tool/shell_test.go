@@ -0,0 +1,57 @@
+package tool
+
+import (
+	"context"
+	"encoding/json"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+func TestShellArgs(t *testing.T) {
+	tests := []struct {
+		shell string
+		want  string
+	}{
+		{"bash", "-c echo hi"},
+		{"/bin/sh", "-c echo hi"},
+		{"cmd", "/C echo hi"},
+		{"CMD.EXE", "/C echo hi"},
+		{"pwsh", "-NoProfile -NonInteractive -Command echo hi"},
+		{"powershell.exe", "-NoProfile -NonInteractive -Command echo hi"},
+	}
+	for _, tt := range tests {
+		if got := strings.Join(ShellArgs(tt.shell, "echo hi"), " "); got != tt.want {
+			t.Errorf("ShellArgs(%q) = %q, want %q", tt.shell, got, tt.want)
+		}
+	}
+}
+
+func TestShell(t *testing.T) {
+	t.Setenv("KLAW_SHELL", "")
+	want := map[string]bool{"bash": true, "sh": true}
+	if runtime.GOOS == "windows" {
+		want = map[string]bool{"pwsh": true, "powershell": true, "cmd": true}
+	}
+	if sh := Shell(); !want[sh] {
+		t.Errorf("unexpected shell %q on %s", sh, runtime.GOOS)
+	}
+
+	t.Setenv("KLAW_SHELL", "cmd")
+	if sh := Shell(); sh != "cmd" {
+		t.Errorf("expected KLAW_SHELL to choose the shell, got %q", sh)
+	}
+	if desc := NewBash(t.TempDir()).Description(); !strings.Contains(desc, "run with cmd, not bash") {
+		t.Errorf("expected the description to name the shell: %s", desc)
+	}
+}
+
+func TestBash_Shell(t *testing.T) {
+	res, err := NewBash(t.TempDir()).Execute(context.Background(), json.RawMessage(`{"command":"echo hello"}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.IsError || res.Content != "hello" {
+		t.Errorf("unexpected result with %s: %+v", Shell(), res)
+	}
+}
@@ -0,0 +1,47 @@
+package tool
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+type skillConfigKey struct{}
+
+// WithSkillConfig attaches per-skill config values (skill -> key -> value) to ctx.
+func WithSkillConfig(ctx context.Context, cfg map[string]map[string]string) context.Context {
+	if len(cfg) == 0 {
+		return ctx
+	}
+	return context.WithValue(ctx, skillConfigKey{}, cfg)
+}
+
+// SkillConfigFromContext returns the config values for a skill, or nil.
+func SkillConfigFromContext(ctx context.Context, skill string) map[string]string {
+	cfg, _ := ctx.Value(skillConfigKey{}).(map[string]map[string]string)
+	return cfg[skill]
+}
+
+// SkillConfigValue returns a single skill config value, or "" if unset.
+func SkillConfigValue(ctx context.Context, skill, key string) string {
+	return SkillConfigFromContext(ctx, skill)[key]
+}
+
+// skillConfigEnv renders the skill config in ctx as KLAW_<SKILL>_<KEY>=value
+// environment entries so shell-driven skills can pick them up.
+func skillConfigEnv(ctx context.Context) []string {
+	cfg, _ := ctx.Value(skillConfigKey{}).(map[string]map[string]string)
+	var env []string
+	for skill, values := range cfg {
+		for key, value := range values {
+			env = append(env, fmt.Sprintf("KLAW_%s_%s=%s", envName(skill), envName(key), value))
+		}
+	}
+	sort.Strings(env)
+	return env
+}
+
+func envName(s string) string {
+	return strings.ToUpper(strings.NewReplacer("-", "_", ".", "_", "/", "_").Replace(s))
+}
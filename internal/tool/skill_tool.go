@@ -8,6 +8,7 @@ import (
 	"net/http"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
 	"time"
 
@@ -16,14 +17,35 @@ import (
 
 // SkillTool allows installing, listing, and creating skills.
 type SkillTool struct {
-	skillsDir string
+	skillsDir string   // where skills are installed and created
+	dirs      []string // where skills are looked up, in order
 }
 
-// NewSkillTool creates a new skill management tool.
+// NewSkillTool creates a new skill management tool working on the global
+// skills.
 func NewSkillTool() *SkillTool {
+	return NewScopedSkillTool(config.ConfigDir() + "/skills")
+}
+
+// NewScopedSkillTool creates a skill management tool looking skills up in
+// dirs, the first one having a skill winning, and installing them in the
+// first, e.g. the skill scope directories of a namespace.
+func NewScopedSkillTool(dirs ...string) *SkillTool {
 	return &SkillTool{
-		skillsDir: config.ConfigDir() + "/skills",
+		skillsDir: dirs[0],
+		dirs:      dirs,
+	}
+}
+
+// skillPath returns the SKILL.md of an installed skill, or "".
+func (t *SkillTool) skillPath(name string) string {
+	for _, dir := range t.dirs {
+		path := filepath.Join(dir, name, "SKILL.md")
+		if _, err := os.Stat(path); err == nil {
+			return path
+		}
 	}
+	return ""
 }
 
 func (t *SkillTool) Name() string {
@@ -91,24 +113,28 @@ func (t *SkillTool) Execute(ctx context.Context, params json.RawMessage) (*Resul
 }
 
 func (t *SkillTool) listSkills() (*Result, error) {
-	entries, err := os.ReadDir(t.skillsDir)
-	if err != nil {
-		if os.IsNotExist(err) {
-			return &Result{Content: "No skills installed.\n\nInstall from skills.sh: skill action=install name=<skill-name>"}, nil
-		}
-		return &Result{Content: fmt.Sprintf("Failed to list skills: %v", err), IsError: true}, nil
-	}
-
 	var skills []string
-	for _, entry := range entries {
-		if !entry.IsDir() {
-			continue
+	seen := make(map[string]bool)
+	for _, dir := range t.dirs {
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			if os.IsNotExist(err) {
+				continue
+			}
+			return &Result{Content: fmt.Sprintf("Failed to list skills: %v", err), IsError: true}, nil
 		}
-		skillPath := filepath.Join(t.skillsDir, entry.Name(), "SKILL.md")
-		if _, err := os.Stat(skillPath); err == nil {
-			skills = append(skills, entry.Name())
+		for _, entry := range entries {
+			if !entry.IsDir() || seen[entry.Name()] {
+				continue
+			}
+			skillPath := filepath.Join(dir, entry.Name(), "SKILL.md")
+			if _, err := os.Stat(skillPath); err == nil {
+				seen[entry.Name()] = true
+				skills = append(skills, entry.Name())
+			}
 		}
 	}
+	sort.Strings(skills)
 
 	if len(skills) == 0 {
 		return &Result{Content: "No skills installed.\n\nInstall from skills.sh: skill action=install name=<skill-name>"}, nil
@@ -127,12 +153,12 @@ func (t *SkillTool) installSkill(name string) (*Result, error) {
 	name = strings.ReplaceAll(name, " ", "-")
 
 	// Check if already installed
-	skillDir := filepath.Join(t.skillsDir, name)
-	skillPath := filepath.Join(skillDir, "SKILL.md")
-	if _, err := os.Stat(skillPath); err == nil {
-		content, _ := os.ReadFile(skillPath)
+	if path := t.skillPath(name); path != "" {
+		content, _ := os.ReadFile(path)
 		return &Result{Content: fmt.Sprintf("Skill '%s' already installed.\n\n%s", name, string(content))}, nil
 	}
+	skillDir := filepath.Join(t.skillsDir, name)
+	skillPath := filepath.Join(skillDir, "SKILL.md")
 
 	// Try to download from skills.sh
 	urls := []string{
@@ -191,7 +217,7 @@ func (t *SkillTool) showSkill(name string) (*Result, error) {
 		return &Result{Content: "Skill name required", IsError: true}, nil
 	}
 
-	skillPath := filepath.Join(t.skillsDir, name, "SKILL.md")
+	skillPath := t.skillPath(name)
 	content, err := os.ReadFile(skillPath)
 	if err != nil {
 		return &Result{Content: fmt.Sprintf("Skill '%s' not found. Install it first: skill action=install name=%s", name, name), IsError: true}, nil
@@ -0,0 +1,53 @@
+package tool
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestSkillTool_Scopes(t *testing.T) {
+	root := t.TempDir()
+	namespace, global := filepath.Join(root, "ns"), filepath.Join(root, "global")
+	for dir, content := range map[string]string{
+		filepath.Join(global, "shared"):    "global shared",
+		filepath.Join(global, "report"):    "global report",
+		filepath.Join(namespace, "report"): "team report",
+	} {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(dir, "SKILL.md"), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	st := NewScopedSkillTool(namespace, global)
+	call := func(params string) *Result {
+		t.Helper()
+		res, err := st.Execute(context.Background(), json.RawMessage(params))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return res
+	}
+
+	if res := call(`{"action":"list"}`); !strings.Contains(res.Content, "- report\n- shared") {
+		t.Errorf("list: %s", res.Content)
+	}
+	if res := call(`{"action":"show","name":"report"}`); !strings.Contains(res.Content, "team report") {
+		t.Errorf("expected the namespace skill to win: %s", res.Content)
+	}
+	if res := call(`{"action":"create","name":"draft","content":"# Draft"}`); res.IsError {
+		t.Fatal(res.Content)
+	}
+	if _, err := os.Stat(filepath.Join(namespace, "draft", "SKILL.md")); err != nil {
+		t.Errorf("expected the skill created in the namespace scope: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(global, "draft")); !os.IsNotExist(err) {
+		t.Errorf("expected no global skill, got %v", err)
+	}
+}
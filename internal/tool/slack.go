@@ -0,0 +1,242 @@
+package tool
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/eachlabs/klaw/internal/channel"
+)
+
+// SlackAPI is the part of the running Slack channel the Slack tools use.
+type SlackAPI interface {
+	PostMessage(channelID, text string) error
+	PostThreadReply(channelID, threadTS, text string) error
+	GetRecentMessages(channelID, threadTS string, limit int) ([]channel.ChannelMessage, error)
+	AddReaction(channelID, messageTS, emoji string) error
+}
+
+// SlackTools returns slack_send, slack_read and slack_react bound to api.
+func SlackTools(api SlackAPI) []Tool {
+	return []Tool{NewSlackSend(api), NewSlackRead(api), NewSlackReact(api)}
+}
+
+// --- slack_send ---
+
+// SlackSend posts a message to a Slack channel or thread.
+type SlackSend struct {
+	api SlackAPI
+}
+
+// NewSlackSend creates the slack_send tool.
+func NewSlackSend(api SlackAPI) *SlackSend {
+	return &SlackSend{api: api}
+}
+
+func (t *SlackSend) Name() string {
+	return "slack_send"
+}
+
+func (t *SlackSend) Description() string {
+	return `Post a message to a Slack channel (by channel ID), optionally as a reply in a thread.
+Use this to notify other channels; your normal reply already goes to the current conversation.`
+}
+
+func (t *SlackSend) Schema() json.RawMessage {
+	return json.RawMessage(`{
+		"type": "object",
+		"properties": {
+			"channel": {
+				"type": "string",
+				"description": "Slack channel ID (e.g. C0123456789)"
+			},
+			"text": {
+				"type": "string",
+				"description": "Message text (Slack mrkdwn)"
+			},
+			"thread_ts": {
+				"type": "string",
+				"description": "Timestamp of the parent message to reply in its thread"
+			}
+		},
+		"required": ["channel", "text"]
+	}`)
+}
+
+type slackSendParams struct {
+	Channel  string `json:"channel"`
+	Text     string `json:"text"`
+	ThreadTS string `json:"thread_ts"`
+}
+
+func (t *SlackSend) Execute(ctx context.Context, params json.RawMessage) (*Result, error) {
+	var p slackSendParams
+	if err := json.Unmarshal(params, &p); err != nil {
+		return &Result{Content: fmt.Sprintf("Invalid parameters: %v", err), IsError: true}, nil
+	}
+	if p.Channel == "" || strings.TrimSpace(p.Text) == "" {
+		return &Result{Content: "channel and text are required", IsError: true}, nil
+	}
+
+	var err error
+	if p.ThreadTS != "" {
+		err = t.api.PostThreadReply(p.Channel, p.ThreadTS, p.Text)
+	} else {
+		err = t.api.PostMessage(p.Channel, p.Text)
+	}
+	if err != nil {
+		return &Result{Content: fmt.Sprintf("Failed to post message: %v", err), IsError: true}, nil
+	}
+
+	if p.ThreadTS != "" {
+		return &Result{Content: fmt.Sprintf("Replied in thread %s of %s", p.ThreadTS, p.Channel)}, nil
+	}
+	return &Result{Content: fmt.Sprintf("Posted message to %s", p.Channel)}, nil
+}
+
+// --- slack_read ---
+
+// SlackRead reads recent messages from a channel or thread.
+type SlackRead struct {
+	api SlackAPI
+}
+
+// NewSlackRead creates the slack_read tool.
+func NewSlackRead(api SlackAPI) *SlackRead {
+	return &SlackRead{api: api}
+}
+
+func (t *SlackRead) Name() string {
+	return "slack_read"
+}
+
+func (t *SlackRead) Description() string {
+	return `Read recent messages from a Slack channel, or from a thread when thread_ts is given.
+Each message includes its ts, which can be used with slack_react or slack_send thread_ts.`
+}
+
+func (t *SlackRead) Schema() json.RawMessage {
+	return json.RawMessage(`{
+		"type": "object",
+		"properties": {
+			"channel": {
+				"type": "string",
+				"description": "Slack channel ID"
+			},
+			"thread_ts": {
+				"type": "string",
+				"description": "Read replies of this thread instead of the channel"
+			},
+			"limit": {
+				"type": "integer",
+				"description": "Number of messages (default: 20, max: 200)"
+			}
+		},
+		"required": ["channel"]
+	}`)
+}
+
+type slackReadParams struct {
+	Channel  string `json:"channel"`
+	ThreadTS string `json:"thread_ts"`
+	Limit    int    `json:"limit"`
+}
+
+func (t *SlackRead) Execute(ctx context.Context, params json.RawMessage) (*Result, error) {
+	var p slackReadParams
+	if err := json.Unmarshal(params, &p); err != nil {
+		return &Result{Content: fmt.Sprintf("Invalid parameters: %v", err), IsError: true}, nil
+	}
+	if p.Channel == "" {
+		return &Result{Content: "channel is required", IsError: true}, nil
+	}
+	limit := p.Limit
+	if limit <= 0 {
+		limit = 20
+	}
+	if limit > 200 {
+		limit = 200
+	}
+
+	messages, err := t.api.GetRecentMessages(p.Channel, p.ThreadTS, limit)
+	if err != nil {
+		return &Result{Content: err.Error(), IsError: true}, nil
+	}
+	if len(messages) == 0 {
+		return &Result{Content: "No messages"}, nil
+	}
+
+	var sb strings.Builder
+	for _, m := range messages {
+		_, _ = fmt.Fprintf(&sb, "[%s] ts=%s <%s> %s\n", m.Timestamp.Format("2006-01-02 15:04"), m.SlackTS, m.User, m.Text)
+	}
+
+	output := sb.String()
+	if len(output) > 30000 {
+		output = output[:30000] + "\n... (output truncated)"
+	}
+	return &Result{Content: output}, nil
+}
+
+// --- slack_react ---
+
+// SlackReact adds an emoji reaction to a message.
+type SlackReact struct {
+	api SlackAPI
+}
+
+// NewSlackReact creates the slack_react tool.
+func NewSlackReact(api SlackAPI) *SlackReact {
+	return &SlackReact{api: api}
+}
+
+func (t *SlackReact) Name() string {
+	return "slack_react"
+}
+
+func (t *SlackReact) Description() string {
+	return "Add an emoji reaction (e.g. white_check_mark, eyes) to a Slack message."
+}
+
+func (t *SlackReact) Schema() json.RawMessage {
+	return json.RawMessage(`{
+		"type": "object",
+		"properties": {
+			"channel": {
+				"type": "string",
+				"description": "Slack channel ID"
+			},
+			"ts": {
+				"type": "string",
+				"description": "Timestamp of the message to react to"
+			},
+			"emoji": {
+				"type": "string",
+				"description": "Emoji name without colons"
+			}
+		},
+		"required": ["channel", "ts", "emoji"]
+	}`)
+}
+
+type slackReactParams struct {
+	Channel string `json:"channel"`
+	TS      string `json:"ts"`
+	Emoji   string `json:"emoji"`
+}
+
+func (t *SlackReact) Execute(ctx context.Context, params json.RawMessage) (*Result, error) {
+	var p slackReactParams
+	if err := json.Unmarshal(params, &p); err != nil {
+		return &Result{Content: fmt.Sprintf("Invalid parameters: %v", err), IsError: true}, nil
+	}
+	if p.Channel == "" || p.TS == "" || p.Emoji == "" {
+		return &Result{Content: "channel, ts and emoji are required", IsError: true}, nil
+	}
+
+	if err := t.api.AddReaction(p.Channel, p.TS, p.Emoji); err != nil {
+		return &Result{Content: fmt.Sprintf("Failed to add reaction: %v", err), IsError: true}, nil
+	}
+	return &Result{Content: fmt.Sprintf("Reacted with :%s: to %s", strings.Trim(p.Emoji, ":"), p.TS)}, nil
+}
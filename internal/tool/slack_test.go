@@ -0,0 +1,75 @@
+package tool
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/eachlabs/klaw/internal/channel"
+)
+
+type fakeSlack struct {
+	posts     []string
+	reactions []string
+}
+
+func (f *fakeSlack) PostMessage(channelID, text string) error {
+	f.posts = append(f.posts, channelID+": "+text)
+	return nil
+}
+
+func (f *fakeSlack) PostThreadReply(channelID, threadTS, text string) error {
+	f.posts = append(f.posts, channelID+"/"+threadTS+": "+text)
+	return nil
+}
+
+func (f *fakeSlack) GetRecentMessages(channelID, threadTS string, limit int) ([]channel.ChannelMessage, error) {
+	return []channel.ChannelMessage{
+		{User: "U1", Text: "deploy failed", Timestamp: time.Unix(1700000000, 0), SlackTS: "1700000000.000100"},
+	}, nil
+}
+
+func (f *fakeSlack) AddReaction(channelID, messageTS, emoji string) error {
+	f.reactions = append(f.reactions, messageTS+" "+emoji)
+	return nil
+}
+
+func TestSlackTools(t *testing.T) {
+	api := &fakeSlack{}
+	tools := NewRegistry()
+	for _, tl := range SlackTools(api) {
+		tools.Register(tl)
+	}
+	call := func(name, params string) *Result {
+		t.Helper()
+		tl, ok := tools.Get(name)
+		if !ok {
+			t.Fatalf("tool %s not registered", name)
+		}
+		res, err := tl.Execute(context.Background(), json.RawMessage(params))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return res
+	}
+
+	call("slack_send", `{"channel":"C1","text":"hi","thread_ts":"1.2"}`)
+	if len(api.posts) != 1 || api.posts[0] != "C1/1.2: hi" {
+		t.Errorf("unexpected posts: %v", api.posts)
+	}
+
+	if res := call("slack_read", `{"channel":"C1"}`); !strings.Contains(res.Content, "ts=1700000000.000100 <U1> deploy failed") {
+		t.Errorf("unexpected read output: %s", res.Content)
+	}
+
+	call("slack_react", `{"channel":"C1","ts":"1700000000.000100","emoji":":eyes:"}`)
+	if len(api.reactions) != 1 || api.reactions[0] != "1700000000.000100 :eyes:" {
+		t.Errorf("unexpected reactions: %v", api.reactions)
+	}
+
+	if res := call("slack_send", `{"channel":"C1"}`); !res.IsError {
+		t.Error("expected error without text")
+	}
+}
@@ -0,0 +1,466 @@
+package tool
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	_ "github.com/go-sql-driver/mysql"
+	_ "github.com/lib/pq"
+	_ "modernc.org/sqlite"
+)
+
+// SQL tools read their connection from the "database" skill config:
+//
+//	database.dsn        postgres://..., mysql://..., sqlite:///path/to/file.db
+//	database.read_only  "false" enables sql_execute (default: read-only)
+//	database.max_rows   row limit for sql_query (default: 100)
+//	database.timeout    statement timeout in seconds (default: 30)
+//
+// DATABASE_URL is used when no DSN is configured for the agent.
+const sqlSkill = "database"
+
+// sqlConn is a resolved database connection config.
+type sqlConn struct {
+	driver   string
+	dsn      string
+	readOnly bool
+	maxRows  int
+	timeout  time.Duration
+}
+
+// sqlConnFromContext resolves the connection for the current agent.
+func sqlConnFromContext(ctx context.Context) (*sqlConn, error) {
+	raw := SkillConfigValue(ctx, sqlSkill, "dsn")
+	if raw == "" {
+		raw = os.Getenv("DATABASE_URL")
+	}
+	if raw == "" {
+		return nil, fmt.Errorf("no database configured (set it with: klaw agent config set <agent> database.dsn=...)")
+	}
+
+	driver, dsn, err := parseSQLDSN(raw)
+	if err != nil {
+		return nil, err
+	}
+
+	conn := &sqlConn{
+		driver:   driver,
+		dsn:      dsn,
+		readOnly: SkillConfigValue(ctx, sqlSkill, "read_only") != "false",
+		maxRows:  100,
+		timeout:  30 * time.Second,
+	}
+	if n, err := strconv.Atoi(SkillConfigValue(ctx, sqlSkill, "max_rows")); err == nil && n > 0 {
+		conn.maxRows = n
+	}
+	if n, err := strconv.Atoi(SkillConfigValue(ctx, sqlSkill, "timeout")); err == nil && n > 0 {
+		conn.timeout = time.Duration(n) * time.Second
+	}
+	return conn, nil
+}
+
+// parseSQLDSN maps a URL-style DSN to a database/sql driver name and the
+// DSN format that driver expects.
+func parseSQLDSN(raw string) (string, string, error) {
+	scheme, rest, ok := strings.Cut(raw, "://")
+	if !ok {
+		if strings.HasSuffix(raw, ".db") || strings.HasSuffix(raw, ".sqlite") || strings.HasSuffix(raw, ".sqlite3") {
+			return "sqlite", raw, nil
+		}
+		return "", "", fmt.Errorf("unrecognized database DSN (expected postgres://, mysql:// or sqlite://)")
+	}
+
+	switch strings.ToLower(scheme) {
+	case "postgres", "postgresql":
+		return "postgres", raw, nil
+	case "sqlite", "sqlite3", "file":
+		return "sqlite", rest, nil
+	case "mysql":
+		u, err := url.Parse(raw)
+		if err != nil {
+			return "", "", fmt.Errorf("invalid mysql DSN: %w", err)
+		}
+		host := u.Host
+		if u.Port() == "" {
+			host += ":3306"
+		}
+		dsn := fmt.Sprintf("tcp(%s)/%s", host, strings.TrimPrefix(u.Path, "/"))
+		if u.User != nil {
+			userinfo := u.User.Username()
+			if pass, ok := u.User.Password(); ok {
+				userinfo += ":" + pass
+			}
+			dsn = userinfo + "@" + dsn
+		}
+		q := u.Query()
+		if q.Get("parseTime") == "" {
+			q.Set("parseTime", "true")
+		}
+		return "mysql", dsn + "?" + q.Encode(), nil
+	default:
+		return "", "", fmt.Errorf("unsupported database scheme: %s", scheme)
+	}
+}
+
+// sqlPool caches open *sql.DB handles by driver and DSN.
+var sqlPool = struct {
+	mu  sync.Mutex
+	dbs map[string]*sql.DB
+}{dbs: make(map[string]*sql.DB)}
+
+func openSQL(conn *sqlConn) (*sql.DB, error) {
+	key := conn.driver + "|" + conn.dsn
+
+	sqlPool.mu.Lock()
+	defer sqlPool.mu.Unlock()
+
+	if db, ok := sqlPool.dbs[key]; ok {
+		return db, nil
+	}
+	db, err := sql.Open(conn.driver, conn.dsn)
+	if err != nil {
+		return nil, err
+	}
+	db.SetMaxOpenConns(4)
+	db.SetConnMaxIdleTime(5 * time.Minute)
+	sqlPool.dbs[key] = db
+	return db, nil
+}
+
+var (
+	reFirstSQLWord = regexp.MustCompile(`^[\s(]*(\w+)`)
+	// Data-modifying statements a WITH clause can wrap
+	reWriteSQL = regexp.MustCompile(`(?i)\b(insert|update|delete|merge)\b`)
+)
+
+// checkReadOnlySQL accepts a single SELECT, possibly with a WITH clause.
+// String literals, quoted identifiers and comments are blanked first so
+// they can't hide a second statement or a keyword.
+func checkReadOnlySQL(query string) error {
+	stmt := strings.TrimSpace(stripSQLLiterals(query))
+	stmt = strings.TrimSpace(strings.TrimSuffix(stmt, ";"))
+	if strings.Contains(stmt, ";") {
+		return fmt.Errorf("sql_query runs a single statement")
+	}
+	var first string
+	if m := reFirstSQLWord.FindStringSubmatch(stmt); m != nil {
+		first = strings.ToLower(m[1])
+	}
+	switch first {
+	case "select":
+		return nil
+	case "with":
+		if m := reWriteSQL.FindString(stmt); m != "" {
+			return fmt.Errorf("sql_query only runs SELECT statements (found %s)", strings.ToUpper(m))
+		}
+		return nil
+	}
+	return fmt.Errorf("sql_query only runs SELECT statements")
+}
+
+// stripSQLLiterals replaces string literals, quoted identifiers and
+// comments with spaces.
+func stripSQLLiterals(query string) string {
+	out := []byte(query)
+	for i := 0; i < len(out); i++ {
+		switch c := out[i]; {
+		case c == '\'' || c == '"' || c == '`':
+			j := i + 1
+			for j < len(out) && out[j] != c {
+				j++
+			}
+			for k := i; k < len(out) && k <= j; k++ {
+				out[k] = ' '
+			}
+			i = j
+		case c == '-' && i+1 < len(out) && out[i+1] == '-':
+			for ; i < len(out) && out[i] != '\n'; i++ {
+				out[i] = ' '
+			}
+		case c == '/' && i+1 < len(out) && out[i+1] == '*':
+			end := strings.Index(string(out[i+2:]), "*/")
+			j := len(out)
+			if end >= 0 {
+				j = i + 2 + end + 2
+			}
+			for k := i; k < j; k++ {
+				out[k] = ' '
+			}
+			i = j - 1
+		}
+	}
+	return string(out)
+}
+
+// readOnlyDSN returns the DSN sql_query opens SQLite with, which refuses
+// writes at the connection level; other databases rely on a read-only
+// transaction.
+func readOnlyDSN(conn *sqlConn) string {
+	if conn.driver != "sqlite" {
+		return conn.dsn
+	}
+	dsn := conn.dsn
+	if !strings.HasPrefix(dsn, "file:") {
+		dsn = "file:" + dsn
+	}
+	sep := "?"
+	if strings.Contains(dsn, "?") {
+		sep = "&"
+	}
+	return dsn + sep + "mode=ro&_pragma=query_only(1)"
+}
+
+// --- sql_query ---
+
+// SQLQuery runs read-only queries.
+type SQLQuery struct{}
+
+// NewSQLQuery creates the sql_query tool.
+func NewSQLQuery() *SQLQuery {
+	return &SQLQuery{}
+}
+
+func (t *SQLQuery) Name() string {
+	return "sql_query"
+}
+
+func (t *SQLQuery) Description() string {
+	return `Run a read-only SQL query (a single SELECT, optionally with WITH) against the agent's database.
+Returns rows as a table. Results are limited to max_rows (default 100).
+Use placeholders with "args" for values ($1 for Postgres, ? for MySQL/SQLite).`
+}
+
+func (t *SQLQuery) Schema() json.RawMessage {
+	return json.RawMessage(`{
+		"type": "object",
+		"properties": {
+			"query": {
+				"type": "string",
+				"description": "The SQL query to run"
+			},
+			"args": {
+				"type": "array",
+				"description": "Values for query placeholders",
+				"items": {}
+			},
+			"max_rows": {
+				"type": "integer",
+				"description": "Maximum rows to return (default: 100)"
+			}
+		},
+		"required": ["query"]
+	}`)
+}
+
+type sqlParams struct {
+	Query   string `json:"query"`
+	Args    []any  `json:"args"`
+	MaxRows int    `json:"max_rows"`
+}
+
+func (t *SQLQuery) Execute(ctx context.Context, params json.RawMessage) (*Result, error) {
+	var p sqlParams
+	if err := json.Unmarshal(params, &p); err != nil {
+		return &Result{Content: fmt.Sprintf("Invalid parameters: %v", err), IsError: true}, nil
+	}
+	if strings.TrimSpace(p.Query) == "" {
+		return &Result{Content: "query is required", IsError: true}, nil
+	}
+	if err := checkReadOnlySQL(p.Query); err != nil {
+		return &Result{Content: err.Error() + "; use sql_execute for changes", IsError: true}, nil
+	}
+
+	conn, err := sqlConnFromContext(ctx)
+	if err != nil {
+		return &Result{Content: err.Error(), IsError: true}, nil
+	}
+	maxRows := conn.maxRows
+	if p.MaxRows > 0 && p.MaxRows < maxRows {
+		maxRows = p.MaxRows
+	}
+
+	db, err := openSQL(&sqlConn{driver: conn.driver, dsn: readOnlyDSN(conn)})
+	if err != nil {
+		return &Result{Content: fmt.Sprintf("Failed to open database: %v", err), IsError: true}, nil
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, conn.timeout)
+	defer cancel()
+
+	// Always roll back: the transaction only exists to enforce read-only mode
+	tx, err := db.BeginTx(ctx, &sql.TxOptions{ReadOnly: conn.driver != "sqlite"})
+	if err != nil {
+		return &Result{Content: fmt.Sprintf("Failed to start transaction: %v", err), IsError: true}, nil
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	rows, err := tx.QueryContext(ctx, p.Query, p.Args...)
+	if err != nil {
+		return &Result{Content: fmt.Sprintf("Query failed: %v", err), IsError: true}, nil
+	}
+	defer func() { _ = rows.Close() }()
+
+	output, err := formatSQLRows(rows, maxRows)
+	if err != nil {
+		return &Result{Content: fmt.Sprintf("Failed to read rows: %v", err), IsError: true}, nil
+	}
+	return &Result{Content: output}, nil
+}
+
+// formatSQLRows renders up to maxRows rows as a pipe-separated table.
+func formatSQLRows(rows *sql.Rows, maxRows int) (string, error) {
+	cols, err := rows.Columns()
+	if err != nil {
+		return "", err
+	}
+
+	var sb strings.Builder
+	sb.WriteString(strings.Join(cols, " | "))
+	sb.WriteString("\n")
+
+	values := make([]any, len(cols))
+	ptrs := make([]any, len(cols))
+	for i := range values {
+		ptrs[i] = &values[i]
+	}
+
+	count, more := 0, false
+	for rows.Next() {
+		if count >= maxRows {
+			more = true
+			break
+		}
+		if err := rows.Scan(ptrs...); err != nil {
+			return "", err
+		}
+		cells := make([]string, len(cols))
+		for i, v := range values {
+			cells[i] = truncateString(formatSQLValue(v), 200)
+		}
+		sb.WriteString(strings.Join(cells, " | "))
+		sb.WriteString("\n")
+		count++
+	}
+	if err := rows.Err(); err != nil {
+		return "", err
+	}
+
+	if more {
+		_, _ = fmt.Fprintf(&sb, "\n(%d rows shown, more available - limit reached)", count)
+	} else {
+		_, _ = fmt.Fprintf(&sb, "\n(%d rows)", count)
+	}
+
+	output := sb.String()
+	if len(output) > 30000 {
+		output = output[:30000] + "\n... (output truncated)"
+	}
+	return output, nil
+}
+
+func formatSQLValue(v any) string {
+	switch val := v.(type) {
+	case nil:
+		return "NULL"
+	case []byte:
+		return string(val)
+	case time.Time:
+		return val.Format(time.RFC3339)
+	default:
+		return fmt.Sprint(val)
+	}
+}
+
+// --- sql_execute ---
+
+// SQLExecute runs statements that modify data. It is disabled unless the
+// agent's database config sets read_only=false.
+type SQLExecute struct{}
+
+// NewSQLExecute creates the sql_execute tool.
+func NewSQLExecute() *SQLExecute {
+	return &SQLExecute{}
+}
+
+func (t *SQLExecute) Name() string {
+	return "sql_execute"
+}
+
+func (t *SQLExecute) Description() string {
+	return `Execute a SQL statement that modifies data or schema (INSERT, UPDATE, DELETE, CREATE, ...).
+Runs in a transaction and returns the number of affected rows.
+Only available when the database is configured with read_only=false.`
+}
+
+func (t *SQLExecute) Schema() json.RawMessage {
+	return json.RawMessage(`{
+		"type": "object",
+		"properties": {
+			"query": {
+				"type": "string",
+				"description": "The SQL statement to execute"
+			},
+			"args": {
+				"type": "array",
+				"description": "Values for statement placeholders",
+				"items": {}
+			}
+		},
+		"required": ["query"]
+	}`)
+}
+
+func (t *SQLExecute) Execute(ctx context.Context, params json.RawMessage) (*Result, error) {
+	var p sqlParams
+	if err := json.Unmarshal(params, &p); err != nil {
+		return &Result{Content: fmt.Sprintf("Invalid parameters: %v", err), IsError: true}, nil
+	}
+	if strings.TrimSpace(p.Query) == "" {
+		return &Result{Content: "query is required", IsError: true}, nil
+	}
+
+	conn, err := sqlConnFromContext(ctx)
+	if err != nil {
+		return &Result{Content: err.Error(), IsError: true}, nil
+	}
+	if conn.readOnly {
+		return &Result{Content: "Database is read-only. Enable writes with: klaw agent config set <agent> database.read_only=false", IsError: true}, nil
+	}
+
+	db, err := openSQL(conn)
+	if err != nil {
+		return &Result{Content: fmt.Sprintf("Failed to open database: %v", err), IsError: true}, nil
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, conn.timeout)
+	defer cancel()
+
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return &Result{Content: fmt.Sprintf("Failed to start transaction: %v", err), IsError: true}, nil
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	res, err := tx.ExecContext(ctx, p.Query, p.Args...)
+	if err != nil {
+		return &Result{Content: fmt.Sprintf("Statement failed: %v", err), IsError: true}, nil
+	}
+	if err := tx.Commit(); err != nil {
+		return &Result{Content: fmt.Sprintf("Commit failed: %v", err), IsError: true}, nil
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return &Result{Content: "Statement executed"}, nil
+	}
+	return &Result{Content: fmt.Sprintf("Statement executed (%d rows affected)", affected)}, nil
+}
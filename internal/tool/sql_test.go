@@ -0,0 +1,117 @@
+package tool
+
+import (
+	"context"
+	"encoding/json"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestParseSQLDSN(t *testing.T) {
+	tests := []struct {
+		raw, driver, dsn string
+	}{
+		{"postgres://u:p@localhost/app", "postgres", "postgres://u:p@localhost/app"},
+		{"sqlite:///tmp/app.db", "sqlite", "/tmp/app.db"},
+		{"data/app.db", "sqlite", "data/app.db"},
+		{"mysql://u:p@db/app", "mysql", "u:p@tcp(db:3306)/app?parseTime=true"},
+	}
+	for _, tt := range tests {
+		driver, dsn, err := parseSQLDSN(tt.raw)
+		if err != nil {
+			t.Fatalf("%s: %v", tt.raw, err)
+		}
+		if driver != tt.driver || dsn != tt.dsn {
+			t.Errorf("%s: got (%s, %s), want (%s, %s)", tt.raw, driver, dsn, tt.driver, tt.dsn)
+		}
+	}
+
+	if _, _, err := parseSQLDSN("oracle://x"); err == nil {
+		t.Error("expected error for unsupported scheme")
+	}
+}
+
+func TestSQLTools_SQLite(t *testing.T) {
+	dsn := "sqlite://" + filepath.Join(t.TempDir(), "test.db")
+	readOnly := WithSkillConfig(context.Background(), map[string]map[string]string{
+		"database": {"dsn": dsn},
+	})
+	writable := WithSkillConfig(context.Background(), map[string]map[string]string{
+		"database": {"dsn": dsn, "read_only": "false", "max_rows": "2"},
+	})
+
+	exec := NewSQLExecute()
+	res, _ := exec.Execute(readOnly, json.RawMessage(`{"query":"CREATE TABLE t (id INTEGER, name TEXT)"}`))
+	if !res.IsError {
+		t.Fatal("expected sql_execute to be refused in read-only mode")
+	}
+
+	res, _ = exec.Execute(writable, json.RawMessage(`{"query":"CREATE TABLE t (id INTEGER, name TEXT)"}`))
+	if res.IsError {
+		t.Fatalf("create failed: %s", res.Content)
+	}
+	res, _ = exec.Execute(writable, json.RawMessage(`{"query":"INSERT INTO t VALUES (?, ?), (2, 'b'), (3, NULL)","args":[1,"a"]}`))
+	if res.IsError || !strings.Contains(res.Content, "3 rows affected") {
+		t.Fatalf("insert: %s", res.Content)
+	}
+
+	query := NewSQLQuery()
+	res, _ = query.Execute(writable, json.RawMessage(`{"query":"SELECT id, name FROM t ORDER BY id"}`))
+	if res.IsError {
+		t.Fatalf("select failed: %s", res.Content)
+	}
+	if !strings.HasPrefix(res.Content, "id | name\n1 | a\n2 | b\n") || !strings.Contains(res.Content, "limit reached") {
+		t.Errorf("unexpected output:\n%s", res.Content)
+	}
+
+	for _, q := range []string{
+		"DELETE FROM t",
+		"WITH x AS (SELECT 1) DELETE FROM t",
+		"PRAGMA journal_mode=DELETE",
+		"SELECT 1; DELETE FROM t",
+	} {
+		data, _ := json.Marshal(map[string]string{"query": q})
+		if res, _ = query.Execute(readOnly, data); !res.IsError {
+			t.Errorf("expected sql_query to refuse %q", q)
+		}
+	}
+
+	// The connection itself refuses writes, e.g. through a function
+	// with side effects the statement check can't see
+	db, err := openSQL(&sqlConn{driver: "sqlite", dsn: readOnlyDSN(&sqlConn{driver: "sqlite", dsn: strings.TrimPrefix(dsn, "sqlite://")})})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := db.Exec("DELETE FROM t"); err == nil {
+		t.Error("expected the read-only connection to refuse DELETE")
+	}
+
+	res, _ = query.Execute(readOnly, json.RawMessage(`{"query":"SELECT count(*) AS n FROM t"}`))
+	if res.IsError || !strings.HasPrefix(res.Content, "n\n3\n") {
+		t.Errorf("rows changed by refused statements:\n%s", res.Content)
+	}
+}
+
+func TestCheckReadOnlySQL(t *testing.T) {
+	tests := []struct {
+		query string
+		ok    bool
+	}{
+		{"SELECT * FROM t", true},
+		{"  (select 1)", true},
+		{"WITH x AS (SELECT 1) SELECT * FROM x;", true},
+		{"SELECT 'a; DELETE FROM t' AS s", true},
+		{"SELECT 1 -- ; DROP TABLE t", true},
+		{"WITH x AS (DELETE FROM t RETURNING *) SELECT * FROM x", false},
+		{"SELECT 1; SELECT 2", false},
+		{"PRAGMA writable_schema=1", false},
+		{"EXPLAIN DELETE FROM t", false},
+		{"/* SELECT */ DELETE FROM t", false},
+	}
+	for _, tt := range tests {
+		if err := checkReadOnlySQL(tt.query); (err == nil) != tt.ok {
+			t.Errorf("%q: got %v, want ok=%v", tt.query, err, tt.ok)
+		}
+	}
+}
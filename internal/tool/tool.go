@@ -26,6 +26,26 @@ type Tool interface {
 type Result struct {
 	Content string
 	IsError bool
+
+	// Attachments are files (e.g. screenshots) delivered to channels that
+	// support them. They are not sent to the model.
+	Attachments []Attachment
+}
+
+// Attachment is a file produced by a tool.
+type Attachment struct {
+	Name     string
+	MimeType string
+	Data     []byte
+}
+
+// WorkDir returns the directory t resolves relative paths against, or ""
+// for tools without one.
+func WorkDir(t Tool) string {
+	if w, ok := t.(interface{ WorkDir() string }); ok {
+		return w.WorkDir()
+	}
+	return ""
 }
 
 // Registry holds available tools.
@@ -94,9 +114,13 @@ func DefaultRegistry(workDir string) *Registry {
 func DefaultRegistryWithScheduler(workDir string, sched interface{}) *Registry {
 	r := NewRegistry()
 	r.Register(NewBash(workDir))
+	for _, t := range NewBackgroundTasks(workDir, nil).Tools() {
+		r.Register(t)
+	}
 	r.Register(NewRead(workDir))
 	r.Register(NewWrite(workDir))
 	r.Register(NewEdit(workDir))
+	r.Register(NewApplyPatch(workDir))
 	r.Register(NewGlob(workDir))
 	r.Register(NewGrep(workDir))
 	r.Register(NewSkillTool())
@@ -104,7 +128,11 @@ func DefaultRegistryWithScheduler(workDir string, sched interface{}) *Registry {
 	r.Register(NewWebSearch())
 	r.Register(NewAgentTool())
 	r.Register(NewAgentListTool())
-	r.Register(NewCronCreateToolWithScheduler(sched))
-	r.Register(NewCronListToolWithScheduler(sched))
+	r.Register(NewAgentUpdateTool(nil))
+	s := sharedScheduler(sched)
+	r.Register(NewCronCreateToolWithScheduler(s))
+	r.Register(NewCronListToolWithScheduler(s))
+	r.Register(NewCronUpdateToolWithScheduler(s))
+	r.Register(NewCronDeleteToolWithScheduler(s))
 	return r
 }
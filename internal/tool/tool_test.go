@@ -3,6 +3,9 @@ package tool
 import (
 	"context"
 	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 )
 
@@ -165,3 +168,141 @@ func TestStubToolExecute(t *testing.T) {
 		t.Errorf("Description() = %q, want 'test tool'", s.Description())
 	}
 }
+
+func TestSkillConfigContext(t *testing.T) {
+	ctx := WithSkillConfig(context.Background(), map[string]map[string]string{
+		"database": {"dsn": "postgres://localhost/app"},
+	})
+
+	if got := SkillConfigValue(ctx, "database", "dsn"); got != "postgres://localhost/app" {
+		t.Errorf("expected dsn, got %q", got)
+	}
+	if got := SkillConfigValue(ctx, "web-search", "api_key"); got != "" {
+		t.Errorf("expected empty value for unset skill, got %q", got)
+	}
+
+	res, err := NewBash(t.TempDir()).Execute(ctx, json.RawMessage(`{"command":"echo $KLAW_DATABASE_DSN"}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Content != "postgres://localhost/app" {
+		t.Errorf("expected env var in bash, got %q", res.Content)
+	}
+}
+
+func TestRegistryWithPolicy(t *testing.T) {
+	dir := t.TempDir()
+	r := NewRegistry()
+	r.Register(NewBash(dir))
+	r.Register(NewWrite(dir))
+	r.Register(NewHTTPGet())
+
+	restricted, err := r.WithPolicy(Policy{
+		DenyCommands:   []string{`rm\s+-rf`},
+		WritablePaths:  []string{"out"},
+		AllowedDomains: []string{"example.com"},
+	}, dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		tool    string
+		params  string
+		blocked bool
+	}{
+		{"bash", `{"command":"rm -rf /tmp/x"}`, true},
+		{"bash", `{"command":"echo hi"}`, false},
+		{"write", `{"path":"out/a.txt","content":"x"}`, false},
+		{"write", `{"path":"../a.txt","content":"x"}`, true},
+		{"write", `{"path":"outside.txt","content":"x"}`, true},
+		{"http_get", `{"url":"https://evil.test/"}`, true},
+	}
+	for _, tt := range tests {
+		tl, _ := restricted.Get(tt.tool)
+		res, err := tl.Execute(context.Background(), json.RawMessage(tt.params))
+		if err != nil {
+			t.Fatal(err)
+		}
+		blocked := res.IsError && strings.HasPrefix(res.Content, "Blocked by agent policy")
+		if blocked != tt.blocked {
+			t.Errorf("%s %s: blocked = %v, want %v (%s)", tt.tool, tt.params, blocked, tt.blocked, res.Content)
+		}
+	}
+
+	if _, err := r.WithPolicy(Policy{AllowCommands: []string{"("}}, dir); err == nil {
+		t.Error("expected error for invalid regex")
+	}
+}
+
+func TestRegistryWithRoot(t *testing.T) {
+	shared, outside := t.TempDir(), t.TempDir()
+	if err := os.WriteFile(filepath.Join(outside, "secret.txt"), []byte("secret"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	r := DefaultRegistry(shared)
+	web, _ := r.Get("web_fetch")
+
+	root := filepath.Join(t.TempDir(), "agents", "coder")
+	rooted, err := r.WithRoot(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Symlink(outside, filepath.Join(root, "escape")); err != nil {
+		t.Fatal(err)
+	}
+	if got, _ := rooted.Get("web_fetch"); got != web {
+		t.Error("expected tools without files to be kept")
+	}
+
+	tests := []struct {
+		tool    string
+		params  string
+		blocked bool
+	}{
+		{"write", `{"path":"notes/a.txt","content":"x"}`, false},
+		{"read", `{"path":"notes/a.txt"}`, false},
+		{"read", `{"path":"../../x.txt"}`, true},
+		{"read", `{"path":"` + filepath.Join(outside, "secret.txt") + `"}`, true},
+		{"read", `{"path":"escape/secret.txt"}`, true},
+		{"write", `{"path":"escape/new.txt","content":"x"}`, true},
+		{"edit", `{"path":"../a.txt","old_string":"x","new_string":"y"}`, true},
+		{"glob", `{"pattern":"**/*.txt"}`, false},
+		{"glob", `{"pattern":"../*"}`, true},
+		{"grep", `{"pattern":"secret","path":"` + outside + `"}`, true},
+		{"apply_patch", `{"patch":"--- /dev/null\n+++ b/../b.txt\n@@ -0,0 +1 @@\n+x\n"}`, true},
+	}
+	for _, tt := range tests {
+		tl, _ := rooted.Get(tt.tool)
+		res, err := tl.Execute(context.Background(), json.RawMessage(tt.params))
+		if err != nil {
+			t.Fatal(err)
+		}
+		blocked := res.IsError && strings.HasPrefix(res.Content, "Blocked:")
+		if blocked != tt.blocked {
+			t.Errorf("%s %s: blocked = %v, want %v (%s)", tt.tool, tt.params, blocked, tt.blocked, res.Content)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(root, "notes", "a.txt")); err != nil {
+		t.Errorf("expected the file in the agent's directory: %v", err)
+	}
+
+	bash, _ := rooted.Get("bash")
+	res, err := bash.Execute(context.Background(), json.RawMessage(`{"command":"pwd"}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resolved, _ := filepath.EvalSymlinks(root); !strings.Contains(res.Content, resolved) {
+		t.Errorf("expected bash to start in %s, got %s", resolved, res.Content)
+	}
+
+	// The guardrails resolve relative paths against the tool's directory
+	restricted, err := rooted.WithPolicy(Policy{DenyCommands: []string{"x"}}, root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	read, _ := restricted.Get("read")
+	if resolved, _ := filepath.EvalSymlinks(root); WorkDir(read) != resolved {
+		t.Errorf("WorkDir = %q, want %q", WorkDir(read), resolved)
+	}
+}
@@ -6,22 +6,45 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"net/url"
 	"regexp"
+	"strconv"
 	"strings"
+	"sync"
 	"time"
 )
 
 // WebFetch fetches content from URLs
 type WebFetch struct {
 	client *http.Client
+	robots *robotsCache
+	ttl    time.Duration
+	mu     sync.Mutex
+	cache  map[string]fetchCacheEntry
+	size   int // bytes of the cached bodies
+}
+
+// maxFetchCacheBytes bounds the page bodies WebFetch keeps; the oldest
+// are dropped first.
+const maxFetchCacheBytes = 32 * 1024 * 1024
+
+// fetchCacheEntry is a cached page body.
+type fetchCacheEntry struct {
+	body      []byte
+	fetchedAt time.Time
 }
 
 // NewWebFetch creates a new web fetch tool
 func NewWebFetch() *WebFetch {
+	client := &http.Client{
+		Timeout:       30 * time.Second,
+		CheckRedirect: checkFetchRedirect,
+	}
 	return &WebFetch{
-		client: &http.Client{
-			Timeout: 30 * time.Second,
-		},
+		client: client,
+		robots: newRobotsCache(client),
+		ttl:    10 * time.Minute,
+		cache:  make(map[string]fetchCacheEntry),
 	}
 }
 
@@ -35,7 +58,8 @@ func (t *WebFetch) Description() string {
 - Check website content
 - Get data from APIs (GET requests)
 
-Returns the text content of the page (HTML tags stripped for readability).
+Returns the main article text of the page (navigation, ads and boilerplate removed).
+Set raw=true to get the original HTML. Pages are cached for 10 minutes and robots.txt is respected.
 For complex web pages that require JavaScript, this may not work - use browser tools instead.`
 }
 
@@ -49,7 +73,11 @@ func (t *WebFetch) Schema() json.RawMessage {
 			},
 			"raw": {
 				"type": "boolean",
-				"description": "Return raw HTML instead of stripped text (default: false)"
+				"description": "Return raw HTML instead of extracted article text (default: false)"
+			},
+			"refresh": {
+				"type": "boolean",
+				"description": "Bypass the cache and fetch a fresh copy (default: false)"
 			}
 		},
 		"required": ["url"]
@@ -57,8 +85,9 @@ func (t *WebFetch) Schema() json.RawMessage {
 }
 
 type webFetchParams struct {
-	URL string `json:"url"`
-	Raw bool   `json:"raw"`
+	URL     string `json:"url"`
+	Raw     bool   `json:"raw"`
+	Refresh bool   `json:"refresh"`
 }
 
 func (t *WebFetch) Execute(ctx context.Context, params json.RawMessage) (*Result, error) {
@@ -76,10 +105,73 @@ func (t *WebFetch) Execute(ctx context.Context, params json.RawMessage) (*Result
 		p.URL = "https://" + p.URL
 	}
 
+	u, err := url.Parse(p.URL)
+	if err != nil || u.Host == "" {
+		return &Result{Content: fmt.Sprintf("Invalid URL: %s", p.URL), IsError: true}, nil
+	}
+
+	if !t.robots.Allowed(ctx, u) {
+		return &Result{Content: fmt.Sprintf("Fetching %s is disallowed by the site's robots.txt", p.URL), IsError: true}, nil
+	}
+
+	body, cached, err := t.fetch(ctx, p.URL, p.Refresh)
+	if err != nil {
+		return &Result{Content: err.Error(), IsError: true}, nil
+	}
+
+	source := ""
+	if cached {
+		source = ", cached"
+	}
+
+	if p.Raw {
+		return &Result{Content: fmt.Sprintf("Fetched %s (%d bytes%s):\n\n%s", p.URL, len(body), source, body)}, nil
+	}
+
+	title, content := extractReadable(string(body))
+
+	// Truncate if too long
+	if len(content) > 50000 {
+		content = content[:50000] + "\n\n[Content truncated - too long]"
+	}
+
+	header := fmt.Sprintf("Fetched %s (%d bytes%s)", p.URL, len(body), source)
+	if title != "" {
+		header += "\nTitle: " + title
+	}
+	return &Result{Content: header + "\n\n" + content}, nil
+}
+
+// checkFetchRedirect applies the agent policy in the request's context to
+// every redirect of web_fetch.
+func checkFetchRedirect(req *http.Request, via []*http.Request) error {
+	if len(via) > maxHTTPRedirects {
+		return fmt.Errorf("stopped after %d redirects", maxHTTPRedirects)
+	}
+	if req.URL.Scheme != "http" && req.URL.Scheme != "https" {
+		return fmt.Errorf("redirect to unsupported scheme %s", req.URL.Scheme)
+	}
+	if reason := policyDomainReason(req.Context(), req.URL.Hostname()); reason != "" {
+		return fmt.Errorf("redirect blocked by agent policy: %s", reason)
+	}
+	return nil
+}
+
+// fetch returns the page body, serving from cache when fresh.
+func (t *WebFetch) fetch(ctx context.Context, pageURL string, refresh bool) ([]byte, bool, error) {
+	if !refresh {
+		t.mu.Lock()
+		entry, ok := t.cache[pageURL]
+		t.mu.Unlock()
+		if ok && time.Since(entry.fetchedAt) < t.ttl {
+			return entry.body, true, nil
+		}
+	}
+
 	// Create request with context
-	req, err := http.NewRequestWithContext(ctx, "GET", p.URL, nil)
+	req, err := http.NewRequestWithContext(ctx, "GET", pageURL, nil)
 	if err != nil {
-		return &Result{Content: fmt.Sprintf("Failed to create request: %v", err), IsError: true}, nil
+		return nil, false, fmt.Errorf("failed to create request: %v", err)
 	}
 
 	// Set user agent
@@ -89,35 +181,53 @@ func (t *WebFetch) Execute(ctx context.Context, params json.RawMessage) (*Result
 	// Execute request
 	resp, err := t.client.Do(req)
 	if err != nil {
-		return &Result{Content: fmt.Sprintf("Failed to fetch URL: %v", err), IsError: true}, nil
+		return nil, false, fmt.Errorf("failed to fetch URL: %v", err)
 	}
 	defer func() { _ = resp.Body.Close() }()
 
 	// Check status
 	if resp.StatusCode != http.StatusOK {
-		return &Result{Content: fmt.Sprintf("HTTP %d: %s", resp.StatusCode, resp.Status), IsError: true}, nil
+		return nil, false, fmt.Errorf("HTTP %d: %s", resp.StatusCode, resp.Status)
 	}
 
 	// Read body (limit to 1MB)
 	body, err := io.ReadAll(io.LimitReader(resp.Body, 1024*1024))
 	if err != nil {
-		return &Result{Content: fmt.Sprintf("Failed to read response: %v", err), IsError: true}, nil
+		return nil, false, fmt.Errorf("failed to read response: %v", err)
 	}
 
-	content := string(body)
+	t.store(pageURL, body)
+	return body, false, nil
+}
 
-	// Strip HTML if not raw mode
-	if !p.Raw {
-		content = stripHTML(content)
-		content = cleanText(content)
+// store caches a page body, dropping expired entries and then the oldest
+// ones until the cache fits in maxFetchCacheBytes.
+func (t *WebFetch) store(pageURL string, body []byte) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
 
-		// Truncate if too long
-		if len(content) > 50000 {
-			content = content[:50000] + "\n\n[Content truncated - too long]"
-		}
+	if old, ok := t.cache[pageURL]; ok {
+		t.size -= len(old.body)
 	}
+	t.cache[pageURL] = fetchCacheEntry{body: body, fetchedAt: time.Now()}
+	t.size += len(body)
 
-	return &Result{Content: fmt.Sprintf("Fetched %s (%d bytes):\n\n%s", p.URL, len(body), content)}, nil
+	for u, e := range t.cache {
+		if u != pageURL && time.Since(e.fetchedAt) >= t.ttl {
+			t.size -= len(e.body)
+			delete(t.cache, u)
+		}
+	}
+	for t.size > maxFetchCacheBytes && len(t.cache) > 1 {
+		var oldest string
+		for u, e := range t.cache {
+			if oldest == "" || e.fetchedAt.Before(t.cache[oldest].fetchedAt) {
+				oldest = u
+			}
+		}
+		t.size -= len(t.cache[oldest].body)
+		delete(t.cache, oldest)
+	}
 }
 
 // stripHTML removes HTML tags and extracts text content
@@ -178,14 +288,21 @@ func cleanText(text string) string {
 // WebSearch performs web searches
 type WebSearch struct {
 	client *http.Client
+	config SearchConfig
 }
 
-// NewWebSearch creates a new web search tool
+// NewWebSearch creates a new web search tool using the configured backend
 func NewWebSearch() *WebSearch {
+	return NewWebSearchWithConfig(loadSearchConfig())
+}
+
+// NewWebSearchWithConfig creates a web search tool with an explicit backend config
+func NewWebSearchWithConfig(cfg SearchConfig) *WebSearch {
 	return &WebSearch{
 		client: &http.Client{
 			Timeout: 30 * time.Second,
 		},
+		config: cfg,
 	}
 }
 
@@ -194,12 +311,13 @@ func (t *WebSearch) Name() string {
 }
 
 func (t *WebSearch) Description() string {
-	return `Search the web using DuckDuckGo. Use this to:
+	return `Search the web. Use this to:
 - Find information on any topic
 - Get current news and events
 - Research questions you don't know the answer to
 
-Returns search results with titles, URLs, and snippets.`
+Returns numbered results with titles, URLs, and snippets.
+Cite sources in your answer using their numbers, e.g. [1].`
 }
 
 func (t *WebSearch) Schema() json.RawMessage {
@@ -212,7 +330,7 @@ func (t *WebSearch) Schema() json.RawMessage {
 			},
 			"max_results": {
 				"type": "integer",
-				"description": "Maximum number of results to return (default: 5)"
+				"description": "Maximum number of results to return (default: 5, or the configured max_results)"
 			}
 		},
 		"required": ["query"]
@@ -234,48 +352,68 @@ func (t *WebSearch) Execute(ctx context.Context, params json.RawMessage) (*Resul
 		return &Result{Content: "Query is required", IsError: true}, nil
 	}
 
+	cfg := t.backendConfig(ctx)
+	if p.MaxResults <= 0 {
+		p.MaxResults = cfg.MaxResults
+	}
 	if p.MaxResults <= 0 {
 		p.MaxResults = 5
 	}
 
-	// Use DuckDuckGo HTML search (no API key needed)
-	searchURL := fmt.Sprintf("https://html.duckduckgo.com/html/?q=%s", strings.ReplaceAll(p.Query, " ", "+"))
-
-	req, err := http.NewRequestWithContext(ctx, "GET", searchURL, nil)
+	backend, err := newSearchBackend(cfg, t.client)
 	if err != nil {
-		return &Result{Content: fmt.Sprintf("Failed to create request: %v", err), IsError: true}, nil
+		return &Result{Content: fmt.Sprintf("Search not configured: %v", err), IsError: true}, nil
 	}
 
-	req.Header.Set("User-Agent", "Mozilla/5.0 (compatible; Klaw/1.0)")
-
-	resp, err := t.client.Do(req)
+	results, err := backend.Search(ctx, p.Query, p.MaxResults)
 	if err != nil {
-		return &Result{Content: fmt.Sprintf("Search failed: %v", err), IsError: true}, nil
+		return &Result{Content: fmt.Sprintf("Search failed (%s): %v", backend.Name(), err), IsError: true}, nil
 	}
-	defer func() { _ = resp.Body.Close() }()
 
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return &Result{Content: fmt.Sprintf("Failed to read response: %v", err), IsError: true}, nil
+	if len(results) == 0 {
+		return &Result{Content: fmt.Sprintf("No results found for: %s", p.Query)}, nil
 	}
 
-	// Parse results from HTML
-	results := parseSearchResults(string(body), p.MaxResults)
+	return &Result{Content: formatSearchResults(p.Query, backend.Name(), results)}, nil
+}
 
-	if len(results) == 0 {
-		return &Result{Content: fmt.Sprintf("No results found for: %s", p.Query)}, nil
+// backendConfig applies per-agent "web-search" skill config over the defaults.
+func (t *WebSearch) backendConfig(ctx context.Context) SearchConfig {
+	cfg := t.config
+	if v := SkillConfigValue(ctx, "web-search", "backend"); v != "" {
+		cfg.Backend = v
+	}
+	if v := SkillConfigValue(ctx, "web-search", "api_key"); v != "" {
+		cfg.APIKey = v
 	}
+	if v := SkillConfigValue(ctx, "web-search", "url"); v != "" {
+		cfg.URL = v
+	}
+	if n, err := strconv.Atoi(SkillConfigValue(ctx, "web-search", "max_results")); err == nil && n > 0 {
+		cfg.MaxResults = n
+	}
+	return cfg
+}
 
+func formatSearchResults(query, backend string, results []searchResult) string {
 	var sb strings.Builder
-	_, _ = fmt.Fprintf(&sb, "Search results for: %s\n\n", p.Query)
+	_, _ = fmt.Fprintf(&sb, "Search results for: %s (via %s)\n\n", query, backend)
+
+	for i, r := range results {
+		_, _ = fmt.Fprintf(&sb, "[%d] %s\n", i+1, r.Title)
+		_, _ = fmt.Fprintf(&sb, "    URL: %s\n", r.URL)
+		if r.Snippet != "" {
+			_, _ = fmt.Fprintf(&sb, "    %s\n", r.Snippet)
+		}
+		sb.WriteString("\n")
+	}
 
+	sb.WriteString("Sources:\n")
 	for i, r := range results {
-		_, _ = fmt.Fprintf(&sb, "%d. %s\n", i+1, r.Title)
-		_, _ = fmt.Fprintf(&sb, "   URL: %s\n", r.URL)
-		_, _ = fmt.Fprintf(&sb, "   %s\n\n", r.Snippet)
+		_, _ = fmt.Fprintf(&sb, "[%d] %s\n", i+1, r.URL)
 	}
 
-	return &Result{Content: sb.String()}, nil
+	return sb.String()
 }
 
 type searchResult struct {
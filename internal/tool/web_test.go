@@ -0,0 +1,118 @@
+package tool
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+)
+
+const articlePage = `<html><head><title>Test Article</title></head><body>
+<nav><a href="/">Home</a> <a href="/about">About</a></nav>
+<div class="sidebar"><p>Subscribe to our newsletter for more updates, offers, and news.</p></div>
+<article>
+<h1>Main Heading</h1>
+<p>This is the first paragraph of the article, with enough text to be scored.</p>
+<p>This is the second paragraph, which also has plenty of words, commas, and content.</p>
+</article>
+<footer>Copyright footer text</footer>
+</body></html>`
+
+func TestWebFetch_ReadabilityAndCache(t *testing.T) {
+	var hits atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/robots.txt" {
+			_, _ = w.Write([]byte("User-agent: *\nDisallow: /private\n"))
+			return
+		}
+		hits.Add(1)
+		_, _ = w.Write([]byte(articlePage))
+	}))
+	defer srv.Close()
+
+	wf := NewWebFetch()
+	res, err := wf.Execute(context.Background(), json.RawMessage(`{"url":"`+srv.URL+`/post"}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.IsError {
+		t.Fatalf("unexpected error: %s", res.Content)
+	}
+	for _, want := range []string{"Title: Test Article", "# Main Heading", "first paragraph", "second paragraph"} {
+		if !strings.Contains(res.Content, want) {
+			t.Errorf("expected %q in output:\n%s", want, res.Content)
+		}
+	}
+	for _, unwanted := range []string{"newsletter", "Copyright", "About"} {
+		if strings.Contains(res.Content, unwanted) {
+			t.Errorf("expected %q to be stripped:\n%s", unwanted, res.Content)
+		}
+	}
+
+	res, _ = wf.Execute(context.Background(), json.RawMessage(`{"url":"`+srv.URL+`/post"}`))
+	if !strings.Contains(res.Content, "cached") || hits.Load() != 1 {
+		t.Errorf("expected cached second fetch, hits=%d", hits.Load())
+	}
+
+	res, _ = wf.Execute(context.Background(), json.RawMessage(`{"url":"`+srv.URL+`/private/page"}`))
+	if !res.IsError || !strings.Contains(res.Content, "robots.txt") {
+		t.Errorf("expected robots.txt denial, got: %s", res.Content)
+	}
+}
+
+func TestParseRobots_SpecificGroupWins(t *testing.T) {
+	robots := "User-agent: *\nDisallow: /\n\nUser-agent: klaw\nDisallow: /tmp\nAllow: /tmp/public\n"
+	rules := parseRobots(strings.NewReader(robots), &robotsRules{})
+
+	if !rules.allowed("/docs") {
+		t.Error("expected /docs allowed for klaw group")
+	}
+	if rules.allowed("/tmp/x") {
+		t.Error("expected /tmp/x disallowed")
+	}
+	if !rules.allowed("/tmp/public/a") {
+		t.Error("expected longer Allow to win")
+	}
+}
+
+func TestParseRobots_MatchesWholeAgentToken(t *testing.T) {
+	for _, agent := range []string{"", "k", "la", "klawbot"} {
+		robots := "User-agent: " + agent + "\nDisallow: /\n"
+		if rules := parseRobots(strings.NewReader(robots), &robotsRules{}); !rules.allowed("/docs") {
+			t.Errorf("group %q should not apply to klaw", agent)
+		}
+	}
+	for _, agent := range []string{"klaw", "Klaw", "KLAW/1.0"} {
+		robots := "User-agent: " + agent + "\nDisallow: /\n"
+		if rules := parseRobots(strings.NewReader(robots), &robotsRules{}); rules.allowed("/docs") {
+			t.Errorf("group %q should apply to klaw", agent)
+		}
+	}
+}
+
+func TestWebFetch_CacheBounded(t *testing.T) {
+	wf := NewWebFetch()
+	page := make([]byte, 8*1024*1024)
+	for i := 0; i < 8; i++ {
+		wf.store(fmt.Sprintf("https://example.com/%d", i), page)
+	}
+	if wf.size > maxFetchCacheBytes {
+		t.Errorf("cache holds %d bytes, want at most %d", wf.size, maxFetchCacheBytes)
+	}
+	if _, ok := wf.cache["https://example.com/7"]; !ok {
+		t.Error("expected the newest page to be kept")
+	}
+	if _, ok := wf.cache["https://example.com/0"]; ok {
+		t.Error("expected the oldest page to be evicted")
+	}
+
+	wf.ttl = 0
+	wf.store("https://example.com/new", []byte("x"))
+	if len(wf.cache) != 1 || wf.size != 1 {
+		t.Errorf("expected expired entries dropped, got %d entries (%d bytes)", len(wf.cache), wf.size)
+	}
+}
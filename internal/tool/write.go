@@ -18,6 +18,11 @@ func NewWrite(workDir string) *Write {
 	return &Write{workDir: workDir}
 }
 
+// WorkDir returns the directory relative paths are resolved against.
+func (w *Write) WorkDir() string {
+	return w.workDir
+}
+
 func (w *Write) Name() string {
 	return "write"
 }
@@ -2,86 +2,31 @@ package tui
 
 import (
 	"context"
+	"fmt"
 	"strings"
 
 	"github.com/charmbracelet/bubbles/spinner"
 	"github.com/charmbracelet/bubbles/textarea"
 	"github.com/charmbracelet/bubbles/viewport"
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/glamour"
 	"github.com/charmbracelet/lipgloss"
 )
 
-var (
-	// Colors for chat
-	chatPurple    = lipgloss.Color("#A855F7")
-	chatGreen     = lipgloss.Color("#22C55E")
-	chatYellow    = lipgloss.Color("#FBBF24")
-	chatRed       = lipgloss.Color("#EF4444")
-	chatGray      = lipgloss.Color("#6B7280")
-	chatDarkGray  = lipgloss.Color("#374151")
-	chatLightGray = lipgloss.Color("#9CA3AF")
-	chatWhite     = lipgloss.Color("#F9FAFB")
-
-	// Styles for chat
-	chatTitleStyle = lipgloss.NewStyle().
-			Bold(true).
-			Foreground(chatPurple).
-			MarginBottom(1)
-
-	chatUserMsgStyle = lipgloss.NewStyle().
-			Foreground(chatWhite).
-			Background(chatPurple).
-			Padding(0, 1).
-			MarginTop(1)
-
-	chatUserLabelStyle = lipgloss.NewStyle().
-			Foreground(chatPurple).
-			Bold(true)
-
-	chatAssistantLabelStyle = lipgloss.NewStyle().
-				Foreground(chatGreen).
-				Bold(true)
-
-	chatAssistantMsgStyle = lipgloss.NewStyle().
-				Foreground(chatWhite).
-				MarginTop(1)
-
-	chatToolStyle = lipgloss.NewStyle().
-			Foreground(chatYellow).
-			Bold(true)
-
-	chatToolOutputStyle = lipgloss.NewStyle().
-			Foreground(chatLightGray).
-			Background(chatDarkGray).
-			Padding(0, 1)
-
-	chatErrorMsgStyle = lipgloss.NewStyle().
-			Foreground(chatRed).
-			Bold(true)
-
-	chatInputBoxStyle = lipgloss.NewStyle().
-			Border(lipgloss.RoundedBorder()).
-			BorderForeground(chatPurple).
-			Padding(0, 1)
-
-	chatInputBoxFocusedStyle = lipgloss.NewStyle().
-				Border(lipgloss.RoundedBorder()).
-				BorderForeground(chatGreen).
-				Padding(0, 1)
-
-	chatStatusStyle = lipgloss.NewStyle().
-			Foreground(chatGray).
-			MarginTop(1)
-
-	chatHelpStyle = lipgloss.NewStyle().
-			Foreground(chatGray)
-)
-
 // Message types for chat
 type ChatMessage struct {
-	Role    string // "user", "assistant", "tool", "error"
+	Role    string // "user", "assistant", "partial", "tool", "error", "done", "usage"
 	Content string
-	Tool    string // tool name if Role == "tool"
+	Tool    string     // tool name if Role == "tool"
+	Usage   *ChatUsage // if Role == "usage"
+}
+
+// ChatUsage is the token usage and estimated cost of one provider request.
+type ChatUsage struct {
+	Model        string
+	InputTokens  int
+	OutputTokens int
+	Cost         float64
 }
 
 // ChatModel is the bubbletea model for chat UI
@@ -91,13 +36,24 @@ type ChatModel struct {
 	viewport viewport.Model
 	spinner  spinner.Model
 
+	// Renders finished replies as markdown; nil until the size is known
+	renderer *glamour.TermRenderer
+
 	// State
-	messages    []ChatMessage
-	thinking    bool
-	width       int
-	height      int
-	ready       bool
-	err         error
+	messages  []ChatMessage
+	streaming string // text of the reply being streamed
+	thinking  bool
+	expanded  bool // show tool output in full
+	width     int
+	height    int
+	ready     bool
+	err       error
+
+	// Status line
+	model        string
+	inputTokens  int
+	outputTokens int
+	cost         float64
 
 	// Channel for sending user input
 	inputChan chan<- string
@@ -123,12 +79,13 @@ func NewChatModel(inputChan chan<- string, outputChan <-chan ChatMessage) ChatMo
 	ta.SetWidth(80)
 	ta.SetHeight(3)
 	ta.ShowLineNumbers = false
-	ta.KeyMap.InsertNewline.SetEnabled(false) // Enter sends message
+	// Enter sends the message; Alt+Enter and Ctrl+J start a new line
+	ta.KeyMap.InsertNewline.SetKeys("alt+enter", "ctrl+j")
 
 	// Spinner for thinking
 	sp := spinner.New()
 	sp.Spinner = spinner.Dot
-	sp.Style = lipgloss.NewStyle().Foreground(chatPurple)
+	sp.Style = lipgloss.NewStyle().Foreground(purple)
 
 	// Viewport for messages
 	vp := viewport.New(80, 20)
@@ -147,6 +104,12 @@ func NewChatModel(inputChan chan<- string, outputChan <-chan ChatMessage) ChatMo
 	}
 }
 
+// SetModel sets the model shown in the status line until the first reply
+// reports the one actually used.
+func (m *ChatModel) SetModel(model string) {
+	m.model = model
+}
+
 func (m ChatModel) Init() tea.Cmd {
 	return tea.Batch(
 		textarea.Blink,
@@ -179,7 +142,23 @@ func (m ChatModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			m.cancel()
 			return m, tea.Quit
 
+		case tea.KeyCtrlO:
+			m.expanded = !m.expanded
+			m.updateViewport()
+			return m, nil
+
+		case tea.KeyPgUp:
+			m.viewport.HalfViewUp()
+			return m, nil
+
+		case tea.KeyPgDown:
+			m.viewport.HalfViewDown()
+			return m, nil
+
 		case tea.KeyEnter:
+			if msg.Alt {
+				break // new line
+			}
 			if !m.thinking {
 				input := strings.TrimSpace(m.textarea.Value())
 				if input != "" {
@@ -207,7 +186,7 @@ func (m ChatModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 		headerHeight := 3
 		inputHeight := 5
-		helpHeight := 2
+		helpHeight := 3 // status and help
 		viewportHeight := m.height - headerHeight - inputHeight - helpHeight - 2
 
 		if !m.ready {
@@ -220,14 +199,44 @@ func (m ChatModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		}
 
 		m.textarea.SetWidth(m.width - 4)
+		m.renderer = newMarkdownRenderer(m.viewport.Width - 2)
 		m.updateViewport()
 
 	case responseMsg:
 		chatMsg := ChatMessage(msg)
-		m.messages = append(m.messages, chatMsg)
-
-		if chatMsg.Role == "done" {
+		switch chatMsg.Role {
+		case "partial":
+			m.streaming += chatMsg.Content
+		case "done":
+			m.streaming = ""
 			m.thinking = false
+		case "tool":
+			// Keep the text streamed before the tool call
+			if m.streaming != "" {
+				m.messages = append(m.messages, ChatMessage{Role: "assistant", Content: m.streaming})
+				m.streaming = ""
+			}
+			// A finished call replaces its announcement
+			if n := len(m.messages); n > 0 && chatMsg.Content != "" {
+				if last := m.messages[n-1]; last.Role == "tool" && last.Tool == chatMsg.Tool && last.Content == "" {
+					m.messages = m.messages[:n-1]
+				}
+			}
+			m.messages = append(m.messages, chatMsg)
+		case "usage":
+			if u := chatMsg.Usage; u != nil {
+				if u.Model != "" {
+					m.model = u.Model
+				}
+				m.inputTokens += u.InputTokens
+				m.outputTokens += u.OutputTokens
+				m.cost += u.Cost
+			}
+		default:
+			if chatMsg.Role == "assistant" {
+				m.streaming = ""
+			}
+			m.messages = append(m.messages, chatMsg)
 		}
 
 		m.updateViewport()
@@ -269,15 +278,21 @@ func (m *ChatModel) updateViewport() {
 
 		case "assistant":
 			content.WriteString(chatAssistantLabelStyle.Render("klaw") + "\n")
-			content.WriteString(chatAssistantMsgStyle.Render(msg.Content) + "\n\n")
+			content.WriteString(m.renderMarkdown(msg.Content) + "\n\n")
 
 		case "tool":
 			content.WriteString(chatToolStyle.Render("⚡ "+msg.Tool) + "\n")
-			if msg.Content != "" {
-				lines := strings.Split(msg.Content, "\n")
+			lines := strings.Split(strings.TrimRight(msg.Content, "\n"), "\n")
+			switch {
+			case msg.Content == "":
+				content.WriteString(chatToolNoteStyle.Render("  running...") + "\n")
+			case m.expanded || len(lines) == 1:
 				for _, line := range lines {
 					content.WriteString(chatToolOutputStyle.Render(line) + "\n")
 				}
+			default:
+				content.WriteString(chatToolOutputStyle.Render(truncate(lines[0], m.viewport.Width-4)) + "\n")
+				content.WriteString(chatToolNoteStyle.Render(fmt.Sprintf("  … %d more lines (ctrl+o to expand)", len(lines)-1)) + "\n")
 			}
 			content.WriteString("\n")
 
@@ -289,10 +304,53 @@ func (m *ChatModel) updateViewport() {
 		}
 	}
 
+	if m.streaming != "" {
+		content.WriteString(chatAssistantLabelStyle.Render("klaw") + "\n")
+		content.WriteString(chatAssistantMsgStyle.Render(m.streaming) + "\n\n")
+	}
+
 	m.viewport.SetContent(content.String())
 	m.viewport.GotoBottom()
 }
 
+// newMarkdownRenderer returns a renderer for replies that wraps at width
+// and suits the terminal's background, or nil if none can be created.
+func newMarkdownRenderer(width int) *glamour.TermRenderer {
+	style := "light"
+	if lipgloss.HasDarkBackground() {
+		style = "dark"
+	}
+	r, err := glamour.NewTermRenderer(glamour.WithStandardStyle(style), glamour.WithWordWrap(width))
+	if err != nil {
+		return nil
+	}
+	return r
+}
+
+// renderMarkdown renders a finished reply with headings, lists and
+// highlighted code blocks, falling back to plain text.
+func (m *ChatModel) renderMarkdown(text string) string {
+	if m.renderer != nil {
+		if out, err := m.renderer.Render(text); err == nil {
+			return strings.Trim(out, "\n")
+		}
+	}
+	return chatAssistantMsgStyle.Render(text)
+}
+
+// statusLine shows the model and the tokens and cost of the conversation.
+func (m ChatModel) statusLine() string {
+	parts := []string{}
+	if m.model != "" {
+		parts = append(parts, m.model)
+	}
+	parts = append(parts, fmt.Sprintf("%s in / %s out", formatTokens(m.inputTokens), formatTokens(m.outputTokens)))
+	if m.cost > 0 {
+		parts = append(parts, fmt.Sprintf("$%.4f", m.cost))
+	}
+	return strings.Join(parts, " • ")
+}
+
 func (m ChatModel) View() string {
 	if !m.ready {
 		return "Loading..."
@@ -324,8 +382,9 @@ func (m ChatModel) View() string {
 	}
 	b.WriteString(inputStyle.Render(m.textarea.View()) + "\n")
 
-	// Help
-	help := chatHelpStyle.Render("Enter to send • Esc to quit")
+	// Status and help
+	b.WriteString(chatHelpStyle.Render(m.statusLine()) + "\n")
+	help := chatHelpStyle.Render("Enter to send • Alt+Enter new line • Ctrl+O tool output • PgUp/PgDn scroll • Esc to quit")
 	b.WriteString(help)
 
 	return b.String()
@@ -4,6 +4,7 @@ package tui
 import (
 	"context"
 	"fmt"
+	"sort"
 	"strings"
 	"time"
 
@@ -17,114 +18,6 @@ import (
 	"github.com/eachlabs/klaw/internal/scheduler"
 )
 
-// Colors
-var (
-	purple    = lipgloss.Color("#7C3AED")
-	green     = lipgloss.Color("#10B981")
-	red       = lipgloss.Color("#EF4444")
-	yellow    = lipgloss.Color("#F59E0B")
-	blue      = lipgloss.Color("#3B82F6")
-	gray      = lipgloss.Color("#6B7280")
-	darkGray  = lipgloss.Color("#374151")
-	white     = lipgloss.Color("#F9FAFB")
-)
-
-// Styles
-var (
-	// Logo/Title
-	logoStyle = lipgloss.NewStyle().
-			Bold(true).
-			Foreground(white).
-			Background(purple).
-			Padding(0, 2).
-			MarginBottom(1)
-
-	// Sidebar
-	sidebarStyle = lipgloss.NewStyle().
-			Width(24).
-			Height(100).
-			Border(lipgloss.RoundedBorder(), false, true, false, false).
-			BorderForeground(darkGray).
-			Padding(1, 1)
-
-	menuItemStyle = lipgloss.NewStyle().
-			Padding(0, 1).
-			MarginBottom(0)
-
-	menuItemActiveStyle = lipgloss.NewStyle().
-				Bold(true).
-				Foreground(purple).
-				Background(lipgloss.Color("#EDE9FE")).
-				Padding(0, 1).
-				MarginBottom(0)
-
-	// Main content
-	contentStyle = lipgloss.NewStyle().
-			Padding(1, 2)
-
-	// Cards
-	cardStyle = lipgloss.NewStyle().
-			Border(lipgloss.RoundedBorder()).
-			BorderForeground(darkGray).
-			Padding(1, 2).
-			MarginBottom(1)
-
-	cardTitleStyle = lipgloss.NewStyle().
-			Bold(true).
-			Foreground(white).
-			MarginBottom(1)
-
-	// Status badges
-	badgeActive = lipgloss.NewStyle().
-			Foreground(green).
-			Bold(true)
-
-	badgeInactive = lipgloss.NewStyle().
-			Foreground(gray)
-
-	badgeError = lipgloss.NewStyle().
-			Foreground(red).
-			Bold(true)
-
-	// Table
-	tableHeaderStyle = lipgloss.NewStyle().
-				Bold(true).
-				Foreground(gray).
-				BorderStyle(lipgloss.NormalBorder()).
-				BorderBottom(true).
-				BorderForeground(darkGray)
-
-	tableRowStyle = lipgloss.NewStyle().
-			Padding(0, 1)
-
-	tableRowSelectedStyle = lipgloss.NewStyle().
-				Background(lipgloss.Color("#EDE9FE")).
-				Foreground(purple).
-				Bold(true).
-				Padding(0, 1)
-
-	// Help bar
-	helpStyle = lipgloss.NewStyle().
-			Foreground(gray).
-			Background(darkGray).
-			Padding(0, 2)
-
-	// Form
-	inputStyle = lipgloss.NewStyle().
-			Border(lipgloss.RoundedBorder()).
-			BorderForeground(gray).
-			Padding(0, 1)
-
-	inputFocusedStyle = lipgloss.NewStyle().
-				Border(lipgloss.RoundedBorder()).
-				BorderForeground(purple).
-				Padding(0, 1)
-
-	labelStyle = lipgloss.NewStyle().
-			Foreground(gray).
-			MarginBottom(0)
-)
-
 // Tab represents main navigation tabs
 type Tab int
 
@@ -132,17 +25,23 @@ const (
 	TabOverview Tab = iota
 	TabNodes
 	TabAgents
+	TabTasks
 	TabJobs
 	TabChannels
+	TabChat
+	TabSkills
 	TabSettings
 )
 
+// localTabs are the tabs of a dashboard on the local store.
+var localTabs = []Tab{TabOverview, TabNodes, TabAgents, TabJobs, TabChannels, TabChat, TabSkills, TabSettings}
+
 func (t Tab) String() string {
-	return []string{"Overview", "Nodes", "Agents", "Jobs", "Channels", "Settings"}[t]
+	return []string{"Overview", "Nodes", "Agents", "Tasks", "Jobs", "Channels", "Chat", "Skills", "Settings"}[t]
 }
 
 func (t Tab) Icon() string {
-	return []string{"📊", "🖥️", "🤖", "⏰", "📡", "⚙️"}[t]
+	return []string{"📊", "🖥️", "🤖", "📋", "⏰", "📡", "💬", "🧩", "⚙️"}[t]
 }
 
 // View mode within a tab
@@ -167,6 +66,7 @@ type ConversationMessage struct {
 // Model is the main TUI model
 type Model struct {
 	// Navigation
+	tabs      []Tab
 	activeTab Tab
 	viewMode  ViewMode
 
@@ -174,6 +74,7 @@ type Model struct {
 	store         *cluster.Store
 	ctrlStore     controller.Store
 	scheduler     *scheduler.Scheduler
+	remote        RemoteController // set in remote mode, instead of store and scheduler
 	clusterName   string
 	namespace     string
 
@@ -182,8 +83,16 @@ type Model struct {
 	channels    []*cluster.ChannelBinding
 	nodes       []*controller.Node
 	jobs        []*scheduler.Job
+	// Routing of the namespace, edited in the Settings tab
+	orchestrator *cluster.OrchestratorConfig
+	// Token usage, cost and message counts of the last days
+	usage *usageStats
 	// Channel logs for detail view
 	channelLogs []*cluster.MessageLog
+	// Controller state in remote mode
+	remoteAgents []*controller.Agent
+	tasks        []*controller.Task
+	remoteErr    error
 
 	// UI State
 	width         int
@@ -192,6 +101,7 @@ type Model struct {
 	selectedIndex int
 	logScrollPos  int
 	err           error
+	notice        string // result of the last action
 
 	// Components
 	spinner      spinner.Model
@@ -201,6 +111,27 @@ type Model struct {
 
 	// Create agent form state
 	formData map[string]string
+	// Job being edited; empty when creating one
+	editJobID string
+	// Agent edit form
+	agentForm agentForm
+
+	// Chat tab
+	connectChat ChatConnector
+	chat        chatPane
+
+	// Skills tab
+	skills skillsPane
+
+	// List filter (/) of the active tab
+	filter      string
+	filtering   bool
+	filterInput textinput.Model
+
+	// Delete confirmation, and the last deletion while it can be undone
+	confirm *pendingDelete
+	undo    *deletion
+	undoSeq int
 }
 
 // Messages
@@ -211,6 +142,8 @@ type dataLoadedMsg struct {
 	channels []*cluster.ChannelBinding
 	nodes    []*controller.Node
 	jobs     []*scheduler.Job
+	orch     *cluster.OrchestratorConfig
+	usage    *usageStats
 }
 
 // NewDashboard creates a new dashboard
@@ -222,6 +155,7 @@ func NewDashboard(store *cluster.Store, sched *scheduler.Scheduler, clusterName,
 	vp := viewport.New(80, 20)
 
 	return Model{
+		tabs:        localTabs,
 		activeTab:   TabOverview,
 		viewMode:    ViewList,
 		store:       store,
@@ -251,6 +185,9 @@ func tickCmd() tea.Cmd {
 }
 
 func (m Model) loadData() tea.Cmd {
+	if m.remote != nil {
+		return m.loadRemoteData()
+	}
 	return func() tea.Msg {
 		agents, _ := m.store.ListAgentBindings(m.clusterName, m.namespace)
 		var nodes []*controller.Node
@@ -265,8 +202,14 @@ func (m Model) loadData() tea.Cmd {
 		if m.scheduler != nil {
 			jobs = m.scheduler.ListJobs(m.clusterName, m.namespace)
 		}
+		sort.Slice(jobs, func(i, j int) bool { return jobs[i].CreatedAt.Before(jobs[j].CreatedAt) })
 		channels, _ := m.store.ListChannelBindings(m.clusterName, m.namespace)
-		return dataLoadedMsg{agents: agents, channels: channels, nodes: nodes, jobs: jobs}
+		orch := defaultOrchestratorConfig()
+		if ns, err := m.store.GetNamespace(m.clusterName, m.namespace); err == nil && ns.Orchestrator != nil {
+			orch = ns.Orchestrator
+		}
+		usage := loadUsageStats(m.store, m.clusterName, m.namespace, time.Now())
+		return dataLoadedMsg{agents: agents, channels: channels, nodes: nodes, jobs: jobs, orch: orch, usage: usage}
 	}
 }
 
@@ -280,25 +223,38 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		if m.viewMode == ViewCreate || m.viewMode == ViewEdit {
 			return m.updateForm(msg)
 		}
+		// Confirming a deletion
+		if m.confirm != nil {
+			return m.updateConfirm(msg)
+		}
+		// Typing a list filter
+		if m.filtering {
+			return m.updateFilter(msg)
+		}
+		// Typing a skill search
+		if m.activeTab == TabSkills && m.skills.searching {
+			return m.updateSkillSearch(msg)
+		}
+		// Keys go to the open conversation, except for switching tabs
+		if m.activeTab == TabChat && m.chat.session != nil && msg.String() != "tab" && msg.String() != "shift+tab" {
+			return m.updateChat(msg)
+		}
 
 		switch msg.String() {
 		case "q", "ctrl+c":
+			m.endChat()
 			return m, tea.Quit
 
-		case "1", "2", "3", "4", "5":
-			m.activeTab = Tab(int(msg.String()[0] - '1'))
-			m.selectedIndex = 0
-			m.viewMode = ViewList
+		case "1", "2", "3", "4", "5", "6", "7", "8", "9":
+			if i := int(msg.String()[0] - '1'); i < len(m.tabs) {
+				cmds = append(cmds, m.switchTab(m.tabs[i]))
+			}
 
 		case "tab":
-			m.activeTab = (m.activeTab + 1) % 5
-			m.selectedIndex = 0
-			m.viewMode = ViewList
+			cmds = append(cmds, m.switchTab(m.tabs[(m.tabIndex()+1)%len(m.tabs)]))
 
 		case "shift+tab":
-			m.activeTab = (m.activeTab + 4) % 5
-			m.selectedIndex = 0
-			m.viewMode = ViewList
+			cmds = append(cmds, m.switchTab(m.tabs[(m.tabIndex()+len(m.tabs)-1)%len(m.tabs)]))
 
 		case "up", "k":
 			if m.viewMode == ViewDetail && m.activeTab == TabChannels {
@@ -335,11 +291,61 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		case "enter":
 			return m.handleEnter()
 
+		case "left", "h", "right", "l", " ":
+			// Change a setting
+			if m.activeTab == TabSettings && m.remote == nil {
+				step := 1
+				if msg.String() == "left" || msg.String() == "h" {
+					step = -1
+				}
+				return m.changeSetting(step)
+			}
+
 		case "n", "c":
 			// New/Create
-			if m.activeTab == TabAgents {
+			if m.remote != nil {
+				break // remote mode is read-only
+			}
+			switch m.activeTab {
+			case TabAgents:
 				m.viewMode = ViewCreate
 				m.initCreateAgentForm()
+			case TabJobs:
+				m.viewMode = ViewCreate
+				m.err = nil
+				m.initJobForm(nil)
+			}
+
+		case "e":
+			// Edit
+			if m.remote != nil {
+				break
+			}
+			if job := m.selectedJob(); m.activeTab == TabJobs && job != nil {
+				m.viewMode = ViewEdit
+				m.err = nil
+				m.initJobForm(job)
+			}
+			if ag := m.selectedAgent(); m.activeTab == TabAgents && ag != nil {
+				m.viewMode = ViewEdit
+				m.initAgentForm(ag)
+			}
+
+		case "x":
+			// Run now
+			return m.handleJobRun()
+
+		case "/", "i", "u":
+			// Filter lists; search, install and uninstall skills
+			if msg.String() == "/" && m.viewMode == ViewList && m.filterable() {
+				return m.startFilter()
+			}
+			// Undo the last deletion
+			if msg.String() == "u" && m.undo != nil && m.activeTab != TabSkills {
+				return m.undoDelete()
+			}
+			if m.activeTab == TabSkills {
+				return m.handleSkillKey(msg.String())
 			}
 
 		case "d":
@@ -347,7 +353,7 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return m.handleDelete()
 
 		case "s":
-			// Toggle status (for channels)
+			// Toggle status (channels, jobs)
 			return m.handleToggleStatus()
 
 		case "r":
@@ -355,6 +361,10 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return m, m.loadData()
 
 		case "esc":
+			if m.viewMode == ViewList && m.filter != "" {
+				m.filter = ""
+				m.selectedIndex = 0
+			}
 			m.viewMode = ViewList
 		}
 
@@ -363,6 +373,10 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.height = msg.Height
 		m.viewport.Width = msg.Width - 30
 		m.viewport.Height = msg.Height - 10
+		if m.chat.session != nil {
+			m.resizeChat()
+			m.refreshChat()
+		}
 
 	case tickMsg:
 		return m, tea.Batch(m.loadData(), tickCmd())
@@ -373,12 +387,34 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.channels = msg.channels
 		m.nodes = msg.nodes
 		m.jobs = msg.jobs
+		m.orchestrator = msg.orch
+		m.usage = msg.usage
+
+	case remoteDataMsg:
+		m.loading = false
+		m.remoteErr = msg.err
+		if msg.err == nil {
+			m.nodes = msg.nodes
+			m.remoteAgents = msg.agents
+			m.tasks = msg.tasks
+		}
 
 	case spinner.TickMsg:
 		var cmd tea.Cmd
 		m.spinner, cmd = m.spinner.Update(msg)
 		cmds = append(cmds, cmd)
 
+	case chatOutputMsg:
+		return m.handleChatOutput(msg)
+
+	case skillsMsg:
+		return m.handleSkillsMsg(msg)
+
+	case undoExpiredMsg:
+		if m.undo != nil && m.undo.id == msg.id {
+			m.undo = nil
+		}
+
 	case errMsg:
 		m.err = msg.err
 	}
@@ -386,16 +422,43 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	return m, tea.Batch(cmds...)
 }
 
+// switchTab shows tab t.
+func (m *Model) switchTab(t Tab) tea.Cmd {
+	m.activeTab = t
+	m.selectedIndex = 0
+	m.viewMode = ViewList
+	m.notice = ""
+	m.filter = ""
+	if t == TabSkills {
+		return m.openSkills()
+	}
+	return nil
+}
+
 func (m Model) getMaxIndex() int {
 	switch m.activeTab {
 	case TabAgents:
+		if m.remote != nil {
+			return len(m.filteredRemoteAgents())
+		}
+		return len(m.filteredAgents())
+	case TabChat:
 		return len(m.agents)
+	case TabTasks:
+		return len(m.tasks)
 	case TabChannels:
-		return len(m.channels)
+		return len(m.filteredChannels())
 	case TabJobs:
-		return len(m.jobs)
+		return len(m.filteredJobs())
 	case TabNodes:
-		return len(m.nodes)
+		return len(m.filteredNodes())
+	case TabSkills:
+		return len(m.skills.installed) + len(m.skills.results)
+	case TabSettings:
+		if m.remote != nil {
+			return 0
+		}
+		return settingCount
 	default:
 		return 0
 	}
@@ -430,6 +493,9 @@ func (m *Model) initCreateAgentForm() {
 }
 
 func (m Model) updateForm(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if m.activeTab == TabAgents && m.viewMode == ViewEdit {
+		return m.updateAgentForm(msg)
+	}
 	switch msg.String() {
 	case "ctrl+c", "esc":
 		m.viewMode = ViewList
@@ -457,9 +523,22 @@ func (m Model) updateForm(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		}
 		return m, nil
 
+	case "left", "right":
+		if m.activeTab == TabJobs && m.focusedInput == jobFieldAgent {
+			if msg.String() == "right" {
+				m.cycleJobAgent(1)
+			} else {
+				m.cycleJobAgent(-1)
+			}
+			return m, nil
+		}
+
 	case "enter":
 		if m.focusedInput == len(m.inputs)-1 {
 			// Submit form
+			if m.activeTab == TabJobs {
+				return m.submitJobForm()
+			}
 			return m.submitAgentForm()
 		}
 		m.focusedInput++
@@ -491,15 +570,7 @@ func (m Model) submitAgentForm() (tea.Model, tea.Cmd) {
 	}
 
 	// Parse triggers
-	var triggers []string
-	if triggersRaw != "" {
-		for _, t := range strings.Split(triggersRaw, ",") {
-			t = strings.TrimSpace(t)
-			if t != "" {
-				triggers = append(triggers, t)
-			}
-		}
-	}
+	triggers := splitList(triggersRaw)
 
 	// Build system prompt
 	systemPrompt := fmt.Sprintf("You are %s, an AI agent. Your role: %s\n\nBe helpful, concise, and take action when needed.", name, description)
@@ -527,51 +598,59 @@ func (m Model) submitAgentForm() (tea.Model, tea.Cmd) {
 
 func (m Model) handleEnter() (tea.Model, tea.Cmd) {
 	switch m.activeTab {
+	case TabNodes:
+		if m.selectedIndex < len(m.filteredNodes()) {
+			m.viewMode = ViewDetail
+		}
+	case TabTasks:
+		if m.selectedIndex < len(m.tasks) {
+			m.viewMode = ViewDetail
+		}
 	case TabAgents:
-		if m.selectedIndex < len(m.agents) {
+		if m.selectedIndex < len(m.filteredAgents()) {
 			m.viewMode = ViewDetail
 		}
 	case TabChannels:
-		if m.selectedIndex < len(m.channels) {
-			ch := m.channels[m.selectedIndex]
+		if channels := m.filteredChannels(); m.selectedIndex < len(channels) {
+			ch := channels[m.selectedIndex]
 			// Load channel logs
 			logs, _ := m.store.GetMessageLogs(m.clusterName, m.namespace, ch.Name, 50)
 			m.channelLogs = logs
 			m.logScrollPos = 0
 			m.viewMode = ViewDetail
 		}
-	}
-	return m, nil
-}
-
-func (m Model) handleDelete() (tea.Model, tea.Cmd) {
-	switch m.activeTab {
-	case TabAgents:
-		if m.selectedIndex < len(m.agents) {
-			agent := m.agents[m.selectedIndex]
-			_ = m.store.DeleteAgentBinding(m.clusterName, m.namespace, agent.Name)
-			return m, m.loadData()
+	case TabJobs:
+		if m.selectedJob() != nil {
+			m.viewMode = ViewDetail
 		}
-	case TabChannels:
-		if m.selectedIndex < len(m.channels) {
-			ch := m.channels[m.selectedIndex]
-			_ = m.store.DeleteChannelBinding(m.clusterName, m.namespace, ch.Name)
-			return m, m.loadData()
+	case TabChat:
+		return m.startChat()
+	case TabSkills:
+		if m.selectedIndex < len(m.skills.installed)+len(m.skills.results) {
+			m.viewMode = ViewDetail
+		}
+	case TabSettings:
+		if m.remote == nil {
+			return m.changeSetting(1)
 		}
 	}
 	return m, nil
 }
 
 func (m Model) handleToggleStatus() (tea.Model, tea.Cmd) {
+	if m.activeTab == TabJobs {
+		return m.toggleJob()
+	}
 	if m.activeTab != TabChannels {
 		return m, nil
 	}
 
-	if m.selectedIndex >= len(m.channels) {
+	channels := m.filteredChannels()
+	if m.selectedIndex >= len(channels) {
 		return m, nil
 	}
 
-	ch := m.channels[m.selectedIndex]
+	ch := channels[m.selectedIndex]
 	newStatus := "active"
 	if ch.Status == "active" {
 		newStatus = "inactive"
@@ -608,13 +687,17 @@ func (m Model) renderSidebar() string {
 	items = append(items, "")
 
 	// Cluster info
-	clusterInfo := lipgloss.NewStyle().Foreground(gray).Render(
-		fmt.Sprintf("📦 %s/%s", m.clusterName, m.namespace))
-	items = append(items, clusterInfo)
+	if m.remote != nil {
+		items = append(items, m.renderRemoteInfo()...)
+	} else {
+		clusterInfo := lipgloss.NewStyle().Foreground(gray).Render(
+			fmt.Sprintf("📦 %s/%s", m.clusterName, m.namespace))
+		items = append(items, clusterInfo)
+	}
 	items = append(items, "")
 
 	// Menu items
-	for i := TabOverview; i <= TabSettings; i++ {
+	for _, i := range m.tabs {
 		style := menuItemStyle
 		if i == m.activeTab {
 			style = menuItemActiveStyle
@@ -624,9 +707,19 @@ func (m Model) renderSidebar() string {
 		// Add counts
 		switch i {
 		case TabAgents:
-			label += fmt.Sprintf(" (%d)", len(m.agents))
+			if m.remote != nil {
+				label += fmt.Sprintf(" (%d)", len(m.remoteAgents))
+			} else {
+				label += fmt.Sprintf(" (%d)", len(m.agents))
+			}
+		case TabTasks:
+			label += fmt.Sprintf(" (%d)", len(m.tasks))
 		case TabChannels:
 			label += fmt.Sprintf(" (%d)", len(m.channels))
+		case TabChat:
+			if m.chat.session != nil {
+				label += " • " + truncate(m.chat.agent, 8)
+			}
 		}
 
 		items = append(items, style.Render(label))
@@ -640,13 +733,27 @@ func (m Model) renderSidebar() string {
 func (m Model) renderContent() string {
 	contentWidth := m.width - 26 // sidebar width + padding
 
+	if m.confirm != nil {
+		return contentStyle.Width(contentWidth).Height(m.height - 2).Render(m.renderConfirm(contentWidth-4, m.height-4))
+	}
+
 	var content string
 	switch m.viewMode {
-	case ViewCreate:
-		content = m.renderCreateForm()
+	case ViewCreate, ViewEdit:
+		if m.activeTab == TabJobs {
+			content = m.renderJobForm()
+		} else if m.viewMode == ViewEdit {
+			content = m.renderAgentForm()
+		} else {
+			content = m.renderCreateForm()
+		}
 	case ViewDetail:
 		content = m.renderDetail()
 	default:
+		if m.remote != nil {
+			content = m.renderRemote(contentWidth)
+			break
+		}
 		switch m.activeTab {
 		case TabOverview:
 			content = m.renderOverview(contentWidth)
@@ -658,6 +765,10 @@ func (m Model) renderContent() string {
 			content = m.renderJobs(contentWidth)
 		case TabChannels:
 			content = m.renderChannels(contentWidth)
+		case TabChat:
+			content = m.renderChat(contentWidth)
+		case TabSkills:
+			content = m.renderSkills(contentWidth)
 		case TabSettings:
 			content = m.renderSettings(contentWidth)
 		}
@@ -710,6 +821,9 @@ func (m Model) renderOverview(width int) string {
 	sections = append(sections, lipgloss.JoinHorizontal(lipgloss.Top, statCards...))
 	sections = append(sections, "")
 
+	// Usage and cost
+	sections = append(sections, m.renderUsage()...)
+
 	// Agents quick list
 	if len(m.agents) > 0 {
 		agentSection := cardTitleStyle.Render("🤖 Agents")
@@ -755,6 +869,7 @@ func (m Model) renderAgents(width int) string {
 	title := lipgloss.NewStyle().Bold(true).Foreground(white).Render("🤖 Agents")
 	sections = append(sections, title)
 	sections = append(sections, "")
+	sections = append(sections, m.renderDeleteStatus()...)
 
 	if len(m.agents) == 0 {
 		empty := cardStyle.Render("No agents configured.\n\nPress [n] to create your first agent.")
@@ -762,13 +877,20 @@ func (m Model) renderAgents(width int) string {
 		return strings.Join(sections, "\n")
 	}
 
+	agents := m.filteredAgents()
+	sections = append(sections, m.renderFilter(len(agents), len(m.agents))...)
+	if len(agents) == 0 {
+		sections = append(sections, m.noMatches("agents"))
+		return strings.Join(sections, "\n")
+	}
+
 	// Table header
 	header := tableHeaderStyle.Width(width - 4).Render(
 		fmt.Sprintf("  %-15s %-30s %-20s %s", "NAME", "DESCRIPTION", "MODEL", "TRIGGERS"))
 	sections = append(sections, header)
 
 	// Rows
-	for i, ag := range m.agents {
+	for i, ag := range agents {
 		style := tableRowStyle
 		prefix := "  "
 		if i == m.selectedIndex {
@@ -797,6 +919,7 @@ func (m Model) renderChannels(width int) string {
 	title := lipgloss.NewStyle().Bold(true).Foreground(white).Render("📡 Channels")
 	sections = append(sections, title)
 	sections = append(sections, "")
+	sections = append(sections, m.renderDeleteStatus()...)
 
 	if len(m.channels) == 0 {
 		empty := cardStyle.Render("No channels configured.\n\nCreate one with:\n  klaw create channel slack --name bot --bot-token ... --app-token ...")
@@ -804,13 +927,20 @@ func (m Model) renderChannels(width int) string {
 		return strings.Join(sections, "\n")
 	}
 
+	channels := m.filteredChannels()
+	sections = append(sections, m.renderFilter(len(channels), len(m.channels))...)
+	if len(channels) == 0 {
+		sections = append(sections, m.noMatches("channels"))
+		return strings.Join(sections, "\n")
+	}
+
 	// Table header
 	header := tableHeaderStyle.Width(width - 4).Render(
 		fmt.Sprintf("  %-15s %-10s %-10s %s", "NAME", "TYPE", "STATUS", "CREATED"))
 	sections = append(sections, header)
 
 	// Rows
-	for i, ch := range m.channels {
+	for i, ch := range channels {
 		style := tableRowStyle
 		prefix := "  "
 		if i == m.selectedIndex {
@@ -846,11 +976,18 @@ func (m Model) renderNodes(width int) string {
 		return strings.Join(sections, "\n")
 	}
 
+	nodes := m.filteredNodes()
+	sections = append(sections, m.renderFilter(len(nodes), len(m.nodes))...)
+	if len(nodes) == 0 {
+		sections = append(sections, m.noMatches("nodes"))
+		return strings.Join(sections, "\n")
+	}
+
 	// Table header
 	header := tableHeaderStyle.Render(fmt.Sprintf("%-12s %-15s %-10s %-20s", "ID", "NAME", "STATUS", "LAST SEEN"))
 	sections = append(sections, header)
 
-	for i, node := range m.nodes {
+	for i, node := range nodes {
 		style := tableRowStyle
 		if i == m.selectedIndex {
 			style = tableRowSelectedStyle
@@ -877,17 +1014,30 @@ func (m Model) renderJobs(width int) string {
 	sections = append(sections, title)
 	sections = append(sections, "")
 
+	if m.err != nil {
+		sections = append(sections, badgeError.Render(fmt.Sprintf("Error: %v", m.err)), "")
+	} else if m.notice != "" {
+		sections = append(sections, badgeActive.Render(m.notice), "")
+	}
+
 	if len(m.jobs) == 0 {
-		empty := cardStyle.Render("No scheduled jobs.\n\nCreate one with: klaw cron create <name> --schedule \"every day at 9am\" --agent <agent> --task \"...\"")
+		empty := cardStyle.Render("No scheduled jobs.\n\nPress [n] to create one, or use: klaw cron create <name> --schedule \"every day at 9am\" --agent <agent> --task \"...\"")
 		sections = append(sections, empty)
 		return strings.Join(sections, "\n")
 	}
 
+	jobs := m.filteredJobs()
+	sections = append(sections, m.renderFilter(len(jobs), len(m.jobs))...)
+	if len(jobs) == 0 {
+		sections = append(sections, m.noMatches("jobs"))
+		return strings.Join(sections, "\n")
+	}
+
 	// Table header
-	header := tableHeaderStyle.Render(fmt.Sprintf("%-8s %-15s %-20s %-12s %-10s", "ID", "NAME", "SCHEDULE", "AGENT", "STATUS"))
+	header := tableHeaderStyle.Render(fmt.Sprintf("%-8s %-15s %-20s %-12s %-10s %s", "ID", "NAME", "SCHEDULE", "AGENT", "STATUS", "NEXT RUN"))
 	sections = append(sections, header)
 
-	for i, job := range m.jobs {
+	for i, job := range jobs {
 		style := tableRowStyle
 		if i == m.selectedIndex {
 			style = tableRowSelectedStyle
@@ -898,50 +1048,19 @@ func (m Model) renderJobs(width int) string {
 			status = badgeInactive.Render("disabled")
 		}
 
-		row := style.Render(fmt.Sprintf("%-8s %-15s %-20s %-12s %-10s",
-			job.ID, truncate(job.Name, 15), truncate(job.Schedule, 20), job.Agent, status))
+		nextRun := "-"
+		if job.NextRun != nil {
+			nextRun = job.NextRun.Format("Jan 02 15:04")
+		}
+
+		row := style.Render(fmt.Sprintf("%-8s %-15s %-20s %-12s %-10s %s",
+			job.ID, truncate(job.Name, 15), truncate(job.Schedule, 20), job.Agent, status, nextRun))
 		sections = append(sections, row)
 	}
 
 	return strings.Join(sections, "\n")
 }
 
-func (m Model) renderSettings(width int) string {
-	var sections []string
-
-	title := lipgloss.NewStyle().Bold(true).Foreground(white).Render("⚙️ Settings")
-	sections = append(sections, title)
-	sections = append(sections, "")
-
-	// Cluster info
-	clusterCard := cardStyle.Width(width - 6).Render(
-		lipgloss.JoinVertical(lipgloss.Left,
-			cardTitleStyle.Render("Cluster"),
-			fmt.Sprintf("Name:      %s", m.clusterName),
-			fmt.Sprintf("Namespace: %s", m.namespace),
-		),
-	)
-	sections = append(sections, clusterCard)
-
-	// Orchestrator settings
-	orchCard := cardStyle.Width(width - 6).Render(
-		lipgloss.JoinVertical(lipgloss.Left,
-			cardTitleStyle.Render("Orchestrator"),
-			"Mode:          hybrid",
-			"Allow Manual:  true (@agent syntax)",
-			fmt.Sprintf("Default Agent: %s", func() string {
-				if len(m.agents) > 0 {
-					return m.agents[0].Name
-				}
-				return "(none)"
-			}()),
-		),
-	)
-	sections = append(sections, orchCard)
-
-	return strings.Join(sections, "\n")
-}
-
 func (m Model) renderCreateForm() string {
 	var sections []string
 
@@ -976,14 +1095,28 @@ func (m Model) renderCreateForm() string {
 
 func (m Model) renderDetail() string {
 	switch m.activeTab {
+	case TabNodes:
+		if nodes := m.filteredNodes(); m.selectedIndex < len(nodes) {
+			return m.renderNodeDetail(nodes[m.selectedIndex])
+		}
+	case TabTasks:
+		if m.selectedIndex < len(m.tasks) {
+			return m.renderTaskDetail(m.tasks[m.selectedIndex])
+		}
 	case TabAgents:
-		if m.selectedIndex < len(m.agents) {
-			return m.renderAgentDetail(m.agents[m.selectedIndex])
+		if agents := m.filteredAgents(); m.selectedIndex < len(agents) {
+			return m.renderAgentDetail(agents[m.selectedIndex])
 		}
 	case TabChannels:
-		if m.selectedIndex < len(m.channels) {
-			return m.renderChannelDetail(m.channels[m.selectedIndex])
+		if channels := m.filteredChannels(); m.selectedIndex < len(channels) {
+			return m.renderChannelDetail(channels[m.selectedIndex])
+		}
+	case TabJobs:
+		if job := m.selectedJob(); job != nil {
+			return m.renderJobDetail(job)
 		}
+	case TabSkills:
+		return m.renderSkillDetail()
 	}
 	return ""
 }
@@ -1037,7 +1170,7 @@ func (m Model) renderAgentDetail(ag *cluster.AgentBinding) string {
 		Border(lipgloss.RoundedBorder()).
 		BorderForeground(darkGray).
 		Padding(1, 2).
-		Foreground(lipgloss.Color("#D1D5DB"))
+		Foreground(body)
 
 	promptContent := strings.Join(displayLines, "\n")
 	if truncated {
@@ -1046,12 +1179,25 @@ func (m Model) renderAgentDetail(ag *cluster.AgentBinding) string {
 
 	sections = append(sections, promptStyle.Render(promptContent))
 
-	hint := lipgloss.NewStyle().Foreground(gray).Render("Esc: back • d: delete")
+	hint := lipgloss.NewStyle().Foreground(gray).Render("Esc: back • e: edit • d: delete")
 	sections = append(sections, "", hint)
 
 	return strings.Join(sections, "\n")
 }
 
+// channelHealth describes the connection state klaw start reported for a
+// channel.
+func channelHealth(ch *cluster.ChannelBinding) string {
+	if ch.Health == nil {
+		return "not running"
+	}
+	health := ch.Health.State
+	if ch.Health.Error != "" {
+		health += ": " + ch.Health.Error
+	}
+	return fmt.Sprintf("%s (%s)", health, ch.Health.UpdatedAt.Format("15:04:05"))
+}
+
 func (m Model) renderChannelDetail(ch *cluster.ChannelBinding) string {
 	var sections []string
 
@@ -1068,6 +1214,7 @@ func (m Model) renderChannelDetail(ch *cluster.ChannelBinding) string {
 		lipgloss.JoinVertical(lipgloss.Left,
 			fmt.Sprintf("Type:     %s", ch.Type),
 			fmt.Sprintf("Status:   %s", status),
+			fmt.Sprintf("Health:   %s", channelHealth(ch)),
 			fmt.Sprintf("Created:  %s", ch.CreatedAt.Format(time.RFC3339)),
 		),
 	)
@@ -1147,19 +1294,74 @@ func (m Model) renderChannelDetail(ch *cluster.ChannelBinding) string {
 func (m Model) renderHelp() string {
 	var keys []string
 
-	switch m.viewMode {
-	case ViewCreate, ViewEdit:
+	switch {
+	case m.confirm != nil:
+		keys = []string{"y: delete", "n/Esc: cancel"}
+	case m.viewMode == ViewEdit && m.activeTab == TabAgents:
+		keys = []string{"Tab: next field", "Space: toggle skill", "Ctrl+S: save", "Esc: cancel"}
+	case m.viewMode == ViewCreate || m.viewMode == ViewEdit:
 		keys = []string{"Tab: next field", "Enter: submit", "Esc: cancel"}
-	case ViewDetail:
+		if m.activeTab == TabJobs {
+			keys = append(keys, "←/→: pick agent")
+		}
+	case m.viewMode == ViewDetail:
 		keys = []string{"Esc: back", "d: delete"}
+		if m.remote != nil || m.activeTab == TabNodes {
+			keys = []string{"Esc: back"}
+		} else if m.activeTab == TabSkills {
+			keys = []string{"Esc: back", "i: install", "u: uninstall"}
+		} else if m.activeTab == TabJobs {
+			keys = []string{"Esc: back", "e: edit", "x: run now", "s: enable/disable", "d: delete"}
+		} else if m.activeTab == TabAgents {
+			keys = []string{"Esc: back", "e: edit", "d: delete"}
+		}
 	default:
+		tabKeys := fmt.Sprintf("1-%d: tabs", len(m.tabs))
+		if m.filtering {
+			keys = []string{"Type to filter", "Enter: apply", "Esc: clear"}
+			break
+		}
+		if m.remote != nil {
+			keys = []string{tabKeys, "r: refresh", "q: quit"}
+			if m.activeTab == TabNodes || m.activeTab == TabAgents {
+				keys = append([]string{"/: filter"}, keys...)
+			}
+			if m.activeTab == TabNodes || m.activeTab == TabTasks {
+				keys = append([]string{"Enter: details"}, keys...)
+			}
+			break
+		}
 		switch m.activeTab {
 		case TabAgents:
-			keys = []string{"n: new", "Enter: details", "d: delete", "1-5: tabs", "q: quit"}
+			keys = []string{"n: new", "e: edit", "Enter: details", "/: filter", "d: delete", tabKeys, "q: quit"}
+			if m.undo != nil {
+				keys = append([]string{"u: undo"}, keys...)
+			}
 		case TabChannels:
-			keys = []string{"s: toggle status", "Enter: details", "d: delete", "1-5: tabs", "q: quit"}
+			keys = []string{"s: toggle status", "Enter: details", "/: filter", "d: delete", tabKeys, "q: quit"}
+			if m.undo != nil {
+				keys = append([]string{"u: undo"}, keys...)
+			}
+		case TabJobs:
+			keys = []string{"n: new", "e: edit", "x: run now", "s: enable/disable", "Enter: details", "/: filter", "d: delete", "q: quit"}
+		case TabNodes:
+			keys = []string{"Enter: details", "/: filter", tabKeys, "r: refresh", "q: quit"}
+		case TabSkills:
+			if m.skills.searching {
+				keys = []string{"Enter: search", "Esc: cancel"}
+			} else {
+				keys = []string{"/: search", "i: install", "u: uninstall", "Enter: details", tabKeys, "q: quit"}
+			}
+		case TabSettings:
+			keys = []string{"↑/↓: select", "←/→ Enter: change", tabKeys, "q: quit"}
+		case TabChat:
+			if m.chat.session != nil {
+				keys = []string{"Enter: send", "ctrl+o: tool output", "PgUp/PgDn: scroll", "Esc: end chat", "Tab: next tab"}
+			} else {
+				keys = []string{"Enter: chat", tabKeys, "q: quit"}
+			}
 		default:
-			keys = []string{"1-5: tabs", "r: refresh", "q: quit"}
+			keys = []string{tabKeys, "r: refresh", "q: quit"}
 		}
 	}
 
@@ -0,0 +1,282 @@
+package tui
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textarea"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/eachlabs/klaw/internal/cluster"
+)
+
+// Fields of the agent edit form; the first ones are m.inputs
+const (
+	agentFieldDescription = iota
+	agentFieldModel
+	agentFieldProvider
+	agentFieldTriggers
+	agentFieldSkills
+	agentFieldPrompt
+	agentFieldCount
+)
+
+// agentForm is the state of the agent edit form beyond its text inputs.
+type agentForm struct {
+	name     string          // agent being edited
+	skills   []string        // skills to pick from
+	selected map[string]bool // picked skills
+	cursor   int             // skill under the cursor
+	prompt   textarea.Model
+}
+
+// selectedAgent returns the agent under the cursor, or nil.
+func (m Model) selectedAgent() *cluster.AgentBinding {
+	if agents := m.filteredAgents(); m.selectedIndex < len(agents) {
+		return agents[m.selectedIndex]
+	}
+	return nil
+}
+
+// initAgentForm prepares the edit form from the agent's binding. The skills
+// to pick from are the installed skills and those the agent already lists.
+func (m *Model) initAgentForm(ab *cluster.AgentBinding) {
+	m.inputs = make([]textinput.Model, agentFieldSkills)
+	for i := range m.inputs {
+		m.inputs[i] = textinput.New()
+	}
+
+	m.inputs[agentFieldDescription].Placeholder = "Writes and reviews code"
+	m.inputs[agentFieldDescription].Width = 60
+	m.inputs[agentFieldDescription].SetValue(ab.Description)
+
+	m.inputs[agentFieldModel].Placeholder = "default model"
+	m.inputs[agentFieldModel].Width = 40
+	m.inputs[agentFieldModel].SetValue(ab.Model)
+
+	m.inputs[agentFieldProvider].Placeholder = "provider klaw runs with"
+	m.inputs[agentFieldProvider].Width = 30
+	m.inputs[agentFieldProvider].SetValue(ab.Provider)
+
+	m.inputs[agentFieldTriggers].Placeholder = "code, fix, bug, implement"
+	m.inputs[agentFieldTriggers].Width = 60
+	m.inputs[agentFieldTriggers].SetValue(strings.Join(ab.Triggers, ", "))
+
+	form := agentForm{name: ab.Name, selected: make(map[string]bool)}
+	seen := make(map[string]bool)
+	if m.skills.loader != nil {
+		if installed, err := m.skills.loader.ListSkills(); err == nil {
+			for _, s := range installed {
+				seen[s] = true
+				form.skills = append(form.skills, s)
+			}
+		}
+	}
+	for _, s := range ab.Skills {
+		form.selected[s] = true
+		if !seen[s] {
+			seen[s] = true
+			form.skills = append(form.skills, s)
+		}
+	}
+	sort.Strings(form.skills)
+
+	form.prompt = textarea.New()
+	form.prompt.Placeholder = "You are ..."
+	form.prompt.CharLimit = 0
+	form.prompt.ShowLineNumbers = false
+	form.prompt.SetWidth(m.width - 34)
+	form.prompt.SetHeight(8)
+	form.prompt.SetValue(ab.SystemPrompt)
+	form.prompt.Blur()
+	m.agentForm = form
+
+	m.inputs[agentFieldDescription].Focus()
+	m.focusedInput = agentFieldDescription
+	m.err = nil
+}
+
+// focusAgentField moves the focus of the agent form to field.
+func (m *Model) focusAgentField(field int) {
+	m.focusedInput = (field + agentFieldCount) % agentFieldCount
+	for i := range m.inputs {
+		if i == m.focusedInput {
+			m.inputs[i].Focus()
+		} else {
+			m.inputs[i].Blur()
+		}
+	}
+	if m.focusedInput == agentFieldPrompt {
+		m.agentForm.prompt.Focus()
+	} else {
+		m.agentForm.prompt.Blur()
+	}
+}
+
+// updateAgentForm handles keys in the agent edit form. Tab moves between
+// fields, ctrl+s saves; in the skills list ←/→ move and space toggles.
+func (m Model) updateAgentForm(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "ctrl+c", "esc":
+		m.viewMode = ViewList
+		m.err = nil
+		return m, nil
+	case "ctrl+s":
+		return m.submitAgentEdit()
+	case "tab":
+		m.focusAgentField(m.focusedInput + 1)
+		return m, nil
+	case "shift+tab":
+		m.focusAgentField(m.focusedInput - 1)
+		return m, nil
+	}
+
+	switch m.focusedInput {
+	case agentFieldPrompt:
+		var cmd tea.Cmd
+		m.agentForm.prompt, cmd = m.agentForm.prompt.Update(msg)
+		return m, cmd
+
+	case agentFieldSkills:
+		form := &m.agentForm
+		switch msg.String() {
+		case "left", "h":
+			if form.cursor > 0 {
+				form.cursor--
+			}
+		case "right", "l":
+			if form.cursor < len(form.skills)-1 {
+				form.cursor++
+			}
+		case " ", "x":
+			if form.cursor < len(form.skills) {
+				name := form.skills[form.cursor]
+				form.selected[name] = !form.selected[name]
+			}
+		case "down", "enter":
+			m.focusAgentField(agentFieldPrompt)
+		case "up":
+			m.focusAgentField(agentFieldTriggers)
+		}
+		return m, nil
+	}
+
+	switch msg.String() {
+	case "down", "enter":
+		m.focusAgentField(m.focusedInput + 1)
+		return m, nil
+	case "up":
+		m.focusAgentField(m.focusedInput - 1)
+		return m, nil
+	}
+	var cmd tea.Cmd
+	m.inputs[m.focusedInput], cmd = m.inputs[m.focusedInput].Update(msg)
+	return m, cmd
+}
+
+// submitAgentEdit saves the form to the agent's binding.
+func (m Model) submitAgentEdit() (tea.Model, tea.Cmd) {
+	ab, err := m.store.GetAgentBinding(m.clusterName, m.namespace, m.agentForm.name)
+	if err != nil {
+		m.err = err
+		return m, nil
+	}
+
+	description := strings.TrimSpace(m.inputs[agentFieldDescription].Value())
+	if description == "" {
+		m.err = fmt.Errorf("description is required")
+		return m, nil
+	}
+	prompt := strings.TrimSpace(m.agentForm.prompt.Value())
+	if prompt == "" {
+		m.err = fmt.Errorf("system prompt is required")
+		return m, nil
+	}
+
+	ab.Description = description
+	ab.Model = strings.TrimSpace(m.inputs[agentFieldModel].Value())
+	ab.Provider = strings.TrimSpace(m.inputs[agentFieldProvider].Value())
+	ab.Triggers = splitList(m.inputs[agentFieldTriggers].Value())
+	ab.SystemPrompt = prompt
+	ab.Skills = nil
+	for _, s := range m.agentForm.skills {
+		if m.agentForm.selected[s] {
+			ab.Skills = append(ab.Skills, s)
+		}
+	}
+
+	if err := m.store.UpdateAgentBinding(ab); err != nil {
+		m.err = err
+		return m, nil
+	}
+	m.viewMode = ViewList
+	m.err = nil
+	m.notice = fmt.Sprintf("Saved agent %s", ab.Name)
+	return m, m.loadData()
+}
+
+// splitList splits a comma-separated list, dropping empty items.
+func splitList(raw string) []string {
+	var items []string
+	for _, item := range strings.Split(raw, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+func (m Model) renderAgentForm() string {
+	var sections []string
+
+	title := lipgloss.NewStyle().Bold(true).Foreground(white).Render("🤖 Edit " + m.agentForm.name)
+	sections = append(sections, title, "")
+
+	labels := []string{"Description:", "Model:", "Provider:", "Triggers:"}
+	for i, input := range m.inputs {
+		style := inputStyle
+		if i == m.focusedInput {
+			style = inputFocusedStyle
+		}
+		sections = append(sections, labelStyle.Render(labels[i]), style.Render(input.View()))
+	}
+
+	// Skills multi-select
+	var skills []string
+	for i, s := range m.agentForm.skills {
+		mark := "[ ]"
+		if m.agentForm.selected[s] {
+			mark = "[x]"
+		}
+		item := fmt.Sprintf("%s %s", mark, s)
+		if i == m.agentForm.cursor && m.focusedInput == agentFieldSkills {
+			item = tableRowSelectedStyle.Render(item)
+		}
+		skills = append(skills, item)
+	}
+	skillList := lipgloss.NewStyle().Foreground(gray).Render("No skills installed")
+	if len(skills) > 0 {
+		skillList = strings.Join(skills, "  ")
+	}
+	style := inputStyle
+	if m.focusedInput == agentFieldSkills {
+		style = inputFocusedStyle
+	}
+	sections = append(sections, labelStyle.Render("Skills:"), style.Render(skillList))
+
+	style = inputStyle
+	if m.focusedInput == agentFieldPrompt {
+		style = inputFocusedStyle
+	}
+	sections = append(sections, labelStyle.Render("System Prompt:"), style.Render(m.agentForm.prompt.View()), "")
+
+	if m.err != nil {
+		sections = append(sections, badgeError.Render(fmt.Sprintf("Error: %v", m.err)))
+	}
+	hint := lipgloss.NewStyle().Foreground(gray).Render("Tab: next field • ←/→ space: pick skills • Ctrl+S: save • Esc: cancel")
+	sections = append(sections, hint)
+
+	return strings.Join(sections, "\n")
+}
@@ -0,0 +1,293 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// ChatSession is a running conversation with an agent.
+type ChatSession struct {
+	Input  chan<- string      // user messages to the agent
+	Output <-chan ChatMessage // replies, streamed text and tool calls
+	Close  func()             // stops the agent
+}
+
+// ChatConnector starts a conversation with the named agent for the chat tab.
+type ChatConnector func(agent string) (*ChatSession, error)
+
+// chatPane is the state of the chat tab.
+type chatPane struct {
+	agent     string
+	session   *ChatSession
+	messages  []ChatMessage
+	streaming string // text of the reply being streamed
+	thinking  bool
+	expanded  bool // show tool output in full
+	input     textinput.Model
+	viewport  viewport.Model
+	err       error
+}
+
+// chatOutputMsg carries one message of a chat session.
+type chatOutputMsg struct {
+	session *ChatSession
+	msg     ChatMessage
+	closed  bool
+}
+
+// SetChatConnector enables the chat tab.
+func (m *Model) SetChatConnector(connect ChatConnector) {
+	m.connectChat = connect
+}
+
+func waitForChat(s *ChatSession) tea.Cmd {
+	return func() tea.Msg {
+		msg, ok := <-s.Output
+		return chatOutputMsg{session: s, msg: msg, closed: !ok}
+	}
+}
+
+// startChat opens a conversation with the selected agent.
+func (m Model) startChat() (tea.Model, tea.Cmd) {
+	if m.connectChat == nil || m.selectedIndex >= len(m.agents) {
+		return m, nil
+	}
+	ag := m.agents[m.selectedIndex]
+	session, err := m.connectChat(ag.Name)
+	if err != nil {
+		m.chat.err = err
+		return m, nil
+	}
+
+	input := textinput.New()
+	input.Placeholder = fmt.Sprintf("Message %s...", ag.Name)
+	input.CharLimit = 4000
+	input.Focus()
+
+	m.chat = chatPane{
+		agent:    ag.Name,
+		session:  session,
+		input:    input,
+		viewport: viewport.New(80, 20),
+	}
+	m.resizeChat()
+	m.refreshChat()
+	return m, tea.Batch(textinput.Blink, waitForChat(session))
+}
+
+// endChat stops the running conversation.
+func (m *Model) endChat() {
+	if m.chat.session != nil && m.chat.session.Close != nil {
+		m.chat.session.Close()
+	}
+	m.chat = chatPane{}
+}
+
+// updateChat handles keys while a conversation is open.
+func (m Model) updateChat(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "ctrl+c":
+		m.endChat()
+		return m, tea.Quit
+
+	case "esc":
+		m.endChat()
+		return m, nil
+
+	case "enter":
+		text := strings.TrimSpace(m.chat.input.Value())
+		if text == "" || m.chat.thinking {
+			return m, nil
+		}
+		m.chat.messages = append(m.chat.messages, ChatMessage{Role: "user", Content: text})
+		m.chat.input.Reset()
+		m.chat.thinking = true
+		m.refreshChat()
+		input := m.chat.session.Input
+		go func() {
+			input <- text
+		}()
+		return m, nil
+
+	case "ctrl+o":
+		m.chat.expanded = !m.chat.expanded
+		m.refreshChat()
+		return m, nil
+
+	case "pgup":
+		m.chat.viewport.HalfViewUp()
+		return m, nil
+
+	case "pgdown":
+		m.chat.viewport.HalfViewDown()
+		return m, nil
+	}
+
+	var cmd tea.Cmd
+	m.chat.input, cmd = m.chat.input.Update(msg)
+	return m, cmd
+}
+
+// handleChatOutput adds a message of the running conversation.
+func (m Model) handleChatOutput(msg chatOutputMsg) (tea.Model, tea.Cmd) {
+	if msg.session != m.chat.session {
+		return m, nil // conversation already ended
+	}
+	if msg.closed {
+		m.chat.thinking = false
+		m.chat.messages = append(m.chat.messages, ChatMessage{Role: "error", Content: "conversation ended"})
+		m.refreshChat()
+		return m, nil
+	}
+
+	out := msg.msg
+	switch out.Role {
+	case "partial":
+		m.chat.streaming += out.Content
+	case "done":
+		m.chat.streaming = ""
+		m.chat.thinking = false
+	case "tool":
+		// Keep the text streamed before the tool call
+		if m.chat.streaming != "" {
+			m.chat.messages = append(m.chat.messages, ChatMessage{Role: "assistant", Content: m.chat.streaming})
+			m.chat.streaming = ""
+		}
+		// A finished call replaces its announcement
+		if n := len(m.chat.messages); n > 0 && out.Content != "" {
+			if last := m.chat.messages[n-1]; last.Role == "tool" && last.Tool == out.Tool && last.Content == "" {
+				m.chat.messages = m.chat.messages[:n-1]
+			}
+		}
+		m.chat.messages = append(m.chat.messages, out)
+	case "assistant":
+		m.chat.streaming = ""
+		m.chat.messages = append(m.chat.messages, out)
+	default:
+		m.chat.messages = append(m.chat.messages, out)
+	}
+	m.refreshChat()
+	return m, waitForChat(m.chat.session)
+}
+
+// chatSize returns the width and height of the transcript.
+func (m Model) chatSize() (int, int) {
+	width := m.width - 26 - 6   // sidebar, content padding
+	height := m.height - 2 - 10 // title, input box, status and help
+	if width < 20 {
+		width = 20
+	}
+	if height < 5 {
+		height = 5
+	}
+	return width, height
+}
+
+func (m *Model) resizeChat() {
+	width, height := m.chatSize()
+	m.chat.viewport.Width = width
+	m.chat.viewport.Height = height
+	m.chat.input.Width = width - 6
+}
+
+// refreshChat renders the transcript into the viewport, following new
+// messages.
+func (m *Model) refreshChat() {
+	width, _ := m.chatSize()
+	wrap := lipgloss.NewStyle().Width(width - 2)
+	userLabel := lipgloss.NewStyle().Foreground(blue).Bold(true)
+	agentLabel := lipgloss.NewStyle().Foreground(purple).Bold(true)
+	toolLabel := lipgloss.NewStyle().Foreground(yellow).Bold(true)
+	toolOutput := lipgloss.NewStyle().Foreground(body)
+	muted := lipgloss.NewStyle().Foreground(gray).Italic(true)
+
+	var b strings.Builder
+	for _, msg := range m.chat.messages {
+		switch msg.Role {
+		case "user":
+			b.WriteString(userLabel.Render("You") + "\n")
+			b.WriteString(wrap.Render(msg.Content) + "\n\n")
+		case "assistant":
+			b.WriteString(agentLabel.Render(m.chat.agent) + "\n")
+			b.WriteString(wrap.Render(strings.TrimSpace(msg.Content)) + "\n\n")
+		case "tool":
+			b.WriteString(toolLabel.Render("⚡ "+msg.Tool) + "\n")
+			lines := strings.Split(strings.TrimRight(msg.Content, "\n"), "\n")
+			switch {
+			case msg.Content == "":
+				b.WriteString(muted.Render("  running...") + "\n")
+			case m.chat.expanded || len(lines) == 1:
+				for _, line := range lines {
+					b.WriteString(toolOutput.Render("  "+truncate(line, width-4)) + "\n")
+				}
+			default:
+				b.WriteString(toolOutput.Render("  "+truncate(lines[0], width-4)) + "\n")
+				b.WriteString(muted.Render(fmt.Sprintf("  … %d more lines (ctrl+o to expand)", len(lines)-1)) + "\n")
+			}
+			b.WriteString("\n")
+		case "error":
+			b.WriteString(badgeError.Render("Error: "+msg.Content) + "\n\n")
+		}
+	}
+	if m.chat.streaming != "" {
+		b.WriteString(agentLabel.Render(m.chat.agent) + "\n")
+		b.WriteString(wrap.Render(m.chat.streaming) + "\n")
+	}
+
+	m.chat.viewport.SetContent(b.String())
+	m.chat.viewport.GotoBottom()
+}
+
+func (m Model) renderChat(width int) string {
+	var sections []string
+
+	if m.chat.session == nil {
+		title := lipgloss.NewStyle().Bold(true).Foreground(white).Render("💬 Chat")
+		sections = append(sections, title, "")
+
+		switch {
+		case m.connectChat == nil:
+			sections = append(sections, cardStyle.Render("Chat is not available in this dashboard."))
+		case len(m.agents) == 0:
+			sections = append(sections, cardStyle.Render("No agents to chat with.\n\nPress [3] to go to Agents and [n] to create one."))
+		default:
+			sections = append(sections, lipgloss.NewStyle().Foreground(gray).Render("Pick an agent to talk to:"), "")
+			for i, ag := range m.agents {
+				style := tableRowStyle
+				prefix := "  "
+				if i == m.selectedIndex {
+					style = tableRowSelectedStyle
+					prefix = "→ "
+				}
+				sections = append(sections, style.Render(fmt.Sprintf("%s%-15s %s", prefix, ag.Name, truncate(ag.Description, width-26))))
+			}
+		}
+		if m.chat.err != nil {
+			sections = append(sections, "", badgeError.Render(fmt.Sprintf("Error: %v", m.chat.err)))
+		}
+		return strings.Join(sections, "\n")
+	}
+
+	title := lipgloss.NewStyle().Bold(true).Foreground(white).Render("💬 " + m.chat.agent)
+	sections = append(sections, title, "")
+	sections = append(sections, m.chat.viewport.View())
+
+	status := ""
+	if m.chat.thinking {
+		status = m.spinner.View() + " " + lipgloss.NewStyle().Foreground(gray).Render("Thinking...")
+	}
+	sections = append(sections, status)
+
+	box := inputFocusedStyle
+	if m.chat.thinking {
+		box = inputStyle
+	}
+	sections = append(sections, box.Render(m.chat.input.View()))
+
+	return strings.Join(sections, "\n")
+}
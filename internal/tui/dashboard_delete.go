@@ -0,0 +1,200 @@
+package tui
+
+import (
+	"fmt"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/eachlabs/klaw/internal/cluster"
+	"github.com/eachlabs/klaw/internal/scheduler"
+)
+
+// undoWindow is how long a deleted agent or channel can be restored with u.
+const undoWindow = 10 * time.Second
+
+// pendingDelete is the item the confirmation dialog asks about.
+type pendingDelete struct {
+	agent   *cluster.AgentBinding
+	channel *cluster.ChannelBinding
+	job     *scheduler.Job
+}
+
+// deletion is a deleted agent or channel that can still be restored.
+type deletion struct {
+	id      int
+	agent   *cluster.AgentBinding
+	channel *cluster.ChannelBinding
+	expires time.Time
+}
+
+// undoExpiredMsg ends the undo window of a deletion.
+type undoExpiredMsg struct{ id int }
+
+func (p pendingDelete) describe() string {
+	switch {
+	case p.agent != nil:
+		return fmt.Sprintf("agent %s", p.agent.Name)
+	case p.channel != nil:
+		return fmt.Sprintf("channel %s", p.channel.Name)
+	case p.job != nil:
+		return fmt.Sprintf("job %s", p.job.Name)
+	}
+	return ""
+}
+
+func (d deletion) describe() string {
+	if d.agent != nil {
+		return fmt.Sprintf("agent %s", d.agent.Name)
+	}
+	return fmt.Sprintf("channel %s", d.channel.Name)
+}
+
+// handleDelete asks to confirm the deletion of the selected agent, channel
+// or job.
+func (m Model) handleDelete() (tea.Model, tea.Cmd) {
+	if m.remote != nil {
+		return m, nil
+	}
+	var p pendingDelete
+	switch m.activeTab {
+	case TabAgents:
+		if agents := m.filteredAgents(); m.selectedIndex < len(agents) {
+			p.agent = agents[m.selectedIndex]
+		}
+	case TabChannels:
+		if channels := m.filteredChannels(); m.selectedIndex < len(channels) {
+			p.channel = channels[m.selectedIndex]
+		}
+	case TabJobs:
+		if job := m.selectedJob(); job != nil && m.scheduler != nil {
+			p.job = job
+		}
+	}
+	if p.describe() == "" {
+		return m, nil
+	}
+	m.confirm = &p
+	return m, nil
+}
+
+// updateConfirm handles keys while the confirmation dialog is open: y or
+// Enter deletes, anything else cancels.
+func (m Model) updateConfirm(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	p := m.confirm
+	m.confirm = nil
+	switch msg.String() {
+	case "ctrl+c":
+		m.endChat()
+		return m, tea.Quit
+	case "y", "Y", "enter":
+		return m.deleteConfirmed(*p)
+	}
+	return m, nil
+}
+
+// deleteConfirmed deletes the item. Agents and channels are kept in memory
+// for undoWindow so that u can restore them.
+func (m Model) deleteConfirmed(p pendingDelete) (tea.Model, tea.Cmd) {
+	var err error
+	switch {
+	case p.agent != nil:
+		err = m.store.DeleteAgentBinding(m.clusterName, m.namespace, p.agent.Name)
+	case p.channel != nil:
+		err = m.store.DeleteChannelBinding(m.clusterName, m.namespace, p.channel.Name)
+	case p.job != nil:
+		err = m.scheduler.DeleteJob(p.job.ID)
+	}
+	if err != nil {
+		m.err = err
+		m.notice = ""
+		return m, nil
+	}
+
+	m.err = nil
+	m.notice = fmt.Sprintf("Deleted %s", p.describe())
+	m.viewMode = ViewList
+	if m.selectedIndex > 0 {
+		m.selectedIndex--
+	}
+	if p.job != nil {
+		return m, m.loadData()
+	}
+
+	m.undoSeq++
+	m.undo = &deletion{
+		id:      m.undoSeq,
+		agent:   p.agent,
+		channel: p.channel,
+		expires: time.Now().Add(undoWindow),
+	}
+	id := m.undoSeq
+	expire := tea.Tick(undoWindow, func(time.Time) tea.Msg { return undoExpiredMsg{id: id} })
+	return m, tea.Batch(m.loadData(), expire)
+}
+
+// undoDelete restores the last deleted agent or channel.
+func (m Model) undoDelete() (tea.Model, tea.Cmd) {
+	d := m.undo
+	if d == nil || time.Now().After(d.expires) {
+		m.undo = nil
+		return m, nil
+	}
+	m.undo = nil
+
+	var err error
+	if d.agent != nil {
+		err = m.store.RestoreAgentBinding(d.agent)
+	} else {
+		err = m.store.RestoreChannelBinding(d.channel)
+	}
+	if err != nil {
+		m.err = fmt.Errorf("could not restore %s: %w", d.describe(), err)
+		m.notice = ""
+		return m, nil
+	}
+	m.err = nil
+	m.notice = fmt.Sprintf("Restored %s", d.describe())
+	return m, m.loadData()
+}
+
+// renderConfirm renders the delete confirmation dialog in the middle of the
+// content area.
+func (m Model) renderConfirm(width, height int) string {
+	what := m.confirm.describe()
+	note := fmt.Sprintf("You can undo this with [u] for %d seconds.", int(undoWindow.Seconds()))
+	if m.confirm.job != nil {
+		note = "This cannot be undone."
+	}
+	dialog := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(red).
+		Padding(1, 3).
+		Render(lipgloss.JoinVertical(lipgloss.Left,
+			lipgloss.NewStyle().Bold(true).Foreground(white).Render(fmt.Sprintf("Delete %s?", what)),
+			"",
+			lipgloss.NewStyle().Foreground(gray).Render(note),
+			"",
+			"[y] Delete    [n] Cancel",
+		))
+	return lipgloss.Place(width, height, lipgloss.Center, lipgloss.Center, dialog)
+}
+
+// renderDeleteStatus shows the outcome of the last deletion or undo, with
+// the time left to undo it.
+func (m Model) renderDeleteStatus() []string {
+	switch {
+	case m.err != nil:
+		return []string{badgeError.Render(fmt.Sprintf("Error: %v", m.err)), ""}
+	case m.undo != nil:
+		left := time.Until(m.undo.expires).Round(time.Second)
+		if left <= 0 {
+			return nil
+		}
+		hint := lipgloss.NewStyle().Foreground(gray).Render(fmt.Sprintf(" • u to undo (%ds)", int(left.Seconds())))
+		return []string{badgeActive.Render(m.notice) + hint, ""}
+	case m.notice != "":
+		return []string{badgeActive.Render(m.notice), ""}
+	}
+	return nil
+}
@@ -0,0 +1,176 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/eachlabs/klaw/internal/cluster"
+	"github.com/eachlabs/klaw/internal/controller"
+	"github.com/eachlabs/klaw/internal/scheduler"
+)
+
+// filterable reports whether the active tab's list can be filtered with /.
+func (m Model) filterable() bool {
+	switch m.activeTab {
+	case TabAgents, TabJobs, TabChannels, TabNodes:
+		return true
+	}
+	return false
+}
+
+// startFilter focuses the filter input of the active tab.
+func (m Model) startFilter() (tea.Model, tea.Cmd) {
+	input := textinput.New()
+	input.Prompt = "/ "
+	input.Placeholder = "filter by name or description"
+	input.Width = 40
+	input.SetValue(m.filter)
+	input.CursorEnd()
+	input.Focus()
+	m.filterInput = input
+	m.filtering = true
+	return m, textinput.Blink
+}
+
+// updateFilter handles keys while typing a filter; the list narrows as
+// you type.
+func (m Model) updateFilter(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "ctrl+c":
+		return m, tea.Quit
+	case "esc":
+		m.filtering = false
+		m.filter = ""
+		m.selectedIndex = 0
+		return m, nil
+	case "enter", "up", "down":
+		m.filtering = false
+		return m, nil
+	}
+
+	var cmd tea.Cmd
+	m.filterInput, cmd = m.filterInput.Update(msg)
+	if value := strings.TrimSpace(m.filterInput.Value()); value != m.filter {
+		m.filter = value
+		m.selectedIndex = 0
+	}
+	return m, cmd
+}
+
+// renderFilter shows the filter being typed, or the one applied, with the
+// number of matches.
+func (m Model) renderFilter(matched, total int) []string {
+	if m.filtering {
+		return []string{inputFocusedStyle.Render(m.filterInput.View()), ""}
+	}
+	if m.filter == "" {
+		return nil
+	}
+	status := fmt.Sprintf("Filter: %s (%d of %d) • / to change, Esc to clear", m.filter, matched, total)
+	return []string{lipgloss.NewStyle().Foreground(yellow).Render(status), ""}
+}
+
+// noMatches renders the empty state of a filtered list.
+func (m Model) noMatches(what string) string {
+	return cardStyle.Render(fmt.Sprintf("No %s match %q.\n\nPress [Esc] to clear the filter.", what, m.filter))
+}
+
+func (m Model) filteredAgents() []*cluster.AgentBinding {
+	if m.filter == "" {
+		return m.agents
+	}
+	var agents []*cluster.AgentBinding
+	for _, ag := range m.agents {
+		if fuzzyMatch(m.filter, ag.Name, ag.Description, strings.Join(ag.Triggers, " ")) {
+			agents = append(agents, ag)
+		}
+	}
+	return agents
+}
+
+func (m Model) filteredRemoteAgents() []*controller.Agent {
+	if m.filter == "" {
+		return m.remoteAgents
+	}
+	var agents []*controller.Agent
+	for _, ag := range m.remoteAgents {
+		if fuzzyMatch(m.filter, ag.Name, ag.Description, m.nodeName(ag.NodeID)) {
+			agents = append(agents, ag)
+		}
+	}
+	return agents
+}
+
+func (m Model) filteredChannels() []*cluster.ChannelBinding {
+	if m.filter == "" {
+		return m.channels
+	}
+	var channels []*cluster.ChannelBinding
+	for _, ch := range m.channels {
+		if fuzzyMatch(m.filter, ch.Name, ch.Type) {
+			channels = append(channels, ch)
+		}
+	}
+	return channels
+}
+
+func (m Model) filteredJobs() []*scheduler.Job {
+	if m.filter == "" {
+		return m.jobs
+	}
+	var jobs []*scheduler.Job
+	for _, job := range m.jobs {
+		if fuzzyMatch(m.filter, job.Name, job.Agent, job.Task) {
+			jobs = append(jobs, job)
+		}
+	}
+	return jobs
+}
+
+func (m Model) filteredNodes() []*controller.Node {
+	if m.filter == "" {
+		return m.nodes
+	}
+	var nodes []*controller.Node
+	for _, n := range m.nodes {
+		if fuzzyMatch(m.filter, n.Name, n.ID, n.Address) {
+			nodes = append(nodes, n)
+		}
+	}
+	return nodes
+}
+
+// fuzzyMatch reports whether every word of pattern occurs, case-insensitively
+// and in order but not necessarily contiguous, in one of fields: "sup bot"
+// matches "support-bot".
+func fuzzyMatch(pattern string, fields ...string) bool {
+	for _, word := range strings.Fields(strings.ToLower(pattern)) {
+		found := false
+		for _, f := range fields {
+			if subsequence(word, strings.ToLower(f)) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+// subsequence reports whether the runes of sub appear in s in order.
+func subsequence(sub, s string) bool {
+	r := []rune(sub)
+	i := 0
+	for _, c := range s {
+		if i < len(r) && unicode.ToLower(c) == r[i] {
+			i++
+		}
+	}
+	return i == len(r)
+}
@@ -0,0 +1,281 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/eachlabs/klaw/internal/scheduler"
+)
+
+// Fields of the job form
+const (
+	jobFieldName = iota
+	jobFieldSchedule
+	jobFieldAgent
+	jobFieldTask
+)
+
+// selectedJob returns the job under the cursor, or nil.
+func (m Model) selectedJob() *scheduler.Job {
+	if jobs := m.filteredJobs(); m.selectedIndex < len(jobs) {
+		return jobs[m.selectedIndex]
+	}
+	return nil
+}
+
+// initJobForm prepares the form for a new job, or for editing job.
+func (m *Model) initJobForm(job *scheduler.Job) {
+	m.inputs = make([]textinput.Model, 4)
+	for i := range m.inputs {
+		m.inputs[i] = textinput.New()
+	}
+
+	m.inputs[jobFieldName].Placeholder = "daily-report"
+	m.inputs[jobFieldName].Width = 30
+
+	m.inputs[jobFieldSchedule].Placeholder = "every day at 9am"
+	m.inputs[jobFieldSchedule].Width = 40
+
+	m.inputs[jobFieldAgent].Placeholder = "support"
+	m.inputs[jobFieldAgent].Width = 30
+
+	m.inputs[jobFieldTask].Placeholder = "Summarize yesterday's support tickets"
+	m.inputs[jobFieldTask].CharLimit = 2000
+	m.inputs[jobFieldTask].Width = 60
+
+	m.editJobID = ""
+	if job != nil {
+		m.editJobID = job.ID
+		m.inputs[jobFieldName].SetValue(job.Name)
+		m.inputs[jobFieldSchedule].SetValue(job.Schedule)
+		m.inputs[jobFieldAgent].SetValue(job.Agent)
+		m.inputs[jobFieldTask].SetValue(job.Task)
+	} else if len(m.agents) > 0 {
+		m.inputs[jobFieldAgent].SetValue(m.agents[0].Name)
+	}
+
+	m.inputs[jobFieldName].Focus()
+	m.focusedInput = jobFieldName
+}
+
+// cycleJobAgent sets the agent field to the next (step 1) or previous
+// (step -1) agent.
+func (m *Model) cycleJobAgent(step int) {
+	n := len(m.agents)
+	if n == 0 {
+		return
+	}
+	idx := -1
+	for i, ag := range m.agents {
+		if ag.Name == m.inputs[jobFieldAgent].Value() {
+			idx = i
+		}
+	}
+	switch {
+	case idx < 0 && step > 0:
+		idx = 0
+	case idx < 0:
+		idx = n - 1
+	default:
+		idx = (idx + step + n) % n
+	}
+	m.inputs[jobFieldAgent].SetValue(m.agents[idx].Name)
+	m.inputs[jobFieldAgent].CursorEnd()
+}
+
+func (m Model) submitJobForm() (tea.Model, tea.Cmd) {
+	name := strings.TrimSpace(m.inputs[jobFieldName].Value())
+	schedule := strings.TrimSpace(m.inputs[jobFieldSchedule].Value())
+	agentName := strings.TrimSpace(m.inputs[jobFieldAgent].Value())
+	task := strings.TrimSpace(m.inputs[jobFieldTask].Value())
+
+	if name == "" || schedule == "" || agentName == "" || task == "" {
+		m.err = fmt.Errorf("name, schedule, agent and task are required")
+		return m, nil
+	}
+	if m.scheduler == nil {
+		m.err = fmt.Errorf("scheduler not available")
+		return m, nil
+	}
+	cron, err := scheduler.ParseSchedule(schedule)
+	if err != nil {
+		m.err = fmt.Errorf("invalid schedule: %w", err)
+		return m, nil
+	}
+	known := false
+	for _, ag := range m.agents {
+		if ag.Name == agentName {
+			known = true
+		}
+	}
+	if !known {
+		m.err = fmt.Errorf("agent not found: %s", agentName)
+		return m, nil
+	}
+	if existing, err := m.scheduler.FindJob(m.clusterName, m.namespace, name); err == nil && existing.ID != m.editJobID {
+		m.err = fmt.Errorf("job %q already exists", name)
+		return m, nil
+	}
+
+	if m.editJobID == "" {
+		if _, err := m.scheduler.CreateJob(name, schedule, agentName, task, m.clusterName, m.namespace); err != nil {
+			m.err = err
+			return m, nil
+		}
+		m.notice = fmt.Sprintf("Created job %s", name)
+	} else {
+		_, err := m.scheduler.UpdateJob(m.editJobID, func(job *scheduler.Job) {
+			job.Name = name
+			job.Schedule = schedule
+			job.Cron = cron
+			job.Agent = agentName
+			job.Task = task
+		})
+		if err != nil {
+			m.err = err
+			return m, nil
+		}
+		m.notice = fmt.Sprintf("Saved job %s", name)
+	}
+
+	m.viewMode = ViewList
+	m.err = nil
+	return m, m.loadData()
+}
+
+// handleJobRun runs the selected job now.
+func (m Model) handleJobRun() (tea.Model, tea.Cmd) {
+	job := m.selectedJob()
+	if m.activeTab != TabJobs || job == nil || m.scheduler == nil {
+		return m, nil
+	}
+	if err := m.scheduler.RunJobNow(job.ID); err != nil {
+		m.err = err
+		return m, nil
+	}
+	m.err = nil
+	m.notice = fmt.Sprintf("Running %s with %s...", job.Name, job.Agent)
+	return m, m.loadData()
+}
+
+// toggleJob enables or disables the selected job.
+func (m Model) toggleJob() (tea.Model, tea.Cmd) {
+	job := m.selectedJob()
+	if job == nil || m.scheduler == nil {
+		return m, nil
+	}
+	var err error
+	if job.Enabled {
+		err = m.scheduler.DisableJob(job.ID)
+		m.notice = fmt.Sprintf("Disabled %s", job.Name)
+	} else {
+		err = m.scheduler.EnableJob(job.ID)
+		m.notice = fmt.Sprintf("Enabled %s", job.Name)
+	}
+	m.err = err
+	return m, m.loadData()
+}
+
+func (m Model) renderJobForm() string {
+	var sections []string
+
+	heading := "⏰ New Job"
+	if m.editJobID != "" {
+		heading = "⏰ Edit Job"
+	}
+	sections = append(sections, lipgloss.NewStyle().Bold(true).Foreground(white).Render(heading), "")
+
+	labels := []string{"Name:", "Schedule:", "Agent:", "Task:"}
+	hint := lipgloss.NewStyle().Foreground(gray)
+	for i, input := range m.inputs {
+		style := inputStyle
+		if i == m.focusedInput {
+			style = inputFocusedStyle
+		}
+		sections = append(sections, labelStyle.Render(labels[i]), style.Render(input.View()))
+
+		switch i {
+		case jobFieldSchedule:
+			sections = append(sections, schedulePreview(input.Value()))
+		case jobFieldAgent:
+			sections = append(sections, hint.Render("←/→ to pick an agent"))
+		}
+		sections = append(sections, "")
+	}
+
+	if m.err != nil {
+		sections = append(sections, badgeError.Render(fmt.Sprintf("Error: %v", m.err)))
+	}
+	sections = append(sections, hint.Render("Enter: next field, saves on the last • Esc: cancel"))
+
+	return strings.Join(sections, "\n")
+}
+
+// schedulePreview shows the cron expression a schedule parses to and when
+// it runs next.
+func schedulePreview(schedule string) string {
+	schedule = strings.TrimSpace(schedule)
+	if schedule == "" {
+		return lipgloss.NewStyle().Foreground(gray).Render("e.g. every 15 minutes, every monday at 10am, 0 9 * * 1-5")
+	}
+	cron, err := scheduler.ParseSchedule(schedule)
+	if err != nil {
+		return badgeError.Render("✗ " + err.Error())
+	}
+	return badgeActive.Render(fmt.Sprintf("✓ %s  (%s, next %s)",
+		cron, scheduler.FormatSchedule(cron), scheduler.NextRunTime(cron).Format("Mon Jan 02 15:04")))
+}
+
+func (m Model) renderJobDetail(job *scheduler.Job) string {
+	var sections []string
+
+	title := lipgloss.NewStyle().Bold(true).Foreground(white).Render(fmt.Sprintf("⏰ %s", job.Name))
+	sections = append(sections, title, "")
+
+	status := badgeActive.Render("enabled")
+	if !job.Enabled {
+		status = badgeInactive.Render("disabled")
+	}
+	nextRun, lastRun := "-", "never"
+	if job.NextRun != nil {
+		nextRun = job.NextRun.Format("Mon Jan 02 15:04")
+	}
+	if job.LastRun != nil {
+		lastRun = job.LastRun.Format(time.RFC3339)
+	}
+	lines := []string{
+		fmt.Sprintf("ID:        %s", job.ID),
+		fmt.Sprintf("Schedule:  %s (%s)", job.Schedule, scheduler.FormatSchedule(job.Cron)),
+		fmt.Sprintf("Agent:     %s", job.Agent),
+		fmt.Sprintf("Status:    %s", status),
+		fmt.Sprintf("Next run:  %s", nextRun),
+		fmt.Sprintf("Last run:  %s (%d runs)", lastRun, job.RunCount),
+	}
+	if ch := job.Config["channel"]; ch != "" {
+		lines = append(lines, fmt.Sprintf("Channel:   %s", ch))
+	}
+	sections = append(sections, cardStyle.Render(lipgloss.JoinVertical(lipgloss.Left, lines...)))
+
+	sections = append(sections, cardTitleStyle.Render("📝 Task"))
+	sections = append(sections, lipgloss.NewStyle().Foreground(body).Render(job.Task), "")
+
+	switch {
+	case job.LastError != "":
+		sections = append(sections, cardTitleStyle.Render("Last error"), badgeError.Render(job.LastError))
+	case job.LastResult != "":
+		result := strings.Split(strings.TrimSpace(job.LastResult), "\n")
+		if len(result) > 15 {
+			result = append(result[:15], fmt.Sprintf("... and %d more lines", len(result)-15))
+		}
+		sections = append(sections, cardTitleStyle.Render("Last result"), strings.Join(result, "\n"))
+	}
+
+	if m.notice != "" {
+		sections = append(sections, "", badgeActive.Render(m.notice))
+	}
+	return strings.Join(sections, "\n")
+}
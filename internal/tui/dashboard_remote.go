@@ -0,0 +1,420 @@
+package tui
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/spinner"
+	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/eachlabs/klaw/internal/controller"
+)
+
+// RemoteController is the controller a remote dashboard monitors.
+// *controller.Client implements it.
+type RemoteController interface {
+	Address() string
+	ListNodes(ctx context.Context) ([]*controller.Node, error)
+	ListAgents(ctx context.Context) ([]*controller.Agent, error)
+	ListTasks(ctx context.Context, status, agentName string) ([]*controller.Task, error)
+}
+
+// remoteTabs are the tabs of a remote dashboard.
+var remoteTabs = []Tab{TabOverview, TabNodes, TabAgents, TabTasks, TabSettings}
+
+// remoteTimeout bounds each refresh of a remote dashboard.
+const remoteTimeout = 5 * time.Second
+
+type remoteDataMsg struct {
+	nodes  []*controller.Node
+	agents []*controller.Agent
+	tasks  []*controller.Task
+	err    error
+}
+
+// NewRemoteDashboard creates a read-only dashboard of the nodes, agents and
+// tasks of a remote controller.
+func NewRemoteDashboard(ctrl RemoteController) Model {
+	s := spinner.New()
+	s.Spinner = spinner.Dot
+	s.Style = lipgloss.NewStyle().Foreground(purple)
+
+	return Model{
+		tabs:      remoteTabs,
+		activeTab: TabOverview,
+		viewMode:  ViewList,
+		remote:    ctrl,
+		loading:   true,
+		spinner:   s,
+		viewport:  viewport.New(80, 20),
+		formData:  make(map[string]string),
+	}
+}
+
+// tabIndex returns the position of the active tab.
+func (m Model) tabIndex() int {
+	for i, t := range m.tabs {
+		if t == m.activeTab {
+			return i
+		}
+	}
+	return 0
+}
+
+func (m Model) loadRemoteData() tea.Cmd {
+	ctrl := m.remote
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), remoteTimeout)
+		defer cancel()
+
+		nodes, err := ctrl.ListNodes(ctx)
+		if err != nil {
+			return remoteDataMsg{err: err}
+		}
+		agents, err := ctrl.ListAgents(ctx)
+		if err != nil {
+			return remoteDataMsg{err: err}
+		}
+		tasks, err := ctrl.ListTasks(ctx, "", "")
+		if err != nil {
+			return remoteDataMsg{err: err}
+		}
+
+		sort.Slice(nodes, func(i, j int) bool { return nodes[i].Name < nodes[j].Name })
+		sort.Slice(agents, func(i, j int) bool { return agents[i].Name < agents[j].Name })
+		sort.SliceStable(tasks, func(i, j int) bool { return tasks[i].CreatedAt.After(tasks[j].CreatedAt) })
+		return remoteDataMsg{nodes: nodes, agents: agents, tasks: tasks}
+	}
+}
+
+// renderRemoteInfo shows the controller and whether it is reachable.
+func (m Model) renderRemoteInfo() []string {
+	info := lipgloss.NewStyle().Foreground(gray).Render("🔌 " + truncate(m.remote.Address(), 19))
+	status := badgeActive.Render("● connected")
+	switch {
+	case m.remoteErr != nil:
+		status = badgeError.Render("● unreachable")
+	case m.loading:
+		status = badgeInactive.Render("● connecting")
+	}
+	return []string{info, status}
+}
+
+func (m Model) renderRemote(width int) string {
+	switch m.activeTab {
+	case TabNodes:
+		return m.renderNodes(width)
+	case TabAgents:
+		return m.renderRemoteAgents(width)
+	case TabTasks:
+		return m.renderTasks(width)
+	case TabSettings:
+		return m.renderRemoteSettings(width)
+	default:
+		return m.renderRemoteOverview(width)
+	}
+}
+
+// remoteError renders the last refresh error, if any.
+func (m Model) remoteError() []string {
+	if m.remoteErr == nil {
+		return nil
+	}
+	return []string{badgeError.Render(fmt.Sprintf("Error: %v", m.remoteErr)), ""}
+}
+
+func (m Model) renderRemoteOverview(width int) string {
+	var sections []string
+
+	title := lipgloss.NewStyle().Bold(true).Foreground(white).Render("📊 Overview")
+	sections = append(sections, title, "")
+	sections = append(sections, m.remoteError()...)
+
+	readyNodes, running, failed := 0, 0, 0
+	for _, n := range m.nodes {
+		if n.Status == "ready" {
+			readyNodes++
+		}
+	}
+	for _, t := range m.tasks {
+		switch t.Status {
+		case "pending", "dispatched", "queued", "running":
+			running++
+		case "failed":
+			failed++
+		}
+	}
+
+	stats := []struct {
+		value string
+		label string
+		color lipgloss.Color
+	}{
+		{fmt.Sprintf("%d/%d", readyNodes, len(m.nodes)), "Nodes ready", purple},
+		{fmt.Sprintf("%d", len(m.remoteAgents)), "Agents", blue},
+		{fmt.Sprintf("%d", running), "Tasks running", yellow},
+		{fmt.Sprintf("%d", failed), "Tasks failed", red},
+	}
+	var statCards []string
+	for _, s := range stats {
+		card := lipgloss.NewStyle().
+			Border(lipgloss.RoundedBorder()).
+			BorderForeground(s.color).
+			Padding(1, 3).
+			Render(
+				lipgloss.JoinVertical(lipgloss.Center,
+					lipgloss.NewStyle().Bold(true).Foreground(s.color).Render(s.value),
+					lipgloss.NewStyle().Foreground(gray).Render(s.label),
+				),
+			)
+		statCards = append(statCards, card)
+	}
+	sections = append(sections, lipgloss.JoinHorizontal(lipgloss.Top, statCards...), "")
+
+	if len(m.nodes) > 0 {
+		var nodeList []string
+		for _, n := range m.nodes {
+			status := badgeInactive.Render("●")
+			if n.Status == "ready" {
+				status = badgeActive.Render("●")
+			}
+			nodeList = append(nodeList, fmt.Sprintf("  %s %s (%d agents)", status, n.Name, len(n.AgentIDs)))
+		}
+		sections = append(sections, cardTitleStyle.Render("🖥️ Nodes"), strings.Join(nodeList, "\n"), "")
+	}
+
+	if len(m.tasks) > 0 {
+		var taskList []string
+		for i, t := range m.tasks {
+			if i >= 5 {
+				taskList = append(taskList, lipgloss.NewStyle().Foreground(gray).Render(fmt.Sprintf("  ... and %d more", len(m.tasks)-5)))
+				break
+			}
+			taskList = append(taskList, fmt.Sprintf("  %s %s: %s", taskStatusBadge(t.Status), t.AgentName, truncate(t.Prompt, width-30)))
+		}
+		sections = append(sections, cardTitleStyle.Render("📋 Recent Tasks"), strings.Join(taskList, "\n"))
+	}
+
+	return strings.Join(sections, "\n")
+}
+
+func (m Model) renderRemoteAgents(width int) string {
+	var sections []string
+
+	title := lipgloss.NewStyle().Bold(true).Foreground(white).Render("🤖 Agents")
+	sections = append(sections, title, "")
+	sections = append(sections, m.remoteError()...)
+
+	if len(m.remoteAgents) == 0 {
+		sections = append(sections, cardStyle.Render("No agents registered.\n\nAgents register when a node starts: klaw node join <controller>"))
+		return strings.Join(sections, "\n")
+	}
+
+	agents := m.filteredRemoteAgents()
+	sections = append(sections, m.renderFilter(len(agents), len(m.remoteAgents))...)
+	if len(agents) == 0 {
+		sections = append(sections, m.noMatches("agents"))
+		return strings.Join(sections, "\n")
+	}
+
+	header := tableHeaderStyle.Width(width - 4).Render(
+		fmt.Sprintf("  %-15s %-15s %-20s %-10s %s", "NAME", "NODE", "NAMESPACE", "STATUS", "MODEL"))
+	sections = append(sections, header)
+
+	for i, ag := range agents {
+		style := tableRowStyle
+		prefix := "  "
+		if i == m.selectedIndex {
+			style = tableRowSelectedStyle
+			prefix = "→ "
+		}
+		status := badgeInactive.Render(ag.Status)
+		if ag.Status == "running" {
+			status = badgeActive.Render(ag.Status)
+		}
+		row := style.Render(fmt.Sprintf("%s%-13s %-15s %-20s %-10s %s",
+			prefix, truncate(ag.Name, 13), truncate(m.nodeName(ag.NodeID), 15),
+			truncate(ag.Cluster+"/"+ag.Namespace, 20), status, truncate(ag.Model, 20)))
+		sections = append(sections, row)
+	}
+
+	return strings.Join(sections, "\n")
+}
+
+func (m Model) renderTasks(width int) string {
+	var sections []string
+
+	title := lipgloss.NewStyle().Bold(true).Foreground(white).Render("📋 Tasks")
+	sections = append(sections, title, "")
+	sections = append(sections, m.remoteError()...)
+
+	if len(m.tasks) == 0 {
+		sections = append(sections, cardStyle.Render("No tasks yet.\n\nDispatch one with: klaw dispatch <agent> \"...\""))
+		return strings.Join(sections, "\n")
+	}
+
+	header := tableHeaderStyle.Render(fmt.Sprintf("%-10s %-13s %-12s %-13s %-15s %s", "ID", "AGENT", "NODE", "STATUS", "CREATED", "PROMPT"))
+	sections = append(sections, header)
+
+	for i, t := range m.tasks {
+		style := tableRowStyle
+		if i == m.selectedIndex {
+			style = tableRowSelectedStyle
+		}
+		row := style.Render(fmt.Sprintf("%-10s %-13s %-12s %-13s %-15s %s",
+			truncate(t.ID, 10), truncate(t.AgentName, 13), truncate(m.nodeName(t.NodeID), 12),
+			taskStatusBadge(t.Status), t.CreatedAt.Format("Jan 02 15:04"), truncate(t.Prompt, max(width-72, 10))))
+		sections = append(sections, row)
+	}
+
+	return strings.Join(sections, "\n")
+}
+
+func (m Model) renderRemoteSettings(width int) string {
+	var sections []string
+
+	title := lipgloss.NewStyle().Bold(true).Foreground(white).Render("⚙️ Settings")
+	sections = append(sections, title, "")
+
+	status := "connected"
+	if m.remoteErr != nil {
+		status = "unreachable"
+	}
+	card := cardStyle.Width(width - 6).Render(
+		lipgloss.JoinVertical(lipgloss.Left,
+			cardTitleStyle.Render("Controller"),
+			fmt.Sprintf("Address: %s", m.remote.Address()),
+			fmt.Sprintf("Status:  %s", status),
+			fmt.Sprintf("Refresh: every %s", 5*time.Second),
+			"Mode:    read-only",
+		),
+	)
+	sections = append(sections, card)
+
+	return strings.Join(sections, "\n")
+}
+
+func (m Model) renderNodeDetail(node *controller.Node) string {
+	var sections []string
+
+	title := lipgloss.NewStyle().Bold(true).Foreground(white).Render(fmt.Sprintf("🖥️ %s", node.Name))
+	sections = append(sections, title, "")
+
+	status := badgeActive.Render(node.Status)
+	if node.Status != "ready" {
+		status = badgeInactive.Render(node.Status)
+	}
+	lines := []string{
+		fmt.Sprintf("ID:        %s", node.ID),
+		fmt.Sprintf("Address:   %s", valueOr(node.Address, "-")),
+		fmt.Sprintf("Status:    %s", status),
+		fmt.Sprintf("Version:   %s", valueOr(node.Version, "-")),
+		fmt.Sprintf("Joined:    %s", node.JoinedAt.Format(time.RFC3339)),
+		fmt.Sprintf("Last seen: %s", node.LastSeen.Format(time.RFC3339)),
+	}
+	if len(node.Labels) > 0 {
+		var labels []string
+		for k, v := range node.Labels {
+			labels = append(labels, k+"="+v)
+		}
+		sort.Strings(labels)
+		lines = append(lines, fmt.Sprintf("Labels:    %s", strings.Join(labels, ", ")))
+	}
+	sections = append(sections, cardStyle.Render(lipgloss.JoinVertical(lipgloss.Left, lines...)))
+
+	var agentList []string
+	for _, ag := range m.remoteAgents {
+		if ag.NodeID == node.ID {
+			agentList = append(agentList, fmt.Sprintf("  • %s (%s/%s, %s)", ag.Name, ag.Cluster, ag.Namespace, ag.Status))
+		}
+	}
+	if len(agentList) > 0 {
+		sections = append(sections, cardTitleStyle.Render("🤖 Agents"), strings.Join(agentList, "\n"))
+	}
+
+	return strings.Join(sections, "\n")
+}
+
+func (m Model) renderTaskDetail(t *controller.Task) string {
+	var sections []string
+
+	title := lipgloss.NewStyle().Bold(true).Foreground(white).Render(fmt.Sprintf("📋 Task %s", t.ID))
+	sections = append(sections, title, "")
+
+	lines := []string{
+		fmt.Sprintf("Agent:     %s", t.AgentName),
+		fmt.Sprintf("Node:      %s", valueOr(m.nodeName(t.NodeID), "-")),
+		fmt.Sprintf("Type:      %s", valueOr(t.Type, "-")),
+		fmt.Sprintf("Status:    %s", taskStatusBadge(t.Status)),
+		fmt.Sprintf("Created:   %s", t.CreatedAt.Format(time.RFC3339)),
+	}
+	if t.StartedAt != nil && t.FinishedAt != nil {
+		lines = append(lines, fmt.Sprintf("Duration:  %s", t.FinishedAt.Sub(*t.StartedAt).Round(time.Second)))
+	}
+	sections = append(sections, cardStyle.Render(lipgloss.JoinVertical(lipgloss.Left, lines...)))
+
+	sections = append(sections, cardTitleStyle.Render("📝 Prompt"))
+	sections = append(sections, lipgloss.NewStyle().Foreground(body).Render(t.Prompt), "")
+
+	if len(t.Events) > 0 {
+		var tools []string
+		for _, ev := range t.Events {
+			switch {
+			case ev.Type == controller.EventToolCall:
+				tools = append(tools, fmt.Sprintf("%s %s %s", ev.Time.Format("15:04:05"), ev.Tool, truncate(ev.Input, 60)))
+			case ev.IsError:
+				tools = append(tools, badgeError.Render("           ✗ "+truncate(ev.Output, 60)))
+			default:
+				tools = append(tools, lipgloss.NewStyle().Foreground(gray).Render("           ↳ "+truncate(ev.Output, 60)))
+			}
+		}
+		sections = append(sections, cardTitleStyle.Render("🔧 Tools"), strings.Join(tools, "\n"), "")
+	}
+
+	switch {
+	case t.Error != "":
+		sections = append(sections, cardTitleStyle.Render("Error"), badgeError.Render(t.Error))
+	case t.Result != "":
+		result := strings.Split(strings.TrimSpace(t.Result), "\n")
+		if len(result) > 15 {
+			result = append(result[:15], fmt.Sprintf("... and %d more lines", len(result)-15))
+		}
+		sections = append(sections, cardTitleStyle.Render("Result"), strings.Join(result, "\n"))
+	}
+
+	return strings.Join(sections, "\n")
+}
+
+// nodeName returns the name of the node with the given ID, or the ID.
+func (m Model) nodeName(id string) string {
+	for _, n := range m.nodes {
+		if n.ID == id {
+			return n.Name
+		}
+	}
+	return id
+}
+
+func taskStatusBadge(status string) string {
+	switch status {
+	case "completed":
+		return badgeActive.Render(status)
+	case "failed":
+		return badgeError.Render(status)
+	case "pending", "dispatched", "running":
+		return lipgloss.NewStyle().Foreground(yellow).Render(status)
+	default:
+		return badgeInactive.Render(status)
+	}
+}
+
+func valueOr(s, fallback string) string {
+	if s == "" {
+		return fallback
+	}
+	return s
+}
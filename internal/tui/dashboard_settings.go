@@ -0,0 +1,137 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/eachlabs/klaw/internal/cluster"
+)
+
+// Settings of the Settings tab
+const (
+	settingMode = iota
+	settingDefaultAgent
+	settingAllowManual
+	settingCount
+)
+
+// routingModes are the orchestrator modes, in the order they cycle through.
+var routingModes = []string{"rules", "ai", "hybrid", "disabled"}
+
+// routingModeHelp describes each orchestrator mode.
+var routingModeHelp = map[string]string{
+	"rules":    "routing rules, then agent triggers, then the default agent",
+	"ai":       "an LLM picks the agent from their descriptions",
+	"hybrid":   "rules and triggers first, then the LLM",
+	"disabled": "only @agent messages are routed",
+}
+
+// defaultOrchestratorConfig is used by namespaces without one: only @agent
+// messages are routed.
+func defaultOrchestratorConfig() *cluster.OrchestratorConfig {
+	return &cluster.OrchestratorConfig{Mode: "disabled", AllowManual: true}
+}
+
+// changeSetting moves the selected setting to its next (step 1) or previous
+// (step -1) value and saves the namespace's orchestrator config.
+func (m Model) changeSetting(step int) (tea.Model, tea.Cmd) {
+	if m.orchestrator == nil {
+		return m, nil
+	}
+	orch := *m.orchestrator
+	orch.Rules = append([]cluster.RoutingRule(nil), m.orchestrator.Rules...)
+
+	switch m.selectedIndex {
+	case settingMode:
+		orch.Mode = cycle(routingModes, orch.Mode, step)
+		m.notice = fmt.Sprintf("Routing mode set to %s", orch.Mode)
+	case settingDefaultAgent:
+		names := []string{""}
+		for _, ag := range m.agents {
+			names = append(names, ag.Name)
+		}
+		orch.DefaultAgent = cycle(names, orch.DefaultAgent, step)
+		m.notice = fmt.Sprintf("Default agent set to %s", valueOr(orch.DefaultAgent, "(none)"))
+	case settingAllowManual:
+		orch.AllowManual = !orch.AllowManual
+		m.notice = fmt.Sprintf("@agent routing %s", map[bool]string{true: "enabled", false: "disabled"}[orch.AllowManual])
+	default:
+		return m, nil
+	}
+
+	if err := m.store.UpdateNamespaceOrchestrator(m.clusterName, m.namespace, &orch); err != nil {
+		m.err = err
+		m.notice = ""
+		return m, nil
+	}
+	m.err = nil
+	m.orchestrator = &orch
+	return m, nil
+}
+
+// cycle returns the value step places after current in values.
+func cycle(values []string, current string, step int) string {
+	n := len(values)
+	for i, v := range values {
+		if v == current {
+			return values[(i+step+n)%n]
+		}
+	}
+	return values[0]
+}
+
+func (m Model) renderSettings(width int) string {
+	var sections []string
+
+	title := lipgloss.NewStyle().Bold(true).Foreground(white).Render("⚙️ Settings")
+	sections = append(sections, title)
+	sections = append(sections, "")
+
+	// Cluster info
+	clusterCard := cardStyle.Width(width - 6).Render(
+		lipgloss.JoinVertical(lipgloss.Left,
+			cardTitleStyle.Render("Cluster"),
+			fmt.Sprintf("Name:      %s", m.clusterName),
+			fmt.Sprintf("Namespace: %s", m.namespace),
+		),
+	)
+	sections = append(sections, clusterCard)
+
+	orch := m.orchestrator
+	if orch == nil {
+		orch = defaultOrchestratorConfig()
+	}
+	rows := []struct {
+		label string
+		value string
+		hint  string
+	}{
+		{"Mode", orch.Mode, routingModeHelp[orch.Mode]},
+		{"Default Agent", valueOr(orch.DefaultAgent, "(none)"), "for messages nothing else routes"},
+		{"Allow Manual", fmt.Sprintf("%v", orch.AllowManual), "@agent syntax"},
+	}
+	hint := lipgloss.NewStyle().Foreground(gray)
+	lines := []string{cardTitleStyle.Render("Orchestrator")}
+	for i, row := range rows {
+		style := tableRowStyle
+		prefix := "  "
+		if i == m.selectedIndex {
+			style = tableRowSelectedStyle
+			prefix = "→ "
+		}
+		lines = append(lines, style.Render(fmt.Sprintf("%s%-14s ‹ %s ›", prefix, row.label+":", row.value))+"  "+hint.Render(row.hint))
+	}
+	lines = append(lines, "", fmt.Sprintf("   %-14s %d (klaw routing add)", "Rules:", len(orch.Rules)))
+	sections = append(sections, cardStyle.Width(width-6).Render(lipgloss.JoinVertical(lipgloss.Left, lines...)))
+
+	switch {
+	case m.err != nil:
+		sections = append(sections, badgeError.Render(fmt.Sprintf("Error: %v", m.err)))
+	case m.notice != "":
+		sections = append(sections, badgeActive.Render(m.notice)+hint.Render(" • restart klaw start to apply"))
+	}
+
+	return strings.Join(sections, "\n")
+}
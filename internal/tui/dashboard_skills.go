@@ -0,0 +1,341 @@
+package tui
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/eachlabs/klaw/internal/skill"
+)
+
+// skillsPane is the state of the Skills tab. Its list holds the installed
+// skills followed by the marketplace results.
+type skillsPane struct {
+	loader *skill.SkillLoader
+	market *skill.Marketplace
+
+	installed []string
+	results   []skill.MarketplaceSkill
+	query     string // last search; empty for featured skills
+	offline   bool   // results come from the built-in catalog
+	loading   bool
+	busy      string // skill being installed
+
+	searching bool
+	search    textinput.Model
+}
+
+// skillsMsg is the result of a marketplace request or an install.
+type skillsMsg struct {
+	results   []skill.MarketplaceSkill
+	offline   bool
+	query     string
+	installed string
+	err       error
+}
+
+// SetSkills enables the Skills tab.
+func (m *Model) SetSkills(loader *skill.SkillLoader, market *skill.Marketplace) {
+	m.skills.loader = loader
+	m.skills.market = market
+}
+
+// openSkills refreshes the installed skills and loads the featured skills
+// the first time the tab is shown.
+func (m *Model) openSkills() tea.Cmd {
+	if m.skills.loader == nil {
+		return nil
+	}
+	m.refreshInstalledSkills()
+	if m.skills.results != nil || m.skills.loading || m.skills.market == nil {
+		return nil
+	}
+	m.skills.loading = true
+	market := m.skills.market
+	return func() tea.Msg {
+		featured, err := market.GetFeatured()
+		return skillsMsg{results: featured, err: err}
+	}
+}
+
+func (m *Model) refreshInstalledSkills() {
+	installed, err := m.skills.loader.ListSkills()
+	if err != nil {
+		m.err = err
+		return
+	}
+	m.skills.installed = installed
+}
+
+// selectedSkill returns the skill under the cursor: its name, and its
+// marketplace entry unless it is an installed skill.
+func (m Model) selectedSkill() (string, *skill.MarketplaceSkill) {
+	i := m.selectedIndex
+	if i < len(m.skills.installed) {
+		return m.skills.installed[i], nil
+	}
+	i -= len(m.skills.installed)
+	if i < len(m.skills.results) {
+		return m.skills.results[i].Name, &m.skills.results[i]
+	}
+	return "", nil
+}
+
+func (m Model) isInstalled(name string) bool {
+	for _, s := range m.skills.installed {
+		if s == name {
+			return true
+		}
+	}
+	return false
+}
+
+// skillUsers returns the agents that list the skill.
+func (m Model) skillUsers(name string) []string {
+	var users []string
+	for _, ag := range m.agents {
+		for _, s := range ag.Skills {
+			if s == name {
+				users = append(users, ag.Name)
+			}
+		}
+	}
+	return users
+}
+
+// handleSkillKey searches (/), installs (i) or uninstalls (u) skills.
+func (m Model) handleSkillKey(key string) (tea.Model, tea.Cmd) {
+	if m.skills.loader == nil {
+		return m, nil
+	}
+	switch key {
+	case "/":
+		if m.skills.market == nil {
+			return m, nil
+		}
+		input := textinput.New()
+		input.Placeholder = "browser, search, database..."
+		input.Width = 40
+		input.SetValue(m.skills.query)
+		input.CursorEnd()
+		input.Focus()
+		m.skills.search = input
+		m.skills.searching = true
+		return m, textinput.Blink
+
+	case "i":
+		name, entry := m.selectedSkill()
+		if entry == nil || m.skills.busy != "" {
+			return m, nil
+		}
+		if m.isInstalled(name) {
+			m.notice = fmt.Sprintf("%s is already installed", name)
+			return m, nil
+		}
+		m.skills.busy = name
+		m.err = nil
+		m.notice = ""
+		loader := m.skills.loader
+		return m, func() tea.Msg {
+			return skillsMsg{installed: name, err: loader.InstallSkill(name)}
+		}
+
+	case "u":
+		name, entry := m.selectedSkill()
+		if name == "" || (entry != nil && !m.isInstalled(name)) {
+			return m, nil
+		}
+		if err := m.skills.loader.UninstallSkill(name); err != nil {
+			m.err = err
+			return m, nil
+		}
+		m.err = nil
+		m.notice = fmt.Sprintf("Uninstalled %s", name)
+		if users := m.skillUsers(name); len(users) > 0 {
+			m.notice += fmt.Sprintf(" (still listed by %s)", strings.Join(users, ", "))
+		}
+		m.refreshInstalledSkills()
+		m.viewMode = ViewList
+		if max := m.getMaxIndex(); m.selectedIndex >= max && max > 0 {
+			m.selectedIndex = max - 1
+		}
+	}
+	return m, nil
+}
+
+// updateSkillSearch handles keys while typing a search.
+func (m Model) updateSkillSearch(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "ctrl+c":
+		return m, tea.Quit
+
+	case "esc":
+		m.skills.searching = false
+		return m, nil
+
+	case "enter":
+		query := strings.TrimSpace(m.skills.search.Value())
+		m.skills.searching = false
+		m.skills.loading = true
+		m.err = nil
+		market := m.skills.market
+		return m, func() tea.Msg {
+			if query == "" {
+				featured, err := market.GetFeatured()
+				return skillsMsg{results: featured, err: err}
+			}
+			result, err := market.Search(query, "")
+			if err != nil {
+				return skillsMsg{query: query, err: err}
+			}
+			return skillsMsg{results: result.Skills, offline: result.Offline, query: query}
+		}
+	}
+
+	var cmd tea.Cmd
+	m.skills.search, cmd = m.skills.search.Update(msg)
+	return m, cmd
+}
+
+// handleSkillsMsg shows search results or the outcome of an install.
+func (m Model) handleSkillsMsg(msg skillsMsg) (tea.Model, tea.Cmd) {
+	if msg.installed != "" {
+		m.skills.busy = ""
+		if msg.err != nil {
+			m.err = msg.err
+			return m, nil
+		}
+		m.notice = fmt.Sprintf("Installed %s", msg.installed)
+		m.refreshInstalledSkills()
+		return m, nil
+	}
+
+	m.skills.loading = false
+	if msg.err != nil {
+		m.err = msg.err
+		return m, nil
+	}
+	if msg.results == nil {
+		msg.results = []skill.MarketplaceSkill{}
+	}
+	m.skills.results = msg.results
+	m.skills.offline = msg.offline
+	m.skills.query = msg.query
+	m.selectedIndex = len(m.skills.installed)
+	if len(msg.results) == 0 {
+		m.selectedIndex = 0
+	}
+	return m, nil
+}
+
+func (m Model) renderSkills(width int) string {
+	var sections []string
+
+	title := lipgloss.NewStyle().Bold(true).Foreground(white).Render("🧩 Skills")
+	sections = append(sections, title, "")
+
+	if m.skills.loader == nil {
+		sections = append(sections, cardStyle.Render("Skills are not available in this dashboard."))
+		return strings.Join(sections, "\n")
+	}
+
+	switch {
+	case m.err != nil:
+		sections = append(sections, badgeError.Render(fmt.Sprintf("Error: %v", m.err)), "")
+	case m.skills.busy != "":
+		sections = append(sections, m.spinner.View()+" "+lipgloss.NewStyle().Foreground(gray).Render(fmt.Sprintf("Installing %s...", m.skills.busy)), "")
+	case m.notice != "":
+		sections = append(sections, badgeActive.Render(m.notice), "")
+	}
+
+	row := func(i int, text string) string {
+		if i == m.selectedIndex {
+			return tableRowSelectedStyle.Render("→ " + text)
+		}
+		return tableRowStyle.Render("  " + text)
+	}
+
+	sections = append(sections, cardTitleStyle.Render(fmt.Sprintf("Installed (%d)", len(m.skills.installed))))
+	if len(m.skills.installed) == 0 {
+		sections = append(sections, lipgloss.NewStyle().Foreground(gray).Render("  No skills installed. Pick one from the marketplace and press [i]."))
+	}
+	for i, name := range m.skills.installed {
+		used := ""
+		if users := m.skillUsers(name); len(users) > 0 {
+			used = "used by " + strings.Join(users, ", ")
+		}
+		sections = append(sections, row(i, fmt.Sprintf("%-28s %s", name, truncate(used, width-40))))
+	}
+	sections = append(sections, "")
+
+	heading := "Marketplace: featured"
+	if m.skills.query != "" {
+		heading = fmt.Sprintf("Marketplace: %q", m.skills.query)
+	}
+	if m.skills.offline {
+		heading += " (offline catalog)"
+	}
+	sections = append(sections, cardTitleStyle.Render(heading))
+	if m.skills.searching {
+		sections = append(sections, inputFocusedStyle.Render(m.skills.search.View()))
+	}
+	switch {
+	case m.skills.loading:
+		sections = append(sections, m.spinner.View()+" "+lipgloss.NewStyle().Foreground(gray).Render("Loading..."))
+	case len(m.skills.results) == 0:
+		sections = append(sections, lipgloss.NewStyle().Foreground(gray).Render("  No skills found."))
+	}
+	if !m.skills.loading {
+		for i, s := range m.skills.results {
+			mark := "  "
+			if m.isInstalled(s.Name) {
+				mark = badgeActive.Render("✓ ")
+			}
+			sections = append(sections, row(len(m.skills.installed)+i,
+				fmt.Sprintf("%s%-26s %s", mark, truncate(s.Name, 26), truncate(s.Description, width-42))))
+		}
+	}
+
+	return strings.Join(sections, "\n")
+}
+
+func (m Model) renderSkillDetail() string {
+	name, entry := m.selectedSkill()
+	if name == "" {
+		return ""
+	}
+	var sections []string
+
+	title := lipgloss.NewStyle().Bold(true).Foreground(white).Render("🧩 " + name)
+	sections = append(sections, title, "")
+
+	if entry != nil {
+		sections = append(sections, cardStyle.Render(strings.TrimRight(skill.FormatSkillCard(*entry), "\n")))
+	}
+	if m.isInstalled(name) {
+		path := m.skills.loader.SkillPath(name)
+		sections = append(sections, badgeActive.Render("Installed")+lipgloss.NewStyle().Foreground(gray).Render("  "+path), "")
+		if data, err := os.ReadFile(path); err == nil {
+			lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+			if len(lines) > 20 {
+				lines = append(lines[:20], fmt.Sprintf("... and %d more lines", len(lines)-20))
+			}
+			sections = append(sections, strings.Join(lines, "\n"))
+		}
+	} else {
+		sections = append(sections, lipgloss.NewStyle().Foreground(gray).Render("Not installed. Press [i] to install."))
+	}
+
+	switch {
+	case m.err != nil:
+		sections = append(sections, "", badgeError.Render(fmt.Sprintf("Error: %v", m.err)))
+	case m.skills.busy != "":
+		sections = append(sections, "", m.spinner.View()+" Installing...")
+	case m.notice != "":
+		sections = append(sections, "", badgeActive.Render(m.notice))
+	}
+	return strings.Join(sections, "\n")
+}
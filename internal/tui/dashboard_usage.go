@@ -0,0 +1,213 @@
+package tui
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/eachlabs/klaw/internal/cluster"
+	"github.com/eachlabs/klaw/internal/scheduler"
+)
+
+// usageDays is the number of days the Overview usage cards cover.
+const usageDays = 7
+
+// usageStats summarizes the namespace's usage records and message logs of
+// the last usageDays days, oldest day first.
+type usageStats struct {
+	days     []string // YYYY-MM-DD
+	tokens   []int
+	cost     []float64
+	messages []int
+	byAgent  map[string]float64 // cost per agent
+}
+
+// loadUsageStats reads the usage records and message counts of the last
+// usageDays days up to now.
+func loadUsageStats(store *cluster.Store, clusterName, namespace string, now time.Time) *usageStats {
+	start := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location()).AddDate(0, 0, -(usageDays - 1))
+	stats := &usageStats{
+		tokens:   make([]int, usageDays),
+		cost:     make([]float64, usageDays),
+		messages: make([]int, usageDays),
+		byAgent:  make(map[string]float64),
+	}
+	index := make(map[string]int, usageDays)
+	for i := 0; i < usageDays; i++ {
+		day := start.AddDate(0, 0, i).Format("2006-01-02")
+		stats.days = append(stats.days, day)
+		index[day] = i
+	}
+
+	records, _ := store.ListUsage(clusterName, namespace, start)
+	for _, rec := range records {
+		i, ok := index[rec.Timestamp.In(now.Location()).Format("2006-01-02")]
+		if !ok {
+			continue
+		}
+		stats.tokens[i] += rec.InputTokens + rec.OutputTokens
+		stats.cost[i] += rec.Cost
+		stats.byAgent[valueOr(rec.Agent, "(default)")] += rec.Cost
+	}
+
+	counts, _ := store.CountMessagesByDay(clusterName, namespace, start)
+	for day, n := range counts {
+		if i, ok := index[day]; ok {
+			stats.messages[i] += n
+		}
+	}
+	return stats
+}
+
+func (u *usageStats) totalTokens() int {
+	total := 0
+	for _, n := range u.tokens {
+		total += n
+	}
+	return total
+}
+
+func (u *usageStats) totalCost() float64 {
+	total := 0.0
+	for _, c := range u.cost {
+		total += c
+	}
+	return total
+}
+
+func (u *usageStats) totalMessages() int {
+	total := 0
+	for _, n := range u.messages {
+		total += n
+	}
+	return total
+}
+
+// jobSuccessRate returns the share of job runs that succeeded, and the
+// number of runs.
+func jobSuccessRate(jobs []*scheduler.Job) (float64, int) {
+	runs, failed := 0, 0
+	for _, job := range jobs {
+		runs += job.RunCount
+		failed += job.FailCount
+	}
+	if runs == 0 {
+		return 0, 0
+	}
+	return float64(runs-failed) / float64(runs), runs
+}
+
+// renderUsage renders the usage cards and sparklines of the Overview tab.
+func (m Model) renderUsage() []string {
+	u := m.usage
+	if u == nil {
+		return nil
+	}
+
+	jobRate := "—"
+	if rate, runs := jobSuccessRate(m.jobs); runs > 0 {
+		jobRate = fmt.Sprintf("%.0f%%", rate*100)
+	}
+	stats := []struct {
+		value string
+		label string
+		color lipgloss.Color
+	}{
+		{formatTokens(u.totalTokens()), fmt.Sprintf("Tokens (%dd)", usageDays), purple},
+		{fmt.Sprintf("$%.2f", u.totalCost()), fmt.Sprintf("Est. cost (%dd)", usageDays), blue},
+		{jobRate, "Job success", green},
+		{fmt.Sprintf("%d", u.totalMessages()), fmt.Sprintf("Messages (%dd)", usageDays), yellow},
+	}
+	var cards []string
+	for _, s := range stats {
+		cards = append(cards, lipgloss.NewStyle().
+			Border(lipgloss.RoundedBorder()).
+			BorderForeground(s.color).
+			Padding(0, 2).
+			Render(lipgloss.JoinVertical(lipgloss.Center,
+				lipgloss.NewStyle().Bold(true).Foreground(s.color).Render(s.value),
+				lipgloss.NewStyle().Foreground(gray).Render(s.label),
+			)))
+	}
+
+	hint := lipgloss.NewStyle().Foreground(gray)
+	tokens := make([]float64, len(u.tokens))
+	messages := make([]float64, len(u.messages))
+	for i := range u.days {
+		tokens[i] = float64(u.tokens[i])
+		messages[i] = float64(u.messages[i])
+	}
+	first, _ := time.Parse("2006-01-02", u.days[0])
+	last, _ := time.Parse("2006-01-02", u.days[len(u.days)-1])
+	span := hint.Render(fmt.Sprintf("%s – %s", first.Format("Jan 02"), last.Format("Jan 02")))
+
+	sections := []string{
+		cardTitleStyle.Render("📈 Usage"),
+		lipgloss.JoinHorizontal(lipgloss.Top, cards...),
+		fmt.Sprintf("  %-14s %s  %s", "Messages/day", lipgloss.NewStyle().Foreground(yellow).Render(sparkline(messages)), span),
+		fmt.Sprintf("  %-14s %s  %s", "Tokens/day", lipgloss.NewStyle().Foreground(purple).Render(sparkline(tokens)), span),
+	}
+
+	if len(u.byAgent) > 0 {
+		type agentCost struct {
+			name string
+			cost float64
+		}
+		var costs []agentCost
+		for name, cost := range u.byAgent {
+			costs = append(costs, agentCost{name, cost})
+		}
+		sort.Slice(costs, func(i, j int) bool {
+			if costs[i].cost != costs[j].cost {
+				return costs[i].cost > costs[j].cost
+			}
+			return costs[i].name < costs[j].name
+		})
+		var top []string
+		for i, c := range costs {
+			if i == 3 {
+				break
+			}
+			top = append(top, fmt.Sprintf("%s $%.2f", c.name, c.cost))
+		}
+		sections = append(sections, fmt.Sprintf("  %-14s %s", "Top agents", strings.Join(top, hint.Render(" • "))))
+	} else {
+		sections = append(sections, hint.Render("  No usage recorded yet; klaw start records the usage of every request."))
+	}
+	return append(sections, "")
+}
+
+// sparkline draws values as a row of block characters scaled to the
+// largest value.
+func sparkline(values []float64) string {
+	const blocks = "▁▂▃▄▅▆▇█"
+	levels := []rune(blocks)
+	max := 0.0
+	for _, v := range values {
+		if v > max {
+			max = v
+		}
+	}
+	var b strings.Builder
+	for _, v := range values {
+		level := 0
+		if max > 0 {
+			level = int(v / max * float64(len(levels)-1))
+		}
+		b.WriteRune(levels[level])
+	}
+	return b.String()
+}
+
+// formatTokens abbreviates a token count: 950, 12.3k, 1.2M.
+func formatTokens(n int) string {
+	switch {
+	case n >= 1_000_000:
+		return fmt.Sprintf("%.1fM", float64(n)/1_000_000)
+	case n >= 1_000:
+		return fmt.Sprintf("%.1fk", float64(n)/1_000)
+	}
+	return fmt.Sprintf("%d", n)
+}
@@ -0,0 +1,337 @@
+package tui
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Palette is the set of colors the dashboard and chat draw with. Colors
+// are hex ("#7C3AED") or ANSI ("99") values.
+type Palette struct {
+	Accent    string // logo, active tab, focused inputs
+	OnAccent  string // text on the accent color
+	Success   string
+	Warning   string
+	Error     string
+	Info      string
+	Muted     string // secondary text
+	Border    string
+	Text      string // titles
+	Body      string // prompts, tasks, tool output
+	Highlight string // background of the selected row
+}
+
+// darkPalette is for terminals with a dark background.
+var darkPalette = Palette{
+	Accent:    "#7C3AED",
+	OnAccent:  "#F9FAFB",
+	Success:   "#10B981",
+	Warning:   "#F59E0B",
+	Error:     "#EF4444",
+	Info:      "#3B82F6",
+	Muted:     "#6B7280",
+	Border:    "#374151",
+	Text:      "#F9FAFB",
+	Body:      "#D1D5DB",
+	Highlight: "#EDE9FE",
+}
+
+// lightPalette is for terminals with a light background.
+var lightPalette = Palette{
+	Accent:    "#6D28D9",
+	OnAccent:  "#FFFFFF",
+	Success:   "#047857",
+	Warning:   "#B45309",
+	Error:     "#B91C1C",
+	Info:      "#1D4ED8",
+	Muted:     "#4B5563",
+	Border:    "#D1D5DB",
+	Text:      "#111827",
+	Body:      "#374151",
+	Highlight: "#EDE9FE",
+}
+
+// paletteColors maps the names used in [ui.colors] to the palette's fields.
+func paletteColors(p *Palette) map[string]*string {
+	return map[string]*string{
+		"accent":    &p.Accent,
+		"on_accent": &p.OnAccent,
+		"success":   &p.Success,
+		"warning":   &p.Warning,
+		"error":     &p.Error,
+		"info":      &p.Info,
+		"muted":     &p.Muted,
+		"border":    &p.Border,
+		"text":      &p.Text,
+		"body":      &p.Body,
+		"highlight": &p.Highlight,
+	}
+}
+
+var colorValue = regexp.MustCompile(`^(#[0-9a-fA-F]{6}|#[0-9a-fA-F]{3}|[0-9]{1,3})$`)
+
+// SetTheme selects the palette of the terminal UIs: "dark", "light", or
+// "auto" (or empty) to pick by the terminal's background. overrides
+// replace single colors by name, as in [ui.colors] of config.toml.
+func SetTheme(theme string, overrides map[string]string) error {
+	var p Palette
+	switch strings.ToLower(theme) {
+	case "", "auto":
+		p = lightPalette
+		if lipgloss.HasDarkBackground() {
+			p = darkPalette
+		}
+	case "dark":
+		p = darkPalette
+		lipgloss.SetHasDarkBackground(true)
+	case "light":
+		p = lightPalette
+		lipgloss.SetHasDarkBackground(false)
+	default:
+		return fmt.Errorf("unknown theme %q (use auto, dark or light)", theme)
+	}
+
+	colors := paletteColors(&p)
+	for name, value := range overrides {
+		field, ok := colors[strings.ToLower(name)]
+		if !ok {
+			names := make([]string, 0, len(colors))
+			for n := range colors {
+				names = append(names, n)
+			}
+			sort.Strings(names)
+			return fmt.Errorf("unknown color %q in [ui.colors] (use %s)", name, strings.Join(names, ", "))
+		}
+		if !colorValue.MatchString(value) {
+			return fmt.Errorf("invalid color %s = %q: use a hex (#7C3AED) or ANSI (0-255) color", name, value)
+		}
+		*field = value
+	}
+
+	applyPalette(p)
+	return nil
+}
+
+func init() {
+	applyPalette(darkPalette)
+}
+
+// Colors
+var (
+	purple    lipgloss.Color
+	green     lipgloss.Color
+	red       lipgloss.Color
+	yellow    lipgloss.Color
+	blue      lipgloss.Color
+	gray      lipgloss.Color
+	darkGray  lipgloss.Color
+	white     lipgloss.Color
+	body      lipgloss.Color
+	highlight lipgloss.Color
+	onAccent  lipgloss.Color
+)
+
+// Styles
+var (
+	logoStyle             lipgloss.Style
+	sidebarStyle          lipgloss.Style
+	menuItemStyle         lipgloss.Style
+	menuItemActiveStyle   lipgloss.Style
+	contentStyle          lipgloss.Style
+	cardStyle             lipgloss.Style
+	cardTitleStyle        lipgloss.Style
+	badgeActive           lipgloss.Style
+	badgeInactive         lipgloss.Style
+	badgeError            lipgloss.Style
+	tableHeaderStyle      lipgloss.Style
+	tableRowStyle         lipgloss.Style
+	tableRowSelectedStyle lipgloss.Style
+	helpStyle             lipgloss.Style
+	inputStyle            lipgloss.Style
+	inputFocusedStyle     lipgloss.Style
+	labelStyle            lipgloss.Style
+
+	chatTitleStyle           lipgloss.Style
+	chatUserMsgStyle         lipgloss.Style
+	chatUserLabelStyle       lipgloss.Style
+	chatAssistantLabelStyle  lipgloss.Style
+	chatAssistantMsgStyle    lipgloss.Style
+	chatToolStyle            lipgloss.Style
+	chatToolOutputStyle      lipgloss.Style
+	chatToolNoteStyle        lipgloss.Style
+	chatErrorMsgStyle        lipgloss.Style
+	chatInputBoxStyle        lipgloss.Style
+	chatInputBoxFocusedStyle lipgloss.Style
+	chatStatusStyle          lipgloss.Style
+	chatHelpStyle            lipgloss.Style
+)
+
+// applyPalette sets the colors and rebuilds the styles from them.
+func applyPalette(p Palette) {
+	purple = lipgloss.Color(p.Accent)
+	green = lipgloss.Color(p.Success)
+	red = lipgloss.Color(p.Error)
+	yellow = lipgloss.Color(p.Warning)
+	blue = lipgloss.Color(p.Info)
+	gray = lipgloss.Color(p.Muted)
+	darkGray = lipgloss.Color(p.Border)
+	white = lipgloss.Color(p.Text)
+	body = lipgloss.Color(p.Body)
+	highlight = lipgloss.Color(p.Highlight)
+	onAccent = lipgloss.Color(p.OnAccent)
+
+	// Logo/Title
+	logoStyle = lipgloss.NewStyle().
+		Bold(true).
+		Foreground(onAccent).
+		Background(purple).
+		Padding(0, 2).
+		MarginBottom(1)
+
+	// Sidebar
+	sidebarStyle = lipgloss.NewStyle().
+		Width(24).
+		Height(100).
+		Border(lipgloss.RoundedBorder(), false, true, false, false).
+		BorderForeground(darkGray).
+		Padding(1, 1)
+
+	menuItemStyle = lipgloss.NewStyle().
+		Padding(0, 1).
+		MarginBottom(0)
+
+	menuItemActiveStyle = lipgloss.NewStyle().
+		Bold(true).
+		Foreground(purple).
+		Background(highlight).
+		Padding(0, 1).
+		MarginBottom(0)
+
+	// Main content
+	contentStyle = lipgloss.NewStyle().
+		Padding(1, 2)
+
+	// Cards
+	cardStyle = lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(darkGray).
+		Padding(1, 2).
+		MarginBottom(1)
+
+	cardTitleStyle = lipgloss.NewStyle().
+		Bold(true).
+		Foreground(white).
+		MarginBottom(1)
+
+	// Status badges
+	badgeActive = lipgloss.NewStyle().
+		Foreground(green).
+		Bold(true)
+
+	badgeInactive = lipgloss.NewStyle().
+		Foreground(gray)
+
+	badgeError = lipgloss.NewStyle().
+		Foreground(red).
+		Bold(true)
+
+	// Table
+	tableHeaderStyle = lipgloss.NewStyle().
+		Bold(true).
+		Foreground(gray).
+		BorderStyle(lipgloss.NormalBorder()).
+		BorderBottom(true).
+		BorderForeground(darkGray)
+
+	tableRowStyle = lipgloss.NewStyle().
+		Padding(0, 1)
+
+	tableRowSelectedStyle = lipgloss.NewStyle().
+		Background(highlight).
+		Foreground(purple).
+		Bold(true).
+		Padding(0, 1)
+
+	// Help bar
+	helpStyle = lipgloss.NewStyle().
+		Foreground(gray).
+		Background(darkGray).
+		Padding(0, 2)
+
+	// Form
+	inputStyle = lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(gray).
+		Padding(0, 1)
+
+	inputFocusedStyle = lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(purple).
+		Padding(0, 1)
+
+	labelStyle = lipgloss.NewStyle().
+		Foreground(gray).
+		MarginBottom(0)
+
+	// Chat
+	chatTitleStyle = lipgloss.NewStyle().
+		Bold(true).
+		Foreground(purple).
+		MarginBottom(1)
+
+	chatUserMsgStyle = lipgloss.NewStyle().
+		Foreground(onAccent).
+		Background(purple).
+		Padding(0, 1).
+		MarginTop(1)
+
+	chatUserLabelStyle = lipgloss.NewStyle().
+		Foreground(purple).
+		Bold(true)
+
+	chatAssistantLabelStyle = lipgloss.NewStyle().
+		Foreground(green).
+		Bold(true)
+
+	chatAssistantMsgStyle = lipgloss.NewStyle().
+		Foreground(white).
+		MarginTop(1)
+
+	chatToolStyle = lipgloss.NewStyle().
+		Foreground(yellow).
+		Bold(true)
+
+	chatToolOutputStyle = lipgloss.NewStyle().
+		Foreground(body).
+		Background(darkGray).
+		Padding(0, 1)
+
+	chatToolNoteStyle = lipgloss.NewStyle().
+		Foreground(gray).
+		Italic(true)
+
+	chatErrorMsgStyle = lipgloss.NewStyle().
+		Foreground(red).
+		Bold(true)
+
+	chatInputBoxStyle = lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(purple).
+		Padding(0, 1)
+
+	chatInputBoxFocusedStyle = lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(green).
+		Padding(0, 1)
+
+	chatStatusStyle = lipgloss.NewStyle().
+		Foreground(gray).
+		MarginTop(1)
+
+	chatHelpStyle = lipgloss.NewStyle().
+		Foreground(gray)
+}